@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewAddCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		target       string
+		name         string
+		force        bool
+		yes          bool
+		varFlags     []string
+		includeFlags []string
+		excludeFlags []string
+		skipPostInit bool
+		trust        bool
+		skipTools    bool
+		strictDeps   bool
+		output       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <template-name>",
+		Short: "Add a feature or component to an existing project",
+		Long: `Add a feature or component to a project generated by blueprint.
+
+The template is recorded in the project manifest next to the templates the project was generated
+from. Variables recorded for the project are the defaults of the template's variables.
+
+A template may declare the projects it can be added to with requires. When the project does not
+meet them, add refuses, or warns and adds the template if the template sets on_mismatch: warn.
+
+A component can be added any number of times, for example once per handler. Each generation is
+recorded under a name, given with --name, and can be listed and regenerated with blueprint
+components.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeAddableTemplates(appCtx),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templateName := args[0]
+
+			format, err := parseResultFormat(output)
+			if err != nil {
+				return err
+			}
+
+			vars, err := parseVarFlags(varFlags)
+			if err != nil {
+				return err
+			}
+
+			enabledIncludes, err := parseIncludeFlags(includeFlags, excludeFlags)
+			if err != nil {
+				return err
+			}
+
+			lineEndings, backup, err := outputSettings(appCtx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := postInitPolicy(appCtx, trust)
+			if err != nil {
+				return err
+			}
+
+			scaffolder, err := newScaffolder(appCtx)
+			if err != nil {
+				return err
+			}
+			if format == ui.ResultFormatJSON {
+				scaffolder.SetPostInitOutput(os.Stderr, os.Stderr)
+			}
+
+			start := time.Now()
+			result, err := scaffolder.Add(scaffold.Options{
+				TemplateRef: template.TemplateRef{
+					Name: templateName,
+				},
+				OutputDir:       target,
+				ComponentName:   name,
+				Variables:       vars,
+				Defaults:        appCtx.Config.Defaults,
+				Mandated:        mandatedIncludes(appCtx),
+				LicenseHeader:   appCtx.Config.LicenseHeader,
+				Locale:          appCtx.Config.Locale,
+				LineEndings:     lineEndings,
+				FinalNewline:    appCtx.Config.FinalNewline,
+				EnabledIncludes: enabledIncludes,
+				Interactive:     !yes && appCtx.Options.Interactive(),
+				DryRun:          appCtx.Options.DryRun,
+				Overwrite:       force,
+				Backup:          backup,
+				SkipPostInit:    skipPostInit,
+				PostInitPolicy:  policy,
+				SkipToolChecks:  skipTools,
+				StrictDeps:      strictDeps,
+				OnEvent:         progressEvents(appCtx),
+			})
+
+			runErr := err
+			if runErr == nil {
+				runErr = result.PostInitErr()
+			}
+			recordRun(appCtx, templateName, start, runErr)
+			notifyRun(appCtx, templateName, result, runErr)
+
+			if err != nil {
+				return fmt.Errorf("add template %q: %w", templateName, err)
+			}
+
+			if err := renderResult(appCtx, result, format, time.Since(start)); err != nil {
+				return err
+			}
+			if appCtx.Options.NoWrite {
+				ui.RenderImpact(impactReport(appCtx, result))
+			}
+
+			return result.PostInitErr()
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&target,
+		"target",
+		".",
+		"Directory of the project to add the template to",
+	)
+
+	cmd.Flags().StringVar(
+		&name,
+		"name",
+		"",
+		"Name to record a component under, listed by blueprint components (default: last element of the template name)",
+	)
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Overwrite existing files, and add a template that is already part of the project again",
+	)
+
+	cmd.Flags().BoolVarP(
+		&yes,
+		"yes",
+		"y",
+		false,
+		"Accept defaults and disable prompts",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		`Set a template variable (format: key=value)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&includeFlags,
+		"include",
+		nil,
+		`Include a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&excludeFlags,
+		"exclude",
+		nil,
+		`Exclude a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().BoolVar(
+		&skipPostInit,
+		"skip-post-init",
+		false,
+		"Do not run post-init commands after scaffolding",
+	)
+
+	cmd.Flags().BoolVar(
+		&trust,
+		"trust",
+		false,
+		"Run post-init commands of builtin templates without confirmation",
+	)
+
+	cmd.Flags().BoolVar(
+		&skipTools,
+		"skip-tool-checks",
+		false,
+		"Do not check that the tools templates require are installed",
+	)
+
+	cmd.Flags().BoolVar(
+		&strictDeps,
+		"strict-deps",
+		false,
+		"Fail when templates depend on different versions of the same package",
+	)
+
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(ui.ResultFormatText),
+		"Output format: text, json (json prints the result to stdout and all other messages to stderr)",
+	)
+
+	return cmd
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewAnalyzeCmd(appCtx *app.Context) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:               "analyze <template>",
+		Short:             "Show where a template uses its variables",
+		Long:              "Compose a template with all of its includes and report which variables each file and include references, along with a variable to file usage matrix.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplates(appCtx, "", false),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := template.NewEngine(appCtx.Resolver)
+
+			tree, err := engine.GetFullTree(template.TemplateRef{Name: args[0]}, template.ComposeOptions{
+				IncludeAll: true,
+				Mandated:   mandatedIncludes(appCtx),
+			})
+			if err != nil {
+				return err
+			}
+
+			analysis, err := engine.AnalyzeTree(tree)
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderAnalysis(buildAnalysisReport(tree, analysis), asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&asJSON,
+		"json",
+		false,
+		"Output as JSON",
+	)
+
+	return cmd
+}
+
+func buildAnalysisReport(tree *template.TemplateNode, analysis *template.Analysis) *ui.AnalysisReport {
+	report := &ui.AnalysisReport{Template: tree.Template.Name}
+
+	usage := make(map[string]*ui.VariableUsage)
+	var names []string
+	addVariable := func(name, declaredBy string) *ui.VariableUsage {
+		u, ok := usage[name]
+		if !ok {
+			u = &ui.VariableUsage{Name: name}
+			usage[name] = u
+			names = append(names, name)
+		}
+		if u.DeclaredBy == "" {
+			u.DeclaredBy = declaredBy
+		}
+		return u
+	}
+
+	var declare func(node *template.TemplateNode)
+	declare = func(node *template.TemplateNode) {
+		for _, v := range node.Template.Variables {
+			addVariable(v.Name, node.Template.Name)
+		}
+		for _, child := range node.Children {
+			declare(child)
+		}
+	}
+	declare(tree)
+
+	for _, f := range analysis.Files {
+		report.Files = append(report.Files, ui.FileVariables{
+			Template:  f.Template,
+			Src:       f.Src,
+			Dest:      f.Dest,
+			Variables: f.Variables,
+		})
+		for _, name := range f.Variables {
+			u := addVariable(name, "")
+			u.Files = append(u.Files, f.Dest)
+		}
+	}
+
+	for _, n := range analysis.Nodes {
+		if n.NodeID == tree.ID {
+			continue
+		}
+		report.Includes = append(report.Includes, ui.IncludeVariables{
+			Name:      n.Template,
+			ID:        n.NodeID,
+			Variables: n.Variables,
+		})
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		report.Matrix = append(report.Matrix, *usage[name])
+	}
+
+	return report
+}
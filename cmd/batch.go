@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/spf13/cobra"
+)
+
+func NewBatchCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		input        string
+		outDir       string
+		force        bool
+		includeFlags []string
+		excludeFlags []string
+		skipPostInit bool
+		allowOutside bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "batch <template>",
+		Short: "Scaffold a template once per input record",
+		Long: `Scaffold a template once for every record of a CSV or JSON file.
+
+A CSV file uses its header row as variable names; a JSON file holds an array of objects.
+Each record is applied like a set of --var flags, and every project is created in the
+directory named by its project name under --out-dir. Prompts are disabled.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplates(appCtx, template.TypeProject, false),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templateName := args[0]
+
+			records, err := vars.LoadRecords(input)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", input, err)
+			}
+			if len(records) == 0 {
+				return fmt.Errorf("%s contains no records", input)
+			}
+
+			enabledIncludes, err := parseIncludeFlags(includeFlags, excludeFlags)
+			if err != nil {
+				return err
+			}
+
+			lineEndings, backup, err := outputSettings(appCtx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := postInitPolicy(appCtx, false)
+			if err != nil {
+				return err
+			}
+
+			scaffolder, err := newScaffolder(appCtx)
+			if err != nil {
+				return err
+			}
+
+			entries := make([]ui.BatchEntry, 0, len(records))
+			failed := 0
+
+			for i, record := range records {
+				start := time.Now()
+				result, err := scaffolder.Scaffold(scaffold.Options{
+					TemplateRef: template.TemplateRef{
+						Name: templateName,
+					},
+					OutputParent:       outDir,
+					Variables:          vars.Variables{Global: record},
+					Defaults:           appCtx.Config.Defaults,
+					Mandated:           mandatedIncludes(appCtx),
+					LicenseHeader:      appCtx.Config.LicenseHeader,
+					Locale:             appCtx.Config.Locale,
+					LineEndings:        lineEndings,
+					FinalNewline:       appCtx.Config.FinalNewline,
+					EnabledIncludes:    enabledIncludes,
+					DryRun:             appCtx.Options.DryRun,
+					Overwrite:          force,
+					Backup:             backup,
+					SkipPostInit:       skipPostInit,
+					PostInitPolicy:     policy,
+					AllowOutsideOutput: allowOutside,
+				})
+
+				runErr := err
+				if runErr == nil {
+					runErr = result.PostInitErr()
+				}
+				recordRun(appCtx, templateName, start, runErr)
+				notifyRun(appCtx, templateName, result, runErr)
+
+				entry := ui.BatchEntry{Record: i + 1, Err: err}
+				if err != nil {
+					failed++
+				} else {
+					entry.OutputDir = result.OutputDir
+					entry.Files = len(result.FilesWritten)
+					if appCtx.Options.DryRun {
+						entry.Files = len(result.Planned)
+					}
+				}
+				entries = append(entries, entry)
+			}
+
+			ui.RenderBatchResult(entries, appCtx.Options.DryRun)
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d records failed", failed, len(records))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&input,
+		"input",
+		"i",
+		"",
+		"CSV or JSON file with one record per project",
+	)
+	_ = cmd.MarkFlagRequired("input")
+
+	cmd.Flags().StringVarP(
+		&outDir,
+		"out-dir",
+		"o",
+		".",
+		"Directory the projects are created in",
+	)
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Overwrite existing files if they exist",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&includeFlags,
+		"include",
+		nil,
+		`Include a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&excludeFlags,
+		"exclude",
+		nil,
+		`Exclude a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().BoolVar(
+		&skipPostInit,
+		"skip-post-init",
+		false,
+		"Do not run post-init commands after scaffolding",
+	)
+
+	cmd.Flags().BoolVar(
+		&allowOutside,
+		"allow-outside-output",
+		false,
+		"Write files outside the output directory for templates that set allow_outside_output",
+	)
+
+	return cmd
+}
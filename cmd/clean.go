@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewCleanCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clean [dir]",
+		Short: "Remove generated build artifacts from a project",
+		Long: `Remove the generated-but-ignorable paths (such as bin/, dist/, or coverage files) that the templates of a
+scaffolded project declared. Paths recorded in the project manifest as scaffolded files are never removed.`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := "."
+			if len(args) > 0 {
+				start = args[0]
+			}
+
+			root, err := manifest.FindRoot(start)
+			if err != nil {
+				return err
+			}
+
+			result, err := scaffold.Clean(root, appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderCleanResult(result, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewCompatCmd(appCtx *app.Context) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "compat <template|dir>",
+		Short: "Report template constructs blueprint cannot render",
+		Long: `Scan every file of a template and list the constructs the template engine cannot render, such as
+unknown functions, Jinja filters, and Jinja statements, instead of failing on the first file.
+
+The argument may be a directory, for example a cookiecutter template being migrated, or the name of a
+template available from the configured sources.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fsys, root, err := compatSource(appCtx, args[0])
+			if err != nil {
+				return err
+			}
+
+			engine := template.NewEngine(appCtx.Resolver)
+			for _, name := range appCtx.Config.Functions {
+				if err := engine.EnableFuncLibrary(name); err != nil {
+					return fmt.Errorf("config: %w", err)
+				}
+			}
+
+			// Directories being migrated usually have no template.yaml yet.
+			if loaded, err := engine.LoadTemplateByPath(fsys, root); err == nil {
+				for _, name := range loaded.Template.Functions {
+					if err := engine.EnableFuncLibrary(name); err != nil {
+						return err
+					}
+				}
+			}
+
+			issues, err := engine.CheckCompatibility(fsys, root)
+			if err != nil {
+				return err
+			}
+
+			report := &ui.CompatReport{Source: args[0], Issues: make([]ui.CompatIssueInfo, 0, len(issues))}
+			for _, issue := range issues {
+				report.Issues = append(report.Issues, ui.CompatIssueInfo{
+					File:      issue.File,
+					Line:      issue.Line,
+					Construct: issue.Construct,
+					Message:   issue.Message,
+				})
+			}
+
+			return ui.RenderCompatReport(report, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&asJSON,
+		"json",
+		false,
+		"Output as JSON",
+	)
+
+	return cmd
+}
+
+// compatSource returns the filesystem to scan for arg, which is either a
+// directory or the name of a template.
+func compatSource(appCtx *app.Context, arg string) (fs.FS, string, error) {
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return os.DirFS(arg), ".", nil
+	}
+
+	resolved, err := appCtx.Resolver.Resolve(template.TemplateRef{Name: arg})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resolved.FS, resolved.Path, nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/spf13/cobra"
+)
+
+// completeTemplates completes the template argument of a command with the
+// names of templates of type typ, or of any type when typ is empty, from
+// every source. Later arguments are completed as directories when dirArg is
+// set, and not at all otherwise.
+func completeTemplates(appCtx *app.Context, typ template.Type, dirArg bool) cobra.CompletionFunc {
+	return completeTemplatesWhere(appCtx, typ, dirArg, func(template.Type) bool { return true })
+}
+
+// completeAddableTemplates completes the template argument of add with the
+// names of the feature and component templates; project templates cannot be
+// added to a project.
+func completeAddableTemplates(appCtx *app.Context) cobra.CompletionFunc {
+	return completeTemplatesWhere(appCtx, "", false, func(typ template.Type) bool {
+		return typ != template.TypeProject
+	})
+}
+
+// completeTemplatesWhere is completeTemplates, offering only the templates
+// whose type keep accepts.
+func completeTemplatesWhere(appCtx *app.Context, typ template.Type, dirArg bool, keep func(template.Type) bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			if dirArg && len(args) == 1 {
+				return nil, cobra.ShellCompDirectiveFilterDirs
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		groups, err := discoverTemplates(appCtx, typ, "", nil)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		// A user template shadows a builtin one of the same name, so each
+		// name is offered once, with the description of the first source.
+		seen := make(map[string]bool)
+		var completions []cobra.Completion
+		for _, group := range groups {
+			for _, entry := range group.Entries {
+				if seen[entry.Name] || !strings.HasPrefix(entry.Name, toComplete) {
+					continue
+				}
+				seen[entry.Name] = true
+				if !keep(entry.Type) {
+					continue
+				}
+				completions = append(completions, cobra.CompletionWithDesc(entry.Name, entry.Description))
+			}
+		}
+		sort.Strings(completions)
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateCompletions(t *testing.T) {
+	appCtx := testAppContext(t, testTemplates)
+
+	complete := func(cmd *cobra.Command, args []string, toComplete string) []cobra.Completion {
+		completions, directive := cmd.ValidArgsFunction(cmd, args, toComplete)
+		assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+		return completions
+	}
+
+	t.Run("init offers project templates", func(t *testing.T) {
+		cmd := NewInitCmd(appCtx)
+		assert.Equal(t, []cobra.Completion{"app\tAn app"}, complete(cmd, nil, ""))
+
+		_, directive := cmd.ValidArgsFunction(cmd, []string{"app"}, "")
+		assert.Equal(t, cobra.ShellCompDirectiveFilterDirs, directive)
+	})
+
+	t.Run("add offers features and components", func(t *testing.T) {
+		cmd := NewAddCmd(appCtx)
+		assert.Equal(t, []cobra.Completion{"auth\tAuthentication", "handler\tAn HTTP handler"}, complete(cmd, nil, ""))
+		assert.Equal(t, []cobra.Completion{"handler\tAn HTTP handler"}, complete(cmd, nil, "h"))
+		assert.Empty(t, complete(cmd, nil, "ap"))
+		assert.Empty(t, complete(cmd, []string{"auth"}, ""))
+	})
+}
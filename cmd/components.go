@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewComponentsCmd(appCtx *app.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "components",
+		Aliases: []string{"component"},
+		Short:   "List and regenerate the components added to a project",
+		Long: `Every component added to a project with blueprint add is recorded in the project manifest under a
+name, with the values of its variables, so that it can be listed and generated again.`,
+	}
+
+	cmd.AddCommand(newComponentsListCmd(appCtx))
+	cmd.AddCommand(newComponentsRegenCmd(appCtx))
+
+	return cmd
+}
+
+func newComponentsListCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [dir]",
+		Short: "List the components added to a project",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := "."
+			if len(args) > 0 {
+				start = args[0]
+			}
+
+			root, err := manifest.FindRoot(start)
+			if err != nil {
+				return err
+			}
+
+			m, err := manifest.Load(root)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderComponents(m.Components, root)
+			return nil
+		},
+	}
+}
+
+func newComponentsRegenCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		force        bool
+		varFlags     []string
+		skipPostInit bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "regen <name> [dir]",
+		Short: "Generate a component of a project again",
+		Long: `Generate a component of a project again with the current version of its template and the values its
+variables were given when it was generated. --var changes values; the new values are recorded.
+
+The files of the component are overwritten. Files changed since the component was generated stop the run unless
+--force is given.`,
+		Args:        cobra.RangeArgs(1, 2),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			dir := "."
+			if len(args) > 1 {
+				dir = args[1]
+			}
+
+			root, err := manifest.FindRoot(dir)
+			if err != nil {
+				return err
+			}
+
+			// Runs are recorded under the template of the component.
+			templateName := name
+			if m, err := manifest.Load(root); err == nil {
+				if component, ok := m.Component(name); ok {
+					templateName = component.Template
+				}
+			}
+
+			vars, err := parseVarFlags(varFlags)
+			if err != nil {
+				return err
+			}
+
+			lineEndings, backup, err := outputSettings(appCtx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := postInitPolicy(appCtx, false)
+			if err != nil {
+				return err
+			}
+
+			scaffolder, err := newScaffolder(appCtx)
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			result, err := scaffolder.Regen(name, scaffold.Options{
+				OutputDir:      root,
+				Variables:      vars,
+				Defaults:       appCtx.Config.Defaults,
+				Mandated:       mandatedIncludes(appCtx),
+				LicenseHeader:  appCtx.Config.LicenseHeader,
+				Locale:         appCtx.Config.Locale,
+				LineEndings:    lineEndings,
+				FinalNewline:   appCtx.Config.FinalNewline,
+				DryRun:         appCtx.Options.DryRun,
+				Overwrite:      force,
+				Backup:         backup,
+				SkipPostInit:   skipPostInit,
+				PostInitPolicy: policy,
+				OnEvent:        progressEvents(appCtx),
+			})
+
+			runErr := err
+			if runErr == nil {
+				runErr = result.PostInitErr()
+			}
+			recordRun(appCtx, templateName, start, runErr)
+
+			if err != nil {
+				return fmt.Errorf("regenerate component %q: %w", name, err)
+			}
+
+			ui.RenderResult(result)
+			if appCtx.Options.NoWrite {
+				ui.RenderImpact(impactReport(appCtx, result))
+			}
+
+			return result.PostInitErr()
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Overwrite files of the component that were changed since it was generated",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		`Change a variable of the component (format: key=value)`,
+	)
+
+	cmd.Flags().BoolVar(
+		&skipPostInit,
+		"skip-post-init",
+		false,
+		"Do not run post-init commands after scaffolding",
+	)
+
+	return cmd
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/config"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCmd(appCtx *app.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and write the configuration",
+		Long: `Read and write the configuration file.
+
+Settings are addressed by key: templates_dir, license_header, functions, defaults.<name>, and
+mandated_includes.<type>. list and get show the effective configuration, including BLUEPRINT_*
+environment variables; set and edit change the config file.`,
+	}
+
+	cmd.AddCommand(newConfigListCmd(appCtx))
+	cmd.AddCommand(newConfigGetCmd(appCtx))
+	cmd.AddCommand(newConfigSetCmd(appCtx))
+	cmd.AddCommand(newConfigEditCmd(appCtx))
+
+	return cmd
+}
+
+func newConfigListCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:         "list",
+		Short:       "Show all settings",
+		Args:        cobra.NoArgs,
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ui.RenderConfigList(appCtx.Config.Path, config.Entries(appCtx.Config))
+			return nil
+		},
+	}
+}
+
+func newConfigGetCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Show the value of a setting",
+		Example: `  blueprint config get templates_dir
+  blueprint config get defaults.author`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := config.Get(appCtx.Config, args[0])
+			if err != nil {
+				return err
+			}
+			ui.RenderConfigValue(value)
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Write a setting to the config file",
+		Long: `Write a setting to the config file, creating the file if needed. Comments and other settings in
+the file are kept.
+
+Lists, such as functions and mandated_includes.<type>, are given comma-separated. Values of
+defaults.<name> are parsed as YAML scalars, so true and 3 are stored as a bool and a number.`,
+		Example: `  blueprint config set templates_dir ~/templates
+  blueprint config set defaults.author "Jane Doe"
+  blueprint config set functions crypto,network`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.Set(appCtx.Config.Path, args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Set %s in %s\n", args[0], appCtx.Config.Path)
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in an editor",
+		Long: `Open the config file in $VISUAL or $EDITOR, creating it if needed. The file is checked after the
+editor exits.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{annotationAllowInvalidConfig: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := appCtx.Config.Path
+			if path == "" {
+				return fmt.Errorf("could not determine the config file path")
+			}
+
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return fmt.Errorf("failed to create config directory: %w", err)
+				}
+				if err := os.WriteFile(path, []byte("# Blueprint configuration\n"), 0644); err != nil {
+					return fmt.Errorf("failed to create config file: %w", err)
+				}
+			}
+
+			editor := strings.Fields(editorCommand())
+			editCmd := exec.Command(editor[0], append(editor[1:], path)...)
+			editCmd.Stdin, editCmd.Stdout, editCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := editCmd.Run(); err != nil {
+				return fmt.Errorf("editor %s: %w", editor[0], err)
+			}
+
+			if _, err := (&config.Loader{ConfigFile: path}).Load(); err != nil {
+				return fmt.Errorf("config file %s is invalid: %w", path, err)
+			}
+			return nil
+		},
+	}
+}
+
+// editorCommand returns the user's editor, falling back to a platform
+// default.
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewContextCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		yes          bool
+		asJSON       bool
+		varFlags     []string
+		includeFlags []string
+		excludeFlags []string
+		answersFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "context [template]",
+		Short: "Print the variables a template would be rendered with",
+		Long: `Compose a template and collect its variables exactly like init does, from template defaults, the
+configuration, --var flags, and prompts, then print the final variables of every template in the tree without
+rendering or writing anything.
+
+With --answers-file, the answers saved by init --save-answers are used without prompts, as init replays them;
+--var, --include, and --exclude override the recorded answers. The template can be omitted, since the file names it.
+
+Values are shown after type conversion, so an int variable given as --var port=8080 appears as a number.`,
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completeTemplates(appCtx, "", false),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var answers *scaffold.Answers
+			if answersFile != "" {
+				loaded, err := scaffold.LoadAnswers(answersFile)
+				if err != nil {
+					return err
+				}
+				answers = loaded
+			}
+
+			var templateName string
+			switch {
+			case len(args) > 0:
+				templateName = args[0]
+				if answers != nil && answers.Template != templateName {
+					return fmt.Errorf("answers file %s is for template %q, not %q", answersFile, answers.Template, templateName)
+				}
+			case answers != nil:
+				templateName = answers.Template
+			default:
+				return fmt.Errorf("a template name is required without --answers-file")
+			}
+
+			vars, err := parseVarFlags(varFlags)
+			if err != nil {
+				return err
+			}
+
+			enabledIncludes, err := parseIncludeFlags(includeFlags, excludeFlags)
+			if err != nil {
+				return err
+			}
+
+			if answers != nil {
+				vars, enabledIncludes = answers.Override(vars, enabledIncludes)
+			}
+
+			scaffolder, err := newScaffolder(appCtx)
+			if err != nil {
+				return err
+			}
+
+			tree, contexts, err := scaffolder.Compose(scaffold.Options{
+				TemplateRef:     template.TemplateRef{Name: templateName},
+				Variables:       vars,
+				Defaults:        appCtx.Config.Defaults,
+				Mandated:        mandatedIncludes(appCtx),
+				Locale:          appCtx.Config.Locale,
+				EnabledIncludes: enabledIncludes,
+				Interactive:     !yes && answers == nil && appCtx.Options.Interactive(),
+			})
+			if err != nil {
+				return fmt.Errorf("context of template %q: %w", templateName, err)
+			}
+
+			report := &ui.ContextReport{Template: templateName}
+			var addNode func(node *template.TemplateNode)
+			addNode = func(node *template.TemplateNode) {
+				info := ui.ContextNodeInfo{
+					ID:        node.ID,
+					Template:  node.Template.Name,
+					Variables: map[string]any{},
+				}
+				if ctx, ok := contexts[node.ID]; ok {
+					info.Variables = ctx.Variables
+				}
+				report.Nodes = append(report.Nodes, info)
+
+				for _, child := range node.Children {
+					addNode(child)
+				}
+			}
+			addNode(tree)
+
+			return ui.RenderContextReport(report, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&yes,
+		"yes",
+		"y",
+		false,
+		"Accept defaults and disable prompts",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		`Set a template variable (format: key=value)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&includeFlags,
+		"include",
+		nil,
+		`Include a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&excludeFlags,
+		"exclude",
+		nil,
+		`Exclude a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().StringVar(
+		&answersFile,
+		"answers-file",
+		"",
+		"Use the answers saved with init --save-answers without prompts",
+	)
+
+	cmd.Flags().BoolVar(
+		&asJSON,
+		"json",
+		false,
+		"Output as JSON instead of YAML",
+	)
+
+	return cmd
+}
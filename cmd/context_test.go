@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestContextReport(t *testing.T) {
+	appCtx := testAppContext(t, testTemplates)
+
+	t.Run("yaml", func(t *testing.T) {
+		out, err := runCmd(t, NewContextCmd(appCtx), "app", "--var", "name=demo", "--var", "auth:provider=basic")
+		require.NoError(t, err)
+
+		var report ui.ContextReport
+		require.NoError(t, yaml.Unmarshal([]byte(out), &report))
+		assert.Equal(t, "app", report.Template)
+		require.Len(t, report.Nodes, 2)
+		assert.Equal(t, "0", report.Nodes[0].ID)
+		assert.Equal(t, "app", report.Nodes[0].Template)
+		assert.Equal(t, "demo", report.Nodes[0].Variables["name"])
+		assert.Equal(t, "0.0", report.Nodes[1].ID)
+		assert.Equal(t, "auth", report.Nodes[1].Template)
+		assert.Equal(t, "demo", report.Nodes[1].Variables["name"], "variables are inherited")
+		assert.Equal(t, "basic", report.Nodes[1].Variables["provider"])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runCmd(t, NewContextCmd(appCtx), "app", "--json", "--exclude", "auth")
+		require.NoError(t, err)
+
+		var report struct {
+			Template string `json:"template"`
+			Nodes    []struct {
+				ID        string `json:"id"`
+				Template  string `json:"template"`
+				Variables struct {
+					Name      string `json:"name"`
+					Blueprint struct {
+						Template struct {
+							Name    string `json:"name"`
+							Version string `json:"version"`
+						} `json:"template"`
+					} `json:"_blueprint"`
+				} `json:"variables"`
+			} `json:"nodes"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		assert.Equal(t, "app", report.Template)
+		require.Len(t, report.Nodes, 1)
+		assert.Equal(t, "0", report.Nodes[0].ID)
+		assert.Equal(t, "app", report.Nodes[0].Variables.Name)
+		assert.Equal(t, "app", report.Nodes[0].Variables.Blueprint.Template.Name)
+		assert.Equal(t, "1.0.0", report.Nodes[0].Variables.Blueprint.Template.Version)
+	})
+
+	t.Run("template is required without answers", func(t *testing.T) {
+		_, err := runCmd(t, NewContextCmd(appCtx))
+		assert.ErrorContains(t, err, "a template name is required without --answers-file")
+	})
+}
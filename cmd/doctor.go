@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewDoctorCmd(appCtx *app.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor <template>",
+		Short: "Check that the tools a template requires are installed",
+		Long: `Check the tools that a template and all of its includes require, as declared under requires.tools,
+without scaffolding anything. Each tool must be on the PATH, and tools with a version constraint must report a
+version it accepts.
+
+init and add run the same checks before rendering, for the templates of the composed tree.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplates(appCtx, "", false),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := template.NewEngine(appCtx.Resolver)
+
+			tree, err := engine.GetFullTree(template.TemplateRef{Name: args[0]}, template.ComposeOptions{
+				IncludeAll: true,
+				Mandated:   mandatedIncludes(appCtx),
+			})
+			if err != nil {
+				return err
+			}
+
+			checks := scaffold.CheckTools(tree.AllTools())
+			ui.RenderToolChecks(tree.Template.Name, checks)
+
+			failed := 0
+			for _, check := range checks {
+				if !check.OK() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return &scaffold.ToolCheckFailedError{Template: tree.Template.Name, Failed: failed, Total: len(checks)}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewExplainCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:         "explain <file>",
+		Short:       "Show which template produced a generated file",
+		Long:        "Look up a file in a generated project and report the template, include chain, source file, and variables that produced it.",
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("resolve %s: %w", args[0], err)
+			}
+
+			root, err := manifest.FindRoot(filepath.Dir(target))
+			if err != nil {
+				return err
+			}
+
+			m, err := manifest.Load(root)
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, target)
+			if err != nil {
+				return fmt.Errorf("resolve %s relative to %s: %w", target, root, err)
+			}
+
+			file, ok := m.FileByPath(rel)
+			if !ok {
+				return fmt.Errorf("%s was not generated by blueprint", filepath.ToSlash(rel))
+			}
+
+			ui.RenderExplain(m, file)
+			return nil
+		},
+	}
+}
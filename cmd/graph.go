@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewGraphCmd(appCtx *app.Context) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "graph <template>",
+		Short: "Show the include graph of a template",
+		Long: `Compose a template with all of its includes and print the include graph.
+
+Every include is shown with whether it is enabled by default, its condition, and the variables it
+contributes, that is, the variables it declares without inheriting them from the including template.
+
+--format selects the output: tree (default) prints an ASCII tree, dot a Graphviz digraph, and
+mermaid a Mermaid flowchart.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplates(appCtx, "", false),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(ui.GraphFormats, format) {
+				return fmt.Errorf("unknown --format %q (expected %s)", format, strings.Join(ui.GraphFormats, ", "))
+			}
+
+			engine := template.NewEngine(appCtx.Resolver)
+
+			tree, err := engine.GetFullTree(template.TemplateRef{Name: args[0]}, template.ComposeOptions{
+				IncludeAll: true,
+				Mandated:   mandatedIncludes(appCtx),
+			})
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderGraph(buildGraphNode(tree, nil), format)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&format,
+		"format",
+		ui.GraphFormatTree,
+		"Output format: tree, dot, or mermaid",
+	)
+
+	return cmd
+}
+
+// buildGraphNode describes node, included by parent, and its includes. The
+// root has no parent and is always enabled.
+func buildGraphNode(node *template.TemplateNode, parent *template.TemplateNode) *ui.GraphNode {
+	g := &ui.GraphNode{
+		Name:             node.Template.Name,
+		ID:               node.ID,
+		Type:             node.Template.Type,
+		Version:          node.Template.Version,
+		EnabledByDefault: parent == nil,
+		Mandated:         node.Mandated,
+	}
+
+	if parent != nil {
+		for _, inc := range parent.Template.Includes {
+			if inc.Name == node.Template.Name {
+				g.EnabledByDefault = inc.EnabledByDefault
+				g.When = inc.When
+				break
+			}
+		}
+	}
+
+	for _, v := range node.RequiredVariables() {
+		g.Variables = append(g.Variables, v.Name)
+	}
+	for _, child := range node.Children {
+		g.Includes = append(g.Includes, buildGraphNode(child, node))
+	}
+	return g
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/config"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// testTemplates are the templates of the source of testAppContext: a
+// project including a feature, and a component.
+var testTemplates = map[string]string{
+	"app/template.yaml": `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+includes:
+  - name: auth
+    enabled_by_default: true
+files:
+  - src: main.go.tmpl
+    dest: main.go
+`,
+	"app/main.go.tmpl": "package main\n",
+	"auth/template.yaml": `name: auth
+type: feature
+version: 1.2.0
+description: Authentication
+tags: [security]
+variables:
+  - name: provider
+    prompt: Provider?
+    type: select
+    options: [oauth, basic]
+    default: oauth
+files:
+  - src: auth.go.tmpl
+    dest: auth/auth.go
+`,
+	"auth/auth.go.tmpl": "package auth\n",
+	"handler/template.yaml": `name: handler
+type: component
+version: 0.1.0
+description: An HTTP handler
+`,
+}
+
+// testAppContext returns an application context whose only source holds
+// files, keyed by slash-separated path.
+func testAppContext(t *testing.T, files map[string]string) *app.Context {
+	t.Helper()
+	dir := t.TempDir()
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	sources := []resolver.Source{{
+		Name:       "USER",
+		Type:       resolver.SourceTypeUser,
+		Filesystem: os.DirFS(dir),
+		Dir:        dir,
+	}}
+	return &app.Context{
+		Config:       &config.Config{},
+		TemplatesDir: dir,
+		Sources:      sources,
+		Resolver:     resolver.NewChainResolver(sources...),
+		Options:      app.Options{CI: true},
+	}
+}
+
+// runCmd runs cmd with args and returns what it wrote to stdout.
+func runCmd(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	prev := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = prev }()
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&buf, r)
+		close(done)
+	}()
+
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err = cmd.Execute()
+
+	require.NoError(t, w.Close())
+	<-done
+	return buf.String(), err
+}
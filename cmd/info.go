@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewInfoCmd(appCtx *app.Context) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:               "info <template>",
+		Short:             "Show details about a template",
+		Long:              "Resolve a template, compose all of its includes, and show its variables, includes, files, dependencies, and post-init commands.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplates(appCtx, "", false),
+		Annotations:       noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engine := template.NewEngine(appCtx.Resolver)
+
+			tree, err := engine.GetFullTree(template.TemplateRef{Name: args[0]}, template.ComposeOptions{
+				IncludeAll: true,
+				Mandated:   mandatedIncludes(appCtx),
+			})
+			if err != nil {
+				return err
+			}
+
+			return ui.RenderTemplateInfo(buildTemplateInfo(tree), asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&asJSON,
+		"json",
+		false,
+		"Output as JSON",
+	)
+
+	return cmd
+}
+
+func buildTemplateInfo(tree *template.TemplateNode) *ui.TemplateInfo {
+	tmpl := tree.Template
+
+	info := &ui.TemplateInfo{
+		Name:         tmpl.Name,
+		Type:         tmpl.Type,
+		Version:      tmpl.Version,
+		Description:  tmpl.Description,
+		Tags:         tmpl.Tags,
+		Keywords:     tmpl.Keywords,
+		Author:       tmpl.Author,
+		License:      tmpl.License,
+		Homepage:     tmpl.Homepage,
+		Variables:    variableInfos(tmpl.Variables),
+		Includes:     includeInfos(tree),
+		Files:        fileInfos(tree),
+		Dependencies: tree.AllDependencies(),
+		NextSteps:    nextSteps(tree),
+	}
+
+	for _, cmd := range tree.AllPostInit() {
+		info.PostInit = append(info.PostInit, ui.PostInitInfo{
+			Command: cmd.Command,
+			WorkDir: cmd.WorkDir,
+		})
+	}
+
+	return info
+}
+
+func variableInfos(vars []template.Variable) []ui.VariableInfo {
+	infos := make([]ui.VariableInfo, 0, len(vars))
+	for _, v := range vars {
+		infos = append(infos, ui.VariableInfo{
+			Name:    v.Name,
+			Type:    v.Type,
+			Prompt:  v.Prompt,
+			Default: v.Default,
+			Options: v.OptionValues(),
+			Role:    v.Role,
+			When:    v.When,
+		})
+	}
+	return infos
+}
+
+// nextSteps returns the next steps text of every template in the tree.
+func nextSteps(node *template.TemplateNode) []string {
+	var steps []string
+	if node.Template.NextSteps != "" {
+		steps = append(steps, node.Template.NextSteps)
+	}
+	for _, child := range node.Children {
+		steps = append(steps, nextSteps(child)...)
+	}
+	return steps
+}
+
+func includeInfos(node *template.TemplateNode) []ui.IncludeInfo {
+	infos := make([]ui.IncludeInfo, 0, len(node.Children))
+	for _, child := range node.Children {
+		info := ui.IncludeInfo{
+			Name:      child.Template.Name,
+			ID:        child.ID,
+			Type:      child.Template.Type,
+			Mandated:  child.Mandated,
+			Variables: variableInfos(child.RequiredVariables()),
+			Includes:  includeInfos(child),
+		}
+
+		for _, inc := range node.Template.Includes {
+			if inc.Name == child.Template.Name {
+				info.EnabledByDefault = inc.EnabledByDefault
+				info.When = inc.When
+				break
+			}
+		}
+
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func fileInfos(node *template.TemplateNode) []ui.FileInfo {
+	var infos []ui.FileInfo
+	for _, f := range node.Template.Files {
+		infos = append(infos, ui.FileInfo{
+			Template: node.Template.Name,
+			Src:      f.Src,
+			Dest:     f.Dest,
+			When:     f.When,
+		})
+	}
+	for _, child := range node.Children {
+		infos = append(infos, fileInfos(child)...)
+	}
+	return infos
+}
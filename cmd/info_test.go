@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoJSON(t *testing.T) {
+	files := maps.Clone(testTemplates)
+	files["auth/template.yaml"] = `name: auth
+type: feature
+version: 1.2.0
+description: Authentication
+variables:
+  - name: provider
+    prompt: Provider?
+    type: select
+    options: [oauth, basic]
+includes:
+  - name: session
+    when: provider == "oauth"
+files:
+  - src: auth.go.tmpl
+    dest: auth/auth.go
+dependencies:
+  - golang.org/x/oauth2@v0.20.0
+post_init:
+  - command: go mod tidy
+`
+	files["session/template.yaml"] = `name: session
+type: feature
+version: 0.3.0
+description: Sessions
+files:
+  - src: session.go.tmpl
+    dest: auth/session.go
+    when: provider == "oauth"
+`
+	files["session/session.go.tmpl"] = "package auth\n"
+
+	out, err := runCmd(t, NewInfoCmd(testAppContext(t, files)), "app", "--json")
+	require.NoError(t, err)
+
+	var info ui.TemplateInfo
+	require.NoError(t, json.Unmarshal([]byte(out), &info))
+	assert.Equal(t, "app", info.Name)
+	assert.Equal(t, template.TypeProject, info.Type)
+	assert.Equal(t, "1.0.0", info.Version)
+	assert.Equal(t, "An app", info.Description)
+	assert.Equal(t, []ui.VariableInfo{
+		{Name: "name", Type: template.VariableTypeString, Prompt: "Name?", Default: "app", Role: template.RoleProjectName},
+	}, info.Variables)
+
+	assert.Equal(t, []ui.IncludeInfo{{
+		Name:             "auth",
+		ID:               "0.0",
+		Type:             template.TypeFeature,
+		EnabledByDefault: true,
+		Variables: []ui.VariableInfo{
+			{Name: "provider", Type: template.VariableTypeSelect, Prompt: "Provider?", Options: []string{"oauth", "basic"}},
+		},
+		Includes: []ui.IncludeInfo{{
+			Name: "session",
+			ID:   "0.0.0",
+			Type: template.TypeFeature,
+			When: `provider == "oauth"`,
+		}},
+	}}, info.Includes)
+
+	assert.Equal(t, []ui.FileInfo{
+		{Template: "app", Src: "main.go.tmpl", Dest: "main.go"},
+		{Template: "auth", Src: "auth.go.tmpl", Dest: "auth/auth.go"},
+		{Template: "session", Src: "session.go.tmpl", Dest: "auth/session.go", When: `provider == "oauth"`},
+	}, info.Files)
+	assert.Equal(t, []string{"golang.org/x/oauth2@v0.20.0"}, info.Dependencies)
+	assert.Equal(t, []ui.PostInitInfo{{Command: "go mod tidy"}}, info.PostInit)
+}
+
+func TestInfoUnknownTemplate(t *testing.T) {
+	_, err := runCmd(t, NewInfoCmd(testAppContext(t, testTemplates)), "missing")
+	assert.Error(t, err)
+}
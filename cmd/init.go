@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/prompt"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
 	"github.com/dhanush0x96c/blueprint/internal/scaffold"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 	"github.com/dhanush0x96c/blueprint/internal/ui"
@@ -14,20 +18,96 @@ import (
 
 func NewInitCmd(appCtx *app.Context) *cobra.Command {
 	var (
-		force        bool
-		yes          bool
-		varFlags     []string
-		includeFlags []string
-		excludeFlags []string
+		force         bool
+		yes           bool
+		varFlags      []string
+		overrideFlags []string
+		includeFlags  []string
+		excludeFlags  []string
+		skipPostInit  bool
+		allowOutside  bool
+		showContent   bool
+		shadow        bool
+		keepPartial   bool
+		rerunPostInit bool
+		trust         bool
+		skipTools     bool
+		prune         bool
+		strictDeps    bool
+		output        string
+		saveAnswers   string
+		answersFile   string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "init <template> [output-dir]",
+		Use:   "init [template] [output-dir]",
 		Short: "Initialize a new project",
-		Long:  `Initialize a new project from a template.`,
-		Args:  cobra.RangeArgs(1, 2),
+		Long: `Initialize a new project from a template.
+
+When no template is given, an interactive picker lists the available project templates. When no
+output directory is given, the project is created in a directory named after the project name,
+lowercased with dashes.
+
+With --save-answers, the variables and include selections of the run are written to a file. Passing
+that file to --answers-file replays them without prompts; --var, --include, and --exclude override
+the recorded answers.
+
+--set-include-var sets a single variable of one included template, e.g. database.port=5433. Unlike
+--var, it fails when the include is not part of the composed tree or does not declare the variable.`,
+		Args:              cobra.RangeArgs(0, 2),
+		ValidArgsFunction: completeTemplates(appCtx, template.TypeProject, true),
+		Annotations:       noWrite,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			templateName := args[0]
+			format, err := parseResultFormat(output)
+			if err != nil {
+				return err
+			}
+			if showContent && !appCtx.Options.DryRun {
+				return fmt.Errorf("--show-content requires --dry-run")
+			}
+			if shadow && !appCtx.Options.DryRun {
+				return fmt.Errorf("--shadow requires --dry-run")
+			}
+			if appCtx.Options.NoWrite {
+				// A shadow run executes post-init commands in a temporary copy.
+				if shadow {
+					return fmt.Errorf("--shadow cannot be used with --no-write")
+				}
+				if saveAnswers != "" {
+					return fmt.Errorf("--save-answers cannot be used with --no-write")
+				}
+			}
+
+			var answers *scaffold.Answers
+			if answersFile != "" {
+				loaded, err := scaffold.LoadAnswers(answersFile)
+				if err != nil {
+					return err
+				}
+				answers = loaded
+			}
+
+			interactive := !yes && answers == nil && appCtx.Options.Interactive()
+
+			var templateName string
+			if len(args) > 0 {
+				templateName = args[0]
+				if answers != nil && answers.Template != templateName {
+					return fmt.Errorf("answers file %s is for template %q, not %q", answersFile, answers.Template, templateName)
+				}
+			} else if answers != nil {
+				templateName = answers.Template
+			} else {
+				if !interactive {
+					return fmt.Errorf("a template name is required when prompts are disabled")
+				}
+
+				name, err := pickTemplate(appCtx)
+				if err != nil {
+					return err
+				}
+				templateName = name
+			}
 
 			var outputDir string
 			if len(args) > 1 {
@@ -39,31 +119,109 @@ func NewInitCmd(appCtx *app.Context) *cobra.Command {
 				return err
 			}
 
+			overrides, err := parseIncludeOverrides(overrideFlags)
+			if err != nil {
+				return err
+			}
+
 			enabledIncludes, err := parseIncludeFlags(includeFlags, excludeFlags)
 			if err != nil {
 				return err
 			}
 
-			scaffolder := scaffold.NewScaffolder(appCtx.Resolver)
+			if answers != nil {
+				vars, enabledIncludes = answers.Override(vars, enabledIncludes)
+			}
+
+			lineEndings, backup, err := outputSettings(appCtx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := postInitPolicy(appCtx, trust)
+			if err != nil {
+				return err
+			}
+
+			scaffolder, err := newScaffolder(appCtx)
+			if err != nil {
+				return err
+			}
+			if format == ui.ResultFormatJSON {
+				scaffolder.SetPostInitOutput(os.Stderr, os.Stderr)
+			}
+
+			var draft *prompt.Draft
+			if interactive && !appCtx.Options.NoWrite {
+				draft, err = openDraft(templateName)
+				if err != nil {
+					return err
+				}
+			}
+
+			start := time.Now()
 			result, err := scaffolder.Scaffold(scaffold.Options{
 				TemplateRef: template.TemplateRef{
 					Name: templateName,
 				},
-				OutputDir:       outputDir,
-				Variables:       vars,
-				EnabledIncludes: enabledIncludes,
-				Interactive:     !yes,
-				DryRun:          appCtx.Options.DryRun,
-				Overwrite:       force,
+				OutputDir:          outputDir,
+				Variables:          vars,
+				IncludeOverrides:   overrides,
+				Defaults:           appCtx.Config.Defaults,
+				Mandated:           mandatedIncludes(appCtx),
+				LicenseHeader:      appCtx.Config.LicenseHeader,
+				Locale:             appCtx.Config.Locale,
+				LineEndings:        lineEndings,
+				FinalNewline:       appCtx.Config.FinalNewline,
+				EnabledIncludes:    enabledIncludes,
+				Interactive:        interactive,
+				DryRun:             appCtx.Options.DryRun,
+				Shadow:             shadow,
+				Overwrite:          force,
+				Backup:             backup,
+				SkipPostInit:       skipPostInit,
+				RerunPostInit:      rerunPostInit,
+				PostInitPolicy:     policy,
+				SkipToolChecks:     skipTools,
+				KeepPartial:        keepPartial,
+				AllowOutsideOutput: allowOutside,
+				Prune:              prune,
+				StrictDeps:         strictDeps,
+				ShowContent:        showContent,
+				Draft:              draft,
+				OnEvent:            progressEvents(appCtx),
 			})
 
+			runErr := err
+			if runErr == nil {
+				runErr = result.PostInitErr()
+			}
+			recordRun(appCtx, templateName, start, runErr)
+			notifyRun(appCtx, templateName, result, runErr)
+
 			if err != nil {
 				return fmt.Errorf("init template %q: %w", templateName, err)
 			}
 
-			ui.RenderResult(result)
+			if draft != nil {
+				_ = draft.Discard()
+			}
+
+			if err := renderResult(appCtx, result, format, time.Since(start)); err != nil {
+				return err
+			}
+			if appCtx.Options.NoWrite {
+				ui.RenderImpact(impactReport(appCtx, result))
+			}
 
-			return nil
+			if saveAnswers != "" {
+				if err := result.Answers.Save(saveAnswers); err != nil {
+					return err
+				}
+				ui.RenderAnswersSaved(saveAnswers)
+			}
+
+			return result.PostInitErr()
 		},
 	}
 
@@ -90,6 +248,13 @@ func NewInitCmd(appCtx *app.Context) *cobra.Command {
 		`Set a template variable (format: key=value)`,
 	)
 
+	cmd.Flags().StringArrayVar(
+		&overrideFlags,
+		"set-include-var",
+		nil,
+		`Set a variable of one included template (format: include.key=value, or #node-id.key=value)`,
+	)
+
 	cmd.Flags().StringArrayVar(
 		&includeFlags,
 		"include",
@@ -104,9 +269,263 @@ func NewInitCmd(appCtx *app.Context) *cobra.Command {
 		`Exclude a template feature (format: template-name)`,
 	)
 
+	cmd.Flags().BoolVar(
+		&skipPostInit,
+		"skip-post-init",
+		false,
+		"Do not run post-init commands after scaffolding",
+	)
+
+	cmd.Flags().BoolVar(
+		&rerunPostInit,
+		"rerun-post-init",
+		false,
+		"Run post-init commands again that an earlier run into the project completed",
+	)
+
+	cmd.Flags().BoolVar(
+		&trust,
+		"trust",
+		false,
+		"Run post-init commands of builtin templates without confirmation",
+	)
+
+	cmd.Flags().BoolVar(
+		&skipTools,
+		"skip-tool-checks",
+		false,
+		"Do not check that the tools templates require are installed",
+	)
+
+	cmd.Flags().BoolVar(
+		&keepPartial,
+		"keep-partial",
+		false,
+		"Keep files written so far if scaffolding fails",
+	)
+
+	cmd.Flags().BoolVar(
+		&allowOutside,
+		"allow-outside-output",
+		false,
+		"Write files outside the output directory for templates that set allow_outside_output",
+	)
+
+	cmd.Flags().BoolVar(
+		&prune,
+		"prune",
+		false,
+		"Remove unmodified files of includes that are no longer enabled",
+	)
+
+	cmd.Flags().BoolVar(
+		&strictDeps,
+		"strict-deps",
+		false,
+		"Fail when templates depend on different versions of the same package",
+	)
+
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(ui.ResultFormatText),
+		"Output format: text, json (json prints the result to stdout and all other messages to stderr)",
+	)
+
+	cmd.Flags().StringVar(
+		&saveAnswers,
+		"save-answers",
+		"",
+		"Write the variables and include selections of this run to a file",
+	)
+
+	cmd.Flags().StringVar(
+		&answersFile,
+		"answers-file",
+		"",
+		"Replay the answers saved with --save-answers without prompts",
+	)
+
+	cmd.Flags().BoolVar(
+		&showContent,
+		"show-content",
+		false,
+		"With --dry-run, print the full content of every file",
+	)
+
+	cmd.Flags().BoolVar(
+		&shadow,
+		"shadow",
+		false,
+		"With --dry-run, run the full pipeline in a temporary copy of the output directory",
+	)
+
 	return cmd
 }
 
+// outputSettings returns the line endings of generated files and the backup
+// mode of overwritten files set in the configuration.
+func outputSettings(appCtx *app.Context) (template.LineEnding, scaffold.BackupMode, error) {
+	lineEndings, err := template.ParseLineEnding(appCtx.Config.LineEndings)
+	if err != nil {
+		return "", "", fmt.Errorf("config: %w", err)
+	}
+	backup, err := scaffold.ParseBackupMode(appCtx.Config.Backup)
+	if err != nil {
+		return "", "", fmt.Errorf("config: %w", err)
+	}
+	return lineEndings, backup, nil
+}
+
+// postInitPolicy returns the post-init policy set in the configuration. With
+// trust, the commands of builtin templates run without confirmation.
+func postInitPolicy(appCtx *app.Context, trust bool) (scaffold.PostInitPolicy, error) {
+	env, err := scaffold.ParsePostInitEnv(appCtx.Config.PostInitEnv)
+	if err != nil {
+		return scaffold.PostInitPolicy{}, fmt.Errorf("config: %w", err)
+	}
+
+	policy := scaffold.PostInitPolicy{
+		Allow: appCtx.Config.PostInitAllow,
+		Deny:  appCtx.Config.PostInitDeny,
+		Env:   env,
+	}
+	if trust {
+		for _, src := range appCtx.Sources {
+			if src.Type == resolver.SourceTypeBuiltin {
+				policy.Trusted = append(policy.Trusted, src.Filesystem)
+			}
+		}
+	}
+	return policy, nil
+}
+
+// parseResultFormat checks the --output flag of init and add. With JSON
+// output, human-readable messages go to stderr so that stdout holds only the
+// result.
+func parseResultFormat(output string) (ui.ResultFormat, error) {
+	format := ui.ResultFormat(output)
+	switch format {
+	case ui.ResultFormatText:
+	case ui.ResultFormatJSON:
+		ui.MessagesToStderr()
+	default:
+		return "", fmt.Errorf("unsupported output format %q (use text or json)", output)
+	}
+	return format, nil
+}
+
+// renderResult prints the result of a scaffold run in the given format.
+func renderResult(appCtx *app.Context, result *scaffold.Result, format ui.ResultFormat, elapsed time.Duration) error {
+	if format == ui.ResultFormatJSON {
+		return ui.RenderResultJSON(result, appCtx.Options.DryRun, elapsed)
+	}
+	ui.RenderResult(result)
+	return nil
+}
+
+// mandatedIncludes converts the configured mandated includes to template types.
+func mandatedIncludes(appCtx *app.Context) map[template.Type][]string {
+	if len(appCtx.Config.MandatedIncludes) == 0 {
+		return nil
+	}
+
+	mandated := make(map[template.Type][]string, len(appCtx.Config.MandatedIncludes))
+	for typ, names := range appCtx.Config.MandatedIncludes {
+		mandated[template.Type(typ)] = names
+	}
+	return mandated
+}
+
+// progressEvents returns the callback that prints the progress of a scaffold
+// run with --verbose, or nil.
+func progressEvents(appCtx *app.Context) func(scaffold.Event) {
+	if !appCtx.Options.Verbose {
+		return nil
+	}
+	return ui.RenderEvent
+}
+
+// newScaffolder creates a scaffolder with the function libraries enabled in
+// the configuration.
+// impactReport returns what the run of a dry-run result would do, including
+// the telemetry and notifications Blueprint itself would send.
+func impactReport(appCtx *app.Context, result *scaffold.Result) *ui.ImpactReport {
+	report := &ui.ImpactReport{Impact: result.Impact()}
+
+	cfg := appCtx.Config
+	if telemetryEnabled(appCtx) && cfg.TelemetryEndpoint != "" {
+		report.Network = append(report.Network, "telemetry endpoint")
+	}
+	if cfg.NotifyWebhook != "" {
+		report.Network = append(report.Network, "notify webhook")
+	}
+	if cfg.NotifyCommand != "" {
+		report.Commands = append(report.Commands, "1 notify")
+	}
+	return report
+}
+
+func newScaffolder(appCtx *app.Context) (*scaffold.Scaffolder, error) {
+	scaffolder := scaffold.NewScaffolder(appCtx.Resolver)
+	if err := scaffolder.EnableFuncLibraries(appCtx.Config.Functions...); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return scaffolder, nil
+}
+
+// openDraft loads the answers saved by an interrupted session for the
+// template and offers to restore them. A declined draft is discarded.
+func openDraft(templateName string) (*prompt.Draft, error) {
+	path, err := prompt.DefaultDraftPath(templateName)
+	if err != nil {
+		// Without a cache directory, answers are simply not saved.
+		return nil, nil
+	}
+
+	draft := prompt.LoadDraft(path, templateName)
+	if draft.Len() == 0 {
+		return draft, nil
+	}
+
+	restore, err := prompt.NewEngine().Confirm(fmt.Sprintf(
+		"Restore %d answers from an unfinished session (%s)?",
+		draft.Len(), draft.UpdatedAt.Local().Format("Jan 2 15:04"),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if !restore {
+		if err := draft.Discard(); err != nil {
+			return nil, err
+		}
+	}
+	return draft, nil
+}
+
+// pickTemplate lets the user choose a project template from all sources.
+func pickTemplate(appCtx *app.Context) (string, error) {
+	groups, err := discoverTemplates(appCtx, template.TypeProject, "", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var options []prompt.TemplateOption
+	for _, g := range groups {
+		for _, e := range g.Entries {
+			options = append(options, prompt.TemplateOption{
+				Name:        e.Name,
+				Description: e.Description,
+				Source:      g.Source,
+			})
+		}
+	}
+
+	return prompt.NewEngine().PickTemplate(options)
+}
+
 func parseVarFlags(flags []string) (vars.Variables, error) {
 	vars := vars.Variables{
 		Global:       make(map[string]string),
@@ -165,6 +584,18 @@ func parseVarFlag(flag string) (scope, key, value string, err error) {
 	return scope, key, value, nil
 }
 
+func parseIncludeOverrides(flags []string) ([]vars.IncludeOverride, error) {
+	overrides := make([]vars.IncludeOverride, 0, len(flags))
+	for _, f := range flags {
+		o, err := vars.ParseIncludeOverride(f)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
 func parseIncludeFlags(includeFlags, excludeFlags []string) (map[string]bool, error) {
 	if len(includeFlags) == 0 && len(excludeFlags) == 0 {
 		return nil, nil
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"path/filepath"
 	"sort"
 
 	"github.com/dhanush0x96c/blueprint/internal/app"
@@ -15,15 +17,26 @@ func NewListCmd(appCtx *app.Context) *cobra.Command {
 	var (
 		source string
 		quiet  bool
+		long   bool
 		tags   []string
+		output string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "list [projects|features|components]",
-		Short: "List available templates",
-		Long:  "List available templates, optionally filtered by type, source, and tags.",
-		Args:  cobra.MaximumNArgs(1),
+		Use:         "list [projects|features|components]",
+		Short:       "List available templates",
+		Long:        "List available templates, optionally filtered by type, source, and tags.",
+		Args:        cobra.MaximumNArgs(1),
+		ValidArgs:   []cobra.Completion{"projects", "features", "components"},
+		Annotations: noWrite,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			format := ui.ListFormat(output)
+			switch format {
+			case ui.ListFormatTable, ui.ListFormatJSON, ui.ListFormatYAML:
+			default:
+				return fmt.Errorf("unsupported output format %q (use table, json, or yaml)", output)
+			}
+
 			var filterType template.Type
 			showType := len(args) == 0
 			if !showType {
@@ -39,7 +52,11 @@ func NewListCmd(appCtx *app.Context) *cobra.Command {
 				return err
 			}
 
-			ui.RenderTemplateList(groups, quiet, showType)
+			if format != ui.ListFormatTable {
+				return ui.RenderTemplateListData(groups, format)
+			}
+
+			ui.RenderTemplateList(groups, quiet, showType, long)
 			return nil
 		},
 	}
@@ -60,6 +77,14 @@ func NewListCmd(appCtx *app.Context) *cobra.Command {
 		"Show compact output (name only)",
 	)
 
+	cmd.Flags().BoolVarP(
+		&long,
+		"long",
+		"l",
+		false,
+		"Show the version, tags, keywords, author, license, and homepage of each template",
+	)
+
 	cmd.Flags().StringSliceVarP(
 		&tags,
 		"tags",
@@ -68,6 +93,16 @@ func NewListCmd(appCtx *app.Context) *cobra.Command {
 		"Filter by tags (comma-separated). Matches templates that contain ANY of the specified tags.",
 	)
 
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		string(ui.ListFormatTable),
+		"Output format: table, json, yaml",
+	)
+
+	cmd.MarkFlagsMutuallyExclusive("quiet", "long")
+
 	return cmd
 }
 
@@ -91,8 +126,9 @@ func discoverTemplates(
 		}
 
 		groups = append(groups, ui.TemplateListGroup{
-			Source:  src.Name,
-			Entries: entries,
+			Source:     src.Name,
+			SourceType: string(src.Type),
+			Entries:    entries,
 		})
 	}
 
@@ -111,11 +147,24 @@ func discoverFromSource(src resolver.Source, filterType template.Type, filterTag
 	}
 
 	entries := make([]ui.TemplateListEntry, 0, len(templates))
-	for _, tmpl := range templates {
+	for dir, tmpl := range templates {
+		// Templates on disk are listed with their full path, embedded ones
+		// with their path inside the source.
+		if src.Dir != "" {
+			dir = filepath.Join(src.Dir, filepath.FromSlash(dir))
+		}
+
 		entries = append(entries, ui.TemplateListEntry{
 			Name:        tmpl.Name,
 			Type:        tmpl.Type,
+			Version:     tmpl.Version,
 			Description: tmpl.Description,
+			Tags:        tmpl.Tags,
+			Keywords:    tmpl.Keywords,
+			Author:      tmpl.Author,
+			License:     tmpl.License,
+			Homepage:    tmpl.Homepage,
+			Path:        dir,
 		})
 	}
 
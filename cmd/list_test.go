@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestListOutput(t *testing.T) {
+	appCtx := testAppContext(t, testTemplates)
+	dir := appCtx.TemplatesDir
+
+	t.Run("json", func(t *testing.T) {
+		out, err := runCmd(t, NewListCmd(appCtx), "--output", "json")
+		require.NoError(t, err)
+
+		var items []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &items))
+		assert.Equal(t, []map[string]any{
+			{"name": "app", "type": "project", "version": "1.0.0", "description": "An app", "source": "user", "path": filepath.Join(dir, "app")},
+			{"name": "auth", "type": "feature", "version": "1.2.0", "description": "Authentication", "tags": []any{"security"}, "source": "user", "path": filepath.Join(dir, "auth")},
+			{"name": "handler", "type": "component", "version": "0.1.0", "description": "An HTTP handler", "source": "user", "path": filepath.Join(dir, "handler")},
+		}, items)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		out, err := runCmd(t, NewListCmd(appCtx), "features", "-o", "yaml")
+		require.NoError(t, err)
+
+		var items []map[string]any
+		require.NoError(t, yaml.Unmarshal([]byte(out), &items))
+		assert.Equal(t, []map[string]any{
+			{"name": "auth", "type": "feature", "version": "1.2.0", "description": "Authentication", "tags": []any{"security"}, "source": "user", "path": filepath.Join(dir, "auth")},
+		}, items)
+	})
+
+	t.Run("empty json", func(t *testing.T) {
+		out, err := runCmd(t, NewListCmd(appCtx), "--output", "json", "--tags", "none")
+		require.NoError(t, err)
+		assert.JSONEq(t, "[]", out)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		out, err := runCmd(t, NewListCmd(appCtx), "--output", "xml")
+		assert.EqualError(t, err, `unsupported output format "xml" (use table, json, or yaml)`)
+		assert.Empty(t, out)
+	})
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/cli"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewNewCmd(appCtx *app.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new",
+		Short: "Create new blueprint resources",
+	}
+
+	cmd.AddCommand(newTemplateCmd(appCtx))
+
+	return cmd
+}
+
+func newTemplateCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		typeArg string
+		dir     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "template <name>",
+		Short: "Create a skeleton template for authoring",
+		Long: `Create a starter template in the user templates directory: a template.yaml with commented examples of
+variables, includes, files, and post_init, plus a sample .tmpl file.
+
+The template is written to <templates_dir>/<type>/<name> unless --dir is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			typ, err := cli.ValidateTemplateTypeArg(typeArg)
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			target := dir
+			if target == "" {
+				target = filepath.Join(appCtx.Config.TemplatesDir, typeArg, name)
+			}
+
+			files, err := scaffold.CreateTemplateSkeleton(target, name, typ)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderTemplateSkeleton(name, target, files)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&typeArg,
+		"type",
+		"projects",
+		"Template type (projects, features, components)",
+	)
+
+	cmd.Flags().StringVar(
+		&dir,
+		"dir",
+		"",
+		"Directory to create the template in",
+	)
+
+	return cmd
+}
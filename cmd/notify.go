@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/notify"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+)
+
+// notifyRun sends the summary of a run of a template to the configured
+// webhook and command. Dry runs and runs the user aborted are not reported.
+// A failed notification is reported as a warning and never fails the command.
+func notifyRun(appCtx *app.Context, templateName string, result *scaffold.Result, runErr error) {
+	cfg := appCtx.Config
+	if cfg.NotifyWebhook == "" && cfg.NotifyCommand == "" {
+		return
+	}
+	if appCtx.Options.DryRun || errors.Is(runErr, huh.ErrUserAborted) {
+		return
+	}
+
+	var outputDir string
+	var files int
+	if result != nil {
+		outputDir, _ = filepath.Abs(result.OutputDir)
+		files = len(result.FilesWritten)
+	}
+
+	summary := notify.NewSummary(templateName, templateVersion(appCtx, templateName), outputDir, files, runErr)
+	if err := notify.Send(cfg.NotifyWebhook, cfg.NotifyCommand, summary); err != nil {
+		ui.RenderNotifyFailed(err)
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/publish"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewPackCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pack <template-path> [archive]",
+		Short: "Validate and pack a template into a .bpt archive",
+		Long: `Pack a template into a single .bpt file, to attach it to a release, send it, or store it as
+an artifact.
+
+The template is validated like blueprint validate and must have no problems. A .bpt file is a
+.tar.gz archive of the template, under a directory named after it, and a blueprint-pack.json
+manifest with the name, type, and version of the template and the SHA-256 checksum of every file.
+The archive defaults to <name>-<version>.bpt in the current directory.
+
+A .bpt file can be used without unpacking it: blueprint init ./go-service-1.2.0.bpt reads it
+directly, and blueprint template install ./go-service-1.2.0.bpt installs it. Its checksums are
+verified every time.`,
+		Example: `  blueprint pack ./go-service
+  blueprint pack ./go-service dist/go-service.bpt`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if filepath.Base(dir) == template.FileName {
+				dir = filepath.Dir(dir)
+			}
+
+			var dest string
+			if len(args) > 1 {
+				dest = args[1]
+			}
+
+			report, err := lintTemplate(appCtx, dir)
+			if err != nil {
+				return err
+			}
+			if len(report.Issues) > 0 {
+				if err := ui.RenderLintReport(report, false); err != nil {
+					return err
+				}
+				return &template.LintError{Template: args[0], Issues: len(report.Issues)}
+			}
+
+			result, err := publish.Pack(dir, dest, appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderPackResult(result, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+}
+
+func NewUnpackCmd(appCtx *app.Context) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "unpack <archive> [dir]",
+		Short: "Verify and extract a .bpt archive",
+		Long: `Extract the template of a .bpt archive written by blueprint pack, to read or change it.
+
+The checksums of the archive are verified before anything is written. The template is extracted
+into a directory named after it in dir, which defaults to the current directory. An existing
+directory of that name is only replaced with --force.
+
+To install the template instead, pass the archive to blueprint template install.`,
+		Example: `  blueprint unpack go-service-1.2.0.bpt
+  blueprint unpack go-service-1.2.0.bpt ~/src/templates`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 1 {
+				dir = args[1]
+			}
+
+			result, err := install.Unpack(args[0], dir, force, appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderUnpackResult(result, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Replace an existing directory of the template",
+	)
+
+	return cmd
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/publish"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewPublishCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		registry string
+		branch   string
+		bump     string
+		version  string
+		force    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "publish <template-path>",
+		Short: "Validate, pack, and publish a template to a registry",
+		Long: `Publish a template to a registry, so that it can be installed with blueprint template install.
+
+The template is validated like blueprint validate and must have no problems. It is packed into a
+.tar.gz archive that is written to the registry as <name>/<name>-<version>.tar.gz, and recorded in
+the index.yaml of the registry with its SHA-256 checksum.
+
+A registry is a directory, or a git repository whose changes are committed and pushed. The registry
+and branch default to the registry and registry_branch settings. A branch that does not exist yet is
+created empty.
+
+With --bump, the version in template.yaml is incremented before the template is packed and written
+back once it is published. A version that is already published is only replaced with --force.`,
+		Example: `  blueprint publish ./go-service --registry ~/src/registry --bump patch
+  blueprint publish ./go-service --registry git@github.com:acme/templates.git --branch registry
+  blueprint publish ./go-service --version 2.0.0 --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if filepath.Base(dir) == template.FileName {
+				dir = filepath.Dir(dir)
+			}
+
+			if registry == "" {
+				registry = appCtx.Config.Registry
+			}
+			if branch == "" {
+				branch = appCtx.Config.RegistryBranch
+			}
+			if registry == "" {
+				return errors.New("no registry to publish to: pass --registry or set the registry setting")
+			}
+
+			report, err := lintTemplate(appCtx, dir)
+			if err != nil {
+				return err
+			}
+			if len(report.Issues) > 0 {
+				if err := ui.RenderLintReport(report, false); err != nil {
+					return err
+				}
+				return &template.LintError{Template: args[0], Issues: len(report.Issues)}
+			}
+
+			result, err := publish.Publish(dir, publish.Options{
+				Registry: registry,
+				Branch:   branch,
+				Bump:     publish.Bump(bump),
+				Version:  version,
+				Force:    force,
+				DryRun:   appCtx.Options.DryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			ui.RenderPublishResult(result, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&registry,
+		"registry",
+		"",
+		"Directory or git repository to publish to (default: the registry setting)",
+	)
+
+	cmd.Flags().StringVar(
+		&branch,
+		"branch",
+		"",
+		"Branch of a git registry (default: the registry_branch setting, or the default branch)",
+	)
+
+	cmd.Flags().StringVar(
+		&bump,
+		"bump",
+		"",
+		"Increment the version before publishing: major, minor, or patch",
+	)
+
+	cmd.Flags().StringVar(
+		&version,
+		"version",
+		"",
+		"Version to publish instead of the version in template.yaml",
+	)
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Replace a version that is already published",
+	)
+
+	return cmd
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/prompt"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewRemoveCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		target string
+		yes    bool
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a feature or component from a project",
+		Long: `Remove a feature or component from a project generated by blueprint, using what the project manifest
+records about it. <name> is the name of a feature template, or the name of a component listed by blueprint
+components list.
+
+The files the template wrote are deleted, and the code it injected into other files, at anchor comments or with
+Go edits, is taken out of them. Files changed since they were written are not deleted unless --force is given.
+The removal is journaled, so blueprint undo restores it.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			root, err := manifest.FindRoot(target)
+			if err != nil {
+				return err
+			}
+
+			plan, err := scaffold.Remove(root, name, force, true)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderRemovePlan(plan)
+			if appCtx.Options.DryRun {
+				return nil
+			}
+
+			if !yes {
+				if !appCtx.Options.Interactive() {
+					return fmt.Errorf("refusing to remove without confirmation; pass --yes to apply")
+				}
+
+				confirmed, err := prompt.NewEngine().Confirm(fmt.Sprintf("Remove %s?", name))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					return nil
+				}
+			}
+
+			result, err := scaffold.Remove(root, name, force, false)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderRemoved(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&target,
+		"target",
+		".",
+		"Directory of the project to remove the template from",
+	)
+
+	cmd.Flags().BoolVarP(
+		&yes,
+		"yes",
+		"y",
+		false,
+		"Remove the template without asking for confirmation",
+	)
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Delete files that were changed since they were written",
+	)
+
+	return cmd
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/prompt"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewRenameCmd(appCtx *app.Context) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <new-name> [dir]",
+		Short: "Rename a scaffolded project",
+		Long: `Rename a project generated by blueprint. Identifiers derived from the project name, such as the module
+path, binary name, or Kubernetes resource names, are updated in every file blueprint generated. The changes are
+shown as a diff before they are applied, and the manifest is updated to the new name.
+
+Only files recorded in the manifest are changed. The project directory itself is not renamed.`,
+		Args:        cobra.RangeArgs(1, 2),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := "."
+			if len(args) > 1 {
+				start = args[1]
+			}
+
+			root, err := manifest.FindRoot(start)
+			if err != nil {
+				return err
+			}
+
+			variable, err := projectNameVariable(appCtx, root)
+			if err != nil {
+				return err
+			}
+
+			plan, err := scaffold.PlanRename(root, variable, args[0])
+			if err != nil {
+				return err
+			}
+
+			ui.RenderRenamePlan(plan)
+			if len(plan.Changes) == 0 || appCtx.Options.DryRun {
+				return nil
+			}
+
+			if !yes {
+				if !appCtx.Options.Interactive() {
+					return fmt.Errorf("refusing to rename without confirmation; pass --yes to apply")
+				}
+
+				confirmed, err := prompt.NewEngine().Confirm(fmt.Sprintf("Rename %s to %s?", plan.OldName, plan.NewName))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					return nil
+				}
+			}
+
+			if err := plan.Apply(); err != nil {
+				return err
+			}
+
+			ui.RenderRenameApplied(plan)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&yes,
+		"yes",
+		"y",
+		false,
+		"Apply the changes without asking for confirmation",
+	)
+
+	return cmd
+}
+
+// projectNameVariable returns the name of the root template's project name
+// variable, falling back to the template definition for manifests written
+// before roles were recorded.
+func projectNameVariable(appCtx *app.Context, root string) (string, error) {
+	m, err := manifest.Load(root)
+	if err != nil {
+		return "", err
+	}
+
+	if name, ok := m.RoleVariable("0", string(template.RoleProjectName)); ok {
+		return name, nil
+	}
+
+	loaded, err := template.NewEngine(appCtx.Resolver).LoadTemplate(template.TemplateRef{Name: m.Template})
+	if err != nil {
+		return "", fmt.Errorf("find project name variable of %s: %w", m.Template, err)
+	}
+
+	v, err := loaded.Template.VariableByRole(template.RoleProjectName)
+	if err != nil {
+		return "", err
+	}
+
+	return v.Name, nil
+}
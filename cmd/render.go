@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/spf13/cobra"
+)
+
+func NewRenderCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		varFlags []string
+		varFile  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render <file>",
+		Short: "Render a single template file to stdout",
+		Long: `Render one template file with the given variables and print the result, to try out template
+expressions without scaffolding a whole template.
+
+Every function library is available, and so is the builtin _blueprint variable. Values from
+--var-file keep their YAML types; --var values are strings and override those of the file. Front
+matter is removed from the output, and its delimiters are used.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			values := make(map[string]any)
+			if varFile != "" {
+				if values, err = vars.LoadFile(varFile); err != nil {
+					return err
+				}
+			}
+			for _, f := range varFlags {
+				scope, key, value, err := parseVarFlag(f)
+				if err != nil {
+					return err
+				}
+				if scope != "" {
+					return fmt.Errorf("invalid variable %q: render takes no scope", f)
+				}
+				values[key] = value
+			}
+
+			builtins := scaffold.DetectBuiltins()
+			builtins.Locale = appCtx.Config.Locale
+			values[template.BuiltinVariable] = builtins.For(&template.Template{})
+
+			engine := template.NewEngine(appCtx.Resolver)
+			out, err := engine.RenderStandalone(content, template.NewTemplateContext(values), args[0])
+			if err != nil {
+				return err
+			}
+
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		"Set template variable (format: key=value)",
+	)
+
+	cmd.Flags().StringVar(
+		&varFile,
+		"var-file",
+		"",
+		"Read variables from a YAML or JSON file",
+	)
+
+	return cmd
+}
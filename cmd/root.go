@@ -5,10 +5,23 @@ import (
 
 	"github.com/dhanush0x96c/blueprint/internal/app"
 	"github.com/dhanush0x96c/blueprint/internal/config"
+	"github.com/dhanush0x96c/blueprint/internal/prompt"
 	"github.com/dhanush0x96c/blueprint/internal/ui"
 	"github.com/spf13/cobra"
 )
 
+// annotationAllowInvalidConfig marks commands that run even when the config
+// file cannot be loaded, such as the command that opens it for editing.
+const annotationAllowInvalidConfig = "blueprint/allow-invalid-config"
+
+// annotationNoWrite marks commands that can run with --no-write: they write
+// nothing, or honor --dry-run without running commands or accessing the
+// network.
+const annotationNoWrite = "blueprint/no-write"
+
+// noWrite is the annotation set on commands that can run with --no-write.
+var noWrite = map[string]string{annotationNoWrite: "true"}
+
 func NewRootCmd() *cobra.Command {
 	cfgLoader := config.Loader{
 		EnvPrefix: "BLUEPRINT",
@@ -25,11 +38,32 @@ func NewRootCmd() *cobra.Command {
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if options.NoColor || ui.NoColorRequested() {
+				options.NoColor = true
+				ui.SetPlain()
+				prompt.SetPlain()
+			}
+
+			if options.NoWrite {
+				if cmd.Annotations[annotationNoWrite] == "" {
+					return fmt.Errorf("%s cannot run with --no-write", cmd.CommandPath())
+				}
+				options.DryRun = true
+			}
+
 			cfg, err := cfgLoader.Load()
+			var ctx *app.Context
+			if err == nil {
+				ctx, err = app.NewContext(cfg, options)
+			}
 			if err != nil {
-				return fmt.Errorf("load config: %w", err)
+				if cmd.Annotations[annotationAllowInvalidConfig] == "" {
+					return fmt.Errorf("load config: %w", err)
+				}
+				if ctx, err = app.NewContext(&config.Config{Path: cfgLoader.ConfigFile}, options); err != nil {
+					return err
+				}
 			}
-			ctx := app.NewContext(cfg, options)
 			*appCtx = *ctx
 
 			return nil
@@ -51,6 +85,13 @@ func NewRootCmd() *cobra.Command {
 		"Enable verbose output",
 	)
 
+	cmd.PersistentFlags().BoolVar(
+		&options.CI,
+		"ci",
+		false,
+		"Disable all prompts and fail on missing input (implied when not run in a terminal)",
+	)
+
 	cmd.PersistentFlags().BoolVar(
 		&options.DryRun,
 		"dry-run",
@@ -58,9 +99,56 @@ func NewRootCmd() *cobra.Command {
 		"Preview actions without writing files",
 	)
 
+	cmd.PersistentFlags().BoolVar(
+		&options.NoWrite,
+		"no-write",
+		false,
+		"Inspect what a run would do without any side effects: implies --dry-run and also blocks post-init commands, git, and network access",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&options.Refresh,
+		"refresh",
+		false,
+		"Download template archives given by URL again instead of using the cached copy",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&options.NoColor,
+		"no-color",
+		false,
+		"Disable colors and Unicode symbols in output (also set by the NO_COLOR environment variable)",
+	)
+
 	cmd.AddCommand(NewInitCmd(appCtx))
+	cmd.AddCommand(NewAddCmd(appCtx))
+	cmd.AddCommand(NewRemoveCmd(appCtx))
+	cmd.AddCommand(NewComponentsCmd(appCtx))
 	cmd.AddCommand(NewListCmd(appCtx))
 	cmd.AddCommand(NewVersionCmd(appCtx))
+	cmd.AddCommand(NewExplainCmd(appCtx))
+	cmd.AddCommand(NewInfoCmd(appCtx))
+	cmd.AddCommand(NewGraphCmd(appCtx))
+	cmd.AddCommand(NewRenderCmd(appCtx))
+	cmd.AddCommand(NewAnalyzeCmd(appCtx))
+	cmd.AddCommand(NewCompatCmd(appCtx))
+	cmd.AddCommand(NewCleanCmd(appCtx))
+	cmd.AddCommand(NewNewCmd(appCtx))
+	cmd.AddCommand(NewRenameCmd(appCtx))
+	cmd.AddCommand(NewUndoCmd(appCtx))
+	cmd.AddCommand(NewBatchCmd(appCtx))
+	cmd.AddCommand(NewValidateCmd(appCtx))
+	cmd.AddCommand(NewTestCmd(appCtx))
+	cmd.AddCommand(NewSmokeCmd(appCtx))
+	cmd.AddCommand(NewDoctorCmd(appCtx))
+	cmd.AddCommand(NewContextCmd(appCtx))
+	cmd.AddCommand(NewSnapshotCmd(appCtx))
+	cmd.AddCommand(NewTemplateCmd(appCtx))
+	cmd.AddCommand(NewPublishCmd(appCtx))
+	cmd.AddCommand(NewPackCmd(appCtx))
+	cmd.AddCommand(NewUnpackCmd(appCtx))
+	cmd.AddCommand(NewConfigCmd(appCtx))
+	cmd.AddCommand(NewTelemetryCmd(appCtx))
 
 	return cmd
 }
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewSmokeCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		varFlags     []string
+		includeFlags []string
+		excludeFlags []string
+		keep         bool
+		skipPostInit bool
+		yes          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "smoke <template>",
+		Short: "Scaffold a template and run its verify commands",
+		Long: `Scaffold a template into a temporary directory with the default answers and run the verify commands
+the templates of the project declare, such as go build ./... and go test ./..., each in the output directory of
+its template. Post-init commands run first unless --skip-post-init is given.
+
+Verify commands are held to the post-init policy: the post_init_allow and post_init_deny patterns and the
+post_init_env setting apply to them, and in a terminal the commands of templates that are not trusted run only
+once confirmed, unless --yes is given.
+
+The template is a template name or the path of a template directory; a template at a path takes precedence over
+installed templates of the same name. Every verify command runs even when an earlier one failed, and the output
+of failed commands is shown. The command exits with a non-zero status when any command fails, so it can gate
+changes to a template repository in CI.
+
+The project is removed afterwards unless --keep is given.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTemplates(appCtx, "", false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars, err := parseVarFlags(varFlags)
+			if err != nil {
+				return err
+			}
+
+			enabledIncludes, err := parseIncludeFlags(includeFlags, excludeFlags)
+			if err != nil {
+				return err
+			}
+
+			lineEndings, _, err := outputSettings(appCtx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := postInitPolicy(appCtx, false)
+			if err != nil {
+				return err
+			}
+
+			scaffolder, templateName, err := smokeScaffolder(appCtx, args[0])
+			if err != nil {
+				return err
+			}
+
+			start := time.Now()
+			result, err := scaffolder.Smoke(scaffold.Options{
+				TemplateRef: template.TemplateRef{
+					Name: templateName,
+				},
+				Variables:       vars,
+				Defaults:        appCtx.Config.Defaults,
+				Mandated:        mandatedIncludes(appCtx),
+				LicenseHeader:   appCtx.Config.LicenseHeader,
+				Locale:          appCtx.Config.Locale,
+				LineEndings:     lineEndings,
+				FinalNewline:    appCtx.Config.FinalNewline,
+				EnabledIncludes: enabledIncludes,
+				SkipPostInit:    skipPostInit,
+				PostInitPolicy:  policy,
+				Interactive:     !yes && appCtx.Options.Interactive(),
+				OnEvent:         progressEvents(appCtx),
+			}, keep)
+
+			runErr := err
+			if runErr == nil {
+				runErr = smokeErr(result)
+			}
+			recordRun(appCtx, templateName, start, runErr)
+
+			if err != nil {
+				return fmt.Errorf("smoke test template %q: %w", templateName, err)
+			}
+
+			ui.RenderSmokeResult(result)
+			return runErr
+		},
+	}
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		`Set a template variable (format: key=value)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&includeFlags,
+		"include",
+		nil,
+		`Include a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&excludeFlags,
+		"exclude",
+		nil,
+		`Exclude a template feature (format: template-name)`,
+	)
+
+	cmd.Flags().BoolVarP(
+		&yes,
+		"yes",
+		"y",
+		false,
+		"Run post-init and verify commands without confirmation",
+	)
+
+	cmd.Flags().BoolVar(
+		&keep,
+		"keep",
+		false,
+		"Keep the scaffolded project and print its directory",
+	)
+
+	cmd.Flags().BoolVar(
+		&skipPostInit,
+		"skip-post-init",
+		false,
+		"Do not run post-init commands before the verify commands",
+	)
+
+	return cmd
+}
+
+// smokeScaffolder returns the scaffolder and template name for the template
+// argument of smoke. A template directory is added as a source ahead of the
+// configured sources.
+func smokeScaffolder(appCtx *app.Context, arg string) (*scaffold.Scaffolder, string, error) {
+	if _, err := os.Stat(filepath.Join(arg, template.FileName)); err != nil {
+		scaffolder, err := newScaffolder(appCtx)
+		return scaffolder, arg, err
+	}
+
+	loaded, err := template.NewEngine(appCtx.Resolver).LoadTemplateByPath(os.DirFS(arg), ".")
+	if err != nil {
+		return nil, "", err
+	}
+
+	sources := append([]resolver.Source{{
+		Name:       "TEST",
+		Type:       resolver.SourceTypeUser,
+		Filesystem: os.DirFS(arg),
+		Dir:        arg,
+	}}, appCtx.Sources...)
+	scaffolder := scaffold.NewScaffolder(resolver.NewChainResolver(sources...))
+	if err := scaffolder.EnableFuncLibraries(appCtx.Config.Functions...); err != nil {
+		return nil, "", fmt.Errorf("config: %w", err)
+	}
+	return scaffolder, loaded.Template.Name, nil
+}
+
+// smokeErr returns the error of a smoke test whose commands failed.
+func smokeErr(result *scaffold.SmokeResult) error {
+	if err := result.Scaffold.PostInitErr(); err != nil {
+		return err
+	}
+	failed := 0
+	for _, v := range result.Verify {
+		if !v.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return &scaffold.SmokeFailedError{Template: result.Template, Failed: failed, Total: len(result.Verify)}
+	}
+	return nil
+}
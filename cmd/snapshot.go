@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/cli"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewSnapshotCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		typeArg  string
+		dir      string
+		varFlags []string
+		exclude  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "snapshot <project-dir> <name>",
+		Short: "Create a template from an existing project",
+		Long: `Create a template from an existing project directory. Every file is copied into the template, and
+occurrences of the values given with --var are replaced with references to the variables, in file contents as well
+as in paths. A draft template.yaml declaring the variables is written next to the files.
+
+For project templates, the project name defaults to the name of the project directory. The template is written to
+<templates_dir>/<type>/<name> unless --dir is given.`,
+		Example: `  blueprint snapshot ./my-service go-service \
+    --var project_name=my-service \
+    --var module_path=github.com/acme/my-service`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			typ, err := cli.ValidateTemplateTypeArg(typeArg)
+			if err != nil {
+				return err
+			}
+
+			source, name := args[0], args[1]
+
+			variables, err := parseSnapshotVars(varFlags)
+			if err != nil {
+				return err
+			}
+			if typ == template.TypeProject && !hasSnapshotVar(variables, "project_name") {
+				abs, err := filepath.Abs(source)
+				if err != nil {
+					return err
+				}
+				variables = append([]scaffold.SnapshotVariable{{Name: "project_name", Value: filepath.Base(abs)}}, variables...)
+			}
+
+			target := dir
+			if target == "" {
+				target = filepath.Join(appCtx.Config.TemplatesDir, typeArg, name)
+			}
+
+			result, err := scaffold.CreateTemplateSnapshot(scaffold.SnapshotOptions{
+				Source:    source,
+				Dir:       target,
+				Name:      name,
+				Type:      typ,
+				Variables: variables,
+				Exclude:   exclude,
+			})
+			if err != nil {
+				return err
+			}
+
+			ui.RenderSnapshot(name, target, variables, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&typeArg,
+		"type",
+		"projects",
+		"Template type (projects, features, components)",
+	)
+
+	cmd.Flags().StringVar(
+		&dir,
+		"dir",
+		"",
+		"Directory to create the template in",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		`Replace a value with a variable (format: name=value)`,
+	)
+
+	cmd.Flags().StringArrayVar(
+		&exclude,
+		"exclude",
+		nil,
+		"Leave out paths matching a pattern (e.g. node_modules, *.log)",
+	)
+
+	return cmd
+}
+
+// parseSnapshotVars parses --var flags of the snapshot command in the order
+// they are given.
+func parseSnapshotVars(flags []string) ([]scaffold.SnapshotVariable, error) {
+	var variables []scaffold.SnapshotVariable
+	for _, f := range flags {
+		scope, key, value, err := parseVarFlag(f)
+		if err != nil {
+			return nil, err
+		}
+		if scope != "" || key == "" {
+			return nil, fmt.Errorf("invalid variable format %q: expected name=value", f)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("variable %s: value must not be empty", key)
+		}
+		if hasSnapshotVar(variables, key) {
+			return nil, fmt.Errorf("variable %s is given more than once", key)
+		}
+		variables = append(variables, scaffold.SnapshotVariable{Name: key, Value: value})
+	}
+	return variables, nil
+}
+
+func hasSnapshotVar(variables []scaffold.SnapshotVariable, name string) bool {
+	for _, v := range variables {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
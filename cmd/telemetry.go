@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/config"
+	"github.com/dhanush0x96c/blueprint/internal/telemetry"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewTelemetryCmd(appCtx *app.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Turn usage telemetry on or off",
+		Long: `Turn opt-in usage telemetry on or off, or show its status.
+
+With telemetry on, every run of init and batch is counted per template version on this
+machine, and sent to telemetry_endpoint when one is configured, so that template authors
+can measure adoption and failure rates. Only the template name and version, whether the
+run succeeded, and its duration are recorded; variable values, paths, and file contents
+never are. Telemetry is off by default, and DO_NOT_TRACK=1 turns it off regardless of the
+configuration.`,
+	}
+
+	cmd.AddCommand(newTelemetrySetCmd(appCtx, "on", true))
+	cmd.AddCommand(newTelemetrySetCmd(appCtx, "off", false))
+	cmd.AddCommand(newTelemetryStatusCmd(appCtx))
+
+	return cmd
+}
+
+func newTelemetrySetCmd(appCtx *app.Context, use string, enabled bool) *cobra.Command {
+	short := "Turn telemetry off"
+	if enabled {
+		short = "Turn telemetry on"
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.Set(appCtx.Config.Path, "telemetry", fmt.Sprint(enabled)); err != nil {
+				return err
+			}
+			fmt.Printf("Telemetry turned %s in %s\n", use, appCtx.Config.Path)
+			return nil
+		},
+	}
+}
+
+func newTelemetryStatusCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:         "status",
+		Short:       "Show whether telemetry is on and the runs recorded",
+		Args:        cobra.NoArgs,
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status := ui.TelemetryStatus{
+				Enabled:    telemetryEnabled(appCtx),
+				DoNotTrack: telemetry.DoNotTrack(),
+				Endpoint:   appCtx.Config.TelemetryEndpoint,
+			}
+
+			statsPath, err := telemetry.DefaultStatsPath()
+			if err == nil {
+				status.StatsPath = statsPath
+				if status.Stats, err = telemetry.LoadStats(statsPath); err != nil {
+					return err
+				}
+			}
+
+			ui.RenderTelemetryStatus(status)
+			return nil
+		},
+	}
+}
+
+// telemetryEnabled reports whether runs should be recorded.
+func telemetryEnabled(appCtx *app.Context) bool {
+	return appCtx.Config.Telemetry && !telemetry.DoNotTrack()
+}
+
+// recordRun records a run of a template that started at start, when
+// telemetry is on. Dry runs and runs the user aborted are not recorded.
+// Recording is best effort and never fails the command.
+func recordRun(appCtx *app.Context, templateName string, start time.Time, err error) {
+	if !telemetryEnabled(appCtx) || appCtx.Options.DryRun || errors.Is(err, huh.ErrUserAborted) {
+		return
+	}
+
+	event := telemetry.NewEvent(templateName, templateVersion(appCtx, templateName), start, err == nil)
+
+	if statsPath, err := telemetry.DefaultStatsPath(); err == nil {
+		_ = telemetry.Record(statsPath, event)
+	}
+	if appCtx.Config.TelemetryEndpoint != "" {
+		_ = telemetry.Send(appCtx.Config.TelemetryEndpoint, event)
+	}
+}
+
+// templateVersion returns the version of a template, or an empty string when
+// it cannot be loaded.
+func templateVersion(appCtx *app.Context, templateName string) string {
+	resolved, err := appCtx.Resolver.Resolve(template.TemplateRef{Name: templateName})
+	if err != nil {
+		return ""
+	}
+	meta, err := template.NewLoader().LoadMetadata(resolved.FS, path.Join(resolved.Path, template.FileName))
+	if err != nil {
+		return ""
+	}
+	return meta.Version
+}
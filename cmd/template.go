@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/publish"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewTemplateCmd(appCtx *app.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "template",
+		Aliases: []string{"templates"},
+		Short:   "Install and maintain templates",
+	}
+
+	cmd.AddCommand(newTemplateInstallCmd(appCtx))
+	cmd.AddCommand(newTemplateUpdateCmd(appCtx))
+	cmd.AddCommand(newTemplatePullCmd(appCtx))
+	cmd.AddCommand(newTemplatePushCmd(appCtx))
+	cmd.AddCommand(newTemplateUninstallCmd(appCtx))
+	cmd.AddCommand(newTemplateListCmd(appCtx))
+	cmd.AddCommand(newExtractIncludeCmd(appCtx))
+	cmd.AddCommand(newTemplateMigrateCmd(appCtx))
+
+	return cmd
+}
+
+func newTemplateInstallCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		ref   string
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install <source>",
+		Short: "Install templates into the user templates directory",
+		Long: `Install every template found in a git repository, an archive, an OCI registry, or a local directory
+into the user templates directory, and record where it came from.
+
+Archives (.tar.gz, .tgz, .tar, .zip) may be local files or HTTP URLs. oci://registry/repository[:tag]
+references are pulled from an OCI registry, at the latest tag when none is given; a reference ending in
+@sha256:<digest> pins the artifact, and a pull that does not match it fails. Other URLs, git@host:repo
+addresses, and paths ending in .git are cloned with git. Anything else is copied from a local directory.
+
+Each template is installed into a directory named after it. A template that already exists is only
+replaced when it was installed from the same source, or with --force.`,
+		Example: `  blueprint template install https://github.com/acme/blueprint-templates.git
+  blueprint template install https://github.com/acme/blueprint-templates.git --ref v1.2.0
+  blueprint template install ./templates.tar.gz
+  blueprint template install oci://ghcr.io/acme/templates/go-service:1.4.0
+  blueprint template install ~/src/my-templates`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := appCtx.TemplatesDir
+			changes, err := install.Install(dir, install.Options{
+				Source:   args[0],
+				Ref:      ref,
+				Force:    force,
+				DryRun:   appCtx.Options.DryRun,
+				Verifier: appCtx.Verifier,
+			})
+			if err != nil {
+				return err
+			}
+
+			ui.RenderInstallChanges(ui.ActionInstall, changes, dir, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&ref,
+		"ref",
+		"",
+		"Branch or tag to install from a git source",
+	)
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Replace templates of the same name that were not installed from this source",
+	)
+
+	return cmd
+}
+
+func newTemplateUpdateCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "update [name...]",
+		Short: "Update installed templates from their sources",
+		Long: `Install templates again from the sources they were installed from, at the same ref. Without a name,
+every installed template is updated.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := appCtx.TemplatesDir
+			changes, err := install.Update(dir, args, appCtx.Verifier, appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderInstallChanges(ui.ActionUpdate, changes, dir, appCtx.Options.DryRun)
+			if root := install.GitRoot(dir); root != "" {
+				ui.RenderTemplatesRepoHint(root)
+			}
+			return nil
+		},
+	}
+}
+
+func newTemplatePullCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull",
+		Short: "Update a templates directory that is a git repository",
+		Long: `Fast-forward the git repository the user templates directory belongs to from its upstream
+branch. This is how a templates directory that is a clone or a worktree of a shared templates
+repository is kept up to date; templates installed with blueprint template install are updated
+with blueprint template update instead.
+
+A pull that cannot fast-forward, or that would overwrite local changes, fails without changing
+anything. With --dry-run, the upstream branch is fetched and the files a pull would change are listed.`,
+		Example: `  git clone git@github.com:acme/blueprint-templates.git ~/src/blueprint-templates
+  blueprint config set templates_dir ~/src/blueprint-templates
+  blueprint template pull`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := install.Pull(appCtx.TemplatesDir, appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderPullResult(result, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+}
+
+func newTemplatePushCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "push <template-path> <reference>",
+		Short: "Validate, pack, and push a template to an OCI registry",
+		Long: `Push a template to an OCI registry, such as GHCR or Harbor, so that it can be installed with
+blueprint template install <reference>.
+
+The template is validated like blueprint validate and must have no problems. It is packed like
+blueprint publish packs it and pushed as an artifact of type application/vnd.blueprint.template.v1,
+tagged with the tag of the reference, or with the template's version when the reference has none.
+The digest of the pushed artifact is printed, for installing it pinned.
+
+Credentials are read from BLUEPRINT_OCI_USERNAME and BLUEPRINT_OCI_PASSWORD, else from the Docker
+config file that docker login writes. Registries on localhost are reached over plain HTTP.`,
+		Example: `  blueprint template push ./go-service oci://ghcr.io/acme/templates/go-service
+  blueprint template push ./go-service oci://harbor.acme.dev/platform/go-service:stable
+  blueprint template push ./go-service oci://localhost:5000/go-service --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			if filepath.Base(dir) == template.FileName {
+				dir = filepath.Dir(dir)
+			}
+
+			report, err := lintTemplate(appCtx, dir)
+			if err != nil {
+				return err
+			}
+			if len(report.Issues) > 0 {
+				if err := ui.RenderLintReport(report, false); err != nil {
+					return err
+				}
+				return &template.LintError{Template: args[0], Issues: len(report.Issues)}
+			}
+
+			result, err := publish.Push(dir, args[1], appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderPushResult(result, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+}
+
+func newTemplateUninstallCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall <name...>",
+		Short: "Remove installed templates",
+		Long: `Remove templates installed with blueprint template install from the user templates directory.
+Templates copied into the directory by hand are left alone.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := appCtx.TemplatesDir
+			changes, err := install.Uninstall(dir, args, appCtx.Options.DryRun)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderInstallChanges(ui.ActionUninstall, changes, dir, appCtx.Options.DryRun)
+			return nil
+		},
+	}
+}
+
+func newTemplateListCmd(appCtx *app.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:         "list",
+		Short:       "List installed templates",
+		Long:        "List the templates installed with blueprint template install, with their versions and sources.",
+		Args:        cobra.NoArgs,
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := appCtx.TemplatesDir
+			records, err := install.List(dir)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderInstalledTemplates(records, dir)
+			if root := install.GitRoot(dir); root != "" {
+				ui.RenderTemplatesRepoHint(root)
+			}
+			return nil
+		},
+	}
+}
+
+func newExtractIncludeCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		files    []string
+		dir      string
+		varFlags []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "extract-include <template-dir> <include-name>",
+		Short: "Move files of a template into a new include",
+		Long: `Move file entries of a template into a new feature template that the original includes by default.
+
+Each --file names the src of a file entry in template.yaml. The entries are removed from the template, and
+their files are moved to the same paths in the include, along with a copy of the template's partials. The
+include declares the variables the moved files refer to and inherits them from the template.
+
+The template is rendered before and after the change with the values given with --var and the defaults of
+its variables, and every file must come out identical. Otherwise nothing is changed.
+
+The include is written next to the template unless --dir is given.`,
+		Example: `  blueprint template extract-include ./templates/project/go-service ci \
+    --file .github \
+    --file Makefile.tmpl`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templateDir, name := args[0], args[1]
+
+			vars, err := parseVarFlags(varFlags)
+			if err != nil {
+				return err
+			}
+
+			target := dir
+			if target == "" {
+				target = filepath.Join(filepath.Dir(filepath.Clean(templateDir)), name)
+			}
+
+			result, err := scaffold.ExtractInclude(scaffold.ExtractOptions{
+				TemplateDir: templateDir,
+				Name:        name,
+				Dir:         target,
+				Files:       files,
+				Variables:   vars,
+				Defaults:    appCtx.Config.Defaults,
+				Functions:   appCtx.Config.Functions,
+				Sources:     appCtx.Sources,
+				Mandated:    mandatedIncludes(appCtx),
+			})
+			if err != nil {
+				return err
+			}
+
+			ui.RenderExtract(name, target, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(
+		&files,
+		"file",
+		nil,
+		"Src of a file entry to move into the include (repeatable)",
+	)
+
+	cmd.Flags().StringVar(
+		&dir,
+		"dir",
+		"",
+		"Directory to create the include in",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&varFlags,
+		"var",
+		nil,
+		`Set a template variable for the verification render (format: key=value)`,
+	)
+
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func newTemplateMigrateCmd(appCtx *app.Context) *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate <dir>",
+		Short: "Rewrite template.yaml files to the newest schema",
+		Long: fmt.Sprintf(`Rewrite every template.yaml under a directory, or a single template.yaml, to the newest template
+schema (schema %d), so that templates written for an older schema can adopt new features without manual edits.
+
+Only the parts of a file that an old schema spells differently are rewritten, and the schema field is set;
+comments, blank lines, and quoting are kept. Directories starting with a dot are skipped.
+
+Use --dry-run to print the changes as a diff without writing them, and --check to fail when any template
+still needs migrating, for example in CI.`, template.SchemaVersion),
+		Example: `  blueprint template migrate ./templates
+  blueprint template migrate ./templates/go-service/template.yaml --dry-run`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			write := !check && !appCtx.Options.DryRun
+			migrated, err := scaffold.MigrateTemplates(args[0], write)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderMigrated(migrated, write)
+
+			if check && len(migrated) > 0 {
+				return fmt.Errorf("%d template(s) need migrating to schema %d", len(migrated), template.SchemaVersion)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&check,
+		"check",
+		false,
+		"Report templates that need migrating and fail if there are any, without writing them",
+	)
+
+	return cmd
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewTestCmd(appCtx *app.Context) *cobra.Command {
+	var update bool
+
+	cmd := &cobra.Command{
+		Use:   "test <template-path> [case...]",
+		Short: "Run the test cases of a template",
+		Long: `Render a template with the input of each of its test cases and compare the result with the files
+the case expects. Test cases live in the template's tests/ directory, one directory per case:
+
+  tests/<case>/case.yaml    variables and include selections to render with
+  tests/<case>/expected/    the project files the case must produce
+
+Cases are rendered in memory without prompting, running post-init commands or detecting a Go toolchain, and the
+builtin variable has fixed values, so the result only changes when the template does. Missing, unexpected and
+changed files are reported, with a diff of each changed file. The command exits with a non-zero status when any
+case fails, so it can gate changes to a template repository in CI.
+
+Use --update to write the rendered files as the expected files of each case.`,
+		Args:        cobra.MinimumNArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if update && appCtx.Options.NoWrite {
+				return fmt.Errorf("--update cannot be used with --no-write")
+			}
+
+			dir := args[0]
+			if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+				dir = filepath.Dir(dir)
+			}
+
+			loaded, err := template.NewEngine(appCtx.Resolver).LoadTemplateByPath(os.DirFS(dir), ".")
+			if err != nil {
+				return err
+			}
+			name := loaded.Template.Name
+
+			cases, err := scaffold.LoadTestCases(dir)
+			if err != nil {
+				return err
+			}
+			if selected := args[1:]; len(selected) > 0 {
+				for _, c := range selected {
+					if !slices.ContainsFunc(cases, func(tc scaffold.TestCase) bool { return tc.Name == c }) {
+						return fmt.Errorf("template %s has no test case %q", name, c)
+					}
+				}
+				cases = slices.DeleteFunc(cases, func(tc scaffold.TestCase) bool {
+					return !slices.Contains(selected, tc.Name)
+				})
+			}
+
+			// The template under test takes precedence over installed
+			// templates of the same name; its includes resolve as usual.
+			sources := append([]resolver.Source{{
+				Name:       "TEST",
+				Type:       resolver.SourceTypeUser,
+				Filesystem: os.DirFS(dir),
+				Dir:        dir,
+			}}, appCtx.Sources...)
+			scaffolder := scaffold.NewScaffolder(resolver.NewChainResolver(sources...))
+			if err := scaffolder.EnableFuncLibraries(appCtx.Config.Functions...); err != nil {
+				return fmt.Errorf("config: %w", err)
+			}
+
+			results := make([]*scaffold.TestResult, 0, len(cases))
+			failed := 0
+			for _, tc := range cases {
+				result := scaffolder.RunTest(name, tc, update)
+				if !result.Passed() {
+					failed++
+				}
+				results = append(results, result)
+			}
+
+			ui.RenderTestResults(name, results)
+
+			if failed > 0 {
+				return &scaffold.TestFailedError{Template: name, Failed: failed, Total: len(cases)}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&update,
+		"update",
+		false,
+		"Write the rendered files as the expected files of each case",
+	)
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/prompt"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewUndoCmd(appCtx *app.Context) *cobra.Command {
+	var (
+		yes   bool
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "undo [dir]",
+		Short: "Revert the last scaffold run in a project",
+		Long: `Revert the most recent run of blueprint init or add in a project. Files the run created are removed,
+files it overwrote or removed are restored from the backups kept in .blueprint/journal/, and directories it created
+are removed once they are empty. Running undo again reverts the run before that.
+
+Files changed since the run are not reverted unless --force is given. Changes made by post-init commands are not
+recorded and stay in place.`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := "."
+			if len(args) > 0 {
+				start = args[0]
+			}
+
+			root, err := manifest.FindRoot(start)
+			if err != nil {
+				return err
+			}
+
+			plan, err := scaffold.Undo(root, force, true)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderUndoPlan(plan)
+			if appCtx.Options.DryRun {
+				return nil
+			}
+
+			if !yes {
+				if !appCtx.Options.Interactive() {
+					return fmt.Errorf("refusing to undo without confirmation; pass --yes to apply")
+				}
+
+				confirmed, err := prompt.NewEngine().Confirm(fmt.Sprintf("Undo the run of %s?", plan.Template))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					return nil
+				}
+			}
+
+			result, err := scaffold.Undo(root, force, false)
+			if err != nil {
+				return err
+			}
+
+			ui.RenderUndoApplied(result)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(
+		&yes,
+		"yes",
+		"y",
+		false,
+		"Undo the run without asking for confirmation",
+	)
+
+	cmd.Flags().BoolVarP(
+		&force,
+		"force",
+		"f",
+		false,
+		"Revert files that were changed since the run",
+	)
+
+	return cmd
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/app"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func NewValidateCmd(appCtx *app.Context) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "validate <template-path>",
+		Short: "Check a template for problems",
+		Long: `Check a template directory for the problems template authors are likely to make: unknown or invalid
+fields in template.yaml, missing source files, template files that do not parse, includes that cannot be
+resolved or include each other, and variables that are used but never declared or declared but never used.
+
+All problems are reported at once. The command exits with a non-zero status when any are found, so it can
+gate changes to a template repository in CI.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: noWrite,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := lintTemplate(appCtx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := ui.RenderLintReport(report, asJSON); err != nil {
+				return err
+			}
+
+			if len(report.Issues) > 0 {
+				return &template.LintError{Template: args[0], Issues: len(report.Issues)}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&asJSON,
+		"json",
+		false,
+		"Output as JSON",
+	)
+
+	return cmd
+}
+
+// lintTemplate checks the template at source, a template directory or its
+// template.yaml, and reports the problems found.
+func lintTemplate(appCtx *app.Context, source string) (*ui.LintReport, error) {
+	dir, root := source, "."
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir, root = filepath.Dir(dir), filepath.Base(dir)
+	}
+
+	engine := template.NewEngine(appCtx.Resolver)
+	for _, name := range appCtx.Config.Functions {
+		if err := engine.EnableFuncLibrary(name); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	issues, err := engine.Lint(os.DirFS(dir), root, appCtx.Config.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ui.LintReport{Source: source, Issues: make([]ui.LintIssueInfo, 0, len(issues))}
+	for _, issue := range issues {
+		report.Issues = append(report.Issues, ui.LintIssueInfo{
+			Template: issue.Template,
+			File:     issue.File,
+			Message:  issue.Message,
+		})
+	}
+	return report, nil
+}
@@ -10,9 +10,10 @@ import (
 
 func NewVersionCmd(appCtx *app.Context) *cobra.Command {
 	return &cobra.Command{
-		Use:   "version",
-		Short: "Print version information",
-		Long:  "Print the version, commit hash, and build date of Blueprint.",
+		Use:         "version",
+		Short:       "Print version information",
+		Long:        "Print the version, commit hash, and build date of Blueprint.",
+		Annotations: noWrite,
 		Run: func(cmd *cobra.Command, args []string) {
 			if appCtx.Options.Verbose {
 				fmt.Printf("Blueprint %s\n", version.Version)
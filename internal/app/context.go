@@ -1,31 +1,46 @@
 package app
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/dhanush0x96c/blueprint/internal/builtin/templates"
 	"github.com/dhanush0x96c/blueprint/internal/config"
+	"github.com/dhanush0x96c/blueprint/internal/install"
 	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/signature"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 )
 
 // Context holds runtime dependencies for the application.
 type Context struct {
-	Config   *config.Config
-	Sources  []resolver.Source
-	Resolver template.Resolver
-	Options  Options
+	Config       *config.Config
+	TemplatesDir string // Config.TemplatesDir with symlinks resolved
+	Sources      []resolver.Source
+	Resolver     template.Resolver
+	Options      Options
+
+	// Verifier checks the signatures of templates downloaded from the
+	// network; nil unless verify_signatures is set.
+	Verifier *signature.Verifier
 }
 
 // Options holds CLI flags and runtime options.
 type Options struct {
 	Verbose bool
 	DryRun  bool
+	NoWrite bool // Implies DryRun; commands with other side effects refuse to run
+	CI      bool // Never prompt; fail on missing input
+	NoColor bool // No colors or Unicode symbols in output; set by --no-color or NO_COLOR
+	Refresh bool // Download template archives again instead of using the cache
 }
 
-// NewContext creates a new application context.
-func NewContext(cfg *config.Config, opts Options) *Context {
-	localFS := os.DirFS(cfg.TemplatesDir)
+// NewContext creates a new application context. It fails if signatures are
+// to be verified without valid trusted keys.
+func NewContext(cfg *config.Config, opts Options) (*Context, error) {
+	templatesDir := resolveTemplatesDir(cfg.TemplatesDir)
+	localFS := os.DirFS(templatesDir)
 	builtinFS := templates.Templates
 
 	sources := []resolver.Source{
@@ -33,6 +48,7 @@ func NewContext(cfg *config.Config, opts Options) *Context {
 			Name:       "USER",
 			Type:       resolver.SourceTypeUser,
 			Filesystem: localFS,
+			Dir:        templatesDir,
 		},
 		{
 			Name:       "BUILTIN",
@@ -41,10 +57,42 @@ func NewContext(cfg *config.Config, opts Options) *Context {
 		},
 	}
 
+	var verifier *signature.Verifier
+	if cfg.VerifySignatures {
+		var err error
+		if verifier, err = signature.NewVerifier(cfg.TrustedKeys); err != nil {
+			return nil, fmt.Errorf("verify_signatures: %w", err)
+		}
+	}
+
+	chain := resolver.NewChainResolver(sources...)
+	if cache, err := install.NewArchiveCache(); err == nil {
+		cache.Refresh = opts.Refresh
+		cache.Offline = opts.NoWrite
+		cache.Verifier = verifier
+		chain = chain.WithArchives(cache)
+	}
+
 	return &Context{
-		Config:   cfg,
-		Sources:  sources,
-		Options:  opts,
-		Resolver: resolver.NewChainResolver(sources...),
+		Config:       cfg,
+		TemplatesDir: templatesDir,
+		Sources:      sources,
+		Options:      opts,
+		Resolver:     chain,
+		Verifier:     verifier,
+	}, nil
+}
+
+// resolveTemplatesDir resolves symlinks in the templates directory, so that
+// a directory linked to a checkout elsewhere is walked like a plain one. A
+// directory that does not exist yet is used as configured.
+func resolveTemplatesDir(dir string) string {
+	if dir == "" {
+		return dir
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return dir
 	}
+	return resolved
 }
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Interactive reports whether prompts may be shown. Prompts are disabled with
+// --ci and whenever stdin or stdout is not a terminal, so that pipelines fail
+// on missing input instead of hanging.
+func (o Options) Interactive() bool {
+	return !o.CI && isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -2,5 +2,69 @@ package config
 
 // Config is the root configuration model for the application.
 type Config struct {
-	TemplatesDir string `yaml:"templates_dir"`
+	// Path is the config file the configuration was loaded from. The file
+	// may not exist.
+	Path string `yaml:"-"`
+
+	TemplatesDir string         `yaml:"templates_dir"`
+	Defaults     map[string]any `yaml:"defaults,omitempty"`
+
+	// MandatedIncludes lists includes, keyed by template type, that are
+	// composed into every template of that type.
+	MandatedIncludes map[string][]string `yaml:"mandated_includes,omitempty"`
+
+	// LicenseHeader is prepended as a comment to every generated source file
+	// of a known type. It overrides the license_header of templates.
+	LicenseHeader string `yaml:"license_header,omitempty"`
+
+	// Locale selects the locale variants of template files, e.g. de or
+	// pt-BR, for templates that do not prompt for one.
+	Locale string `yaml:"locale,omitempty"`
+
+	// LineEndings converts the line endings of generated text files to lf,
+	// crlf, or native, overriding the line_endings of templates, and
+	// FinalNewline terminates the last line of every generated text file.
+	LineEndings  string `yaml:"line_endings,omitempty"`
+	FinalNewline bool   `yaml:"final_newline,omitempty"`
+
+	// Backup selects how files overwritten with --force are backed up:
+	// suffix (the default) copies each to <name>.bak, tree into
+	// .blueprint/backups/<timestamp>/ of the project, and none keeps no copy.
+	Backup string `yaml:"backup,omitempty"`
+
+	// Functions lists optional template function libraries enabled for every
+	// template.
+	Functions []string `yaml:"functions,omitempty"`
+
+	// VerifySignatures refuses templates downloaded from the network unless
+	// they are archives signed with minisign by one of TrustedKeys, the
+	// minisign public keys of the publishers to trust.
+	VerifySignatures bool     `yaml:"verify_signatures,omitempty"`
+	TrustedKeys      []string `yaml:"trusted_keys,omitempty"`
+
+	// PostInitAllow and PostInitDeny are patterns of post-init commands, in
+	// which * matches any text: commands matching a denied pattern never run,
+	// and when patterns are allowed, only commands matching one of them run.
+	// PostInitEnv runs commands with the environment of Blueprint (inherit,
+	// the default) or only the variables they need (restricted).
+	PostInitAllow []string `yaml:"post_init_allow,omitempty"`
+	PostInitDeny  []string `yaml:"post_init_deny,omitempty"`
+	PostInitEnv   string   `yaml:"post_init_env,omitempty"`
+
+	// Registry is the directory or git repository templates are published
+	// to, and RegistryBranch the branch of a git registry.
+	Registry       string `yaml:"registry,omitempty"`
+	RegistryBranch string `yaml:"registry_branch,omitempty"`
+
+	// Telemetry enables recording the runs of templates, and
+	// TelemetryEndpoint is the URL each run is also sent to. Telemetry is off
+	// unless enabled.
+	Telemetry         bool   `yaml:"telemetry,omitempty"`
+	TelemetryEndpoint string `yaml:"telemetry_endpoint,omitempty"`
+
+	// NotifyWebhook is the URL the summary of every init and batch run is
+	// posted to, and NotifyCommand a shell command that receives the summary
+	// on stdin.
+	NotifyWebhook string `yaml:"notify_webhook,omitempty"`
+	NotifyCommand string `yaml:"notify_command,omitempty"`
 }
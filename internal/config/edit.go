@@ -0,0 +1,395 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/signature"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// Keys lists the settings that can be read and written by key. Variable
+// defaults and mandated includes are addressed per entry, as defaults.<name>
+// and mandated_includes.<type>.
+var Keys = []string{
+	"templates_dir",
+	"license_header",
+	"locale",
+	"line_endings",
+	"final_newline",
+	"backup",
+	"functions",
+	"verify_signatures",
+	"trusted_keys",
+	"post_init_allow",
+	"post_init_deny",
+	"post_init_env",
+	"registry",
+	"registry_branch",
+	"telemetry",
+	"telemetry_endpoint",
+	"notify_webhook",
+	"notify_command",
+	"defaults.<name>",
+	"mandated_includes.<type>",
+}
+
+// Entry is a setting and its value.
+type Entry struct {
+	Key   string
+	Value any
+}
+
+// Entries returns every setting of cfg that has a value, sorted by key.
+func Entries(cfg *Config) []Entry {
+	entries := []Entry{{Key: "templates_dir", Value: cfg.TemplatesDir}}
+
+	if cfg.LicenseHeader != "" {
+		entries = append(entries, Entry{Key: "license_header", Value: cfg.LicenseHeader})
+	}
+	if cfg.Locale != "" {
+		entries = append(entries, Entry{Key: "locale", Value: cfg.Locale})
+	}
+	if cfg.LineEndings != "" {
+		entries = append(entries, Entry{Key: "line_endings", Value: cfg.LineEndings})
+	}
+	if cfg.FinalNewline {
+		entries = append(entries, Entry{Key: "final_newline", Value: cfg.FinalNewline})
+	}
+	if cfg.Backup != "" {
+		entries = append(entries, Entry{Key: "backup", Value: cfg.Backup})
+	}
+	if len(cfg.Functions) > 0 {
+		entries = append(entries, Entry{Key: "functions", Value: cfg.Functions})
+	}
+	if cfg.VerifySignatures {
+		entries = append(entries, Entry{Key: "verify_signatures", Value: cfg.VerifySignatures})
+	}
+	if len(cfg.TrustedKeys) > 0 {
+		entries = append(entries, Entry{Key: "trusted_keys", Value: cfg.TrustedKeys})
+	}
+	if len(cfg.PostInitAllow) > 0 {
+		entries = append(entries, Entry{Key: "post_init_allow", Value: cfg.PostInitAllow})
+	}
+	if len(cfg.PostInitDeny) > 0 {
+		entries = append(entries, Entry{Key: "post_init_deny", Value: cfg.PostInitDeny})
+	}
+	if cfg.PostInitEnv != "" {
+		entries = append(entries, Entry{Key: "post_init_env", Value: cfg.PostInitEnv})
+	}
+	if cfg.Registry != "" {
+		entries = append(entries, Entry{Key: "registry", Value: cfg.Registry})
+	}
+	if cfg.RegistryBranch != "" {
+		entries = append(entries, Entry{Key: "registry_branch", Value: cfg.RegistryBranch})
+	}
+	if cfg.Telemetry {
+		entries = append(entries, Entry{Key: "telemetry", Value: cfg.Telemetry})
+	}
+	if cfg.TelemetryEndpoint != "" {
+		entries = append(entries, Entry{Key: "telemetry_endpoint", Value: cfg.TelemetryEndpoint})
+	}
+	if cfg.NotifyWebhook != "" {
+		entries = append(entries, Entry{Key: "notify_webhook", Value: cfg.NotifyWebhook})
+	}
+	if cfg.NotifyCommand != "" {
+		entries = append(entries, Entry{Key: "notify_command", Value: cfg.NotifyCommand})
+	}
+	for name, value := range cfg.Defaults {
+		entries = append(entries, Entry{Key: "defaults." + name, Value: value})
+	}
+	for typ, names := range cfg.MandatedIncludes {
+		entries = append(entries, Entry{Key: "mandated_includes." + typ, Value: names})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// Get returns the value of a setting of cfg. Getting defaults or
+// mandated_includes returns all of their entries.
+func Get(cfg *Config, key string) (any, error) {
+	section, name, nested := strings.Cut(key, ".")
+
+	switch {
+	case key == "templates_dir":
+		return cfg.TemplatesDir, nil
+	case key == "license_header":
+		return cfg.LicenseHeader, nil
+	case key == "locale":
+		return cfg.Locale, nil
+	case key == "line_endings":
+		return cfg.LineEndings, nil
+	case key == "final_newline":
+		return cfg.FinalNewline, nil
+	case key == "backup":
+		return cfg.Backup, nil
+	case key == "functions":
+		return cfg.Functions, nil
+	case key == "verify_signatures":
+		return cfg.VerifySignatures, nil
+	case key == "trusted_keys":
+		return cfg.TrustedKeys, nil
+	case key == "post_init_allow":
+		return cfg.PostInitAllow, nil
+	case key == "post_init_deny":
+		return cfg.PostInitDeny, nil
+	case key == "post_init_env":
+		return cfg.PostInitEnv, nil
+	case key == "registry":
+		return cfg.Registry, nil
+	case key == "registry_branch":
+		return cfg.RegistryBranch, nil
+	case key == "telemetry":
+		return cfg.Telemetry, nil
+	case key == "telemetry_endpoint":
+		return cfg.TelemetryEndpoint, nil
+	case key == "notify_webhook":
+		return cfg.NotifyWebhook, nil
+	case key == "notify_command":
+		return cfg.NotifyCommand, nil
+	case key == "defaults":
+		return cfg.Defaults, nil
+	case key == "mandated_includes":
+		return cfg.MandatedIncludes, nil
+	case nested && section == "defaults" && name != "":
+		value, ok := cfg.Defaults[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not set", key)
+		}
+		return value, nil
+	case nested && section == "mandated_includes" && name != "":
+		names, ok := cfg.MandatedIncludes[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not set", key)
+		}
+		return names, nil
+	}
+
+	return nil, unknownKeyError(key)
+}
+
+// Set writes a setting to the config file at path, creating the file if it
+// does not exist. The file is edited as a YAML node tree, so its comments and
+// other settings are kept.
+//
+// Lists are given comma-separated. The value of a variable default is parsed
+// as YAML, so that "true" and "3" are stored as a bool and an int.
+func Set(path, key, value string) error {
+	keyPath, node, err := settingNode(key, value)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a mapping", path)
+	}
+
+	mapping := doc.Content[0]
+	for _, k := range keyPath[:len(keyPath)-1] {
+		mapping = childMapping(mapping, k)
+	}
+	setMappingValue(mapping, keyPath[len(keyPath)-1], node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// FormatValue formats a setting value for display. Lists are comma-separated
+// and maps are written as YAML.
+func FormatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]any:
+		return formatMap(v, len(v))
+	case map[string][]string:
+		return formatMap(v, len(v))
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatMap writes a map with n entries as YAML.
+func formatMap(m any, n int) string {
+	if n == 0 {
+		return ""
+	}
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Sprint(m)
+	}
+	return strings.TrimRight(string(data), "\n")
+}
+
+// settingNode validates a key and value and returns the path of the key in
+// the config file and the YAML node of the value.
+func settingNode(key, value string) ([]string, *yaml.Node, error) {
+	section, name, nested := strings.Cut(key, ".")
+
+	switch {
+	case key == "templates_dir", key == "license_header", key == "registry", key == "registry_branch",
+		key == "telemetry_endpoint", key == "notify_webhook", key == "notify_command":
+		return []string{key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+
+	case key == "locale":
+		if !template.IsLocale(value) {
+			return nil, nil, fmt.Errorf("invalid locale %q: expected a language tag such as de or pt-BR", value)
+		}
+		return []string{key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+
+	case key == "line_endings":
+		if _, err := template.ParseLineEnding(value); err != nil || value == "" {
+			return nil, nil, fmt.Errorf("invalid value %q for line_endings: expected lf, crlf, or native", value)
+		}
+		return []string{key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+
+	case key == "backup":
+		switch value {
+		case "suffix", "tree", "none":
+		default:
+			return nil, nil, fmt.Errorf("invalid value %q for backup: expected suffix, tree, or none", value)
+		}
+		return []string{key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+
+	case key == "telemetry", key == "final_newline", key == "verify_signatures":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid value %q for %s: expected true or false", value, key)
+		}
+		return []string{key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(enabled)}, nil
+
+	case key == "functions":
+		items := splitList(value)
+		for _, item := range items {
+			if !template.IsFuncLibrary(item) {
+				return nil, nil, fmt.Errorf("unknown function library %q (available: %s)",
+					item, strings.Join(template.FuncLibraries(), ", "))
+			}
+		}
+		return []string{key}, listNode(items), nil
+
+	case key == "trusted_keys":
+		items := splitList(value)
+		for _, item := range items {
+			if _, err := signature.ParsePublicKey(item); err != nil {
+				return nil, nil, err
+			}
+		}
+		return []string{key}, listNode(items), nil
+
+	case key == "post_init_allow", key == "post_init_deny":
+		return []string{key}, listNode(splitList(value)), nil
+
+	case key == "post_init_env":
+		switch value {
+		case "inherit", "restricted":
+		default:
+			return nil, nil, fmt.Errorf("invalid value %q for post_init_env: expected inherit or restricted", value)
+		}
+		return []string{key}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+
+	case nested && section == "defaults" && name != "":
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &node); err != nil || len(node.Content) != 1 || node.Content[0].Kind != yaml.ScalarNode {
+			return []string{section, name}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+		}
+		return []string{section, name}, node.Content[0], nil
+
+	case nested && section == "mandated_includes" && name != "":
+		switch template.Type(name) {
+		case template.TypeProject, template.TypeFeature, template.TypeComponent:
+		default:
+			return nil, nil, fmt.Errorf("invalid template type %q: expected project, feature, or component", name)
+		}
+		return []string{section, name}, listNode(splitList(value)), nil
+
+	case key == "defaults", key == "mandated_includes":
+		return nil, nil, fmt.Errorf("set a single entry of %s, e.g. %s.<name>", key, key)
+	}
+
+	return nil, nil, unknownKeyError(key)
+}
+
+func unknownKeyError(key string) error {
+	return fmt.Errorf("unknown config key %q (known keys: %s)", key, strings.Join(Keys, ", "))
+}
+
+// listNode returns a YAML sequence of strings.
+func listNode(items []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, item := range items {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+	}
+	return node
+}
+
+// childMapping returns the mapping stored under key in m, replacing any other
+// value.
+func childMapping(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			if m.Content[i+1].Kind != yaml.MappingNode {
+				m.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			return m.Content[i+1]
+		}
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+	return child
+}
+
+// setMappingValue sets the value of key in m, keeping the comments of an
+// existing entry.
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			old := m.Content[i+1]
+			value.LineComment = old.LineComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
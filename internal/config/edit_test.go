@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet_KeepsCommentsAndOtherSettings(t *testing.T) {
+	path := writeConfig(t, `# My settings
+templates_dir: /templates # shared checkout
+defaults:
+  license: mit
+`)
+
+	require.NoError(t, Set(path, "templates_dir", "/other"))
+	require.NoError(t, Set(path, "defaults.author", "Jane Doe"))
+	require.NoError(t, Set(path, "defaults.strict", "true"))
+	require.NoError(t, Set(path, "functions", "crypto, network"))
+	require.NoError(t, Set(path, "mandated_includes.project", "baseline"))
+	require.NoError(t, Set(path, "telemetry", "true"))
+	require.NoError(t, Set(path, "notify_webhook", "https://hooks.example.com/T000/B000"))
+	require.NoError(t, Set(path, "post_init_deny", "curl *, rm -rf *"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# My settings")
+	assert.Contains(t, string(data), "templates_dir: /other # shared checkout")
+
+	cfg, err := (&Loader{ConfigFile: path}).Load()
+	require.NoError(t, err)
+	assert.Equal(t, "/other", cfg.TemplatesDir)
+	assert.Equal(t, map[string]any{"license": "mit", "author": "Jane Doe", "strict": true}, cfg.Defaults)
+	assert.Equal(t, []string{"crypto", "network"}, cfg.Functions)
+	assert.Equal(t, map[string][]string{"project": {"baseline"}}, cfg.MandatedIncludes)
+	assert.True(t, cfg.Telemetry)
+	assert.Equal(t, "https://hooks.example.com/T000/B000", cfg.NotifyWebhook)
+	assert.Equal(t, []string{"curl *", "rm -rf *"}, cfg.PostInitDeny)
+}
+
+func TestSet_CreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blueprint", "config.yaml")
+
+	require.NoError(t, Set(path, "license_header", "Copyright Acme"))
+
+	cfg, err := (&Loader{ConfigFile: path}).Load()
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright Acme", cfg.LicenseHeader)
+}
+
+func TestSet_RejectsInvalidSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	tests := map[string][2]string{
+		"unknown key":      {"output_dir", "out"},
+		"whole section":    {"defaults", "x"},
+		"unknown library":  {"functions", "crypto,nope"},
+		"invalid template": {"mandated_includes.service", "baseline"},
+		"invalid bool":     {"telemetry", "maybe"},
+		"invalid locale":   {"locale", "german"},
+		"invalid backup":   {"backup", "copy"},
+		"invalid endings":  {"line_endings", "cr"},
+		"invalid newline":  {"final_newline", "yes please"},
+		"invalid key":      {"trusted_keys", "not-a-minisign-key"},
+		"invalid env":      {"post_init_env", "sandboxed"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, Set(path, tt[0], tt[1]))
+		})
+	}
+	assert.NoFileExists(t, path)
+}
+
+func TestGet(t *testing.T) {
+	cfg := &Config{
+		TemplatesDir: "/templates",
+		Functions:    []string{"crypto", "network"},
+		Defaults:     map[string]any{"author": "Jane"},
+	}
+
+	value, err := Get(cfg, "defaults.author")
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", value)
+
+	value, err = Get(cfg, "functions")
+	require.NoError(t, err)
+	assert.Equal(t, "crypto,network", FormatValue(value))
+
+	_, err = Get(cfg, "defaults.license")
+	assert.ErrorContains(t, err, "not set")
+
+	_, err = Get(cfg, "editor")
+	assert.ErrorContains(t, err, "unknown config key")
+}
+
+func TestEntries(t *testing.T) {
+	cfg := &Config{
+		TemplatesDir:     "/templates",
+		Defaults:         map[string]any{"author": "Jane"},
+		MandatedIncludes: map[string][]string{"project": {"baseline"}},
+	}
+
+	assert.Equal(t, []Entry{
+		{Key: "defaults.author", Value: "Jane"},
+		{Key: "mandated_includes.project", Value: []string{"baseline"}},
+		{Key: "templates_dir", Value: "/templates"},
+	}, Entries(cfg))
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad_EnvOverridesConfigFile(t *testing.T) {
+	path := writeConfig(t, `templates_dir: /from/file
+license_header: File header
+functions: [crypto]
+defaults:
+  author: File Author
+  license: mit
+mandated_includes:
+  project: [baseline]
+`)
+	t.Setenv("BLUEPRINT_TEMPLATES_DIR", "/from/env")
+	t.Setenv("BLUEPRINT_LICENSE_HEADER", "Env header")
+	t.Setenv("BLUEPRINT_FUNCTIONS", "network, kubernetes-names")
+	t.Setenv("BLUEPRINT_MANDATED_INCLUDES", "project=compliance;feature=lint,audit")
+	t.Setenv("BLUEPRINT_DEFAULTS_AUTHOR", "Env Author")
+	t.Setenv("BLUEPRINT_DEFAULTS_GO_VERSION", "1.25")
+
+	cfg, err := (&Loader{ConfigFile: path, EnvPrefix: "BLUEPRINT"}).Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "/from/env", cfg.TemplatesDir)
+	assert.Equal(t, "Env header", cfg.LicenseHeader)
+	assert.Equal(t, []string{"network", "kubernetes-names"}, cfg.Functions)
+	assert.Equal(t, map[string][]string{
+		"project": {"compliance"},
+		"feature": {"lint", "audit"},
+	}, cfg.MandatedIncludes)
+	assert.Equal(t, map[string]any{
+		"author":     "Env Author",
+		"license":    "mit",
+		"go_version": "1.25",
+	}, cfg.Defaults)
+}
+
+func TestLoad_ConfigFileOverridesDefaults(t *testing.T) {
+	path := writeConfig(t, "templates_dir: /from/file\n")
+	t.Setenv("BLUEPRINT_TEMPLATES_DIR", "")
+
+	cfg, err := (&Loader{ConfigFile: path, EnvPrefix: "BLUEPRINT"}).Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "/from/file", cfg.TemplatesDir)
+}
+
+func TestLoad_DefaultsWithoutFileOrEnv(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BLUEPRINT_CONFIG", "")
+	t.Setenv("BLUEPRINT_TEMPLATES_DIR", "")
+
+	cfg, err := (&Loader{EnvPrefix: "BLUEPRINT"}).Load()
+	require.NoError(t, err)
+
+	configDir, err := os.UserConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configDir, "blueprint", "templates"), cfg.TemplatesDir)
+}
+
+func TestLoad_ConfigPathFromEnv(t *testing.T) {
+	t.Setenv("BLUEPRINT_CONFIG", writeConfig(t, "license_header: From env path\n"))
+
+	cfg, err := (&Loader{EnvPrefix: "BLUEPRINT"}).Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "From env path", cfg.LicenseHeader)
+}
+
+func TestLoad_InvalidMandatedIncludes(t *testing.T) {
+	t.Setenv("BLUEPRINT_MANDATED_INCLUDES", "compliance")
+
+	_, err := (&Loader{ConfigFile: writeConfig(t, ""), EnvPrefix: "BLUEPRINT"}).Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BLUEPRINT_MANDATED_INCLUDES")
+}
+
+func TestLoad_TelemetryFromEnv(t *testing.T) {
+	t.Setenv("BLUEPRINT_TELEMETRY", "false")
+	t.Setenv("BLUEPRINT_TELEMETRY_ENDPOINT", "https://telemetry.example.com/runs")
+
+	cfg, err := (&Loader{ConfigFile: writeConfig(t, "telemetry: true\n"), EnvPrefix: "BLUEPRINT"}).Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Telemetry)
+	assert.Equal(t, "https://telemetry.example.com/runs", cfg.TelemetryEndpoint)
+
+	t.Setenv("BLUEPRINT_TELEMETRY", "sometimes")
+	_, err = (&Loader{ConfigFile: writeConfig(t, ""), EnvPrefix: "BLUEPRINT"}).Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BLUEPRINT_TELEMETRY")
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -21,6 +23,9 @@ func (l *Loader) applyDefaults(cfg *Config) error {
 }
 
 func (l *Loader) applyConfigFile(cfg *Config) error {
+	if l.ConfigFile == "" {
+		l.ConfigFile = l.env("CONFIG")
+	}
 	if l.ConfigFile == "" {
 		path, err := DefaultPath()
 		if err != nil {
@@ -28,6 +33,7 @@ func (l *Loader) applyConfigFile(cfg *Config) error {
 		}
 		l.ConfigFile = path
 	}
+	cfg.Path = l.ConfigFile
 
 	data, err := os.ReadFile(l.ConfigFile)
 	if err != nil {
@@ -39,11 +45,165 @@ func (l *Loader) applyConfigFile(cfg *Config) error {
 	return yaml.Unmarshal(data, cfg)
 }
 
+// applyEnv overrides the configuration with the environment variables named
+// after the config keys with the loader's prefix, e.g. BLUEPRINT_TEMPLATES_DIR
+// for templates_dir. Empty variables are ignored.
+//
+// Lists are comma-separated. Mandated includes are given per type, separated
+// by semicolons, as in "project=compliance-baseline;feature=lint". Each
+// variable default is set by its own BLUEPRINT_DEFAULTS_<NAME> variable, with
+// the name lowercased, and is merged into the defaults of the config file.
 func (l *Loader) applyEnv(cfg *Config) error {
-	// TODO: Apply the environment variables
+	if l.EnvPrefix == "" {
+		return nil
+	}
+
+	if v := l.env("TEMPLATES_DIR"); v != "" {
+		cfg.TemplatesDir = v
+	}
+
+	if v := l.env("LICENSE_HEADER"); v != "" {
+		cfg.LicenseHeader = v
+	}
+
+	if v := l.env("LOCALE"); v != "" {
+		cfg.Locale = v
+	}
+
+	if v := l.env("LINE_ENDINGS"); v != "" {
+		cfg.LineEndings = v
+	}
+
+	if v := l.env("FINAL_NEWLINE"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s_FINAL_NEWLINE: invalid value %q: expected true or false", l.EnvPrefix, v)
+		}
+		cfg.FinalNewline = enabled
+	}
+
+	if v := l.env("BACKUP"); v != "" {
+		cfg.Backup = v
+	}
+
+	if v := l.env("FUNCTIONS"); v != "" {
+		cfg.Functions = splitList(v)
+	}
+
+	if v := l.env("VERIFY_SIGNATURES"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s_VERIFY_SIGNATURES: invalid value %q: expected true or false", l.EnvPrefix, v)
+		}
+		cfg.VerifySignatures = enabled
+	}
+
+	if v := l.env("TRUSTED_KEYS"); v != "" {
+		cfg.TrustedKeys = splitList(v)
+	}
+
+	if v := l.env("POST_INIT_ALLOW"); v != "" {
+		cfg.PostInitAllow = splitList(v)
+	}
+
+	if v := l.env("POST_INIT_DENY"); v != "" {
+		cfg.PostInitDeny = splitList(v)
+	}
+
+	if v := l.env("POST_INIT_ENV"); v != "" {
+		cfg.PostInitEnv = v
+	}
+
+	if v := l.env("REGISTRY"); v != "" {
+		cfg.Registry = v
+	}
+
+	if v := l.env("REGISTRY_BRANCH"); v != "" {
+		cfg.RegistryBranch = v
+	}
+
+	if v := l.env("TELEMETRY"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s_TELEMETRY: invalid value %q: expected true or false", l.EnvPrefix, v)
+		}
+		cfg.Telemetry = enabled
+	}
+
+	if v := l.env("TELEMETRY_ENDPOINT"); v != "" {
+		cfg.TelemetryEndpoint = v
+	}
+
+	if v := l.env("NOTIFY_WEBHOOK"); v != "" {
+		cfg.NotifyWebhook = v
+	}
+
+	if v := l.env("NOTIFY_COMMAND"); v != "" {
+		cfg.NotifyCommand = v
+	}
+
+	if v := l.env("MANDATED_INCLUDES"); v != "" {
+		mandated, err := parseMandatedIncludes(v)
+		if err != nil {
+			return fmt.Errorf("%s_MANDATED_INCLUDES: %w", l.EnvPrefix, err)
+		}
+		cfg.MandatedIncludes = mandated
+	}
+
+	defaultsPrefix := l.EnvPrefix + "_DEFAULTS_"
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(key, defaultsPrefix)
+		if !ok || name == "" || value == "" {
+			continue
+		}
+		if cfg.Defaults == nil {
+			cfg.Defaults = make(map[string]any)
+		}
+		cfg.Defaults[strings.ToLower(name)] = value
+	}
+
 	return nil
 }
 
+// env returns the value of the environment variable for key with the
+// loader's prefix.
+func (l *Loader) env(key string) string {
+	if l.EnvPrefix == "" {
+		return ""
+	}
+	return os.Getenv(l.EnvPrefix + "_" + key)
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseMandatedIncludes parses mandated includes in the form
+// "type=name,name;type=name".
+func parseMandatedIncludes(s string) (map[string][]string, error) {
+	mandated := make(map[string][]string)
+	for _, group := range strings.Split(s, ";") {
+		if strings.TrimSpace(group) == "" {
+			continue
+		}
+		typ, names, ok := strings.Cut(group, "=")
+		typ = strings.TrimSpace(typ)
+		if !ok || typ == "" {
+			return nil, fmt.Errorf("invalid entry %q: expected type=name,name", group)
+		}
+		mandated[typ] = append(mandated[typ], splitList(names)...)
+	}
+	return mandated, nil
+}
+
 func (l *Loader) applyCLI(cfg *Config) error {
 	// TODO: Apply CLI options
 	return nil
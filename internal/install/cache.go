@@ -0,0 +1,356 @@
+package install
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/signature"
+)
+
+// etagFileName is the file of a cache entry that holds the ETag of the
+// archive last downloaded from its URL, and signedFileName the file that
+// holds the directory of the archive and the ID of the key it was verified
+// with, if it was.
+const (
+	etagFileName   = "etag"
+	signedFileName = "signed"
+)
+
+// entryLockWait is how long a fetch waits for another process fetching the
+// same archive, and entryLockPoll how often it checks whether it is done.
+const (
+	entryLockWait = 5 * time.Minute
+	entryLockPoll = 100 * time.Millisecond
+)
+
+// ArchiveCache keeps template archives downloaded over HTTP(S) extracted on
+// disk, keyed by URL and ETag. An archive is downloaded again only when the
+// server no longer reports the cached ETag for it. Each entry is locked while
+// it is read or replaced, so processes sharing the cache do not see or leave
+// half-written entries.
+type ArchiveCache struct {
+	Dir     string       // Directory of the cache
+	Refresh bool         // Download archives again even when they are cached
+	Offline bool         // Never download; only archives already cached are used
+	Client  *http.Client // Client archives are downloaded with; http.DefaultClient if nil
+
+	// Verifier, if set, requires archives to be signed with one of its keys.
+	// Archives cached without being verified are downloaded again.
+	Verifier *signature.Verifier
+
+	mu      sync.Mutex
+	fetched map[string]string // Directories of the archives fetched by this cache
+}
+
+// NewArchiveCache returns an archive cache in the user cache directory.
+func NewArchiveCache() (*ArchiveCache, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveCache{Dir: filepath.Join(cacheDir, "blueprint", "archives")}, nil
+}
+
+// Fetch returns the directory the archive at rawURL is extracted to,
+// downloading it unless the cache holds the current version. An archive is
+// fetched at most once per cache, so that resolving the same template again
+// makes no further requests. A downloaded archive is verified against the
+// .sha256 file published next to it, if there is one, and against its
+// .minisig signature if the cache has a verifier.
+func (c *ArchiveCache) Fetch(rawURL string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if dir, ok := c.fetched[rawURL]; ok {
+		return dir, nil
+	}
+
+	dir, err := c.fetch(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if c.fetched == nil {
+		c.fetched = make(map[string]string)
+	}
+	c.fetched[rawURL] = dir
+	return dir, nil
+}
+
+func (c *ArchiveCache) fetch(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("invalid archive URL %q: expected an http or https URL", rawURL)
+	}
+	if DetectKind(u.Path) != KindArchive {
+		return "", fmt.Errorf("unsupported archive %s (supported: %s)", rawURL, strings.Join(archiveExtensions, ", "))
+	}
+
+	entry := filepath.Join(c.Dir, cacheKey(rawURL))
+	lock, err := lockEntry(entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to cache %s: %w", rawURL, err)
+	}
+	defer lock.Release()
+
+	previous := ""
+	if etag, err := os.ReadFile(filepath.Join(entry, etagFileName)); err == nil {
+		dir := filepath.Join(entry, cacheKey(string(etag)))
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			previous = dir
+		}
+	}
+	cached := previous
+	if c.Verifier != nil && (previous == "" || signedTree(entry) != filepath.Base(previous)) {
+		cached = ""
+	}
+
+	if c.Offline {
+		if cached == "" {
+			return "", fmt.Errorf("%s is not cached and cannot be downloaded without network access", rawURL)
+		}
+		return cached, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if cached != "" && !c.Refresh {
+		etag, _ := os.ReadFile(filepath.Join(entry, etagFileName))
+		req.Header.Set("If-None-Match", string(etag))
+	}
+
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != "" {
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+
+	tmp, err := os.MkdirTemp(entry, stagingPrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive cache: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	archive := filepath.Join(tmp, path.Base(u.Path))
+	sum, err := saveArchive(resp.Body, archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	if err := c.verifyChecksum(u, sum); err != nil {
+		return "", err
+	}
+	signedBy := ""
+	if c.Verifier != nil {
+		key, err := verifyArchive(c.client(), rawURL, archive, c.Verifier)
+		if err != nil {
+			return "", err
+		}
+		signedBy = key.String()
+	}
+
+	extracted := filepath.Join(tmp, "archive")
+	if err := extractArchive(archive, u.Path, extracted); err != nil {
+		return "", err
+	}
+
+	// The tree of the previous ETag is replaced by the new one. The signed
+	// marker goes first, so that it never vouches for a tree it was not
+	// written for, even if the run stops halfway.
+	etag := resp.Header.Get("ETag")
+	dir := filepath.Join(entry, cacheKey(etag))
+	if err := os.Remove(filepath.Join(entry, signedFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to cache %s: %w", rawURL, err)
+	}
+	if previous != "" {
+		_ = os.RemoveAll(previous)
+	}
+	_ = os.RemoveAll(dir)
+	if err := os.Rename(extracted, dir); err != nil {
+		return "", fmt.Errorf("failed to cache %s: %w", rawURL, err)
+	}
+	if err := writeEntryFile(entry, etagFileName, etag); err != nil {
+		return "", fmt.Errorf("failed to cache %s: %w", rawURL, err)
+	}
+	if signedBy != "" {
+		if err := writeEntryFile(entry, signedFileName, filepath.Base(dir)+"\n"+signedBy); err != nil {
+			return "", fmt.Errorf("failed to cache %s: %w", rawURL, err)
+		}
+	}
+	return dir, nil
+}
+
+// signedTree returns the directory of the archive the signed marker of a
+// cache entry vouches for, or an empty string if there is no marker.
+func signedTree(entry string) string {
+	data, err := os.ReadFile(filepath.Join(entry, signedFileName))
+	if err != nil {
+		return ""
+	}
+	tree, _, _ := strings.Cut(string(data), "\n")
+	return tree
+}
+
+// writeEntryFile replaces a file of a cache entry with content, through a
+// rename, so that readers see either the old or the new content.
+func writeEntryFile(entry, name, content string) error {
+	f, err := os.CreateTemp(entry, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	_, writeErr := f.WriteString(content)
+	if err := errors.Join(writeErr, f.Close()); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filepath.Join(entry, name)); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// lockEntry locks a cache entry, creating its directory, and waits while
+// another process holds the lock.
+func lockEntry(entry string) (*scaffold.Lock, error) {
+	deadline := time.Now().Add(entryLockWait)
+	for {
+		lock, err := scaffold.AcquireLock(entry)
+		var locked *scaffold.LockedError
+		if !errors.As(err, &locked) {
+			return lock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is in use by process %d on %s", entry, locked.PID, locked.Hostname)
+		}
+		time.Sleep(entryLockPoll)
+	}
+}
+
+// verifyChecksum compares the SHA-256 sum of a downloaded archive with the
+// .sha256 file published next to it. Archives without one are not verified.
+func (c *ArchiveCache) verifyChecksum(archiveURL *url.URL, sum string) error {
+	sidecar := sidecarURL(archiveURL, ".sha256")
+	data, found, err := downloadSidecar(c.client(), sidecar)
+	if err != nil || !found {
+		return err
+	}
+
+	// The file is in the format of sha256sum: the sum, then the file name.
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return fmt.Errorf("%s is empty", sidecar)
+	}
+	if !strings.EqualFold(fields[0], sum) {
+		return &ChecksumMismatchError{URL: archiveURL.String(), Expected: strings.ToLower(fields[0]), Actual: sum}
+	}
+	return nil
+}
+
+func (c *ArchiveCache) client() *http.Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return http.DefaultClient
+}
+
+// saveArchive writes r to dest and returns the hex SHA-256 sum of the content.
+func saveArchive(r io.Reader, dest string) (string, error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	_, copyErr := io.Copy(io.MultiWriter(f, h), r)
+	if err := errors.Join(copyErr, f.Close()); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheKey returns the name of the cache directory of s.
+func cacheKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
+
+// verifyArchive verifies the archive file at archive, downloaded from
+// archiveURL, with the signature published next to it, and returns the key
+// it was signed with.
+func verifyArchive(client *http.Client, archiveURL, archive string, verifier *signature.Verifier) (*signature.PublicKey, error) {
+	u, err := url.Parse(archiveURL)
+	if err != nil {
+		return nil, &SignatureError{Source: archiveURL, Err: err}
+	}
+	sidecar := sidecarURL(u, signature.Extension)
+
+	sig, found, err := downloadSidecar(client, sidecar)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, &SignatureError{Source: archiveURL, Err: fmt.Errorf("no signature is published at %s", sidecar)}
+	}
+
+	content, err := os.ReadFile(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", archive, err)
+	}
+	key, err := verifier.Verify(content, sig)
+	if err != nil {
+		return nil, &SignatureError{Source: archiveURL, Err: err}
+	}
+	return key, nil
+}
+
+// sidecarURL returns the URL of the file published next to an archive with
+// ext appended to its name.
+func sidecarURL(archiveURL *url.URL, ext string) string {
+	sidecar := *archiveURL
+	sidecar.Path += ext
+	sidecar.RawPath = ""
+	return sidecar.String()
+}
+
+// downloadSidecar downloads a small file published next to an archive.
+// found is false if the server has no such file.
+func downloadSidecar(client *http.Client, rawURL string) (data []byte, found bool, err error) {
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("failed to download %s: %s", rawURL, resp.Status)
+	}
+
+	data, err = io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download %s: %w", rawURL, err)
+	}
+	return data, true, nil
+}
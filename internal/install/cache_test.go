@@ -0,0 +1,294 @@
+package install
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tarGz returns a .tar.gz archive of the given files, keyed by
+// slash-separated path.
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// archiveServer serves archive at /go-api.tar.gz with the given ETag and
+// the files published next to it, keyed by extension, and counts the full
+// downloads of the archive.
+func archiveServer(t *testing.T, archive []byte, etag *string, sidecars map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var downloads atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/go-api.tar.gz":
+			if r.Header.Get("If-None-Match") == *etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			downloads.Add(1)
+			w.Header().Set("ETag", *etag)
+			_, _ = w.Write(archive)
+		default:
+			sidecar, ok := sidecars[strings.TrimPrefix(r.URL.Path, "/go-api.tar.gz")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(sidecar))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &downloads
+}
+
+func TestArchiveCacheFetch(t *testing.T) {
+	archive := tarGz(t, map[string]string{"go-api/template.yaml": templateManifest("go-api", "1.0.0")})
+	etag := `"v1"`
+	srv, downloads := archiveServer(t, archive, &etag, nil)
+	url := srv.URL + "/go-api.tar.gz"
+	cacheDir := t.TempDir()
+
+	dir, err := (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "go-api", "template.yaml"))
+	assert.Equal(t, int32(1), downloads.Load())
+
+	// An unchanged ETag reuses the extracted archive.
+	cached, err := (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, dir, cached)
+	assert.Equal(t, int32(1), downloads.Load())
+
+	// Offline, the cached archive is used without a request.
+	srv.Close()
+	cached, err = (&ArchiveCache{Dir: cacheDir, Offline: true}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, dir, cached)
+}
+
+func TestArchiveCacheFetchRefreshesChangedArchives(t *testing.T) {
+	archive := tarGz(t, map[string]string{"go-api/template.yaml": templateManifest("go-api", "1.0.0")})
+	etag := `"v1"`
+	srv, downloads := archiveServer(t, archive, &etag, nil)
+	url := srv.URL + "/go-api.tar.gz"
+	cacheDir := t.TempDir()
+
+	first, err := (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+	require.NoError(t, err)
+
+	etag = `"v2"`
+	second, err := (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.NoDirExists(t, first)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	// Refresh downloads the archive even though its ETag is unchanged.
+	_, err = (&ArchiveCache{Dir: cacheDir, Refresh: true}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), downloads.Load())
+}
+
+func TestArchiveCacheFetchLocksEntries(t *testing.T) {
+	archive := tarGz(t, map[string]string{"go-api/template.yaml": templateManifest("go-api", "1.0.0")})
+	etag := `"v1"`
+	srv, downloads := archiveServer(t, archive, &etag, nil)
+	url := srv.URL + "/go-api.tar.gz"
+
+	t.Run("caches sharing a directory download once", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		dirs := make([]string, 8)
+		errs := make([]error, len(dirs))
+
+		var wg sync.WaitGroup
+		for i := range dirs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				dirs[i], errs[i] = (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+			}()
+		}
+		wg.Wait()
+
+		for i := range dirs {
+			require.NoError(t, errs[i])
+			assert.Equal(t, dirs[0], dirs[i])
+		}
+		assert.FileExists(t, filepath.Join(dirs[0], "go-api", "template.yaml"))
+		assert.Equal(t, int32(1), downloads.Load())
+	})
+
+	t.Run("waits for the process holding the entry", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		lock, err := scaffold.AcquireLock(filepath.Join(cacheDir, cacheKey(url)))
+		require.NoError(t, err)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			t.Fatalf("fetch returned while the entry was locked: %v", err)
+		case <-time.After(3 * entryLockPoll):
+		}
+
+		require.NoError(t, lock.Release())
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("fetch did not resume after the entry was unlocked")
+		}
+	})
+}
+
+func TestArchiveCacheFetchVerifiesChecksum(t *testing.T) {
+	archive := tarGz(t, map[string]string{"go-api/template.yaml": templateManifest("go-api", "1.0.0")})
+	sum := sha256.Sum256(archive)
+	etag := `"v1"`
+
+	srv, _ := archiveServer(t, archive, &etag, map[string]string{
+		".sha256": hex.EncodeToString(sum[:]) + "  go-api.tar.gz\n",
+	})
+	_, err := (&ArchiveCache{Dir: t.TempDir()}).Fetch(srv.URL + "/go-api.tar.gz")
+	require.NoError(t, err)
+
+	srv, _ = archiveServer(t, archive, &etag, map[string]string{
+		".sha256": "0000000000000000000000000000000000000000000000000000000000000000\n",
+	})
+	cacheDir := t.TempDir()
+	_, err = (&ArchiveCache{Dir: cacheDir}).Fetch(srv.URL + "/go-api.tar.gz")
+	var mismatch *ChecksumMismatchError
+	require.True(t, errors.As(err, &mismatch), "got %v", err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), mismatch.Actual)
+
+	// Nothing is cached from a rejected archive.
+	_, err = (&ArchiveCache{Dir: cacheDir, Offline: true}).Fetch(srv.URL + "/go-api.tar.gz")
+	assert.Error(t, err)
+}
+
+// signArchive returns a trusted key and a minisign signature of archive
+// made with it.
+func signArchive(t *testing.T, archive []byte) (key, sig string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	id := []byte("keyid-01")
+
+	signed := ed25519.Sign(private, archive)
+	comment := "timestamp:1700000000"
+	global := ed25519.Sign(private, append(append([]byte(nil), signed...), comment...))
+
+	key = base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), public...))
+	sig = "untrusted comment: test\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), signed...)) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+	return key, sig
+}
+
+func TestArchiveCacheFetchVerifiesSignature(t *testing.T) {
+	archive := tarGz(t, map[string]string{"go-api/template.yaml": templateManifest("go-api", "1.0.0")})
+	key, sig := signArchive(t, archive)
+	verifier, err := signature.NewVerifier([]string{key})
+	require.NoError(t, err)
+	etag := `"v1"`
+
+	unsigned, _ := archiveServer(t, archive, &etag, nil)
+	_, err = (&ArchiveCache{Dir: t.TempDir(), Verifier: verifier}).Fetch(unsigned.URL + "/go-api.tar.gz")
+	var sigErr *SignatureError
+	require.ErrorAs(t, err, &sigErr)
+	assert.ErrorContains(t, err, "no signature is published")
+
+	tampered, _ := archiveServer(t, append(archive, 0), &etag, map[string]string{".minisig": sig})
+	_, err = (&ArchiveCache{Dir: t.TempDir(), Verifier: verifier}).Fetch(tampered.URL + "/go-api.tar.gz")
+	assert.ErrorIs(t, err, signature.ErrInvalidSignature)
+
+	signed, downloads := archiveServer(t, archive, &etag, map[string]string{".minisig": sig})
+	url := signed.URL + "/go-api.tar.gz"
+	cacheDir := t.TempDir()
+
+	// An archive cached without verification is downloaded again to verify it.
+	_, err = (&ArchiveCache{Dir: cacheDir}).Fetch(url)
+	require.NoError(t, err)
+	_, err = (&ArchiveCache{Dir: cacheDir, Verifier: verifier}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	_, err = (&ArchiveCache{Dir: cacheDir, Verifier: verifier}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), downloads.Load())
+
+	// Replacing a verified archive without verification drops the marker,
+	// so the archive is verified again.
+	_, err = (&ArchiveCache{Dir: cacheDir, Refresh: true}).Fetch(url)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(cacheDir, cacheKey(url), signedFileName))
+	_, err = (&ArchiveCache{Dir: cacheDir, Verifier: verifier}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), downloads.Load())
+
+	// A marker written for another tree of the entry does not vouch for it.
+	entry := filepath.Join(cacheDir, cacheKey(url))
+	require.NoError(t, writeEntryFile(entry, signedFileName, cacheKey(`"v0"`)+"\n"+key))
+	_, err = (&ArchiveCache{Dir: cacheDir, Verifier: verifier}).Fetch(url)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), downloads.Load())
+}
+
+func TestInstallRefusesUnverifiableSources(t *testing.T) {
+	key, _ := signArchive(t, nil)
+	verifier, err := signature.NewVerifier([]string{key})
+	require.NoError(t, err)
+
+	for _, source := range []string{"https://github.com/acme/templates.git", "oci://ghcr.io/acme/templates/go-api:1.0.0"} {
+		_, err := Install(t.TempDir(), Options{Source: source, Verifier: verifier})
+		var sigErr *SignatureError
+		assert.ErrorAs(t, err, &sigErr, source)
+	}
+}
+
+func TestArchiveCacheFetchRejects(t *testing.T) {
+	cache := &ArchiveCache{Dir: t.TempDir()}
+
+	_, err := cache.Fetch("https://example.com/templates/go-api.txt")
+	assert.ErrorContains(t, err, "unsupported archive")
+
+	_, err = cache.Fetch("ftp://example.com/go-api.tar.gz")
+	assert.ErrorContains(t, err, "expected an http or https URL")
+
+	_, err = (&ArchiveCache{Dir: t.TempDir(), Offline: true}).Fetch("https://example.com/go-api.tar.gz")
+	assert.ErrorContains(t, err, "not cached")
+}
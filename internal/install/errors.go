@@ -0,0 +1,82 @@
+package install
+
+import "fmt"
+
+// NotInstalledError is returned when a template was not installed with
+// blueprint template install, for example because it was copied into the
+// templates directory by hand.
+type NotInstalledError struct {
+	Name string
+}
+
+func (e *NotInstalledError) Error() string {
+	return fmt.Sprintf("template %q is not installed", e.Name)
+}
+
+// ConflictError is returned when installing a template would replace a
+// template that was not installed from the same source.
+type ConflictError struct {
+	Name string
+	Path string // Directory of the existing template
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("template %q already exists at %s", e.Name, e.Path)
+}
+
+// NoTemplatesError is returned when a source does not contain any template.
+type NoTemplatesError struct {
+	Source string
+}
+
+func (e *NoTemplatesError) Error() string {
+	return fmt.Sprintf("no templates found in %s", e.Source)
+}
+
+// NotGitRepoError is returned when pulling a templates directory that is not
+// inside a git repository.
+type NotGitRepoError struct {
+	Dir string
+}
+
+func (e *NotGitRepoError) Error() string {
+	return fmt.Sprintf("templates directory %s is not a git repository", e.Dir)
+}
+
+// NoUpstreamError is returned when the current branch of a templates
+// repository does not track a remote branch to pull from.
+type NoUpstreamError struct {
+	Dir string
+}
+
+func (e *NoUpstreamError) Error() string {
+	return fmt.Sprintf("the current branch of %s has no upstream branch", e.Dir)
+}
+
+// ChecksumMismatchError is returned when a downloaded template archive does
+// not match the SHA-256 sum published next to it.
+type ChecksumMismatchError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.URL, e.Expected, e.Actual)
+}
+
+// SignatureError is returned when signatures are verified and a remote
+// template is unsigned, signed with a key that is not trusted, or does not
+// match its signature.
+type SignatureError struct {
+	Source string
+	Err    error
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("signature verification failed for %s: %v", e.Source, e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,329 @@
+package install
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/oci"
+	"github.com/dhanush0x96c/blueprint/internal/signature"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// Kind is the kind of source a template is installed from.
+type Kind string
+
+const (
+	KindGit     Kind = "git"
+	KindArchive Kind = "archive"
+	KindPath    Kind = "path"
+	KindOCI     Kind = "oci"
+)
+
+// archiveExtensions lists the supported archive formats. Packed templates
+// (.bpt) are gzipped tar archives whose checksums are verified once
+// extracted.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar", ".zip", template.ArchiveExtension}
+
+// DetectKind returns the kind of a source. oci:// references are pulled from
+// a registry. Archives are recognized by their extension, whether local or
+// downloaded over HTTP. Other URLs, scp-style addresses such as
+// git@host:repo, and paths ending in .git are cloned with git. Anything else
+// is a local directory.
+func DetectKind(source string) Kind {
+	if oci.IsReference(source) {
+		return KindOCI
+	}
+
+	lower := strings.ToLower(source)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return KindArchive
+		}
+	}
+
+	if isURL(source) || strings.HasPrefix(source, "git@") || strings.HasSuffix(lower, ".git") {
+		return KindGit
+	}
+	return KindPath
+}
+
+func isURL(source string) bool {
+	for _, scheme := range []string{"http://", "https://", "ssh://", "git://", "file://", oci.Scheme} {
+		if strings.HasPrefix(source, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetched is a source made available on disk.
+type fetched struct {
+	Root   string // Directory holding the templates of the source
+	Commit string // Commit of a git source
+	Digest string // Manifest digest of an OCI source
+}
+
+// fetch makes the source available on disk, using tmp for clones and
+// extracted archives. Local directories are used in place. With a verifier,
+// downloaded archives must be signed with one of its keys, and git and OCI
+// sources, which cannot be verified, are refused.
+func fetch(source string, kind Kind, ref, tmp string, verifier *signature.Verifier) (*fetched, error) {
+	if ref != "" && kind != KindGit {
+		return nil, fmt.Errorf("a ref can only be given for git sources")
+	}
+	if verifier != nil && (kind == KindGit || kind == KindOCI) {
+		return nil, &SignatureError{
+			Source: source,
+			Err:    fmt.Errorf("%s sources cannot be verified; install a signed archive instead", kind),
+		}
+	}
+
+	switch kind {
+	case KindGit:
+		return cloneGit(source, ref, filepath.Join(tmp, "repo"))
+	case KindArchive:
+		root := filepath.Join(tmp, "archive")
+		if err := fetchArchive(source, tmp, root, verifier); err != nil {
+			return nil, err
+		}
+		return &fetched{Root: root}, nil
+	case KindOCI:
+		return pullOCI(source, filepath.Join(tmp, "oci"))
+	default:
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory, git repository, or archive", source)
+		}
+		return &fetched{Root: source}, nil
+	}
+}
+
+// cloneGit clones the default branch of a git repository, or ref, without
+// its history.
+func cloneGit(url, ref, dir string) (*fetched, error) {
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", url, dir)
+
+	if _, err := runGit(args...); err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+
+	commit, err := runGit("-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit of %s: %w", url, err)
+	}
+
+	return &fetched{Root: dir, Commit: commit}, nil
+}
+
+// pullOCI pulls a template artifact from a registry and extracts it into dir.
+func pullOCI(source, dir string) (*fetched, error) {
+	ref, err := oci.ParseReference(source)
+	if err != nil {
+		return nil, err
+	}
+	artifact, digest, err := oci.NewClient().Pull(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(artifact.Layer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	defer gz.Close()
+	if err := extractTar(gz, dir); err != nil {
+		return nil, err
+	}
+	return &fetched{Root: dir, Digest: digest}, nil
+}
+
+func runGit(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// fetchArchive extracts a local or downloaded archive into dir. With a
+// verifier, a downloaded archive is only extracted if it is signed with one
+// of its keys.
+func fetchArchive(source, tmp, dir string, verifier *signature.Verifier) error {
+	archive := source
+	if isURL(source) {
+		archive = filepath.Join(tmp, path.Base(source))
+		if err := download(source, archive); err != nil {
+			return err
+		}
+		if verifier != nil {
+			if _, err := verifyArchive(http.DefaultClient, source, archive, verifier); err != nil {
+				return err
+			}
+		}
+	}
+
+	return extractArchive(archive, source, dir)
+}
+
+// extractArchive extracts the archive file at archive into dir. The format is
+// taken from the extension of name, the source the archive came from.
+func extractArchive(archive, name, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to open archive: %w", err)
+		}
+		return extractZip(f, info.Size(), dir)
+	case strings.HasSuffix(lower, ".tar"):
+		return extractTar(f, dir)
+	default:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer gz.Close()
+		if err := extractTar(gz, dir); err != nil {
+			return err
+		}
+		if template.IsArchive(lower) {
+			if _, err := template.VerifyPack(os.DirFS(dir)); err != nil {
+				return fmt.Errorf("failed to read %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+}
+
+func download(url, dest string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return nil
+}
+
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if _, err := archiveEntryPath(dir, hdr.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(dir, hdr.Name, os.FileMode(hdr.Mode), tr); err != nil {
+				return err
+			}
+		}
+		// Links and special files are not part of templates and are skipped.
+	}
+}
+
+func extractZip(r io.ReaderAt, size int64, dir string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		err = extractFile(dir, f.Name, f.Mode(), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(dir, name string, mode os.FileMode, r io.Reader) error {
+	dest, err := archiveEntryPath(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return nil
+}
+
+// archiveEntryPath returns where an archive entry is extracted to, refusing
+// entries that would land outside dir.
+func archiveEntryPath(dir, name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "/"))
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("archive entry %s is outside the archive", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
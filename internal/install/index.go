@@ -0,0 +1,115 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IndexFileName is the name of the file in the user templates directory that
+// records where installed templates came from.
+const IndexFileName = "installed.yaml"
+
+// SchemaVersion is the current index schema version.
+const SchemaVersion = 1
+
+// Index records the templates installed into the user templates directory.
+// Templates copied into the directory by hand are not recorded.
+type Index struct {
+	SchemaVersion int      `yaml:"schema_version"`
+	Templates     []Record `yaml:"templates"`
+}
+
+// Record records an installed template and where it came from.
+type Record struct {
+	Name        string    `yaml:"name"`
+	Type        string    `yaml:"type"`
+	Version     string    `yaml:"version"`
+	Path        string    `yaml:"path"` // Directory of the template, relative to the templates directory
+	Source      string    `yaml:"source"`
+	Kind        Kind      `yaml:"kind"`
+	Ref         string    `yaml:"ref,omitempty"`    // Branch or tag the git source was cloned at
+	Commit      string    `yaml:"commit,omitempty"` // Commit the git source was cloned at
+	Digest      string    `yaml:"digest,omitempty"` // Manifest digest the OCI source was pulled at
+	InstalledAt time.Time `yaml:"installed_at"`
+}
+
+// IndexPath returns the path of the index of the templates directory dir.
+func IndexPath(dir string) string {
+	return filepath.Join(dir, IndexFileName)
+}
+
+// LoadIndex reads the index of the templates directory dir. A missing index
+// is empty.
+func LoadIndex(dir string) (*Index, error) {
+	idx := &Index{SchemaVersion: SchemaVersion}
+
+	data, err := os.ReadFile(IndexPath(dir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to read install index: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("failed to parse install index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// Save writes the index into the templates directory dir.
+func (idx *Index) Save(dir string) error {
+	sort.Slice(idx.Templates, func(i, j int) bool { return idx.Templates[i].Name < idx.Templates[j].Name })
+
+	data, err := yaml.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode install index: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	if err := os.WriteFile(IndexPath(dir), data, 0644); err != nil {
+		return fmt.Errorf("failed to write install index: %w", err)
+	}
+
+	return nil
+}
+
+// Template returns the record of the installed template with the given name.
+func (idx *Index) Template(name string) (*Record, bool) {
+	for i := range idx.Templates {
+		if idx.Templates[i].Name == name {
+			return &idx.Templates[i], true
+		}
+	}
+	return nil, false
+}
+
+// record adds a template to the index, replacing an earlier record of the
+// same name.
+func (idx *Index) record(r Record) {
+	if existing, ok := idx.Template(r.Name); ok {
+		*existing = r
+		return
+	}
+	idx.Templates = append(idx.Templates, r)
+}
+
+// remove drops the record of the template with the given name.
+func (idx *Index) remove(name string) {
+	for i := range idx.Templates {
+		if idx.Templates[i].Name == name {
+			idx.Templates = append(idx.Templates[:i], idx.Templates[i+1:]...)
+			return
+		}
+	}
+}
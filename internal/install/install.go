@@ -0,0 +1,467 @@
+// Package install installs templates into the user templates directory from
+// git repositories, archives, OCI registries, and local directories, and records where they
+// came from so they can be updated and uninstalled.
+package install
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/signature"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// stagingPrefix prefixes the directories templates are copied into before
+// they are moved into place.
+const stagingPrefix = ".blueprint-install-"
+
+// Options configures installing templates from a source.
+type Options struct {
+	Source string // Git URL, archive path or URL, oci:// reference, or local directory
+	Ref    string // Branch or tag to clone a git source at
+	Force  bool   // Replace templates of the same name that were not installed from Source
+	DryRun bool   // Report what would change without writing anything
+
+	// Verifier, if set, requires remote sources to be archives signed with
+	// one of its keys.
+	Verifier *signature.Verifier
+}
+
+// Change is a template that was installed, updated, or uninstalled.
+type Change struct {
+	Record
+	Previous string // Version that was replaced or removed; empty for a new template
+}
+
+// Install installs every template found in a source into the templates
+// directory dir. Each template is copied to a directory named after it.
+// Templates nested inside another template of the source are installed as
+// part of it.
+func Install(dir string, opts Options) ([]Change, error) {
+	idx, err := LoadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := DetectKind(opts.Source)
+	source := opts.Source
+	if !isURL(source) && !strings.HasPrefix(source, "git@") {
+		if source, err = filepath.Abs(source); err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", opts.Source, err)
+		}
+	}
+
+	return installFrom(dir, idx, source, kind, opts.Ref, nil, opts.Force, opts.DryRun, opts.Verifier)
+}
+
+// Update installs the named templates again from the sources they were
+// installed from, or every installed template when no name is given. With a
+// verifier, remote sources must be archives signed with one of its keys.
+func Update(dir string, names []string, verifier *signature.Verifier, dryRun bool) ([]Change, error) {
+	idx, err := LoadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectRecords(idx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	// Templates installed from the same source are fetched once.
+	type origin struct {
+		source string
+		kind   Kind
+		ref    string
+	}
+	var origins []origin
+	groups := make(map[origin]map[string]bool)
+	for _, r := range selected {
+		o := origin{r.Source, r.Kind, r.Ref}
+		if groups[o] == nil {
+			groups[o] = make(map[string]bool)
+			origins = append(origins, o)
+		}
+		groups[o][r.Name] = true
+	}
+
+	var changes []Change
+	for _, o := range origins {
+		updated, err := installFrom(dir, idx, o.source, o.kind, o.ref, groups[o], true, dryRun, verifier)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, updated...)
+	}
+	return changes, nil
+}
+
+// Uninstall removes installed templates from the templates directory dir.
+// Templates that were not installed with Install are left alone.
+func Uninstall(dir string, names []string, dryRun bool) ([]Change, error) {
+	idx, err := LoadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectRecords(idx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0, len(selected))
+	for _, r := range selected {
+		changes = append(changes, Change{Record: r, Previous: r.Version})
+		if dryRun {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, filepath.FromSlash(r.Path))); err != nil {
+			return nil, fmt.Errorf("failed to remove template %q: %w", r.Name, err)
+		}
+		idx.remove(r.Name)
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+	return changes, idx.Save(dir)
+}
+
+// List returns the installed templates of the templates directory dir,
+// sorted by name.
+func List(dir string) ([]Record, error) {
+	idx, err := LoadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	records := append([]Record(nil), idx.Templates...)
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return records, nil
+}
+
+// selectRecords returns the records of the named templates, or every record
+// when no name is given.
+func selectRecords(idx *Index, names []string) ([]Record, error) {
+	if len(names) == 0 {
+		return append([]Record(nil), idx.Templates...), nil
+	}
+
+	records := make([]Record, 0, len(names))
+	for _, name := range names {
+		r, ok := idx.Template(name)
+		if !ok {
+			return nil, &NotInstalledError{Name: name}
+		}
+		records = append(records, *r)
+	}
+	return records, nil
+}
+
+// installFrom fetches a source and installs its templates, or only those
+// named in only when it is not nil. With force, templates of the same name
+// that were not installed from the source are replaced as long as they live
+// where the template would be installed.
+func installFrom(dir string, idx *Index, source string, kind Kind, ref string, only map[string]bool, force, dryRun bool, verifier *signature.Verifier) ([]Change, error) {
+	tmp, err := os.MkdirTemp("", "blueprint-install-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	src, err := fetch(source, kind, ref, tmp, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := discover(src.Root, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUnique(found); err != nil {
+		return nil, err
+	}
+	if only != nil {
+		found, err = filterTemplates(found, only, source)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(found) == 0 {
+		return nil, &NoTemplatesError{Source: source}
+	}
+
+	existing, err := discover(dir, true)
+	if err != nil {
+		return nil, err
+	}
+	existingPaths := make(map[string]string, len(existing))
+	for _, t := range existing {
+		if _, ok := existingPaths[t.Meta.Name]; !ok {
+			existingPaths[t.Meta.Name] = t.Path
+		}
+	}
+
+	now := time.Now().UTC()
+	changes := make([]Change, 0, len(found))
+	for _, t := range found {
+		target, previous, err := installTarget(dir, idx, t.Meta.Name, existingPaths, source, ref, force)
+		if err != nil {
+			return nil, err
+		}
+
+		record := Record{
+			Name:        t.Meta.Name,
+			Type:        string(t.Meta.Type),
+			Version:     t.Meta.Version,
+			Path:        target,
+			Source:      source,
+			Kind:        kind,
+			Ref:         ref,
+			Commit:      src.Commit,
+			Digest:      src.Digest,
+			InstalledAt: now,
+		}
+		changes = append(changes, Change{Record: record, Previous: previous})
+		if dryRun {
+			continue
+		}
+
+		if err := copyTemplate(filepath.Join(src.Root, filepath.FromSlash(t.Path)), dir, target); err != nil {
+			return nil, err
+		}
+		idx.record(record)
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+	return changes, idx.Save(dir)
+}
+
+// installTarget returns the directory, relative to dir, that the template
+// named name is installed to and the version it replaces. It fails when the
+// template would replace one that was not installed from source, unless force
+// is set and that template lives in the same directory.
+func installTarget(dir string, idx *Index, name string, existing map[string]string, source, ref string, force bool) (string, string, error) {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", "", fmt.Errorf("template name %q cannot be used as a directory name", name)
+	}
+
+	target := name
+	record, installed := idx.Template(name)
+	if installed {
+		target = record.Path
+	}
+
+	current, exists := existing[name]
+	switch {
+	case exists && current != target:
+		return "", "", &ConflictError{Name: name, Path: filepath.Join(dir, filepath.FromSlash(current))}
+	case installed && (record.Source != source || record.Ref != ref) && !force:
+		return "", "", &ConflictError{Name: name, Path: filepath.Join(dir, filepath.FromSlash(target))}
+	case !installed && !force:
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(target))); err == nil {
+			return "", "", &ConflictError{Name: name, Path: filepath.Join(dir, filepath.FromSlash(target))}
+		}
+	}
+
+	if installed && exists {
+		return target, record.Version, nil
+	}
+	return target, "", nil
+}
+
+// found is a template found in a directory tree.
+type found struct {
+	Path string // Slash-separated directory of the template, relative to the root
+	Meta *template.Metadata
+}
+
+// discover finds the templates under root, sorted by path. Templates nested
+// inside another template are part of it and are not returned. Git metadata
+// and staging directories are skipped, as are templates that cannot be read
+// when ignoreErrors is set.
+func discover(root string, ignoreErrors bool) ([]found, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	fsys := os.DirFS(root)
+	loader := template.NewLoader()
+
+	var templates []found
+	err := fs.WalkDir(fsys, ".", func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || strings.HasPrefix(d.Name(), stagingPrefix) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != template.FileName {
+			return nil
+		}
+
+		meta, err := loader.LoadMetadata(fsys, pth)
+		if err != nil {
+			if ignoreErrors {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", pth, err)
+		}
+		templates = append(templates, found{Path: path.Dir(pth), Meta: meta})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover templates in %s: %w", root, err)
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Path < templates[j].Path })
+
+	outer := templates[:0]
+	for _, t := range templates {
+		if len(outer) > 0 && isWithin(t.Path, outer[len(outer)-1].Path) {
+			continue
+		}
+		outer = append(outer, t)
+	}
+	return outer, nil
+}
+
+// isWithin reports whether the slash-separated path p is inside dir.
+func isWithin(p, dir string) bool {
+	return dir == "." || strings.HasPrefix(p, dir+"/")
+}
+
+// checkUnique fails when a source defines two templates of the same name.
+func checkUnique(templates []found) error {
+	paths := make(map[string]string, len(templates))
+	for _, t := range templates {
+		if other, ok := paths[t.Meta.Name]; ok {
+			return fmt.Errorf("template %q is defined twice, in %s and %s", t.Meta.Name, other, t.Path)
+		}
+		paths[t.Meta.Name] = t.Path
+	}
+	return nil
+}
+
+// filterTemplates keeps the templates named in only, failing when the source
+// no longer provides one of them.
+func filterTemplates(templates []found, only map[string]bool, source string) ([]found, error) {
+	var kept []found
+	seen := make(map[string]bool, len(only))
+	for _, t := range templates {
+		if only[t.Meta.Name] {
+			kept = append(kept, t)
+			seen[t.Meta.Name] = true
+		}
+	}
+
+	for name := range only {
+		if !seen[name] {
+			return nil, fmt.Errorf("template %q is no longer provided by %s", name, source)
+		}
+	}
+	return kept, nil
+}
+
+// copyTemplate copies the template directory src to target inside dir. The
+// template is copied to a staging directory first and then moved into place,
+// so that a failed copy leaves an installed template untouched.
+func copyTemplate(src, dir, target string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(dir, stagingPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := copyTree(src, filepath.Join(staging, "new")); err != nil {
+		return err
+	}
+
+	dest := filepath.Join(dir, filepath.FromSlash(target))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+
+	old := filepath.Join(staging, "old")
+	replaced := false
+	if _, err := os.Stat(dest); err == nil {
+		if err := os.Rename(dest, old); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", dest, err)
+		}
+		replaced = true
+	}
+
+	if err := os.Rename(filepath.Join(staging, "new"), dest); err != nil {
+		if replaced {
+			_ = os.Rename(old, dest)
+		}
+		return fmt.Errorf("failed to install %s: %w", dest, err)
+	}
+	return nil
+}
+
+// copyTree copies the regular files and directories under src to dst,
+// skipping git metadata.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, pth)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFile(pth, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return nil
+}
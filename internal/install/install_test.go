@@ -0,0 +1,273 @@
+package install
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templateManifest(name, version string) string {
+	return "name: " + name + "\ntype: feature\nversion: " + version + "\ndescription: test\n"
+}
+
+// writeSource writes a source with the given files, keyed by slash-separated
+// path.
+func writeSource(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := map[string]Kind{
+		"https://github.com/acme/templates":          KindGit,
+		"git@github.com:acme/templates.git":          KindGit,
+		"../templates.git":                           KindGit,
+		"https://example.com/templates.tar.gz":       KindArchive,
+		"./templates.zip":                            KindArchive,
+		"./templates.TGZ":                            KindArchive,
+		"./templates":                                KindPath,
+		"/home/user/src/templates":                   KindPath,
+		"file:///home/user/src/templates-repo":       KindGit,
+		"https://example.com/download/templates.tar": KindArchive,
+		"./go-cli-1.0.0.bpt":                         KindArchive,
+	}
+	for source, want := range tests {
+		assert.Equal(t, want, DetectKind(source), source)
+	}
+}
+
+func TestInstallFromPath(t *testing.T) {
+	src := t.TempDir()
+	writeSource(t, src, map[string]string{
+		"alpha/template.yaml":        templateManifest("alpha", "1.0.0"),
+		"alpha/file.txt":             "alpha\n",
+		"alpha/nested/template.yaml": templateManifest("nested", "1.0.0"),
+		"group/beta/template.yaml":   templateManifest("beta", "2.0.0"),
+		".git/template.yaml":         templateManifest("ignored", "1.0.0"),
+	})
+	dir := filepath.Join(t.TempDir(), "templates")
+
+	changes, err := Install(dir, Options{Source: src})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "alpha", changes[0].Name)
+	assert.Equal(t, "beta", changes[1].Name)
+	assert.Empty(t, changes[0].Previous)
+
+	assert.FileExists(t, filepath.Join(dir, "alpha", "file.txt"))
+	assert.FileExists(t, filepath.Join(dir, "alpha", "nested", "template.yaml"))
+	assert.FileExists(t, filepath.Join(dir, "beta", "template.yaml"))
+
+	records, err := List(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, Record{
+		Name:        "beta",
+		Type:        "feature",
+		Version:     "2.0.0",
+		Path:        "beta",
+		Source:      src,
+		Kind:        KindPath,
+		InstalledAt: records[1].InstalledAt,
+	}, records[1])
+}
+
+func TestInstallDryRun(t *testing.T) {
+	src := t.TempDir()
+	writeSource(t, src, map[string]string{"template.yaml": templateManifest("alpha", "1.0.0")})
+	dir := filepath.Join(t.TempDir(), "templates")
+
+	changes, err := Install(dir, Options{Source: src, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.NoDirExists(t, dir)
+}
+
+func TestInstallConflicts(t *testing.T) {
+	src := t.TempDir()
+	writeSource(t, src, map[string]string{"template.yaml": templateManifest("alpha", "1.0.0")})
+
+	t.Run("copied by hand", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSource(t, dir, map[string]string{"alpha/template.yaml": templateManifest("alpha", "0.1.0")})
+
+		_, err := Install(dir, Options{Source: src})
+		var conflict *ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "alpha", conflict.Name)
+
+		_, err = Install(dir, Options{Source: src, Force: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("elsewhere in the directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeSource(t, dir, map[string]string{"mine/alpha/template.yaml": templateManifest("alpha", "0.1.0")})
+
+		_, err := Install(dir, Options{Source: src, Force: true})
+		var conflict *ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, filepath.Join(dir, "mine", "alpha"), conflict.Path)
+	})
+
+	t.Run("from another source", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := Install(dir, Options{Source: src})
+		require.NoError(t, err)
+
+		other := t.TempDir()
+		writeSource(t, other, map[string]string{"template.yaml": templateManifest("alpha", "9.0.0")})
+
+		_, err = Install(dir, Options{Source: other})
+		var conflict *ConflictError
+		require.ErrorAs(t, err, &conflict)
+	})
+}
+
+func TestInstallNoTemplates(t *testing.T) {
+	_, err := Install(t.TempDir(), Options{Source: t.TempDir()})
+	var noTemplates *NoTemplatesError
+	require.ErrorAs(t, err, &noTemplates)
+}
+
+func TestUpdateAndUninstall(t *testing.T) {
+	src := t.TempDir()
+	writeSource(t, src, map[string]string{
+		"alpha/template.yaml": templateManifest("alpha", "1.0.0"),
+		"alpha/old.txt":       "old\n",
+		"beta/template.yaml":  templateManifest("beta", "1.0.0"),
+	})
+	dir := t.TempDir()
+
+	_, err := Install(dir, Options{Source: src})
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(filepath.Join(src, "alpha", "old.txt")))
+	writeSource(t, src, map[string]string{
+		"alpha/template.yaml": templateManifest("alpha", "1.1.0"),
+		"beta/template.yaml":  templateManifest("beta", "2.0.0"),
+	})
+
+	changes, err := Update(dir, []string{"alpha"}, nil, false)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "1.0.0", changes[0].Previous)
+	assert.Equal(t, "1.1.0", changes[0].Version)
+	assert.NoFileExists(t, filepath.Join(dir, "alpha", "old.txt"))
+
+	records, err := List(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", records[0].Version)
+	assert.Equal(t, "1.0.0", records[1].Version)
+
+	changes, err = Uninstall(dir, []string{"beta"}, false)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.NoDirExists(t, filepath.Join(dir, "beta"))
+
+	records, err = List(dir)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "alpha", records[0].Name)
+
+	_, err = Uninstall(dir, []string{"beta"}, false)
+	var notInstalled *NotInstalledError
+	require.ErrorAs(t, err, &notInstalled)
+}
+
+func TestUpdateRemovedTemplate(t *testing.T) {
+	src := t.TempDir()
+	writeSource(t, src, map[string]string{"alpha/template.yaml": templateManifest("alpha", "1.0.0")})
+	dir := t.TempDir()
+
+	_, err := Install(dir, Options{Source: src})
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(filepath.Join(src, "alpha")))
+	writeSource(t, src, map[string]string{"beta/template.yaml": templateManifest("beta", "1.0.0")})
+
+	_, err = Update(dir, nil, nil, false)
+	require.ErrorContains(t, err, `template "alpha" is no longer provided`)
+	assert.DirExists(t, filepath.Join(dir, "alpha"))
+}
+
+func TestInstallFromTarGz(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "templates.tar.gz")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"templates-main/alpha/template.yaml": templateManifest("alpha", "1.0.0"),
+		"templates-main/alpha/main.go.tmpl":  "package main\n",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	dir := t.TempDir()
+	changes, err := Install(dir, Options{Source: archive})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, KindArchive, changes[0].Kind)
+	assert.FileExists(t, filepath.Join(dir, "alpha", "main.go.tmpl"))
+}
+
+func TestInstallFromZipRejectsEscapingEntries(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "templates.zip")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("../escape/template.yaml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(templateManifest("alpha", "1.0.0")))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	_, err = Install(t.TempDir(), Options{Source: archive})
+	require.ErrorContains(t, err, "outside the archive")
+}
+
+func TestInstallFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	writeSource(t, repo, map[string]string{"template.yaml": templateManifest("alpha", "1.0.0")})
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	dir := t.TempDir()
+	changes, err := Install(dir, Options{Source: "file://" + filepath.ToSlash(repo), Ref: "main"})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, KindGit, changes[0].Kind)
+	assert.Equal(t, "main", changes[0].Ref)
+	assert.Len(t, changes[0].Commit, 40)
+	assert.NoDirExists(t, filepath.Join(dir, "alpha", ".git"))
+	assert.FileExists(t, filepath.Join(dir, "alpha", "template.yaml"))
+}
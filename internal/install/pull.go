@@ -0,0 +1,89 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PullResult describes the update of a templates directory that is a git
+// repository.
+type PullResult struct {
+	Root     string   // Top level of the work tree
+	Upstream string   // Branch pulled from, e.g. origin/main
+	Before   string   // Commit before pulling
+	After    string   // Commit after pulling; the upstream commit for a dry run
+	Files    []string // Files changed by the pull, relative to Root
+}
+
+// UpToDate reports whether the pull brought in no commits.
+func (r *PullResult) UpToDate() bool {
+	return r.Before == r.After
+}
+
+// GitRoot returns the top level of the git work tree dir belongs to, or an
+// empty string when it is not in one. Linked worktrees are work trees too.
+func GitRoot(dir string) string {
+	root, err := runGit("-C", dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// Pull fast-forwards the git repository the templates directory belongs to
+// from its upstream branch. Local commits and uncommitted changes are left to
+// git: a pull that cannot fast-forward, or that would overwrite local
+// changes, fails without changing anything. A dry run fetches the upstream
+// branch and reports the changes a pull would bring in.
+func Pull(dir string, dryRun bool) (*PullResult, error) {
+	root := GitRoot(dir)
+	if root == "" {
+		return nil, &NotGitRepoError{Dir: dir}
+	}
+
+	upstream, err := runGit("-C", root, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return nil, &NoUpstreamError{Dir: root}
+	}
+
+	before, err := runGit("-C", root, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit of %s: %w", root, err)
+	}
+	result := &PullResult{Root: root, Upstream: upstream, Before: before}
+
+	var diff string
+	if dryRun {
+		if _, err := runGit("-C", root, "fetch", "--quiet"); err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", upstream, err)
+		}
+		if result.After, err = runGit("-C", root, "rev-parse", "@{upstream}"); err != nil {
+			return nil, fmt.Errorf("failed to read commit of %s: %w", upstream, err)
+		}
+		if _, err := runGit("-C", root, "merge-base", "--is-ancestor", "HEAD", "@{upstream}"); err != nil {
+			return nil, fmt.Errorf("cannot fast-forward %s to %s: the branches have diverged", root, upstream)
+		}
+		diff = "HEAD...@{upstream}"
+	} else {
+		if _, err := runGit("-C", root, "pull", "--ff-only", "--quiet"); err != nil {
+			return nil, fmt.Errorf("failed to pull %s: %w", upstream, err)
+		}
+		if result.After, err = runGit("-C", root, "rev-parse", "HEAD"); err != nil {
+			return nil, fmt.Errorf("failed to read commit of %s: %w", root, err)
+		}
+		diff = before + ".." + result.After
+	}
+
+	if result.UpToDate() {
+		return result, nil
+	}
+
+	files, err := runGit("-C", root, "diff", "--name-only", diff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	if files != "" {
+		result.Files = strings.Split(files, "\n")
+	}
+	return result, nil
+}
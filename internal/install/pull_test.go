@@ -0,0 +1,81 @@
+package install
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	origin := t.TempDir()
+	writeSource(t, origin, map[string]string{"alpha/template.yaml": templateManifest("alpha", "1.0.0")})
+	git(t, origin, "init", "-q", "-b", "main")
+	git(t, origin, "add", ".")
+	git(t, origin, "commit", "-q", "-m", "init")
+
+	clone := filepath.Join(t.TempDir(), "templates")
+	git(t, origin, "clone", "-q", origin, clone)
+
+	result, err := Pull(clone, false)
+	require.NoError(t, err)
+	assert.True(t, result.UpToDate())
+	assert.Equal(t, "origin/main", result.Upstream)
+
+	writeSource(t, origin, map[string]string{"alpha/template.yaml": templateManifest("alpha", "1.1.0")})
+	git(t, origin, "commit", "-q", "-am", "bump")
+
+	// A dry run reports the incoming change without applying it.
+	result, err = Pull(filepath.Join(clone, "alpha"), true)
+	require.NoError(t, err)
+	assert.False(t, result.UpToDate())
+	assert.Equal(t, []string{"alpha/template.yaml"}, result.Files)
+	assert.Equal(t, result.Before, gitHead(t, clone))
+
+	result, err = Pull(clone, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha/template.yaml"}, result.Files)
+	assert.Equal(t, result.After, gitHead(t, clone))
+}
+
+func TestPull_Errors(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	_, err := Pull(t.TempDir(), false)
+	var notRepo *NotGitRepoError
+	require.ErrorAs(t, err, &notRepo)
+
+	repo := t.TempDir()
+	writeSource(t, repo, map[string]string{"alpha/template.yaml": templateManifest("alpha", "1.0.0")})
+	git(t, repo, "init", "-q", "-b", "main")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "-q", "-m", "init")
+
+	_, err = Pull(repo, false)
+	var noUpstream *NoUpstreamError
+	require.ErrorAs(t, err, &noUpstream)
+}
+
+// gitHead returns the commit checked out in dir.
+func gitHead(t *testing.T, dir string) string {
+	t.Helper()
+	head, err := runGit("-C", dir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	return head
+}
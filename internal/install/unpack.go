@@ -0,0 +1,72 @@
+package install
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// UnpackResult describes a packed template extracted by Unpack.
+type UnpackResult struct {
+	Name    string
+	Version string
+	Dir     string // Directory the template was extracted to
+	Files   int    // Number of files extracted
+}
+
+// Unpack verifies the packed template at archive and extracts it into a
+// directory named after the template in dir. An existing directory of that
+// name is only replaced with force.
+func Unpack(archive, dir string, force, dryRun bool) (*UnpackResult, error) {
+	packed, err := template.OpenArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	name := packed.Manifest.Name
+
+	dest := filepath.Join(dir, filepath.FromSlash(name))
+	// Replacing dest removes it, so it must never be dir or lie outside it.
+	if rel, err := filepath.Rel(dir, dest); err != nil || rel == "." || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("template name %q cannot be used as a directory name", name)
+	}
+	if _, err := os.Stat(dest); err == nil && !force {
+		return nil, fmt.Errorf("%s already exists; pass --force to replace it", dest)
+	}
+
+	result := &UnpackResult{
+		Name:    name,
+		Version: packed.Manifest.Version,
+		Dir:     dest,
+		Files:   len(packed.Manifest.Files),
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if err := os.RemoveAll(dest); err != nil {
+		return nil, fmt.Errorf("failed to replace %s: %w", dest, err)
+	}
+	err = fs.WalkDir(packed.FS, name, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		content, err := fs.ReadFile(packed.FS, pth)
+		if err != nil {
+			return err
+		}
+		return extractFile(dir, pth, info.Mode(), bytes.NewReader(content))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack %s: %w", archive, err)
+	}
+
+	return result, nil
+}
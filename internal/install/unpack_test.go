@@ -0,0 +1,70 @@
+package install
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePack writes a packed template named name holding files, keyed by
+// their path in the archive, and returns its path.
+func writePack(t *testing.T, name string, files map[string]string) string {
+	t.Helper()
+	manifest := template.PackManifest{
+		Format:  template.PackFormat,
+		Name:    name,
+		Type:    template.TypeFeature,
+		Version: "1.0.0",
+		Files:   make(map[string]string, len(files)),
+	}
+	entries := make(map[string]string, len(files)+1)
+	for pth, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		manifest.Files[pth] = hex.EncodeToString(sum[:])
+		entries[pth] = content
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	entries[template.PackManifestName] = string(data)
+
+	archive := filepath.Join(t.TempDir(), name+template.ArchiveExtension)
+	require.NoError(t, os.WriteFile(archive, tarGz(t, entries), 0644))
+	return archive
+}
+
+func TestUnpack(t *testing.T) {
+	archive := writePack(t, "alpha", map[string]string{
+		"alpha/template.yaml": templateManifest("alpha", "1.0.0"),
+		"alpha/main.go.tmpl":  "package main\n",
+	})
+	dir := t.TempDir()
+
+	result, err := Unpack(archive, dir, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", result.Name)
+	assert.Equal(t, 2, result.Files)
+	assert.FileExists(t, filepath.Join(dir, "alpha", "main.go.tmpl"))
+
+	_, err = Unpack(archive, dir, false, false)
+	assert.ErrorContains(t, err, "pass --force to replace it")
+}
+
+func TestUnpackRejectsNameOfTargetDir(t *testing.T) {
+	archive := writePack(t, ".", map[string]string{
+		"template.yaml": templateManifest(".", "1.0.0"),
+	})
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.txt")
+	require.NoError(t, os.WriteFile(keep, []byte("keep\n"), 0644))
+
+	_, err := Unpack(archive, dir, true, false)
+	require.ErrorContains(t, err, `invalid template name "."`)
+	assert.FileExists(t, keep)
+}
@@ -0,0 +1,12 @@
+package manifest
+
+import "fmt"
+
+// NotFoundError is returned when no manifest exists for a project.
+type NotFoundError struct {
+	Path string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no blueprint manifest found for %s", e.Path)
+}
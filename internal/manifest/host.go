@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HostFileName is the name of the user-level journal of files written
+// outside a project.
+const HostFileName = "host-files.yaml"
+
+// HostJournal records the files Blueprint wrote outside of projects, such as
+// shell aliases or tool configuration, so they can be found again after the
+// project that wrote them is gone.
+type HostJournal struct {
+	SchemaVersion int        `yaml:"schema_version"`
+	Files         []HostFile `yaml:"files"`
+}
+
+// HostFile records the provenance of a file written outside a project.
+type HostFile struct {
+	Path      string    `yaml:"path"`             // Absolute path of the file
+	Target    string    `yaml:"target,omitempty"` // home or xdg-config; empty for files of allow_outside_output templates
+	Template  string    `yaml:"template"`
+	Project   string    `yaml:"project"` // Absolute path of the project that wrote the file
+	Hash      string    `yaml:"sha256"`
+	WrittenAt time.Time `yaml:"written_at"`
+}
+
+// DefaultHostJournalPath returns the path of the host journal inside the user
+// config directory.
+func DefaultHostJournalPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "blueprint", HostFileName), nil
+}
+
+// LoadHostJournal reads the host journal at path. A missing journal is empty.
+func LoadHostJournal(path string) (*HostJournal, error) {
+	j := &HostJournal{SchemaVersion: SchemaVersion}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return j, nil
+		}
+		return nil, fmt.Errorf("failed to read host journal: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("failed to parse host journal: %w", err)
+	}
+
+	return j, nil
+}
+
+// Record adds a file to the journal, replacing an earlier record of the same
+// path.
+func (j *HostJournal) Record(f HostFile) {
+	for i := range j.Files {
+		if j.Files[i].Path == f.Path {
+			j.Files[i] = f
+			return
+		}
+	}
+	j.Files = append(j.Files, f)
+}
+
+// Save writes the journal to path.
+func (j *HostJournal) Save(path string) error {
+	data, err := yaml.Marshal(j)
+	if err != nil {
+		return fmt.Errorf("failed to encode host journal: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create host journal directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write host journal: %w", err)
+	}
+
+	return nil
+}
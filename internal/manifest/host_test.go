@@ -0,0 +1,28 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostJournal_RecordSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blueprint", HostFileName)
+
+	j, err := LoadHostJournal(path)
+	require.NoError(t, err)
+	assert.Empty(t, j.Files)
+
+	j.Record(HostFile{Path: "/home/me/.aliases", Target: "home", Template: "aliases", Hash: "a"})
+	j.Record(HostFile{Path: "/home/me/.config/app/config.yaml", Target: "xdg-config", Template: "app", Hash: "b"})
+	j.Record(HostFile{Path: "/home/me/.aliases", Target: "home", Template: "aliases", Hash: "c"})
+	require.NoError(t, j.Save(path))
+
+	loaded, err := LoadHostJournal(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Files, 2)
+	assert.Equal(t, "c", loaded.Files[0].Hash)
+	assert.Equal(t, "xdg-config", loaded.Files[1].Target)
+}
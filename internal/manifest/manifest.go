@@ -0,0 +1,235 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Dir is the directory inside a generated project that holds blueprint state.
+	Dir = ".blueprint"
+
+	// FileName is the name of the manifest file inside Dir.
+	FileName = "manifest.yaml"
+
+	// SchemaVersion is the current manifest schema version.
+	SchemaVersion = 1
+)
+
+// Manifest records how a project was generated.
+type Manifest struct {
+	SchemaVersion    int            `yaml:"schema_version"`
+	Template         string         `yaml:"template"`
+	TemplateVersion  string         `yaml:"template_version"`
+	BlueprintVersion string         `yaml:"blueprint_version"`
+	CreatedAt        time.Time      `yaml:"created_at"`
+	Nodes            []Node         `yaml:"nodes"`
+	Versions         []Version      `yaml:"versions,omitempty"` // Versions chosen for templates that includes constrain
+	Files            []File         `yaml:"files"`
+	Injections       []Injection    `yaml:"injections,omitempty"` // Code templates inserted into files of the project
+	Components       []Component    `yaml:"components,omitempty"` // Generations of component templates added to the project
+	PostInit         []PostInitStep `yaml:"post_init,omitempty"`  // Post-init commands that completed
+}
+
+// Node records a template of the composed tree and the variables it was rendered with.
+type Node struct {
+	ID        string            `yaml:"id"`
+	Template  string            `yaml:"template"`
+	Version   string            `yaml:"version"`
+	Mount     string            `yaml:"mount,omitempty"`
+	Mandated  bool              `yaml:"mandated,omitempty"`
+	Variables map[string]any    `yaml:"variables,omitempty"`
+	Roles     map[string]string `yaml:"roles,omitempty"` // Variable names keyed by role
+	Clean     []string          `yaml:"clean,omitempty"` // Project-relative patterns of ignorable generated paths
+}
+
+// Version records the version chosen for a template that includes constrain:
+// the newest available one that satisfies every constraint.
+type Version struct {
+	Template   string            `yaml:"template"`
+	Version    string            `yaml:"version"`
+	RequiredBy map[string]string `yaml:"required_by"` // Constraint keyed by the including template
+}
+
+// Component records a generation of a component template added to the
+// project, such as one handler, so that it can be listed and generated again.
+type Component struct {
+	Name        string         `yaml:"name"` // Unique within the project, e.g. users-handler
+	Template    string         `yaml:"template"`
+	Version     string         `yaml:"version"`
+	Node        string         `yaml:"node"`                // Node the files of the generation are recorded under
+	Variables   map[string]any `yaml:"variables,omitempty"` // Values of the variables the template declares
+	GeneratedAt time.Time      `yaml:"generated_at"`
+}
+
+// File records the provenance of a generated file.
+type File struct {
+	Path   string `yaml:"path"`
+	Node   string `yaml:"node"`
+	Source string `yaml:"source"`
+	Hash   string `yaml:"sha256"`
+}
+
+// Injection records code that a node inserted into a project file, at an
+// anchor comment or with a Go edit, so that it can be taken out again when
+// the template is removed.
+type Injection struct {
+	Path   string `yaml:"path"`
+	Node   string `yaml:"node"`
+	Anchor string `yaml:"anchor,omitempty"`  // Anchor comment the code was inserted at
+	GoEdit string `yaml:"go_edit,omitempty"` // Kind of a Go edit: import, field, or statement
+	Name   string `yaml:"name,omitempty"`    // Struct or function a Go edit added to, or the name of an import
+	Code   string `yaml:"code"`              // Inserted code; the import path of an import
+}
+
+// PostInitStep records a post-init command that completed, so that a later
+// run into the project does not repeat it.
+type PostInitStep struct {
+	Command     string    `yaml:"command"`
+	Dir         string    `yaml:"dir"` // Project-relative, slash-separated
+	CompletedAt time.Time `yaml:"completed_at"`
+}
+
+// Path returns the manifest path for the project rooted at root.
+func Path(root string) string {
+	return filepath.Join(root, Dir, FileName)
+}
+
+// Load reads the manifest of the project rooted at root.
+func Load(root string) (*Manifest, error) {
+	data, err := os.ReadFile(Path(root))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &NotFoundError{Path: root}
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	return &m, nil
+}
+
+// Save writes the manifest into the project rooted at root.
+func (m *Manifest) Save(root string) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	path := Path(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// FindRoot walks up from start until it finds a directory containing a manifest.
+func FindRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", start, err)
+	}
+
+	for {
+		if _, err := os.Stat(Path(dir)); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", &NotFoundError{Path: start}
+		}
+		dir = parent
+	}
+}
+
+// FileByPath returns the record for the file at the given project-relative path.
+func (m *Manifest) FileByPath(path string) (*File, bool) {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for i := range m.Files {
+		if m.Files[i].Path == path {
+			return &m.Files[i], true
+		}
+	}
+	return nil, false
+}
+
+// Node returns the node with the given ID.
+func (m *Manifest) Node(id string) (*Node, bool) {
+	for i := range m.Nodes {
+		if m.Nodes[i].ID == id {
+			return &m.Nodes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Component returns the generation of a component with the given name.
+func (m *Manifest) Component(name string) (*Component, bool) {
+	for i := range m.Components {
+		if m.Components[i].Name == name {
+			return &m.Components[i], true
+		}
+	}
+	return nil, false
+}
+
+// PostInitStep returns the record of a completed post-init command run in
+// the given project-relative directory.
+func (m *Manifest) PostInitStep(command, dir string) (*PostInitStep, bool) {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	for i := range m.PostInit {
+		if m.PostInit[i].Command == command && m.PostInit[i].Dir == dir {
+			return &m.PostInit[i], true
+		}
+	}
+	return nil, false
+}
+
+// RoleVariable returns the name of the variable that has the given role in
+// the node with the given ID.
+func (m *Manifest) RoleVariable(id, role string) (string, bool) {
+	node, ok := m.Node(id)
+	if !ok {
+		return "", false
+	}
+	name, ok := node.Roles[role]
+	return name, ok
+}
+
+// Lineage returns the chain of nodes from the root down to the node with the given ID.
+func (m *Manifest) Lineage(id string) []Node {
+	var chain []Node
+
+	parts := strings.Split(id, ".")
+	for i := range parts {
+		if node, ok := m.Node(strings.Join(parts[:i+1], ".")); ok {
+			chain = append(chain, *node)
+		}
+	}
+
+	return chain
+}
+
+// HashContent returns the hex-encoded SHA-256 hash of content.
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
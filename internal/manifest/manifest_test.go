@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_SaveLoad(t *testing.T) {
+	root := t.TempDir()
+
+	m := &Manifest{
+		SchemaVersion: SchemaVersion,
+		Template:      "go-cli",
+		Nodes: []Node{
+			{ID: "0", Template: "go-cli", Variables: map[string]any{"app_name": "demo"}},
+			{ID: "0.0", Template: "go-testing"},
+		},
+		Files: []File{
+			{Path: "main_test.go", Node: "0.0", Source: "features/go/testing/main_test.go.tmpl"},
+		},
+	}
+	require.NoError(t, m.Save(root))
+
+	loaded, err := Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, "go-cli", loaded.Template)
+
+	file, ok := loaded.FileByPath("./main_test.go")
+	require.True(t, ok)
+	assert.Equal(t, "0.0", file.Node)
+
+	lineage := loaded.Lineage(file.Node)
+	require.Len(t, lineage, 2)
+	assert.Equal(t, "go-cli", lineage[0].Template)
+	assert.Equal(t, "go-testing", lineage[1].Template)
+}
+
+func TestFindRoot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, (&Manifest{SchemaVersion: SchemaVersion}).Save(root))
+
+	nested := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+
+	found, err := FindRoot(nested)
+	require.NoError(t, err)
+	assert.Equal(t, root, found)
+
+	_, err = Load(t.TempDir())
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
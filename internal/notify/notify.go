@@ -0,0 +1,201 @@
+// Package notify posts the summary of a scaffold run to a webhook or a
+// command, so that people a project was provisioned for hear about it when
+// blueprint runs inside automation.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// webhookTimeout bounds how long a run waits for the webhook.
+const webhookTimeout = 10 * time.Second
+
+// Summary describes a scaffold run.
+type Summary struct {
+	Template     string    `json:"template"`
+	Version      string    `json:"version,omitempty"`
+	Project      string    `json:"project,omitempty"`
+	OutputDir    string    `json:"output_dir,omitempty"`
+	Success      bool      `json:"success"`
+	Error        string    `json:"error,omitempty"`
+	FilesWritten int       `json:"files_written"`
+	Links        []string  `json:"links,omitempty"` // Web URLs of the project, e.g. of its git remotes
+	Time         time.Time `json:"time"`
+
+	// Text is the summary as a message. Slack and Teams incoming webhooks
+	// show it as is.
+	Text string `json:"text"`
+}
+
+// NewSummary returns the summary of a run. runErr is the error the run
+// failed with, if any.
+func NewSummary(templateName, templateVersion, outputDir string, filesWritten int, runErr error) Summary {
+	s := Summary{
+		Template:     templateName,
+		Version:      templateVersion,
+		OutputDir:    outputDir,
+		Success:      runErr == nil,
+		FilesWritten: filesWritten,
+		Time:         time.Now().UTC(),
+	}
+	if outputDir != "" {
+		s.Project = filepath.Base(outputDir)
+		s.Links = ProjectLinks(outputDir)
+	}
+	if runErr != nil {
+		s.Error = runErr.Error()
+	}
+	s.Text = s.message()
+	return s
+}
+
+// message formats the summary as a short message.
+func (s Summary) message() string {
+	name := s.Template
+	if s.Version != "" {
+		name += " " + s.Version
+	}
+
+	var b strings.Builder
+	switch {
+	case s.Success:
+		fmt.Fprintf(&b, "✓ Created %s from %s (%d files) in %s", s.Project, name, s.FilesWritten, s.OutputDir)
+	case s.Project != "":
+		fmt.Fprintf(&b, "✗ Creating %s from %s failed: %s", s.Project, name, s.Error)
+	default:
+		fmt.Fprintf(&b, "✗ Scaffolding %s failed: %s", name, s.Error)
+	}
+	for _, link := range s.Links {
+		b.WriteString("\n" + link)
+	}
+	return b.String()
+}
+
+// Send posts the summary to webhook and runs command with the summary on
+// stdin, skipping whichever is empty. Both are attempted when one fails.
+func Send(webhook, command string, s Summary) error {
+	var errs []error
+	if webhook != "" {
+		errs = append(errs, PostWebhook(webhook, s))
+	}
+	if command != "" {
+		errs = append(errs, RunCommand(command, s))
+	}
+	return errors.Join(errs...)
+}
+
+// PostWebhook posts the summary as JSON to webhook.
+func PostWebhook(webhook string, s Summary) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post notification: %s", resp.Status)
+	}
+	return nil
+}
+
+// RunCommand runs command in the platform shell with the summary as JSON on
+// stdin.
+func RunCommand(command string, s Summary) error {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(body)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("notify command %q failed: %w: %s", command, err, msg)
+		}
+		return fmt.Errorf("notify command %q failed: %w", command, err)
+	}
+	return nil
+}
+
+// ProjectLinks returns web URLs of the git remotes of the project in dir, for
+// example one a post-init command created. Remotes given as scp-like SSH
+// addresses, such as git@github.com:acme/app.git, are turned into HTTPS URLs.
+func ProjectLinks(dir string) []string {
+	out, err := exec.Command("git", "-C", dir, "remote").Output()
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	for _, remote := range strings.Fields(string(out)) {
+		out, err := exec.Command("git", "-C", dir, "remote", "get-url", remote).Output()
+		if err != nil {
+			continue
+		}
+		if link := webURL(strings.TrimSpace(string(out))); link != "" {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// webURL returns the web URL of a git remote, or an empty string for a
+// remote that is not hosted, such as a local path.
+func webURL(remote string) string {
+	remote = strings.TrimSuffix(remote, ".git")
+
+	switch {
+	case strings.HasPrefix(remote, "https://"), strings.HasPrefix(remote, "http://"):
+		// Credentials in the URL must not be posted.
+		u, err := url.Parse(remote)
+		if err != nil {
+			return ""
+		}
+		u.User = nil
+		return u.String()
+	case strings.HasPrefix(remote, "ssh://"):
+		rest := strings.TrimPrefix(remote, "ssh://")
+		if _, host, ok := strings.Cut(rest, "@"); ok {
+			rest = host
+		}
+		host, path, _ := strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host, ":")
+		return "https://" + host + "/" + path
+	}
+
+	// scp-like syntax: [user@]host:path
+	if strings.Contains(remote, "://") {
+		return ""
+	}
+	host, path, ok := strings.Cut(remote, ":")
+	// A single letter is the drive of a Windows path.
+	if !ok || len(host) < 2 || strings.ContainsAny(host, `/\`) {
+		return ""
+	}
+	if _, h, ok := strings.Cut(host, "@"); ok {
+		host = h
+	}
+	return "https://" + host + "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,400 @@
+package oci
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Media types of template artifacts. The manifest is a plain OCI image
+// manifest, so any OCI 1.1 registry stores it.
+const (
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ArtifactType      = "application/vnd.blueprint.template.v1"
+	ConfigMediaType   = "application/vnd.blueprint.template.config.v1+json"
+	LayerMediaType    = "application/vnd.blueprint.template.layer.v1.tar+gzip"
+)
+
+const (
+	requestTimeout  = 2 * time.Minute
+	maxManifestSize = 4 << 20
+	maxLayerSize    = 256 << 20
+)
+
+// Descriptor describes a blob or manifest in a registry.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Artifact is a template packaged for a registry.
+type Artifact struct {
+	Config []byte // JSON metadata of the template
+	Layer  []byte // Gzipped tar of the template directory
+}
+
+// Client talks to OCI registries. It authenticates with the token or basic
+// authentication a registry asks for, using the credentials of
+// LookupCredentials, and caches the tokens it obtains.
+type Client struct {
+	http        *http.Client
+	credentials func(registry string) (username, password string)
+	auth        map[string]string // Authorization header by registry and scope
+}
+
+// NewClient returns a client using the credentials of LookupCredentials.
+func NewClient() *Client {
+	return &Client{
+		http:        &http.Client{Timeout: requestTimeout},
+		credentials: LookupCredentials,
+		auth:        make(map[string]string),
+	}
+}
+
+// Push uploads an artifact and tags its manifest with the tag of ref. It
+// returns the digest of the manifest, which pins the pushed artifact.
+func (c *Client) Push(ref Reference, artifact Artifact, annotations map[string]string) (string, error) {
+	if ref.Tag == "" || ref.Digest != "" {
+		return "", fmt.Errorf("push %s: a tag and no digest is required", ref)
+	}
+	scope := "repository:" + ref.Repository + ":pull,push"
+
+	config := Descriptor{MediaType: ConfigMediaType, Digest: digestOf(artifact.Config), Size: int64(len(artifact.Config))}
+	layer := Descriptor{
+		MediaType:   LayerMediaType,
+		Digest:      digestOf(artifact.Layer),
+		Size:        int64(len(artifact.Layer)),
+		Annotations: map[string]string{"org.opencontainers.image.title": "template.tar.gz"},
+	}
+	if err := c.pushBlob(ref, scope, config.Digest, artifact.Config); err != nil {
+		return "", err
+	}
+	if err := c.pushBlob(ref, scope, layer.Digest, artifact.Layer); err != nil {
+		return "", err
+	}
+
+	manifest, err := json.Marshal(Manifest{
+		SchemaVersion: 2,
+		MediaType:     ManifestMediaType,
+		ArtifactType:  ArtifactType,
+		Config:        config,
+		Layers:        []Descriptor{layer},
+		Annotations:   annotations,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	resp, err := c.do(ref, scope, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, ref.baseURL()+"/v2/"+ref.Repository+"/manifests/"+ref.Tag, bytes.NewReader(manifest))
+		if err == nil {
+			req.Header.Set("Content-Type", ManifestMediaType)
+		}
+		return req, err
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", responseError(resp, "push manifest of "+ref.String())
+	}
+
+	return digestOf(manifest), nil
+}
+
+// pushBlob uploads a blob unless the repository already has it.
+func (c *Client) pushBlob(ref Reference, scope, digest string, blob []byte) error {
+	blobURL := ref.baseURL() + "/v2/" + ref.Repository + "/blobs/" + digest
+	resp, err := c.do(ref, scope, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodHead, blobURL, nil)
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = c.do(ref, scope, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, ref.baseURL()+"/v2/"+ref.Repository+"/blobs/uploads/", nil)
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return responseError(resp, "start upload to "+ref.String())
+	}
+
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return fmt.Errorf("start upload to %s: registry returned no upload location", ref)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = c.do(ref, scope, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, location.String(), bytes.NewReader(blob))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/octet-stream")
+		}
+		return req, err
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp, "upload blob to "+ref.String())
+	}
+	return nil
+}
+
+// Pull downloads the artifact ref points to and returns it with the digest
+// of its manifest. A pinned reference fails when the manifest does not match
+// its digest, and every blob is checked against the digest the manifest
+// records.
+func (c *Client) Pull(ref Reference) (*Artifact, string, error) {
+	scope := "repository:" + ref.Repository + ":pull"
+
+	resp, err := c.do(ref, scope, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodGet, ref.baseURL()+"/v2/"+ref.Repository+"/manifests/"+ref.manifestRef(), nil)
+		if err == nil {
+			req.Header.Set("Accept", ManifestMediaType)
+		}
+		return req, err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", responseError(resp, "pull "+ref.String())
+	}
+
+	data, err := readLimited(resp.Body, maxManifestSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("pull %s: %w", ref, err)
+	}
+	digest := digestOf(data)
+	if ref.Digest != "" && digest != ref.Digest {
+		return nil, "", fmt.Errorf("pull %s: manifest has digest %s", ref, digest)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, "", fmt.Errorf("pull %s: invalid manifest: %w", ref, err)
+	}
+	if manifest.ArtifactType != ArtifactType && manifest.Config.MediaType != ConfigMediaType {
+		return nil, "", fmt.Errorf("pull %s: not a Blueprint template (artifact type %q)", ref, manifest.ArtifactType)
+	}
+
+	artifact := &Artifact{}
+	if artifact.Config, err = c.pullBlob(ref, scope, manifest.Config); err != nil {
+		return nil, "", err
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != LayerMediaType {
+			continue
+		}
+		if artifact.Layer, err = c.pullBlob(ref, scope, layer); err != nil {
+			return nil, "", err
+		}
+		return artifact, digest, nil
+	}
+	return nil, "", fmt.Errorf("pull %s: manifest has no layer of type %s", ref, LayerMediaType)
+}
+
+// pullBlob downloads a blob and checks it against its descriptor.
+func (c *Client) pullBlob(ref Reference, scope string, desc Descriptor) ([]byte, error) {
+	if desc.Size > maxLayerSize {
+		return nil, fmt.Errorf("pull %s: blob %s is too large (%d bytes)", ref, desc.Digest, desc.Size)
+	}
+
+	resp, err := c.do(ref, scope, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, ref.baseURL()+"/v2/"+ref.Repository+"/blobs/"+desc.Digest, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "pull blob of "+ref.String())
+	}
+
+	blob, err := readLimited(resp.Body, maxLayerSize)
+	if err != nil {
+		return nil, fmt.Errorf("pull %s: %w", ref, err)
+	}
+	if digestOf(blob) != desc.Digest || int64(len(blob)) != desc.Size {
+		return nil, fmt.Errorf("pull %s: blob does not match digest %s", ref, desc.Digest)
+	}
+	return blob, nil
+}
+
+// do sends a request built by newRequest. When the registry asks for
+// authentication, it obtains a token for scope, or uses basic
+// authentication, and sends the request again.
+func (c *Client) do(ref Reference, scope string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	key := ref.Registry + " " + scope
+
+	send := func() (*http.Response, error) {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if auth := c.auth[key]; auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach registry %s: %w", ref.Registry, err)
+		}
+		return resp, nil
+	}
+
+	resp, err := send()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || c.auth[key] != "" {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	auth, err := c.authenticate(ref, scope, challenge)
+	if err != nil {
+		return nil, err
+	}
+	c.auth[key] = auth
+	return send()
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate answers an authentication challenge with the value of the
+// Authorization header to send.
+func (c *Client) authenticate(ref Reference, scope, challenge string) (string, error) {
+	username, password := c.credentials(ref.Registry)
+	scheme, params, _ := strings.Cut(challenge, " ")
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if username == "" {
+			return "", &AuthError{Registry: ref.Registry}
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(username, password)
+		return req.Header.Get("Authorization"), nil
+
+	case "bearer":
+		values := make(map[string]string)
+		for _, m := range challengeParam.FindAllStringSubmatch(params, -1) {
+			values[m[1]] = m[2]
+		}
+		realm, err := url.Parse(values["realm"])
+		if err != nil || values["realm"] == "" {
+			return "", fmt.Errorf("registry %s sent an invalid authentication challenge", ref.Registry)
+		}
+		query := realm.Query()
+		if values["service"] != "" {
+			query.Set("service", values["service"])
+		}
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to authenticate with registry %s: %w", ref.Registry, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", &AuthError{Registry: ref.Registry, Status: resp.Status}
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", fmt.Errorf("failed to authenticate with registry %s: %w", ref.Registry, err)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		if token.Token == "" {
+			return "", &AuthError{Registry: ref.Registry}
+		}
+		return "Bearer " + token.Token, nil
+	}
+
+	return "", &AuthError{Registry: ref.Registry}
+}
+
+// responseError describes a failed registry request, with the messages of
+// the errors the registry returned.
+func responseError(resp *http.Response, action string) error {
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return &AuthError{Registry: resp.Request.URL.Host, Status: resp.Status}
+	}
+
+	var body struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	data, _ := readLimited(resp.Body, 64<<10)
+	if json.Unmarshal(data, &body) == nil && len(body.Errors) > 0 {
+		msgs := make([]string, len(body.Errors))
+		for i, e := range body.Errors {
+			msgs[i] = strings.TrimSpace(e.Code + " " + e.Message)
+		}
+		return fmt.Errorf("%s: %s: %s", action, resp.Status, strings.Join(msgs, "; "))
+	}
+	return fmt.Errorf("%s: %s", action, resp.Status)
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("response exceeds %d bytes", limit)
+	}
+	return data, nil
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
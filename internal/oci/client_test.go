@@ -0,0 +1,175 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a minimal OCI registry that requires a bearer token, which
+// its token endpoint hands out for the user alice.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+	tokens    int
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, *httptest.Server) {
+	r := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+	srv := httptest.NewServer(r.handler(t))
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+func (r *fakeRegistry) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if req.URL.Path == "/token" {
+			if user, pass, _ := req.BasicAuth(); user != "alice" || pass != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			r.tokens++
+			assert.NotEmpty(t, req.URL.Query().Get("scope"))
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "t0ken"})
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer t0ken" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="fake"`, req.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := req.URL.Path
+		switch {
+		case strings.HasPrefix(path, "/upload/"):
+			body, _ := io.ReadAll(req.Body)
+			r.blobs[req.URL.Query().Get("digest")] = body
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasSuffix(path, "/blobs/uploads/"):
+			r.uploads++
+			w.Header().Set("Location", fmt.Sprintf("/upload/%d?state=x", r.uploads))
+			w.WriteHeader(http.StatusAccepted)
+		case strings.Contains(path, "/blobs/"):
+			_, digest, _ := strings.Cut(path, "/blobs/")
+			blob, ok := r.blobs[digest]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(blob)
+		case strings.Contains(path, "/manifests/"):
+			repoPath, ref, _ := strings.Cut(path, "/manifests/")
+			key := repoPath + "@" + ref
+			if req.Method == http.MethodPut {
+				body, _ := io.ReadAll(req.Body)
+				r.manifests[key] = body
+				r.manifests[repoPath+"@"+digestOf(body)] = body
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			manifest, ok := r.manifests[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+				return
+			}
+			w.Header().Set("Content-Type", ManifestMediaType)
+			_, _ = w.Write(manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func testClient(username, password string) *Client {
+	c := NewClient()
+	c.credentials = func(string) (string, string) { return username, password }
+	return c
+}
+
+func testReference(t *testing.T, srv *httptest.Server, suffix string) Reference {
+	t.Helper()
+	ref, err := ParseReference(Scheme + strings.TrimPrefix(srv.URL, "http://") + "/acme/go-api" + suffix)
+	require.NoError(t, err)
+	return ref
+}
+
+func TestPushPull(t *testing.T) {
+	registry, srv := newFakeRegistry(t)
+	artifact := Artifact{Config: []byte(`{"name":"go-api"}`), Layer: []byte("layer")}
+
+	digest, err := testClient("alice", "s3cret").Push(testReference(t, srv, ":1.0.0"), artifact, map[string]string{"a": "b"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^sha256:[a-f0-9]{64}$`, digest)
+	assert.Equal(t, 1, registry.tokens, "the token is cached")
+
+	// Blobs the repository has are not uploaded again.
+	uploads := registry.uploads
+	_, err = testClient("alice", "s3cret").Push(testReference(t, srv, ":latest"), artifact, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uploads, registry.uploads)
+
+	pulled, pulledDigest, err := testClient("alice", "s3cret").Pull(testReference(t, srv, ":1.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, digest, pulledDigest)
+	assert.Equal(t, artifact.Layer, pulled.Layer)
+	assert.Equal(t, artifact.Config, pulled.Config)
+
+	pinned, pinnedDigest, err := testClient("alice", "s3cret").Pull(testReference(t, srv, "@"+digest))
+	require.NoError(t, err)
+	assert.Equal(t, digest, pinnedDigest)
+	assert.Equal(t, artifact.Layer, pinned.Layer)
+}
+
+func TestPullVerifiesDigests(t *testing.T) {
+	registry, srv := newFakeRegistry(t)
+	client := testClient("alice", "s3cret")
+
+	digest, err := client.Push(testReference(t, srv, ":1.0.0"), Artifact{Config: []byte("{}"), Layer: []byte("layer")}, nil)
+	require.NoError(t, err)
+
+	// A tag that moved to other content fails a pinned pull.
+	other := strings.Repeat("0", 64)
+	registry.manifests["/v2/acme/go-api@sha256:"+other] = registry.manifests["/v2/acme/go-api@1.0.0"]
+	_, _, err = client.Pull(testReference(t, srv, ":1.0.0@sha256:"+other))
+	require.ErrorContains(t, err, "manifest has digest "+digest)
+
+	// A corrupted blob fails the pull.
+	for d, blob := range registry.blobs {
+		if string(blob) == "layer" {
+			registry.blobs[d] = []byte("tampered")
+		}
+	}
+	_, _, err = client.Pull(testReference(t, srv, ":1.0.0"))
+	require.ErrorContains(t, err, "does not match digest")
+
+	_, _, err = client.Pull(testReference(t, srv, ":2.0.0"))
+	require.ErrorContains(t, err, "MANIFEST_UNKNOWN manifest unknown")
+}
+
+func TestPullRequiresCredentials(t *testing.T) {
+	_, srv := newFakeRegistry(t)
+
+	_, _, err := testClient("", "").Pull(testReference(t, srv, ":1.0.0"))
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.Equal(t, strings.TrimPrefix(srv.URL, "http://"), authErr.Registry)
+}
+
+func TestPushRequiresTag(t *testing.T) {
+	_, err := NewClient().Push(Reference{Registry: "ghcr.io", Repository: "acme/x"}, Artifact{}, nil)
+	require.ErrorContains(t, err, "a tag and no digest is required")
+}
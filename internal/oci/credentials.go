@@ -0,0 +1,84 @@
+package oci
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables holding registry credentials. They take precedence
+// over the Docker config file.
+const (
+	EnvUsername = "BLUEPRINT_OCI_USERNAME"
+	EnvPassword = "BLUEPRINT_OCI_PASSWORD"
+)
+
+// LookupCredentials returns the username and password for a registry: those
+// of BLUEPRINT_OCI_USERNAME and BLUEPRINT_OCI_PASSWORD when set, else those
+// docker login stored in the Docker config file ($DOCKER_CONFIG/config.json
+// or ~/.docker/config.json). Credential helpers are not supported. Both are
+// empty when there are no credentials.
+func LookupCredentials(registry string) (username, password string) {
+	if username := os.Getenv(EnvUsername); username != "" {
+		return username, os.Getenv(EnvPassword)
+	}
+
+	path := dockerConfigPath()
+	if path == "" {
+		return "", ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+	return dockerCredentials(data, registry)
+}
+
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// dockerCredentials returns the credentials for registry in a Docker config
+// file. Its auths are keyed by host, or by a URL of the host.
+func dockerCredentials(data []byte, registry string) (username, password string) {
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", ""
+	}
+
+	for key, entry := range config.Auths {
+		host := key
+		if u, err := url.Parse(key); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if host != registry {
+			continue
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return "", ""
+			}
+			username, password, _ = strings.Cut(string(decoded), ":")
+			return username, password
+		}
+		return entry.Username, entry.Password
+	}
+	return "", ""
+}
@@ -0,0 +1,18 @@
+package oci
+
+import "fmt"
+
+// AuthError is returned when a registry refuses access, for example because
+// no credentials are configured for it or they lack the needed permissions.
+type AuthError struct {
+	Registry string
+	Status   string // HTTP status of the refusal, if any
+}
+
+func (e *AuthError) Error() string {
+	msg := fmt.Sprintf("registry %s denied access", e.Registry)
+	if e.Status != "" {
+		msg += " (" + e.Status + ")"
+	}
+	return msg
+}
@@ -0,0 +1,108 @@
+// Package oci pushes templates to and pulls them from OCI registries, such as
+// GHCR or Harbor, as artifacts holding a single gzipped tar of the template
+// directory.
+package oci
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Scheme prefixes template sources that are OCI references.
+const Scheme = "oci://"
+
+var (
+	tagPattern    = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]{0,127}$`)
+	digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	repoPattern   = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+)
+
+// Reference is a reference to an artifact in a registry, written as
+// oci://registry/repository[:tag][@digest]. A digest pins the artifact; the
+// tag is then only informational.
+type Reference struct {
+	Registry   string // Host and optional port, e.g. ghcr.io
+	Repository string // e.g. acme/templates/go-api
+	Tag        string
+	Digest     string // e.g. sha256:…
+}
+
+// IsReference reports whether a source is an OCI reference.
+func IsReference(source string) bool {
+	return strings.HasPrefix(source, Scheme)
+}
+
+// ParseReference parses an OCI reference. The oci:// scheme is optional.
+func ParseReference(s string) (Reference, error) {
+	rest := strings.TrimPrefix(s, Scheme)
+
+	var ref Reference
+	if i := strings.Index(rest, "@"); i >= 0 {
+		ref.Digest = rest[i+1:]
+		rest = rest[:i]
+		if !digestPattern.MatchString(ref.Digest) {
+			return Reference{}, fmt.Errorf("invalid OCI reference %q: digest must be sha256:<64 hex digits>", s)
+		}
+	}
+
+	registry, repo, ok := strings.Cut(rest, "/")
+	if !ok || registry == "" {
+		return Reference{}, fmt.Errorf("invalid OCI reference %q: expected oci://registry/repository[:tag][@digest]", s)
+	}
+	if i := strings.LastIndex(repo, ":"); i >= 0 {
+		ref.Tag = repo[i+1:]
+		repo = repo[:i]
+		if !tagPattern.MatchString(ref.Tag) {
+			return Reference{}, fmt.Errorf("invalid OCI reference %q: invalid tag %q", s, ref.Tag)
+		}
+	}
+	if !repoPattern.MatchString(repo) {
+		return Reference{}, fmt.Errorf("invalid OCI reference %q: invalid repository %q", s, repo)
+	}
+
+	ref.Registry = registry
+	ref.Repository = repo
+	return ref, nil
+}
+
+// String returns the reference with the oci:// scheme.
+func (r Reference) String() string {
+	s := Scheme + r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// manifestRef returns what the manifest is addressed by: the digest when the
+// reference is pinned, else the tag, else latest.
+func (r Reference) manifestRef() string {
+	switch {
+	case r.Digest != "":
+		return r.Digest
+	case r.Tag != "":
+		return r.Tag
+	default:
+		return "latest"
+	}
+}
+
+// baseURL returns the URL of the registry's API. Registries on the loopback
+// interface are reached over plain HTTP, like local development registries
+// usually are; all others over HTTPS.
+func (r Reference) baseURL() string {
+	host := r.Registry
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if host == "localhost" || net.ParseIP(host).IsLoopback() {
+		return "http://" + r.Registry
+	}
+	return "https://" + r.Registry
+}
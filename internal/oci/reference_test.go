@@ -0,0 +1,66 @@
+package oci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	tests := []struct {
+		in   string
+		want Reference
+	}{
+		{"oci://ghcr.io/acme/go-api", Reference{Registry: "ghcr.io", Repository: "acme/go-api"}},
+		{"ghcr.io/acme/go-api:1.2.0", Reference{Registry: "ghcr.io", Repository: "acme/go-api", Tag: "1.2.0"}},
+		{"oci://localhost:5000/go-api:v1", Reference{Registry: "localhost:5000", Repository: "go-api", Tag: "v1"}},
+		{"oci://ghcr.io/acme/go-api@" + digest, Reference{Registry: "ghcr.io", Repository: "acme/go-api", Digest: digest}},
+		{"oci://ghcr.io/acme/go-api:1.2.0@" + digest, Reference{Registry: "ghcr.io", Repository: "acme/go-api", Tag: "1.2.0", Digest: digest}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"oci://ghcr.io", "oci:///acme/x", "oci://ghcr.io/Acme/x", "oci://ghcr.io/acme/x:bad tag", "oci://ghcr.io/acme/x@sha256:abc"} {
+		_, err := ParseReference(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestReferenceString(t *testing.T) {
+	ref := Reference{Registry: "ghcr.io", Repository: "acme/go-api", Tag: "1.2.0", Digest: "sha256:" + strings.Repeat("0", 64)}
+	parsed, err := ParseReference(ref.String())
+	require.NoError(t, err)
+	assert.Equal(t, ref, parsed)
+}
+
+func TestReferenceBaseURL(t *testing.T) {
+	assert.Equal(t, "https://ghcr.io", Reference{Registry: "ghcr.io"}.baseURL())
+	assert.Equal(t, "http://localhost:5000", Reference{Registry: "localhost:5000"}.baseURL())
+	assert.Equal(t, "http://127.0.0.1:5000", Reference{Registry: "127.0.0.1:5000"}.baseURL())
+	assert.Equal(t, "http://[::1]:5000", Reference{Registry: "[::1]:5000"}.baseURL())
+}
+
+func TestDockerCredentials(t *testing.T) {
+	config := []byte(`{"auths":{
+		"ghcr.io":{"auth":"YWxpY2U6czNjcmV0"},
+		"https://harbor.acme.dev/v2/":{"username":"bob","password":"pw"}
+	}}`)
+
+	username, password := dockerCredentials(config, "ghcr.io")
+	assert.Equal(t, "alice", username)
+	assert.Equal(t, "s3cret", password)
+
+	username, password = dockerCredentials(config, "harbor.acme.dev")
+	assert.Equal(t, "bob", username)
+	assert.Equal(t, "pw", password)
+
+	username, _ = dockerCredentials(config, "quay.io")
+	assert.Empty(t, username)
+}
@@ -0,0 +1,210 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// Answers remembers what was entered in variable forms and include
+// selections. When the prompt flow is replayed after an answer was edited on
+// the review screen, remembered forms are not shown again; only forms that
+// were not part of the previous pass are.
+type Answers struct {
+	groups   map[string]*answeredGroup
+	includes map[string]*answeredIncludes
+	order    []string // Keys of the groups and include selections asked in the current pass
+}
+
+type answeredGroup struct {
+	title     string
+	variables []template.Variable
+	values    map[string]any
+	context   *template.Context
+}
+
+type answeredIncludes struct {
+	includes []template.Include
+	selected []string
+}
+
+// NewAnswers creates an empty set of answers.
+func NewAnswers() *Answers {
+	return &Answers{
+		groups:   make(map[string]*answeredGroup),
+		includes: make(map[string]*answeredIncludes),
+	}
+}
+
+// BeginPass starts a new pass through the prompt flow.
+func (a *Answers) BeginPass() {
+	a.order = nil
+}
+
+func (a *Answers) lookupGroup(group VariableGroup) (map[string]any, bool) {
+	answered, ok := a.groups[group.Key]
+	if !ok {
+		return nil, false
+	}
+	for _, v := range group.Variables {
+		if _, ok := answered.values[v.Name]; !ok {
+			return nil, false
+		}
+	}
+	a.order = append(a.order, group.Key)
+	return answered.values, true
+}
+
+func (a *Answers) recordGroup(group VariableGroup, values map[string]any) {
+	variables := make([]template.Variable, len(group.Variables))
+	for i, v := range group.Variables {
+		variables[i] = v.Variable
+	}
+	a.groups[group.Key] = &answeredGroup{title: group.Title, variables: variables, values: values, context: group.Context}
+	a.order = append(a.order, group.Key)
+}
+
+// includesKey identifies an include selection by the offered includes.
+func includesKey(includes []template.Include) string {
+	names := make([]string, len(includes))
+	for i, inc := range includes {
+		names[i] = inc.Name
+	}
+	return "includes:" + strings.Join(names, ",")
+}
+
+func (a *Answers) lookupIncludes(includes []template.Include) ([]string, bool) {
+	key := includesKey(includes)
+	answered, ok := a.includes[key]
+	if !ok {
+		return nil, false
+	}
+	a.order = append(a.order, key)
+	return answered.selected, true
+}
+
+func (a *Answers) recordIncludes(includes []template.Include, selected []string) {
+	key := includesKey(includes)
+	a.includes[key] = &answeredIncludes{includes: includes, selected: selected}
+	a.order = append(a.order, key)
+}
+
+// reviewItem is an entry of the review screen.
+type reviewItem struct {
+	label string
+	edit  func(e *Engine) error
+}
+
+// reviewItems lists the answers of the current pass in the order they were
+// asked.
+func (a *Answers) reviewItems() []reviewItem {
+	var items []reviewItem
+
+	for _, key := range a.order {
+		if answered, ok := a.includes[key]; ok {
+			items = append(items, reviewItem{
+				label: fmt.Sprintf("Features: %s", formatAnswer(answered.selected)),
+				edit: func(e *Engine) error {
+					selected, err := e.selectIncludes(answered.includes, answered.selected)
+					if err != nil {
+						return err
+					}
+					answered.selected = selected
+					return nil
+				},
+			})
+			continue
+		}
+
+		answered := a.groups[key]
+		for _, v := range answered.variables {
+			// Variables whose condition no longer holds were not asked.
+			if !conditionHolds(Variable{Variable: v}, conditionContext(answered.context, answered.values)) {
+				continue
+			}
+			answer := formatAnswer(answered.values[v.Name])
+			if v.IsSecret() {
+				answer = secretPlaceholder
+			}
+			items = append(items, reviewItem{
+				label: fmt.Sprintf("%s %s", v.Prompt, answer),
+				edit: func(e *Engine) error {
+					value, err := e.promptVariable(answered.title, Variable{Variable: v, Value: answered.values[v.Name]})
+					if err != nil {
+						return err
+					}
+					answered.values[v.Name] = value
+					return nil
+				},
+			})
+		}
+	}
+
+	return items
+}
+
+// Review shows the answers of the current pass and lets the user pick one to
+// change. It returns true once the user confirms the answers, or false after
+// an answer was changed, in which case the prompt flow should be replayed.
+func (e *Engine) Review() (bool, error) {
+	if e.answers == nil {
+		return true, nil
+	}
+
+	items := e.answers.reviewItems()
+	if len(items) == 0 {
+		return true, nil
+	}
+
+	options := make([]huh.Option[int], 0, len(items)+1)
+	options = append(options, huh.NewOption(continueLabel(), -1))
+	for i, item := range items {
+		options = append(options, huh.NewOption("  "+item.label, i))
+	}
+
+	choice := -1
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Review your answers").
+				Description("Select an answer to change it").
+				Value(&choice).
+				Options(options...),
+		),
+	).WithTheme(e.theme).Run()
+	if err != nil {
+		return false, fmt.Errorf("review failed: %w", err)
+	}
+
+	if choice < 0 {
+		return true, nil
+	}
+
+	if err := items[choice].edit(e); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// secretPlaceholder stands in for the answer to a secret on the review screen.
+const secretPlaceholder = "********"
+
+// formatAnswer renders an answer for the review screen.
+func formatAnswer(value any) string {
+	switch v := value.(type) {
+	case []string:
+		if len(v) == 0 {
+			return "(none)"
+		}
+		return strings.Join(v, ", ")
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,108 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswers_ReplaysRememberedForms(t *testing.T) {
+	name := template.Variable{Name: "app_name", Prompt: "App name?", Type: template.VariableTypeString}
+	port := template.Variable{Name: "port", Prompt: "Port?", Type: template.VariableTypeInt}
+	group := VariableGroup{Key: "go-cli#0", Title: "Variables", Variables: []Variable{{Variable: name}}}
+	includes := []template.Include{{Name: "testing"}, {Name: "docker"}}
+
+	answers := NewAnswers()
+	answers.BeginPass()
+
+	_, ok := answers.lookupGroup(group)
+	require.False(t, ok)
+	answers.recordGroup(group, map[string]any{"app_name": "my-app"})
+	answers.recordIncludes(includes, []string{"docker"})
+
+	answers.BeginPass()
+
+	values, ok := answers.lookupGroup(group)
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"app_name": "my-app"}, values)
+
+	selected, ok := answers.lookupIncludes(includes)
+	require.True(t, ok)
+	assert.Equal(t, []string{"docker"}, selected)
+
+	_, ok = answers.lookupIncludes(includes[:1])
+	assert.False(t, ok, "a different set of includes is asked again")
+
+	group.Variables = append(group.Variables, Variable{Variable: port})
+	_, ok = answers.lookupGroup(group)
+	assert.False(t, ok, "a group with a new variable is asked again")
+}
+
+func TestAnswers_ReviewItemsFollowCurrentPass(t *testing.T) {
+	first := VariableGroup{Key: "a#0", Variables: []Variable{
+		{Variable: template.Variable{Name: "app_name", Prompt: "App name?"}},
+		{Variable: template.Variable{Name: "linters", Prompt: "Linters?"}},
+	}}
+	second := VariableGroup{Key: "b#0.0", Variables: []Variable{
+		{Variable: template.Variable{Name: "docker", Prompt: "Use Docker?"}},
+	}}
+
+	answers := NewAnswers()
+	answers.BeginPass()
+	answers.recordGroup(first, map[string]any{"app_name": "my-app", "linters": []string{}})
+	answers.recordIncludes([]template.Include{{Name: "b"}}, []string{"b"})
+	answers.recordGroup(second, map[string]any{"docker": true})
+
+	labels := func() []string {
+		var l []string
+		for _, item := range answers.reviewItems() {
+			l = append(l, item.label)
+		}
+		return l
+	}
+
+	assert.Equal(t, []string{"App name? my-app", "Linters? (none)", "Features: b", "Use Docker? true"}, labels())
+
+	// The second group is no longer part of the tree after the include was
+	// deselected, so it is not reviewed.
+	answers.BeginPass()
+	_, ok := answers.lookupGroup(first)
+	require.True(t, ok)
+	assert.Equal(t, []string{"App name? my-app", "Linters? (none)"}, labels())
+}
+
+func TestAnswers_ReviewItemsSkipUnmetConditions(t *testing.T) {
+	group := VariableGroup{Key: "a#0", Variables: []Variable{
+		{Variable: template.Variable{Name: "use_database", Prompt: "Use a database?"}},
+		{Variable: template.Variable{Name: "db_port", Prompt: "Port?", When: "{{ .use_database }}"}},
+	}}
+
+	answers := NewAnswers()
+	answers.BeginPass()
+	answers.recordGroup(group, map[string]any{"use_database": false, "db_port": 5432})
+
+	var labels []string
+	for _, item := range answers.reviewItems() {
+		labels = append(labels, item.label)
+	}
+	assert.Equal(t, []string{"Use a database? false"}, labels)
+}
+
+func TestAnswers_ReviewItemsMaskSecrets(t *testing.T) {
+	group := VariableGroup{Key: "a#0", Variables: []Variable{
+		{Variable: template.Variable{Name: "region", Prompt: "Region?", Type: template.VariableTypeString}},
+		{Variable: template.Variable{Name: "api_token", Prompt: "API token?", Type: template.VariableTypeSecret}},
+	}}
+
+	answers := NewAnswers()
+	answers.BeginPass()
+	answers.recordGroup(group, map[string]any{"region": "eu", "api_token": "t0ken"})
+
+	var labels []string
+	for _, item := range answers.reviewItems() {
+		labels = append(labels, item.label)
+	}
+	assert.Equal(t, []string{"Region? eu", "API token? ********"}, labels)
+}
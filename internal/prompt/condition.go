@@ -0,0 +1,62 @@
+package prompt
+
+import (
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// formGroups arranges the fields of a variable group into form groups. A
+// variable with a `when` condition gets a group of its own, which is skipped
+// unless the condition holds for the answers given before it is reached. The
+// variables between conditional ones share a group.
+func formGroups(group VariableGroup, variables []Variable, fields []huh.Field, values map[string]any) []*huh.Group {
+	var groups []*huh.Group
+	var pending []huh.Field
+
+	flush := func() {
+		if len(pending) > 0 {
+			groups = append(groups, huh.NewGroup(pending...).Title(group.Title))
+			pending = nil
+		}
+	}
+
+	for i, variable := range variables {
+		if variable.When == "" {
+			pending = append(pending, fields[i])
+			continue
+		}
+
+		flush()
+		groups = append(groups, huh.NewGroup(fields[i]).Title(group.Title).WithHideFunc(func() bool {
+			answers := make(map[string]any, len(variables))
+			for _, v := range variables {
+				answers[v.Name] = maskValue(v, extractValue(values[v.Name], v.Type))
+			}
+			return !conditionHolds(variable, conditionContext(group.Context, answers))
+		}))
+	}
+	flush()
+
+	return groups
+}
+
+// conditionContext returns the context `when` conditions are evaluated
+// against: the values collected before prompting, with the answers on top.
+func conditionContext(base *template.Context, answers map[string]any) *template.Context {
+	ctx := template.NewTemplateContext(make(map[string]any))
+	if base != nil {
+		ctx.Merge(base)
+	}
+	for name, value := range answers {
+		ctx.Set(name, value)
+	}
+	return ctx
+}
+
+// conditionHolds reports whether the variable should be prompted for. A
+// condition that cannot be evaluated shows the prompt rather than silently
+// keeping the default.
+func conditionHolds(variable Variable, ctx *template.Context) bool {
+	ok, err := template.NewRenderer().EvaluateCondition(variable.When, ctx)
+	return err != nil || ok
+}
@@ -0,0 +1,44 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionHolds(t *testing.T) {
+	port := Variable{Variable: template.Variable{Name: "db_port", When: "{{ .use_database }}"}}
+	base := template.NewTemplateContext(map[string]any{"use_database": true})
+
+	assert.True(t, conditionHolds(port, conditionContext(base, nil)))
+	assert.False(t, conditionHolds(port, conditionContext(base, map[string]any{"use_database": false})))
+	assert.False(t, conditionHolds(port, conditionContext(nil, nil)))
+	assert.True(t, conditionHolds(Variable{}, conditionContext(nil, nil)), "no condition")
+
+	broken := Variable{Variable: template.Variable{When: "{{ index .missing 1 }}"}}
+	assert.True(t, conditionHolds(broken, conditionContext(nil, nil)), "a failing condition shows the prompt")
+}
+
+func TestFormGroups(t *testing.T) {
+	variables := []Variable{
+		{Variable: template.Variable{Name: "name", Type: template.VariableTypeString}},
+		{Variable: template.Variable{Name: "use_database", Type: template.VariableTypeBool}},
+		{Variable: template.Variable{Name: "db_port", Type: template.VariableTypeInt, When: "{{ .use_database }}"}},
+		{Variable: template.Variable{Name: "license", Type: template.VariableTypeString}},
+	}
+
+	e := NewEngine()
+	fields := make([]huh.Field, len(variables))
+	values := make(map[string]any)
+	for i, v := range variables {
+		fields[i], values[v.Name] = e.createFormField(v)
+	}
+
+	groups := formGroups(VariableGroup{Title: "Variables"}, variables, fields, values)
+	assert.Len(t, groups, 3, "the conditional variable is asked in a group of its own")
+
+	unconditional := formGroups(VariableGroup{}, variables[:2], fields[:2], values)
+	assert.Len(t, unconditional, 1)
+}
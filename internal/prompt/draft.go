@@ -0,0 +1,195 @@
+package prompt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// Draft persists answers while they are entered, so that a session that was
+// interrupted by a crash or a closed terminal can be resumed. Answers are
+// keyed by variable group and variable name.
+type Draft struct {
+	path string
+
+	mu        sync.Mutex
+	Template  string                    `json:"template"`
+	UpdatedAt time.Time                 `json:"updated_at"`
+	Answers   map[string]map[string]any `json:"answers"`
+	last      []byte
+}
+
+// DefaultDraftPath returns the draft file for scaffolding templateName from
+// the current directory, inside the user cache directory.
+func DefaultDraftPath(templateName string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(cwd + "\x00" + templateName))
+	return filepath.Join(cacheDir, "blueprint", "drafts", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// LoadDraft reads the draft at path. A missing or unreadable draft yields an
+// empty one, since a draft is only ever a convenience.
+func LoadDraft(path, templateName string) *Draft {
+	d := &Draft{path: path, Template: templateName}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return d
+	}
+
+	var saved Draft
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Template != templateName {
+		return d
+	}
+
+	d.UpdatedAt = saved.UpdatedAt
+	d.Answers = saved.Answers
+	d.last = data
+	return d
+}
+
+// Len returns the number of saved answers.
+func (d *Draft) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := 0
+	for _, group := range d.Answers {
+		n += len(group)
+	}
+	return n
+}
+
+// Answer returns the saved answer for a variable converted to its type. An
+// answer saved under one of the variable's previous names is used when there
+// is none under its current name.
+func (d *Draft) Answer(key string, variable template.Variable, previousNames ...string) (any, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, name := range append([]string{variable.Name}, previousNames...) {
+		if raw, ok := d.Answers[key][name]; ok {
+			return restoreValue(raw, variable.Type)
+		}
+	}
+	return nil, false
+}
+
+// Save records the answers of a variable group and writes the draft when it
+// changed.
+func (d *Draft) Save(key string, answers map[string]any) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.Answers == nil {
+		d.Answers = make(map[string]map[string]any)
+	}
+	d.Answers[key] = answers
+
+	data, err := json.Marshal(struct {
+		Template string                    `json:"template"`
+		Answers  map[string]map[string]any `json:"answers"`
+	}{d.Template, d.Answers})
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, d.last) {
+		return nil
+	}
+
+	d.UpdatedAt = time.Now().UTC()
+	out, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	tmp := d.path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0o600); err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	if err := os.Rename(tmp, d.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	d.last = data
+	return nil
+}
+
+// Discard removes the draft file and forgets all answers.
+func (d *Draft) Discard() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.Answers = nil
+	d.last = nil
+	if err := os.Remove(d.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// restoreValue converts a value decoded from JSON to the Go type the form
+// field of the variable type expects.
+func restoreValue(raw any, typ template.VariableType) (any, bool) {
+	switch typ {
+	case template.VariableTypeString, template.VariableTypeSelect, template.VariableTypeInt:
+		s, ok := raw.(string)
+		return s, ok
+	case template.VariableTypeBool:
+		b, ok := raw.(bool)
+		return b, ok
+	case template.VariableTypeMultiSelect:
+		items, ok := raw.([]any)
+		if !ok {
+			return nil, false
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// draftValue returns the current value behind a form field pointer.
+func draftValue(valuePtr any) any {
+	switch v := valuePtr.(type) {
+	case *string:
+		return *v
+	case *bool:
+		return *v
+	case *[]string:
+		return append([]string{}, *v...)
+	default:
+		return nil
+	}
+}
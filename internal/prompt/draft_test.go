@@ -0,0 +1,77 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraft_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drafts", "draft.json")
+
+	draft := LoadDraft(path, "go-cli")
+	assert.Equal(t, 0, draft.Len())
+
+	require.NoError(t, draft.Save("go-cli#0", map[string]any{
+		"app_name": "my-app",
+		"port":     "80",
+		"docker":   true,
+		"linters":  []string{"vet", "lint"},
+	}))
+	assert.FileExists(t, path)
+
+	restored := LoadDraft(path, "go-cli")
+	assert.Equal(t, 4, restored.Len())
+	assert.False(t, restored.UpdatedAt.IsZero())
+
+	tests := []struct {
+		variable template.Variable
+		want     any
+	}{
+		{template.Variable{Name: "app_name", Type: template.VariableTypeString}, "my-app"},
+		{template.Variable{Name: "port", Type: template.VariableTypeInt}, "80"},
+		{template.Variable{Name: "docker", Type: template.VariableTypeBool}, true},
+		{template.Variable{Name: "linters", Type: template.VariableTypeMultiSelect}, []string{"vet", "lint"}},
+	}
+	for _, tt := range tests {
+		value, ok := restored.Answer("go-cli#0", tt.variable)
+		require.True(t, ok, tt.variable.Name)
+		assert.Equal(t, tt.want, value, tt.variable.Name)
+	}
+
+	_, ok := restored.Answer("go-cli#0", template.Variable{Name: "docker", Type: template.VariableTypeString})
+	assert.False(t, ok, "answers of a different type are ignored")
+
+	_, ok = restored.Answer("testing#0.0", tests[0].variable)
+	assert.False(t, ok)
+
+	value, ok := restored.Answer("go-cli#0", template.Variable{Name: "project_name", Type: template.VariableTypeString}, "app_name")
+	require.True(t, ok, "answers saved under a previous name are restored")
+	assert.Equal(t, "my-app", value)
+}
+
+func TestDraft_IgnoresOtherTemplatesAndCorruptFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "draft.json")
+
+	require.NoError(t, LoadDraft(path, "go-cli").Save("go-cli#0", map[string]any{"app_name": "x"}))
+	assert.Equal(t, 0, LoadDraft(path, "go-api").Len())
+
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	assert.Equal(t, 0, LoadDraft(path, "go-cli").Len())
+}
+
+func TestDraft_Discard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "draft.json")
+
+	draft := LoadDraft(path, "go-cli")
+	require.NoError(t, draft.Save("go-cli#0", map[string]any{"app_name": "x"}))
+	require.NoError(t, draft.Discard())
+
+	assert.NoFileExists(t, path)
+	assert.Equal(t, 0, draft.Len())
+	require.NoError(t, draft.Discard(), "discarding twice is not an error")
+}
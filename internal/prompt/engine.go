@@ -2,44 +2,108 @@ package prompt
 
 import (
 	"fmt"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 )
 
 // Engine handles interactive prompts for collecting template variables
 type Engine struct {
-	theme *huh.Theme
+	theme   *huh.Theme
+	draft   *Draft
+	answers *Answers
+	preview PreviewInclude
 }
 
 // NewEngine creates a new prompt engine
 func NewEngine() *Engine {
 	return &Engine{
-		theme: huh.ThemeCharm(),
+		theme: theme(),
 	}
 }
 
+// WithDraft returns a copy of the engine that prefills variable forms from
+// the draft and saves answers to it as they are entered.
+func (e *Engine) WithDraft(d *Draft) *Engine {
+	drafted := *e
+	drafted.draft = d
+	return &drafted
+}
+
+// WithAnswers returns a copy of the engine that records the answers of every
+// form in a and skips forms a already holds answers for.
+func (e *Engine) WithAnswers(a *Answers) *Engine {
+	answered := *e
+	answered.answers = a
+	return &answered
+}
+
 // PromptVariables prompts for all variables as a single form
-// This provides a better UX than individual prompts
+// This provides a better UX than individual prompts. A variable whose `when`
+// condition does not hold for the earlier answers is skipped and keeps its
+// current value.
 func (e *Engine) PromptVariables(group VariableGroup) (*template.Context, error) {
 	if len(group.Variables) == 0 {
 		return template.NewTemplateContext(make(map[string]any)), nil
 	}
 
+	if e.answers != nil {
+		if values, ok := e.answers.lookupGroup(group); ok {
+			return template.NewTemplateContext(copyValues(values)), nil
+		}
+	}
+
 	fields := make([]huh.Field, 0, len(group.Variables))
+	prompted := make([]Variable, 0, len(group.Variables))
 	values := make(map[string]any)
+	masks := make(map[string]template.InputMask)
 
 	for _, variable := range group.Variables {
+		if e.draft != nil {
+			if value, ok := e.draft.Answer(group.Key, variable.Variable, variable.PreviousNames...); ok {
+				variable.Value = value
+			}
+		}
+
 		field, valuePtr := e.createFormField(variable)
 		if field != nil {
 			fields = append(fields, field)
+			prompted = append(prompted, variable)
 			values[variable.Name] = valuePtr
 		}
+		if variable.Mask != "" {
+			masks[variable.Name] = variable.Mask
+		}
 	}
 
-	form := huh.NewForm(
-		huh.NewGroup(fields...).Title(group.Title),
-	).WithTheme(e.theme)
+	form := huh.NewForm(formGroups(group, prompted, fields, values)...).WithTheme(e.theme)
+
+	var save func()
+	if e.draft != nil {
+		save = func() {
+			answers := make(map[string]any, len(values))
+			for _, variable := range prompted {
+				// Secrets are never written to disk.
+				if variable.IsSecret() {
+					continue
+				}
+				answers[variable.Name] = draftValue(values[variable.Name])
+			}
+			// Saving is best effort; a failure must not interrupt the form.
+			_ = e.draft.Save(group.Key, answers)
+		}
+		defer save()
+	}
+
+	mask := maskFilter(masks)
+	form = form.WithProgramOptions(tea.WithFilter(func(m tea.Model, msg tea.Msg) tea.Msg {
+		if save != nil {
+			save()
+		}
+		return mask(m, msg)
+	}))
 
 	if err := form.Run(); err != nil {
 		return nil, fmt.Errorf("form prompt failed: %w", err)
@@ -49,41 +113,73 @@ func (e *Engine) PromptVariables(group VariableGroup) (*template.Context, error)
 	ctx := template.NewTemplateContext(make(map[string]any))
 	for _, variable := range group.Variables {
 		valuePtr := values[variable.Name]
-		ctx.Set(variable.Name, extractValue(valuePtr, variable.Type))
+		ctx.Set(variable.Name, maskValue(variable, extractValue(valuePtr, variable.Type)))
+	}
+
+	if e.answers != nil {
+		e.answers.recordGroup(group, copyValues(ctx.Variables))
 	}
 
 	return ctx, nil
 }
 
+// promptVariable asks for a single variable again, e.g. after it was picked
+// on the review screen.
+func (e *Engine) promptVariable(title string, variable Variable) (any, error) {
+	field, valuePtr := e.createFormField(variable)
+	if field == nil {
+		return variable.Value, nil
+	}
+
+	form := huh.NewForm(huh.NewGroup(field).Title(title)).WithTheme(e.theme)
+	if variable.Mask != "" {
+		form = form.WithProgramOptions(tea.WithFilter(maskFilter(map[string]template.InputMask{variable.Name: variable.Mask})))
+	}
+	if err := form.Run(); err != nil {
+		return nil, fmt.Errorf("form prompt failed: %w", err)
+	}
+
+	return maskValue(variable, extractValue(valuePtr, variable.Type)), nil
+}
+
+func copyValues(values map[string]any) map[string]any {
+	copied := make(map[string]any, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return copied
+}
+
 // PromptIncludes prompts the user to select which includes to enable
 func (e *Engine) PromptIncludes(includes []template.Include) ([]template.Include, error) {
 	if len(includes) == 0 {
 		return nil, nil
 	}
 
-	options := make([]huh.Option[string], len(includes))
-	selected := make([]string, 0)
+	var selected []string
+	var answered bool
+	if e.answers != nil {
+		selected, answered = e.answers.lookupIncludes(includes)
+	}
 
-	// Pre-select includes that are enabled by default
-	for i, inc := range includes {
-		options[i] = huh.NewOption(inc.Name, inc.Name)
-		if inc.EnabledByDefault {
-			selected = append(selected, inc.Name)
+	if !answered {
+		// Pre-select includes that are enabled by default
+		defaults := make([]string, 0)
+		for _, inc := range includes {
+			if inc.EnabledByDefault {
+				defaults = append(defaults, inc.Name)
+			}
 		}
-	}
 
-	err := huh.NewForm(
-		huh.NewGroup(
-			huh.NewMultiSelect[string]().
-				Title("Select features to include").
-				Description("Use space to select/deselect, enter to confirm").
-				Options(options...).
-				Value(&selected),
-		),
-	).WithTheme(e.theme).Run()
+		var err error
+		selected, err = e.selectIncludes(includes, defaults)
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf("include selection failed: %w", err)
+		if e.answers != nil {
+			e.answers.recordIncludes(includes, selected)
+		}
 	}
 
 	selectedNames := make(map[string]bool, len(selected))
@@ -101,13 +197,245 @@ func (e *Engine) PromptIncludes(includes []template.Include) ([]template.Include
 	return enabledIncludes, nil
 }
 
+// selectIncludes shows the include picker with the given names preselected.
+func (e *Engine) selectIncludes(includes []template.Include, preselected []string) ([]string, error) {
+	options := make([]huh.Option[string], len(includes))
+	for i, inc := range includes {
+		options[i] = huh.NewOption(inc.Name, inc.Name)
+	}
+
+	selected := append(make([]string, 0, len(preselected)), preselected...)
+	field := huh.NewMultiSelect[string]().
+		Title("Select features to include").
+		Description("Use space to select/deselect, enter to confirm").
+		Options(options...).
+		Value(&selected)
+
+	form := huh.NewForm(huh.NewGroup(field)).WithTheme(e.theme)
+
+	if e.preview != nil {
+		state := &includePreview{
+			field:    field,
+			includes: make(map[string]template.Include, len(includes)),
+			load:     e.preview,
+		}
+		for _, inc := range includes {
+			state.includes[inc.Name] = inc
+		}
+		field.DescriptionFunc(state.description, state)
+		form = form.WithHeight(len(includes) + previewHeight).WithProgramOptions(tea.WithFilter(func(_ tea.Model, msg tea.Msg) tea.Msg {
+			if key, ok := msg.(tea.KeyMsg); ok && key.String() == previewKey && !field.GetFiltering() {
+				state.open = !state.open
+				return previewToggledMsg{}
+			}
+			return msg
+		}))
+	}
+
+	err := form.Run()
+	if err != nil {
+		return nil, fmt.Errorf("include selection failed: %w", err)
+	}
+
+	return selected, nil
+}
+
+// ConfirmPostInit asks the user whether the given post-init commands should be run
+func (e *Engine) ConfirmPostInit(commands []string) (bool, error) {
+	if len(commands) == 0 {
+		return false, nil
+	}
+
+	confirmed := true
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Run post-init commands?").
+				Description(strings.Join(commands, "\n")).
+				Value(&confirmed),
+		),
+	).WithTheme(e.theme).Run()
+
+	if err != nil {
+		return false, fmt.Errorf("post-init confirmation failed: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// ConfirmOutsideOutput asks the user to confirm writing files outside the
+// output directory. The answer defaults to no.
+func (e *Engine) ConfirmOutsideOutput(paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	var confirmed bool
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Write files outside the output directory?").
+				Description(strings.Join(paths, "\n")).
+				Value(&confirmed),
+		),
+	).WithTheme(e.theme).Run()
+
+	if err != nil {
+		return false, fmt.Errorf("output confirmation failed: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// ConfirmRemoveFiles asks the user to confirm removing files generated by
+// includes that are no longer enabled. The answer defaults to no.
+func (e *Engine) ConfirmRemoveFiles(paths []string) (bool, error) {
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	var confirmed bool
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Remove files of includes that are no longer enabled?").
+				Description(strings.Join(paths, "\n")).
+				Value(&confirmed),
+		),
+	).WithTheme(e.theme).Run()
+
+	if err != nil {
+		return false, fmt.Errorf("remove confirmation failed: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// Confirm asks the user a yes/no question. The answer defaults to no.
+func (e *Engine) Confirm(title string) (bool, error) {
+	var confirmed bool
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(title).
+				Value(&confirmed),
+		),
+	).WithTheme(e.theme).Run()
+
+	if err != nil {
+		return false, fmt.Errorf("confirmation failed: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// PromptEnv prompts for values of environment variables required by post-init commands
+func (e *Engine) PromptEnv(env []template.EnvVar) (map[string]string, error) {
+	if len(env) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]huh.Field, 0, len(env))
+	values := make(map[string]*string, len(env))
+
+	for _, v := range env {
+		value := ""
+		input := huh.NewInput().
+			Title(v.Prompt).
+			Description(v.Name).
+			Value(&value)
+		if v.Required {
+			input = input.Validate(ValidateNonEmptyString)
+		}
+		fields = append(fields, input)
+		values[v.Name] = &value
+	}
+
+	err := huh.NewForm(
+		huh.NewGroup(fields...).Title("Environment for post-init commands"),
+	).WithTheme(e.theme).Run()
+
+	if err != nil {
+		return nil, fmt.Errorf("environment prompt failed: %w", err)
+	}
+
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		result[name] = *value
+	}
+
+	return result, nil
+}
+
+// PickTemplate prompts the user to choose a template from the given options
+// and returns the selected template name. Options are expected in resolution
+// order; see uniqueTemplateOptions.
+func (e *Engine) PickTemplate(options []TemplateOption) (string, error) {
+	options = uniqueTemplateOptions(options)
+	if len(options) == 0 {
+		return "", fmt.Errorf("no templates available")
+	}
+
+	huhOptions := make([]huh.Option[string], len(options))
+	for i, opt := range options {
+		label := fmt.Sprintf("[%s] %s", opt.Source, opt.Name)
+		if opt.Description != "" {
+			label = label + descriptionSeparator() + opt.Description
+		}
+		huhOptions[i] = huh.NewOption(label, opt.Name)
+	}
+
+	var selected string
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Select a template").
+				Options(huhOptions...).
+				Value(&selected),
+		),
+	).WithTheme(e.theme).Run()
+
+	if err != nil {
+		return "", fmt.Errorf("template selection failed: %w", err)
+	}
+
+	return selected, nil
+}
+
+// uniqueTemplateOptions keeps the first option of each template name. A name
+// resolves to the first source that has it, so a template shadowed by one of
+// the same name in an earlier source cannot be picked by name.
+func uniqueTemplateOptions(options []TemplateOption) []TemplateOption {
+	seen := make(map[string]bool, len(options))
+	unique := make([]TemplateOption, 0, len(options))
+	for _, opt := range options {
+		if seen[opt.Name] {
+			continue
+		}
+		seen[opt.Name] = true
+		unique = append(unique, opt)
+	}
+	return unique
+}
+
 // createFormField creates a huh form field for a variable
 func (e *Engine) createFormField(variable Variable) (huh.Field, any) {
 	switch variable.Type {
 	case template.VariableTypeString:
+		value := applyMask(variable.Mask, CastValue[string](variable.Value))
+		return huh.NewInput().
+			Key(variable.Name).
+			Title(variable.Prompt).
+			Value(&value).
+			Suggestions(suggestions(variable)).
+			Validate(ValidateNonEmptyString), &value
+
+	case template.VariableTypeSecret:
 		value := CastValue[string](variable.Value)
 		return huh.NewInput().
+			Key(variable.Name).
 			Title(variable.Prompt).
+			EchoMode(huh.EchoModePassword).
 			Value(&value).
 			Validate(ValidateNonEmptyString), &value
 
@@ -129,10 +457,7 @@ func (e *Engine) createFormField(variable Variable) (huh.Field, any) {
 
 	case template.VariableTypeSelect:
 		value := CastValue[string](variable.Value)
-		options := make([]huh.Option[string], len(variable.Options))
-		for i, opt := range variable.Options {
-			options[i] = huh.NewOption(opt, opt)
-		}
+		options := selectOptions(variable)
 		return huh.NewSelect[string]().
 			Title(variable.Prompt).
 			Options(options...).
@@ -140,10 +465,7 @@ func (e *Engine) createFormField(variable Variable) (huh.Field, any) {
 
 	case template.VariableTypeMultiSelect:
 		value := CastValue[[]string](variable.Value)
-		options := make([]huh.Option[string], len(variable.Options))
-		for i, opt := range variable.Options {
-			options[i] = huh.NewOption(opt, opt)
-		}
+		options := selectOptions(variable)
 		return huh.NewMultiSelect[string]().
 			Title(variable.Prompt).
 			Options(options...).
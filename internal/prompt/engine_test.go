@@ -0,0 +1,20 @@
+package prompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniqueTemplateOptions(t *testing.T) {
+	options := []TemplateOption{
+		{Name: "go-cli", Description: "My CLI", Source: "USER"},
+		{Name: "go-api", Description: "An API", Source: "BUILTIN"},
+		{Name: "go-cli", Description: "A CLI", Source: "BUILTIN"},
+	}
+
+	assert.Equal(t, []TemplateOption{
+		{Name: "go-cli", Description: "My CLI", Source: "USER"},
+		{Name: "go-api", Description: "An API", Source: "BUILTIN"},
+	}, uniqueTemplateOptions(options))
+}
@@ -0,0 +1,202 @@
+package prompt
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// commonLicenses are the SPDX identifiers offered by the license provider.
+var commonLicenses = []string{
+	"MIT", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "GPL-2.0-only", "GPL-3.0-only",
+	"LGPL-3.0-only", "AGPL-3.0-only", "MPL-2.0", "ISC", "Unlicense",
+}
+
+// suggestions returns the completions offered for a variable, computing
+// them when they come from a provider.
+func suggestions(variable Variable) []string {
+	s := variable.Suggestions
+	if s == nil {
+		return nil
+	}
+
+	switch s.Provider {
+	case "":
+		return s.Values
+	case template.SuggestDirectory:
+		if wd, err := os.Getwd(); err == nil {
+			return []string{filepath.Base(wd)}
+		}
+	case template.SuggestGitRemote:
+		return gitRemoteModules()
+	case template.SuggestGitUserName:
+		return nonEmpty(gitOutput("config", "--get", "user.name"))
+	case template.SuggestGitUserEmail:
+		return nonEmpty(gitOutput("config", "--get", "user.email"))
+	case template.SuggestLicense:
+		return commonLicenses
+	}
+	return nil
+}
+
+// gitRemoteModules derives module paths, such as github.com/acme/app, from
+// the URLs of the git remotes of the current directory.
+func gitRemoteModules() []string {
+	var modules []string
+	for _, remote := range strings.Fields(gitOutput("remote")) {
+		module := remoteModule(gitOutput("remote", "get-url", remote))
+		if module != "" && !slices.Contains(modules, module) {
+			modules = append(modules, module)
+		}
+	}
+	return modules
+}
+
+// remoteModule converts a git remote URL to a module path. Both URLs and the
+// scp-like user@host:path syntax are understood.
+func remoteModule(remote string) string {
+	var host, p string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, p = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, "@"); ok && !strings.Contains(at, "/") {
+		host, p, _ = strings.Cut(rest, ":")
+	}
+
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	if host == "" || p == "" {
+		return ""
+	}
+	return host + "/" + p
+}
+
+// gitOutput runs git in the current directory and returns its trimmed
+// output, or an empty string when git fails or is not installed.
+func gitOutput(args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func nonEmpty(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// selectOptions returns the options of a select or multiselect variable,
+// shown by their label and description but selecting their value.
+func selectOptions(variable Variable) []huh.Option[string] {
+	options := make([]huh.Option[string], len(variable.Options))
+	for i, opt := range variable.Options {
+		key := opt.Title()
+		if opt.Description != "" {
+			key += " - " + opt.Description
+		}
+		options[i] = huh.NewOption(key, opt.Value)
+	}
+	return options
+}
+
+// maskRune applies an input mask to a typed rune. It reports false for a rune
+// the mask does not allow.
+func maskRune(mask template.InputMask, r rune) (rune, bool) {
+	switch mask {
+	case template.MaskLowercase:
+		return unicode.ToLower(r), true
+	case template.MaskUppercase:
+		return unicode.ToUpper(r), true
+	case template.MaskSlug, template.MaskSnake:
+		sep, other := '-', '_'
+		if mask == template.MaskSnake {
+			sep, other = '_', '-'
+		}
+		r = unicode.ToLower(r)
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == sep:
+			return r, true
+		case r == ' ' || r == other:
+			return sep, true
+		default:
+			return 0, false
+		}
+	default:
+		return r, true
+	}
+}
+
+// applyMask applies an input mask to a whole value, such as a default or a
+// pasted value typed without the terminal form.
+func applyMask(mask template.InputMask, value string) string {
+	if mask == "" {
+		return value
+	}
+	var b strings.Builder
+	for _, r := range value {
+		if masked, ok := maskRune(mask, r); ok {
+			b.WriteRune(masked)
+		}
+	}
+	return b.String()
+}
+
+// maskValue applies the mask of a string variable to its prompted value.
+func maskValue(variable Variable, value any) any {
+	if s, ok := value.(string); ok && variable.Mask != "" {
+		return applyMask(variable.Mask, s)
+	}
+	return value
+}
+
+// maskFilter returns a program filter that applies the masks of the form's
+// fields, by field key, to the keys typed into the focused field.
+func maskFilter(masks map[string]template.InputMask) func(tea.Model, tea.Msg) tea.Msg {
+	return func(m tea.Model, msg tea.Msg) tea.Msg {
+		key, ok := msg.(tea.KeyMsg)
+		if !ok || len(masks) == 0 {
+			return msg
+		}
+		form, ok := m.(*huh.Form)
+		if !ok {
+			return msg
+		}
+		field := form.GetFocusedField()
+		if field == nil {
+			return msg
+		}
+		mask := masks[field.GetKey()]
+		if mask == "" {
+			return msg
+		}
+
+		switch key.Type {
+		case tea.KeySpace:
+			key.Type, key.Runes = tea.KeyRunes, []rune{' '}
+		case tea.KeyRunes:
+		default:
+			return msg
+		}
+
+		runes := make([]rune, 0, len(key.Runes))
+		for _, r := range key.Runes {
+			if masked, ok := maskRune(mask, r); ok {
+				runes = append(runes, masked)
+			}
+		}
+		if len(runes) == 0 {
+			return nil
+		}
+		key.Runes = runes
+		return key
+	}
+}
@@ -0,0 +1,84 @@
+package prompt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMask(t *testing.T) {
+	tests := []struct {
+		mask  template.InputMask
+		value string
+		want  string
+	}{
+		{"", "My App", "My App"},
+		{template.MaskLowercase, "My App", "my app"},
+		{template.MaskUppercase, "my_app", "MY_APP"},
+		{template.MaskSlug, "My App_v2!", "my-app-v2"},
+		{template.MaskSnake, "My-App v2", "my_app_v2"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, applyMask(tt.mask, tt.value), "%s %q", tt.mask, tt.value)
+	}
+}
+
+func TestMaskFilter(t *testing.T) {
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewInput().Key("name"),
+	))
+	filter := maskFilter(map[string]template.InputMask{"name": template.MaskSlug})
+
+	msg := filter(form, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("My App")})
+	assert.Equal(t, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("my-app")}, msg)
+
+	msg = filter(form, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Equal(t, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")}, msg)
+
+	assert.Nil(t, filter(form, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")}))
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	assert.Equal(t, enter, filter(form, enter))
+}
+
+func TestRemoteModule(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/acme/app.git":      "github.com/acme/app",
+		"git@github.com:acme/app.git":          "github.com/acme/app",
+		"ssh://git@gitlab.example.com/team/x/": "gitlab.example.com/team/x",
+		"/srv/git/app.git":                     "",
+	}
+
+	for remote, want := range tests {
+		assert.Equal(t, want, remoteModule(remote), remote)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	static := Variable{Variable: template.Variable{Suggestions: &template.Suggestions{Values: []string{"a", "b"}}}}
+	assert.Equal(t, []string{"a", "b"}, suggestions(static))
+
+	license := Variable{Variable: template.Variable{Suggestions: &template.Suggestions{Provider: template.SuggestLicense}}}
+	assert.Contains(t, suggestions(license), "MIT")
+
+	assert.Nil(t, suggestions(Variable{}))
+}
+
+func TestSelectOptions(t *testing.T) {
+	variable := Variable{Variable: template.Variable{Options: []template.Option{
+		{Value: "none"},
+		{Value: "postgres", Label: "PostgreSQL", Description: "recommended"},
+	}}}
+
+	options := selectOptions(variable)
+	require.Len(t, options, 2)
+	assert.Equal(t, "none", options[0].Key)
+	assert.Equal(t, "none", options[0].Value)
+	assert.Equal(t, "PostgreSQL - recommended", options[1].Key)
+	assert.Equal(t, "postgres", options[1].Value)
+}
@@ -0,0 +1,119 @@
+package prompt
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// previewKey toggles the preview of the highlighted include.
+const previewKey = "?"
+
+// maxPreviewItems limits how many entries of a list the preview shows.
+const maxPreviewItems = 8
+
+// previewHeight is the number of lines reserved for the preview, since a
+// form does not grow after it was first drawn.
+const previewHeight = 10
+
+// IncludePreview describes what enabling an include adds to the project.
+type IncludePreview struct {
+	Description  string
+	Variables    []string // Names of the variables it asks for
+	Files        []string // Destination paths of the files it generates
+	Dependencies []string
+	Includes     []string // Names of its own includes
+}
+
+// PreviewInclude loads the preview of an include.
+type PreviewInclude func(inc template.Include) (*IncludePreview, error)
+
+// WithIncludePreview returns a copy of the engine that lets the user preview
+// the highlighted include in the include picker.
+func (e *Engine) WithIncludePreview(fn PreviewInclude) *Engine {
+	previewed := *e
+	previewed.preview = fn
+	return &previewed
+}
+
+// includePreview is the state of the preview in the include picker. It is
+// used as the binding of the picker's description, so that the description
+// is recomputed whenever the preview is toggled or the cursor moves.
+type includePreview struct {
+	field    *huh.MultiSelect[string]
+	includes map[string]template.Include
+	load     PreviewInclude
+	open     bool
+}
+
+// previewToggledMsg redraws the picker after the preview was toggled.
+type previewToggledMsg struct{}
+
+// Hash implements hashstructure.Hashable.
+func (p *includePreview) Hash() (uint64, error) {
+	h := fnv.New64a()
+	if p.open {
+		name, _ := p.field.Hovered()
+		h.Write([]byte("open\x00" + name))
+	}
+	return h.Sum64(), nil
+}
+
+// description returns the description of the picker: usage help, or the
+// preview of the highlighted include while the preview is open.
+func (p *includePreview) description() string {
+	help := fmt.Sprintf("Use space to select/deselect, enter to confirm, %s to preview", previewKey)
+	if !p.open {
+		return help
+	}
+
+	name, ok := p.field.Hovered()
+	if !ok {
+		return help
+	}
+
+	preview, err := p.load(p.includes[name])
+	if err != nil {
+		return fmt.Sprintf("%s: preview unavailable: %v", name, err)
+	}
+	return formatIncludePreview(name, preview)
+}
+
+// formatIncludePreview renders the preview of an include as plain text.
+func formatIncludePreview(name string, preview *IncludePreview) string {
+	var b strings.Builder
+
+	b.WriteString(name)
+	if preview.Description != "" {
+		b.WriteString(" - " + preview.Description)
+	}
+
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Variables", preview.Variables},
+		{"Files", preview.Files},
+		{"Dependencies", preview.Dependencies},
+		{"Includes", preview.Includes},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n%s: %s", section.title, previewList(section.items))
+	}
+
+	fmt.Fprintf(&b, "\n(press %s to close)", previewKey)
+	return b.String()
+}
+
+func previewList(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	if len(items) <= maxPreviewItems {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:maxPreviewItems], ", "), len(items)-maxPreviewItems)
+}
@@ -0,0 +1,53 @@
+package prompt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatIncludePreview(t *testing.T) {
+	out := formatIncludePreview("docker", &IncludePreview{
+		Description: "Dockerfile and compose setup",
+		Variables:   []string{"base_image"},
+		Files:       []string{"Dockerfile", "a", "b", "c", "d", "e", "f", "g", "h", "i"},
+	})
+
+	assert.Equal(t, "docker - Dockerfile and compose setup\n"+
+		"Variables: base_image\n"+
+		"Files: Dockerfile, a, b, c, d, e, f, g and 2 more\n"+
+		"Dependencies: none\n"+
+		"Includes: none\n"+
+		"(press ? to close)", out)
+}
+
+func TestIncludePreview_Description(t *testing.T) {
+	field := huh.NewMultiSelect[string]().Options(huh.NewOptions("docker", "testing")...)
+	state := &includePreview{
+		field:    field,
+		includes: map[string]template.Include{"docker": {Name: "docker"}, "testing": {Name: "testing"}},
+		load: func(inc template.Include) (*IncludePreview, error) {
+			if inc.Name == "testing" {
+				return nil, errors.New("not found")
+			}
+			return &IncludePreview{Description: "Container setup"}, nil
+		},
+	}
+
+	closed, err := state.Hash()
+	require.NoError(t, err)
+	assert.Contains(t, state.description(), "? to preview")
+
+	state.open = true
+	opened, err := state.Hash()
+	require.NoError(t, err)
+	assert.NotEqual(t, closed, opened, "toggling the preview must change the binding")
+	assert.Contains(t, state.description(), "docker - Container setup")
+
+	field.Options(huh.NewOptions("testing")...)
+	assert.Equal(t, "testing: preview unavailable: not found", state.description())
+}
@@ -0,0 +1,67 @@
+package prompt
+
+import (
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// plain is set by SetPlain; engines created afterwards use plainTheme.
+var plain bool
+
+// SetPlain makes prompts render without colors or Unicode symbols, for
+// terminals and logs that only handle plain ASCII.
+func SetPlain() {
+	plain = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// theme returns the theme new engines use.
+func theme() *huh.Theme {
+	if plain {
+		return plainTheme()
+	}
+	return huh.ThemeCharm()
+}
+
+// plainTheme is huh's base theme with every Unicode indicator and border
+// replaced by an ASCII one. Focused buttons are bracketed because they
+// cannot be told apart by color.
+func plainTheme() *huh.Theme {
+	t := huh.ThemeBase()
+
+	button := lipgloss.NewStyle().MarginRight(1)
+
+	t.Focused.Base = lipgloss.NewStyle().PaddingLeft(1).BorderStyle(lipgloss.Border{Left: "|"}).BorderLeft(true)
+	t.Focused.Card = t.Focused.Base
+	t.Focused.NextIndicator = lipgloss.NewStyle().MarginLeft(1).SetString("->")
+	t.Focused.PrevIndicator = lipgloss.NewStyle().MarginRight(1).SetString("<-")
+	t.Focused.SelectedPrefix = lipgloss.NewStyle().SetString("[x] ")
+	t.Focused.FocusedButton = button.Transform(func(s string) string { return "[" + s + "]" })
+	t.Focused.BlurredButton = button.Transform(func(s string) string { return " " + s + " " })
+
+	t.Blurred = t.Focused
+	t.Blurred.Base = t.Blurred.Base.BorderStyle(lipgloss.Border{Left: " "})
+	t.Blurred.Card = t.Blurred.Base
+	t.Blurred.MultiSelectSelector = lipgloss.NewStyle().SetString("  ")
+	t.Blurred.NextIndicator = lipgloss.NewStyle()
+	t.Blurred.PrevIndicator = lipgloss.NewStyle()
+
+	return t
+}
+
+// continueLabel is the first option of the review prompt.
+func continueLabel() string {
+	if plain {
+		return "Continue with these answers"
+	}
+	return "✓ Continue with these answers"
+}
+
+// descriptionSeparator separates a template name from its description.
+func descriptionSeparator() string {
+	if plain {
+		return " - "
+	}
+	return " — "
+}
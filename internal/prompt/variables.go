@@ -9,13 +9,23 @@ import (
 // Variable extends a template variable with a collected value.
 type Variable struct {
 	template.Variable
-	Value any
+	Value         any
+	PreviousNames []string // Deprecated names the variable was renamed from
 }
 
 // VariableGroup is a set of variables prompted together.
 type VariableGroup struct {
+	Key       string // Identifies the group in a Draft
 	Title     string
 	Variables []Variable
+	Context   *template.Context // Values collected before prompting; `when` conditions can refer to them
+}
+
+// TemplateOption is a template offered by the template picker.
+type TemplateOption struct {
+	Name        string
+	Description string
+	Source      string
 }
 
 // CastValue safely casts a validated variable value to the requested type.
@@ -30,7 +40,7 @@ func CastValue[T any](value any) T {
 
 func extractValue(valuePtr any, varType template.VariableType) any {
 	switch varType {
-	case template.VariableTypeString, template.VariableTypeSelect:
+	case template.VariableTypeString, template.VariableTypeSecret, template.VariableTypeSelect:
 		return *CastValue[*string](valuePtr)
 	case template.VariableTypeInt:
 		value := CastValue[*string](valuePtr)
@@ -0,0 +1,15 @@
+package publish
+
+import "fmt"
+
+// VersionExistsError is returned when the version of a template is already
+// published to the registry.
+type VersionExistsError struct {
+	Name     string
+	Version  string
+	Registry string
+}
+
+func (e *VersionExistsError) Error() string {
+	return fmt.Sprintf("template %s %s is already published to %s", e.Name, e.Version, e.Registry)
+}
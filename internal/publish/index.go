@@ -0,0 +1,92 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IndexFileName is the name of the index at the root of a registry.
+const IndexFileName = "index.yaml"
+
+// SchemaVersion is the current registry index schema version.
+const SchemaVersion = 1
+
+// Index lists the templates published to a registry, one entry per version.
+type Index struct {
+	SchemaVersion int     `yaml:"schema_version"`
+	Templates     []Entry `yaml:"templates"`
+}
+
+// Entry records a published version of a template.
+type Entry struct {
+	Name        string    `yaml:"name"`
+	Type        string    `yaml:"type"`
+	Version     string    `yaml:"version"`
+	Description string    `yaml:"description,omitempty"`
+	Tags        []string  `yaml:"tags,omitempty"`
+	Archive     string    `yaml:"archive"` // Path of the archive, relative to the registry
+	SHA256      string    `yaml:"sha256"`  // Checksum of the archive
+	PublishedAt time.Time `yaml:"published_at"`
+}
+
+// LoadIndex reads the index of the registry rooted at root. A missing index
+// is empty.
+func LoadIndex(root string) (*Index, error) {
+	idx := &Index{SchemaVersion: SchemaVersion}
+
+	data, err := os.ReadFile(filepath.Join(root, IndexFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to read registry index: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("failed to parse registry index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// Save writes the index into the registry rooted at root. Entries are sorted
+// by name; the versions of a template keep the order they were published in.
+func (idx *Index) Save(root string) error {
+	sort.SliceStable(idx.Templates, func(i, j int) bool { return idx.Templates[i].Name < idx.Templates[j].Name })
+
+	data, err := yaml.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to encode registry index: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, IndexFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write registry index: %w", err)
+	}
+
+	return nil
+}
+
+// Entry returns the entry of a version of a template.
+func (idx *Index) Entry(name, version string) (*Entry, bool) {
+	for i := range idx.Templates {
+		if idx.Templates[i].Name == name && idx.Templates[i].Version == version {
+			return &idx.Templates[i], true
+		}
+	}
+	return nil, false
+}
+
+// record adds an entry, replacing the entry of the same version.
+func (idx *Index) record(e Entry) {
+	if existing, ok := idx.Entry(e.Name, e.Version); ok {
+		*existing = e
+		return
+	}
+	idx.Templates = append(idx.Templates, e)
+}
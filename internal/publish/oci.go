@@ -0,0 +1,96 @@
+package publish
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/oci"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// PushResult describes a template pushed to an OCI registry.
+type PushResult struct {
+	Name      string
+	Version   string
+	Reference oci.Reference // Reference pushed to, with the tag
+	Digest    string        // Digest of the pushed manifest; empty for a dry run
+	Size      int           // Size of the packed template in bytes
+}
+
+// ociConfig is the config blob of a template artifact: the metadata of the
+// template.
+type ociConfig struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// Pinned returns the reference pinned to the pushed manifest.
+func (r *PushResult) Pinned() oci.Reference {
+	ref := r.Reference
+	ref.Digest = r.Digest
+	return ref
+}
+
+// Push packs the template in dir like Publish does and pushes it to an OCI
+// registry as an artifact. Without a tag in reference, the template's
+// version is the tag. The template is expected to be validated first.
+func Push(dir, reference string, dryRun bool) (*PushResult, error) {
+	ref, err := oci.ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Digest != "" {
+		return nil, fmt.Errorf("cannot push to %s: a digest is assigned by the registry, give a tag instead", reference)
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(dir, template.FileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	meta, err := template.NewLoader().LoadMetadata(os.DirFS(dir), template.FileName)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Tag == "" {
+		ref.Tag = meta.Version
+	}
+
+	archive, err := pack(dir, meta.Name, manifest)
+	if err != nil {
+		return nil, err
+	}
+	config, err := json.Marshal(ociConfig{
+		Name:        meta.Name,
+		Type:        string(meta.Type),
+		Version:     meta.Version,
+		Description: meta.Description,
+		Tags:        meta.Tags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template metadata: %w", err)
+	}
+
+	result := &PushResult{Name: meta.Name, Version: meta.Version, Reference: ref, Size: len(archive)}
+	if dryRun {
+		return result, nil
+	}
+
+	annotations := map[string]string{
+		"org.opencontainers.image.title":   meta.Name,
+		"org.opencontainers.image.version": meta.Version,
+	}
+	if meta.Description != "" {
+		annotations["org.opencontainers.image.description"] = meta.Description
+	}
+
+	result.Digest, err = oci.NewClient().Push(ref, oci.Artifact{Config: config, Layer: archive}, annotations)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package publish
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry serves a minimal OCI registry without authentication and
+// returns its host.
+func newTestRegistry(t *testing.T) string {
+	var mu sync.Mutex
+	content := map[string][]byte{} // Blobs by digest, manifests by tag and digest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, key, _ := strings.Cut(r.URL.Path, "/blobs/")
+		if _, ref, ok := strings.Cut(r.URL.Path, "/manifests/"); ok {
+			key = ref
+		}
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("Location", "/upload")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(body)
+			content["sha256:"+hex.EncodeToString(sum[:])] = body
+			content[key] = body
+			w.WriteHeader(http.StatusCreated)
+		case content[key] != nil:
+			_, _ = w.Write(content[key])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestPushAndInstall(t *testing.T) {
+	host := newTestRegistry(t)
+	dir := writeTemplate(t)
+
+	result, err := Push(dir, "oci://"+host+"/acme/alpha", false)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", result.Reference.Tag, "the version is the default tag")
+	assert.NotEmpty(t, result.Digest)
+
+	// Packing is deterministic, so pushing again yields the same digest.
+	again, err := Push(dir, "oci://"+host+"/acme/alpha:stable", false)
+	require.NoError(t, err)
+	assert.Equal(t, result.Digest, again.Digest)
+
+	templatesDir := t.TempDir()
+	changes, err := install.Install(templatesDir, install.Options{Source: result.Pinned().String()})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "alpha", changes[0].Name)
+	assert.Equal(t, install.KindOCI, changes[0].Kind)
+	assert.Equal(t, result.Digest, changes[0].Digest)
+
+	content, err := os.ReadFile(filepath.Join(templatesDir, "alpha", "files", "main.go.tmpl"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(content))
+}
+
+func TestPushDryRun(t *testing.T) {
+	dir := writeTemplate(t)
+
+	result, err := Push(dir, "oci://registry.invalid/acme/alpha:edge", true)
+	require.NoError(t, err)
+	assert.Equal(t, "oci://registry.invalid/acme/alpha:edge", result.Reference.String())
+	assert.Empty(t, result.Digest)
+	assert.Positive(t, result.Size)
+
+	_, err = Push(dir, fmt.Sprintf("oci://ghcr.io/acme/alpha@sha256:%064d", 0), true)
+	require.ErrorContains(t, err, "a digest is assigned by the registry")
+}
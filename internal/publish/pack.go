@@ -0,0 +1,193 @@
+package publish
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// PackResult describes a template packed into a .bpt archive.
+type PackResult struct {
+	Name    string
+	Version string
+	Path    string // Archive written
+	Files   int    // Number of files of the template
+	Size    int    // Size of the archive in bytes
+}
+
+// packedFile is a file of an archive.
+type packedFile struct {
+	Name    string // Slash-separated path in the archive
+	Mode    int64
+	Content []byte
+}
+
+// Pack packs the template in dir into a .bpt archive at dest: a gzipped tar
+// archive of the template, under a directory named after it, and a pack
+// manifest with the checksum of every file. Without dest, the archive is
+// written to <name>-<version>.bpt in the current directory. The template is
+// expected to be validated first.
+func Pack(dir, dest string, dryRun bool) (*PackResult, error) {
+	meta, err := template.NewLoader().LoadMetadata(os.DirFS(dir), template.FileName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filepath.Join(dir, template.FileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	files, err := collectFiles(dir, meta.Name, content)
+	if err != nil {
+		return nil, err
+	}
+
+	packManifest := template.PackManifest{
+		Format:  template.PackFormat,
+		Name:    meta.Name,
+		Type:    meta.Type,
+		Version: meta.Version,
+		Files:   make(map[string]string, len(files)),
+	}
+	for _, f := range files {
+		packManifest.Files[f.Name] = manifest.HashContent(f.Content)
+	}
+	data, err := json.MarshalIndent(packManifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pack manifest: %w", err)
+	}
+
+	// The manifest comes first, so that readers see what the archive holds
+	// before its files.
+	archive, err := writeArchive(append(
+		[]packedFile{{Name: template.PackManifestName, Mode: 0644, Content: append(data, '\n')}},
+		files...,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", dir, err)
+	}
+
+	if dest == "" {
+		dest = meta.Name + "-" + meta.Version + template.ArchiveExtension
+	}
+	result := &PackResult{Name: meta.Name, Version: meta.Version, Path: dest, Files: len(files), Size: len(archive)}
+	if dryRun {
+		return result, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	if err := os.WriteFile(dest, archive, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	return result, nil
+}
+
+// pack writes the files of the template in dir, as collectFiles reads them,
+// to a gzipped tar archive.
+func pack(dir, name string, manifest []byte) ([]byte, error) {
+	files, err := collectFiles(dir, name, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := writeArchive(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", dir, err)
+	}
+	return archive, nil
+}
+
+// collectFiles reads the regular files of the template in dir, under a
+// directory named after the template, skipping git metadata. manifest
+// replaces the content of template.yaml.
+func collectFiles(dir, name string, manifest []byte) ([]packedFile, error) {
+	var files []packedFile
+
+	err := filepath.WalkDir(dir, func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, pth)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		content := manifest
+		if rel != template.FileName {
+			if content, err = os.ReadFile(pth); err != nil {
+				return err
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := int64(0644)
+		if info.Mode().Perm()&0111 != 0 {
+			mode = 0755
+		}
+
+		files = append(files, packedFile{Name: path.Join(name, rel), Mode: mode, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", dir, err)
+	}
+
+	return files, nil
+}
+
+// writeArchive writes files to a gzipped tar archive. Entries carry no
+// timestamps or owners, so writing the same files yields the same checksum.
+func writeArchive(files []packedFile) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, f := range files {
+		header := &tar.Header{
+			Name:     f.Name,
+			Mode:     f.Mode,
+			Size:     int64(len(f.Content)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
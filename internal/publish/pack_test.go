@@ -0,0 +1,90 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackAndOpen(t *testing.T) {
+	dir := writeTemplate(t)
+	dest := filepath.Join(t.TempDir(), "alpha.bpt")
+
+	result, err := Pack(dir, dest, false)
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", result.Name)
+	assert.Equal(t, "1.2.3", result.Version)
+	assert.Equal(t, 2, result.Files)
+
+	archive, err := template.OpenArchive(dest)
+	require.NoError(t, err)
+	assert.Equal(t, template.PackFormat, archive.Manifest.Format)
+	assert.Equal(t, template.TypeFeature, archive.Manifest.Type)
+	assert.Len(t, archive.Manifest.Files, 2)
+
+	loaded, err := template.NewLoader().Load(archive.FS, archive.Resolved().Path)
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", loaded.Template.Name)
+
+	// Packing the same files yields the same archive.
+	again := filepath.Join(t.TempDir(), "alpha.bpt")
+	_, err = Pack(dir, again, false)
+	require.NoError(t, err)
+	first, _ := os.ReadFile(dest)
+	second, _ := os.ReadFile(again)
+	assert.Equal(t, first, second)
+}
+
+func TestPackDefaultsToVersionedName(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	result, err := Pack(writeTemplate(t), "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "alpha-1.2.3.bpt", result.Path)
+	assert.NoFileExists(t, result.Path)
+}
+
+func TestPackedTemplatesResolveAndInstall(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "alpha-1.2.3.bpt")
+	_, err := Pack(writeTemplate(t), dest, false)
+	require.NoError(t, err)
+
+	resolved, err := resolver.ResolvePath(template.TemplateRef{Name: dest})
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", resolved.Path)
+
+	_, err = resolver.ResolvePath(template.TemplateRef{Name: dest, Version: "2.0.0"})
+	var notFound *template.TemplateNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	templatesDir := t.TempDir()
+	changes, err := install.Install(templatesDir, install.Options{Source: dest})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.FileExists(t, filepath.Join(templatesDir, "alpha", "files", "main.go.tmpl"))
+}
+
+func TestUnpack(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "alpha-1.2.3.bpt")
+	_, err := Pack(writeTemplate(t), dest, false)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	result, err := install.Unpack(dest, dir, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "alpha"), result.Dir)
+	assert.FileExists(t, filepath.Join(dir, "alpha", "files", "main.go.tmpl"))
+	assert.NoFileExists(t, filepath.Join(dir, template.PackManifestName))
+
+	_, err = install.Unpack(dest, dir, false, false)
+	assert.ErrorContains(t, err, "already exists")
+
+	_, err = install.Unpack(dest, dir, true, false)
+	assert.NoError(t, err)
+}
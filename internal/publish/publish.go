@@ -0,0 +1,241 @@
+package publish
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// Options describes how a template is published.
+type Options struct {
+	Registry string // Directory or git repository to publish to
+	Branch   string // Branch of a git registry; the default branch when empty
+	Bump     Bump   // Part of the version to increment, if any
+	Version  string // Version to publish instead of the current one
+	Force    bool   // Replace a version that is already published
+	DryRun   bool   // Report what would be published without changing anything
+}
+
+// Result describes a published template.
+type Result struct {
+	Entry    Entry
+	Previous string // Version in template.yaml before publishing; empty when unchanged
+	Registry string
+	Branch   string // Branch pushed to a git registry
+	Commit   string // Commit pushed to a git registry
+}
+
+// Publish packs the template in dir into an archive, adds it to the registry
+// and its index, and pushes the change when the registry is a git
+// repository. The version in template.yaml is updated last, once the
+// template is published. The template is expected to be validated first.
+func Publish(dir string, opts Options) (*Result, error) {
+	if opts.Registry == "" {
+		return nil, errors.New("no registry to publish to")
+	}
+	kind := install.DetectKind(opts.Registry)
+	if kind == install.KindArchive {
+		return nil, fmt.Errorf("registry %s must be a directory or a git repository, not an archive", opts.Registry)
+	}
+	if opts.Branch != "" && kind != install.KindGit {
+		return nil, fmt.Errorf("a branch can only be given for git registries")
+	}
+
+	manifestPath := filepath.Join(dir, template.FileName)
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	meta, err := template.NewLoader().LoadMetadata(os.DirFS(dir), template.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := nextVersion(meta.Version, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Registry: opts.Registry, Branch: opts.Branch}
+	if version != meta.Version {
+		result.Previous = meta.Version
+		if manifest, err = setVersion(manifest, version); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := pack(dir, meta.Name, manifest)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(archive)
+
+	result.Entry = Entry{
+		Name:        meta.Name,
+		Type:        string(meta.Type),
+		Version:     version,
+		Description: meta.Description,
+		Tags:        meta.Tags,
+		Archive:     path.Join(meta.Name, meta.Name+"-"+version+".tar.gz"),
+		SHA256:      hex.EncodeToString(sum[:]),
+		PublishedAt: time.Now().UTC(),
+	}
+
+	tmp, err := os.MkdirTemp("", "blueprint-publish-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	root := opts.Registry
+	if kind == install.KindGit {
+		root = filepath.Join(tmp, "registry")
+		if err := checkoutRegistry(opts.Registry, opts.Branch, root); err != nil {
+			return nil, err
+		}
+	}
+
+	idx, err := LoadIndex(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := idx.Entry(meta.Name, version); ok && !opts.Force {
+		return nil, &VersionExistsError{Name: meta.Name, Version: version, Registry: opts.Registry}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	archivePath := filepath.Join(root, filepath.FromSlash(result.Entry.Archive))
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create registry directory: %w", err)
+	}
+	if err := os.WriteFile(archivePath, archive, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	idx.record(result.Entry)
+	if err := idx.Save(root); err != nil {
+		return nil, err
+	}
+
+	if kind == install.KindGit {
+		message := fmt.Sprintf("Publish %s %s", meta.Name, version)
+		if result.Commit, err = pushRegistry(root, opts.Branch, message); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.Previous != "" {
+		info, err := os.Stat(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update template version: %w", err)
+		}
+		if err := os.WriteFile(manifestPath, manifest, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("failed to update template version: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// nextVersion returns the version to publish: the given version, the current
+// version bumped, or the current version.
+func nextVersion(current string, opts Options) (string, error) {
+	switch {
+	case opts.Version != "" && opts.Bump != "":
+		return "", errors.New("a version and a version bump cannot both be given")
+	case opts.Version != "":
+		if _, _, err := parseVersion(opts.Version); err != nil {
+			return "", err
+		}
+		return opts.Version, nil
+	case opts.Bump != "":
+		return BumpVersion(current, opts.Bump)
+	default:
+		return current, nil
+	}
+}
+
+// checkoutRegistry clones the branch of a git registry without its history.
+// A branch that does not exist yet is created empty.
+func checkoutRegistry(url, branch, dir string) error {
+	args := []string{"clone", "-q", "--depth", "1"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	_, err := runGit("", append(args, "--", url, dir)...)
+	switch {
+	case err == nil:
+		return nil
+	case branch == "":
+		return fmt.Errorf("failed to clone registry %s: %w", url, err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if _, err := runGit("", "clone", "-q", "--depth", "1", "--", url, dir); err != nil {
+		return fmt.Errorf("failed to clone registry %s: %w", url, err)
+	}
+	if _, err := runGit(dir, "checkout", "-q", "--orphan", branch); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	if _, err := runGit(dir, "rm", "-rq", "--ignore-unmatch", "."); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// pushRegistry commits the changes to a git registry and pushes them to the
+// branch, or to the branch that was cloned. It returns the commit.
+func pushRegistry(dir, branch, message string) (string, error) {
+	if _, err := runGit(dir, "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to commit to registry: %w", err)
+	}
+	if _, err := runGit(dir, "commit", "-q", "-m", message); err != nil {
+		return "", fmt.Errorf("failed to commit to registry: %w", err)
+	}
+
+	target := "HEAD"
+	if branch != "" {
+		target = "HEAD:refs/heads/" + branch
+	}
+	if _, err := runGit(dir, "push", "-q", "origin", target); err != nil {
+		return "", fmt.Errorf("failed to push to registry: %w", err)
+	}
+
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read registry commit: %w", err)
+	}
+	return commit, nil
+}
+
+// runGit runs git in dir, or the current directory when dir is empty.
+func runGit(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,147 @@
+package publish
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `# An example template
+name: alpha
+type: feature
+version: "1.2.3" # bumped by blueprint publish
+description: test
+`
+
+func writeTemplate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "template.yaml"), []byte(testManifest), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "files"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "files", "main.go.tmpl"), []byte("package main\n"), 0644))
+	return dir
+}
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		bump    Bump
+		want    string
+	}{
+		{"1.2.3", BumpPatch, "1.2.4"},
+		{"1.2.3", BumpMinor, "1.3.0"},
+		{"1.2.3", BumpMajor, "2.0.0"},
+		{"v0.9.1-rc.1", BumpMinor, "v0.10.0"},
+	}
+	for _, tt := range tests {
+		got, err := BumpVersion(tt.version, tt.bump)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.version)
+	}
+
+	_, err := BumpVersion("1.2", BumpPatch)
+	require.ErrorContains(t, err, "major.minor.patch")
+	_, err = BumpVersion("1.2.3", "huge")
+	require.ErrorContains(t, err, "unknown version bump")
+}
+
+func TestSetVersion(t *testing.T) {
+	edited, err := setVersion([]byte(testManifest), "1.3.0")
+	require.NoError(t, err)
+	assert.Contains(t, string(edited), "version: \"1.3.0\" # bumped by blueprint publish\n")
+	assert.Contains(t, string(edited), "# An example template\n")
+
+	edited, err = setVersion([]byte("name: x\nversion: 1.0.0\nvariables:\n  - name: version\n"), "2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "name: x\nversion: 2.0.0\nvariables:\n  - name: version\n", string(edited))
+
+	_, err = setVersion([]byte("name: x\n"), "2.0.0")
+	require.Error(t, err)
+}
+
+func TestPublishToDirectory(t *testing.T) {
+	dir := writeTemplate(t)
+	registry := filepath.Join(t.TempDir(), "registry")
+
+	result, err := Publish(dir, Options{Registry: registry, Bump: BumpMinor})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", result.Previous)
+	assert.Equal(t, "1.3.0", result.Entry.Version)
+	assert.Equal(t, "alpha/alpha-1.3.0.tar.gz", result.Entry.Archive)
+	assert.Len(t, result.Entry.SHA256, 64)
+
+	manifest, err := os.ReadFile(filepath.Join(dir, "template.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), `version: "1.3.0"`)
+
+	idx, err := LoadIndex(registry)
+	require.NoError(t, err)
+	require.Len(t, idx.Templates, 1)
+	assert.Equal(t, result.Entry.SHA256, idx.Templates[0].SHA256)
+
+	// The published archive installs like any other.
+	changes, err := install.Install(t.TempDir(), install.Options{Source: filepath.Join(registry, "alpha", "alpha-1.3.0.tar.gz")})
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "1.3.0", changes[0].Version)
+
+	_, err = Publish(dir, Options{Registry: registry})
+	var exists *VersionExistsError
+	require.ErrorAs(t, err, &exists)
+
+	again, err := Publish(dir, Options{Registry: registry, Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, result.Entry.SHA256, again.Entry.SHA256, "packing is reproducible")
+	idx, err = LoadIndex(registry)
+	require.NoError(t, err)
+	assert.Len(t, idx.Templates, 1)
+}
+
+func TestPublishDryRun(t *testing.T) {
+	dir := writeTemplate(t)
+	registry := filepath.Join(t.TempDir(), "registry")
+
+	result, err := Publish(dir, Options{Registry: registry, Version: "2.0.0", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", result.Entry.Version)
+
+	manifest, err := os.ReadFile(filepath.Join(dir, "template.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, testManifest, string(manifest))
+	assert.NoDirExists(t, registry)
+}
+
+func TestPublishToGitBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	remote := filepath.Join(t.TempDir(), "registry.git")
+	out, err := exec.Command("git", "init", "-q", "--bare", "-b", "main", remote).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	dir := writeTemplate(t)
+	result, err := Publish(dir, Options{Registry: remote, Branch: "registry", Bump: BumpPatch})
+	require.NoError(t, err)
+	assert.Len(t, result.Commit, 40)
+
+	out, err = exec.Command("git", "-C", remote, "show", "registry:index.yaml").CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Contains(t, string(out), "version: 1.2.4")
+
+	// A second version is added to the existing branch.
+	_, err = Publish(dir, Options{Registry: remote, Branch: "registry", Bump: BumpPatch})
+	require.NoError(t, err)
+	out, err = exec.Command("git", "-C", remote, "ls-tree", "-r", "--name-only", "registry").CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Equal(t, "alpha/alpha-1.2.4.tar.gz\nalpha/alpha-1.2.5.tar.gz\nindex.yaml\n", string(out))
+}
@@ -0,0 +1,98 @@
+package publish
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bump is the part of a version to increment.
+type Bump string
+
+const (
+	BumpMajor Bump = "major"
+	BumpMinor Bump = "minor"
+	BumpPatch Bump = "patch"
+)
+
+// BumpVersion increments a part of a major.minor.patch version and resets
+// the parts after it. A leading v is kept; a pre-release or build suffix is
+// dropped.
+func BumpVersion(version string, bump Bump) (string, error) {
+	prefix, parts, err := parseVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	switch bump {
+	case BumpMajor:
+		parts = [3]int{parts[0] + 1, 0, 0}
+	case BumpMinor:
+		parts = [3]int{parts[0], parts[1] + 1, 0}
+	case BumpPatch:
+		parts = [3]int{parts[0], parts[1], parts[2] + 1}
+	default:
+		return "", fmt.Errorf("unknown version bump %q (expected major, minor, or patch)", bump)
+	}
+
+	return fmt.Sprintf("%s%d.%d.%d", prefix, parts[0], parts[1], parts[2]), nil
+}
+
+// parseVersion splits a version such as v1.2.3-rc.1 into its prefix and
+// numeric parts.
+func parseVersion(version string) (string, [3]int, error) {
+	var parts [3]int
+
+	prefix := ""
+	rest := version
+	if strings.HasPrefix(rest, "v") {
+		prefix, rest = "v", rest[1:]
+	}
+	if i := strings.IndexAny(rest, "-+"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	fields := strings.Split(rest, ".")
+	if len(fields) != 3 {
+		return "", parts, fmt.Errorf("version %q is not of the form major.minor.patch", version)
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return "", parts, fmt.Errorf("version %q is not of the form major.minor.patch", version)
+		}
+		parts[i] = n
+	}
+
+	return prefix, parts, nil
+}
+
+// versionLine matches the top-level version field of template.yaml, with an
+// optional trailing comment.
+var versionLine = regexp.MustCompile(`(?m)^version:([ \t]*)(.*?)([ \t]+#.*)?$`)
+
+// setVersion returns the content of template.yaml with its version replaced.
+// Only the version line is edited, so comments and formatting are kept, and
+// the quoting of the previous value is reused.
+func setVersion(manifest []byte, version string) ([]byte, error) {
+	loc := versionLine.FindSubmatchIndex(manifest)
+	if loc == nil {
+		return nil, fmt.Errorf("template.yaml has no top-level version field")
+	}
+
+	old := string(manifest[loc[4]:loc[5]])
+	value := version
+	switch {
+	case strings.HasPrefix(old, `"`):
+		value = `"` + version + `"`
+	case strings.HasPrefix(old, `'`):
+		value = `'` + version + `'`
+	}
+
+	edited := make([]byte, 0, len(manifest)+len(value))
+	edited = append(edited, manifest[:loc[4]]...)
+	edited = append(edited, value...)
+	edited = append(edited, manifest[loc[5]:]...)
+	return edited, nil
+}
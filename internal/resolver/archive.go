@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// ArchiveFetcher downloads a template archive and returns the directory it
+// is extracted to.
+type ArchiveFetcher interface {
+	Fetch(url string) (string, error)
+}
+
+// IsArchiveURL reports whether a template reference is the http or https URL
+// of a template archive rather than the name of a template.
+func IsArchiveURL(name string) bool {
+	return strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://")
+}
+
+// ResolveArchive resolves the template in the archive at ref.Name. The
+// archive must contain a single template, or a single template of
+// ref.Version if a version is given.
+func ResolveArchive(fetcher ArchiveFetcher, ref template.TemplateRef) (*template.ResolvedTemplate, error) {
+	dir, err := fetcher.Fetch(ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	source := Source{Name: ref.Name, Filesystem: os.DirFS(dir), Dir: dir}
+	found, err := NewSourceResolver(source).Discover(template.DiscoverOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for pth, meta := range found {
+		if ref.Version == "" || meta.Version == ref.Version {
+			paths = append(paths, pth)
+		}
+	}
+
+	switch {
+	case len(found) == 0:
+		return nil, fmt.Errorf("archive %s has no %s", ref.Name, template.FileName)
+	case len(paths) == 0:
+		return nil, &template.TemplateNotFoundError{Name: ref.Name}
+	case len(paths) > 1:
+		return nil, fmt.Errorf("archive %s contains %d templates; an archive must contain a single template", ref.Name, len(paths))
+	}
+
+	return &template.ResolvedTemplate{Path: paths[0], FS: source.Filesystem}, nil
+}
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dirFetcher fetches every archive from the same directory.
+type dirFetcher string
+
+func (d dirFetcher) Fetch(string) (string, error) { return string(d), nil }
+
+func TestChainResolver_ResolvesArchives(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "go-cli-1.0.0", "go-cli"), validProjectTemplate)
+	const url = "https://example.com/templates/go-cli.tar.gz"
+
+	r := NewChainResolver()
+	_, err := r.Resolve(template.TemplateRef{Name: url})
+	assert.Error(t, err)
+
+	r = r.WithArchives(dirFetcher(dir))
+	resolved, err := r.Resolve(template.TemplateRef{Name: url})
+	require.NoError(t, err)
+	assert.Equal(t, "go-cli-1.0.0/go-cli", resolved.Path)
+
+	_, err = r.Resolve(template.TemplateRef{Name: url, Version: "2.0.0"})
+	var notFound *template.TemplateNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestResolveArchive_RequiresSingleTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "one"), validProjectTemplate)
+	writeTemplate(t, filepath.Join(dir, "two"), validProjectTemplate)
+
+	_, err := ResolveArchive(dirFetcher(dir), template.TemplateRef{Name: "https://example.com/t.zip"})
+	assert.ErrorContains(t, err, "contains 2 templates")
+
+	_, err = ResolveArchive(dirFetcher(t.TempDir()), template.TemplateRef{Name: "https://example.com/t.zip"})
+	assert.ErrorContains(t, err, "has no template.yaml")
+}
@@ -2,6 +2,8 @@ package resolver
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/dhanush0x96c/blueprint/internal/template"
 )
@@ -9,6 +11,7 @@ import (
 // ChainResolver is a resolver that chains multiple resolvers together.
 type ChainResolver struct {
 	resolvers []template.Resolver
+	archives  ArchiveFetcher
 }
 
 // NewChainResolver creates a new chain resolver from the provided sources.
@@ -20,8 +23,28 @@ func NewChainResolver(sources ...Source) *ChainResolver {
 	return &ChainResolver{resolvers: resolvers}
 }
 
-// Resolve resolves a template reference using the chain of resolvers.
+// WithArchives returns a copy of the chain that resolves references to
+// template archive URLs with fetcher.
+func (c *ChainResolver) WithArchives(fetcher ArchiveFetcher) *ChainResolver {
+	cp := *c
+	cp.archives = fetcher
+	return &cp
+}
+
+// Resolve resolves a template reference using the chain of resolvers. A
+// reference that is a path is resolved as a template directory on disk, and
+// one that is a URL as a template archive, bypassing the sources.
 func (c *ChainResolver) Resolve(ref template.TemplateRef) (*template.ResolvedTemplate, error) {
+	if IsPath(ref.Name) {
+		return ResolvePath(ref)
+	}
+	if IsArchiveURL(ref.Name) {
+		if c.archives == nil {
+			return nil, fmt.Errorf("cannot download template archive %s", ref.Name)
+		}
+		return ResolveArchive(c.archives, ref)
+	}
+
 	if len(c.resolvers) == 0 {
 		return nil, &template.TemplateNotFoundError{Name: ref.Name}
 	}
@@ -37,3 +60,51 @@ func (c *ChainResolver) Resolve(ref template.TemplateRef) (*template.ResolvedTem
 
 	return nil, errors.Join(errs...)
 }
+
+// Versions returns the versions of a template offered by any source of the
+// chain.
+func (c *ChainResolver) Versions(name string) ([]string, error) {
+	var versions []string
+	for _, r := range c.resolvers {
+		lister, ok := r.(template.VersionLister)
+		if !ok {
+			continue
+		}
+		listed, err := lister.Versions(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range listed {
+			if !slices.Contains(versions, v) {
+				versions = append(versions, v)
+			}
+		}
+	}
+	return versions, nil
+}
+
+// ResolveVariableLibrary finds a variable library in the first source that
+// ships it.
+func (c *ChainResolver) ResolveVariableLibrary(name string) (*template.VariableLibrary, error) {
+	var errs []error
+	for _, r := range c.resolvers {
+		libs, ok := r.(template.VariableLibraryResolver)
+		if !ok {
+			continue
+		}
+		lib, err := libs.ResolveVariableLibrary(name)
+		if err == nil {
+			return lib, nil
+		}
+
+		var notFound *template.VariableLibraryNotFoundError
+		if !errors.As(err, &notFound) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, &template.VariableLibraryNotFoundError{Name: name}
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// IsPath reports whether a template reference names a directory or packed
+// template on disk rather than a template: an absolute path, one starting
+// with ./ or ../, or a .bpt file. Template names may contain slashes, so a
+// relative directory must be given with a leading ./ to be resolved as a
+// path.
+func IsPath(name string) bool {
+	if name == "." || name == ".." || filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return true
+	}
+	if template.IsArchive(name) && !IsArchiveURL(name) {
+		return true
+	}
+	for _, prefix := range []string{"./", "../", `.\`, `..\`} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvePath resolves the template in the directory ref.Name, which must
+// contain a template file, or in the packed template ref.Name, which is read
+// into memory. A reference with a version only resolves a template of that
+// version.
+func ResolvePath(ref template.TemplateRef) (*template.ResolvedTemplate, error) {
+	abs, err := filepath.Abs(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("resolve template path %s: %w", ref.Name, err)
+	}
+
+	info, err := os.Stat(abs)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &template.TemplateNotFoundError{Name: ref.Name}
+	} else if err != nil {
+		return nil, fmt.Errorf("resolve template path %s: %w", ref.Name, err)
+	}
+	if !info.IsDir() {
+		if template.IsArchive(abs) {
+			return resolvePacked(ref, abs)
+		}
+		return nil, fmt.Errorf("template path %s is not a directory", ref.Name)
+	}
+
+	// The parent directory is the filesystem, so that the template keeps the
+	// name of its directory as its path.
+	fsys := os.DirFS(filepath.Dir(abs))
+	pth := filepath.Base(abs)
+	if filepath.Dir(abs) == abs {
+		fsys, pth = os.DirFS(abs), "."
+	}
+
+	meta, err := template.NewLoader().LoadMetadata(fsys, pth)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template path %s has no %s", ref.Name, template.FileName)
+	} else if err != nil {
+		return nil, err
+	}
+	if ref.Version != "" && meta.Version != ref.Version {
+		return nil, &template.TemplateNotFoundError{Name: ref.Name}
+	}
+
+	return &template.ResolvedTemplate{Path: pth, FS: fsys}, nil
+}
+
+// resolvePacked resolves the template of the packed template at pth.
+func resolvePacked(ref template.TemplateRef, pth string) (*template.ResolvedTemplate, error) {
+	archive, err := template.OpenArchive(pth)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Version != "" && archive.Manifest.Version != ref.Version {
+		return nil, &template.TemplateNotFoundError{Name: ref.Name}
+	}
+	return archive.Resolved(), nil
+}
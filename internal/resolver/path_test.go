@@ -0,0 +1,55 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPath(t *testing.T) {
+	for _, name := range []string{".", "..", "./tpl", "../templates/tpl", "/srv/tpl", "go-cli-1.0.0.bpt"} {
+		assert.True(t, IsPath(name), name)
+	}
+	for _, name := range []string{"go-cli", "features/go/testing", ".hidden", "https://example.com/go-cli.bpt"} {
+		assert.False(t, IsPath(name), name)
+	}
+}
+
+func TestChainResolver_ResolvesPaths(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "one-off")
+	writeTemplate(t, dir, validProjectTemplate)
+
+	// A source that offers a template of the same name is bypassed.
+	other := t.TempDir()
+	writeTemplate(t, filepath.Join(other, "go-cli"), validProjectTemplate)
+	r := NewChainResolver(Source{Name: "test", Type: SourceTypeUser, Filesystem: os.DirFS(other)})
+
+	resolved, err := r.Resolve(template.TemplateRef{Name: dir})
+	require.NoError(t, err)
+	assert.Equal(t, "one-off", resolved.Path)
+
+	tmpl, err := template.NewLoader().Load(resolved.FS, resolved.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "go-cli", tmpl.Template.Name)
+
+	t.Chdir(base)
+	resolved, err = r.Resolve(template.TemplateRef{Name: "./one-off"})
+	require.NoError(t, err)
+	assert.Equal(t, "one-off", resolved.Path)
+
+	_, err = r.Resolve(template.TemplateRef{Name: "./one-off", Version: "2.0.0"})
+	var notFound *template.TemplateNotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	_, err = r.Resolve(template.TemplateRef{Name: "./missing"})
+	require.ErrorAs(t, err, &notFound)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "empty"), 0o755))
+	_, err = r.Resolve(template.TemplateRef{Name: "./empty"})
+	require.ErrorContains(t, err, "has no "+template.FileName)
+}
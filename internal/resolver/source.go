@@ -15,4 +15,5 @@ type Source struct {
 	Name       string
 	Type       SourceType
 	Filesystem fs.FS
+	Dir        string // Directory of the source on disk; empty for embedded sources
 }
@@ -20,7 +20,8 @@ func NewSourceResolver(source Source) *SourceResolver {
 	return &SourceResolver{source: source, loader: template.NewLoader()}
 }
 
-// Resolve resolves templates from the configured source.
+// Resolve resolves templates from the configured source. A reference with a
+// version only resolves a template of that version.
 func (r *SourceResolver) Resolve(ref template.TemplateRef) (*template.ResolvedTemplate, error) {
 	templates, err := r.Discover(template.DiscoverOptions{IgnoreErrors: true})
 	if err != nil {
@@ -28,7 +29,7 @@ func (r *SourceResolver) Resolve(ref template.TemplateRef) (*template.ResolvedTe
 	}
 
 	for pth, tmpl := range templates {
-		if tmpl.Name == ref.Name {
+		if tmpl.Name == ref.Name && (ref.Version == "" || tmpl.Version == ref.Version) {
 			return &template.ResolvedTemplate{
 				Path: pth,
 				FS:   r.source.Filesystem,
@@ -116,3 +117,24 @@ func (r *SourceResolver) Exists(name string) bool {
 
 	return false
 }
+
+// Versions returns the versions of the template named name in the source.
+func (r *SourceResolver) Versions(name string) ([]string, error) {
+	templates, err := r.Discover(template.DiscoverOptions{IgnoreErrors: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			versions = append(versions, tmpl.Version)
+		}
+	}
+	return versions, nil
+}
+
+// ResolveVariableLibrary finds a variable library shipped by the source.
+func (r *SourceResolver) ResolveVariableLibrary(name string) (*template.VariableLibrary, error) {
+	return template.FindVariableLibrary(r.source.Filesystem, name)
+}
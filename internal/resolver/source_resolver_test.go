@@ -3,6 +3,7 @@ package resolver
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/dhanush0x96c/blueprint/internal/template"
@@ -151,3 +152,31 @@ func TestSourceResolver_Discover(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestChainResolver_Versions(t *testing.T) {
+	user, builtin := t.TempDir(), t.TempDir()
+	writeTemplate(t, filepath.Join(user, "testing"), validFeatureTemplate)
+	writeTemplate(t, filepath.Join(builtin, "testing"), strings.Replace(validFeatureTemplate, `"1.0.0"`, `"2.0.0"`, 1))
+
+	r := NewChainResolver(
+		Source{Name: "user", Type: SourceTypeUser, Filesystem: os.DirFS(user)},
+		Source{Name: "builtin", Type: SourceTypeBuiltin, Filesystem: os.DirFS(builtin)},
+	)
+
+	versions, err := r.Versions("testing")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.0.0", "2.0.0"}, versions)
+
+	// Without a version, the first source wins; with one, the source that
+	// has it.
+	resolved, err := r.Resolve(template.TemplateRef{Name: "testing"})
+	require.NoError(t, err)
+	require.Equal(t, os.DirFS(user), resolved.FS)
+
+	resolved, err = r.Resolve(template.TemplateRef{Name: "testing", Version: "2.0.0"})
+	require.NoError(t, err)
+	require.Equal(t, os.DirFS(builtin), resolved.FS)
+
+	_, err = r.Resolve(template.TemplateRef{Name: "testing", Version: "3.0.0"})
+	require.Error(t, err)
+}
@@ -0,0 +1,320 @@
+package scaffold
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+)
+
+// addition describes how a tree added to a project is recorded in the
+// project manifest.
+type addition struct {
+	project   *manifest.Manifest
+	node      string // ID the root of the tree is recorded under
+	component string // Name of the generation, for component templates
+}
+
+// Add scaffolds a feature or component into the existing project at
+// opts.OutputDir and records it in the project manifest next to the
+// templates the project was generated from. The template's requirements are
+// checked against the manifest first: depending on the template, a project
+// that does not meet them is refused or the mismatches are reported as
+// warnings. Variables recorded for the project are the defaults of the
+// template's variables, so values such as the module path are not asked
+// again.
+//
+// A feature is part of a project once: adding it again fails unless
+// opts.Overwrite is set. A component can be added any number of times, each
+// generation recorded under opts.ComponentName or a name derived from the
+// template.
+func (s *Scaffolder) Add(opts Options) (*Result, error) {
+	if opts.OutputDir == "" {
+		opts.OutputDir = "."
+	}
+
+	project, err := manifest.Load(opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := s.engine.LoadTemplate(opts.TemplateRef)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := loaded.Template
+
+	add := &addition{project: project, node: nextChildID(project)}
+	switch tmpl.Type {
+	case template.TypeProject:
+		return nil, fmt.Errorf("%s is a project template; only features and components can be added", tmpl.Name)
+	case template.TypeComponent:
+		if add.component, err = componentName(project, tmpl.Name, opts.ComponentName); err != nil {
+			return nil, err
+		}
+	default:
+		if opts.ComponentName != "" {
+			return nil, fmt.Errorf("%s is a feature; only components are recorded under a name", tmpl.Name)
+		}
+		if id, ok := addedNodeID(project, tmpl.Name); ok {
+			if !opts.Overwrite {
+				return nil, &AlreadyAddedError{Template: tmpl.Name, Node: id}
+			}
+			add.node = id
+		}
+	}
+
+	var warnings []template.Warning
+	if mismatches := tmpl.Requires.Check(projectState(project)); len(mismatches) > 0 {
+		if tmpl.Requires.Policy() != template.MismatchWarn {
+			return nil, &template.IncompatibleError{Template: tmpl.Name, Project: project.Template, Mismatches: mismatches}
+		}
+		for _, m := range mismatches {
+			warnings = append(warnings, template.Warning{Template: tmpl.Name, Message: m.String()})
+		}
+	}
+
+	result, err := s.add(opts, add)
+	if err != nil {
+		return nil, err
+	}
+	result.Warnings = append(warnings, result.Warnings...)
+	return result, nil
+}
+
+// Regen generates a component of the project at opts.OutputDir again, with
+// the current version of its template and the variables recorded for the
+// generation, which opts.Variables override. The files of the generation are
+// overwritten; when any of them changed since it was generated, Regen fails
+// with a RegenConflictError unless opts.Overwrite is set.
+func (s *Scaffolder) Regen(name string, opts Options) (*Result, error) {
+	if opts.OutputDir == "" {
+		opts.OutputDir = "."
+	}
+
+	project, err := manifest.Load(opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	component, ok := project.Component(name)
+	if !ok {
+		return nil, &ComponentNotFoundError{Name: name, Dir: opts.OutputDir}
+	}
+
+	if !opts.Overwrite {
+		if modified := modifiedFiles(opts.OutputDir, project, component.Node); len(modified) > 0 {
+			return nil, &RegenConflictError{Component: name, Files: modified}
+		}
+	}
+
+	recorded := vars.Variables{NameSpecific: map[string]map[string]string{
+		component.Template: formatAnswers(component.Variables),
+	}}
+	opts.TemplateRef = template.TemplateRef{Name: component.Template}
+	opts.Variables = recorded.Merge(opts.Variables)
+	opts.Overwrite = true
+
+	return s.add(opts, &addition{project: project, node: component.Node, component: name})
+}
+
+// add scaffolds a tree into the project of add. Variables recorded for the
+// project are the defaults of the tree's variables.
+func (s *Scaffolder) add(opts Options, add *addition) (*Result, error) {
+	defaults := maps.Clone(opts.Defaults)
+	if defaults == nil {
+		defaults = make(map[string]any)
+	}
+	maps.Copy(defaults, projectState(add.project).Variables)
+	opts.Defaults = defaults
+	opts.addTo = add
+
+	return s.Scaffold(opts)
+}
+
+// componentName returns the name to record a generation of a component
+// template under: the given name, which must not be taken, or else the last
+// element of the template name, numbered from 2 when taken.
+func componentName(m *manifest.Manifest, templateName, name string) (string, error) {
+	if name != "" {
+		if _, ok := m.Component(name); ok {
+			return "", fmt.Errorf("the project already has a component named %q; regenerate it with blueprint components regen %s", name, name)
+		}
+		return name, nil
+	}
+
+	base := path.Base(templateName)
+	name = base
+	for i := 2; ; i++ {
+		if _, ok := m.Component(name); !ok {
+			return name, nil
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// modifiedFiles returns the project files recorded under the node with the
+// given ID, or its descendants, that changed since they were written.
+func modifiedFiles(root string, m *manifest.Manifest, id string) []string {
+	var modified []string
+	for _, f := range m.Files {
+		if f.Node != id && !strings.HasPrefix(f.Node, id+".") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(f.Path)))
+		if err == nil && manifest.HashContent(content) != f.Hash {
+			modified = append(modified, f.Path)
+		}
+	}
+	return modified
+}
+
+// projectState returns the state of a project that requirements are checked
+// against. Where nodes record different values for a variable, the value of
+// the node closest to the project root wins.
+func projectState(m *manifest.Manifest) template.ProjectState {
+	state := template.ProjectState{Template: m.Template, Variables: make(map[string]any)}
+
+	nodes := slices.Clone(m.Nodes)
+	slices.SortStableFunc(nodes, func(a, b manifest.Node) int {
+		return strings.Count(a.ID, ".") - strings.Count(b.ID, ".")
+	})
+	for _, node := range nodes {
+		if strings.HasPrefix(node.ID, staleNodePrefix) {
+			continue
+		}
+		for name, value := range node.Variables {
+			if _, ok := state.Variables[name]; !ok {
+				state.Variables[name] = value
+			}
+		}
+	}
+	return state
+}
+
+// addedNodeID returns the ID of the node of the project that the template was
+// recorded under.
+func addedNodeID(m *manifest.Manifest, name string) (string, bool) {
+	for _, node := range m.Nodes {
+		if node.Template == name && !strings.HasPrefix(node.ID, staleNodePrefix) {
+			return node.ID, true
+		}
+	}
+	return "", false
+}
+
+// addTo turns the recorded manifest of an added tree into the manifest of
+// the project it was added to. The root of the tree is recorded under the
+// node ID of the addition, replacing the nodes of an earlier addition there.
+// Files keep their records unless the tree wrote them again. A component is
+// recorded with the values of the variables its template declares.
+func (r *manifestRecorder) addTo(add *addition, tree *template.TemplateNode) {
+	project, root := add.project, add.node
+	remap := func(id string) string {
+		return root + strings.TrimPrefix(id, "0")
+	}
+	replaced := func(id string) bool {
+		return id == root || strings.HasPrefix(id, root+".")
+	}
+
+	m := *project
+	m.BlueprintVersion = r.manifest.BlueprintVersion
+
+	m.Nodes = slices.DeleteFunc(slices.Clone(project.Nodes), func(n manifest.Node) bool { return replaced(n.ID) })
+	for _, node := range r.manifest.Nodes {
+		node.ID = remap(node.ID)
+		m.Nodes = append(m.Nodes, node)
+	}
+
+	added := make(map[string]manifest.File, len(r.manifest.Files))
+	for _, f := range r.manifest.Files {
+		f.Node = remap(f.Node)
+		added[f.Path] = f
+	}
+	m.Files = nil
+	for _, f := range project.Files {
+		if a, ok := added[f.Path]; ok {
+			f = a
+			delete(added, f.Path)
+		} else if replaced(f.Node) && !slices.ContainsFunc(m.Nodes, func(n manifest.Node) bool { return n.ID == f.Node }) {
+			// Files of a replaced node that the tree no longer writes stay
+			// attributed to the added template.
+			f.Node = root
+		}
+		m.Files = append(m.Files, f)
+	}
+	for _, f := range r.manifest.Files {
+		if a, ok := added[f.Path]; ok {
+			m.Files = append(m.Files, a)
+		}
+	}
+
+	// Injections of an earlier addition stay recorded, since the code they
+	// inserted stays in place when the tree injects it again.
+	m.Injections = slices.Clone(project.Injections)
+	for _, injection := range r.manifest.Injections {
+		injection.Node = remap(injection.Node)
+		if !slices.Contains(m.Injections, injection) {
+			m.Injections = append(m.Injections, injection)
+		}
+	}
+
+	m.Versions = slices.DeleteFunc(slices.Clone(project.Versions), func(v manifest.Version) bool {
+		return slices.ContainsFunc(r.manifest.Versions, func(added manifest.Version) bool { return added.Template == v.Template })
+	})
+	m.Versions = append(m.Versions, r.manifest.Versions...)
+
+	if add.component != "" {
+		record := manifest.Component{
+			Name:        add.component,
+			Template:    tree.Template.Name,
+			Version:     tree.Template.Version,
+			Node:        root,
+			GeneratedAt: r.manifest.CreatedAt,
+		}
+		if node, ok := r.manifest.Node("0"); ok {
+			for _, v := range tree.Template.Variables {
+				value, ok := node.Variables[v.Name]
+				if !ok {
+					continue
+				}
+				if record.Variables == nil {
+					record.Variables = make(map[string]any)
+				}
+				record.Variables[v.Name] = value
+			}
+		}
+		m.Components = slices.Clone(project.Components)
+		if i := slices.IndexFunc(m.Components, func(c manifest.Component) bool { return c.Name == add.component }); i >= 0 {
+			m.Components[i] = record
+		} else {
+			m.Components = append(m.Components, record)
+		}
+	}
+
+	r.manifest = &m
+}
+
+// nextChildID returns the ID of a new child of the project root.
+func nextChildID(m *manifest.Manifest) string {
+	next := 0
+	for _, node := range m.Nodes {
+		rest, ok := strings.CutPrefix(node.ID, "0.")
+		if !ok {
+			continue
+		}
+		index, _, _ := strings.Cut(rest, ".")
+		if n, err := strconv.Atoi(index); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return "0." + strconv.Itoa(next)
+}
@@ -0,0 +1,221 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAddScaffolder(t *testing.T, onMismatch string) *Scaffolder {
+	t.Helper()
+	return newTestScaffolder(t, map[string]string{
+		"api/" + template.FileName: `name: api
+type: project
+version: 1.0.0
+description: An API
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: api
+  - name: framework
+    prompt: Framework?
+    type: string
+    default: chi
+files:
+  - src: main.txt
+    dest: main.txt
+`,
+		"api/main.txt": "main\n",
+		"metrics/" + template.FileName: `name: metrics
+type: feature
+version: 1.0.0
+description: Metrics
+requires:
+  projects: [api]
+  variables:
+    framework: chi
+  on_mismatch: ` + onMismatch + `
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+files:
+  - src: metrics.txt.tmpl
+    dest: metrics/{{ .name }}.txt
+`,
+		"metrics/metrics.txt.tmpl": "metrics of {{ .name }}\n",
+		"components/handler/" + template.FileName: `name: components/handler
+type: component
+version: 1.0.0
+description: A handler
+variables:
+  - name: resource
+    prompt: Resource?
+    type: string
+files:
+  - src: handler.txt.tmpl
+    dest: handlers/{{ .resource }}.txt
+`,
+		"components/handler/handler.txt.tmpl": "{{ .resource }} handler of {{ .name }}\n",
+	})
+}
+
+func scaffoldAPI(t *testing.T, s *Scaffolder, framework string) string {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "api")
+	_, err := s.Scaffold(Options{
+		TemplateRef:  template.TemplateRef{Name: "api"},
+		OutputDir:    out,
+		Variables:    vars.Variables{Global: map[string]string{"framework": framework}},
+		SkipPostInit: true,
+	})
+	require.NoError(t, err)
+	return out
+}
+
+func TestAddRecordsTemplateInManifest(t *testing.T) {
+	s := newAddScaffolder(t, "error")
+	out := scaffoldAPI(t, s, "chi")
+
+	result, err := s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+
+	// The project name recorded for the project is the default.
+	content, err := os.ReadFile(filepath.Join(out, "metrics", "api.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "metrics of api\n", string(content))
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	assert.Equal(t, "api", m.Template)
+	node, ok := m.Node("0.0")
+	require.True(t, ok)
+	assert.Equal(t, "metrics", node.Template)
+	f, ok := m.FileByPath("metrics/api.txt")
+	require.True(t, ok)
+	assert.Equal(t, "0.0", f.Node)
+	_, ok = m.FileByPath("main.txt")
+	assert.True(t, ok, "files of the project stay recorded")
+
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	var added *AlreadyAddedError
+	require.ErrorAs(t, err, &added)
+	assert.Equal(t, "0.0", added.Node)
+
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out, Overwrite: true})
+	require.NoError(t, err)
+	m, err = manifest.Load(out)
+	require.NoError(t, err)
+	assert.Len(t, m.Nodes, 2, "adding again replaces the node")
+}
+
+func TestAddChecksRequirements(t *testing.T) {
+	s := newAddScaffolder(t, "error")
+	out := scaffoldAPI(t, s, "gin")
+
+	_, err := s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	var incompatible *template.IncompatibleError
+	require.ErrorAs(t, err, &incompatible)
+	assert.Equal(t, "api", incompatible.Project)
+	require.Len(t, incompatible.Mismatches, 1)
+	assert.Equal(t, "requires framework=chi, project has framework=gin", incompatible.Mismatches[0].String())
+	assert.NoDirExists(t, filepath.Join(out, "metrics"))
+
+	s = newAddScaffolder(t, "warn")
+	result, err := s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	require.NoError(t, err)
+	assert.Contains(t, result.Warnings, template.Warning{Template: "metrics", Message: "requires framework=chi, project has framework=gin"})
+	assert.FileExists(t, filepath.Join(out, "metrics", "api.txt"))
+}
+
+func TestAddRequiresProject(t *testing.T) {
+	s := newAddScaffolder(t, "error")
+
+	_, err := s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: t.TempDir()})
+	var notFound *manifest.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	out := scaffoldAPI(t, s, "chi")
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "api"}, OutputDir: out})
+	require.ErrorContains(t, err, "only features and components can be added")
+}
+
+func TestAddRecordsComponentGenerations(t *testing.T) {
+	s := newAddScaffolder(t, "error")
+	out := scaffoldAPI(t, s, "chi")
+
+	addHandler := func(resource, name string) error {
+		_, err := s.Add(Options{
+			TemplateRef:   template.TemplateRef{Name: "components/handler"},
+			OutputDir:     out,
+			ComponentName: name,
+			Variables:     vars.Variables{Global: map[string]string{"resource": resource}},
+		})
+		return err
+	}
+	require.NoError(t, addHandler("users", ""))
+	require.NoError(t, addHandler("orders", ""))
+	require.NoError(t, addHandler("items", "items-handler"))
+	require.ErrorContains(t, addHandler("carts", "items-handler"), `already has a component named "items-handler"`)
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	require.Len(t, m.Components, 3)
+	assert.Equal(t, []string{"handler", "handler-2", "items-handler"},
+		[]string{m.Components[0].Name, m.Components[1].Name, m.Components[2].Name})
+	assert.Equal(t, map[string]any{"resource": "orders"}, m.Components[1].Variables)
+	assert.Equal(t, "0.1", m.Components[1].Node)
+	f, ok := m.FileByPath("handlers/orders.txt")
+	require.True(t, ok)
+	assert.Equal(t, "0.1", f.Node)
+}
+
+func TestRegenComponent(t *testing.T) {
+	s := newAddScaffolder(t, "error")
+	out := scaffoldAPI(t, s, "chi")
+
+	_, err := s.Add(Options{
+		TemplateRef: template.TemplateRef{Name: "components/handler"},
+		OutputDir:   out,
+		Variables:   vars.Variables{Global: map[string]string{"resource": "users"}},
+	})
+	require.NoError(t, err)
+
+	usersFile := filepath.Join(out, "handlers", "users.txt")
+	require.NoError(t, os.WriteFile(usersFile, []byte("edited\n"), 0644))
+
+	_, err = s.Regen("handler", Options{OutputDir: out})
+	var conflict *RegenConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"handlers/users.txt"}, conflict.Files)
+
+	// The recorded variables are used again.
+	_, err = s.Regen("handler", Options{OutputDir: out, Overwrite: true})
+	require.NoError(t, err)
+	content, err := os.ReadFile(usersFile)
+	require.NoError(t, err)
+	assert.Equal(t, "users handler of api\n", string(content))
+
+	_, err = s.Regen("handler", Options{OutputDir: out, Variables: vars.Variables{Global: map[string]string{"resource": "people"}}})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(out, "handlers", "people.txt"))
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	require.Len(t, m.Components, 1)
+	assert.Equal(t, map[string]any{"resource": "people"}, m.Components[0].Variables)
+	assert.Len(t, m.Nodes, 2)
+
+	_, err = s.Regen("missing", Options{OutputDir: out})
+	var notFound *ComponentNotFoundError
+	require.ErrorAs(t, err, &notFound)
+}
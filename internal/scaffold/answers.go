@@ -0,0 +1,211 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"gopkg.in/yaml.v3"
+)
+
+// answersHeader is written at the top of every answers file.
+const answersHeader = "# Blueprint answers. Replay with: blueprint init --answers-file <this file>\n"
+
+// Answers records the variables and include selections of a scaffold so that
+// it can be replayed without prompts.
+type Answers struct {
+	Template  string                    `yaml:"template"`
+	Variables map[string]map[string]any `yaml:"variables,omitempty"` // Keyed by template name
+	Nodes     map[string]map[string]any `yaml:"nodes,omitempty"`     // Values that differ between nodes of the same template, keyed by node ID
+	Includes  map[string]bool           `yaml:"includes,omitempty"`  // Whether each optional include was enabled
+}
+
+// newAnswers collects the values of the variables declared by each template
+// of the tree and whether each of its includes was enabled. Mandated includes
+// are left out, since policy enables them on every run.
+func newAnswers(tree *template.TemplateNode, contexts template.RenderContexts) *Answers {
+	a := &Answers{
+		Template:  tree.Template.Name,
+		Variables: make(map[string]map[string]any),
+		Includes:  make(map[string]bool),
+	}
+
+	var walk func(node *template.TemplateNode)
+	walk = func(node *template.TemplateNode) {
+		a.recordNode(node, contexts[node.ID])
+
+		enabled := make(map[string]bool, len(node.Children))
+		for _, child := range node.Children {
+			enabled[child.Template.Name] = true
+		}
+		for _, inc := range node.Template.Includes {
+			if inc.Mandated {
+				continue
+			}
+			a.Includes[inc.Name] = a.Includes[inc.Name] || enabled[inc.Name]
+		}
+
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	if len(a.Includes) == 0 {
+		a.Includes = nil
+	}
+	return a
+}
+
+// recordNode records the values of the variables a node declares, except
+// secrets, which have to be given again on replay. Values
+// that differ from those of an earlier node of the same template are recorded
+// for the node alone.
+func (a *Answers) recordNode(node *template.TemplateNode, ctx *template.Context) {
+	if ctx == nil {
+		return
+	}
+
+	values := make(map[string]any)
+	for _, v := range node.Template.Variables {
+		if v.IsSecret() {
+			continue
+		}
+		if value, ok := ctx.Variables[v.Name]; ok {
+			values[v.Name] = value
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	existing, ok := a.Variables[node.Template.Name]
+	if !ok {
+		a.Variables[node.Template.Name] = values
+		return
+	}
+
+	differing := make(map[string]any)
+	for name, value := range values {
+		if recorded, ok := existing[name]; !ok || !reflect.DeepEqual(recorded, value) {
+			differing[name] = value
+		}
+	}
+	if len(differing) > 0 {
+		if a.Nodes == nil {
+			a.Nodes = make(map[string]map[string]any)
+		}
+		a.Nodes[node.ID] = differing
+	}
+}
+
+// LoadAnswers reads an answers file.
+func LoadAnswers(path string) (*Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+
+	var a Answers
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("failed to parse answers file %s: %w", path, err)
+	}
+	if a.Template == "" {
+		return nil, fmt.Errorf("answers file %s does not name a template", path)
+	}
+
+	return &a, nil
+}
+
+// Save writes the answers to path.
+func (a *Answers) Save(path string) error {
+	data, err := yaml.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode answers: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create answers directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, append([]byte(answersHeader), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write answers file: %w", err)
+	}
+
+	return nil
+}
+
+// Vars returns the recorded values as variables scoped to their template or
+// node, in the form --var values take, so that they are coerced to the
+// declared types in the same way.
+func (a *Answers) Vars() vars.Variables {
+	v := vars.Variables{
+		Global:       make(map[string]string),
+		NameSpecific: make(map[string]map[string]string),
+		NodeSpecific: make(map[string]map[string]string),
+	}
+
+	for name, values := range a.Variables {
+		v.NameSpecific[name] = formatAnswers(values)
+	}
+	for id, values := range a.Nodes {
+		v.NodeSpecific[id] = formatAnswers(values)
+	}
+
+	return v
+}
+
+// Override returns the recorded variables and include selections with those
+// given on the command line taking precedence.
+func (a *Answers) Override(v vars.Variables, includes map[string]bool) (vars.Variables, map[string]bool) {
+	if len(a.Includes) == 0 {
+		return a.Vars().Merge(v), includes
+	}
+
+	merged := make(map[string]bool, len(a.Includes)+len(includes))
+	for name, enabled := range a.Includes {
+		merged[name] = enabled
+	}
+	for name, enabled := range includes {
+		merged[name] = enabled
+	}
+	return a.Vars().Merge(v), merged
+}
+
+func formatAnswers(values map[string]any) map[string]string {
+	formatted := make(map[string]string, len(values))
+	for name, value := range values {
+		formatted[name] = formatAnswer(value)
+	}
+	return formatted
+}
+
+// formatAnswer formats a value as it would be given with --var. Lists are
+// comma-separated.
+func formatAnswer(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		return strings.Join(v, ",")
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatAnswer(item)
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
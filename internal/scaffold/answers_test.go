@@ -0,0 +1,90 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswersReplay(t *testing.T) {
+	s := newStaleScaffolder(t)
+	dir := t.TempDir()
+
+	opts := staleOptions(filepath.Join(dir, "first"), false)
+	opts.Variables = vars.Variables{Global: map[string]string{"name": "demo"}}
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Answers{
+		Template:  "app",
+		Variables: map[string]map[string]any{"app": {"name": "demo"}},
+		Includes:  map[string]bool{"extra": false},
+	}, result.Answers)
+
+	path := filepath.Join(dir, "answers", "app.yaml")
+	require.NoError(t, result.Answers.Save(path))
+	loaded, err := LoadAnswers(path)
+	require.NoError(t, err)
+	assert.Equal(t, result.Answers, loaded)
+
+	replayed, err := s.Scaffold(Options{
+		TemplateRef:     opts.TemplateRef,
+		OutputParent:    dir,
+		Variables:       loaded.Vars(),
+		EnabledIncludes: loaded.Includes,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "demo"), replayed.OutputDir)
+	assert.Equal(t, []string{"main.txt"}, replayed.FilesWritten)
+}
+
+func TestLoadAnswersRequiresTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, (&Answers{}).Save(path))
+
+	_, err := LoadAnswers(path)
+	require.ErrorContains(t, err, "does not name a template")
+}
+
+func TestFormatAnswer(t *testing.T) {
+	assert.Equal(t, "true", formatAnswer(true))
+	assert.Equal(t, "8080", formatAnswer(8080))
+	assert.Equal(t, "vet,staticcheck", formatAnswer([]any{"vet", "staticcheck"}))
+	assert.Equal(t, "a,b", formatAnswer([]string{"a", "b"}))
+	assert.Equal(t, "", formatAnswer(nil))
+}
+
+func TestAnswersOverride(t *testing.T) {
+	s := newStaleScaffolder(t)
+	answers := &Answers{
+		Template:  "app",
+		Variables: map[string]map[string]any{"app": {"name": "demo"}},
+		Includes:  map[string]bool{"extra": true},
+	}
+
+	t.Run("recorded answers apply", func(t *testing.T) {
+		v, includes := answers.Override(vars.Variables{}, nil)
+		tree, contexts, err := s.Compose(Options{TemplateRef: template.TemplateRef{Name: "app"}, Variables: v, EnabledIncludes: includes})
+		require.NoError(t, err)
+
+		assert.Equal(t, "demo", contexts[tree.ID].Variables["name"])
+		assert.Len(t, tree.Children, 1)
+	})
+
+	t.Run("flags take precedence", func(t *testing.T) {
+		v, includes := answers.Override(
+			vars.Variables{Global: map[string]string{"name": "other"}},
+			map[string]bool{"extra": false},
+		)
+		tree, contexts, err := s.Compose(Options{TemplateRef: template.TemplateRef{Name: "app"}, Variables: v, EnabledIncludes: includes})
+		require.NoError(t, err)
+
+		assert.Equal(t, "other", contexts[tree.ID].Variables["name"])
+		assert.Empty(t, tree.Children)
+		assert.Equal(t, map[string]bool{"extra": true}, answers.Includes)
+	})
+}
@@ -0,0 +1,105 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+)
+
+// BackupMode selects how existing files are backed up before Options.Overwrite
+// replaces them.
+type BackupMode string
+
+const (
+	BackupSuffix BackupMode = "suffix" // Copy to <name>.bak next to the file
+	BackupTree   BackupMode = "tree"   // Copy into a tree under manifest.Dir/BackupDir/<timestamp>/
+	BackupNone   BackupMode = "none"   // Keep no backups
+)
+
+const (
+	// BackupDir is the directory inside manifest.Dir that holds the backup
+	// trees of runs, one directory per run.
+	BackupDir = "backups"
+
+	// backupSuffix is appended to the name of a file backed up in place.
+	backupSuffix = ".bak"
+
+	// backupIDFormat names the backup tree of a run.
+	backupIDFormat = "20060102T150405Z"
+)
+
+// ParseBackupMode parses a backup mode. The empty string is BackupSuffix.
+func ParseBackupMode(s string) (BackupMode, error) {
+	switch mode := BackupMode(s); mode {
+	case "":
+		return BackupSuffix, nil
+	case BackupSuffix, BackupTree, BackupNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid backup mode %q: expected suffix, tree, or none", s)
+	}
+}
+
+// Backup is a copy of a file made before it was overwritten.
+type Backup struct {
+	Path   string // File that was overwritten, relative to its output directory
+	Backup string // Path of the copy, relative to the project root
+}
+
+// backups makes the copies of a run. Every copy is written through the
+// writer, so that it is journaled and removed when the run is rolled back.
+type backups struct {
+	mode BackupMode
+	root string // Project root the backup tree is created in
+	dir  string // Backup tree of the run, relative to root
+}
+
+func newBackups(mode BackupMode, root string, now time.Time) *backups {
+	if mode == "" {
+		mode = BackupSuffix
+	}
+	return &backups{
+		mode: mode,
+		root: root,
+		dir:  filepath.Join(manifest.Dir, BackupDir, now.UTC().Format(backupIDFormat)),
+	}
+}
+
+// backup copies the file at fullPath before it is replaced with content and
+// returns the path of the copy relative to the project root. A file that
+// already has the content is not copied, and "" is returned. Files outside
+// the project root are backed up next to themselves in either mode.
+func (b *backups) backup(w *Writer, fullPath string, content []byte) (string, error) {
+	existing, err := os.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", fullPath, err)
+	}
+	if bytes.Equal(existing, content) {
+		return "", nil
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", fullPath, err)
+	}
+
+	target := fullPath + backupSuffix
+	rel, err := filepath.Rel(b.root, fullPath)
+	outside := err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	if b.mode == BackupTree && !outside {
+		target = filepath.Join(b.root, b.dir, rel)
+	}
+
+	if err := w.WriteFileWithPerm(target, existing, info.Mode().Perm()); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", fullPath, err)
+	}
+
+	if rel, err := filepath.Rel(b.root, target); err == nil && !outside {
+		return filepath.ToSlash(rel), nil
+	}
+	return target, nil
+}
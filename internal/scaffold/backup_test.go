@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_Backups(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		mode    BackupMode
+		backups []Backup
+	}{
+		"suffix": {
+			mode: BackupSuffix,
+			backups: []Backup{
+				{Path: "README.md", Backup: "README.md.bak"},
+				{Path: "cmd/root.go", Backup: "cmd/root.go.bak"},
+			},
+		},
+		"tree": {
+			mode: BackupTree,
+			backups: []Backup{
+				{Path: "README.md", Backup: ".blueprint/backups/20260301T123000Z/README.md"},
+				{Path: "cmd/root.go", Backup: ".blueprint/backups/20260301T123000Z/cmd/root.go"},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "cmd"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("mine"), 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(root, "cmd", "root.go"), []byte("package mine"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module app"), 0644))
+
+			writer := NewWriter().WithBackups(newBackups(tt.mode, root, now))
+			result, err := writer.WriteFiles(root, []template.RenderedFile{
+				{Path: "README.md", Content: []byte("generated")},
+				{Path: "cmd/root.go", Content: []byte("package cmd")},
+				{Path: "go.mod", Content: []byte("module app")},
+				{Path: "main.go", Content: []byte("package main")},
+			}, true)
+			require.NoError(t, err)
+
+			// Unchanged and new files are not backed up.
+			assert.Equal(t, tt.backups, result.Backups)
+
+			content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(tt.backups[0].Backup)))
+			require.NoError(t, err)
+			assert.Equal(t, "mine", string(content))
+
+			info, err := os.Stat(filepath.Join(root, filepath.FromSlash(tt.backups[0].Backup)))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		})
+	}
+}
+
+func TestWriter_BackupsRolledBack(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("mine"), 0644))
+
+	journal := NewJournal()
+	writer := NewWriter().WithJournal(journal).WithBackups(newBackups(BackupTree, root, time.Now()))
+	_, err := writer.WriteFiles(root, []template.RenderedFile{
+		{Path: "README.md", Content: []byte("generated")},
+	}, true)
+	require.NoError(t, err)
+
+	require.NoError(t, journal.Rollback())
+
+	content, err := os.ReadFile(filepath.Join(root, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine", string(content))
+	assert.NoDirExists(t, filepath.Join(root, ".blueprint"))
+}
+
+func TestParseBackupMode(t *testing.T) {
+	mode, err := ParseBackupMode("")
+	require.NoError(t, err)
+	assert.Equal(t, BackupSuffix, mode)
+
+	mode, err = ParseBackupMode("tree")
+	require.NoError(t, err)
+	assert.Equal(t, BackupTree, mode)
+
+	_, err = ParseBackupMode("copy")
+	assert.Error(t, err)
+}
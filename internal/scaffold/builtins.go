@@ -0,0 +1,33 @@
+package scaffold
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/version"
+)
+
+// DetectBuiltins gathers the values of the builtin variable from the host.
+func DetectBuiltins() template.Builtins {
+	return template.Builtins{
+		Now:          time.Now(),
+		Version:      version.Version,
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		GitUserName:  gitConfig("user.name"),
+		GitUserEmail: gitConfig("user.email"),
+	}
+}
+
+// gitConfig returns a value of the git configuration, or an empty string when
+// it is not set or git is not installed.
+func gitConfig(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,81 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBuiltinScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.2.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+includes:
+  - name: linux
+    enabled_by_default: true
+    when: '{{ eq ._blueprint.os "linux" }}'
+files:
+  - src: LICENSE.tmpl
+    dest: LICENSE
+`,
+		"app/LICENSE.tmpl": "Copyright {{ ._blueprint.year }} {{ ._blueprint.git.name }} <{{ ._blueprint.git.email }}>\n" +
+			"{{ ._blueprint.template.name }} {{ ._blueprint.template.version }} on {{ ._blueprint.date }}\n",
+		"linux/" + template.FileName: `name: linux
+type: feature
+version: 0.1.0
+description: Linux support
+files:
+  - src: linux.txt.tmpl
+    dest: linux.txt
+`,
+		"linux/linux.txt.tmpl": "{{ ._blueprint.template.name }}\n",
+	})
+	s.builtins = func() template.Builtins {
+		return template.Builtins{
+			Now:          time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+			OS:           "linux",
+			GitUserName:  "Ada",
+			GitUserEmail: "ada@example.com",
+		}
+	}
+	return s
+}
+
+func TestScaffoldBuiltinVariables(t *testing.T) {
+	s := newBuiltinScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out})
+	require.NoError(t, err)
+
+	license, err := os.ReadFile(filepath.Join(out, "LICENSE"))
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright 2024 Ada <ada@example.com>\napp 1.2.0 on 2024-03-09\n", string(license))
+
+	// Each template sees its own name, and include conditions can use the
+	// builtin values.
+	linux, err := os.ReadFile(filepath.Join(out, "linux.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "linux\n", string(linux))
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	for _, node := range m.Nodes {
+		assert.NotContains(t, node.Variables, template.BuiltinVariable)
+	}
+}
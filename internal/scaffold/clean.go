@@ -0,0 +1,160 @@
+package scaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+)
+
+// CleanResult reports the paths handled by Clean, relative to the project root.
+type CleanResult struct {
+	Removed   []string // Paths removed (or that would be removed in a dry run)
+	Protected []string // Matched paths kept because they hold scaffolded files or protected directories
+}
+
+// cleanProtectedNames are the directories Clean never removes, whatever a
+// pattern matches: the repository and Blueprint's own state.
+var cleanProtectedNames = []string{".git", manifest.Dir}
+
+// Clean removes the generated paths that the templates of the project rooted
+// at root declared as ignorable. Matches are limited to the project, and a
+// match that is, or contains, a file recorded in the manifest, a .git
+// directory, or the .blueprint directory is never removed, so only build
+// output and similar artifacts are deleted. Symbolic links are removed as
+// links, and matches reached through a linked directory are kept.
+func Clean(root string, dryRun bool) (*CleanResult, error) {
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make([]string, 0, len(m.Files)+1)
+	for _, f := range m.Files {
+		recorded = append(recorded, f.Path)
+	}
+	recorded = append(recorded, manifest.Dir+"/"+manifest.FileName)
+
+	seen := make(map[string]bool)
+	result := &CleanResult{}
+
+	for _, node := range m.Nodes {
+		for _, pattern := range node.Clean {
+			matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+			if err != nil {
+				return nil, fmt.Errorf("invalid clean pattern %q: %w", pattern, err)
+			}
+
+			for _, match := range matches {
+				rel, err := filepath.Rel(root, match)
+				if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					continue
+				}
+				rel = filepath.ToSlash(rel)
+
+				if seen[rel] {
+					continue
+				}
+				seen[rel] = true
+
+				if holdsRecordedFile(rel, recorded) || holdsProtectedDir(root, rel) || linkedParent(root, rel) {
+					result.Protected = append(result.Protected, rel)
+					continue
+				}
+				result.Removed = append(result.Removed, rel)
+			}
+		}
+	}
+
+	sort.Strings(result.Removed)
+	sort.Strings(result.Protected)
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, rel := range result.Removed {
+		if err := removeCleanPath(filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+	}
+
+	return result, nil
+}
+
+// holdsRecordedFile reports whether rel is one of the recorded paths or a
+// directory containing one.
+func holdsRecordedFile(rel string, recorded []string) bool {
+	for _, p := range recorded {
+		if p == rel || strings.HasPrefix(p, rel+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsProtectedDir reports whether rel is, lies in, or contains one of the
+// protected directories. Symbolic links are not followed.
+func holdsProtectedDir(root, rel string) bool {
+	for _, elem := range strings.Split(rel, "/") {
+		if slices.Contains(cleanProtectedNames, elem) {
+			return true
+		}
+	}
+
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	info, err := os.Lstat(full)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	found := false
+	err = filepath.WalkDir(full, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && slices.Contains(cleanProtectedNames, d.Name()) {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	// A directory that cannot be fully read is kept rather than removed blind.
+	return found || err != nil
+}
+
+// linkedParent reports whether a directory between root and rel is a
+// symbolic link, so that removing rel would delete files outside the project.
+func linkedParent(root, rel string) bool {
+	dir := root
+	elems := strings.Split(rel, "/")
+	for _, elem := range elems[:len(elems)-1] {
+		dir = filepath.Join(dir, elem)
+		info, err := os.Lstat(dir)
+		if err != nil || info.Mode()&fs.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// removeCleanPath removes a matched path. A symbolic link is removed itself,
+// never what it points to.
+func removeCleanPath(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return os.Remove(path)
+	}
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,115 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCleanFixture(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(root, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(p), 0644))
+	}
+}
+
+func TestClean(t *testing.T) {
+	root := t.TempDir()
+
+	writeCleanFixture(t, root,
+		"main.go",
+		"bin/app",
+		"dist/app.tar.gz",
+		"coverage.out",
+		"docs/guide.md",
+		"service/bin/service",
+	)
+
+	m := &manifest.Manifest{
+		SchemaVersion: manifest.SchemaVersion,
+		Template:      "go-cli",
+		Nodes: []manifest.Node{
+			{ID: "0", Template: "go-cli", Clean: []string{"bin", "dist", "*.out", "docs", "main.go"}},
+			{ID: "0.0", Template: "service", Clean: []string{"service/bin", "missing"}},
+		},
+		Files: []manifest.File{
+			{Path: "main.go", Node: "0"},
+			{Path: "docs/guide.md", Node: "0"},
+		},
+	}
+	require.NoError(t, m.Save(root))
+
+	t.Run("dry run only reports", func(t *testing.T) {
+		result, err := Clean(root, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"bin", "coverage.out", "dist", "service/bin"}, result.Removed)
+		assert.Equal(t, []string{"docs", "main.go"}, result.Protected)
+		assert.DirExists(t, filepath.Join(root, "bin"))
+	})
+
+	t.Run("removes generated paths only", func(t *testing.T) {
+		_, err := Clean(root, false)
+		require.NoError(t, err)
+
+		assert.NoDirExists(t, filepath.Join(root, "bin"))
+		assert.NoDirExists(t, filepath.Join(root, "dist"))
+		assert.NoDirExists(t, filepath.Join(root, "service", "bin"))
+		assert.NoFileExists(t, filepath.Join(root, "coverage.out"))
+
+		assert.FileExists(t, filepath.Join(root, "main.go"))
+		assert.FileExists(t, filepath.Join(root, "docs", "guide.md"))
+		assert.FileExists(t, manifest.Path(root))
+	})
+}
+
+func TestCleanWildcardPattern(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	writeCleanFixture(t, root,
+		"main.go",
+		"notes.txt",
+		".git/HEAD",
+		"vendor/.git/HEAD",
+		"build/out.bin",
+	)
+	writeCleanFixture(t, outside, "keep.txt", "pkg/lib.a")
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "linked")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "keep.txt"), filepath.Join(root, "keep.txt")))
+
+	m := &manifest.Manifest{
+		SchemaVersion: manifest.SchemaVersion,
+		Template:      "go-cli",
+		Nodes: []manifest.Node{
+			{ID: "0", Template: "go-cli", Clean: []string{"*", ".*", "*/pkg"}},
+		},
+		Files: []manifest.File{
+			{Path: "main.go", Node: "0"},
+		},
+	}
+	require.NoError(t, m.Save(root))
+
+	result, err := Clean(root, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"build", "keep.txt", "linked", "notes.txt"}, result.Removed)
+	assert.Equal(t, []string{".blueprint", ".git", "linked/pkg", "main.go", "vendor"}, result.Protected)
+
+	assert.FileExists(t, filepath.Join(root, "main.go"))
+	assert.FileExists(t, filepath.Join(root, ".git", "HEAD"))
+	assert.FileExists(t, filepath.Join(root, "vendor", ".git", "HEAD"))
+	assert.FileExists(t, manifest.Path(root))
+	assert.NoDirExists(t, filepath.Join(root, "build"))
+
+	// Links are removed, not what they point to.
+	assert.NoFileExists(t, filepath.Join(root, "linked"))
+	assert.FileExists(t, filepath.Join(outside, "keep.txt"))
+	assert.FileExists(t, filepath.Join(outside, "pkg", "lib.a"))
+}
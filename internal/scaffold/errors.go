@@ -0,0 +1,188 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LockedError is returned when an output directory is locked by another
+// blueprint process.
+type LockedError struct {
+	Dir      string
+	PID      int
+	Hostname string
+	Created  time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("output directory %s is locked by process %d on %s since %s",
+		e.Dir, e.PID, e.Hostname, e.Created.Format(time.RFC3339))
+}
+
+// RolledBackError is returned when scaffolding failed and the changes made to
+// the output directory were rolled back.
+type RolledBackError struct {
+	Dir         string
+	Err         error
+	RollbackErr error // Set when the rollback itself failed
+}
+
+func (e *RolledBackError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("%v (rolling back %s failed: %v)", e.Err, e.Dir, e.RollbackErr)
+	}
+	return fmt.Sprintf("%v (changes to %s were rolled back)", e.Err, e.Dir)
+}
+
+func (e *RolledBackError) Unwrap() error {
+	return e.Err
+}
+
+// NothingToUndoError is returned by Undo when a project has no journaled run.
+type NothingToUndoError struct {
+	Dir string
+}
+
+func (e *NothingToUndoError) Error() string {
+	return fmt.Sprintf("no scaffold run to undo in %s", e.Dir)
+}
+
+// UndoConflictError is returned by Undo when files the run wrote were changed
+// since. Paths are relative to the project root, or absolute outside it.
+type UndoConflictError struct {
+	Dir   string
+	Files []string
+}
+
+func (e *UndoConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) in %s changed since the last run", len(e.Files), e.Dir)
+}
+
+// TestFailedError is returned when test cases of a template failed.
+type TestFailedError struct {
+	Template string
+	Failed   int
+	Total    int
+}
+
+func (e *TestFailedError) Error() string {
+	return fmt.Sprintf("%d of %d test case(s) of template %s failed", e.Failed, e.Total, e.Template)
+}
+
+// AlreadyAddedError is returned by Add when the template is already part of
+// the project.
+type AlreadyAddedError struct {
+	Template string
+	Node     string
+}
+
+func (e *AlreadyAddedError) Error() string {
+	return fmt.Sprintf("%s is already part of the project (node %s)", e.Template, e.Node)
+}
+
+// ComponentNotFoundError is returned by Regen when the project has no
+// component generation of the given name.
+type ComponentNotFoundError struct {
+	Name string
+	Dir  string
+}
+
+func (e *ComponentNotFoundError) Error() string {
+	return fmt.Sprintf("no component named %q in %s", e.Name, e.Dir)
+}
+
+// RegenConflictError is returned by Regen when files of the component were
+// changed since it was generated. Paths are relative to the project root.
+type RegenConflictError struct {
+	Component string
+	Files     []string
+}
+
+func (e *RegenConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) of component %s changed since it was generated", len(e.Files), e.Component)
+}
+
+// NotAddedError is returned by Remove when the project has neither a
+// component generation nor an added template of the given name.
+type NotAddedError struct {
+	Name string
+	Dir  string
+}
+
+func (e *NotAddedError) Error() string {
+	return fmt.Sprintf("%s is not part of the project in %s", e.Name, e.Dir)
+}
+
+// RemoveConflictError is returned by Remove when files of the template were
+// changed since it wrote them, or no longer hold the code it injected. Paths
+// are relative to the project root.
+type RemoveConflictError struct {
+	Template string
+	Files    []string
+}
+
+func (e *RemoveConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) of %s changed since it was added", len(e.Files), e.Template)
+}
+
+// SmokeFailedError is returned by the smoke command when post-init or verify
+// commands of the scaffolded project failed.
+type SmokeFailedError struct {
+	Template string
+	Failed   int
+	Total    int
+}
+
+func (e *SmokeFailedError) Error() string {
+	return fmt.Sprintf("%d of %d verify command(s) of template %s failed", e.Failed, e.Total, e.Template)
+}
+
+// MissingToolsError is returned when tools the templates of the tree require
+// are not installed, or not in a version they accept.
+type MissingToolsError struct {
+	Checks []ToolCheck // Failed checks
+}
+
+func (e *MissingToolsError) Error() string {
+	problems := make([]string, len(e.Checks))
+	for i, check := range e.Checks {
+		problems[i] = fmt.Sprintf("%s: %s", check.Tool, check.Problem)
+	}
+	return "missing required tools: " + strings.Join(problems, "; ")
+}
+
+// ToolCheckFailedError is returned by blueprint doctor when tools a template
+// requires are missing or not in a version it accepts.
+type ToolCheckFailedError struct {
+	Template string
+	Failed   int
+	Total    int
+}
+
+func (e *ToolCheckFailedError) Error() string {
+	return fmt.Sprintf("%d of %d tool(s) required by template %s are missing or outdated", e.Failed, e.Total, e.Template)
+}
+
+// PostInitDeniedError is returned when the post-init policy refuses a command
+// of the tree, because one of the commands it chains matches a denied pattern
+// or no allowed one, or because it uses an operator the policy cannot check.
+type PostInitDeniedError struct {
+	Command  string
+	Template string
+	Part     string // Command of the chain that was refused; empty if Operator is set
+	Pattern  string // Denied pattern Part matches; empty if it matches no allowed pattern
+	Operator string // Shell operator of Command that patterns cannot check, such as $( or >
+}
+
+func (e *PostInitDeniedError) Error() string {
+	switch {
+	case e.Operator != "":
+		return fmt.Sprintf("post-init command %q of template %s uses %s, which the post-init policy cannot check",
+			e.Command, e.Template, e.Operator)
+	case e.Pattern != "":
+		return fmt.Sprintf("post-init command %q of template %s is denied by %q", e.Command, e.Template, e.Pattern)
+	default:
+		return fmt.Sprintf("post-init command %q of template %s is not allowed", e.Command, e.Template)
+	}
+}
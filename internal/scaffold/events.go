@@ -0,0 +1,117 @@
+package scaffold
+
+import (
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// EventKind identifies a step of a scaffold run.
+type EventKind string
+
+const (
+	EventTemplateLoaded    EventKind = "template_loaded"    // A template of the tree was loaded and its variables are collected next
+	EventVariableCollected EventKind = "variable_collected" // A variable of a template has its value
+	EventFileRendered      EventKind = "file_rendered"      // The content of a file was rendered
+	EventFileWritten       EventKind = "file_written"       // A file was written
+	EventPostInitStarted   EventKind = "post_init_started"  // A post-init command started
+	EventPostInitFinished  EventKind = "post_init_finished" // A post-init command finished
+)
+
+// Event reports the progress of a scaffold run to Options.OnEvent. Only the
+// fields that apply to the kind of event are set.
+type Event struct {
+	Kind     EventKind
+	Template string // Template the event concerns
+	Node     string // ID of the template's node in the tree
+
+	Variable string // Name of the collected variable
+	Value    any    // Value of the collected variable; never set for secrets
+
+	Path  string // File path, relative to the output directory unless the file is written outside it
+	Index int    // Position of the file among the files of the run, from 1
+	Total int    // Number of files planned for the run; files that are skipped are neither rendered nor written
+
+	Command string         // Post-init command
+	Dir     string         // Directory the post-init command runs in
+	Status  PostInitStatus // Outcome of a finished post-init command
+	Err     error          // Error of a failed post-init command
+}
+
+// emit reports an event to the callback of the options, if any.
+func (o Options) emit(e Event) {
+	if o.OnEvent != nil {
+		o.OnEvent(e)
+	}
+}
+
+// emitVariables reports the variables a node declares that have a value.
+func (o Options) emitVariables(node *template.TemplateNode, ctx *template.Context) {
+	if o.OnEvent == nil || ctx == nil {
+		return
+	}
+	for _, v := range node.Template.Variables {
+		value, ok := ctx.Variables[v.Name]
+		if !ok {
+			continue
+		}
+		e := Event{Kind: EventVariableCollected, Template: node.Template.Name, Node: node.ID, Variable: v.Name}
+		if !v.IsSecret() {
+			e.Value = value
+		}
+		o.OnEvent(e)
+	}
+}
+
+// fileProgress numbers the files of a run for their events.
+type fileProgress struct {
+	opts      Options
+	outputDir string
+	total     int
+	rendered  int
+	written   int
+}
+
+func newFileProgress(opts Options, outputDir string, renderResult *template.RenderResult) *fileProgress {
+	total := 0
+	for _, files := range renderResult.Files {
+		total += len(files)
+	}
+	return &fileProgress{opts: opts, outputDir: outputDir, total: total}
+}
+
+// path returns the path of a file written into nodeDir for events.
+func (p *fileProgress) path(nodeDir string, file template.RenderedFile) string {
+	if filepath.IsAbs(file.Path) {
+		return file.Path
+	}
+	rel, err := filepath.Rel(p.outputDir, filepath.Join(nodeDir, file.Path))
+	if err != nil {
+		return file.Path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (p *fileProgress) fileRendered(node *template.TemplateNode, nodeDir string, file template.RenderedFile) {
+	p.rendered++
+	p.opts.emit(Event{
+		Kind:     EventFileRendered,
+		Template: node.Template.Name,
+		Node:     node.ID,
+		Path:     p.path(nodeDir, file),
+		Index:    p.rendered,
+		Total:    p.total,
+	})
+}
+
+func (p *fileProgress) fileWritten(node *template.TemplateNode, nodeDir string, file template.RenderedFile) {
+	p.written++
+	p.opts.emit(Event{
+		Kind:     EventFileWritten,
+		Template: node.Template.Name,
+		Node:     node.ID,
+		Path:     p.path(nodeDir, file),
+		Index:    p.written,
+		Total:    p.total,
+	})
+}
@@ -0,0 +1,47 @@
+package scaffold
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldReportsEvents(t *testing.T) {
+	s := newPostInitScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	var events []Event
+	_, err := s.Scaffold(Options{
+		TemplateRef: template.TemplateRef{Name: "app"},
+		OutputDir:   out,
+		KeepPartial: true,
+		OnEvent:     func(e Event) { events = append(events, e) },
+	})
+	require.NoError(t, err)
+
+	kinds := make([]EventKind, len(events))
+	for i, e := range events {
+		kinds[i] = e.Kind
+	}
+	assert.Equal(t, []EventKind{
+		EventTemplateLoaded,
+		EventVariableCollected,
+		EventFileRendered,
+		EventFileWritten,
+		EventPostInitStarted, EventPostInitFinished,
+		EventPostInitStarted, EventPostInitFinished,
+		EventPostInitStarted, EventPostInitFinished,
+	}, kinds)
+
+	assert.Equal(t, Event{Kind: EventTemplateLoaded, Template: "app", Node: "0"}, events[0])
+	assert.Equal(t, Event{Kind: EventVariableCollected, Template: "app", Node: "0", Variable: "name", Value: "app"}, events[1])
+	assert.Equal(t, Event{Kind: EventFileWritten, Template: "app", Node: "0", Path: "README.md", Index: 1, Total: 1}, events[3])
+
+	assert.Equal(t, PostInitSucceeded, events[5].Status)
+	assert.Equal(t, PostInitFailed, events[9].Status)
+	assert.Equal(t, "test -f ready", events[9].Command)
+	assert.Error(t, events[9].Err)
+}
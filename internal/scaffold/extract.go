@@ -0,0 +1,536 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"gopkg.in/yaml.v3"
+)
+
+// ExtractOptions describes files of a template to move into a new include.
+type ExtractOptions struct {
+	TemplateDir string                     // Directory of the template the files are taken from
+	Name        string                     // Name of the new include template
+	Dir         string                     // Directory the include is created in
+	Files       []string                   // Src values of the file entries to move
+	Variables   vars.Variables             // Values used to render the template for verification
+	Defaults    map[string]any             // Variable defaults from the user configuration
+	Functions   []string                   // Function libraries enabled in the configuration
+	Sources     []resolver.Source          // Sources the includes of the template are resolved from
+	Mandated    map[template.Type][]string // Includes mandated per template type
+}
+
+// ExtractResult describes an include created from the files of a template.
+type ExtractResult struct {
+	Template  string   // Name of the template the files were taken from
+	Moved     []string // Moved source paths, relative to the template directory
+	Variables []string // Variables the include declares and inherits
+	Verified  int      // Number of rendered files compared before and after
+}
+
+// ExtractInclude moves the file entries listed in opts.Files, and the files
+// they refer to, from the template at opts.TemplateDir into a new feature
+// template at opts.Dir. The include declares the variables the moved files
+// refer to and inherits them from the template, which includes it by default.
+//
+// The template is rendered before and after the change, and every file must
+// come out identical. If not, all changes are undone and an error lists the
+// files that differ.
+func ExtractInclude(opts ExtractOptions) (result *ExtractResult, err error) {
+	if !template.IsValidName(opts.Name) {
+		return nil, fmt.Errorf("invalid template name %q: use lowercase letters, digits, and dashes", opts.Name)
+	}
+	if len(opts.Files) == 0 {
+		return nil, fmt.Errorf("no files to extract")
+	}
+
+	templateDir, err := filepath.Abs(opts.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	includeDir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(includeDir)
+	if err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", opts.Dir)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read directory %s: %w", opts.Dir, err)
+	}
+
+	loaded, err := template.NewLoader().Load(os.DirFS(templateDir), ".")
+	if err != nil {
+		return nil, err
+	}
+	parent := loaded.Template
+
+	for _, inc := range parent.Includes {
+		if inc.Name == opts.Name {
+			return nil, fmt.Errorf("template %s already includes %s", parent.Name, opts.Name)
+		}
+	}
+
+	moved, kept, err := splitFiles(parent.Files, opts.Files)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", parent.Name, err)
+	}
+
+	scaffolder, err := extractScaffolder(opts, templateDir, includeDir)
+	if err != nil {
+		return nil, err
+	}
+	renderOpts := Options{
+		TemplateRef: template.TemplateRef{Name: parent.Name},
+		Variables:   opts.Variables,
+		Defaults:    opts.Defaults,
+		Mandated:    opts.Mandated,
+		DryRun:      true,
+	}
+
+	before, err := renderedContents(scaffolder, renderOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s before extracting: %w", parent.Name, err)
+	}
+
+	include, err := includeTemplate(scaffolder, loaded, opts.Name, moved)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := NewJournal()
+	defer func() {
+		if err != nil {
+			if rbErr := journal.Rollback(); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+	writer := NewWriter().WithJournal(journal)
+
+	result = &ExtractResult{Template: parent.Name}
+	for _, v := range include.Variables {
+		result.Variables = append(result.Variables, v.Name)
+	}
+
+	if err := writeIncludeManifest(writer, includeDir, include); err != nil {
+		return nil, err
+	}
+
+	sources := []string{template.PartialsDir}
+	for _, f := range moved {
+		sources = append(sources, f.Src)
+	}
+	for i, src := range sources {
+		copied, err := moveTemplateFiles(writer, journal, templateDir, includeDir, src, i > 0)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			result.Moved = append(result.Moved, copied...)
+		}
+	}
+
+	manifestPath := filepath.Join(templateDir, template.FileName)
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+	rewritten, err := rewriteParentManifest(data, moved, template.Include{
+		Name:             opts.Name,
+		EnabledByDefault: true,
+		Inherits:         inheritAll(result.Variables),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.WriteFile(manifestPath, rewritten); err != nil {
+		return nil, err
+	}
+
+	after, err := renderedContents(scaffolder, renderOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s after extracting: %w", parent.Name, err)
+	}
+	if diff := diffContents(before, after); len(diff) > 0 {
+		return nil, fmt.Errorf("extracting changes the rendered output of %s: %s", parent.Name, strings.Join(diff, ", "))
+	}
+	result.Verified = len(before)
+
+	keep := make(map[string]bool, len(kept))
+	for _, f := range kept {
+		keep[path.Clean(f.Src)] = true
+	}
+	removeEmptyDirs(templateDir, result.Moved, keep)
+	return result, nil
+}
+
+// splitFiles separates the file entries whose src is listed from the rest.
+// A listed source must exist and must not be shared with an entry that stays.
+func splitFiles(files []template.File, srcs []string) (moved, kept []template.File, err error) {
+	listed := make(map[string]bool, len(srcs))
+	for _, src := range srcs {
+		listed[path.Clean(src)] = true
+	}
+
+	found := make(map[string]bool)
+	for _, f := range files {
+		if listed[path.Clean(f.Src)] {
+			moved = append(moved, f)
+			found[path.Clean(f.Src)] = true
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	for _, src := range srcs {
+		if !found[path.Clean(src)] {
+			return nil, nil, fmt.Errorf("no file entry with src %q", src)
+		}
+	}
+
+	for _, m := range moved {
+		for _, k := range kept {
+			if pathsOverlap(path.Clean(m.Src), path.Clean(k.Src)) {
+				return nil, nil, fmt.Errorf("%s is also used by the file entry with src %q", m.Src, k.Src)
+			}
+		}
+	}
+
+	return moved, kept, nil
+}
+
+// pathsOverlap reports whether a and b are the same path or one contains the other.
+func pathsOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// extractScaffolder returns a scaffolder that resolves the template and the
+// new include from their directories before the configured sources.
+func extractScaffolder(opts ExtractOptions, templateDir, includeDir string) (*Scaffolder, error) {
+	var sources []resolver.Source
+	for _, dir := range []string{filepath.Dir(templateDir), filepath.Dir(includeDir)} {
+		if len(sources) > 0 && sources[0].Dir == dir {
+			continue
+		}
+		sources = append(sources, resolver.Source{
+			Name:       "LOCAL",
+			Type:       resolver.SourceTypeUser,
+			Filesystem: os.DirFS(dir),
+			Dir:        dir,
+		})
+	}
+	sources = append(sources, opts.Sources...)
+
+	scaffolder := NewScaffolder(resolver.NewChainResolver(sources...))
+	if err := scaffolder.EnableFuncLibraries(opts.Functions...); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return scaffolder, nil
+}
+
+// renderedFile is the content and mode of a rendered file.
+type renderedFile struct {
+	content []byte
+	mode    fs.FileMode
+}
+
+// renderedContents renders the template tree for opts and returns the content
+// of every file by output path.
+func renderedContents(s *Scaffolder, opts Options) (map[string]renderedFile, error) {
+	rendered, err := s.Render(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]renderedFile, len(rendered.Files))
+	for _, f := range rendered.Files {
+		content, err := f.Load()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Path] = renderedFile{content: content, mode: f.Mode}
+	}
+	return files, nil
+}
+
+// diffContents returns the sorted paths of the files that are missing from,
+// added to, or different in after.
+func diffContents(before, after map[string]renderedFile) []string {
+	var diff []string
+	for p, b := range before {
+		a, ok := after[p]
+		switch {
+		case !ok:
+			diff = append(diff, p+" (missing)")
+		case !bytes.Equal(a.content, b.content) || a.mode != b.mode:
+			diff = append(diff, p)
+		}
+	}
+	for p := range after {
+		if _, ok := before[p]; !ok {
+			diff = append(diff, p+" (added)")
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// includeTemplate builds the manifest of the include. It declares the
+// variables of the parent that the moved files refer to, and shares the
+// parent's function libraries, delimiters, and license header.
+func includeTemplate(s *Scaffolder, parent *template.LoadedTemplate, name string, moved []template.File) (*template.Template, error) {
+	include := &template.Template{
+		Name:               name,
+		Type:               template.TypeFeature,
+		Version:            parent.Template.Version,
+		Description:        fmt.Sprintf("Extracted from %s", parent.Template.Name),
+		Files:              moved,
+		Functions:          parent.Template.Functions,
+		Delimiters:         parent.Template.Delimiters,
+		AllowOutsideOutput: parent.Template.AllowOutsideOutput,
+		LicenseHeader:      parent.Template.LicenseHeader,
+	}
+
+	analysis, err := s.engine.AnalyzeTree(&template.TemplateNode{
+		ID:       "0",
+		Template: include,
+		FS:       parent.FS,
+		Path:     parent.Path,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", parent.Template.Name, err)
+	}
+
+	used := make(map[string]bool)
+	for _, node := range analysis.Nodes {
+		for _, v := range node.Variables {
+			used[v] = true
+		}
+	}
+
+	for _, v := range parent.Template.Variables {
+		if !used[v.Name] {
+			continue
+		}
+		v.Role = ""
+		include.Variables = append(include.Variables, v)
+	}
+
+	return include, nil
+}
+
+// inheritAll maps each variable to the parent variable of the same name.
+func inheritAll(names []string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	inherits := make(map[string]string, len(names))
+	for _, n := range names {
+		inherits[n] = n
+	}
+	return inherits
+}
+
+// writeIncludeManifest writes the template.yaml of the include.
+func writeIncludeManifest(w *Writer, dir string, include *template.Template) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(include); err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	return w.WriteFile(filepath.Join(dir, template.FileName), buf.Bytes())
+}
+
+// moveTemplateFiles moves the file or directory src of the template at
+// fromDir to the same path in toDir, and returns the moved files relative to
+// fromDir. When required is false, a missing src is not an error and its
+// files are copied rather than moved.
+func moveTemplateFiles(w *Writer, j *Journal, fromDir, toDir, src string, required bool) ([]string, error) {
+	root := filepath.Join(fromDir, filepath.FromSlash(src))
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) && !required {
+		return nil, nil
+	}
+
+	var moved []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(fromDir, p)
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+
+		if err := w.WriteFileWithPerm(filepath.Join(toDir, rel), content, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		if !required {
+			return nil
+		}
+
+		if err := j.RecordFile(p); err != nil {
+			return err
+		}
+		if err := os.Remove(p); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		moved = append(moved, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return moved, nil
+}
+
+// removeEmptyDirs removes the directories of the given files, relative to
+// root, that are left empty, deepest first. Directories in keep are left in
+// place.
+func removeEmptyDirs(root string, files []string, keep map[string]bool) {
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		for dir := path.Dir(f); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	// Deepest first, so that parents are empty by the time they are reached.
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, dir := range sorted {
+		if keep[dir] {
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(dir))
+		if entries, err := os.ReadDir(full); err == nil && len(entries) == 0 {
+			_ = os.Remove(full)
+		}
+	}
+}
+
+// rewriteParentManifest removes the moved file entries from the template.yaml
+// in data and adds include to its includes. The manifest is edited as a YAML
+// node tree so that comments and the order of fields are kept.
+func rewriteParentManifest(data []byte, moved []template.File, include template.Include) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse template YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("template.yaml is not a mapping")
+	}
+	root := doc.Content[0]
+
+	movedSrcs := make(map[string]bool, len(moved))
+	for _, f := range moved {
+		movedSrcs[f.Src] = true
+	}
+
+	filesIdx := mappingIndex(root, "files")
+	if filesIdx >= 0 {
+		files := root.Content[filesIdx+1]
+		var remaining []*yaml.Node
+		for _, entry := range files.Content {
+			if src := mappingValue(entry, "src"); src != nil && movedSrcs[src.Value] {
+				continue
+			}
+			remaining = append(remaining, entry)
+		}
+		files.Content = remaining
+
+		if len(remaining) == 0 {
+			root.Content = append(root.Content[:filesIdx], root.Content[filesIdx+2:]...)
+		}
+	}
+
+	var entry yaml.Node
+	if err := entry.Encode(include); err != nil {
+		return nil, fmt.Errorf("failed to encode include: %w", err)
+	}
+
+	if idx := mappingIndex(root, "includes"); idx >= 0 {
+		includes := root.Content[idx+1]
+		if includes.Kind != yaml.SequenceNode {
+			includes.Kind, includes.Tag, includes.Value = yaml.SequenceNode, "!!seq", ""
+		}
+		includes.Style = 0
+		includes.Content = append(includes.Content, &entry)
+	} else {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "includes"}
+		value := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{&entry}}
+
+		at := len(root.Content)
+		if idx := mappingIndex(root, "files"); idx >= 0 {
+			at = idx
+		}
+		root.Content = append(root.Content[:at], append([]*yaml.Node{key, value}, root.Content[at:]...)...)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingIndex returns the index of the key node in a mapping node, or -1.
+func mappingIndex(m *yaml.Node, key string) int {
+	if m.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value node of key in a mapping node, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(m, key); i >= 0 {
+		return m.Content[i+1]
+	}
+	return nil
+}
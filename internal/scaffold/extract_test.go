@@ -0,0 +1,152 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const extractManifest = `# The service template.
+name: svc
+type: project
+version: 1.2.0
+description: A service
+
+variables:
+  - name: project_name
+    prompt: Project name?
+    type: string
+    role: project_name
+  - name: port
+    prompt: Port?
+    type: int
+    default: 8080
+
+files:
+  - src: main.go.tmpl
+    dest: main.go
+  # CI configuration
+  - src: ci
+    dest: .ci
+  - src: Dockerfile.tmpl
+    dest: Dockerfile
+`
+
+func writeExtractFixture(t *testing.T) string {
+	t.Helper()
+	base := t.TempDir()
+	dir := filepath.Join(base, "svc")
+
+	files := map[string]string{
+		template.FileName:  extractManifest,
+		"main.go.tmpl":     "package main // {{ .project_name }}\n",
+		"ci/build.yml":     "build\n",
+		"ci/lint.yml.tmpl": "lint {{ .project_name }}\n",
+		"Dockerfile.tmpl":  "EXPOSE {{ .port }}\n",
+	}
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestExtractInclude(t *testing.T) {
+	dir := writeExtractFixture(t)
+	includeDir := filepath.Join(filepath.Dir(dir), "svc-ci")
+
+	result, err := ExtractInclude(ExtractOptions{
+		TemplateDir: dir,
+		Name:        "svc-ci",
+		Dir:         includeDir,
+		Files:       []string{"ci", "Dockerfile.tmpl"},
+		Variables:   vars.Variables{Global: map[string]string{"project_name": "demo"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "svc", result.Template)
+	assert.ElementsMatch(t, []string{"ci/build.yml", "ci/lint.yml.tmpl", "Dockerfile.tmpl"}, result.Moved)
+	assert.Equal(t, []string{"project_name", "port"}, result.Variables)
+	assert.Equal(t, 4, result.Verified)
+
+	assert.NoDirExists(t, filepath.Join(dir, "ci"))
+	assert.FileExists(t, filepath.Join(includeDir, "ci", "lint.yml.tmpl"))
+
+	parent, err := template.NewLoader().Load(os.DirFS(dir), ".")
+	require.NoError(t, err)
+	assert.Equal(t, []template.File{{Src: "main.go.tmpl", Dest: "main.go"}}, parent.Template.Files)
+	assert.Equal(t, []template.Include{{
+		Name:             "svc-ci",
+		EnabledByDefault: true,
+		Inherits:         map[string]string{"project_name": "project_name", "port": "port"},
+	}}, parent.Template.Includes)
+
+	data, err := os.ReadFile(filepath.Join(dir, template.FileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# The service template.")
+
+	include, err := template.NewLoader().Load(os.DirFS(includeDir), ".")
+	require.NoError(t, err)
+	assert.Equal(t, template.TypeFeature, include.Template.Type)
+	assert.Equal(t, "1.2.0", include.Template.Version)
+	require.Len(t, include.Template.Variables, 2)
+	assert.Empty(t, include.Template.Variables[0].Role)
+}
+
+func TestExtractInclude_RollsBackWhenOutputChanges(t *testing.T) {
+	dir := writeExtractFixture(t)
+	base := filepath.Dir(dir)
+	writeCleanFixture(t, filepath.Join(base, "notice"), "NOTICE")
+	require.NoError(t, os.WriteFile(filepath.Join(base, "notice", template.FileName), []byte(
+		"name: notice\ntype: component\nversion: 1.0.0\nfiles:\n  - src: NOTICE\n    dest: NOTICE\n"), 0644))
+	original, err := os.ReadFile(filepath.Join(dir, template.FileName))
+	require.NoError(t, err)
+
+	// Mandated includes of features are composed into the new include only.
+	_, err = ExtractInclude(ExtractOptions{
+		TemplateDir: dir,
+		Name:        "svc-ci",
+		Dir:         filepath.Join(base, "svc-ci"),
+		Files:       []string{"ci"},
+		Variables:   vars.Variables{Global: map[string]string{"project_name": "demo"}},
+		Mandated:    map[template.Type][]string{template.TypeFeature: {"notice"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NOTICE (added)")
+
+	assert.NoDirExists(t, filepath.Join(base, "svc-ci"))
+	assert.FileExists(t, filepath.Join(dir, "ci", "build.yml"))
+	data, err := os.ReadFile(filepath.Join(dir, template.FileName))
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(data))
+}
+
+func TestExtractInclude_UnknownFile(t *testing.T) {
+	dir := writeExtractFixture(t)
+
+	_, err := ExtractInclude(ExtractOptions{
+		TemplateDir: dir,
+		Name:        "svc-ci",
+		Dir:         filepath.Join(filepath.Dir(dir), "svc-ci"),
+		Files:       []string{"missing.tmpl"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no file entry with src "missing.tmpl"`)
+}
+
+func TestSplitFiles_SharedSource(t *testing.T) {
+	files := []template.File{
+		{Src: "ci", Dest: ".ci"},
+		{Src: "ci/build.yml", Dest: "build.yml"},
+	}
+
+	_, _, err := splitFiles(files, []string{"ci"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "also used")
+}
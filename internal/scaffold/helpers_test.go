@@ -0,0 +1,29 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestScaffolder writes files, keyed by slash-separated path, into a
+// temporary directory and returns a scaffolder resolving templates from it.
+func newTestScaffolder(t *testing.T, files map[string]string) *Scaffolder {
+	t.Helper()
+	dir := t.TempDir()
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	return NewScaffolder(resolver.NewChainResolver(resolver.Source{
+		Name:       "LOCAL",
+		Type:       resolver.SourceTypeUser,
+		Filesystem: os.DirFS(dir),
+		Dir:        dir,
+	}))
+}
@@ -0,0 +1,42 @@
+package scaffold
+
+import (
+	"path"
+	"strings"
+)
+
+// Impact counts what a real run would do, from the result of a dry run.
+type Impact struct {
+	Created      int // Files that would be created
+	Overwritten  int // Existing files that would be replaced
+	Outside      int // Files that would be written outside the output directory
+	Bytes        int // Total size of the files that would be written
+	Commands     int // Post-init commands that would run
+	Dependencies int // Dependencies the project would declare
+}
+
+// Impact returns the counts of what the run would do. It is only meaningful
+// for a dry run.
+func (r *Result) Impact() Impact {
+	impact := Impact{
+		Commands:     len(r.PostInitCmds),
+		Dependencies: len(r.Dependencies),
+	}
+
+	for _, f := range r.Planned {
+		switch f.Status {
+		case PlanCreate:
+			impact.Created++
+		case PlanOverwrite:
+			impact.Overwritten++
+		default:
+			continue
+		}
+
+		impact.Bytes += f.Size
+		if path.IsAbs(f.Path) || strings.HasPrefix(f.Path, "../") {
+			impact.Outside++
+		}
+	}
+	return impact
+}
@@ -0,0 +1,32 @@
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultImpact(t *testing.T) {
+	result := &Result{
+		Planned: []PlannedFile{
+			{Path: "main.go", Size: 100, Status: PlanCreate},
+			{Path: "go.mod", Size: 20, Status: PlanOverwrite},
+			{Path: "README.md", Size: 50, Status: PlanUnchanged},
+			{Path: "LICENSE", Size: 1000, Status: PlanSkip},
+			{Path: "../shared/.editorconfig", Size: 5, Status: PlanCreate},
+			{Path: "/home/dev/.config/app.yaml", Size: 7, Status: PlanCreate},
+		},
+		PostInitCmds: []template.PostInit{{Command: "go mod tidy"}, {Command: "git init"}},
+		Dependencies: []string{"github.com/spf13/cobra"},
+	}
+
+	assert.Equal(t, Impact{
+		Created:      3,
+		Overwritten:  1,
+		Outside:      2,
+		Bytes:        132,
+		Commands:     2,
+		Dependencies: 1,
+	}, result.Impact())
+}
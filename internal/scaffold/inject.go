@@ -0,0 +1,168 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// injectFiles injects the snippets of a node into the files on disk they
+// target, relative to nodeDir, and makes its Go edits to them. It returns the
+// paths of the files it changed. A file that already holds a snippet is left
+// as it is.
+func injectFiles(
+	node *template.TemplateNode,
+	snippets []template.RenderedFile,
+	nodeDir string,
+	writer *Writer,
+	recorder *manifestRecorder,
+) ([]string, error) {
+	var changed []string
+	for _, snippet := range snippets {
+		fullPath := filepath.Join(nodeDir, snippet.Path)
+		content, injected, err := injectInto(node, snippet, fullPath)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(content, injected) {
+			continue
+		}
+
+		if err := writer.WriteFile(fullPath, injected); err != nil {
+			return nil, fmt.Errorf("failed to inject into %s: %w", snippet.Path, err)
+		}
+		recorder.recordInjected(node.ID, nodeDir, snippet, injected)
+		if !slices.Contains(changed, snippet.Path) {
+			changed = append(changed, snippet.Path)
+		}
+	}
+	return changed, nil
+}
+
+// injectInto reads the file at fullPath and returns its content before and
+// after injecting the snippet.
+func injectInto(node *template.TemplateNode, snippet template.RenderedFile, fullPath string) ([]byte, []byte, error) {
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: cannot inject into %s: %w", node.Template.Name, snippet.Path, err)
+	}
+
+	injected, err := template.InjectFile(node.Template.Name, content, snippet)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, injected, nil
+}
+
+// planInjection plans the change a snippet makes to the file on disk it
+// targets, on top of the content earlier snippets gave the file, if any. It
+// returns the content of the file with the snippet injected.
+func planInjection(
+	node *template.TemplateNode,
+	snippet template.RenderedFile,
+	dir string,
+	outputDir string,
+	opts Options,
+	injected map[string][]byte,
+) (PlannedFile, []byte, error) {
+	p := PlannedFile{Path: template.OutputPath(dir, filepath.ToSlash(snippet.Path))}
+	fullPath := filepath.Join(outputDir, filepath.FromSlash(p.Path))
+
+	var content []byte
+	var err error
+	if prior, ok := injected[p.Path]; ok {
+		content, err = template.InjectFile(node.Template.Name, prior, snippet)
+	} else {
+		_, content, err = injectInto(node, snippet, fullPath)
+	}
+	if err != nil {
+		return p, nil, err
+	}
+
+	p.Size = len(content)
+	if opts.ShowContent {
+		p.Content = content
+	}
+	return p, content, comparePlanned(&p, fullPath, content, true)
+}
+
+// recordInjected records the content of a file a snippet of the node with
+// the given ID was injected into, and the injection itself. The file keeps
+// the node that wrote it.
+func (r *manifestRecorder) recordInjected(nodeID, nodeDir string, snippet template.RenderedFile, content []byte) {
+	prefix, err := filepath.Rel(r.root, nodeDir)
+	if err != nil {
+		prefix = ""
+	}
+	filePath := filepath.ToSlash(filepath.Join(prefix, snippet.Path))
+	if filePath == ".." || strings.HasPrefix(filePath, "../") {
+		return
+	}
+
+	if r.injected == nil {
+		r.injected = make(map[string]string)
+	}
+	r.injected[filePath] = manifest.HashContent(content)
+	r.recordInjection(nodeID, filePath, snippet)
+}
+
+// recordInjection records a snippet of the node with the given ID that was
+// injected into the file at the project-relative path.
+func (r *manifestRecorder) recordInjection(nodeID, filePath string, snippet template.RenderedFile) {
+	record := manifest.Injection{Path: filePath, Node: nodeID}
+	if edit := snippet.GoEdit; edit != nil {
+		record.GoEdit = edit.Kind()
+		switch record.GoEdit {
+		case template.GoEditImport:
+			record.Name, record.Code = edit.ImportName, edit.Import
+		case template.GoEditField:
+			record.Name, record.Code = edit.Struct, edit.Field
+		case template.GoEditStatement:
+			record.Name, record.Code = edit.Func, edit.Statement
+		}
+	} else {
+		// The snippet was rendered for the injection already, so rendering
+		// it again does not fail.
+		text, err := snippet.Load()
+		if err != nil {
+			return
+		}
+		record.Anchor, record.Code = snippet.Anchor, string(text)
+	}
+
+	if !slices.Contains(r.manifest.Injections, record) {
+		r.manifest.Injections = append(r.manifest.Injections, record)
+	}
+}
+
+// injectedSnippet returns the snippet that an injection was recorded for.
+func injectedSnippet(record manifest.Injection) template.RenderedFile {
+	snippet := template.RenderedFile{Path: record.Path}
+	switch record.GoEdit {
+	case "":
+		snippet.Anchor, snippet.Content = record.Anchor, []byte(record.Code)
+	case template.GoEditImport:
+		snippet.GoEdit = &template.GoEdit{File: record.Path, ImportName: record.Name, Import: record.Code}
+	case template.GoEditField:
+		snippet.GoEdit = &template.GoEdit{File: record.Path, Struct: record.Name, Field: record.Code}
+	case template.GoEditStatement:
+		snippet.GoEdit = &template.GoEdit{File: record.Path, Func: record.Name, Statement: record.Code}
+	}
+	return snippet
+}
+
+// updateInjected updates the hashes of the recorded files that snippets were
+// injected into, so that they do not count as changed by the user.
+func (r *manifestRecorder) updateInjected() {
+	for i, f := range r.manifest.Files {
+		if hash, ok := r.injected[f.Path]; ok {
+			r.manifest.Files[i].Hash = hash
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInjectScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+
+	feature := func(name string) string {
+		return `name: ` + name + `
+type: feature
+version: 1.0.0
+description: Routes of ` + name + `
+files:
+  - src: route.go.tmpl
+    dest: main.go
+    action: inject
+    anchor: routes
+`
+	}
+	return newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+includes:
+  - name: health
+    enabled_by_default: true
+files:
+  - src: main.go
+    dest: main.go
+`,
+		"app/main.go":                  "func routes(r Router) {\n\t// blueprint:routes\n}\n",
+		"health/" + template.FileName:  feature("health"),
+		"health/route.go.tmpl":         `r.Get("/health", health)` + "\n",
+		"metrics/" + template.FileName: feature("metrics"),
+		"metrics/route.go.tmpl":        `r.Get("/metrics", metrics)` + "\n",
+		"logging/" + template.FileName: `name: logging
+type: feature
+version: 1.0.0
+description: Logging
+variables:
+  - name: level
+    prompt: Level?
+    type: string
+    default: Info
+go_edits:
+  - file: server.go
+    import: log/slog
+  - file: server.go
+    struct: Server
+    field: logger *slog.Logger
+  - file: server.go
+    func: Server.routes
+    statement: 'r.Use(logRequests(slog.Level{{ .level }}))'
+`,
+	})
+}
+
+func TestInjectIntoFiles(t *testing.T) {
+	s := newInjectScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	// An included feature injects into a file of the same run.
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, SkipPostInit: true})
+	require.NoError(t, err)
+	mainPath := filepath.Join(out, "main.go")
+	content, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, "func routes(r Router) {\n\tr.Get(\"/health\", health)\n\t// blueprint:routes\n}\n", string(content))
+
+	// An added feature injects into the file on disk.
+	result, err := s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go"}, result.FilesWritten)
+	content, err = os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, "func routes(r Router) {\n\tr.Get(\"/health\", health)\n\tr.Get(\"/metrics\", metrics)\n\t// blueprint:routes\n}\n", string(content))
+
+	// The file stays recorded for the project, with its new content.
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	f, ok := m.FileByPath("main.go")
+	require.True(t, ok)
+	assert.Equal(t, "0", f.Node)
+	assert.Equal(t, manifest.HashContent(content), f.Hash)
+
+	// Adding again does not inject twice.
+	result, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out, Overwrite: true})
+	require.NoError(t, err)
+	assert.Empty(t, result.FilesWritten)
+	again, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(again))
+}
+
+func TestInjectRequiresAnchor(t *testing.T) {
+	s := newInjectScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, SkipPostInit: true})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(out, "main.go"), []byte("func routes(r Router) {}\n"), 0644))
+
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	var anchorErr *template.AnchorNotFoundError
+	require.ErrorAs(t, err, &anchorErr)
+	assert.Equal(t, "metrics", anchorErr.Template)
+	assert.Equal(t, "main.go", anchorErr.Path)
+}
+
+func TestGoEdits(t *testing.T) {
+	s := newInjectScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, SkipPostInit: true})
+	require.NoError(t, err)
+	mainPath := filepath.Join(out, "server.go")
+	require.NoError(t, os.WriteFile(mainPath, []byte(`package main
+
+type Server struct {
+	name string
+}
+
+func (s *Server) routes(r Router) {
+}
+`), 0644))
+
+	// A dry run plans the edits to a file as one change.
+	result, err := s.Add(Options{TemplateRef: template.TemplateRef{Name: "logging"}, OutputDir: out, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, result.Planned, 1)
+	assert.Equal(t, "server.go", result.Planned[0].Path)
+	assert.Contains(t, result.Planned[0].Diff, "+\tlogger *slog.Logger")
+	assert.Contains(t, result.Planned[0].Diff, "+\tr.Use(logRequests(slog.LevelInfo))")
+
+	result, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "logging"}, OutputDir: out})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server.go"}, result.FilesWritten)
+
+	content, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, `package main
+
+import "log/slog"
+
+type Server struct {
+	name   string
+	logger *slog.Logger
+}
+
+func (s *Server) routes(r Router) {
+	r.Use(logRequests(slog.LevelInfo))
+}
+`, string(content))
+
+	// A struct the file does not declare stops the run.
+	require.NoError(t, os.WriteFile(mainPath, []byte("package main\n\nfunc (s *Server) routes(r Router) {}\n"), 0644))
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "logging"}, OutputDir: out, Overwrite: true})
+	var notFound *template.GoTargetNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "logging", notFound.Template)
+	assert.Equal(t, "server.go", notFound.Path)
+	assert.Equal(t, "Server", notFound.Name)
+}
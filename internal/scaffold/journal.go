@@ -0,0 +1,138 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Journal records the filesystem changes made during a scaffolding run so
+// that they can be undone if the run fails.
+type Journal struct {
+	entries     []journalEntry
+	seen        map[string]bool
+	postInitEnv []string // Names of the environment variables post-init commands used
+}
+
+// journalEntry is the state of a path before the run first touched it.
+type journalEntry struct {
+	path     string
+	dir      bool        // Directory created by the run
+	existed  bool        // File existed before the run
+	original []byte      // Original content of an existing file
+	perm     fs.FileMode // Original permissions of an existing file
+}
+
+// NewJournal creates an empty journal.
+func NewJournal() *Journal {
+	return &Journal{seen: make(map[string]bool)}
+}
+
+// RecordPostInitEnv records the names of the environment variables the
+// post-init commands of the run used. Their values are never recorded.
+func (j *Journal) RecordPostInitEnv(names []string) {
+	if j == nil {
+		return
+	}
+	j.postInitEnv = append(j.postInitEnv, names...)
+}
+
+// RecordDirs records every missing directory on the way to dir, outermost
+// first, before they are created.
+func (j *Journal) RecordDirs(dir string) error {
+	if j == nil || dir == "" || dir == "." {
+		return nil
+	}
+
+	var missing []string
+	for current := filepath.Clean(dir); ; {
+		if _, err := os.Stat(current); err == nil {
+			break
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to stat %s: %w", current, err)
+		}
+
+		missing = append(missing, current)
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		if j.seen[missing[i]] {
+			continue
+		}
+		j.seen[missing[i]] = true
+		j.entries = append(j.entries, journalEntry{path: missing[i], dir: true})
+	}
+
+	return nil
+}
+
+// RecordFile records the current state of a file before it is written.
+// Only the first recording of a path is kept.
+func (j *Journal) RecordFile(path string) error {
+	if j == nil || j.seen[path] {
+		return nil
+	}
+
+	entry := journalEntry{path: path}
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		entry.existed = true
+		entry.original = content
+		entry.perm = info.Mode().Perm()
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	j.seen[path] = true
+	j.entries = append(j.entries, entry)
+	return nil
+}
+
+// Rollback undoes the recorded changes in reverse order. Files that existed
+// are restored, new files are removed, and directories created by the run are
+// removed along with anything left inside them.
+func (j *Journal) Rollback() error {
+	if j == nil {
+		return nil
+	}
+
+	var errs []error
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		entry := j.entries[i]
+
+		var err error
+		switch {
+		case entry.dir:
+			err = os.RemoveAll(entry.path)
+		case entry.existed:
+			err = os.WriteFile(entry.path, entry.original, entry.perm)
+			if err == nil {
+				err = os.Chmod(entry.path, entry.perm)
+			}
+		default:
+			err = os.Remove(entry.path)
+		}
+
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to roll back %s: %w", entry.path, err))
+		}
+	}
+
+	j.entries = nil
+	j.seen = make(map[string]bool)
+	return errors.Join(errs...)
+}
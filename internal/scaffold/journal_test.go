@@ -0,0 +1,137 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal_Rollback(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "README.md")
+	require.NoError(t, os.WriteFile(existing, []byte("original"), 0600))
+
+	journal := NewJournal()
+	writer := NewWriter().WithJournal(journal)
+
+	require.NoError(t, writer.WriteFile(existing, []byte("changed")))
+	require.NoError(t, writer.WriteFile(filepath.Join(root, "new.txt"), []byte("new")))
+	require.NoError(t, writer.WriteFile(filepath.Join(root, "pkg", "sub", "file.go"), []byte("package sub")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "pkg", "generated.sum"), []byte("by post-init"), 0644))
+
+	require.NoError(t, journal.Rollback())
+
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+
+	info, err := os.Stat(existing)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	assert.NoFileExists(t, filepath.Join(root, "new.txt"))
+	assert.NoDirExists(t, filepath.Join(root, "pkg"))
+	assert.DirExists(t, root)
+}
+
+func TestJournal_RollbackRemovesCreatedOutputDir(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "app")
+
+	journal := NewJournal()
+	require.NoError(t, journal.RecordDirs(output))
+
+	writer := NewWriter().WithJournal(journal)
+	require.NoError(t, writer.WriteFile(filepath.Join(output, "main.go"), []byte("package main")))
+
+	require.NoError(t, journal.Rollback())
+	assert.NoDirExists(t, output)
+}
+
+func TestWriter_WriteFileLeavesNoTempFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, NewWriter().WriteFile(filepath.Join(root, "a.txt"), []byte("a")))
+
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "a.txt", entries[0].Name())
+}
+
+func TestWriter_WriteFilesHonorsMode(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := NewWriter().WriteFiles(root, []template.RenderedFile{
+		{Path: "run.sh", Content: []byte("#!/bin/sh"), Mode: 0755},
+		{Path: "README.md", Content: []byte("# app")},
+	}, false)
+	require.NoError(t, err)
+
+	info, err := os.Stat(filepath.Join(root, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	info, err = os.Stat(filepath.Join(root, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestWriter_WriteFilesOverwriteMode(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "run.sh"), []byte("echo old"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("old"), 0600))
+
+	_, err := NewWriter().WriteFiles(root, []template.RenderedFile{
+		{Path: "run.sh", Content: []byte("#!/bin/sh"), Mode: 0755},
+		{Path: "notes.txt", Content: []byte("new")},
+	}, true)
+	require.NoError(t, err)
+
+	// The declared mode applies; without one, the file keeps its own.
+	info, err := os.Stat(filepath.Join(root, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	info, err = os.Stat(filepath.Join(root, "notes.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestWriter_WriteFilesRendersPlannedFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "existing.txt"), []byte("keep"), 0644))
+
+	node := &template.TemplateNode{
+		ID: "0",
+		Template: &template.Template{
+			Name: "root",
+			Files: []template.File{
+				{Src: "main.go.tmpl", Dest: "main.go"},
+				{Src: "broken.tmpl", Dest: "existing.txt"},
+			},
+		},
+		FS: fstest.MapFS{
+			"main.go.tmpl": {Data: []byte("package {{ .name }}\n")},
+			"broken.tmpl":  {Data: []byte("{{ .missing.field }}")},
+		},
+		Path: ".",
+	}
+	planned, err := template.NewRenderer().PlanAll(node, template.RenderContexts{
+		"0": template.NewTemplateContext(map[string]any{"name": "api"}),
+	})
+	require.NoError(t, err)
+
+	var hooked []string
+	result, err := NewWriter().OnWrite(func(file template.RenderedFile, content []byte) {
+		hooked = append(hooked, file.Path+"="+string(content))
+	}).WriteFiles(root, planned.Files["0"], false)
+	require.NoError(t, err, "skipped files must not be rendered")
+
+	assert.Equal(t, []string{"main.go"}, result.Written)
+	assert.Equal(t, []string{"existing.txt"}, result.Skipped)
+	assert.Equal(t, []string{"main.go=package api\n"}, hooked)
+}
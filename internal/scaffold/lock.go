@@ -0,0 +1,162 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+const (
+	// LockFileName is the name of the lock file created in an output directory
+	// while it is being scaffolded.
+	LockFileName = ".blueprint.lock"
+
+	// lockStaleAfter is the age after which a lock is considered stale
+	// regardless of its owner.
+	lockStaleAfter = time.Hour
+)
+
+// lockInfo is the content of a lock file.
+type lockInfo struct {
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Created  time.Time `json:"created"`
+}
+
+// Lock is an acquired lock on an output directory.
+type Lock struct {
+	path string
+}
+
+// AcquireLock creates a lock file in dir, creating dir if needed.
+// A stale lock left behind by a dead process or older than an hour is
+// removed and replaced. A live lock results in a *LockedError.
+func AcquireLock(dir string) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, LockFileName)
+
+	hostname, _ := os.Hostname()
+	data, err := json.Marshal(lockInfo{
+		PID:      os.Getpid(),
+		Hostname: hostname,
+		Created:  time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode lock: %w", err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err := writeLockFile(path, data)
+		if err == nil {
+			return &Lock{path: path}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock %s: %w", path, err)
+		}
+
+		existing, err := readLockFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil && !existing.isStale(hostname) {
+			return nil, &LockedError{
+				Dir:      dir,
+				PID:      existing.PID,
+				Hostname: existing.Hostname,
+				Created:  existing.Created,
+			}
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale lock %s: %w", path, err)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to acquire lock %s", path)
+}
+
+// Release removes the lock file.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release lock %s: %w", l.path, err)
+	}
+	return nil
+}
+
+func writeLockFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, writeErr := f.Write(data)
+	closeErr := f.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	return closeErr
+}
+
+// readLockFile reads an existing lock. It returns nil when the lock has
+// disappeared or cannot be decoded, in which case it is treated as stale.
+func readLockFile(path string) (*lockInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read lock %s: %w", path, err)
+	}
+
+	var info lockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, nil
+	}
+
+	return &info, nil
+}
+
+func (l *lockInfo) isStale(hostname string) bool {
+	if time.Since(l.Created) > lockStaleAfter {
+		return true
+	}
+
+	if l.Hostname != hostname {
+		return false
+	}
+
+	return !processAlive(l.PID)
+}
+
+// processAlive reports whether a process with the given PID is running on
+// this host.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		// FindProcess only succeeds for live processes on Windows.
+		return true
+	}
+
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,63 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLock(t *testing.T, dir string, info lockInfo) {
+	t.Helper()
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LockFileName), data, 0644))
+}
+
+func TestAcquireLock(t *testing.T) {
+	hostname, _ := os.Hostname()
+
+	t.Run("acquire and release", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "out")
+
+		lock, err := AcquireLock(dir)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, LockFileName))
+
+		require.NoError(t, lock.Release())
+		assert.NoFileExists(t, filepath.Join(dir, LockFileName))
+	})
+
+	t.Run("live lock fails", func(t *testing.T) {
+		dir := t.TempDir()
+		writeLock(t, dir, lockInfo{PID: os.Getpid(), Hostname: hostname, Created: time.Now()})
+
+		_, err := AcquireLock(dir)
+		var lockedErr *LockedError
+		require.ErrorAs(t, err, &lockedErr)
+		assert.Equal(t, os.Getpid(), lockedErr.PID)
+	})
+
+	t.Run("expired lock is replaced", func(t *testing.T) {
+		dir := t.TempDir()
+		writeLock(t, dir, lockInfo{PID: os.Getpid(), Hostname: hostname, Created: time.Now().Add(-2 * lockStaleAfter)})
+
+		lock, err := AcquireLock(dir)
+		require.NoError(t, err)
+		require.NoError(t, lock.Release())
+	})
+
+	t.Run("corrupt lock is replaced", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, LockFileName), []byte("garbage"), 0644))
+
+		lock, err := AcquireLock(dir)
+		require.NoError(t, err)
+		require.NoError(t, lock.Release())
+	})
+}
@@ -0,0 +1,208 @@
+package scaffold
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/version"
+)
+
+// manifestRecorder builds the project manifest while files are written.
+// Files written outside the project are recorded for the host journal.
+type manifestRecorder struct {
+	root      string
+	manifest  *manifest.Manifest
+	hostFiles []manifest.HostFile
+	injected  map[string]string // Hashes of the files content was injected into, by path
+}
+
+func newManifestRecorder(
+	root string,
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	dirs map[string]string,
+) *manifestRecorder {
+	m := &manifest.Manifest{
+		SchemaVersion:    manifest.SchemaVersion,
+		Template:         tree.Template.Name,
+		TemplateVersion:  tree.Template.Version,
+		BlueprintVersion: version.Version,
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	var addNode func(node *template.TemplateNode)
+	addNode = func(node *template.TemplateNode) {
+		record := manifest.Node{
+			ID:       node.ID,
+			Template: node.Template.Name,
+			Version:  node.Template.Version,
+			Mount:    node.Mount,
+			Mandated: node.Mandated,
+		}
+		for _, v := range node.Template.Variables {
+			if v.Role == "" {
+				continue
+			}
+			if record.Roles == nil {
+				record.Roles = make(map[string]string)
+			}
+			record.Roles[string(v.Role)] = v.Name
+		}
+		for _, pattern := range node.Template.Clean {
+			record.Clean = append(record.Clean, path.Join(dirs[node.ID], pattern))
+		}
+		if ctx, ok := contexts[node.ID]; ok {
+			record.Variables = recordedVariables(node, ctx.Variables)
+		}
+		m.Nodes = append(m.Nodes, record)
+
+		for _, child := range node.Children {
+			addNode(child)
+		}
+	}
+	addNode(tree)
+
+	for _, resolution := range tree.Versions {
+		if !slices.ContainsFunc(m.Nodes, func(n manifest.Node) bool { return n.Template == resolution.Template }) {
+			continue
+		}
+		record := manifest.Version{
+			Template:   resolution.Template,
+			Version:    resolution.Version,
+			RequiredBy: make(map[string]string, len(resolution.Requirements)),
+		}
+		for _, req := range resolution.Requirements {
+			record.RequiredBy[req.By] = req.Constraint
+		}
+		m.Versions = append(m.Versions, record)
+	}
+
+	return &manifestRecorder{root: root, manifest: m}
+}
+
+// recordedVariables returns the variables to record in the manifest. The
+// builtin variable is left out, since it describes the run rather than the
+// project, and so are the secrets the node declares.
+func recordedVariables(node *template.TemplateNode, variables map[string]any) map[string]any {
+	secrets := secretNames(node)
+	if _, ok := variables[template.BuiltinVariable]; !ok && len(secrets) == 0 {
+		return variables
+	}
+
+	recorded := make(map[string]any, len(variables))
+	for name, value := range variables {
+		if name != template.BuiltinVariable && !secrets[name] {
+			recorded[name] = value
+		}
+	}
+	return recorded
+}
+
+// record adds the provenance record of a file written for a node. Files
+// written outside the project directory are not part of the project and are
+// recorded in the host journal instead.
+func (r *manifestRecorder) record(node *template.TemplateNode, nodeDir string, file template.RenderedFile, content []byte) {
+	if filepath.IsAbs(file.Path) {
+		r.recordHost(node, file.Path, file, content)
+		return
+	}
+
+	prefix, err := filepath.Rel(r.root, nodeDir)
+	if err != nil {
+		prefix = ""
+	}
+
+	filePath := filepath.ToSlash(filepath.Join(prefix, file.Path))
+	if filePath == ".." || strings.HasPrefix(filePath, "../") {
+		r.recordHost(node, filepath.Join(nodeDir, file.Path), file, content)
+		return
+	}
+
+	r.manifest.Files = append(r.manifest.Files, manifest.File{
+		Path:   filePath,
+		Node:   node.ID,
+		Source: file.Source,
+		Hash:   manifest.HashContent(content),
+	})
+	for _, injection := range file.Injected {
+		r.recordInjection(injection.Node, filePath, injection.Snippet)
+	}
+}
+
+// recordHost adds the record of a file written outside the project at
+// fullPath.
+func (r *manifestRecorder) recordHost(node *template.TemplateNode, fullPath string, file template.RenderedFile, content []byte) {
+	fullPath, err := filepath.Abs(fullPath)
+	if err != nil {
+		return
+	}
+	project, err := filepath.Abs(r.root)
+	if err != nil {
+		project = r.root
+	}
+
+	r.hostFiles = append(r.hostFiles, manifest.HostFile{
+		Path:      fullPath,
+		Target:    string(file.Target),
+		Template:  node.Template.Name,
+		Project:   project,
+		Hash:      manifest.HashContent(content),
+		WrittenAt: time.Now().UTC(),
+	})
+}
+
+// save writes the manifest if any files were recorded and adds the files
+// written outside the project to the host journal, recording both changes in
+// the journal first.
+func (r *manifestRecorder) save(journal *Journal) error {
+	if err := r.saveHostFiles(journal); err != nil {
+		return err
+	}
+
+	if len(r.manifest.Files) == 0 {
+		return nil
+	}
+
+	path := manifest.Path(r.root)
+	if err := journal.RecordDirs(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := journal.RecordFile(path); err != nil {
+		return err
+	}
+
+	return r.manifest.Save(r.root)
+}
+
+func (r *manifestRecorder) saveHostFiles(journal *Journal) error {
+	if len(r.hostFiles) == 0 {
+		return nil
+	}
+
+	path, err := manifest.DefaultHostJournalPath()
+	if err != nil {
+		return err
+	}
+
+	host, err := manifest.LoadHostJournal(path)
+	if err != nil {
+		return err
+	}
+	for _, f := range r.hostFiles {
+		host.Record(f)
+	}
+
+	if err := journal.RecordDirs(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := journal.RecordFile(path); err != nil {
+		return err
+	}
+
+	return host.Save(path)
+}
@@ -0,0 +1,69 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldRecordsIncludeVersions(t *testing.T) {
+	user, builtin := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(user, "app", template.FileName): `name: app
+type: project
+version: 1.0.0
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+includes:
+  - name: logging
+    version: ^1.2.0
+    enabled_by_default: true
+`,
+		filepath.Join(user, "logging", template.FileName):    "name: logging\ntype: feature\nversion: 1.1.0\nfiles:\n  - src: log.txt\n    dest: log.txt\n",
+		filepath.Join(user, "logging", "log.txt"):            "1.1.0\n",
+		filepath.Join(builtin, "logging", template.FileName): "name: logging\ntype: feature\nversion: 1.4.0\nfiles:\n  - src: log.txt\n    dest: log.txt\n",
+		filepath.Join(builtin, "logging", "log.txt"):         "1.4.0\n",
+	}
+	for p, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+
+	s := NewScaffolder(resolver.NewChainResolver(
+		resolver.Source{Name: "user", Type: resolver.SourceTypeUser, Filesystem: os.DirFS(user), Dir: user},
+		resolver.Source{Name: "builtin", Type: resolver.SourceTypeBuiltin, Filesystem: os.DirFS(builtin)},
+	))
+
+	out := filepath.Join(t.TempDir(), "app")
+	_, err := s.Scaffold(Options{
+		TemplateRef:     template.TemplateRef{Name: "app"},
+		OutputDir:       out,
+		Variables:       vars.Variables{Global: map[string]string{"name": "demo"}},
+		EnabledIncludes: map[string]bool{"logging": true},
+	})
+	require.NoError(t, err)
+
+	// The user source shadows logging, but only the builtin version
+	// satisfies the constraint.
+	content, err := os.ReadFile(filepath.Join(out, "log.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0\n", string(content))
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	assert.Equal(t, []manifest.Version{{
+		Template:   "logging",
+		Version:    "1.4.0",
+		RequiredBy: map[string]string{"app": "^1.2.0"},
+	}}, m.Versions)
+}
@@ -0,0 +1,99 @@
+package scaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// MigratedTemplate is a template.yaml rewritten to the newest schema.
+type MigratedTemplate struct {
+	Path   string // Path of the template.yaml
+	Result *template.MigrationResult
+	Diff   string // Unified diff of the rewrite
+}
+
+// MigrateTemplates migrates every template.yaml under dir, or the file dir
+// names, to the newest schema and returns the templates that changed. The
+// files are only rewritten when write is set, and keep their permissions.
+// Hidden directories are not searched.
+func MigrateTemplates(dir string, write bool) ([]MigratedTemplate, error) {
+	var paths []string
+	base := dir
+	if info, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		paths = append(paths, dir)
+		base = filepath.Dir(dir)
+	} else {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && d.Name() == template.FileName {
+				paths = append(paths, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no %s found in %s", template.FileName, dir)
+	}
+
+	var migrated []MigratedTemplate
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := template.Migrate(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		if !result.Changed() {
+			continue
+		}
+
+		name, err := filepath.Rel(base, p)
+		if err != nil {
+			name = p
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(data)),
+			B:        difflib.SplitLines(string(result.Content)),
+			FromFile: "a/" + filepath.ToSlash(name),
+			ToFile:   "b/" + filepath.ToSlash(name),
+			Context:  3,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s: %w", p, err)
+		}
+		migrated = append(migrated, MigratedTemplate{Path: p, Result: result, Diff: diff})
+	}
+
+	if !write {
+		return migrated, nil
+	}
+	for _, m := range migrated {
+		info, err := os.Stat(m.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(m.Path, m.Result.Content, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", m.Path, err)
+		}
+	}
+	return migrated, nil
+}
@@ -0,0 +1,47 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateTemplates(t *testing.T) {
+	dir := t.TempDir()
+	old := "name: old\npost_init:\n  - make\n"
+	current := "name: current\nschema: 2\n"
+	for p, content := range map[string]string{
+		"old/" + template.FileName:      old,
+		"current/" + template.FileName:  current,
+		".git/old/" + template.FileName: old,
+		"old/tests/case/" + "case.yaml": "variables: {}\n",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	migrated, err := MigrateTemplates(dir, false)
+	require.NoError(t, err)
+	require.Len(t, migrated, 1)
+	oldPath := filepath.Join(dir, "old", template.FileName)
+	assert.Equal(t, oldPath, migrated[0].Path)
+	assert.Contains(t, migrated[0].Diff, "+  - command: make")
+	content, err := os.ReadFile(oldPath)
+	require.NoError(t, err)
+	assert.Equal(t, old, string(content), "nothing is written without write")
+
+	_, err = MigrateTemplates(dir, true)
+	require.NoError(t, err)
+	content, err = os.ReadFile(oldPath)
+	require.NoError(t, err)
+	assert.Equal(t, "schema: 2\nname: old\npost_init:\n  - command: make\n", string(content))
+
+	migrated, err = MigrateTemplates(dir, true)
+	require.NoError(t, err)
+	assert.Empty(t, migrated)
+}
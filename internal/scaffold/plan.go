@@ -0,0 +1,144 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// PlanStatus describes what a real run would do with a planned file.
+type PlanStatus string
+
+const (
+	PlanCreate    PlanStatus = "create"    // The file does not exist yet
+	PlanOverwrite PlanStatus = "overwrite" // The file exists and would be replaced
+	PlanSkip      PlanStatus = "skip"      // The file exists and would be kept
+	PlanUnchanged PlanStatus = "unchanged" // The file exists with the same content
+	PlanRemove    PlanStatus = "remove"    // The file exists and would be removed; only planned by a shadow run
+)
+
+// PlannedFile is a file a dry run would write. Its content is rendered to
+// check it, measure its size, and compare it with an existing file, but only
+// kept when Options.ShowContent is set.
+type PlannedFile struct {
+	Path    string // Slash-separated and relative to the output directory
+	Size    int
+	Status  PlanStatus
+	Diff    string // Unified diff against the existing text file, if it differs
+	Binary  bool
+	Content []byte // Rendered content when Options.ShowContent is set
+}
+
+// planFiles renders the files of a tree one at a time, as writing them would,
+// and compares each with the file already at its path in outputDir.
+func planFiles(
+	tree *template.TemplateNode,
+	renderResult *template.RenderResult,
+	dirs map[string]string,
+	outputDir string,
+	opts Options,
+) ([]PlannedFile, error) {
+	planned := make([]PlannedFile, 0)
+	// Files on disk that snippets were injected into, by path, so that every
+	// snippet injected into a file is planned as one change to it.
+	injected := make(map[string]int)
+	injectedContent := make(map[string][]byte)
+
+	var planNode func(node *template.TemplateNode) error
+	planNode = func(node *template.TemplateNode) error {
+		for _, file := range renderResult.Files[node.ID] {
+			content, err := file.Load()
+			if err != nil {
+				return err
+			}
+
+			p := PlannedFile{
+				Path:   template.OutputPath(dirs[node.ID], filepath.ToSlash(file.Path)),
+				Size:   len(content),
+				Binary: template.IsBinary(content),
+			}
+			if opts.ShowContent {
+				p.Content = content
+			}
+
+			fullPath := filepath.FromSlash(p.Path)
+			if !filepath.IsAbs(fullPath) {
+				fullPath = filepath.Join(outputDir, fullPath)
+			}
+			if err := comparePlanned(&p, fullPath, content, opts.Overwrite); err != nil {
+				return err
+			}
+
+			planned = append(planned, p)
+		}
+		for _, snippet := range renderResult.Injections[node.ID] {
+			p, content, err := planInjection(node, snippet, dirs[node.ID], outputDir, opts, injectedContent)
+			if err != nil {
+				return err
+			}
+			injectedContent[p.Path] = content
+			if i, ok := injected[p.Path]; ok {
+				planned[i] = p
+				continue
+			}
+			injected[p.Path] = len(planned)
+			planned = append(planned, p)
+		}
+		for _, child := range node.Children {
+			if err := planNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := planNode(tree); err != nil {
+		return nil, err
+	}
+	return planned, nil
+}
+
+// comparePlanned sets the status of a planned file from the file currently
+// at fullPath, and its diff when both are text and differ.
+func comparePlanned(p *PlannedFile, fullPath string, content []byte, overwrite bool) error {
+	existing, err := os.ReadFile(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			p.Status = PlanCreate
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", p.Path, err)
+	}
+
+	switch {
+	case bytes.Equal(existing, content):
+		p.Status = PlanUnchanged
+		return nil
+	case overwrite:
+		p.Status = PlanOverwrite
+	default:
+		p.Status = PlanSkip
+	}
+
+	if p.Binary || template.IsBinary(existing) {
+		return nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existing)),
+		B:        difflib.SplitLines(string(content)),
+		FromFile: "a/" + p.Path,
+		ToFile:   "b/" + p.Path,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", p.Path, err)
+	}
+	p.Diff = diff
+	return nil
+}
@@ -0,0 +1,66 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planFixture() (*template.TemplateNode, *template.RenderResult) {
+	tree := &template.TemplateNode{
+		ID:       "0",
+		Template: &template.Template{Name: "app"},
+		Children: []*template.TemplateNode{
+			{ID: "0.0", Template: &template.Template{Name: "svc"}},
+		},
+	}
+	result := &template.RenderResult{
+		Files: map[string][]template.RenderedFile{
+			"0": {
+				{Path: "main.go", Content: []byte("package main\n")},
+				{Path: "README.md", Content: []byte("# app\nnew line\n")},
+			},
+			"0.0": {
+				{Path: "svc.go", Content: []byte("package svc\n")},
+			},
+		},
+	}
+	return tree, result
+}
+
+func TestPlanFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# app\n"), 0644))
+
+	tree, result := planFixture()
+	planned, err := planFiles(tree, result, map[string]string{"0.0": "svc"}, root, Options{})
+	require.NoError(t, err)
+
+	require.Len(t, planned, 3)
+	assert.Equal(t, PlanUnchanged, planned[0].Status)
+	assert.Empty(t, planned[0].Diff)
+	assert.Equal(t, PlanSkip, planned[1].Status)
+	assert.Contains(t, planned[1].Diff, "--- a/README.md")
+	assert.Contains(t, planned[1].Diff, "+new line")
+	assert.Equal(t, "svc/svc.go", planned[2].Path)
+	assert.Equal(t, PlanCreate, planned[2].Status)
+	assert.Nil(t, planned[2].Content)
+}
+
+func TestPlanFiles_OverwriteAndContent(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# old\n"), 0644))
+
+	tree, result := planFixture()
+	planned, err := planFiles(tree, result, nil, root, Options{Overwrite: true, ShowContent: true})
+	require.NoError(t, err)
+
+	require.Len(t, planned, 3)
+	assert.Equal(t, PlanOverwrite, planned[1].Status)
+	assert.Equal(t, "# app\nnew line\n", string(planned[1].Content))
+}
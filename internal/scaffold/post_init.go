@@ -0,0 +1,219 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+)
+
+// PostInitStatus represents the outcome of a post-init command.
+type PostInitStatus string
+
+const (
+	PostInitSucceeded PostInitStatus = "succeeded"
+	PostInitFailed    PostInitStatus = "failed"
+	PostInitSkipped   PostInitStatus = "skipped"
+	PostInitCompleted PostInitStatus = "completed" // Completed by an earlier run and not run again
+)
+
+// PostInitStep is a post-init command bound to the directory it runs in.
+type PostInitStep struct {
+	Command    string
+	Dir        string
+	Template   string   // Name of the template declaring the command
+	Env        []string // Additional environment in KEY=value form
+	Idempotent bool     // Safe to run again after it completed
+	Completed  bool     // Completed by an earlier run and not to be run again
+	Trusted    bool     // Runs without confirmation
+}
+
+// PostInitResult reports the outcome of a single post-init command.
+type PostInitResult struct {
+	Command  string
+	Dir      string
+	Status   PostInitStatus
+	Err      error
+	Duration time.Duration // Time the command ran; zero when it was not run
+}
+
+// PostInitRunner executes post-init commands and streams their output.
+type PostInitRunner struct {
+	stdout     io.Writer
+	stderr     io.Writer
+	emit       func(Event)
+	restricted bool
+}
+
+// NewPostInitRunner creates a new post-init runner that streams command output
+// to the given writers.
+func NewPostInitRunner(stdout, stderr io.Writer) *PostInitRunner {
+	return &PostInitRunner{
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+// WithEvents returns a copy of the runner that reports each command it
+// executes to emit when it starts and finishes.
+func (r *PostInitRunner) WithEvents(emit func(Event)) *PostInitRunner {
+	reporting := *r
+	reporting.emit = emit
+	return &reporting
+}
+
+// WithRestrictedEnv returns a copy of the runner that runs commands with a
+// restricted environment instead of the environment of Blueprint: only the
+// variables listed in restrictedEnvVars and those of each step.
+func (r *PostInitRunner) WithRestrictedEnv() *PostInitRunner {
+	restricted := *r
+	restricted.restricted = true
+	return &restricted
+}
+
+// Run executes the steps sequentially. Steps completed by an earlier run are
+// reported as such without running. Execution stops at the first failing
+// command and all remaining steps are reported as skipped.
+func (r *PostInitRunner) Run(steps []PostInitStep) []PostInitResult {
+	results := make([]PostInitResult, 0, len(steps))
+	failed := false
+
+	for _, step := range steps {
+		result := PostInitResult{
+			Command: step.Command,
+			Dir:     step.Dir,
+		}
+
+		if failed {
+			result.Status = PostInitSkipped
+			results = append(results, result)
+			continue
+		}
+
+		if step.Completed {
+			result.Status = PostInitCompleted
+			results = append(results, result)
+			continue
+		}
+
+		r.report(Event{Kind: EventPostInitStarted, Command: step.Command, Dir: step.Dir})
+		start := time.Now()
+		err := r.runStep(step)
+		result.Duration = time.Since(start)
+		if err != nil {
+			result.Status = PostInitFailed
+			result.Err = err
+			failed = true
+		} else {
+			result.Status = PostInitSucceeded
+		}
+		r.report(Event{Kind: EventPostInitFinished, Command: step.Command, Dir: step.Dir, Status: result.Status, Err: result.Err})
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (r *PostInitRunner) report(e Event) {
+	if r.emit != nil {
+		r.emit(e)
+	}
+}
+
+// SkipAll reports every step as skipped without executing it.
+func (r *PostInitRunner) SkipAll(steps []PostInitStep) []PostInitResult {
+	results := make([]PostInitResult, 0, len(steps))
+	for _, step := range steps {
+		status := PostInitSkipped
+		if step.Completed {
+			status = PostInitCompleted
+		}
+		results = append(results, PostInitResult{
+			Command: step.Command,
+			Dir:     step.Dir,
+			Status:  status,
+		})
+	}
+	return results
+}
+
+func (r *PostInitRunner) runStep(step PostInitStep) error {
+	cmd := shellCommand(step.Command)
+	cmd.Dir = step.Dir
+	if r.restricted {
+		cmd.Env = append(restrictedEnv(), step.Env...)
+	} else if len(step.Env) > 0 {
+		cmd.Env = append(os.Environ(), step.Env...)
+	}
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run %q in %s: %w", step.Command, step.Dir, err)
+	}
+
+	return nil
+}
+
+// shellCommand wraps a command string in the platform shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// recordPostInit records the post-init commands that completed in the
+// manifest of the project, so that a later run into the project, such as one
+// resuming after a failed command, skips them. Commands that were not run
+// keep the record of an earlier run; failed commands lose it.
+func recordPostInit(outputDir string, results []PostInitResult, journal *Journal) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	m, err := manifest.Load(outputDir)
+	if err != nil {
+		var notFound *manifest.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+
+	steps := make([]manifest.PostInitStep, 0, len(results))
+	for _, res := range results {
+		dir := postInitDir(outputDir, res.Dir)
+		switch res.Status {
+		case PostInitSucceeded:
+			steps = append(steps, manifest.PostInitStep{Command: res.Command, Dir: dir, CompletedAt: time.Now().UTC()})
+		case PostInitCompleted, PostInitSkipped:
+			if prev, ok := m.PostInitStep(res.Command, dir); ok {
+				steps = append(steps, *prev)
+			}
+		}
+	}
+	m.PostInit = steps
+
+	if err := journal.RecordFile(manifest.Path(outputDir)); err != nil {
+		return err
+	}
+	return m.Save(outputDir)
+}
+
+// postInitDir returns the directory of a post-init command relative to the
+// project, as recorded in the manifest.
+func postInitDir(outputDir, dir string) string {
+	rel, err := filepath.Rel(outputDir, dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	return filepath.ToSlash(rel)
+}
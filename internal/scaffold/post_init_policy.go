@@ -0,0 +1,206 @@
+package scaffold
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// PostInitEnv selects the environment post-init commands run with.
+type PostInitEnv string
+
+const (
+	// PostInitEnvInherit runs commands with the environment of Blueprint.
+	PostInitEnvInherit PostInitEnv = "inherit"
+	// PostInitEnvRestricted runs commands with only the variables a shell
+	// and common tools need, the variables templates declare, and their
+	// secrets, keeping credentials in the environment away from commands.
+	PostInitEnvRestricted PostInitEnv = "restricted"
+)
+
+// ParsePostInitEnv parses a post-init environment mode. An empty value is
+// PostInitEnvInherit.
+func ParsePostInitEnv(s string) (PostInitEnv, error) {
+	switch env := PostInitEnv(s); env {
+	case "":
+		return PostInitEnvInherit, nil
+	case PostInitEnvInherit, PostInitEnvRestricted:
+		return env, nil
+	default:
+		return "", fmt.Errorf("invalid post-init environment %q: expected inherit or restricted", s)
+	}
+}
+
+// restrictedEnvVars lists the variables passed to post-init commands run
+// with a restricted environment.
+var restrictedEnvVars = map[string]bool{
+	"PATH": true, "HOME": true, "USER": true, "LOGNAME": true, "SHELL": true, "TERM": true,
+	"LANG": true, "LC_ALL": true, "LC_CTYPE": true, "TMPDIR": true, "TZ": true,
+	// Windows
+	"SYSTEMROOT": true, "SYSTEMDRIVE": true, "WINDIR": true, "COMSPEC": true, "PATHEXT": true,
+	"TEMP": true, "TMP": true, "USERPROFILE": true, "APPDATA": true, "LOCALAPPDATA": true,
+}
+
+// restrictedEnv returns the entries of the environment passed to post-init
+// commands run with a restricted environment.
+func restrictedEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if runtime.GOOS == "windows" {
+			name = strings.ToUpper(name)
+		}
+		if restrictedEnvVars[name] {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// declaredEnv returns the variables the templates of the tree declare that
+// are set in the environment, in KEY=value form. They are passed on
+// explicitly to commands run with a restricted environment, since the
+// environment is not inherited.
+func declaredEnv(tree *template.TemplateNode) []string {
+	var env []string
+	for _, e := range tree.AllEnv() {
+		if value, ok := os.LookupEnv(e.Name); ok {
+			env = append(env, e.Name+"="+value)
+		}
+	}
+	return env
+}
+
+// PostInitPolicy decides which post-init commands may run, which of them
+// need confirmation, and the environment they run with. Patterns match whole
+// commands, with * matching any text, as in "npm install*". A command that
+// chains several commands with shell operators such as ; && || | passes only
+// if every one of them does.
+type PostInitPolicy struct {
+	Allow   []string    // Only commands matching one of these may run; any command if empty
+	Deny    []string    // Commands matching one of these never run
+	Trusted []fs.FS     // Filesystems of templates whose commands run without confirmation
+	Env     PostInitEnv // Environment commands run with; PostInitEnvInherit if empty
+}
+
+// check returns a PostInitDeniedError for the first pending step the policy
+// refuses. When the policy has patterns, commands of untrusted templates that
+// substitute commands or redirect input or output are refused, since what
+// they run or write cannot be matched.
+func (p PostInitPolicy) check(steps []PostInitStep) error {
+	for _, step := range steps {
+		if step.Completed {
+			continue
+		}
+		if len(p.Allow) > 0 || len(p.Deny) > 0 {
+			if op := uncheckableOperator(step.Command); op != "" && !step.Trusted {
+				return &PostInitDeniedError{Command: step.Command, Template: step.Template, Operator: op}
+			}
+		}
+		for _, part := range splitCommand(step.Command) {
+			if err := p.checkCommand(part); err != nil {
+				err.Command, err.Template = step.Command, step.Template
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCommand checks a single command of a post-init step against the deny
+// and allow patterns.
+func (p PostInitPolicy) checkCommand(command string) *PostInitDeniedError {
+	for _, pattern := range p.Deny {
+		if matchCommand(pattern, command) {
+			return &PostInitDeniedError{Part: command, Pattern: pattern}
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, pattern := range p.Allow {
+		if matchCommand(pattern, command) {
+			return nil
+		}
+	}
+	return &PostInitDeniedError{Part: command}
+}
+
+// commandSeparators lists the shell operators that chain commands, longest
+// first.
+var commandSeparators = []string{"&&", "||", ";", "|", "&", "\n"}
+
+// splitCommand splits a shell command into the commands it chains. Quotes
+// are not taken into account, so an operator inside a quoted argument splits
+// too; that only makes the policy stricter.
+func splitCommand(command string) []string {
+	parts := []string{command}
+	for _, sep := range commandSeparators {
+		var split []string
+		for _, part := range parts {
+			split = append(split, strings.Split(part, sep)...)
+		}
+		parts = split
+	}
+
+	commands := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			commands = append(commands, part)
+		}
+	}
+	return commands
+}
+
+// uncheckableOperator returns the first shell operator in command that runs
+// or writes something patterns cannot see: command substitution and
+// redirection. It returns an empty string if there is none.
+func uncheckableOperator(command string) string {
+	for _, op := range []string{"$(", "`", ">", "<"} {
+		if strings.Contains(command, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// trusts reports whether the commands of templates read from fsys run
+// without confirmation.
+func (p PostInitPolicy) trusts(fsys fs.FS) bool {
+	for _, trusted := range p.Trusted {
+		if sameFS(trusted, fsys) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameFS reports whether a and b are the same filesystem. Filesystems of
+// types that cannot be compared, such as maps, are never the same.
+func sameFS(a, b fs.FS) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// matchCommand reports whether command matches pattern, in which * matches
+// any text. Runs of whitespace are compared as a single space.
+func matchCommand(pattern, command string) bool {
+	parts := strings.Split(strings.Join(strings.Fields(pattern), " "), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	return re.MatchString(strings.Join(strings.Fields(command), " "))
+}
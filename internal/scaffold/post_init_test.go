@@ -0,0 +1,235 @@
+package scaffold
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPostInitScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("post-init commands in this test need sh")
+	}
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+files:
+  - src: README.md
+    dest: README.md
+post_init:
+  - command: echo once >> once.log
+  - command: echo always >> always.log
+    idempotent: true
+  - command: test -f ready
+`,
+		"app/README.md": "app\n",
+	})
+	s.postInit = NewPostInitRunner(io.Discard, io.Discard)
+	return s
+}
+
+func postInitStatuses(results []PostInitResult) []PostInitStatus {
+	statuses := make([]PostInitStatus, len(results))
+	for i, res := range results {
+		statuses[i] = res.Status
+	}
+	return statuses
+}
+
+func TestScaffoldResumesPostInit(t *testing.T) {
+	s := newPostInitScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, KeepPartial: true}
+
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []PostInitStatus{PostInitSucceeded, PostInitSucceeded, PostInitFailed}, postInitStatuses(result.PostInit))
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	require.Len(t, m.PostInit, 2)
+	assert.Equal(t, "echo once >> once.log", m.PostInit[0].Command)
+	assert.Equal(t, ".", m.PostInit[0].Dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(out, "ready"), nil, 0644))
+
+	result, err = s.Scaffold(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []PostInitStatus{PostInitCompleted, PostInitSucceeded, PostInitSucceeded}, postInitStatuses(result.PostInit))
+
+	once, err := os.ReadFile(filepath.Join(out, "once.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "once\n", string(once))
+	always, err := os.ReadFile(filepath.Join(out, "always.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "always\nalways\n", string(always))
+
+	m, err = manifest.Load(out)
+	require.NoError(t, err)
+	assert.Len(t, m.PostInit, 3)
+
+	// Everything completed, so a run without post-init keeps the records.
+	opts.SkipPostInit = true
+	_, err = s.Scaffold(opts)
+	require.NoError(t, err)
+	m, err = manifest.Load(out)
+	require.NoError(t, err)
+	assert.Len(t, m.PostInit, 3)
+}
+
+func TestScaffoldRerunPostInit(t *testing.T) {
+	s := newPostInitScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.MkdirAll(out, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "ready"), nil, 0644))
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out}
+
+	_, err := s.Scaffold(opts)
+	require.NoError(t, err)
+
+	opts.RerunPostInit = true
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []PostInitStatus{PostInitSucceeded, PostInitSucceeded, PostInitSucceeded}, postInitStatuses(result.PostInit))
+
+	once, err := os.ReadFile(filepath.Join(out, "once.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "once\nonce\n", string(once))
+}
+
+func TestPostInitRunnerStopsAtFirstFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("post-init commands in this test need sh")
+	}
+	dir := t.TempDir()
+	r := NewPostInitRunner(io.Discard, io.Discard)
+
+	results := r.Run([]PostInitStep{
+		{Command: "echo first > first.txt", Dir: dir},
+		{Command: "exit 3", Dir: dir},
+		{Command: "echo third > third.txt", Dir: dir},
+		{Command: "echo fourth > fourth.txt", Dir: dir, Completed: true},
+	})
+
+	assert.Equal(t, []PostInitStatus{PostInitSucceeded, PostInitFailed, PostInitSkipped, PostInitSkipped}, postInitStatuses(results))
+	assert.Error(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+	assert.Zero(t, results[2].Duration)
+	assert.FileExists(t, filepath.Join(dir, "first.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "third.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "fourth.txt"))
+}
+
+func TestScaffoldDeclinedPostInit(t *testing.T) {
+	s := newPostInitScaffolder(t)
+	var asked []string
+	s.confirmPostInit = func(commands []string) (bool, error) {
+		asked = commands
+		return false, nil
+	}
+	out := t.TempDir()
+
+	// Composing without prompts leaves only the confirmation to answer.
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out}
+	tree, contexts, err := s.Compose(opts)
+	require.NoError(t, err)
+
+	opts.Interactive = true
+	results, used, err := s.runPostInit(tree, contexts, nil, out, nil, opts)
+	require.NoError(t, err)
+
+	assert.Len(t, asked, 3)
+	assert.Empty(t, used)
+	assert.Equal(t, []PostInitStatus{PostInitSkipped, PostInitSkipped, PostInitSkipped}, postInitStatuses(results))
+	assert.NoFileExists(t, filepath.Join(out, "once.log"))
+	assert.NoFileExists(t, filepath.Join(out, "always.log"))
+}
+
+func TestScaffoldDeniedPostInit(t *testing.T) {
+	s := newPostInitScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(Options{
+		TemplateRef:    template.TemplateRef{Name: "app"},
+		OutputDir:      out,
+		PostInitPolicy: PostInitPolicy{Deny: []string{"test *"}},
+	})
+
+	// The first command appends to a file, which the patterns cannot check.
+	var denied *PostInitDeniedError
+	require.ErrorAs(t, err, &denied)
+	assert.Equal(t, "echo once >> once.log", denied.Command)
+	assert.Equal(t, "app", denied.Template)
+	assert.Equal(t, ">", denied.Operator)
+	assert.NoDirExists(t, out)
+}
+
+func TestPostInitRunnerRestrictedEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("post-init commands in this test need sh")
+	}
+	t.Setenv("BLUEPRINT_TEST_TOKEN", "secret")
+
+	var out strings.Builder
+	r := NewPostInitRunner(&out, io.Discard).WithRestrictedEnv()
+	results := r.Run([]PostInitStep{{
+		Command: `echo "token=$BLUEPRINT_TEST_TOKEN name=$NAME"`,
+		Dir:     t.TempDir(),
+		Env:     []string{"NAME=app"},
+	}})
+
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "token= name=app\n", out.String())
+}
+
+func TestScaffoldRefusesPostInitWorkDirOutsideOutput(t *testing.T) {
+	for name, workDir := range map[string]string{"parent": "../..", "absolute": "/tmp"} {
+		t.Run(name, func(t *testing.T) {
+			s := newTestScaffolder(t, map[string]string{
+				"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+files:
+  - src: README.md
+    dest: README.md
+post_init:
+  - command: touch escaped
+    workdir: ` + workDir + `
+`,
+				"app/README.md": "app\n",
+			})
+			s.postInit = NewPostInitRunner(io.Discard, io.Discard)
+			out := filepath.Join(t.TempDir(), "app")
+
+			_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "workdir")
+			assert.NoDirExists(t, out)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package scaffold
+
+import (
+	"path"
+
+	"github.com/dhanush0x96c/blueprint/internal/prompt"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// previewInclude loads an include on its own to show what enabling it adds.
+// Only the include itself is described; its own includes are listed by name.
+func (s *Scaffolder) previewInclude(inc template.Include) (*prompt.IncludePreview, error) {
+	loaded, err := s.engine.LoadTemplate(template.TemplateRef{Name: inc.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	node := &template.TemplateNode{Template: loaded.Template, Inherited: inc.Inherits}
+	tmpl := loaded.Template
+
+	preview := &prompt.IncludePreview{
+		Description:  tmpl.Description,
+		Dependencies: tmpl.Dependencies,
+	}
+	for _, v := range node.RequiredVariables() {
+		preview.Variables = append(preview.Variables, v.Name)
+	}
+	for _, f := range tmpl.Files {
+		preview.Files = append(preview.Files, path.Join(inc.Mount, f.Dest))
+	}
+	for _, child := range tmpl.Includes {
+		preview.Includes = append(preview.Includes, child.Name)
+	}
+
+	return preview, nil
+}
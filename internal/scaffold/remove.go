@@ -0,0 +1,205 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// RemoveResult reports the changes Remove makes, or would make in a dry run.
+// Paths are relative to the project root.
+type RemoveResult struct {
+	Template  string
+	Component string // Name of the removed component generation; empty for a feature
+	Node      string
+	Removed   []string // Files the template wrote
+	Reverted  []string // Files of other templates the template injected code into
+	Modified  []string // Files changed since they were written, or no longer holding injected code; only removed with force
+}
+
+// Remove takes a feature, or a component generation, back out of the project
+// rooted at root, using what the project manifest records about it: the
+// files it wrote are deleted, and the code it injected into other files is
+// taken out of them. name is the name of a component generation or of a
+// template added to the project. Files changed since the template wrote them
+// are only deleted with force, and injected code that cannot be found is then
+// left alone; otherwise nothing is changed and a *RemoveConflictError lists
+// them.
+//
+// The removal is journaled like a scaffold run, so that Undo restores the
+// files.
+func Remove(root, name string, force, dryRun bool) (*RemoveResult, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+
+	m, err := manifest.Load(absRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RemoveResult{}
+	if component, ok := m.Component(name); ok {
+		result.Template, result.Component, result.Node = component.Template, component.Name, component.Node
+	} else if id, ok := addedNodeID(m, name); ok {
+		result.Template, result.Node = name, id
+	} else {
+		return nil, &NotAddedError{Name: name, Dir: root}
+	}
+	if result.Node == "0" {
+		return nil, fmt.Errorf("%s is the project template; only features and components can be removed", name)
+	}
+
+	owned := func(id string) bool {
+		return id == result.Node || strings.HasPrefix(id, result.Node+".")
+	}
+	modified := func(p string) {
+		if !slices.Contains(result.Modified, p) {
+			result.Modified = append(result.Modified, p)
+		}
+	}
+
+	for _, f := range m.Files {
+		if !owned(f.Node) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(absRoot, filepath.FromSlash(f.Path)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, err)
+		}
+		if manifest.HashContent(content) != f.Hash {
+			modified(f.Path)
+		}
+		result.Removed = append(result.Removed, f.Path)
+	}
+
+	// Injections are taken out in the reverse of the order they were made.
+	reverted := make(map[string][]byte)
+	for i := len(m.Injections) - 1; i >= 0; i-- {
+		injection := m.Injections[i]
+		if !owned(injection.Node) || slices.Contains(result.Removed, injection.Path) {
+			continue
+		}
+
+		content, ok := reverted[injection.Path]
+		if !ok {
+			content, err = os.ReadFile(filepath.Join(absRoot, filepath.FromSlash(injection.Path)))
+			if errors.Is(err, os.ErrNotExist) {
+				modified(injection.Path)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", injection.Path, err)
+			}
+		}
+
+		out, found, err := template.UninjectFile(content, injectedSnippet(injection))
+		if err != nil {
+			return nil, fmt.Errorf("cannot take code of %s out of %s: %w", result.Template, injection.Path, err)
+		}
+		if !found {
+			modified(injection.Path)
+			continue
+		}
+		if !ok {
+			result.Reverted = append(result.Reverted, injection.Path)
+		}
+		reverted[injection.Path] = out
+	}
+
+	if len(result.Modified) > 0 && !force {
+		return nil, &RemoveConflictError{Template: result.Template, Files: result.Modified}
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	lock, err := AcquireLock(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	journal := NewJournal()
+	if err := applyRemoval(absRoot, m, result, reverted, owned, journal); err != nil {
+		return nil, &RolledBackError{Dir: root, Err: err, RollbackErr: journal.Rollback()}
+	}
+	if err := journal.Save(absRoot, result.Template); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applyRemoval deletes and rewrites the files of a removal, journaling every
+// change, and records the removal in the manifest.
+func applyRemoval(
+	absRoot string,
+	m *manifest.Manifest,
+	result *RemoveResult,
+	reverted map[string][]byte,
+	owned func(id string) bool,
+	journal *Journal,
+) error {
+	for _, p := range result.Removed {
+		full := filepath.Join(absRoot, filepath.FromSlash(p))
+		if err := journal.RecordFile(full); err != nil {
+			return err
+		}
+		if err := os.Remove(full); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+	}
+
+	hashes := make(map[string][2]string, len(reverted))
+	for _, p := range result.Reverted {
+		full := filepath.Join(absRoot, filepath.FromSlash(p))
+		before, err := currentHash(full)
+		if err != nil {
+			return err
+		}
+		if err := journal.RecordFile(full); err != nil {
+			return err
+		}
+		// Writing the existing file keeps its permissions.
+		if err := os.WriteFile(full, reverted[p], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p, err)
+		}
+		hashes[p] = [2]string{before, manifest.HashContent(reverted[p])}
+	}
+
+	m.Nodes = slices.DeleteFunc(m.Nodes, func(n manifest.Node) bool { return owned(n.ID) })
+	m.Files = slices.DeleteFunc(m.Files, func(f manifest.File) bool { return owned(f.Node) })
+	for i, f := range m.Files {
+		// Files that were not changed otherwise do not count as changed by
+		// the user.
+		if h, ok := hashes[f.Path]; ok && f.Hash == h[0] {
+			m.Files[i].Hash = h[1]
+		}
+	}
+	m.Injections = slices.DeleteFunc(m.Injections, func(inj manifest.Injection) bool { return owned(inj.Node) })
+	m.Components = slices.DeleteFunc(m.Components, func(c manifest.Component) bool { return owned(c.Node) })
+	m.Versions = slices.DeleteFunc(m.Versions, func(v manifest.Version) bool {
+		return !slices.ContainsFunc(m.Nodes, func(n manifest.Node) bool { return n.Template == v.Template })
+	})
+
+	if err := journal.RecordFile(manifest.Path(absRoot)); err != nil {
+		return err
+	}
+	if err := m.Save(absRoot); err != nil {
+		return err
+	}
+
+	removeEmptyDirs(absRoot, result.Removed, nil)
+	return nil
+}
@@ -0,0 +1,167 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const removeServerGo = `package main
+
+type Server struct {
+	name string
+}
+
+func (s *Server) routes(r Router) {
+}
+`
+
+func TestRemove(t *testing.T) {
+	s := newInjectScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, SkipPostInit: true})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(out, "server.go"), []byte(removeServerGo), 0644))
+	original, err := os.ReadFile(filepath.Join(out, "main.go"))
+	require.NoError(t, err)
+
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	require.NoError(t, err)
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "logging"}, OutputDir: out})
+	require.NoError(t, err)
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	assert.Len(t, m.Injections, 5, "the injections of the included and the added templates are recorded")
+
+	// A dry run changes nothing.
+	plan, err := Remove(out, "logging", false, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"server.go"}, plan.Reverted)
+	assert.Empty(t, plan.Removed)
+
+	result, err := Remove(out, "logging", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "0.2", result.Node)
+	content, err := os.ReadFile(filepath.Join(out, "server.go"))
+	require.NoError(t, err)
+	assert.Equal(t, removeServerGo, string(content))
+
+	result, err = Remove(out, "metrics", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go"}, result.Reverted)
+	content, err = os.ReadFile(filepath.Join(out, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(content))
+
+	// The file of the project template is not counted as changed.
+	m, err = manifest.Load(out)
+	require.NoError(t, err)
+	f, ok := m.FileByPath("main.go")
+	require.True(t, ok)
+	assert.Equal(t, manifest.HashContent(content), f.Hash)
+	_, ok = m.Node("0.1")
+	assert.False(t, ok)
+	assert.Len(t, m.Injections, 1)
+
+	// A template included when the project was generated can be removed too.
+	_, err = Remove(out, "health", false, false)
+	require.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(out, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "func routes(r Router) {\n\t// blueprint:routes\n}\n", string(content))
+
+	// Undo restores the removal.
+	_, err = Undo(out, false, false)
+	require.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(out, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(original), string(content))
+	_, ok = mustLoadManifest(t, out).Node("0.0")
+	assert.True(t, ok)
+
+	var notAdded *NotAddedError
+	_, err = Remove(out, "metrics", false, false)
+	require.ErrorAs(t, err, &notAdded)
+	_, err = Remove(out, "app", false, false)
+	assert.ErrorContains(t, err, "only features and components can be removed")
+}
+
+func TestRemove_Conflict(t *testing.T) {
+	s := newInjectScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, SkipPostInit: true})
+	require.NoError(t, err)
+	_, err = s.Add(Options{TemplateRef: template.TemplateRef{Name: "metrics"}, OutputDir: out})
+	require.NoError(t, err)
+
+	mainPath := filepath.Join(out, "main.go")
+	changed := "func routes(r Router) {\n\tr.Get(\"/health\", health)\n\tr.Get(\"/metrics\", metricsHandler)\n\t// blueprint:routes\n}\n"
+	require.NoError(t, os.WriteFile(mainPath, []byte(changed), 0644))
+
+	var conflict *RemoveConflictError
+	_, err = Remove(out, "metrics", false, false)
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"main.go"}, conflict.Files)
+
+	// With force the code that cannot be found is left alone.
+	result, err := Remove(out, "metrics", true, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Reverted)
+	content, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, changed, string(content))
+	_, ok := mustLoadManifest(t, out).Node("0.1")
+	assert.False(t, ok)
+}
+
+func TestRemove_Files(t *testing.T) {
+	s := newAddScaffolder(t, "error")
+	out := scaffoldAPI(t, s, "chi")
+
+	for _, resource := range []string{"users", "orders"} {
+		_, err := s.Add(Options{
+			TemplateRef: template.TemplateRef{Name: "components/handler"},
+			OutputDir:   out,
+			Variables:   vars.Variables{Global: map[string]string{"resource": resource}},
+		})
+		require.NoError(t, err)
+	}
+
+	result, err := Remove(out, "handler", false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "components/handler", result.Template)
+	assert.Equal(t, []string{"handlers/users.txt"}, result.Removed)
+	assert.NoFileExists(t, filepath.Join(out, "handlers", "users.txt"))
+	assert.FileExists(t, filepath.Join(out, "handlers", "orders.txt"))
+
+	m := mustLoadManifest(t, out)
+	require.Len(t, m.Components, 1)
+	assert.Equal(t, "handler-2", m.Components[0].Name)
+	_, ok := m.FileByPath("handlers/users.txt")
+	assert.False(t, ok)
+
+	// A changed file is only deleted with force; empty directories go with it.
+	require.NoError(t, os.WriteFile(filepath.Join(out, "handlers", "orders.txt"), []byte("mine\n"), 0644))
+	var conflict *RemoveConflictError
+	_, err = Remove(out, "handler-2", false, false)
+	require.ErrorAs(t, err, &conflict)
+	assert.FileExists(t, filepath.Join(out, "handlers", "orders.txt"))
+
+	_, err = Remove(out, "handler-2", true, false)
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(out, "handlers"))
+}
+
+func mustLoadManifest(t *testing.T, root string) *manifest.Manifest {
+	t.Helper()
+	m, err := manifest.Load(root)
+	require.NoError(t, err)
+	return m
+}
@@ -0,0 +1,257 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// RenameChange is a managed file affected by renaming a project.
+type RenameChange struct {
+	Path    string // Project-relative path before the rename
+	NewPath string // Project-relative path after the rename
+	Diff    string // Unified diff of the content, empty if only the path changes
+
+	content []byte
+	rehash  bool // The file was unmodified, so its recorded hash follows the new content
+}
+
+// RenamePlan describes the changes that rename a scaffolded project.
+type RenamePlan struct {
+	Root      string
+	OldName   string
+	NewName   string
+	Changes   []RenameChange
+	Variables []string // Recorded variables whose values change
+
+	manifest *manifest.Manifest
+}
+
+// replacement replaces one spelling of the old project name.
+type replacement struct {
+	old string
+	new string
+}
+
+// PlanRename computes the changes needed to rename the project rooted at root
+// from the current value of the given project name variable to newName. Every
+// spelling of the old name derived from it (lowercase, uppercase, snake_case,
+// SCREAMING_SNAKE_CASE, kebab-case) is replaced in the content and paths of
+// files recorded in the manifest, and in the recorded variable values, so
+// derived identifiers such as module paths, binary names, and resource names
+// follow. Matches inside longer words are left alone.
+func PlanRename(root, variable, newName string) (*RenamePlan, error) {
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	rootNode, ok := m.Node("0")
+	if !ok {
+		return nil, fmt.Errorf("manifest has no root template")
+	}
+
+	oldName, ok := rootNode.Variables[variable].(string)
+	if !ok || oldName == "" {
+		return nil, fmt.Errorf("manifest does not record a value for the project name variable %q", variable)
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("new project name must not be empty")
+	}
+	if newName == oldName {
+		return nil, fmt.Errorf("project is already named %s", oldName)
+	}
+
+	reps := nameReplacements(oldName, newName)
+	plan := &RenamePlan{Root: root, OldName: oldName, NewName: newName, manifest: m}
+
+	managed := make(map[string]bool, len(m.Files))
+	for _, f := range m.Files {
+		managed[f.Path] = true
+	}
+
+	for _, f := range m.Files {
+		full := filepath.Join(root, filepath.FromSlash(f.Path))
+		content, err := os.ReadFile(full)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, err)
+		}
+
+		change := RenameChange{
+			Path:    f.Path,
+			NewPath: replaceName(f.Path, reps),
+			content: content,
+			rehash:  manifest.HashContent(content) == f.Hash,
+		}
+
+		if !template.IsBinary(content) {
+			updated := replaceName(string(content), reps)
+			if updated != string(content) {
+				diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+					A:        difflib.SplitLines(string(content)),
+					B:        difflib.SplitLines(updated),
+					FromFile: "a/" + change.Path,
+					ToFile:   "b/" + change.NewPath,
+					Context:  2,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to diff %s: %w", f.Path, err)
+				}
+				change.Diff = diff
+				change.content = []byte(updated)
+			}
+		}
+
+		if change.Diff == "" && change.NewPath == change.Path {
+			continue
+		}
+
+		if change.NewPath != change.Path && !managed[change.NewPath] {
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(change.NewPath))); err == nil {
+				return nil, fmt.Errorf("cannot move %s to %s: destination already exists", change.Path, change.NewPath)
+			}
+		}
+
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	seen := make(map[string]bool)
+	for i := range m.Nodes {
+		for name, value := range m.Nodes[i].Variables {
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if updated := replaceName(s, reps); updated != s {
+				m.Nodes[i].Variables[name] = updated
+				if !seen[name] {
+					seen[name] = true
+					plan.Variables = append(plan.Variables, name)
+				}
+			}
+		}
+	}
+	sort.Strings(plan.Variables)
+
+	return plan, nil
+}
+
+// Apply writes the planned changes and updates the manifest. If any step
+// fails, the files touched so far are restored.
+func (p *RenamePlan) Apply() (err error) {
+	journal := NewJournal()
+	defer func() {
+		if err != nil {
+			if rbErr := journal.Rollback(); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	writer := NewWriter().WithJournal(journal)
+
+	for _, change := range p.Changes {
+		oldPath := filepath.Join(p.Root, filepath.FromSlash(change.Path))
+		newPath := filepath.Join(p.Root, filepath.FromSlash(change.NewPath))
+
+		if err := writer.WriteFile(newPath, change.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", change.NewPath, err)
+		}
+
+		if newPath != oldPath {
+			if err := journal.RecordFile(oldPath); err != nil {
+				return err
+			}
+			if err := os.Remove(oldPath); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+			}
+		}
+
+		if f, ok := p.manifest.FileByPath(change.Path); ok {
+			f.Path = change.NewPath
+			if change.rehash {
+				f.Hash = manifest.HashContent(change.content)
+			}
+		}
+	}
+
+	if err := journal.RecordFile(manifest.Path(p.Root)); err != nil {
+		return err
+	}
+
+	return p.manifest.Save(p.Root)
+}
+
+// nameReplacements returns the spellings of oldName and their counterparts in
+// newName, longest first.
+func nameReplacements(oldName, newName string) []replacement {
+	variants := []func(string) string{
+		func(s string) string { return s },
+		strings.ToLower,
+		strings.ToUpper,
+		func(s string) string { return strings.ReplaceAll(strings.ToLower(s), "-", "_") },
+		func(s string) string { return strings.ReplaceAll(strings.ToUpper(s), "-", "_") },
+		func(s string) string { return strings.ReplaceAll(strings.ToLower(s), "_", "-") },
+	}
+
+	seen := make(map[string]bool)
+	var reps []replacement
+	for _, variant := range variants {
+		o := variant(oldName)
+		if seen[o] {
+			continue
+		}
+		seen[o] = true
+		reps = append(reps, replacement{old: o, new: variant(newName)})
+	}
+
+	sort.SliceStable(reps, func(i, j int) bool {
+		return len(reps[i].old) > len(reps[j].old)
+	})
+	return reps
+}
+
+// replaceName replaces every occurrence of the old spellings in s that is not
+// part of a longer word.
+func replaceName(s string, reps []replacement) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		matched := false
+		for _, rep := range reps {
+			end := i + len(rep.old)
+			if !strings.HasPrefix(s[i:], rep.old) {
+				continue
+			}
+			if (i > 0 && isWordByte(s[i-1])) || (end < len(s) && isWordByte(s[end])) {
+				continue
+			}
+
+			b.WriteString(rep.new)
+			i = end
+			matched = true
+			break
+		}
+
+		if !matched {
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
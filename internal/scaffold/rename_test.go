@@ -0,0 +1,102 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceName(t *testing.T) {
+	reps := nameReplacements("my-app", "billing-svc")
+
+	cases := map[string]string{
+		"module github.com/acme/my-app":   "module github.com/acme/billing-svc",
+		"name: my-app-worker":             "name: billing-svc-worker",
+		"MY_APP_PORT=8080":                "BILLING_SVC_PORT=8080",
+		"package my_app":                  "package billing_svc",
+		"not-my-apple nor amy-app":        "not-my-apple nor amy-app",
+		"cmd/my-app/main.go":              "cmd/billing-svc/main.go",
+		"My-App is different from my-app": "My-App is different from billing-svc",
+	}
+
+	for input, expected := range cases {
+		assert.Equal(t, expected, replaceName(input, reps), input)
+	}
+}
+
+func TestRename(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"go.mod":             "module github.com/acme/my-app\n",
+		"cmd/my-app/main.go": "package main // my-app\n",
+		"README.md":          "# Unrelated\n",
+		"edited.txt":         "user changed my-app\n",
+	}
+
+	m := &manifest.Manifest{
+		SchemaVersion: manifest.SchemaVersion,
+		Template:      "go-cli",
+		Nodes: []manifest.Node{{
+			ID:        "0",
+			Template:  "go-cli",
+			Variables: map[string]any{"app_name": "my-app", "module_path": "github.com/acme/my-app", "port": 8080},
+			Roles:     map[string]string{"project_name": "app_name"},
+		}},
+	}
+	for p, content := range files {
+		writeCleanFixture(t, root, p)
+		require.NoError(t, os.WriteFile(filepath.Join(root, filepath.FromSlash(p)), []byte(content), 0644))
+		hash := manifest.HashContent([]byte(content))
+		if p == "edited.txt" {
+			hash = "edited-by-user"
+		}
+		m.Files = append(m.Files, manifest.File{Path: p, Node: "0", Hash: hash})
+	}
+	require.NoError(t, m.Save(root))
+
+	plan, err := PlanRename(root, "app_name", "billing-svc")
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", plan.OldName)
+	assert.Len(t, plan.Changes, 3)
+	assert.Equal(t, []string{"app_name", "module_path"}, plan.Variables)
+
+	require.NoError(t, plan.Apply())
+
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	require.NoError(t, err)
+	assert.Equal(t, "module github.com/acme/billing-svc\n", string(content))
+
+	assert.NoFileExists(t, filepath.Join(root, "cmd", "my-app", "main.go"))
+	content, err = os.ReadFile(filepath.Join(root, "cmd", "billing-svc", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main // billing-svc\n", string(content))
+
+	updated, err := manifest.Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, "billing-svc", updated.Nodes[0].Variables["app_name"])
+	assert.Equal(t, "github.com/acme/billing-svc", updated.Nodes[0].Variables["module_path"])
+
+	moved, ok := updated.FileByPath("cmd/billing-svc/main.go")
+	require.True(t, ok)
+	assert.Equal(t, manifest.HashContent([]byte("package main // billing-svc\n")), moved.Hash)
+
+	edited, ok := updated.FileByPath("edited.txt")
+	require.True(t, ok)
+	assert.Equal(t, "edited-by-user", edited.Hash)
+}
+
+func TestPlanRename_SameName(t *testing.T) {
+	root := t.TempDir()
+	m := &manifest.Manifest{
+		Nodes: []manifest.Node{{ID: "0", Variables: map[string]any{"app_name": "my-app"}}},
+	}
+	require.NoError(t, m.Save(root))
+
+	_, err := PlanRename(root, "app_name", "my-app")
+	require.Error(t, err)
+}
@@ -2,8 +2,16 @@ package scaffold
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
 	"github.com/dhanush0x96c/blueprint/internal/prompt"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 	"github.com/dhanush0x96c/blueprint/internal/vars"
@@ -14,85 +22,420 @@ type Scaffolder struct {
 	engine       *template.Engine
 	promptEngine *prompt.Engine
 	writer       *Writer
+	postInit     *PostInitRunner
+	goToolchain  *goToolchain
+	tools        *toolChecker
+	builtins     func() template.Builtins
+
+	// confirmPostInit asks whether post-init commands may run.
+	confirmPostInit func(commands []string) (bool, error)
 }
 
 // NewScaffolder creates a new scaffolder with the given template resolver.
 func NewScaffolder(resolver template.Resolver) *Scaffolder {
+	promptEngine := prompt.NewEngine()
 	return &Scaffolder{
-		engine:       template.NewEngine(resolver),
-		promptEngine: prompt.NewEngine(),
-		writer:       NewWriter(),
+		engine:          template.NewEngine(resolver),
+		promptEngine:    promptEngine,
+		writer:          NewWriter(),
+		postInit:        NewPostInitRunner(os.Stdout, os.Stderr),
+		goToolchain:     newGoToolchain(),
+		tools:           newToolChecker(),
+		builtins:        sync.OnceValue(DetectBuiltins),
+		confirmPostInit: promptEngine.ConfirmPostInit,
 	}
 }
 
 // Options contains options for scaffolding
 type Options struct {
-	TemplateRef     template.TemplateRef // Template reference to scaffold
-	OutputDir       string               // Output directory for scaffolded files
-	Variables       vars.Variables       // Pre-provided variables
-	EnabledIncludes map[string]bool      // Pre-selected includes (skip prompt)
-	Interactive     bool                 // Whether to prompt for variables
-	DryRun          bool                 // If true, don't write files
-	Overwrite       bool                 // Whether to overwrite existing files
+	TemplateRef        template.TemplateRef       // Template reference to scaffold
+	OutputDir          string                     // Output directory for scaffolded files
+	OutputParent       string                     // Directory the project is created in when OutputDir is empty
+	Variables          vars.Variables             // Pre-provided variables
+	IncludeOverrides   []vars.IncludeOverride     // Variables of single templates in the tree; each must be declared by its target
+	Defaults           map[string]any             // Variable defaults from the user configuration
+	Mandated           map[template.Type][]string // Includes mandated per template type
+	LicenseHeader      string                     // License header overriding the template's
+	LineEndings        template.LineEnding        // Line endings of generated text files overriding the template's
+	FinalNewline       bool                       // Terminates the last line of every generated text file, whatever the template sets
+	Locale             string                     // Locale of file variants for templates without a locale variable
+	EnabledIncludes    map[string]bool            // Pre-selected includes (skip prompt)
+	Interactive        bool                       // Whether to prompt for variables
+	DryRun             bool                       // If true, don't write files
+	Shadow             bool                       // With DryRun, plans files by scaffolding into a temporary copy of the output directory
+	Overwrite          bool                       // Whether to overwrite existing files
+	Backup             BackupMode                 // How files replaced with Overwrite are backed up; BackupSuffix if empty
+	SkipPostInit       bool                       // If true, don't run post-init commands
+	RerunPostInit      bool                       // Runs post-init commands again that an earlier run completed
+	PostInitPolicy     PostInitPolicy             // Which post-init commands may run, and how
+	SkipToolChecks     bool                       // If true, don't check that the tools templates require are installed
+	KeepPartial        bool                       // If true, keep written files when scaffolding fails
+	ShowContent        bool                       // If true, a dry run keeps the content of planned files
+	AllowOutsideOutput bool                       // Confirms writing files outside the output directory for templates that allow it
+	Prune              bool                       // Removes unmodified files of templates no longer in the tree without asking
+	StrictDeps         bool                       // Fails instead of warning when templates depend on different versions of a package
+	ComponentName      string                     // Name of the generation when adding a component; derived from the template if empty
+	OnEvent            func(Event)                // Called with the progress of the run, if set
+	Draft              *prompt.Draft              // Saves prompted answers for crash recovery, if set
+
+	shadowOf string    // Real output directory of a shadow run
+	addTo    *addition // Project the tree is added to
 }
 
 // Result contains the results of a scaffolding operation
 type Result struct {
-	FilesWritten []string            // List of files written
-	FilesSkipped []string            // List of files skipped (already exist)
-	Dependencies []string            // Dependencies that need to be installed
-	PostInitCmds []template.PostInit // Post-init commands to run
+	OutputDir          string              // Directory the project was scaffolded into
+	FilesWritten       []string            // List of files written
+	FilesSkipped       []string            // List of files skipped (already exist)
+	Backups            []Backup            // Copies of the files replaced with Options.Overwrite
+	Dependencies       []string            // Dependencies that need to be installed
+	PostInitCmds       []template.PostInit // Post-init commands declared by the tree
+	PostInit           []PostInitResult    // Outcome of each executed post-init command
+	PostInitEnv        []string            // Names of environment variables used by post-init
+	Planned            []PlannedFile       // Files a dry run would write
+	NextSteps          []string            // Rendered next steps of the templates in the tree; empty for a dry run
+	Warnings           []template.Warning  // Non-fatal issues found while scaffolding
+	Mandated           []string            // Includes composed by organization policy
+	Removed            []string            // Files of templates no longer in the tree that were removed
+	Stale              []StaleFile         // Files of templates no longer in the tree that were kept
+	UnusedDependencies []string            // Dependencies only declared by templates no longer in the tree
+	Answers            *Answers            // Variables and include selections, for replaying the scaffold
+}
+
+// PostInitErr returns the error of the first failed post-init command, if any.
+func (r *Result) PostInitErr() error {
+	for _, res := range r.PostInit {
+		if res.Status == PostInitFailed {
+			return fmt.Errorf("post-init command %q failed: %w", res.Command, res.Err)
+		}
+	}
+	return nil
 }
 
-// Scaffold performs the complete scaffolding operation
-func (s *Scaffolder) Scaffold(opts Options) (*Result, error) {
-	tree, err := s.resolveTemplateTree(opts)
+// Scaffold performs the complete scaffolding operation. Every change made to
+// the output directory is journaled; if writing files or running post-init
+// commands fails, the changes are rolled back unless opts.KeepPartial is set.
+// The journal of a completed run is saved in the project for Undo.
+func (s *Scaffolder) Scaffold(opts Options) (result *Result, err error) {
+	tree, contexts, err := s.resolveTemplateTree(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	contexts, err := s.collectVariables(tree, opts)
+	outputDir, err := s.determineOutputDir(tree, contexts, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	outputDir, err := s.determineOutputDir(tree, contexts, opts)
+	if opts.DryRun && opts.Shadow {
+		return s.shadow(tree, contexts, outputDir, opts)
+	}
+	return s.scaffoldTree(tree, contexts, outputDir, opts)
+}
+
+// scaffoldTree writes a composed tree into outputDir and runs its post-init
+// commands.
+func (s *Scaffolder) scaffoldTree(
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	outputDir string,
+	opts Options,
+) (result *Result, err error) {
+	if !opts.SkipToolChecks {
+		if err := s.checkTools(tree); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.StrictDeps {
+		if conflicts := tree.DependencyConflicts(); len(conflicts) > 0 {
+			return nil, &template.DependencyConflictError{Conflicts: conflicts}
+		}
+	}
+
+	previous := loadPreviousManifest(outputDir, tree)
+
+	// Env-only secrets are left out of rendering and only reach post-init
+	// commands.
+	contexts, secretEnv := splitSecrets(tree, contexts)
+
+	journal := NewJournal()
+	if !opts.DryRun {
+		if err := journal.RecordDirs(outputDir); err != nil {
+			return nil, err
+		}
+
+		lock, lockErr := AcquireLock(outputDir)
+		if lockErr != nil {
+			return nil, lockErr
+		}
+		defer lock.Release()
+
+		defer func() {
+			if err != nil && !opts.KeepPartial {
+				err = &RolledBackError{Dir: outputDir, Err: err, RollbackErr: journal.Rollback()}
+			}
+		}()
+	}
+
+	dirs := make(map[string]string)
+	if err := s.collectNodeDirs(tree, contexts, "", dirs); err != nil {
+		return nil, err
+	}
+
+	// Post-init commands the policy refuses fail the run before anything is
+	// written.
+	if !opts.SkipPostInit {
+		steps, err := s.postInitSteps(tree, contexts, secretEnv, outputDir, previous, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := opts.PostInitPolicy.check(steps); err != nil {
+			return nil, err
+		}
+	}
+
+	renderResult, err := s.render(tree, contexts, dirs, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	renderResult, err := s.render(tree, contexts)
+	// Next steps are rendered before anything is written so that a broken
+	// template fails early, but only reported for a real run.
+	var nextSteps []string
+	if err := s.renderNextSteps(tree, contexts, &nextSteps); err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		nextSteps = nil
+	}
+
+	// Files of includes that were disabled since the previous run are only
+	// removed for a real run; a dry run reports them.
+	stale := findStaleFiles(outputDir, previous, tree, renderResult, dirs)
+	var removed []string
+	if !opts.DryRun {
+		removed, stale, err = s.pruneStaleFiles(outputDir, stale, opts, journal)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var written, skipped []string
+	var backups []Backup
+	var planned []PlannedFile
+	if opts.DryRun {
+		planned, err = planFiles(tree, renderResult, dirs, outputDir, opts)
+	} else {
+		if opts.shadowOf != "" {
+			// A shadow run plans the rendered files against the real output
+			// directory and does not write files outside it.
+			if planned, err = planFiles(tree, renderResult, dirs, opts.shadowOf, opts); err != nil {
+				return nil, err
+			}
+			dropOutsideFiles(renderResult, dirs)
+		}
+		written, skipped, backups, err = s.writeFiles(tree, renderResult, contexts, dirs, outputDir, previous, stale, opts, journal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	postInit, envUsed, err := s.runPostInit(tree, contexts, secretEnv, outputDir, previous, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordPostInit(outputDir, postInit, journal); err != nil {
+		return nil, err
+	}
+	journal.RecordPostInitEnv(envUsed)
+
+	result = &Result{
+		OutputDir:          outputDir,
+		FilesWritten:       written,
+		FilesSkipped:       skipped,
+		Backups:            backups,
+		Planned:            planned,
+		NextSteps:          nextSteps,
+		Dependencies:       tree.AllDependencies(),
+		PostInitCmds:       tree.AllPostInit(),
+		PostInit:           postInit,
+		PostInitEnv:        envUsed,
+		Warnings:           s.warnings(tree, opts, renderResult),
+		Mandated:           mandatedIncludes(tree),
+		Removed:            removed,
+		Stale:              stale,
+		UnusedDependencies: s.unusedDependencies(previous, tree),
+		Answers:            newAnswers(tree, contexts),
+	}
+
+	if !opts.KeepPartial {
+		if err := result.PostInitErr(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The journal is kept so that `blueprint undo` can revert the run.
+	if !opts.DryRun {
+		if err := journal.Save(outputDir, tree.Template.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// warnings collects the non-fatal issues found while composing and rendering
+// the tree.
+func (s *Scaffolder) warnings(tree *template.TemplateNode, opts Options, renderResult *template.RenderResult) []template.Warning {
+	warnings := unusedVariableWarnings(tree, opts.Variables)
+	warnings = append(warnings, s.goVersionWarnings(tree)...)
+	for _, c := range tree.DependencyConflicts() {
+		warnings = append(warnings, template.Warning{Message: "conflicting dependency versions for " + c.String()})
+	}
+	return append(warnings, renderResult.Warnings...)
+}
+
+// SetPostInitOutput streams the output of post-init commands to the given
+// writers instead of stdout and stderr.
+func (s *Scaffolder) SetPostInitOutput(stdout, stderr io.Writer) {
+	s.postInit = NewPostInitRunner(stdout, stderr)
+}
+
+// EnableFuncLibraries makes the functions of the given optional libraries
+// available to every template.
+func (s *Scaffolder) EnableFuncLibraries(names ...string) error {
+	for _, name := range names {
+		if err := s.engine.EnableFuncLibrary(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rendered is a scaffolded project that has not been written. The content of
+// its files is rendered on demand by RenderedFile.Load.
+type Rendered struct {
+	Tree      *template.TemplateNode
+	Contexts  template.RenderContexts
+	Files     []template.RenderedFile // Paths are slash-separated and relative to the project root
+	Warnings  []template.Warning
+	NextSteps []string
+}
+
+// Compose composes the template tree for opts and collects the variables of
+// every node without rendering any file.
+func (s *Scaffolder) Compose(opts Options) (*template.TemplateNode, template.RenderContexts, error) {
+	return s.resolveTemplateTree(opts)
+}
+
+// Render composes the template tree for opts and plans its files without
+// writing anything to disk.
+func (s *Scaffolder) Render(opts Options) (*Rendered, error) {
+	tree, contexts, err := s.resolveTemplateTree(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	written, skipped, err := s.writeFiles(tree, renderResult, contexts, outputDir, opts)
+	dirs := make(map[string]string)
+	if err := s.collectNodeDirs(tree, contexts, "", dirs); err != nil {
+		return nil, err
+	}
+
+	renderResult, err := s.render(tree, contexts, dirs, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Result{
-		FilesWritten: written,
-		FilesSkipped: skipped,
-		Dependencies: tree.AllDependencies(),
-		PostInitCmds: tree.AllPostInit(),
-	}, nil
+	rendered := &Rendered{
+		Tree:     tree,
+		Contexts: contexts,
+		Warnings: s.warnings(tree, opts, renderResult),
+	}
+	if err := s.renderNextSteps(tree, contexts, &rendered.NextSteps); err != nil {
+		return nil, err
+	}
+
+	var addNode func(node *template.TemplateNode)
+	addNode = func(node *template.TemplateNode) {
+		for _, file := range renderResult.Files[node.ID] {
+			file.Path = template.OutputPath(dirs[node.ID], filepath.ToSlash(file.Path))
+			rendered.Files = append(rendered.Files, file)
+		}
+		for _, child := range node.Children {
+			addNode(child)
+		}
+	}
+	addNode(tree)
+
+	return rendered, nil
 }
 
-func (s *Scaffolder) resolveTemplateTree(opts Options) (*template.TemplateNode, error) {
+// resolveTemplateTree composes the template tree and collects the variables of
+// each node as it is composed. In interactive mode the answers are shown for
+// review afterwards; when one is changed, the tree is composed again and only
+// forms that were not answered before are shown.
+func (s *Scaffolder) resolveTemplateTree(opts Options) (*template.TemplateNode, template.RenderContexts, error) {
+	if !opts.Interactive {
+		return s.composeTree(opts, s.promptEngine)
+	}
+
+	answers := prompt.NewAnswers()
+	promptEngine := s.promptEngine.WithAnswers(answers).WithIncludePreview(s.previewInclude)
+	for {
+		answers.BeginPass()
+
+		tree, contexts, err := s.composeTree(opts, promptEngine)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		confirmed, err := promptEngine.Review()
+		if err != nil {
+			return nil, nil, err
+		}
+		if confirmed {
+			return tree, contexts, nil
+		}
+	}
+}
+
+// composeTree composes the template tree once, collecting variables with the
+// given prompt engine.
+func (s *Scaffolder) composeTree(opts Options, promptEngine *prompt.Engine) (*template.TemplateNode, template.RenderContexts, error) {
 	var confirm template.ConfirmIncludes
 	if opts.Interactive {
-		confirm = s.promptEngine.PromptIncludes
+		confirm = promptEngine.PromptIncludes
 	} else {
 		confirm = s.confirmIncludesFromOptions(opts.EnabledIncludes)
 	}
 
-	tree, err := s.engine.GetFullTree(opts.TemplateRef, confirm)
+	pipelineOpts := opts
+	pipelineOpts.Variables = opts.Variables.WithIncludeOverrides(opts.IncludeOverrides)
+	builtins := s.builtins()
+	builtins.Locale = opts.Locale
+	pipeline := newVariablePipeline(s.engine, promptEngine, pipelineOpts, builtins)
+
+	tree, err := s.engine.GetFullTree(opts.TemplateRef, template.ComposeOptions{
+		Confirm:   confirm,
+		Variables: pipeline.CollectNode,
+		Mandated:  opts.Mandated,
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return tree, nil
+	if err := checkIncludeOverrides(tree, opts.IncludeOverrides); err != nil {
+		return nil, nil, err
+	}
+
+	contexts, err := pipeline.Finalize(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.applyGoVersion(tree, contexts)
+
+	return tree, contexts, nil
 }
 
 func (s *Scaffolder) confirmIncludesFromOptions(enabledIncludes map[string]bool) template.ConfirmIncludes {
@@ -113,11 +456,6 @@ func (s *Scaffolder) confirmIncludesFromOptions(enabledIncludes map[string]bool)
 	}
 }
 
-func (s *Scaffolder) collectVariables(tree *template.TemplateNode, opts Options) (template.RenderContexts, error) {
-	pipeline := newVariablePipeline(tree, s.engine, s.promptEngine, opts)
-	return pipeline.Collect()
-}
-
 func (s *Scaffolder) determineOutputDir(
 	tree *template.TemplateNode,
 	contexts template.RenderContexts,
@@ -137,36 +475,164 @@ func (s *Scaffolder) determineOutputDir(
 		return "", err
 	}
 
-	return projectName, nil
+	dir := slugify(projectName)
+	if dir == "" {
+		return "", fmt.Errorf("project name %q has no letters or digits to name the output directory after; "+
+			"give an output directory", projectName)
+	}
+
+	return filepath.Join(opts.OutputParent, dir), nil
 }
 
-func (s *Scaffolder) render(tree *template.TemplateNode, contexts template.RenderContexts) (*template.RenderResult, error) {
-	renderResult, err := s.engine.RenderNode(tree, contexts)
+// slugify turns a project name into a directory name: letters are converted
+// to lowercase, and every run of other characters than letters and digits
+// becomes a single dash, so that "My API_Service" is my-api-service.
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+func (s *Scaffolder) render(
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	dirs map[string]string,
+	opts Options,
+) (*template.RenderResult, error) {
+	renderResult, err := s.engine.PlanNode(tree, contexts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render template tree: %w", err)
 	}
+
+	if err := s.confineOutputPaths(renderResult, tree, dirs, opts); err != nil {
+		return nil, err
+	}
+
+	if err := renderResult.ResolveCollisions(tree, dirs); err != nil {
+		return nil, err
+	}
+
+	if err := renderResult.ApplyInjections(tree, dirs); err != nil {
+		return nil, err
+	}
+
+	header := tree.Template.LicenseHeader
+	if opts.LicenseHeader != "" {
+		header = opts.LicenseHeader
+	}
+
+	if header != "" {
+		text, err := s.engine.RenderText(header, contexts[tree.ID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to render license header: %w", err)
+		}
+		renderResult.ApplyHeader(text)
+	}
+
+	endings := tree.Template.LineEndings
+	if opts.LineEndings != "" {
+		endings = opts.LineEndings
+	}
+	renderResult.ApplyLineEndings(endings, tree.Template.FinalNewline || opts.FinalNewline)
+
 	return renderResult, nil
 }
 
+// confineOutputPaths rejects files rendered outside the output directory.
+// Files of templates that allow it are kept when opts.AllowOutsideOutput is set or
+// the user confirms them; a dry run lists them without asking.
+func (s *Scaffolder) confineOutputPaths(
+	renderResult *template.RenderResult,
+	tree *template.TemplateNode,
+	dirs map[string]string,
+	opts Options,
+) error {
+	outside, err := renderResult.ConfineOutputPaths(tree, dirs, hostDirs())
+	if err != nil {
+		return err
+	}
+	if len(outside) == 0 || opts.AllowOutsideOutput || opts.DryRun || opts.shadowOf != "" {
+		return nil
+	}
+
+	if opts.Interactive {
+		paths := make([]string, len(outside))
+		for i, f := range outside {
+			paths[i] = f.Path
+		}
+
+		confirmed, err := s.promptEngine.ConfirmOutsideOutput(paths)
+		if err != nil {
+			return err
+		}
+		if confirmed {
+			return nil
+		}
+	}
+
+	return &template.OutsideOutputError{Files: outside}
+}
+
+// hostDirs returns the directories of the host file targets. A directory that
+// cannot be determined is left empty.
+func hostDirs() template.HostDirs {
+	home, _ := os.UserHomeDir()
+
+	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(xdgConfig) && home != "" {
+		xdgConfig = filepath.Join(home, ".config")
+	}
+
+	return template.HostDirs{Home: home, XDGConfig: xdgConfig}
+}
+
 func (s *Scaffolder) writeFiles(
 	tree *template.TemplateNode,
 	renderResult *template.RenderResult,
 	contexts template.RenderContexts,
+	dirs map[string]string,
 	outputDir string,
+	previous *manifest.Manifest,
+	stale []StaleFile,
 	opts Options,
-) ([]string, []string, error) {
+	journal *Journal,
+) ([]string, []string, []Backup, error) {
 	written := make([]string, 0)
 	skipped := make([]string, 0)
+	var backups []Backup
 
-	if opts.DryRun {
-		return written, skipped, nil
+	writer := s.writer.WithJournal(journal)
+	if opts.Overwrite && opts.Backup != BackupNone {
+		writer = writer.WithBackups(newBackups(opts.Backup, outputDir, time.Now()))
+	}
+	recorder := newManifestRecorder(outputDir, tree, contexts, dirs)
+	progress := newFileProgress(opts, outputDir, renderResult)
+	if err := s.writeNode(tree, renderResult, contexts, outputDir, opts, writer, recorder, progress, &written, &skipped, &backups); err != nil {
+		return nil, nil, nil, err
 	}
 
-	if err := s.writeNode(tree, renderResult, contexts, outputDir, opts, &written, &skipped); err != nil {
-		return nil, nil, err
+	if opts.addTo != nil {
+		recorder.addTo(opts.addTo, tree)
+	} else {
+		recorder.carryOver(previous, tree, stale)
+	}
+	recorder.updateInjected()
+	if err := recorder.save(journal); err != nil {
+		return nil, nil, nil, err
 	}
 
-	return written, skipped, nil
+	return written, skipped, backups, nil
 }
 
 func (s *Scaffolder) writeNode(
@@ -175,8 +641,12 @@ func (s *Scaffolder) writeNode(
 	contexts template.RenderContexts,
 	outputDir string,
 	opts Options,
+	writer *Writer,
+	recorder *manifestRecorder,
+	progress *fileProgress,
 	written *[]string,
 	skipped *[]string,
+	backups *[]Backup,
 ) error {
 	nodeOutputDir, err := s.resolveNodeOutputDir(node, contexts, outputDir)
 	if err != nil {
@@ -185,16 +655,49 @@ func (s *Scaffolder) writeNode(
 
 	files, ok := renderResult.Files[node.ID]
 	if ok {
-		writeResult, err := s.writer.WriteFiles(nodeOutputDir, files, opts.Overwrite)
+		writeResult, err := writer.OnRender(func(file template.RenderedFile) {
+			progress.fileRendered(node, nodeOutputDir, file)
+		}).OnWrite(func(file template.RenderedFile, content []byte) {
+			recorder.record(node, nodeOutputDir, file, content)
+			progress.fileWritten(node, nodeOutputDir, file)
+		}).WriteFiles(nodeOutputDir, files, opts.Overwrite)
 		if err != nil {
 			return err
 		}
 		*written = append(*written, writeResult.Written...)
 		*skipped = append(*skipped, writeResult.Skipped...)
+		*backups = append(*backups, writeResult.Backups...)
+	}
+
+	injected, err := injectFiles(node, renderResult.Injections[node.ID], nodeOutputDir, writer, recorder)
+	if err != nil {
+		return err
 	}
+	*written = append(*written, injected...)
 
 	for _, child := range node.Children {
-		if err := s.writeNode(child, renderResult, contexts, nodeOutputDir, opts, written, skipped); err != nil {
+		if err := s.writeNode(child, renderResult, contexts, nodeOutputDir, opts, writer, recorder, progress, written, skipped, backups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderNextSteps renders the next steps of every template in the tree with
+// the template's own variables.
+func (s *Scaffolder) renderNextSteps(node *template.TemplateNode, contexts template.RenderContexts, steps *[]string) error {
+	if node.Template.NextSteps != "" {
+		text, err := s.engine.RenderText(node.Template.NextSteps, contexts[node.ID])
+		if err != nil {
+			return fmt.Errorf("failed to render next steps of %s: %w", node.Template.Name, err)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			*steps = append(*steps, text)
+		}
+	}
+
+	for _, child := range node.Children {
+		if err := s.renderNextSteps(child, contexts, steps); err != nil {
 			return err
 		}
 	}
@@ -230,3 +733,246 @@ func (s *Scaffolder) resolveNodeOutputDir(
 
 	return filepath.Join(parentDir, projectName), nil
 }
+
+// collectNodeDirs records the output directory of every node relative to the
+// output root.
+func (s *Scaffolder) collectNodeDirs(
+	node *template.TemplateNode,
+	contexts template.RenderContexts,
+	parentDir string,
+	dirs map[string]string,
+) error {
+	dir, err := s.resolveNodeOutputDir(node, contexts, parentDir)
+	if err != nil {
+		return err
+	}
+	dirs[node.ID] = filepath.ToSlash(dir)
+
+	for _, child := range node.Children {
+		if err := s.collectNodeDirs(child, contexts, dir, dirs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Scaffolder) runPostInit(
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	secretEnv map[string][]string,
+	outputDir string,
+	previous *manifest.Manifest,
+	opts Options,
+) ([]PostInitResult, []string, error) {
+	if opts.DryRun || opts.SkipPostInit {
+		return nil, nil, nil
+	}
+
+	steps, err := s.postInitSteps(tree, contexts, secretEnv, outputDir, previous, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(steps) == 0 {
+		return nil, nil, nil
+	}
+
+	var pending, unconfirmed []string
+	for _, step := range steps {
+		if step.Completed {
+			continue
+		}
+		pending = append(pending, step.Command)
+		if !step.Trusted {
+			unconfirmed = append(unconfirmed, describePostInitStep(step, outputDir))
+		}
+	}
+	if len(pending) == 0 {
+		return s.postInit.SkipAll(steps), nil, nil
+	}
+
+	// Commands of trusted templates run without confirmation.
+	if opts.Interactive && len(unconfirmed) > 0 {
+		confirmed, err := s.confirmPostInit(unconfirmed)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !confirmed {
+			return s.postInit.SkipAll(steps), nil, nil
+		}
+	}
+
+	env, used, err := s.resolvePostInitEnv(tree.AllEnv(), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner := s.postInit.WithEvents(opts.emit)
+	if opts.PostInitPolicy.Env == PostInitEnvRestricted {
+		env = append(env, declaredEnv(tree)...)
+		runner = runner.WithRestrictedEnv()
+	}
+
+	// Each step also receives the secrets of the template declaring it.
+	for i := range steps {
+		steps[i].Env = append(slices.Clip(env), steps[i].Env...)
+	}
+
+	return runner.Run(steps), used, nil
+}
+
+// postInitSteps collects the post-init commands of the tree. Commands an
+// earlier run into the project completed are marked as such, unless they are
+// safe to run again, and commands of templates the policy trusts are marked
+// as trusted.
+func (s *Scaffolder) postInitSteps(
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	secretEnv map[string][]string,
+	outputDir string,
+	previous *manifest.Manifest,
+	opts Options,
+) ([]PostInitStep, error) {
+	var steps []PostInitStep
+	if err := s.collectPostInitSteps(tree, contexts, secretEnv, outputDir, opts.PostInitPolicy, &steps); err != nil {
+		return nil, err
+	}
+
+	// Like files, commands stay inside the output directory.
+	for _, step := range steps {
+		if rel, err := filepath.Rel(outputDir, step.Dir); err != nil || !filepath.IsLocal(rel) {
+			return nil, fmt.Errorf("workdir of post-init command %q of template %s is outside the output directory",
+				step.Command, step.Template)
+		}
+	}
+
+	for i, step := range steps {
+		if previous != nil && !opts.RerunPostInit && !step.Idempotent {
+			_, steps[i].Completed = previous.PostInitStep(step.Command, postInitDir(outputDir, step.Dir))
+		}
+	}
+	return steps, nil
+}
+
+// describePostInitStep describes a post-init command for confirmation: the
+// exact command, the template declaring it, and where it runs.
+func describePostInitStep(step PostInitStep, outputDir string) string {
+	return fmt.Sprintf("$ %s  (%s, in %s)", step.Command, step.Template, postInitDir(outputDir, step.Dir))
+}
+
+// resolvePostInitEnv fills the environment variables declared for post-init
+// commands. Values already present in the environment take precedence, then
+// declared defaults, then interactive prompts. It returns the extra environment
+// entries to inject and the names of all declared variables that are set.
+func (s *Scaffolder) resolvePostInitEnv(declared []template.EnvVar, opts Options) ([]string, []string, error) {
+	var env []string
+	var used []string
+	var missing []template.EnvVar
+
+	for _, e := range declared {
+		if _, ok := os.LookupEnv(e.Name); ok {
+			used = append(used, e.Name)
+			continue
+		}
+
+		if e.Default != "" {
+			env = append(env, e.Name+"="+e.Default)
+			used = append(used, e.Name)
+			continue
+		}
+
+		if opts.Interactive && e.Prompt != "" {
+			missing = append(missing, e)
+			continue
+		}
+
+		if e.Required {
+			return nil, nil, fmt.Errorf("post-init requires environment variable %s to be set", e.Name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return env, used, nil
+	}
+
+	prompted, err := s.promptEngine.PromptEnv(missing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, e := range missing {
+		value := prompted[e.Name]
+		if value == "" {
+			if e.Required {
+				return nil, nil, fmt.Errorf("post-init requires environment variable %s to be set", e.Name)
+			}
+			continue
+		}
+		env = append(env, e.Name+"="+value)
+		used = append(used, e.Name)
+	}
+
+	return env, used, nil
+}
+
+func (s *Scaffolder) collectPostInitSteps(
+	node *template.TemplateNode,
+	contexts template.RenderContexts,
+	secretEnv map[string][]string,
+	parentDir string,
+	policy PostInitPolicy,
+	steps *[]PostInitStep,
+) error {
+	nodeOutputDir, err := s.resolveNodeOutputDir(node, contexts, parentDir)
+	if err != nil {
+		return err
+	}
+
+	ctx, ok := contexts[node.ID]
+	if !ok {
+		return fmt.Errorf("no context found for template %s (ID: %s)", node.Template.Name, node.ID)
+	}
+
+	for _, cmd := range node.Template.PostInit {
+		dir := nodeOutputDir
+		if cmd.WorkDir != "" {
+			workDir, err := s.engine.RenderPath(cmd.WorkDir, ctx)
+			if err != nil {
+				return fmt.Errorf("failed to render workdir for post-init command %q: %w", cmd.Command, err)
+			}
+			if filepath.IsAbs(workDir) || filepath.VolumeName(workDir) != "" {
+				return fmt.Errorf("workdir %s of post-init command %q of template %s must be relative",
+					workDir, cmd.Command, node.Template.Name)
+			}
+			dir = filepath.Join(nodeOutputDir, workDir)
+		}
+
+		*steps = append(*steps, PostInitStep{
+			Command:    cmd.Command,
+			Dir:        dir,
+			Template:   node.Template.Name,
+			Env:        secretEnv[node.ID],
+			Idempotent: cmd.Idempotent,
+			Trusted:    policy.trusts(node.FS),
+		})
+	}
+
+	for _, child := range node.Children {
+		if err := s.collectPostInitSteps(child, contexts, secretEnv, nodeOutputDir, policy, steps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mandatedIncludes returns the names of all mandated nodes in the tree.
+func mandatedIncludes(node *template.TemplateNode) []string {
+	var names []string
+	if node.Mandated {
+		names = append(names, node.Template.Name)
+	}
+	for _, child := range node.Children {
+		names = append(names, mandatedIncludes(child)...)
+	}
+	return names
+}
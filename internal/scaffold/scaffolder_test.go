@@ -0,0 +1,136 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"demo":               "demo",
+		"My API_Service":     "my-api-service",
+		"  --billing v2-- ":  "billing-v2",
+		"../../etc":          "etc",
+		"Café Überweisungen": "café-überweisungen",
+		"!!!":                "",
+	}
+
+	for name, want := range tests {
+		assert.Equal(t, want, slugify(name), name)
+	}
+}
+
+func TestScaffoldDefaultOutputDir(t *testing.T) {
+	s := newStaleScaffolder(t)
+	dir := t.TempDir()
+
+	opts := staleOptions("", false)
+	opts.OutputParent = dir
+	opts.Variables = vars.Variables{Global: map[string]string{"name": "My Service"}}
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "my-service"), result.OutputDir)
+	assert.FileExists(t, filepath.Join(dir, "my-service", "main.txt"))
+
+	opts.Variables = vars.Variables{Global: map[string]string{"name": "???"}}
+	_, err = s.Scaffold(opts)
+	require.ErrorContains(t, err, `project name "???" has no letters or digits`)
+}
+
+func TestScaffoldDependencyConflicts(t *testing.T) {
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+includes:
+  - name: extra
+    enabled_by_default: true
+dependencies:
+  - github.com/spf13/cobra@v1.8.0
+`,
+		"extra/" + template.FileName: `name: extra
+type: feature
+version: 1.0.0
+description: Extras
+dependencies:
+  - github.com/spf13/cobra@v1.10.2
+`,
+	})
+
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: t.TempDir(), DryRun: true}
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.com/spf13/cobra@v1.10.2"}, result.Dependencies)
+	assert.Contains(t, result.Warnings, template.Warning{
+		Message: "conflicting dependency versions for github.com/spf13/cobra: app requests v1.8.0, extra requests v1.10.2; using v1.10.2",
+	})
+
+	opts.StrictDeps = true
+	_, err = s.Scaffold(opts)
+	var conflictErr *template.DependencyConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "github.com/spf13/cobra", conflictErr.Conflicts[0].Package)
+}
+
+func TestScaffoldLineEndings(t *testing.T) {
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+line_endings: crlf
+final_newline: true
+files:
+  - src: README.md
+    dest: README.md
+  - src: run.sh
+    dest: run.sh
+`,
+		"app/README.md": "# App\r\nUsage\n",
+		"app/run.sh":    "echo hi",
+	})
+
+	out := t.TempDir()
+	_, err := s.Scaffold(Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out, SkipPostInit: true})
+	require.NoError(t, err)
+
+	readme, err := os.ReadFile(filepath.Join(out, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# App\r\nUsage\r\n", string(readme))
+	script, err := os.ReadFile(filepath.Join(out, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo hi\r\n", string(script))
+
+	// The configured line endings override the template's.
+	_, err = s.Scaffold(Options{
+		TemplateRef:  template.TemplateRef{Name: "app"},
+		OutputDir:    out,
+		Overwrite:    true,
+		Backup:       BackupNone,
+		LineEndings:  template.LineEndingLF,
+		SkipPostInit: true,
+	})
+	require.NoError(t, err)
+	script, err = os.ReadFile(filepath.Join(out, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo hi\n", string(script))
+}
@@ -0,0 +1,64 @@
+package scaffold
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// splitSecrets returns the contexts files are rendered with, which leave out
+// the env-only secrets of each node, and the environment each node's
+// post-init commands receive its secrets in, keyed by node ID. The given
+// contexts are not modified.
+func splitSecrets(tree *template.TemplateNode, contexts template.RenderContexts) (template.RenderContexts, map[string][]string) {
+	rendered := make(template.RenderContexts, len(contexts))
+	maps.Copy(rendered, contexts)
+	env := make(map[string][]string)
+
+	var walk func(node *template.TemplateNode)
+	walk = func(node *template.TemplateNode) {
+		if ctx, ok := contexts[node.ID]; ok {
+			var stripped *template.Context
+			for _, v := range node.Template.Variables {
+				if !v.IsSecret() {
+					continue
+				}
+				value, ok := ctx.Get(v.Name)
+				if !ok {
+					continue
+				}
+				env[node.ID] = append(env[node.ID], fmt.Sprintf("%s=%v", v.EnvName(), value))
+
+				if v.EnvOnly {
+					if stripped == nil {
+						stripped = template.NewTemplateContext(maps.Clone(ctx.Variables))
+						rendered[node.ID] = stripped
+					}
+					delete(stripped.Variables, v.Name)
+				}
+			}
+		}
+
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	return rendered, env
+}
+
+// secretNames returns the names of the secrets a node declares.
+func secretNames(node *template.TemplateNode) map[string]bool {
+	var names map[string]bool
+	for _, v := range node.Template.Variables {
+		if v.IsSecret() {
+			if names == nil {
+				names = make(map[string]bool)
+			}
+			names[v.Name] = true
+		}
+	}
+	return names
+}
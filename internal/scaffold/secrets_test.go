@@ -0,0 +1,76 @@
+package scaffold
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("post-init commands in this test need sh")
+	}
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+  - name: api_token
+    prompt: API token?
+    type: secret
+    env_only: true
+  - name: signing_key
+    prompt: Signing key?
+    type: secret
+files:
+  - src: key.txt.tmpl
+    dest: key.txt
+post_init:
+  - command: printf '%s' "$API_TOKEN:$SIGNING_KEY" > env.txt
+`,
+		"app/key.txt.tmpl": "{{ .signing_key }}|{{ .api_token }}",
+	})
+	s.postInit = NewPostInitRunner(io.Discard, io.Discard)
+
+	out := filepath.Join(t.TempDir(), "app")
+	result, err := s.Scaffold(Options{
+		TemplateRef: template.TemplateRef{Name: "app"},
+		OutputDir:   out,
+		Variables: vars.Variables{Global: map[string]string{
+			"name":        "demo",
+			"api_token":   "t0ken",
+			"signing_key": "k3y",
+		}},
+	})
+	require.NoError(t, err)
+
+	rendered, err := os.ReadFile(filepath.Join(out, "key.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "k3y|<no value>", string(rendered), "an env-only secret is not rendered")
+
+	env, err := os.ReadFile(filepath.Join(out, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "t0ken:k3y", string(env))
+
+	assert.Equal(t, map[string]map[string]any{"app": {"name": "demo"}}, result.Answers.Variables)
+
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	require.Len(t, m.Nodes, 1)
+	assert.NotContains(t, m.Nodes[0].Variables, "api_token")
+	assert.NotContains(t, m.Nodes[0].Variables, "signing_key")
+	assert.Equal(t, "demo", m.Nodes[0].Variables["name"])
+}
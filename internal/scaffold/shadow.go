@@ -0,0 +1,230 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// shadow performs a dry run by scaffolding into a temporary copy of the
+// output directory and comparing the copy with the original. Unlike a plain
+// dry run, which only renders files, the plan reflects every step of a real
+// run: license headers, collisions, pruned files, and the changes post-init
+// commands make. The output directory itself is never changed.
+func (s *Scaffolder) shadow(
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	outputDir string,
+	opts Options,
+) (*Result, error) {
+	tmp, err := os.MkdirTemp("", "blueprint-shadow-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	shadowDir := filepath.Join(tmp, filepath.Base(outputDir))
+	if err := copyOutputDir(outputDir, shadowDir); err != nil {
+		return nil, err
+	}
+
+	// A failed post-init command is reported with the plan rather than
+	// rolling the copy back.
+	shadowOpts := opts
+	shadowOpts.DryRun = false
+	shadowOpts.KeepPartial = true
+	shadowOpts.shadowOf = outputDir
+	// Backups would show up as new files of the plan.
+	shadowOpts.Backup = BackupNone
+
+	result, err := s.scaffoldTree(tree, contexts, shadowDir, shadowOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	planned, err := planShadow(shadowDir, outputDir, result.Planned, opts.ShowContent)
+	if err != nil {
+		return nil, err
+	}
+
+	result.OutputDir = outputDir
+	result.Planned = planned
+	result.FilesWritten = nil
+	result.FilesSkipped = nil
+	result.Backups = nil
+	result.Removed = nil
+	result.NextSteps = nil
+	return result, nil
+}
+
+// copyOutputDir copies an existing output directory to dir. Nothing is
+// copied when the output directory does not exist yet.
+func copyOutputDir(outputDir, dir string) error {
+	if _, err := os.Stat(outputDir); errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := os.CopyFS(dir, os.DirFS(outputDir)); err != nil {
+		return fmt.Errorf("failed to copy %s to shadow directory: %w", outputDir, err)
+	}
+	return nil
+}
+
+// planShadow compares the shadow directory with the output directory. The
+// rendered files planned by the shadow run are compared again with their
+// final content, followed by the other files the run created or changed and
+// the files it removed. The blueprint state and git directories are not
+// compared.
+func planShadow(shadowDir, outputDir string, rendered []PlannedFile, showContent bool) ([]PlannedFile, error) {
+	planned := make([]PlannedFile, 0, len(rendered))
+	seen := make(map[string]bool)
+
+	for _, p := range rendered {
+		seen[p.Path] = true
+
+		// Files outside the output directory are not written by a shadow run.
+		if !filepath.IsLocal(filepath.FromSlash(p.Path)) {
+			planned = append(planned, p)
+			continue
+		}
+
+		shadowed, err := planShadowFile(p, shadowDir, outputDir, showContent)
+		if err != nil {
+			return nil, err
+		}
+		if shadowed != nil {
+			planned = append(planned, *shadowed)
+		}
+	}
+
+	err := walkProjectFiles(shadowDir, func(rel string) error {
+		if seen[rel] {
+			return nil
+		}
+		seen[rel] = true
+
+		shadowed, err := planShadowFile(PlannedFile{Path: rel}, shadowDir, outputDir, showContent)
+		if err != nil {
+			return err
+		}
+		if shadowed != nil && shadowed.Status != PlanUnchanged {
+			planned = append(planned, *shadowed)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = walkProjectFiles(outputDir, func(rel string) error {
+		if seen[rel] {
+			return nil
+		}
+		info, err := os.Lstat(filepath.Join(outputDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		planned = append(planned, PlannedFile{Path: rel, Size: int(info.Size()), Status: PlanRemove})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return planned, nil
+}
+
+// planShadowFile compares a file in the shadow directory with the file at
+// the same path in the output directory. It returns nil for a file that
+// exists in neither.
+func planShadowFile(p PlannedFile, shadowDir, outputDir string, showContent bool) (*PlannedFile, error) {
+	fullPath := filepath.Join(outputDir, filepath.FromSlash(p.Path))
+
+	content, err := os.ReadFile(filepath.Join(shadowDir, filepath.FromSlash(p.Path)))
+	if errors.Is(err, os.ErrNotExist) {
+		// Written and then removed again, e.g. by a post-init command.
+		info, err := os.Lstat(fullPath)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		return &PlannedFile{Path: p.Path, Size: int(info.Size()), Status: PlanRemove}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", p.Path, err)
+	}
+
+	// A skipped file that nothing else changed keeps the diff of the content
+	// that was not written.
+	if p.Status == PlanSkip {
+		if existing, err := os.ReadFile(fullPath); err == nil && bytes.Equal(existing, content) {
+			return &p, nil
+		}
+	}
+
+	shadowed := PlannedFile{
+		Path:   p.Path,
+		Size:   len(content),
+		Binary: template.IsBinary(content),
+	}
+	if showContent {
+		shadowed.Content = content
+	}
+	if err := comparePlanned(&shadowed, fullPath, content, true); err != nil {
+		return nil, err
+	}
+	return &shadowed, nil
+}
+
+// walkProjectFiles calls fn with the slash-separated path, relative to root,
+// of every regular file under root. Git directories, the blueprint state
+// directory, and the lock file are skipped. A missing root has no files.
+func walkProjectFiles(root string, fn func(rel string) error) error {
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || rel == manifest.Dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || rel == LockFileName {
+			return nil
+		}
+		return fn(filepath.ToSlash(rel))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// dropOutsideFiles removes the files rendered outside the output directory.
+func dropOutsideFiles(renderResult *template.RenderResult, dirs map[string]string) {
+	for id, files := range renderResult.Files {
+		kept := files[:0]
+		for _, file := range files {
+			outPath := template.OutputPath(dirs[id], filepath.ToSlash(file.Path))
+			if filepath.IsLocal(filepath.FromSlash(outPath)) {
+				kept = append(kept, file)
+			}
+		}
+		renderResult.Files[id] = kept
+	}
+}
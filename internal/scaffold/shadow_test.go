@@ -0,0 +1,115 @@
+package scaffold
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShadowScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("post-init commands in this test need sh")
+	}
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+files:
+  - src: README.md
+    dest: README.md
+  - src: main.txt
+    dest: main.txt
+post_init:
+  - command: echo formatted >> README.md
+  - command: echo generated > gen.txt
+  - command: rm -f old.txt
+`,
+		"app/README.md": "app\n",
+		"app/main.txt":  "main\n",
+	})
+	s.postInit = NewPostInitRunner(io.Discard, io.Discard)
+	return s
+}
+
+func plannedByPath(planned []PlannedFile) map[string]PlannedFile {
+	byPath := make(map[string]PlannedFile, len(planned))
+	for _, p := range planned {
+		byPath[p.Path] = p
+	}
+	return byPath
+}
+
+func TestScaffoldShadowDryRun(t *testing.T) {
+	s := newShadowScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.MkdirAll(out, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "README.md"), []byte("old\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "main.txt"), []byte("main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "old.txt"), []byte("old\n"), 0644))
+
+	result, err := s.Scaffold(Options{
+		TemplateRef: template.TemplateRef{Name: "app"},
+		OutputDir:   out,
+		DryRun:      true,
+		Shadow:      true,
+		Overwrite:   true,
+		ShowContent: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, out, result.OutputDir)
+	assert.Empty(t, result.FilesWritten)
+
+	planned := plannedByPath(result.Planned)
+	require.Len(t, planned, 4)
+
+	readme := planned["README.md"]
+	assert.Equal(t, PlanOverwrite, readme.Status)
+	assert.Equal(t, "app\nformatted\n", string(readme.Content))
+	assert.Contains(t, readme.Diff, "+formatted")
+	assert.Equal(t, PlanUnchanged, planned["main.txt"].Status)
+	assert.Equal(t, PlanCreate, planned["gen.txt"].Status)
+	assert.Equal(t, PlanRemove, planned["old.txt"].Status)
+
+	// The output directory is left as it was.
+	readmeContent, err := os.ReadFile(filepath.Join(out, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "old\n", string(readmeContent))
+	assert.FileExists(t, filepath.Join(out, "old.txt"))
+	assert.NoFileExists(t, filepath.Join(out, "gen.txt"))
+	assert.NoDirExists(t, filepath.Join(out, ".blueprint"))
+}
+
+func TestScaffoldShadowDryRunNewProject(t *testing.T) {
+	s := newShadowScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	result, err := s.Scaffold(Options{
+		TemplateRef: template.TemplateRef{Name: "app"},
+		OutputDir:   out,
+		DryRun:      true,
+		Shadow:      true,
+	})
+	require.NoError(t, err)
+
+	planned := plannedByPath(result.Planned)
+	require.Len(t, planned, 3)
+	for _, p := range planned {
+		assert.Equal(t, PlanCreate, p.Status, p.Path)
+	}
+	assert.Equal(t, len("app\nformatted\n"), planned["README.md"].Size)
+	assert.NoDirExists(t, out)
+}
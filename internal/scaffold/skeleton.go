@@ -0,0 +1,123 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// CreateTemplateSkeleton writes a starter template named name into dir: a
+// template.yaml with commented examples of every section and a sample .tmpl
+// file. It refuses to write into an existing, non-empty directory and returns
+// the paths of the created files relative to dir.
+func CreateTemplateSkeleton(dir, name string, typ template.Type) ([]string, error) {
+	if !template.IsValidName(name) {
+		return nil, fmt.Errorf("invalid template name %q: use lowercase letters, digits, and dashes", name)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", dir)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	sample, sampleDest, sampleContent := skeletonSample(name, typ)
+	files := []template.RenderedFile{
+		{Path: template.FileName, Content: []byte(skeletonManifest(name, typ, sample, sampleDest))},
+		{Path: sample, Content: []byte(sampleContent)},
+	}
+
+	result, err := NewWriter().WriteFiles(dir, files, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Written, nil
+}
+
+// skeletonSample returns the sample file of a skeleton: its source path,
+// destination, and content.
+func skeletonSample(name string, typ template.Type) (string, string, string) {
+	if typ == template.TypeProject {
+		return "README.md.tmpl", "README.md", fmt.Sprintf(`# {{ .project_name }}
+
+Generated from the %s template.
+`, name)
+	}
+
+	return "example.txt.tmpl", "example.txt", fmt.Sprintf(`{{/* Reference declared variables as {{ .name }}. */}}
+Added by the %s template.
+`, name)
+}
+
+func skeletonManifest(name string, typ template.Type, sample, sampleDest string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `name: %s
+type: %s
+version: 0.1.0
+schema: %d
+description: "TODO: describe what this template generates"
+tags: []
+
+# Variables are collected before rendering and referenced as {{ .name }}.
+`, name, typ, template.SchemaVersion)
+
+	if typ == template.TypeProject {
+		b.WriteString(`variables:
+  - name: project_name
+    prompt: "What is the project name?"
+    type: string
+    role: project_name # names the output directory
+  # - name: use_docker
+  #   prompt: "Add a Dockerfile?"
+  #   type: bool
+  #   default: false
+  # - name: license
+  #   prompt: "Which license?"
+  #   type: select
+  #   options: [MIT, Apache-2.0]
+  #   default: MIT
+`)
+	} else {
+		b.WriteString(`# variables:
+#   - name: use_docker
+#     prompt: "Add a Dockerfile?"
+#     type: bool
+#     default: false
+`)
+	}
+
+	fmt.Fprintf(&b, `
+# Other templates composed into this one.
+# includes:
+#   - name: go-testing
+#     enabled_by_default: true
+#   - name: go-docker
+#     when: "{{ .use_docker }}"
+
+# Files to generate. Files ending in .tmpl are rendered with the variables and
+# the extension is stripped; other files are copied as-is. Directories are
+# copied recursively.
+files:
+  - src: %s
+    dest: %s
+  # - src: Dockerfile
+  #   dest: Dockerfile
+  #   when: "{{ .use_docker }}"
+
+# Commands run in the output directory after the files are written.
+# post_init:
+#   - command: "git init"
+#   - command: "npm install"
+#     workdir: frontend
+#     idempotent: true
+`, sample, sampleDest)
+
+	return b.String()
+}
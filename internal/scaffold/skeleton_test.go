@@ -0,0 +1,45 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTemplateSkeleton(t *testing.T) {
+	for _, typ := range []template.Type{template.TypeProject, template.TypeFeature, template.TypeComponent} {
+		t.Run(string(typ), func(t *testing.T) {
+			base := t.TempDir()
+			dir := filepath.Join(base, "my-template")
+
+			files, err := CreateTemplateSkeleton(dir, "my-template", typ)
+			require.NoError(t, err)
+			assert.Len(t, files, 2)
+
+			loaded, err := template.NewLoader().Load(os.DirFS(base), "my-template")
+			require.NoError(t, err)
+			assert.Equal(t, "my-template", loaded.Template.Name)
+			assert.Equal(t, typ, loaded.Template.Type)
+			require.Len(t, loaded.Template.Files, 1)
+			assert.FileExists(t, filepath.Join(dir, loaded.Template.Files[0].Src))
+		})
+	}
+}
+
+func TestCreateTemplateSkeleton_RefusesNonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keep.txt"), []byte("mine"), 0644))
+
+	_, err := CreateTemplateSkeleton(dir, "my-template", template.TypeProject)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not empty")
+}
+
+func TestCreateTemplateSkeleton_InvalidName(t *testing.T) {
+	_, err := CreateTemplateSkeleton(t.TempDir(), "My Template", template.TypeProject)
+	require.Error(t, err)
+}
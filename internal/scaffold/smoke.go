@@ -0,0 +1,220 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// VerifyStep is a verify command of a template bound to the directory it
+// runs in.
+type VerifyStep struct {
+	Template string
+	Command  string
+	Dir      string
+	Trusted  bool // Runs without confirmation
+}
+
+// ErrSmokeDeclined is returned by Smoke when the commands of a smoke test
+// were not confirmed.
+var ErrSmokeDeclined = errors.New("the post-init and verify commands were not confirmed")
+
+// VerifyResult is the outcome of a verify command.
+type VerifyResult struct {
+	VerifyStep
+	Passed   bool
+	Output   []byte // Combined stdout and stderr
+	Duration time.Duration
+	Err      error
+}
+
+// SmokeResult is the outcome of a smoke test of a template.
+type SmokeResult struct {
+	Template string
+	Dir      string // Directory the project was scaffolded into; removed afterwards unless kept
+	Kept     bool
+	Scaffold *Result
+	Verify   []VerifyResult
+}
+
+// Passed reports whether scaffolding and every verify command succeeded.
+func (r *SmokeResult) Passed() bool {
+	if r.Scaffold == nil || r.Scaffold.PostInitErr() != nil {
+		return false
+	}
+	for _, v := range r.Verify {
+		if !v.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Smoke scaffolds a template into a temporary directory without prompting,
+// so that variables take their defaults unless opts.Variables sets them, and
+// then runs the verify commands of every template in the tree, each in the
+// output directory of its template. Unlike post-init commands, every verify
+// command runs even when an earlier one failed. The project is removed
+// afterwards unless keep is set. opts.OutputDir is ignored.
+//
+// Verify commands are held to opts.PostInitPolicy like post-init commands:
+// both are checked before anything is scaffolded, run with the environment
+// the policy selects, and, with opts.Interactive, the commands of untrusted
+// templates run only once confirmed.
+func (s *Scaffolder) Smoke(opts Options, keep bool) (*SmokeResult, error) {
+	tmp, err := os.MkdirTemp("", "blueprint-smoke-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create smoke test directory: %w", err)
+	}
+
+	result := &SmokeResult{Template: opts.TemplateRef.Name, Dir: filepath.Join(tmp, "project")}
+	result.Kept = keep
+	if !keep {
+		defer os.RemoveAll(tmp)
+	}
+
+	confirm := opts.Interactive
+	opts.OutputDir = result.Dir
+	opts.Interactive = false
+	opts.DryRun = false
+	opts.KeepPartial = true
+
+	tree, contexts, err := s.resolveTemplateTree(opts)
+	if err != nil {
+		return result, err
+	}
+	result.Template = tree.Template.Name
+
+	var steps []VerifyStep
+	if err := s.collectVerifySteps(tree, contexts, result.Dir, opts.PostInitPolicy, &steps); err != nil {
+		return result, err
+	}
+	if err := s.checkSmokeCommands(tree, contexts, result.Dir, steps, confirm, opts); err != nil {
+		return result, err
+	}
+
+	result.Scaffold, err = s.scaffoldTree(tree, contexts, result.Dir, opts)
+	if err != nil {
+		return result, err
+	}
+	if result.Scaffold.PostInitErr() != nil {
+		return result, nil
+	}
+
+	var env []string
+	if opts.PostInitPolicy.Env == PostInitEnvRestricted {
+		env = append(restrictedEnv(), declaredEnv(tree)...)
+	}
+	for _, step := range steps {
+		result.Verify = append(result.Verify, runVerifyStep(step, env))
+	}
+
+	return result, nil
+}
+
+// checkSmokeCommands checks the post-init and verify commands of a smoke test
+// against the post-init policy and, with confirm, asks for confirmation of
+// those of untrusted templates.
+func (s *Scaffolder) checkSmokeCommands(
+	tree *template.TemplateNode,
+	contexts template.RenderContexts,
+	outputDir string,
+	verify []VerifyStep,
+	confirm bool,
+	opts Options,
+) error {
+	var commands []PostInitStep
+	if !opts.SkipPostInit {
+		postInit, err := s.postInitSteps(tree, contexts, nil, outputDir, nil, opts)
+		if err != nil {
+			return err
+		}
+		commands = postInit
+	}
+	for _, step := range verify {
+		commands = append(commands, PostInitStep{Command: step.Command, Dir: step.Dir, Template: step.Template, Trusted: step.Trusted})
+	}
+
+	if err := opts.PostInitPolicy.check(commands); err != nil {
+		return err
+	}
+	if !confirm {
+		return nil
+	}
+
+	var unconfirmed []string
+	for _, step := range commands {
+		if !step.Trusted {
+			unconfirmed = append(unconfirmed, describePostInitStep(step, outputDir))
+		}
+	}
+	if len(unconfirmed) == 0 {
+		return nil
+	}
+	confirmed, err := s.confirmPostInit(unconfirmed)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrSmokeDeclined
+	}
+	return nil
+}
+
+// collectVerifySteps collects the verify commands of the tree in order,
+// each bound to the output directory of the node declaring it.
+func (s *Scaffolder) collectVerifySteps(
+	node *template.TemplateNode,
+	contexts template.RenderContexts,
+	parentDir string,
+	policy PostInitPolicy,
+	steps *[]VerifyStep,
+) error {
+	nodeOutputDir, err := s.resolveNodeOutputDir(node, contexts, parentDir)
+	if err != nil {
+		return err
+	}
+
+	for _, command := range node.Template.Verify {
+		*steps = append(*steps, VerifyStep{
+			Template: node.Template.Name,
+			Command:  command,
+			Dir:      nodeOutputDir,
+			Trusted:  policy.trusts(node.FS),
+		})
+	}
+
+	for _, child := range node.Children {
+		if err := s.collectVerifySteps(child, contexts, nodeOutputDir, policy, steps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runVerifyStep runs a verify command. With env set, the command runs with
+// exactly that environment instead of the environment of Blueprint.
+func runVerifyStep(step VerifyStep, env []string) VerifyResult {
+	var output bytes.Buffer
+	cmd := shellCommand(step.Command)
+	cmd.Dir = step.Dir
+	cmd.Env = env
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	err := cmd.Run()
+	return VerifyResult{
+		VerifyStep: step,
+		Passed:     err == nil,
+		Output:     output.Bytes(),
+		Duration:   time.Since(start),
+		Err:        err,
+	}
+}
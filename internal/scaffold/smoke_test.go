@@ -0,0 +1,149 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSmokeScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("verify commands in this test need sh")
+	}
+	return newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+  - name: greeting
+    prompt: Greeting?
+    type: string
+    default: hello
+files:
+  - src: README.md.tmpl
+    dest: README.md
+verify:
+  - test -f README.md
+  - grep -q hello README.md
+  - echo checked && false
+`,
+		"app/README.md.tmpl": "{{ .greeting }}\n",
+	})
+}
+
+func TestSmokeRunsVerifyCommands(t *testing.T) {
+	s := newSmokeScaffolder(t)
+
+	result, err := s.Smoke(Options{TemplateRef: template.TemplateRef{Name: "app"}}, false)
+	require.NoError(t, err)
+	assert.False(t, result.Passed())
+	assert.NoDirExists(t, result.Dir, "the project is removed")
+
+	// Every command runs; the failed one keeps its output.
+	require.Len(t, result.Verify, 3)
+	assert.True(t, result.Verify[0].Passed)
+	assert.True(t, result.Verify[1].Passed)
+	assert.False(t, result.Verify[2].Passed)
+	assert.Error(t, result.Verify[2].Err)
+	assert.Equal(t, "checked\n", string(result.Verify[2].Output))
+	assert.Equal(t, result.Dir, result.Verify[0].Dir)
+}
+
+func TestSmokeUsesVariablesAndKeepsProject(t *testing.T) {
+	s := newSmokeScaffolder(t)
+
+	result, err := s.Smoke(Options{
+		TemplateRef: template.TemplateRef{Name: "app"},
+		Variables:   vars.Variables{Global: map[string]string{"greeting": "bye"}},
+	}, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(filepath.Dir(result.Dir)) })
+
+	assert.True(t, result.Kept)
+	assert.FileExists(t, filepath.Join(result.Dir, "README.md"))
+	assert.False(t, result.Verify[1].Passed, "the greeting given with --var is used")
+}
+
+func TestSmokeHoldsVerifyCommandsToPolicy(t *testing.T) {
+	s := newSmokeScaffolder(t)
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}}
+
+	t.Run("denied commands fail before scaffolding", func(t *testing.T) {
+		opts := opts
+		opts.PostInitPolicy = PostInitPolicy{Deny: []string{"grep *"}}
+
+		result, err := s.Smoke(opts, false)
+		var denied *PostInitDeniedError
+		require.ErrorAs(t, err, &denied)
+		assert.Equal(t, "grep -q hello README.md", denied.Command)
+		assert.Equal(t, "grep *", denied.Pattern)
+		assert.Nil(t, result.Scaffold)
+		assert.Empty(t, result.Verify)
+	})
+
+	t.Run("commands are confirmed", func(t *testing.T) {
+		var asked []string
+		s.confirmPostInit = func(commands []string) (bool, error) {
+			asked = commands
+			return false, nil
+		}
+		opts := opts
+		opts.Interactive = true
+
+		result, err := s.Smoke(opts, false)
+		require.ErrorIs(t, err, ErrSmokeDeclined)
+		assert.Len(t, asked, 3)
+		assert.Nil(t, result.Scaffold)
+		assert.Empty(t, result.Verify)
+	})
+}
+
+func TestSmokeRestrictedEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("verify commands in this test need sh")
+	}
+	t.Setenv("BLUEPRINT_TEST_TOKEN", "t0ken")
+	t.Setenv("BLUEPRINT_TEST_REGION", "eu-west-9")
+
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+env:
+  - name: BLUEPRINT_TEST_REGION
+verify:
+  - test -z "$BLUEPRINT_TEST_TOKEN"
+  - test "$BLUEPRINT_TEST_REGION" = eu-west-9
+`,
+	})
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}}
+
+	result, err := s.Smoke(opts, false)
+	require.NoError(t, err)
+	assert.False(t, result.Verify[0].Passed, "the environment is inherited by default")
+
+	opts.PostInitPolicy = PostInitPolicy{Env: PostInitEnvRestricted}
+	result, err = s.Smoke(opts, false)
+	require.NoError(t, err)
+	assert.True(t, result.Passed())
+}
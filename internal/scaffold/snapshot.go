@@ -0,0 +1,309 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// snapshotFilesDir holds the files of a snapshot that are copied as one
+	// directory.
+	snapshotFilesDir = "files"
+	// snapshotListedDir holds the files of a snapshot that need their own
+	// entry in template.yaml, because their path contains a variable or they
+	// are executable templates.
+	snapshotListedDir = "listed"
+)
+
+// SnapshotVariable is a value of a project that a snapshot replaces with a
+// variable.
+type SnapshotVariable struct {
+	Name  string
+	Value string
+}
+
+// SnapshotOptions describes a project to convert into a template.
+type SnapshotOptions struct {
+	Source    string             // Project directory to snapshot
+	Dir       string             // Directory the template is created in
+	Name      string             // Template name
+	Type      template.Type      // Template type
+	Variables []SnapshotVariable // Values to replace with variables
+	Exclude   []string           // Patterns of paths to leave out, matched against the relative path and the base name
+}
+
+// SnapshotResult describes a template created from a project.
+type SnapshotResult struct {
+	Files        []string       // Created files, relative to the template directory
+	Templated    int            // Number of project files turned into .tmpl files
+	Replacements map[string]int // Number of replaced occurrences per variable
+	Skipped      []string       // Project paths left out, relative to the source
+}
+
+// CreateTemplateSnapshot converts the project at opts.Source into a template
+// in opts.Dir. Occurrences of the variable values in file contents and paths
+// are replaced with references to the variables, literal {{ and }} are
+// escaped, and a draft template.yaml declaring the variables is written.
+// Version control and blueprint state directories are always left out. Like
+// CreateTemplateSkeleton, it refuses to write into a non-empty directory.
+func CreateTemplateSnapshot(opts SnapshotOptions) (*SnapshotResult, error) {
+	if !template.IsValidName(opts.Name) {
+		return nil, fmt.Errorf("invalid template name %q: use lowercase letters, digits, and dashes", opts.Name)
+	}
+
+	info, err := os.Stat(opts.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project %s: %w", opts.Source, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", opts.Source)
+	}
+
+	entries, err := os.ReadDir(opts.Dir)
+	if err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", opts.Dir)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read directory %s: %w", opts.Dir, err)
+	}
+
+	s := &snapshotter{
+		opts:   opts,
+		writer: NewWriter(),
+		result: &SnapshotResult{Replacements: make(map[string]int)},
+	}
+	s.values = append(s.values, opts.Variables...)
+	sort.SliceStable(s.values, func(i, j int) bool {
+		return len(s.values[i].Value) > len(s.values[j].Value)
+	})
+
+	if err := s.walk(); err != nil {
+		return nil, err
+	}
+	if len(s.result.Files) == 0 {
+		return nil, fmt.Errorf("no files to snapshot in %s", opts.Source)
+	}
+
+	if err := s.writeManifest(); err != nil {
+		return nil, err
+	}
+
+	return s.result, nil
+}
+
+// snapshotter copies the files of a project into a template.
+type snapshotter struct {
+	opts     SnapshotOptions
+	values   []SnapshotVariable // Longest value first
+	writer   *Writer
+	result   *SnapshotResult
+	hasFiles bool            // Some file was copied into snapshotFilesDir
+	listed   []template.File // Entries of the files in snapshotListedDir
+}
+
+func (s *snapshotter) walk() error {
+	target, _ := filepath.Abs(s.opts.Dir)
+
+	return filepath.WalkDir(s.opts.Source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(s.opts.Source, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if abs, _ := filepath.Abs(p); abs == target || s.excluded(rel, d.Name()) ||
+				d.Name() == ".git" || d.Name() == manifest.Dir {
+				s.result.Skipped = append(s.result.Skipped, rel+"/")
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() || s.excluded(rel, d.Name()) {
+			s.result.Skipped = append(s.result.Skipped, rel)
+			return nil
+		}
+
+		return s.copyFile(p, rel)
+	})
+}
+
+func (s *snapshotter) excluded(rel, name string) bool {
+	for _, pattern := range s.opts.Exclude {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// copyFile copies a project file into the template, turning it into a .tmpl
+// file when its content references a variable or must be escaped.
+func (s *snapshotter) copyFile(fullPath, rel string) error {
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	executable := info.Mode().Perm()&0o111 != 0
+
+	templated := false
+	if !template.IsBinary(content) {
+		replaced := s.replace(string(content))
+		if replaced != string(content) || strings.HasSuffix(rel, ".tmpl") {
+			content = []byte(replaced)
+			templated = true
+			s.result.Templated++
+		}
+	}
+
+	dest := s.replace(rel)
+	listed := dest != rel || (templated && executable)
+
+	src := path.Join(snapshotFilesDir, rel)
+	if listed {
+		src = path.Join(snapshotListedDir, rel)
+	}
+	if templated {
+		src += ".tmpl"
+	}
+
+	mode := info.Mode().Perm()
+	if err := s.writer.WriteFileWithPerm(filepath.Join(s.opts.Dir, filepath.FromSlash(src)), content, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", src, err)
+	}
+	s.result.Files = append(s.result.Files, src)
+
+	if !listed {
+		s.hasFiles = true
+		return nil
+	}
+
+	entry := template.File{Src: src, Dest: dest}
+	if executable {
+		entry.Mode = fmt.Sprintf("%04o", mode)
+	}
+	s.listed = append(s.listed, entry)
+	return nil
+}
+
+// replace replaces every occurrence of a variable value in text that is not
+// part of a longer word with a reference to the variable, and escapes the
+// literal action delimiters {{ and }}.
+func (s *snapshotter) replace(text string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(text); {
+		matched := false
+		for _, v := range s.values {
+			end := i + len(v.Value)
+			if v.Value == "" || !strings.HasPrefix(text[i:], v.Value) {
+				continue
+			}
+			if (i > 0 && isWordByte(text[i-1])) || (end < len(text) && isWordByte(text[end])) {
+				continue
+			}
+
+			fmt.Fprintf(&b, "{{ .%s }}", v.Name)
+			s.result.Replacements[v.Name]++
+			i = end
+			matched = true
+			break
+		}
+		if matched {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text[i:], "{{"):
+			b.WriteString(`{{ "{{" }}`)
+			i += 2
+		case strings.HasPrefix(text[i:], "}}"):
+			b.WriteString(`{{ "}}" }}`)
+			i += 2
+		default:
+			b.WriteByte(text[i])
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// writeManifest writes the draft template.yaml of the snapshot.
+func (s *snapshotter) writeManifest() error {
+	tmpl := template.Template{
+		Name:        s.opts.Name,
+		Type:        s.opts.Type,
+		Version:     "0.1.0",
+		Description: "TODO: describe what this template generates",
+	}
+
+	for _, v := range s.opts.Variables {
+		variable := template.Variable{
+			Name:    v.Name,
+			Prompt:  fmt.Sprintf("Value for %s?", v.Name),
+			Type:    template.VariableTypeString,
+			Default: v.Value,
+		}
+		if v.Name == "project_name" && s.opts.Type == template.TypeProject {
+			variable.Prompt = "What is the project name?"
+			variable.Role = template.RoleProjectName
+			variable.Default = nil
+		}
+		tmpl.Variables = append(tmpl.Variables, variable)
+	}
+
+	if s.hasFiles {
+		tmpl.Files = append(tmpl.Files, template.File{Src: snapshotFilesDir, Dest: "."})
+	}
+	tmpl.Files = append(tmpl.Files, s.listed...)
+
+	var buf bytes.Buffer
+	source, err := filepath.Abs(s.opts.Source)
+	if err != nil {
+		source = s.opts.Source
+	}
+	fmt.Fprintf(&buf, "# Draft created by `blueprint snapshot` from %s.\n", filepath.Base(source))
+	buf.WriteString("# Review the prompts and description, and add tags, includes, and post_init as needed.\n")
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&tmpl); err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	if err := s.writer.WriteFile(filepath.Join(s.opts.Dir, template.FileName), buf.Bytes()); err != nil {
+		return err
+	}
+	s.result.Files = append([]string{template.FileName}, s.result.Files...)
+	return nil
+}
@@ -0,0 +1,64 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTemplateSnapshot(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "my-app")
+	writeCleanFixture(t, source, ".git/HEAD", "node_modules/dep.js", "LICENSE")
+	require.NoError(t, os.MkdirAll(filepath.Join(source, "cmd", "my-app"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "go.mod"), []byte("module github.com/acme/my-app\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "cmd", "my-app", "main.go"),
+		[]byte("// my-app {{ literal }}\nimport \"github.com/acme/my-app/internal\"\n// my-apps\n"), 0644))
+
+	base := t.TempDir()
+	result, err := CreateTemplateSnapshot(SnapshotOptions{
+		Source: source,
+		Dir:    filepath.Join(base, "my-app"),
+		Name:   "my-app",
+		Type:   template.TypeProject,
+		Variables: []SnapshotVariable{
+			{Name: "project_name", Value: "my-app"},
+			{Name: "module_path", Value: "github.com/acme/my-app"},
+		},
+		Exclude: []string{"node_modules"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Templated)
+	assert.Equal(t, 2, result.Replacements["project_name"])
+	assert.Equal(t, 2, result.Replacements["module_path"])
+	assert.Equal(t, []string{".git/", "node_modules/"}, result.Skipped)
+
+	loaded, err := template.NewLoader().Load(os.DirFS(base), "my-app")
+	require.NoError(t, err)
+	require.Len(t, loaded.Template.Variables, 2)
+	assert.Equal(t, template.RoleProjectName, loaded.Template.Variables[0].Role)
+	assert.Equal(t, "github.com/acme/my-app", loaded.Template.Variables[1].Default)
+	require.Len(t, loaded.Template.Files, 2)
+	assert.Equal(t, template.File{Src: "files", Dest: "."}, loaded.Template.Files[0])
+	assert.Equal(t, "cmd/{{ .project_name }}/main.go", loaded.Template.Files[1].Dest)
+
+	main, err := os.ReadFile(filepath.Join(base, "my-app", loaded.Template.Files[1].Src))
+	require.NoError(t, err)
+	assert.Equal(t, "// {{ .project_name }} {{ \"{{\" }} literal {{ \"}}\" }}\nimport \"{{ .module_path }}/internal\"\n// my-apps\n", string(main))
+	assert.FileExists(t, filepath.Join(base, "my-app", "files", "LICENSE"))
+}
+
+func TestCreateTemplateSnapshot_RefusesNonEmptyDir(t *testing.T) {
+	source := t.TempDir()
+	writeCleanFixture(t, source, "main.go")
+	dir := t.TempDir()
+	writeCleanFixture(t, dir, "keep.txt")
+
+	_, err := CreateTemplateSnapshot(SnapshotOptions{Source: source, Dir: dir, Name: "app", Type: template.TypeComponent})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not empty")
+}
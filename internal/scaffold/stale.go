@@ -0,0 +1,250 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// StaleFile is a file generated by an earlier run for a template that is no
+// longer part of the tree, such as an include that was disabled.
+type StaleFile struct {
+	Path     string // Relative to the project root
+	Template string // Template that generated the file
+	Modified bool   // Changed since it was generated; never removed
+}
+
+// loadPreviousManifest returns the manifest of an earlier run of the same
+// template in outputDir, or nil when there is none.
+func loadPreviousManifest(outputDir string, tree *template.TemplateNode) *manifest.Manifest {
+	previous, err := manifest.Load(outputDir)
+	if err != nil || previous.Template != tree.Template.Name {
+		return nil
+	}
+	return previous
+}
+
+// treeTemplates returns the names of the templates in the tree.
+func treeTemplates(tree *template.TemplateNode) map[string]string {
+	ids := make(map[string]string)
+	var walk func(node *template.TemplateNode)
+	walk = func(node *template.TemplateNode) {
+		if _, ok := ids[node.Template.Name]; !ok {
+			ids[node.Template.Name] = node.ID
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return ids
+}
+
+// findStaleFiles returns the files the previous manifest attributes to
+// templates that are no longer in the tree and that the tree does not
+// produce again. Files whose content differs from the recorded hash, or that
+// cannot be read, are marked as modified.
+func findStaleFiles(
+	outputDir string,
+	previous *manifest.Manifest,
+	tree *template.TemplateNode,
+	renderResult *template.RenderResult,
+	dirs map[string]string,
+) []StaleFile {
+	if previous == nil {
+		return nil
+	}
+
+	current := treeTemplates(tree)
+	produced := make(map[string]bool)
+	for id, files := range renderResult.Files {
+		for _, f := range files {
+			produced[template.OutputPath(dirs[id], filepath.ToSlash(f.Path))] = true
+		}
+	}
+
+	var stale []StaleFile
+	for _, f := range previous.Files {
+		node, ok := previous.Node(f.Node)
+		if !ok || produced[f.Path] {
+			continue
+		}
+		if _, ok := current[node.Template]; ok {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(f.Path)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		stale = append(stale, StaleFile{
+			Path:     f.Path,
+			Template: node.Template,
+			Modified: err != nil || manifest.HashContent(content) != f.Hash,
+		})
+	}
+
+	return stale
+}
+
+// unusedDependencies returns the dependencies of the templates in the
+// previous manifest that are no longer in the tree and that no template of
+// the tree still declares. Templates that cannot be loaded are skipped.
+func (s *Scaffolder) unusedDependencies(previous *manifest.Manifest, tree *template.TemplateNode) []string {
+	if previous == nil {
+		return nil
+	}
+
+	current := treeTemplates(tree)
+	declared := make(map[string]bool)
+	for _, dep := range tree.AllDependencies() {
+		declared[dep] = true
+	}
+
+	var unused []string
+	for _, node := range previous.Nodes {
+		if _, ok := current[node.Template]; ok {
+			continue
+		}
+		loaded, err := s.engine.LoadTemplate(template.TemplateRef{Name: node.Template})
+		if err != nil {
+			continue
+		}
+		for _, dep := range loaded.Template.Dependencies {
+			if !declared[dep] {
+				declared[dep] = true
+				unused = append(unused, dep)
+			}
+		}
+	}
+
+	return unused
+}
+
+// pruneStaleFiles removes the unmodified stale files when opts.Prune is set
+// or the user confirms it, and returns the removed files and the stale files
+// that were kept. Removals are journaled; directories left empty are removed
+// once every file is gone.
+func (s *Scaffolder) pruneStaleFiles(outputDir string, stale []StaleFile, opts Options, journal *Journal) ([]string, []StaleFile, error) {
+	var removable []string
+	var kept []StaleFile
+	for _, f := range stale {
+		if f.Modified {
+			kept = append(kept, f)
+			continue
+		}
+		removable = append(removable, f.Path)
+	}
+	if len(removable) == 0 {
+		return nil, kept, nil
+	}
+
+	remove := opts.Prune
+	if !remove && opts.Interactive {
+		confirmed, err := s.promptEngine.ConfirmRemoveFiles(removable)
+		if err != nil {
+			return nil, nil, err
+		}
+		remove = confirmed
+	}
+	if !remove {
+		return nil, stale, nil
+	}
+
+	for _, p := range removable {
+		fullPath := filepath.Join(outputDir, filepath.FromSlash(p))
+		if err := journal.RecordFile(fullPath); err != nil {
+			return nil, nil, err
+		}
+		if err := os.Remove(fullPath); err != nil {
+			return nil, nil, fmt.Errorf("failed to remove %s: %w", p, err)
+		}
+	}
+	removeEmptyDirs(outputDir, removable, nil)
+
+	return removable, kept, nil
+}
+
+// carryOver keeps the records of files from the previous manifest that were
+// not written again, such as existing files that were skipped, as long as
+// their template is still part of the tree. The records are attached to the
+// first node of the same template. Stale files that were kept stay recorded
+// under their previous node, renamed so that it cannot clash with the tree,
+// so that a later run can still offer to remove them. Completed post-init
+// commands stay recorded until post-init runs again, and code injected by
+// templates of the tree stays recorded for them.
+func (r *manifestRecorder) carryOver(previous *manifest.Manifest, tree *template.TemplateNode, stale []StaleFile) {
+	if previous == nil {
+		return
+	}
+	r.manifest.PostInit = previous.PostInit
+
+	current := treeTemplates(tree)
+	recorded := make(map[string]bool, len(r.manifest.Files))
+	for _, f := range r.manifest.Files {
+		recorded[f.Path] = true
+	}
+	kept := make(map[string]bool, len(stale))
+	for _, f := range stale {
+		kept[f.Path] = true
+	}
+
+	staleNodes := make(map[string]string)
+	for _, f := range previous.Files {
+		node, ok := previous.Node(f.Node)
+		if !ok || recorded[f.Path] {
+			continue
+		}
+
+		if id, ok := current[node.Template]; ok {
+			f.Node = id
+		} else if kept[f.Path] {
+			id, ok := staleNodes[node.ID]
+			if !ok {
+				id = staleNodeID(node.ID)
+				staleNodes[node.ID] = id
+				carried := *node
+				carried.ID = id
+				r.manifest.Nodes = append(r.manifest.Nodes, carried)
+			}
+			f.Node = id
+		} else {
+			continue
+		}
+		r.manifest.Files = append(r.manifest.Files, f)
+	}
+
+	// Code injected by templates of the tree stays in place.
+	for _, injection := range previous.Injections {
+		node, ok := previous.Node(injection.Node)
+		if !ok {
+			continue
+		}
+		id, ok := current[node.Template]
+		if !ok {
+			continue
+		}
+		injection.Node = id
+		if !slices.Contains(r.manifest.Injections, injection) {
+			r.manifest.Injections = append(r.manifest.Injections, injection)
+		}
+	}
+}
+
+// staleNodeID returns the ID under which the node of kept stale files is
+// recorded.
+func staleNodeID(id string) string {
+	if strings.HasPrefix(id, staleNodePrefix) {
+		return id
+	}
+	return staleNodePrefix + id
+}
+
+// staleNodePrefix prefixes the IDs of nodes that are no longer in the tree.
+const staleNodePrefix = "stale."
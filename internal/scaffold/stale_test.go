@@ -0,0 +1,148 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStaleScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+	return newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+includes:
+  - name: extra
+    enabled_by_default: true
+dependencies:
+  - base@1
+files:
+  - src: main.txt
+    dest: main.txt
+`,
+		"app/main.txt": "main\n",
+		"extra/" + template.FileName: `name: extra
+type: feature
+version: 1.0.0
+description: Extras
+dependencies:
+  - base@1
+  - extra@1
+files:
+  - src: extra.txt
+    dest: extra/extra.txt
+  - src: notes.txt
+    dest: notes.txt
+`,
+		"extra/extra.txt": "extra\n",
+		"extra/notes.txt": "notes\n",
+	})
+}
+
+func staleOptions(outputDir string, extra bool) Options {
+	return Options{
+		TemplateRef:     template.TemplateRef{Name: "app"},
+		OutputDir:       outputDir,
+		EnabledIncludes: map[string]bool{"extra": extra},
+	}
+}
+
+func TestScaffoldReportsStaleFiles(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(staleOptions(out, true))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(out, "notes.txt"), []byte("mine\n"), 0644))
+
+	result, err := s.Scaffold(staleOptions(out, false))
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Removed)
+	assert.ElementsMatch(t, []StaleFile{
+		{Path: "extra/extra.txt", Template: "extra"},
+		{Path: "notes.txt", Template: "extra", Modified: true},
+	}, result.Stale)
+	assert.Equal(t, []string{"extra@1"}, result.UnusedDependencies)
+	assert.FileExists(t, filepath.Join(out, "extra", "extra.txt"))
+
+	// Kept files stay recorded so that a later run can still remove them.
+	m, err := manifest.Load(out)
+	require.NoError(t, err)
+	f, ok := m.FileByPath("extra/extra.txt")
+	require.True(t, ok)
+	node, ok := m.Node(f.Node)
+	require.True(t, ok)
+	assert.Equal(t, "extra", node.Template)
+}
+
+func TestScaffoldPrunesStaleFiles(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(staleOptions(out, true))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(out, "notes.txt"), []byte("mine\n"), 0644))
+
+	_, err = s.Scaffold(staleOptions(out, false))
+	require.NoError(t, err)
+
+	opts := staleOptions(out, false)
+	opts.Prune = true
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"extra/extra.txt"}, result.Removed)
+	assert.Equal(t, []StaleFile{{Path: "notes.txt", Template: "extra", Modified: true}}, result.Stale)
+	assert.NoDirExists(t, filepath.Join(out, "extra"))
+	assert.FileExists(t, filepath.Join(out, "notes.txt"))
+	assert.FileExists(t, filepath.Join(out, "main.txt"))
+}
+
+func TestScaffoldDryRunKeepsStaleFiles(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(staleOptions(out, true))
+	require.NoError(t, err)
+
+	opts := staleOptions(out, false)
+	opts.Prune = true
+	opts.DryRun = true
+	result, err := s.Scaffold(opts)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Removed)
+	assert.Len(t, result.Stale, 2)
+	assert.FileExists(t, filepath.Join(out, "extra", "extra.txt"))
+}
+
+func TestScaffoldIgnoresManifestOfOtherTemplate(t *testing.T) {
+	out := t.TempDir()
+	m := &manifest.Manifest{
+		Template: "other",
+		Nodes:    []manifest.Node{{ID: "0", Template: "other"}},
+		Files:    []manifest.File{{Path: "other.txt", Node: "0"}},
+	}
+	require.NoError(t, m.Save(out))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "other.txt"), nil, 0644))
+
+	result, err := newStaleScaffolder(t).Scaffold(staleOptions(out, false))
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Stale)
+	assert.Empty(t, result.UnusedDependencies)
+}
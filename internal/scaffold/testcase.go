@@ -0,0 +1,263 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// TestCasesDir is the directory of a template that holds its test cases,
+	// one directory per case.
+	TestCasesDir = "tests"
+	// testCaseFile describes the input of a test case.
+	testCaseFile = "case.yaml"
+	// testExpectedDir holds the files a test case must render.
+	testExpectedDir = "expected"
+)
+
+// testBuiltins are the values of the builtin variable in test cases, fixed so
+// that expected files do not depend on the machine or the day they are
+// rendered on.
+var testBuiltins = template.Builtins{
+	Now:          time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	Version:      "test",
+	OS:           "linux",
+	Arch:         "amd64",
+	GitUserName:  "Test User",
+	GitUserEmail: "test@example.com",
+}
+
+// TestCase is a test of a template: the variables and include selections to
+// render it with, and the project files it must produce.
+type TestCase struct {
+	Name        string          `yaml:"-"`
+	Dir         string          `yaml:"-"`
+	Description string          `yaml:"description,omitempty"`
+	Variables   map[string]any  `yaml:"variables,omitempty"`
+	Includes    map[string]bool `yaml:"includes,omitempty"` // Includes not listed keep enabled_by_default
+}
+
+// ExpectedDir returns the directory holding the files the case must render.
+func (tc TestCase) ExpectedDir() string {
+	return filepath.Join(tc.Dir, testExpectedDir)
+}
+
+// TestResult is the outcome of a test case.
+type TestResult struct {
+	Case       string
+	Missing    []string       // Expected files that were not rendered
+	Unexpected []string       // Rendered files that are not expected
+	Changed    []TestFileDiff // Files rendered with different content
+	Updated    bool           // The expected files were replaced with the rendered ones
+	Err        error          // Rendering failed
+}
+
+// TestFileDiff is an expected file rendered with different content.
+type TestFileDiff struct {
+	Path string
+	Diff string // Unified diff from the expected to the rendered content; empty for binary files
+}
+
+// Passed reports whether the case rendered the expected files.
+func (r *TestResult) Passed() bool {
+	return r.Err == nil && len(r.Missing) == 0 && len(r.Unexpected) == 0 && len(r.Changed) == 0
+}
+
+// LoadTestCases reads the test cases of the template in dir: every directory
+// in its tests directory that holds a case.yaml, sorted by name. A template
+// without a tests directory has no cases.
+func LoadTestCases(dir string) ([]TestCase, error) {
+	testsDir := filepath.Join(dir, TestCasesDir)
+	entries, err := os.ReadDir(testsDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read test cases: %w", err)
+	}
+
+	var cases []TestCase
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		caseDir := filepath.Join(testsDir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(caseDir, testCaseFile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read test case %s: %w", entry.Name(), err)
+		}
+
+		tc := TestCase{Name: entry.Name(), Dir: caseDir}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&tc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse test case %s: %w", entry.Name(), err)
+		}
+		cases = append(cases, tc)
+	}
+
+	return cases, nil
+}
+
+// RunTest renders the template with the variables and includes of a test
+// case, without prompting or writing the project, and compares the rendered
+// files with the expected ones. The builtin variable has fixed values, no Go
+// toolchain is detected, and post-init commands are not run. Files written
+// outside the project are not compared. With update, the expected files are
+// replaced with the rendered ones instead.
+func (s *Scaffolder) RunTest(templateName string, tc TestCase, update bool) *TestResult {
+	result := &TestResult{Case: tc.Name}
+
+	tester := *s
+	tester.builtins = func() template.Builtins { return testBuiltins }
+	tester.goToolchain = &goToolchain{detect: func() (string, error) {
+		return "", errors.New("no Go toolchain in tests")
+	}}
+
+	rendered, err := tester.Render(Options{
+		TemplateRef:     template.TemplateRef{Name: templateName},
+		Variables:       vars.Variables{Global: formatAnswers(tc.Variables)},
+		EnabledIncludes: tc.Includes,
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	actual := make(map[string][]byte)
+	for _, file := range rendered.Files {
+		if file.Target != "" && file.Target != template.TargetProject {
+			continue
+		}
+		content, err := file.Load()
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		actual[file.Path] = content
+	}
+
+	if update {
+		if err := writeExpected(tc.ExpectedDir(), actual); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Updated = true
+		return result
+	}
+
+	expected, err := readExpected(tc.ExpectedDir())
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	for _, p := range sortedFileKeys(expected) {
+		content, ok := actual[p]
+		if !ok {
+			result.Missing = append(result.Missing, p)
+			continue
+		}
+		if bytes.Equal(content, expected[p]) {
+			continue
+		}
+
+		change := TestFileDiff{Path: p}
+		if !template.IsBinary(content) && !template.IsBinary(expected[p]) {
+			change.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(string(expected[p])),
+				B:        difflib.SplitLines(string(content)),
+				FromFile: "expected/" + p,
+				ToFile:   "rendered/" + p,
+				Context:  3,
+			})
+			if err != nil {
+				result.Err = fmt.Errorf("failed to diff %s: %w", p, err)
+				return result
+			}
+		}
+		result.Changed = append(result.Changed, change)
+	}
+	for _, p := range sortedFileKeys(actual) {
+		if _, ok := expected[p]; !ok {
+			result.Unexpected = append(result.Unexpected, p)
+		}
+	}
+
+	return result
+}
+
+// readExpected reads the expected files of a case, keyed by slash-separated
+// path. A missing directory expects no files.
+func readExpected(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected files: %w", err)
+	}
+	return files, nil
+}
+
+// writeExpected replaces the expected files of a case.
+func writeExpected(dir string, files map[string][]byte) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove expected files: %w", err)
+	}
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return fmt.Errorf("failed to write expected files: %w", err)
+		}
+		if err := os.WriteFile(full, content, 0644); err != nil {
+			return fmt.Errorf("failed to write expected files: %w", err)
+		}
+	}
+	return nil
+}
+
+func sortedFileKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
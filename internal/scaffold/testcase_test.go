@@ -0,0 +1,102 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTest(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+files:
+  - src: readme.md.tmpl
+    dest: README.md
+`,
+		"readme.md.tmpl":                 "# {{ .name }} ({{ ._blueprint.year }})\n",
+		"tests/basic/case.yaml":          "description: Default answers\nvariables:\n  name: demo\n",
+		"tests/basic/expected/README.md": "# demo (2000)\n",
+		"tests/notes.txt":                "not a case",
+	}
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	s := NewScaffolder(resolver.NewChainResolver(resolver.Source{
+		Name:       "TEST",
+		Type:       resolver.SourceTypeUser,
+		Filesystem: os.DirFS(dir),
+		Dir:        dir,
+	}))
+
+	cases, err := LoadTestCases(dir)
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	tc := cases[0]
+	assert.Equal(t, "basic", tc.Name)
+	assert.Equal(t, "Default answers", tc.Description)
+
+	t.Run("passes when the rendered files match", func(t *testing.T) {
+		result := s.RunTest("app", tc, false)
+		require.NoError(t, result.Err)
+		assert.True(t, result.Passed())
+	})
+
+	t.Run("reports changed, missing and unexpected files", func(t *testing.T) {
+		expected := tc.ExpectedDir()
+		require.NoError(t, os.WriteFile(filepath.Join(expected, "README.md"), []byte("# other (2000)\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(expected, "LICENSE"), []byte("MIT\n"), 0644))
+
+		result := s.RunTest("app", tc, false)
+		require.NoError(t, result.Err)
+		assert.False(t, result.Passed())
+		assert.Equal(t, []string{"LICENSE"}, result.Missing)
+		assert.Empty(t, result.Unexpected)
+		require.Len(t, result.Changed, 1)
+		assert.Equal(t, "README.md", result.Changed[0].Path)
+		assert.Contains(t, result.Changed[0].Diff, "-# other (2000)")
+		assert.Contains(t, result.Changed[0].Diff, "+# demo (2000)")
+	})
+
+	t.Run("update replaces the expected files", func(t *testing.T) {
+		result := s.RunTest("app", tc, true)
+		require.NoError(t, result.Err)
+		assert.True(t, result.Updated)
+
+		entries, err := os.ReadDir(tc.ExpectedDir())
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "README.md", entries[0].Name())
+
+		assert.True(t, s.RunTest("app", tc, false).Passed())
+	})
+
+	t.Run("reports a rendering error", func(t *testing.T) {
+		result := s.RunTest("app", TestCase{Name: "empty", Dir: t.TempDir()}, false)
+		assert.Error(t, result.Err)
+		assert.False(t, result.Passed())
+	})
+}
+
+func TestLoadTestCases_NoTestsDir(t *testing.T) {
+	cases, err := LoadTestCases(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, cases)
+}
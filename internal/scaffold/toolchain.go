@@ -0,0 +1,114 @@
+package scaffold
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// goToolchain detects the installed Go toolchain, once, for templates that
+// declare the Go versions they support.
+type goToolchain struct {
+	detect func() (string, error)
+
+	once    sync.Once
+	version *template.GoVersion // nil when no toolchain was found
+}
+
+func newGoToolchain() *goToolchain {
+	return &goToolchain{detect: detectGoVersion}
+}
+
+// detectGoVersion returns the version of the go command on the PATH.
+func detectGoVersion() (string, error) {
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// installed returns the installed Go version, or nil when the go command is
+// missing or reports a version that cannot be parsed.
+func (t *goToolchain) installed() *template.GoVersion {
+	t.once.Do(func() {
+		out, err := t.detect()
+		if err != nil {
+			return
+		}
+		if v, err := template.ParseGoVersion(out); err == nil {
+			t.version = &v
+		}
+	})
+	return t.version
+}
+
+// applyGoVersion sets the go_version variable of every node whose template
+// declares supported Go versions, unless it already has a value.
+func (s *Scaffolder) applyGoVersion(tree *template.TemplateNode, contexts template.RenderContexts) {
+	walkNodes(tree, func(node *template.TemplateNode) {
+		if node.Template.Go == nil {
+			return
+		}
+		ctx, ok := contexts[node.ID]
+		if !ok {
+			return
+		}
+		if _, set := ctx.Variables[template.GoVersionVariable]; set {
+			return
+		}
+		if version := node.Template.Go.Select(s.goToolchain.installed()); version != "" {
+			ctx.Set(template.GoVersionVariable, version)
+		}
+	})
+}
+
+// goVersionWarnings reports templates whose supported Go versions do not
+// include the installed toolchain, or that declare Go versions when no
+// toolchain is installed.
+func (s *Scaffolder) goVersionWarnings(tree *template.TemplateNode) []template.Warning {
+	var warnings []template.Warning
+	seen := make(map[string]bool)
+
+	walkNodes(tree, func(node *template.TemplateNode) {
+		if node.Template.Go == nil || seen[node.Template.Name] {
+			return
+		}
+		seen[node.Template.Name] = true
+
+		installed := s.goToolchain.installed()
+		if installed == nil {
+			warnings = append(warnings, template.Warning{
+				Template: node.Template.Name,
+				Message:  "no Go toolchain found; install Go " + goRange(node.Template.Go) + " to build the project",
+			})
+			return
+		}
+		if msg := node.Template.Go.Check(*installed); msg != "" {
+			warnings = append(warnings, template.Warning{Template: node.Template.Name, Message: msg})
+		}
+	})
+
+	return warnings
+}
+
+// goRange describes the supported Go versions of a requirement.
+func goRange(r *template.GoRequirement) string {
+	switch {
+	case r.Min != "" && r.Max != "":
+		return r.Min + " to " + r.Max
+	case r.Min != "":
+		return r.Min + " or newer"
+	default:
+		return r.Max + " or older"
+	}
+}
+
+func walkNodes(node *template.TemplateNode, fn func(*template.TemplateNode)) {
+	fn(node)
+	for _, child := range node.Children {
+		walkNodes(child, fn)
+	}
+}
@@ -0,0 +1,81 @@
+package scaffold
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGoScaffolder(t *testing.T, installed string, detectErr error) *Scaffolder {
+	t.Helper()
+	s := newTestScaffolder(t, map[string]string{
+		"svc/" + template.FileName: `name: svc
+type: project
+version: 1.0.0
+description: A Go service
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: svc
+go:
+  min: "1.22"
+  max: "1.24"
+files:
+  - src: go.mod.tmpl
+    dest: go.mod
+`,
+		"svc/go.mod.tmpl": "module example.com/{{ .name }}\n\ngo {{ .go_version }}\n",
+	})
+	s.goToolchain.detect = func() (string, error) { return installed, detectErr }
+	return s
+}
+
+func renderGoMod(t *testing.T, s *Scaffolder, variables vars.Variables) (string, []template.Warning) {
+	t.Helper()
+	rendered, err := s.Render(Options{TemplateRef: template.TemplateRef{Name: "svc"}, Variables: variables})
+	require.NoError(t, err)
+	require.Len(t, rendered.Files, 1)
+
+	content, err := rendered.Files[0].Load()
+	require.NoError(t, err)
+	return string(content), rendered.Warnings
+}
+
+func TestGoVersionFromInstalledToolchain(t *testing.T) {
+	content, warnings := renderGoMod(t, newGoScaffolder(t, "go1.23.4", nil), vars.Variables{})
+
+	assert.Contains(t, content, "go 1.23.4\n")
+	assert.Empty(t, warnings)
+}
+
+func TestGoVersionOutsideSupportedRange(t *testing.T) {
+	content, warnings := renderGoMod(t, newGoScaffolder(t, "go1.26.0", nil), vars.Variables{})
+
+	assert.Contains(t, content, "go 1.24\n")
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "svc", warnings[0].Template)
+	assert.Contains(t, warnings[0].Message, "newer than 1.24")
+}
+
+func TestGoVersionWithoutToolchain(t *testing.T) {
+	content, warnings := renderGoMod(t, newGoScaffolder(t, "", errors.New("not found")), vars.Variables{})
+
+	assert.Contains(t, content, "go 1.22\n")
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0].Message, "install Go 1.22 to 1.24")
+}
+
+func TestGoVersionGivenByUser(t *testing.T) {
+	content, warnings := renderGoMod(t, newGoScaffolder(t, "go1.23.4", nil), vars.Variables{
+		Global: map[string]string{"go_version": "1.22.1"},
+	})
+
+	assert.Contains(t, content, "go 1.22.1\n")
+	assert.Empty(t, warnings)
+}
@@ -0,0 +1,134 @@
+package scaffold
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// ToolCheck is the outcome of checking a tool a template requires.
+type ToolCheck struct {
+	Tool    template.RequiredTool
+	Path    string // Where the tool was found on the PATH; empty if it was not
+	Version string // Version the tool reported; empty if it was not asked or printed none
+	Problem string // Why the requirement is not met; empty if it is
+}
+
+// OK reports whether the requirement is met.
+func (c ToolCheck) OK() bool {
+	return c.Problem == ""
+}
+
+// toolChecker looks tools up on the PATH and asks them for their version,
+// once per tool.
+type toolChecker struct {
+	lookPath func(name string) (string, error)
+	version  func(path string, args ...string) (string, error)
+
+	mu    sync.Mutex
+	found map[string]foundTool
+}
+
+// foundTool is what the checker found out about a tool.
+type foundTool struct {
+	path       string
+	version    string
+	versionErr error
+	asked      bool // Whether the tool was asked for its version
+}
+
+func newToolChecker() *toolChecker {
+	return &toolChecker{
+		lookPath: exec.LookPath,
+		version:  runToolVersion,
+		found:    make(map[string]foundTool),
+	}
+}
+
+// runToolVersion runs a tool with args and returns what it printed. Some
+// tools, such as java, print their version to stderr.
+func runToolVersion(path string, args ...string) (string, error) {
+	out, err := exec.Command(path, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", path, strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// CheckTools checks that each tool is on the PATH and, for tools with a
+// version constraint, that the version it reports is accepted.
+func CheckTools(tools []template.RequiredTool) []ToolCheck {
+	return newToolChecker().check(tools)
+}
+
+func (c *toolChecker) check(tools []template.RequiredTool) []ToolCheck {
+	checks := make([]ToolCheck, 0, len(tools))
+	for _, tool := range tools {
+		checks = append(checks, c.checkTool(tool))
+	}
+	return checks
+}
+
+func (c *toolChecker) checkTool(tool template.RequiredTool) ToolCheck {
+	check := ToolCheck{Tool: tool}
+
+	found := c.lookup(tool.Name, tool.Constraint != nil)
+	check.Path = found.path
+	check.Version = found.version
+
+	switch {
+	case found.path == "":
+		check.Problem = "not found on the PATH"
+	case tool.Constraint == nil:
+	case found.versionErr != nil:
+		check.Problem = fmt.Sprintf("version could not be determined: %v", found.versionErr)
+	case found.version == "":
+		check.Problem = "version could not be determined"
+	case !tool.Allows(found.version):
+		check.Problem = fmt.Sprintf("version %s does not satisfy %s", found.version, tool.Constraint)
+	}
+	return check
+}
+
+// lookup finds a tool on the PATH and, with withVersion, asks it for its
+// version.
+func (c *toolChecker) lookup(name string, withVersion bool) foundTool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found, ok := c.found[name]
+	if !ok {
+		if path, err := c.lookPath(name); err == nil {
+			found.path = path
+		}
+	}
+	if withVersion && !found.asked && found.path != "" {
+		found.asked = true
+		out, err := c.version(found.path, template.ToolVersionArgs(name)...)
+		if err != nil {
+			found.versionErr = err
+		} else {
+			found.version = template.ToolVersion(out)
+		}
+	}
+	c.found[name] = found
+	return found
+}
+
+// checkTools returns a MissingToolsError for the tools required by the tree
+// that are missing or have a version that is not accepted.
+func (s *Scaffolder) checkTools(tree *template.TemplateNode) error {
+	var failed []ToolCheck
+	for _, check := range s.tools.check(tree.AllTools()) {
+		if !check.OK() {
+			failed = append(failed, check)
+		}
+	}
+	if len(failed) > 0 {
+		return &MissingToolsError{Checks: failed}
+	}
+	return nil
+}
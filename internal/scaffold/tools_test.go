@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTools returns a tool checker that finds the tools of installed, keyed
+// by name, which report the given version output.
+func fakeTools(installed map[string]string) *toolChecker {
+	c := newToolChecker()
+	c.lookPath = func(name string) (string, error) {
+		if _, ok := installed[name]; !ok {
+			return "", errors.New("executable file not found in $PATH")
+		}
+		return "/usr/bin/" + name, nil
+	}
+	c.version = func(path string, args ...string) (string, error) {
+		return installed[filepath.Base(path)], nil
+	}
+	return c
+}
+
+func requiredTools(t *testing.T, specs ...string) []template.RequiredTool {
+	t.Helper()
+	var tools []template.RequiredTool
+	for _, spec := range specs {
+		req, err := template.ParseToolRequirement(spec)
+		require.NoError(t, err)
+		tools = append(tools, template.RequiredTool{ToolRequirement: req, Template: "app"})
+	}
+	return tools
+}
+
+func TestToolCheckerCheck(t *testing.T) {
+	c := fakeTools(map[string]string{
+		"go":     "go version go1.21.5 linux/amd64",
+		"node":   "v20.11.0",
+		"docker": "",
+		"make":   "usage: make",
+	})
+
+	checks := c.check(requiredTools(t, "go >= 1.22", "node >= 20", "docker", "make >= 4", "kubectl"))
+	require.Len(t, checks, 5)
+
+	assert.Equal(t, "version 1.21.5 does not satisfy >=1.22", checks[0].Problem)
+	assert.True(t, checks[1].OK())
+	assert.Equal(t, "20.11.0", checks[1].Version)
+	assert.Equal(t, "/usr/bin/node", checks[1].Path)
+	assert.True(t, checks[2].OK(), "tools without a constraint are not asked for their version")
+	assert.Equal(t, "version could not be determined", checks[3].Problem)
+	assert.Equal(t, "not found on the PATH", checks[4].Problem)
+}
+
+func TestScaffoldChecksTools(t *testing.T) {
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+requires:
+  tools: ["go >= 1.22", docker]
+files:
+  - src: README.md
+    dest: README.md
+`,
+		"app/README.md": "app\n",
+	})
+	s.tools = fakeTools(map[string]string{"go": "go version go1.21.5 linux/amd64"})
+	out := filepath.Join(t.TempDir(), "app")
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}, OutputDir: out}
+
+	_, err := s.Scaffold(opts)
+	var missing *MissingToolsError
+	require.ErrorAs(t, err, &missing)
+	require.Len(t, missing.Checks, 2)
+	assert.Equal(t, "go", missing.Checks[0].Tool.Name)
+	assert.Equal(t, "app", missing.Checks[0].Tool.Template)
+	assert.Equal(t, "docker", missing.Checks[1].Tool.Name)
+	assert.NoDirExists(t, out)
+
+	opts.SkipToolChecks = true
+	_, err = s.Scaffold(opts)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(out, "README.md"))
+}
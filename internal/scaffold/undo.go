@@ -0,0 +1,346 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// JournalDir is the directory inside manifest.Dir that holds the journals
+	// of earlier runs, one directory per run.
+	JournalDir = "journal"
+
+	// journalFileName is the name of the journal inside the directory of a run.
+	journalFileName = "journal.yaml"
+
+	// journalIDFormat names the directory of a run so that the directories
+	// sort by the time of the run.
+	journalIDFormat = "20060102T150405.000000000Z"
+
+	// journalKeep is the number of run journals kept in a project.
+	journalKeep = 10
+)
+
+// savedJournal is the journal of a completed run, as saved in the project.
+type savedJournal struct {
+	SchemaVersion int          `yaml:"schema_version"`
+	Template      string       `yaml:"template"`
+	CreatedAt     time.Time    `yaml:"created_at"`
+	Entries       []savedEntry `yaml:"entries"`
+	PostInitEnv   []string     `yaml:"post_init_env,omitempty"` // Names of the environment variables post-init commands used, never their values
+}
+
+// savedEntry is a path the run changed.
+type savedEntry struct {
+	Path   string `yaml:"path"`             // Project-relative and slash-separated; absolute outside the project
+	Dir    bool   `yaml:"dir,omitempty"`    // Directory created by the run
+	Backup string `yaml:"backup,omitempty"` // Original content, relative to the run directory; empty for a file the run created
+	Mode   string `yaml:"mode,omitempty"`   // Original permissions, in octal
+	Hash   string `yaml:"sha256,omitempty"` // Content after the run; empty when the run removed the file
+}
+
+// Save writes the journal of a completed run to the project rooted at root,
+// so that the run can be undone later. The original content of every file the
+// run overwrote or removed is kept with it. Only the most recent journals are
+// kept.
+func (j *Journal) Save(root, templateName string) error {
+	if j == nil || len(j.entries) == 0 {
+		return nil
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+
+	now := time.Now().UTC()
+	dir := filepath.Join(absRoot, manifest.Dir, JournalDir, now.Format(journalIDFormat))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	saved := savedJournal{SchemaVersion: 1, Template: templateName, CreatedAt: now, PostInitEnv: j.postInitEnv}
+	for i, entry := range j.entries {
+		e, err := saveEntry(entry, absRoot, dir, i)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return err
+		}
+		saved.Entries = append(saved.Entries, e)
+	}
+
+	data, err := yaml.Marshal(saved)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, journalFileName), data, 0644); err != nil {
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return pruneJournals(absRoot)
+}
+
+// saveEntry converts a journal entry, writing the original content of a file
+// to the run directory.
+func saveEntry(entry journalEntry, absRoot, dir string, index int) (savedEntry, error) {
+	abs, err := filepath.Abs(entry.path)
+	if err != nil {
+		return savedEntry{}, fmt.Errorf("failed to resolve %s: %w", entry.path, err)
+	}
+
+	e := savedEntry{Path: abs, Dir: entry.dir}
+	if rel, err := filepath.Rel(absRoot, abs); err == nil && filepath.IsLocal(rel) {
+		e.Path = filepath.ToSlash(rel)
+	}
+	if entry.dir {
+		return e, nil
+	}
+
+	if entry.existed {
+		e.Backup = path.Join("backup", strconv.Itoa(index))
+		e.Mode = fmt.Sprintf("%#o", entry.perm)
+
+		backup := filepath.Join(dir, filepath.FromSlash(e.Backup))
+		if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+			return savedEntry{}, fmt.Errorf("failed to back up %s: %w", e.Path, err)
+		}
+		if err := os.WriteFile(backup, entry.original, 0600); err != nil {
+			return savedEntry{}, fmt.Errorf("failed to back up %s: %w", e.Path, err)
+		}
+	}
+
+	e.Hash, err = currentHash(abs)
+	if err != nil {
+		return savedEntry{}, err
+	}
+	return e, nil
+}
+
+// pruneJournals removes the oldest run journals beyond journalKeep.
+func pruneJournals(absRoot string) error {
+	runs, err := journalRuns(absRoot)
+	if err != nil {
+		return err
+	}
+	for len(runs) > journalKeep {
+		if err := os.RemoveAll(filepath.Join(absRoot, manifest.Dir, JournalDir, runs[0])); err != nil {
+			return fmt.Errorf("failed to remove old journal: %w", err)
+		}
+		runs = runs[1:]
+	}
+	return nil
+}
+
+// journalRuns returns the names of the run directories in a project, oldest
+// first.
+func journalRuns(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, manifest.Dir, JournalDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journals: %w", err)
+	}
+
+	var runs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			runs = append(runs, entry.Name())
+		}
+	}
+	slices.Sort(runs)
+	return runs, nil
+}
+
+// currentHash returns the hash of the file at path, or an empty string when
+// it does not exist.
+func currentHash(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return manifest.HashContent(content), nil
+}
+
+// UndoResult reports the changes Undo reverted, or would revert in a dry run.
+// Paths are relative to the project root, or absolute outside it.
+type UndoResult struct {
+	Template string
+	RunAt    time.Time
+	Removed  []string // Files the run created
+	Restored []string // Files the run overwrote or removed
+	Modified []string // Files changed since the run, reverted with force
+	Kept     []string // Directories the run created that hold other files
+
+	// PostInitEnv names the environment variables the post-init commands of
+	// the run used. What the commands changed is not reverted.
+	PostInitEnv []string
+}
+
+// Undo reverts the most recent run journaled in the project rooted at root:
+// files the run created are removed, files it overwrote or removed are
+// restored, and directories it created are removed when they are empty
+// afterwards. Files changed since the run are only reverted with force;
+// otherwise nothing is changed and an *UndoConflictError lists them.
+//
+// Only the files Blueprint wrote are journaled, so changes made by post-init
+// commands are not reverted.
+func Undo(root string, force, dryRun bool) (*UndoResult, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+
+	runs, err := journalRuns(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, &NothingToUndoError{Dir: root}
+	}
+	runDir := filepath.Join(absRoot, manifest.Dir, JournalDir, runs[len(runs)-1])
+
+	data, err := os.ReadFile(filepath.Join(runDir, journalFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var saved savedJournal
+	if err := yaml.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+
+	result := &UndoResult{Template: saved.Template, RunAt: saved.CreatedAt, PostInitEnv: saved.PostInitEnv}
+	for _, e := range saved.Entries {
+		if e.Dir {
+			continue
+		}
+		hash, err := currentHash(entryPath(absRoot, e.Path))
+		if err != nil {
+			return nil, err
+		}
+		if hash != e.Hash {
+			result.Modified = append(result.Modified, e.Path)
+		}
+		if e.Backup == "" {
+			result.Removed = append(result.Removed, e.Path)
+		} else {
+			result.Restored = append(result.Restored, e.Path)
+		}
+	}
+	if len(result.Modified) > 0 && !force {
+		return nil, &UndoConflictError{Dir: root, Files: result.Modified}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	lock, err := AcquireLock(absRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(saved.Entries) - 1; i >= 0; i-- {
+		if err := undoEntry(absRoot, runDir, saved.Entries[i]); err != nil {
+			_ = lock.Release()
+			return nil, err
+		}
+	}
+
+	if err := os.RemoveAll(runDir); err != nil {
+		_ = lock.Release()
+		return nil, fmt.Errorf("failed to remove journal: %w", err)
+	}
+	_ = removeEmptyDir(filepath.Dir(runDir))
+
+	// The lock file is removed before the directories so that a project
+	// directory created by the run can be removed too.
+	if err := lock.Release(); err != nil {
+		return nil, err
+	}
+
+	for i := len(saved.Entries) - 1; i >= 0; i-- {
+		e := saved.Entries[i]
+		if !e.Dir {
+			continue
+		}
+		removed := removeEmptyDir(entryPath(absRoot, e.Path))
+		if !removed {
+			result.Kept = append(result.Kept, e.Path)
+		}
+	}
+
+	return result, nil
+}
+
+// undoEntry reverts the change the run made to a file.
+func undoEntry(absRoot, runDir string, e savedEntry) error {
+	if e.Dir {
+		return nil
+	}
+	full := entryPath(absRoot, e.Path)
+
+	if e.Backup == "" {
+		if err := os.Remove(full); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", e.Path, err)
+		}
+		return nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(runDir, filepath.FromSlash(e.Backup)))
+	if err != nil {
+		return fmt.Errorf("failed to read backup of %s: %w", e.Path, err)
+	}
+	mode, err := strconv.ParseUint(e.Mode, 0, 32)
+	if err != nil {
+		return fmt.Errorf("invalid mode %q of %s in journal", e.Mode, e.Path)
+	}
+	perm := fs.FileMode(mode).Perm()
+
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", e.Path, err)
+	}
+	if err := os.WriteFile(full, content, perm); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", e.Path, err)
+	}
+	if err := os.Chmod(full, perm); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", e.Path, err)
+	}
+	return nil
+}
+
+// entryPath returns the full path of a journaled path.
+func entryPath(absRoot, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(absRoot, filepath.FromSlash(p))
+}
+
+// removeEmptyDir removes dir if it is empty and reports whether it no longer
+// exists.
+func removeEmptyDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return true
+	}
+	if err != nil || len(entries) > 0 {
+		return false
+	}
+	return os.Remove(dir) == nil
+}
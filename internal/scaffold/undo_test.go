@@ -0,0 +1,167 @@
+package scaffold
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndo_RemovesCreatedProject(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(staleOptions(out, true))
+	require.NoError(t, err)
+
+	runs, err := journalRuns(out)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	result, err := Undo(out, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "app", result.Template)
+	assert.ElementsMatch(t, []string{"main.txt", "extra/extra.txt", "notes.txt", ".blueprint/manifest.yaml"}, result.Removed)
+	assert.Empty(t, result.Restored)
+	assert.Empty(t, result.Kept)
+	assert.NoDirExists(t, out)
+
+	var nothing *NothingToUndoError
+	_, err = Undo(filepath.Dir(out), false, false)
+	assert.ErrorAs(t, err, &nothing)
+}
+
+func TestUndo_RestoresOverwrittenFiles(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.MkdirAll(out, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "main.txt"), []byte("mine\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "keep.txt"), []byte("keep\n"), 0644))
+
+	opts := staleOptions(out, false)
+	opts.Overwrite = true
+	_, err := s.Scaffold(opts)
+	require.NoError(t, err)
+
+	result, err := Undo(out, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.txt"}, result.Restored)
+	assert.FileExists(t, filepath.Join(out, ".blueprint", "manifest.yaml"))
+
+	_, err = Undo(out, false, false)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(out, "main.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine\n", string(content))
+	info, err := os.Stat(filepath.Join(out, "main.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.FileExists(t, filepath.Join(out, "keep.txt"))
+	assert.NoDirExists(t, filepath.Join(out, ".blueprint"))
+}
+
+func TestUndo_ChangedFiles(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	_, err := s.Scaffold(staleOptions(out, false))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(out, "main.txt"), []byte("edited\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "new.txt"), []byte("new\n"), 0644))
+
+	var conflict *UndoConflictError
+	_, err = Undo(out, false, false)
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []string{"main.txt"}, conflict.Files)
+	assert.FileExists(t, filepath.Join(out, "main.txt"))
+
+	result, err := Undo(out, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.txt"}, result.Modified)
+	assert.Equal(t, []string{"."}, result.Kept)
+	assert.NoFileExists(t, filepath.Join(out, "main.txt"))
+	assert.FileExists(t, filepath.Join(out, "new.txt"))
+}
+
+func TestJournalSave_KeepsRecentRuns(t *testing.T) {
+	s := newStaleScaffolder(t)
+	out := filepath.Join(t.TempDir(), "app")
+
+	opts := staleOptions(out, false)
+	opts.Overwrite = true
+	for range journalKeep + 2 {
+		_, err := s.Scaffold(opts)
+		require.NoError(t, err)
+	}
+
+	runs, err := journalRuns(out)
+	require.NoError(t, err)
+	assert.Len(t, runs, journalKeep)
+}
+
+func TestJournalSave_PostInitEnvNames(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("post-init commands in this test need sh")
+	}
+	t.Setenv("BLUEPRINT_TEST_REGION", "eu-west-9")
+
+	s := newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+  - name: api_token
+    prompt: API token?
+    type: secret
+    env_only: true
+env:
+  - name: BLUEPRINT_TEST_REGION
+  - name: BLUEPRINT_TEST_MIRROR
+    default: mirror.example.com
+  - name: BLUEPRINT_TEST_UNSET
+files:
+  - src: main.txt
+    dest: main.txt
+post_init:
+  - command: printf '%s' "$BLUEPRINT_TEST_REGION" > region.txt
+`,
+		"app/main.txt": "main\n",
+	})
+	s.postInit = NewPostInitRunner(io.Discard, io.Discard)
+
+	out := filepath.Join(t.TempDir(), "app")
+	_, err := s.Scaffold(Options{
+		TemplateRef: template.TemplateRef{Name: "app"},
+		OutputDir:   out,
+		Variables:   vars.Variables{Global: map[string]string{"name": "demo", "api_token": "t0ken"}},
+	})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(out, "region.txt"))
+
+	result, err := Undo(out, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BLUEPRINT_TEST_REGION", "BLUEPRINT_TEST_MIRROR"}, result.PostInitEnv)
+
+	runs, err := journalRuns(out)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	journal, err := os.ReadFile(filepath.Join(out, manifest.Dir, JournalDir, runs[0], journalFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(journal), "eu-west-9")
+	assert.NotContains(t, string(journal), "mirror.example.com")
+	assert.NotContains(t, string(journal), "API_TOKEN")
+	assert.NotContains(t, string(journal), "t0ken")
+}
@@ -2,60 +2,138 @@ package scaffold
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/dhanush0x96c/blueprint/internal/prompt"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 	"github.com/dhanush0x96c/blueprint/internal/vars"
 )
 
+// variablePipeline collects variables node by node while the template tree is
+// composed, so that include conditions can be evaluated against them.
 type variablePipeline struct {
-	tree         *template.TemplateNode
 	engine       *template.Engine
 	promptEngine *prompt.Engine
 	opts         Options
+	builtins     template.Builtins
+	contexts     template.RenderContexts
 }
 
 func newVariablePipeline(
-	tree *template.TemplateNode,
 	engine *template.Engine,
 	promptEngine *prompt.Engine,
 	opts Options,
+	builtins template.Builtins,
 ) *variablePipeline {
+	if opts.Draft != nil {
+		promptEngine = promptEngine.WithDraft(opts.Draft)
+	}
+
 	return &variablePipeline{
-		tree:         tree,
 		engine:       engine,
 		promptEngine: promptEngine,
 		opts:         opts,
+		builtins:     builtins,
+		contexts:     make(template.RenderContexts),
 	}
 }
 
-func (p *variablePipeline) Collect() (template.RenderContexts, error) {
-	contexts := make(template.RenderContexts)
+// CollectNode collects the variables of a single node. It is called by the
+// composer before the node's includes are resolved, so the node has no
+// children yet.
+func (p *variablePipeline) CollectNode(node *template.TemplateNode) (*template.Context, error) {
+	p.opts.emit(Event{Kind: EventTemplateLoaded, Template: node.Template.Name, Node: node.ID})
+
+	vars.InheritFromParent(node, p.contexts)
 
-	for _, collector := range p.collectors() {
-		if err := collector.Collect(contexts); err != nil {
+	for _, collector := range p.collectors(node) {
+		if err := collector.Collect(p.contexts); err != nil {
 			return nil, fmt.Errorf("failed to collect variables: %w", err)
 		}
 	}
 
-	vars.ApplyInheritance(p.tree, contexts)
+	p.opts.emitVariables(node, p.contexts[node.ID])
+	return p.contexts[node.ID], nil
+}
+
+// Finalize applies inheritance across the composed tree and validates the
+// collected contexts.
+func (p *variablePipeline) Finalize(tree *template.TemplateNode) (template.RenderContexts, error) {
+	vars.ApplyInheritance(tree, p.contexts)
 
-	if err := p.engine.ValidateContexts(p.tree, contexts); err != nil {
+	if err := p.engine.ValidateContexts(tree, p.contexts); err != nil {
 		return nil, fmt.Errorf("context validation failed: %w", err)
 	}
 
-	return contexts, nil
+	return p.contexts, nil
 }
 
-func (p *variablePipeline) collectors() []vars.Collector {
+func (p *variablePipeline) collectors(node *template.TemplateNode) []vars.Collector {
 	collectors := []vars.Collector{
-		vars.NewDefaultCollector(p.tree),
-		vars.NewCLICollector(p.tree, p.opts.Variables),
+		vars.NewBuiltinCollector(node, p.builtins),
+		vars.NewDefaultCollector(node),
+		vars.NewConfigCollector(node, p.opts.Defaults),
+		vars.NewCLICollector(node, p.opts.Variables),
 	}
 
 	if p.opts.Interactive {
-		collectors = append(collectors, vars.NewPromptCollector(p.tree, p.promptEngine))
+		collectors = append(collectors, vars.NewPromptCollector(node, p.promptEngine))
 	}
 
 	return collectors
 }
+
+// checkIncludeOverrides checks that every include override targets a template
+// in the composed tree and a variable that template declares, so that a
+// mistyped override fails instead of being silently ignored.
+func checkIncludeOverrides(tree *template.TemplateNode, overrides []vars.IncludeOverride) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	var nodes []*template.TemplateNode
+	var collect func(node *template.TemplateNode)
+	collect = func(node *template.TemplateNode) {
+		nodes = append(nodes, node)
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(tree)
+
+	for _, o := range overrides {
+		var target *template.TemplateNode
+		var names []string
+		for _, node := range nodes {
+			if o.Include == node.Template.Name || o.Include == "#"+node.ID {
+				target = node
+				break
+			}
+			if !slices.Contains(names, node.Template.Name) {
+				names = append(names, node.Template.Name)
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("include variable %s: %q is not in the composed tree (templates: %s)",
+				o, o.Include, strings.Join(names, ", "))
+		}
+
+		if _, deprecated := target.Template.RenamedVariable(o.Variable); deprecated {
+			continue
+		}
+		declared := make([]string, 0, len(target.Template.Variables))
+		for _, v := range target.Template.Variables {
+			declared = append(declared, v.Name)
+		}
+		if target.Template.Go != nil {
+			declared = append(declared, template.GoVersionVariable)
+		}
+		if !slices.Contains(declared, o.Variable) {
+			return fmt.Errorf("include variable %s: template %s does not declare %q (variables: %s)",
+				o, target.Template.Name, o.Variable, strings.Join(declared, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOverrideScaffolder(t *testing.T) *Scaffolder {
+	t.Helper()
+	return newTestScaffolder(t, map[string]string{
+		"app/" + template.FileName: `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: name
+    prompt: Name?
+    type: string
+    role: project_name
+    default: app
+  - name: port
+    prompt: Port?
+    type: int
+    default: 8080
+includes:
+  - name: database
+    enabled_by_default: true
+`,
+		"database/" + template.FileName: `name: database
+type: feature
+version: 1.0.0
+description: A database
+variables:
+  - name: port
+    prompt: Database port?
+    type: int
+    default: 5432
+`,
+	})
+}
+
+func TestComposeIncludeOverrides(t *testing.T) {
+	s := newOverrideScaffolder(t)
+	opts := Options{TemplateRef: template.TemplateRef{Name: "app"}}
+
+	t.Run("sets the variable of the include only", func(t *testing.T) {
+		opts := opts
+		opts.IncludeOverrides = []vars.IncludeOverride{{Include: "database", Variable: "port", Value: "5433"}}
+
+		tree, contexts, err := s.Compose(opts)
+		require.NoError(t, err)
+		require.Len(t, tree.Children, 1)
+
+		port, _ := contexts[tree.ID].Get("port")
+		assert.Equal(t, 8080, port)
+		port, _ = contexts[tree.Children[0].ID].Get("port")
+		assert.Equal(t, 5433, port)
+	})
+
+	t.Run("targets a node by ID", func(t *testing.T) {
+		opts := opts
+		opts.IncludeOverrides = []vars.IncludeOverride{{Include: "#0.0", Variable: "port", Value: "5433"}}
+
+		tree, contexts, err := s.Compose(opts)
+		require.NoError(t, err)
+
+		port, _ := contexts[tree.Children[0].ID].Get("port")
+		assert.Equal(t, 5433, port)
+	})
+
+	t.Run("fails for an include not in the tree", func(t *testing.T) {
+		opts := opts
+		opts.EnabledIncludes = map[string]bool{"database": false}
+		opts.IncludeOverrides = []vars.IncludeOverride{{Include: "database", Variable: "port", Value: "5433"}}
+
+		_, _, err := s.Compose(opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"database" is not in the composed tree`)
+	})
+
+	t.Run("fails for an undeclared variable", func(t *testing.T) {
+		opts := opts
+		opts.IncludeOverrides = []vars.IncludeOverride{{Include: "database", Variable: "host", Value: "db"}}
+
+		_, _, err := s.Compose(opts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `template database does not declare "host" (variables: port)`)
+	})
+}
@@ -0,0 +1,119 @@
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+)
+
+// unusedVariableWarnings reports CLI variables that no template in the tree declares,
+// as well as name and node scopes that match no template in the tree. Variables
+// given under a deprecated name are reported with their current name.
+func unusedVariableWarnings(tree *template.TemplateNode, variables vars.Variables) []template.Warning {
+	// Each map holds the current name of every declared or deprecated variable.
+	declared := make(map[string]string)
+	names := make(map[string]map[string]string)
+	nodes := make(map[string]map[string]string)
+
+	var collect func(node *template.TemplateNode)
+	collect = func(node *template.TemplateNode) {
+		if names[node.Template.Name] == nil {
+			names[node.Template.Name] = make(map[string]string)
+		}
+		nodes[node.ID] = make(map[string]string)
+		for _, v := range node.Template.Variables {
+			declared[v.Name] = v.Name
+			names[node.Template.Name][v.Name] = v.Name
+			nodes[node.ID][v.Name] = v.Name
+		}
+		if node.Template.Go != nil {
+			declared[template.GoVersionVariable] = template.GoVersionVariable
+			names[node.Template.Name][template.GoVersionVariable] = template.GoVersionVariable
+			nodes[node.ID][template.GoVersionVariable] = template.GoVersionVariable
+		}
+		for _, d := range node.Template.Deprecated {
+			if _, ok := declared[d.Name]; !ok {
+				declared[d.Name] = d.RenamedTo
+			}
+			names[node.Template.Name][d.Name] = d.RenamedTo
+			nodes[node.ID][d.Name] = d.RenamedTo
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(tree)
+
+	var warnings []template.Warning
+
+	for _, key := range sortedKeys(variables.Global) {
+		name, ok := declared[key]
+		switch {
+		case !ok:
+			warnings = append(warnings, template.Warning{
+				Message: fmt.Sprintf("variable %q is not used by any template", key),
+			})
+		case name != key:
+			warnings = append(warnings, deprecatedWarning(key, name))
+		}
+	}
+
+	warnings = append(warnings, unusedScopedWarnings(variables.NameSpecific, names, "")...)
+	warnings = append(warnings, unusedScopedWarnings(variables.NodeSpecific, nodes, "#")...)
+
+	return warnings
+}
+
+func unusedScopedWarnings(
+	scoped map[string]map[string]string,
+	known map[string]map[string]string,
+	prefix string,
+) []template.Warning {
+	scopes := make([]string, 0, len(scoped))
+	for scope := range scoped {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	var warnings []template.Warning
+	for _, scope := range scopes {
+		declared, ok := known[scope]
+		if !ok {
+			warnings = append(warnings, template.Warning{
+				Message: fmt.Sprintf("variable scope %q does not match any template", prefix+scope),
+			})
+			continue
+		}
+
+		for _, key := range sortedKeys(scoped[scope]) {
+			name, ok := declared[key]
+			switch {
+			case !ok:
+				warnings = append(warnings, template.Warning{
+					Message: fmt.Sprintf("variable %q is not used by %s", prefix+scope+":"+key, prefix+scope),
+				})
+			case name != key:
+				warnings = append(warnings, deprecatedWarning(prefix+scope+":"+key, prefix+scope+":"+name))
+			}
+		}
+	}
+
+	return warnings
+}
+
+func deprecatedWarning(key, renamed string) template.Warning {
+	return template.Warning{
+		Message: fmt.Sprintf("variable %q is deprecated, use %q instead", key, renamed),
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,7 @@
 package scaffold
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,12 +13,17 @@ import (
 type Writer struct {
 	defaultPerm os.FileMode
 	dirPerm     os.FileMode
+	journal     *Journal
+	backups     *backups
+	onRender    func(file template.RenderedFile)
+	onWrite     func(file template.RenderedFile, content []byte)
 }
 
 // WriteResult contains the files written and skipped during a write operation.
 type WriteResult struct {
 	Written []string
 	Skipped []string
+	Backups []Backup
 }
 
 // NewWriter creates a new file writer with default permissions
@@ -28,12 +34,46 @@ func NewWriter() *Writer {
 	}
 }
 
+// WithJournal returns a copy of the writer that records every change in j
+// before making it.
+func (w *Writer) WithJournal(j *Journal) *Writer {
+	journaled := *w
+	journaled.journal = j
+	return &journaled
+}
+
+// WithBackups returns a copy of the writer that backs up every existing file
+// WriteFiles overwrites with b.
+func (w *Writer) WithBackups(b *backups) *Writer {
+	backedUp := *w
+	backedUp.backups = b
+	return &backedUp
+}
+
+// OnWrite returns a copy of the writer that calls fn with the content of
+// every file WriteFiles writes.
+func (w *Writer) OnWrite(fn func(file template.RenderedFile, content []byte)) *Writer {
+	hooked := *w
+	hooked.onWrite = fn
+	return &hooked
+}
+
+// OnRender returns a copy of the writer that calls fn once the content of a
+// file WriteFiles writes was rendered.
+func (w *Writer) OnRender(fn func(file template.RenderedFile)) *Writer {
+	hooked := *w
+	hooked.onRender = fn
+	return &hooked
+}
+
 // WriteFile writes content to a file, creating parent directories if needed
 func (w *Writer) WriteFile(path string, content []byte) error {
 	return w.WriteFileWithPerm(path, content, w.defaultPerm)
 }
 
 // WriteFiles writes multiple rendered files into the given output directory.
+// The content of planned files is rendered one file at a time while writing,
+// and not for files that are skipped.
 func (w *Writer) WriteFiles(outputDir string, files []template.RenderedFile, overwrite bool) (*WriteResult, error) {
 	result := &WriteResult{
 		Written: make([]string, 0, len(files)),
@@ -41,16 +81,49 @@ func (w *Writer) WriteFiles(outputDir string, files []template.RenderedFile, ove
 	}
 
 	for _, file := range files {
-		fullPath := filepath.Join(outputDir, file.Path)
+		fullPath := file.Path
+		if !filepath.IsAbs(fullPath) {
+			fullPath = filepath.Join(outputDir, file.Path)
+		}
 
-		if _, err := os.Stat(fullPath); err == nil && !overwrite {
+		_, statErr := os.Stat(fullPath)
+		exists := statErr == nil
+		if exists && !overwrite {
 			result.Skipped = append(result.Skipped, file.Path)
 			continue
 		}
 
-		if err := w.WriteFile(fullPath, file.Content); err != nil {
+		content, err := file.Load()
+		if err != nil {
+			return nil, err
+		}
+		if w.onRender != nil {
+			w.onRender(file)
+		}
+
+		if exists && w.backups != nil {
+			backup, err := w.backups.backup(w, fullPath, content)
+			if err != nil {
+				return nil, err
+			}
+			if backup != "" {
+				result.Backups = append(result.Backups, Backup{Path: file.Path, Backup: backup})
+			}
+		}
+
+		// A mode the template sets applies to overwritten files too, so that
+		// a script becomes executable; other files keep their permissions.
+		if file.Mode != 0 {
+			err = w.writeFile(fullPath, content, file.Mode)
+		} else {
+			err = w.WriteFileWithPerm(fullPath, content, w.defaultPerm)
+		}
+		if err != nil {
 			return nil, fmt.Errorf("failed to write file %s: %w", file.Path, err)
 		}
+		if w.onWrite != nil {
+			w.onWrite(file, content)
+		}
 
 		result.Written = append(result.Written, file.Path)
 	}
@@ -58,14 +131,48 @@ func (w *Writer) WriteFiles(outputDir string, files []template.RenderedFile, ove
 	return result, nil
 }
 
-// WriteFileWithPerm writes content to a file with specific permissions
+// WriteFileWithPerm writes content to a file with specific permissions.
+// Overwritten files keep their permissions.
 func (w *Writer) WriteFileWithPerm(path string, content []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	return w.writeFile(path, content, perm)
+}
+
+// writeFile writes content to a file and sets its permissions to perm.
+// The content is staged in a temporary file next to path and renamed into
+// place, so a failed write never leaves a truncated file behind.
+func (w *Writer) writeFile(path string, content []byte, perm os.FileMode) error {
 	dir := filepath.Dir(path)
 	if err := w.EnsureDir(dir); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	if err := os.WriteFile(path, content, perm); err != nil {
+	if err := w.journal.RecordFile(path); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	_, writeErr := tmp.Write(content)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -78,6 +185,10 @@ func (w *Writer) EnsureDir(path string) error {
 		return nil
 	}
 
+	if err := w.journal.RecordDirs(path); err != nil {
+		return err
+	}
+
 	if err := os.MkdirAll(path, w.dirPerm); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
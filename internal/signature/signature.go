@@ -0,0 +1,172 @@
+// Package signature verifies template archives signed with minisign.
+//
+// Publishers sign an archive with minisign -Sm <archive>, which writes the
+// signature to <archive>.minisig, and hand out their public key. Blueprint
+// verifies downloaded archives against the public keys it is configured to
+// trust.
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Extension is appended to the name of an archive to get the name of its
+// signature.
+const Extension = ".minisig"
+
+const (
+	algorithmLegacy    = "Ed" // Signs the message itself
+	algorithmPrehashed = "ED" // Signs the BLAKE2b-512 hash of the message
+)
+
+var (
+	// ErrUntrustedKey is returned when a signature was made with a key that is
+	// not trusted.
+	ErrUntrustedKey = errors.New("signed with a key that is not trusted")
+
+	// ErrInvalidSignature is returned when a signature does not match the
+	// signed content.
+	ErrInvalidSignature = errors.New("signature does not match the content")
+)
+
+// PublicKey is a minisign public key.
+type PublicKey struct {
+	ID  [8]byte
+	Key ed25519.PublicKey
+}
+
+// String returns the ID of the key in the hex form minisign prints.
+func (k *PublicKey) String() string {
+	return keyID(k.ID)
+}
+
+// ParsePublicKey parses a minisign public key: the base64 line of a
+// minisign.pub file, or the whole file.
+func ParsePublicKey(s string) (*PublicKey, error) {
+	line := lastLine(s)
+	data, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(data) != 2+8+ed25519.PublicKeySize || string(data[:2]) != algorithmLegacy {
+		return nil, fmt.Errorf("invalid public key %q: expected a minisign public key", line)
+	}
+
+	key := &PublicKey{Key: ed25519.PublicKey(data[10:])}
+	copy(key.ID[:], data[2:10])
+	return key, nil
+}
+
+// Signature is a parsed minisign signature.
+type Signature struct {
+	Algorithm      string
+	KeyID          [8]byte
+	Signature      []byte
+	TrustedComment string
+	GlobalSig      []byte // Signs Signature and TrustedComment
+}
+
+// ParseSignature parses the content of a .minisig file.
+func ParseSignature(data []byte) (*Signature, error) {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n")), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return nil, errors.New("invalid signature: expected a minisign signature")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return nil, errors.New("invalid signature: expected a minisign signature")
+	}
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return nil, errors.New("invalid signature: malformed trusted comment signature")
+	}
+
+	s := &Signature{
+		Algorithm:      string(sig[:2]),
+		Signature:      sig[10:],
+		TrustedComment: strings.TrimPrefix(lines[2], "trusted comment: "),
+		GlobalSig:      global,
+	}
+	copy(s.KeyID[:], sig[2:10])
+	if s.Algorithm != algorithmLegacy && s.Algorithm != algorithmPrehashed {
+		return nil, fmt.Errorf("invalid signature: unsupported algorithm %q", s.Algorithm)
+	}
+	return s, nil
+}
+
+// Verifier verifies signatures against a set of trusted public keys.
+type Verifier struct {
+	Keys []*PublicKey
+}
+
+// NewVerifier returns a verifier that trusts the given public keys.
+func NewVerifier(keys []string) (*Verifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no trusted keys to verify signatures with")
+	}
+
+	v := &Verifier{}
+	for _, s := range keys {
+		key, err := ParsePublicKey(s)
+		if err != nil {
+			return nil, err
+		}
+		v.Keys = append(v.Keys, key)
+	}
+	return v, nil
+}
+
+// Verify checks that sig is a signature of message made with a trusted key
+// and returns that key.
+func (v *Verifier) Verify(message, sig []byte) (*PublicKey, error) {
+	s, err := ParseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	var key *PublicKey
+	for _, k := range v.Keys {
+		if k.ID == s.KeyID {
+			key = k
+			break
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w (key %s)", ErrUntrustedKey, keyID(s.KeyID))
+	}
+
+	signed := message
+	if s.Algorithm == algorithmPrehashed {
+		sum := blake2b.Sum512(message)
+		signed = sum[:]
+	}
+	if !ed25519.Verify(key.Key, signed, s.Signature) {
+		return nil, ErrInvalidSignature
+	}
+	if !ed25519.Verify(key.Key, append(bytes.Clone(s.Signature), s.TrustedComment...), s.GlobalSig) {
+		return nil, ErrInvalidSignature
+	}
+	return key, nil
+}
+
+// keyID formats a key ID the way minisign prints it: the bytes in reverse
+// order, in uppercase hex.
+func keyID(id [8]byte) string {
+	reversed := make([]byte, len(id))
+	for i, b := range id {
+		reversed[len(id)-1-i] = b
+	}
+	return strings.ToUpper(hex.EncodeToString(reversed))
+}
+
+// lastLine returns the last non-empty line of s, trimmed.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
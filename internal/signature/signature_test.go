@@ -0,0 +1,98 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+// testKey is a minisign key pair.
+type testKey struct {
+	id      [8]byte
+	private ed25519.PrivateKey
+	public  string // Base64 line of minisign.pub
+}
+
+func newTestKey(t *testing.T, id byte) testKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	k := testKey{private: private}
+	k.id[0] = id
+	k.public = base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), k.id[:]...), public...))
+	return k
+}
+
+// sign returns a .minisig file for message, prehashed as minisign signs by
+// default or as the legacy format.
+func (k testKey) sign(message []byte, prehashed bool) []byte {
+	algorithm, signed := "Ed", message
+	if prehashed {
+		sum := blake2b.Sum512(message)
+		algorithm, signed = "ED", sum[:]
+	}
+	sig := ed25519.Sign(k.private, signed)
+	comment := "timestamp:1700000000\tfile:go-api.tar.gz"
+	global := ed25519.Sign(k.private, append(append([]byte(nil), sig...), comment...))
+
+	return []byte("untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), k.id[:]...), sig...)) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n")
+}
+
+func TestVerify(t *testing.T) {
+	key := newTestKey(t, 1)
+	v, err := NewVerifier([]string{newTestKey(t, 2).public, key.public})
+	require.NoError(t, err)
+
+	message := []byte("archive content")
+	for _, prehashed := range []bool{true, false} {
+		signedBy, err := v.Verify(message, key.sign(message, prehashed))
+		require.NoError(t, err)
+		assert.Equal(t, key.id, signedBy.ID)
+	}
+
+	_, err = v.Verify([]byte("tampered content"), key.sign(message, true))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = v.Verify(message, newTestKey(t, 3).sign(message, true))
+	assert.ErrorIs(t, err, ErrUntrustedKey)
+	assert.ErrorContains(t, err, "0000000000000003")
+}
+
+func TestVerifyRejectsChangedTrustedComment(t *testing.T) {
+	key := newTestKey(t, 1)
+	v, err := NewVerifier([]string{key.public})
+	require.NoError(t, err)
+
+	message := []byte("archive content")
+	lines := strings.Split(string(key.sign(message, true)), "\n")
+	lines[2] = "trusted comment: timestamp:1800000000\tfile:other.tar.gz"
+
+	_, err = v.Verify(message, []byte(strings.Join(lines, "\n")))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newTestKey(t, 7)
+
+	parsed, err := ParsePublicKey("untrusted comment: minisign public key 0700000000000000\n" + key.public + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, key.id, parsed.ID)
+	assert.Equal(t, "0000000000000007", parsed.String())
+
+	for _, invalid := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("Ed too short"))} {
+		_, err := ParsePublicKey(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	_, err = NewVerifier(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+// Package telemetry records opt-in usage of templates. Only the template
+// name and version, whether the run succeeded, and how long it took are
+// recorded; variable values, paths, and file contents never are.
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// sendTimeout bounds how long a run waits for the endpoint, so that a slow or
+// unreachable endpoint does not hold up the command.
+const sendTimeout = 2 * time.Second
+
+// Event is a single scaffold run.
+type Event struct {
+	Template   string    `json:"template"`
+	Version    string    `json:"version,omitempty"` // Empty when the template could not be loaded
+	Success    bool      `json:"success"`
+	DurationMS int64     `json:"duration_ms"`
+	Time       time.Time `json:"time"`
+}
+
+// NewEvent returns the event of a run of a template that started at start.
+func NewEvent(templateName, templateVersion string, start time.Time, success bool) Event {
+	return Event{
+		Template:   templateName,
+		Version:    templateVersion,
+		Success:    success,
+		DurationMS: time.Since(start).Milliseconds(),
+		Time:       time.Now().UTC(),
+	}
+}
+
+// TemplateStats aggregates the runs of a version of a template.
+type TemplateStats struct {
+	Template        string    `json:"template"`
+	Version         string    `json:"version,omitempty"`
+	Runs            int       `json:"runs"`
+	Failures        int       `json:"failures"`
+	TotalDurationMS int64     `json:"total_duration_ms"`
+	LastRun         time.Time `json:"last_run"`
+}
+
+// AverageDuration returns the mean duration of the runs.
+func (s TemplateStats) AverageDuration() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return time.Duration(s.TotalDurationMS/int64(s.Runs)) * time.Millisecond
+}
+
+// Stats holds the runs recorded on this machine, aggregated per template
+// version.
+type Stats struct {
+	Templates []TemplateStats `json:"templates"`
+}
+
+// DefaultStatsPath returns the file the local aggregate is kept in, inside
+// the user cache directory.
+func DefaultStatsPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "blueprint", "telemetry.json"), nil
+}
+
+// LoadStats reads the aggregate at path. A missing file holds no runs.
+func LoadStats(path string) (*Stats, error) {
+	stats := &Stats{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return stats, nil
+		}
+		return nil, fmt.Errorf("failed to read telemetry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("failed to parse telemetry: %w", err)
+	}
+	return stats, nil
+}
+
+// Add counts an event in the aggregate of its template version.
+func (s *Stats) Add(e Event) {
+	i := sort.Search(len(s.Templates), func(i int) bool {
+		t := s.Templates[i]
+		return t.Template > e.Template || (t.Template == e.Template && t.Version >= e.Version)
+	})
+	if i == len(s.Templates) || s.Templates[i].Template != e.Template || s.Templates[i].Version != e.Version {
+		s.Templates = append(s.Templates, TemplateStats{})
+		copy(s.Templates[i+1:], s.Templates[i:])
+		s.Templates[i] = TemplateStats{Template: e.Template, Version: e.Version}
+	}
+
+	t := &s.Templates[i]
+	t.Runs++
+	if !e.Success {
+		t.Failures++
+	}
+	t.TotalDurationMS += e.DurationMS
+	t.LastRun = e.Time
+}
+
+// Record adds an event to the aggregate at path.
+func Record(path string, e Event) error {
+	stats, err := LoadStats(path)
+	if err != nil {
+		return err
+	}
+	stats.Add(e)
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write telemetry: %w", err)
+	}
+	return nil
+}
+
+// Send posts an event as JSON to endpoint.
+func Send(endpoint string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send telemetry: %s", resp.Status)
+	}
+	return nil
+}
+
+// DoNotTrack reports whether the DO_NOT_TRACK environment variable asks for
+// telemetry to be disabled, overriding the configuration.
+func DoNotTrack() bool {
+	switch os.Getenv("DO_NOT_TRACK") {
+	case "", "0", "false":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord_AggregatesPerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.json")
+	now := time.Now().UTC()
+
+	require.NoError(t, Record(path, Event{Template: "go-cli", Version: "1.0.0", Success: true, DurationMS: 100, Time: now}))
+	require.NoError(t, Record(path, Event{Template: "go-cli", Version: "1.0.0", Success: false, DurationMS: 300, Time: now}))
+	require.NoError(t, Record(path, Event{Template: "go-api", Version: "2.0.0", Success: true, DurationMS: 50, Time: now}))
+
+	stats, err := LoadStats(path)
+	require.NoError(t, err)
+	require.Len(t, stats.Templates, 2)
+
+	assert.Equal(t, "go-api", stats.Templates[0].Template)
+	cli := stats.Templates[1]
+	assert.Equal(t, 2, cli.Runs)
+	assert.Equal(t, 1, cli.Failures)
+	assert.Equal(t, 200*time.Millisecond, cli.AverageDuration())
+}
+
+func TestLoadStats_Missing(t *testing.T) {
+	stats, err := LoadStats(filepath.Join(t.TempDir(), "telemetry.json"))
+	require.NoError(t, err)
+	assert.Empty(t, stats.Templates)
+}
+
+func TestSend(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	event := NewEvent("go-cli", "1.0.0", time.Now(), true)
+	require.NoError(t, Send(server.URL, event))
+
+	assert.Equal(t, "go-cli", received["template"])
+	assert.Equal(t, "1.0.0", received["version"])
+	assert.Equal(t, true, received["success"])
+	assert.Len(t, received, 5, "only the template, its version, the outcome, and the timing are sent")
+}
+
+func TestSend_RejectedEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	require.Error(t, Send(server.URL, NewEvent("go-cli", "", time.Now(), false)))
+}
+
+func TestDoNotTrack(t *testing.T) {
+	t.Setenv("DO_NOT_TRACK", "1")
+	assert.True(t, DoNotTrack())
+
+	t.Setenv("DO_NOT_TRACK", "0")
+	assert.False(t, DoNotTrack())
+}
@@ -0,0 +1,319 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"text/template"
+	"text/template/parse"
+)
+
+// FileUsage lists the variables a single template file refers to.
+type FileUsage struct {
+	NodeID    string
+	Template  string
+	Src       string
+	Dest      string
+	Variables []string
+}
+
+// NodeUsage lists the variables referenced anywhere in a single template of the
+// tree: its files, destinations, file conditions, and the condition that
+// enables the include.
+type NodeUsage struct {
+	NodeID    string
+	Template  string
+	Variables []string
+}
+
+// Analysis reports which variables each file and each template of a tree refer to.
+type Analysis struct {
+	Files []FileUsage
+	Nodes []NodeUsage
+}
+
+// ReferencedVariables parses a template string and returns the sorted names of
+// the top-level variables it refers to, e.g. "name" for {{ .name.first }}.
+// Fields accessed inside range and with blocks are reported as well, so the
+// result may include fields of nested values.
+func (r *Renderer) ReferencedVariables(content, name string) ([]string, error) {
+	tmpl, err := r.parse(r.newTemplate(name).Funcs(allLibraryFuncs()), content)
+	if err != nil {
+		return nil, err
+	}
+
+	return referencedVariables(tmpl, false), nil
+}
+
+// referencedVariables returns the sorted top-level variables a parsed template
+// refers to, following the templates and partials it invokes. In scoped mode,
+// fields inside range and with blocks, and in templates invoked with anything
+// but the root context, are not reported.
+func referencedVariables(tmpl *template.Template, scoped bool) []string {
+	c := &fieldCollector{
+		tmpl:    tmpl,
+		seen:    make(map[string]bool),
+		visited: make(map[string]bool),
+		scoped:  scoped,
+	}
+	c.collectTemplate(tmpl.Name(), true)
+	return sortedSet(c.seen)
+}
+
+// AnalyzeTree reports the variables referenced by every file and template in
+// the tree, including the variables used in destination paths and conditions.
+func (r *Renderer) AnalyzeTree(node *TemplateNode) (*Analysis, error) {
+	analysis := &Analysis{}
+	if err := r.analyzeNode(node, "", analysis); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+func (r *Renderer) analyzeNode(node *TemplateNode, when string, analysis *Analysis) error {
+	nodeVars := make(map[string]bool)
+
+	names, err := r.ReferencedVariables(when, "when")
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		nodeVars[n] = true
+	}
+
+	partials, err := loadPartials(node.FS, node.Path)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range node.Template.Files {
+		shared := make(map[string]bool)
+		for _, text := range []string{file.Dest, file.When} {
+			names, err := r.ReferencedVariables(text, "path")
+			if err != nil {
+				return err
+			}
+			for _, n := range names {
+				shared[n] = true
+			}
+		}
+		// The item of a looping file is the element of its list variable.
+		if file.ForEach != "" {
+			shared[file.ForEach] = true
+		}
+
+		srcPath := path.Join(node.Path, file.Src)
+		err := fs.WalkDir(node.FS, srcPath, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !file.selectsUnder(srcPath, p, d.IsDir()) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+				return nil
+			}
+
+			var dest string // Set by front matter
+			vars := make(map[string]bool, len(shared))
+			for n := range shared {
+				vars[n] = true
+			}
+
+			if isTemplateFile(p) {
+				content, err := fs.ReadFile(node.FS, p)
+				if err != nil {
+					return fmt.Errorf("failed to read template file %s: %w", p, err)
+				}
+				if IsBinary(content) {
+					content = nil
+				}
+				fm, body, err := SplitFrontMatter(content)
+				if err != nil {
+					return fmt.Errorf("%s: %w", p, err)
+				}
+				fr := r.withDelimiters(node.Template.DelimitersFor(file)).withPartials(partials)
+				if fm != nil {
+					fr = fr.withDelimiters(fm.Delimiters)
+					for _, text := range []string{fm.Dest, fm.When} {
+						names, err := r.ReferencedVariables(text, "path")
+						if err != nil {
+							return err
+						}
+						for _, n := range names {
+							vars[n] = true
+						}
+					}
+					dest = fm.Dest
+				}
+				names, err := fr.ReferencedVariables(string(body), p)
+				if err != nil {
+					return err
+				}
+				for _, n := range names {
+					vars[n] = true
+				}
+			}
+
+			rel, err := relPath(srcPath, p)
+			if err != nil {
+				return err
+			}
+			if dest == "" {
+				dest = stripTemplateExt(path.Join(file.Dest, rel))
+			}
+
+			if file.ForEach != "" {
+				delete(vars, ForEachItemVariable)
+			}
+			for n := range vars {
+				nodeVars[n] = true
+			}
+
+			analysis.Files = append(analysis.Files, FileUsage{
+				NodeID:    node.ID,
+				Template:  node.Template.Name,
+				Src:       p,
+				Dest:      dest,
+				Variables: sortedSet(vars),
+			})
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to analyze %s: %w", srcPath, err)
+		}
+	}
+
+	analysis.Nodes = append(analysis.Nodes, NodeUsage{
+		NodeID:    node.ID,
+		Template:  node.Template.Name,
+		Variables: sortedSet(nodeVars),
+	})
+
+	for _, child := range node.Children {
+		if err := r.analyzeNode(child, includeCondition(node.Template, child.Template.Name), analysis); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// includeCondition returns the when condition of the named include, if any.
+func includeCondition(tmpl *Template, name string) string {
+	for _, inc := range tmpl.Includes {
+		if inc.Name == name {
+			return inc.When
+		}
+	}
+	return ""
+}
+
+// relPath returns p relative to root for paths inside a walked directory,
+// or "" when p is root itself.
+func relPath(root, p string) (string, error) {
+	if p == root {
+		return "", nil
+	}
+	if len(p) > len(root) && p[:len(root)+1] == root+"/" {
+		return p[len(root)+1:], nil
+	}
+	return "", fmt.Errorf("%s is not inside %s", p, root)
+}
+
+func sortedSet(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fieldCollector records the first identifier of every field reference in a
+// parse tree.
+type fieldCollector struct {
+	tmpl    *template.Template
+	seen    map[string]bool
+	visited map[string]bool // Invoked templates already walked, by name and context
+	scoped  bool            // Only record fields while dot is the root context
+}
+
+// collect walks node; root reports whether dot is the root context there.
+func (c *fieldCollector) collect(node parse.Node, root bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.collect(child, root)
+		}
+	case *parse.ActionNode:
+		c.collect(n.Pipe, root)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			c.collect(cmd, root)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			c.collect(arg, root)
+		}
+	case *parse.FieldNode:
+		if len(n.Ident) > 0 && (root || !c.scoped) {
+			c.seen[n.Ident[0]] = true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			c.seen[n.Ident[1]] = true
+		}
+	case *parse.ChainNode:
+		c.collect(n.Node, root)
+	case *parse.IfNode:
+		c.collectBranch(&n.BranchNode, root, root)
+	case *parse.RangeNode:
+		c.collectBranch(&n.BranchNode, root, false)
+	case *parse.WithNode:
+		c.collectBranch(&n.BranchNode, root, false)
+	case *parse.TemplateNode:
+		c.collect(n.Pipe, root)
+		c.collectTemplate(n.Name, root && isDot(n.Pipe))
+	}
+}
+
+// collectTemplate walks the associated template name once per context.
+func (c *fieldCollector) collectTemplate(name string, root bool) {
+	key := fmt.Sprintf("%s/%t", name, root)
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+
+	if t := c.tmpl.Lookup(name); t != nil && t.Tree != nil {
+		c.collect(t.Tree.Root, root)
+	}
+}
+
+// isDot reports whether a pipeline is just dot.
+func isDot(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	_, ok := pipe.Cmds[0].Args[0].(*parse.DotNode)
+	return ok
+}
+
+// collectBranch walks a branch whose body runs with dot being the root
+// context when bodyRoot is set.
+func (c *fieldCollector) collectBranch(n *parse.BranchNode, root, bodyRoot bool) {
+	c.collect(n.Pipe, root)
+	c.collect(n.List, bodyRoot)
+	c.collect(n.ElseList, root)
+}
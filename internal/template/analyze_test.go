@@ -0,0 +1,103 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferencedVariables(t *testing.T) {
+	r, _ := newTestRenderer(t)
+
+	names, err := r.ReferencedVariables(
+		`{{ .name | toUpper }} {{ if .enabled }}{{ .config.port }}{{ end }} {{ $.owner }}`,
+		"test",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config", "enabled", "name", "owner"}, names)
+}
+
+func TestAnalyzeTree(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main.go.tmpl"), []byte("package {{ .pkg }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "LICENSE"), []byte("{{ .ignored }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db.go.tmpl"), []byte("// {{ .driver }}"), 0644))
+
+	fsys := os.DirFS(dir)
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:     "root",
+			Files:    []File{{Src: "src", Dest: "{{ .name }}"}},
+			Includes: []Include{{Name: "database", When: "{{ .use_db }}"}},
+		},
+		FS:   fsys,
+		Path: ".",
+		Children: []*TemplateNode{{
+			ID: "0.0",
+			Template: &Template{
+				Name:  "database",
+				Files: []File{{Src: "db.go.tmpl", Dest: "db.go.tmpl"}},
+			},
+			FS:   fsys,
+			Path: ".",
+		}},
+	}
+
+	analysis, err := r.AnalyzeTree(node)
+	require.NoError(t, err)
+
+	require.Len(t, analysis.Files, 3)
+	assert.Equal(t, "{{ .name }}/LICENSE", analysis.Files[0].Dest)
+	assert.Equal(t, []string{"name"}, analysis.Files[0].Variables)
+	assert.Equal(t, "{{ .name }}/main.go", analysis.Files[1].Dest)
+	assert.Equal(t, []string{"name", "pkg"}, analysis.Files[1].Variables)
+	assert.Equal(t, "db.go", analysis.Files[2].Dest)
+	assert.Equal(t, []string{"driver"}, analysis.Files[2].Variables)
+
+	require.Len(t, analysis.Nodes, 2)
+	assert.Equal(t, []string{"name", "pkg"}, analysis.Nodes[0].Variables)
+	assert.Equal(t, []string{"driver", "use_db"}, analysis.Nodes[1].Variables)
+}
+
+func TestAnalyzeTree_CustomDelimiters(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml.tmpl"), []byte("name: [[ .name ]]\nrepo: {{ .Values.repo }}"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:       "chart",
+			Delimiters: []string{"[[", "]]"},
+			Files:      []File{{Src: "values.yaml.tmpl", Dest: "values.yaml"}},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	analysis, err := r.AnalyzeTree(node)
+	require.NoError(t, err)
+	require.Len(t, analysis.Files, 1)
+	assert.Equal(t, []string{"name"}, analysis.Files[0].Variables)
+}
+
+func TestReferencedVariables_FollowsInvokedTemplates(t *testing.T) {
+	r, _ := newTestRenderer(t)
+	r = r.withPartials([]partial{
+		{name: "header", source: "partials/header.tmpl", content: "{{ .owner }}"},
+		{name: "footer", source: "partials/footer.tmpl", content: "{{ .unused }}"},
+	})
+
+	names, err := r.ReferencedVariables(
+		`{{ define "title" }}{{ .name }}{{ end }}{{ template "header" . }}{{ template "title" . }}`,
+		"test",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "owner"}, names)
+}
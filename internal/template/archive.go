@@ -0,0 +1,184 @@
+package template
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing/fstest"
+)
+
+// ArchiveExtension is the extension of packed templates.
+const ArchiveExtension = ".bpt"
+
+// PackManifestName is the file at the root of a packed template that
+// describes the template and lists the checksums of its files.
+const PackManifestName = "blueprint-pack.json"
+
+// PackFormat is the version of the packed template format written by this
+// release. Archives of a newer format are rejected.
+const PackFormat = 1
+
+// PackManifest describes a packed template: a gzipped tar archive holding
+// the manifest and the files of the template under a directory named after
+// it.
+type PackManifest struct {
+	Format  int    `json:"format"`
+	Name    string `json:"name"`
+	Type    Type   `json:"type"`
+	Version string `json:"version"`
+
+	// Files maps the path of every file of the archive, other than the
+	// manifest, to the hex SHA-256 sum of its content.
+	Files map[string]string `json:"files"`
+}
+
+// Archive is a packed template opened in memory.
+type Archive struct {
+	Manifest *PackManifest
+	FS       fs.FS // Files of the archive, with the template in Manifest.Name
+}
+
+// Resolved returns the template of the archive.
+func (a *Archive) Resolved() *ResolvedTemplate {
+	return &ResolvedTemplate{Path: a.Manifest.Name, FS: a.FS}
+}
+
+// IsArchive reports whether pth names a packed template.
+func IsArchive(pth string) bool {
+	return strings.HasSuffix(strings.ToLower(pth), ArchiveExtension)
+}
+
+// OpenArchive reads the packed template at pth into memory and verifies the
+// checksums of its files.
+func OpenArchive(pth string) (*Archive, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	archive, err := ReadArchive(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", pth, err)
+	}
+	return archive, nil
+}
+
+// ReadArchive reads a packed template from r into memory and verifies the
+// checksums of its files.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := make(fstest.MapFS)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Directories are implied by the files, and links and special files
+		// are not part of templates.
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		if !fs.ValidPath(name) {
+			return nil, fmt.Errorf("archive entry %q escapes the archive", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = &fstest.MapFile{Data: data, Mode: fs.FileMode(hdr.Mode).Perm()}
+	}
+
+	manifest, err := VerifyPack(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{Manifest: manifest, FS: files}, nil
+}
+
+// VerifyPack reads the pack manifest at the root of fsys and checks that
+// fsys holds exactly the files it lists, with the listed checksums.
+func VerifyPack(fsys fs.FS) (*PackManifest, error) {
+	data, err := fs.ReadFile(fsys, PackManifestName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("not a packed template: %s is missing", PackManifestName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var manifest PackManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", PackManifestName, err)
+	}
+	if manifest.Format < 1 || manifest.Format > PackFormat {
+		return nil, fmt.Errorf("unsupported pack format %d (this release reads format %d)", manifest.Format, PackFormat)
+	}
+	if !IsValidName(manifest.Name) {
+		return nil, fmt.Errorf("invalid %s: invalid template name %q: use lowercase letters, digits, and dashes", PackManifestName, manifest.Name)
+	}
+
+	var problems []string
+	seen := make(map[string]bool, len(manifest.Files))
+	err = fs.WalkDir(fsys, ".", func(pth string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || pth == PackManifestName {
+			return err
+		}
+		seen[pth] = true
+
+		want, ok := manifest.Files[pth]
+		if !ok {
+			problems = append(problems, pth+" is not listed")
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, pth)
+		if err != nil {
+			return err
+		}
+		if got := checksum(content); !strings.EqualFold(got, want) {
+			problems = append(problems, pth+" does not match its checksum")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for pth := range manifest.Files {
+		if !seen[pth] {
+			problems = append(problems, pth+" is missing")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("packed template %s is corrupt: %s", manifest.Name, strings.Join(problems, "; "))
+	}
+	return &manifest, nil
+}
+
+// checksum returns the hex SHA-256 sum of content, as listed in pack
+// manifests.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPackManifest = `{
+  "format": 1,
+  "name": "alpha",
+  "type": "feature",
+  "version": "1.0.0",
+  "files": {
+    "alpha/template.yaml": "%s",
+    "alpha/main.go.tmpl": "%s"
+  }
+}`
+
+func packedFS(manifest string) fstest.MapFS {
+	return fstest.MapFS{
+		PackManifestName:      {Data: []byte(manifest)},
+		"alpha/template.yaml": {Data: []byte("name: alpha\n")},
+		"alpha/main.go.tmpl":  {Data: []byte("package main\n")},
+	}
+}
+
+func TestVerifyPack(t *testing.T) {
+	valid := fmt.Sprintf(testPackManifest, checksum([]byte("name: alpha\n")), checksum([]byte("package main\n")))
+
+	manifest, err := VerifyPack(packedFS(valid))
+	require.NoError(t, err)
+	assert.Equal(t, "alpha", manifest.Name)
+	assert.Equal(t, "1.0.0", manifest.Version)
+
+	fsys := packedFS(valid)
+	fsys["alpha/main.go.tmpl"].Data = []byte("package other\n")
+	_, err = VerifyPack(fsys)
+	assert.ErrorContains(t, err, "alpha/main.go.tmpl does not match its checksum")
+
+	fsys = packedFS(valid)
+	fsys["alpha/extra.txt"] = &fstest.MapFile{Data: []byte("x")}
+	delete(fsys, "alpha/main.go.tmpl")
+	_, err = VerifyPack(fsys)
+	assert.ErrorContains(t, err, "alpha/extra.txt is not listed")
+	assert.ErrorContains(t, err, "alpha/main.go.tmpl is missing")
+}
+
+func TestVerifyPackRejectsUnknownArchives(t *testing.T) {
+	fsys := packedFS("")
+	delete(fsys, PackManifestName)
+	_, err := VerifyPack(fsys)
+	assert.ErrorContains(t, err, "not a packed template")
+
+	_, err = VerifyPack(packedFS(`{"format": 2, "name": "alpha"}`))
+	assert.ErrorContains(t, err, "unsupported pack format 2")
+
+	for _, name := range []string{"", ".", "..", "../alpha", "alpha/beta", "Alpha"} {
+		_, err = VerifyPack(packedFS(fmt.Sprintf(`{"format": 1, "name": %q}`, name)))
+		assert.ErrorContains(t, err, "invalid template name", name)
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	assert.True(t, IsArchive("dist/go-cli-1.0.0.bpt"))
+	assert.True(t, IsArchive("GO-CLI.BPT"))
+	assert.False(t, IsArchive("go-cli.tar.gz"))
+}
@@ -0,0 +1,14 @@
+package template
+
+import "bytes"
+
+// binarySniffLen is how much of a file is inspected to decide whether it is
+// binary, matching the heuristic used by git.
+const binarySniffLen = 8000
+
+// IsBinary reports whether content looks like a binary file, i.e. contains a
+// NUL byte near its start. Binary files are never rendered as templates or
+// otherwise modified.
+func IsBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), binarySniffLen)], 0) >= 0
+}
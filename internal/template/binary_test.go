@@ -0,0 +1,20 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBinary(t *testing.T) {
+	assert.False(t, IsBinary(nil))
+	assert.False(t, IsBinary([]byte("package main\n")))
+	assert.False(t, IsBinary([]byte("héllo wörld")))
+	assert.True(t, IsBinary([]byte("\x89PNG\r\n\x1a\n\x00\x00")))
+
+	late := append(make([]byte, binarySniffLen), 0)
+	for i := range binarySniffLen {
+		late[i] = 'a'
+	}
+	assert.False(t, IsBinary(late))
+}
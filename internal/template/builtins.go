@@ -0,0 +1,42 @@
+package template
+
+import "time"
+
+// BuiltinVariable is the variable Blueprint sets in every context, holding
+// values templates commonly need without prompting for them, such as the
+// current year. Templates may not declare a variable of this name.
+const BuiltinVariable = "_blueprint"
+
+// Builtins are the values of the builtin variable that are the same for every
+// template of a run.
+type Builtins struct {
+	Now          time.Time
+	Version      string // Blueprint version
+	OS           string
+	Arch         string
+	GitUserName  string // user.name from the git configuration, if any
+	GitUserEmail string // user.email from the git configuration, if any
+	Locale       string // Configured locale, if any
+}
+
+// For returns the value of the builtin variable for a template. Every key is
+// always present, so that templates render an empty string rather than
+// <no value> for values that could not be determined.
+func (b Builtins) For(tmpl *Template) map[string]any {
+	return map[string]any{
+		"date":    b.Now.Format(time.DateOnly),
+		"year":    b.Now.Year(),
+		"version": b.Version,
+		"os":      b.OS,
+		"arch":    b.Arch,
+		"locale":  b.Locale,
+		"template": map[string]any{
+			"name":    tmpl.Name,
+			"version": tmpl.Version,
+		},
+		"git": map[string]any{
+			"name":  b.GitUserName,
+			"email": b.GitUserEmail,
+		},
+	}
+}
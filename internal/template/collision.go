@@ -0,0 +1,92 @@
+package template
+
+import (
+	"fmt"
+)
+
+// fileOwner identifies the rendered file currently claiming an output path.
+type fileOwner struct {
+	node  *TemplateNode
+	index int
+}
+
+// ResolveCollisions detects files rendered to the same output path by
+// different nodes of the tree and applies the collision policy of the node
+// that comes later in composition order. dirs maps node IDs to the directory,
+// relative to the output root, that the node's files are written to; nodes
+// missing from dirs write to the root.
+//
+// Collisions under the default policy are collected and returned together as
+// a *CollisionError. Skipped and overridden files are reported as warnings.
+func (r *RenderResult) ResolveCollisions(tree *TemplateNode, dirs map[string]string) error {
+	owners := make(map[string]fileOwner)
+	dropped := make(map[string]map[int]bool)
+	var collisions []Collision
+
+	drop := func(owner fileOwner) {
+		if dropped[owner.node.ID] == nil {
+			dropped[owner.node.ID] = make(map[int]bool)
+		}
+		dropped[owner.node.ID][owner.index] = true
+	}
+
+	var walk func(node *TemplateNode)
+	walk = func(node *TemplateNode) {
+		for i, file := range r.Files[node.ID] {
+			outPath := OutputPath(dirs[node.ID], file.Path)
+			current := fileOwner{node: node, index: i}
+
+			existing, ok := owners[outPath]
+			if !ok || existing.node == node {
+				owners[outPath] = current
+				continue
+			}
+
+			switch node.Collision {
+			case CollisionPolicySkip:
+				drop(current)
+				r.Warnings = append(r.Warnings, Warning{
+					Template: node.Template.Name,
+					Message:  fmt.Sprintf("skipped %s, already provided by %s", outPath, existing.node.Template.Name),
+				})
+			case CollisionPolicyOverride:
+				drop(existing)
+				owners[outPath] = current
+				r.Warnings = append(r.Warnings, Warning{
+					Template: node.Template.Name,
+					Message:  fmt.Sprintf("overrode %s provided by %s", outPath, existing.node.Template.Name),
+				})
+			default:
+				collisions = append(collisions, Collision{
+					Path:      outPath,
+					Templates: []string{existing.node.Template.Name, node.Template.Name},
+				})
+			}
+		}
+
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	if len(collisions) > 0 {
+		return &CollisionError{Collisions: collisions}
+	}
+
+	for id, indexes := range dropped {
+		kept := make([]RenderedFile, 0, len(r.Files[id]))
+		for i, file := range r.Files[id] {
+			if !indexes[i] {
+				kept = append(kept, file)
+			}
+		}
+		if len(kept) == 0 {
+			delete(r.Files, id)
+			continue
+		}
+		r.Files[id] = kept
+	}
+
+	return nil
+}
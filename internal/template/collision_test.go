@@ -0,0 +1,77 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collisionTree(policy CollisionPolicy) *TemplateNode {
+	return &TemplateNode{
+		ID:       "0",
+		Template: &Template{Name: "base"},
+		Children: []*TemplateNode{
+			{ID: "0.0", Template: &Template{Name: "docker"}, Collision: policy},
+		},
+	}
+}
+
+func collisionResult() *RenderResult {
+	return &RenderResult{
+		Files: map[string][]RenderedFile{
+			"0": {
+				{Path: "Makefile", Content: []byte("base")},
+				{Path: "main.go", Content: []byte("package main")},
+			},
+			"0.0": {
+				{Path: "Makefile", Content: []byte("docker")},
+				{Path: "Dockerfile", Content: []byte("FROM scratch")},
+			},
+		},
+	}
+}
+
+func TestResolveCollisions_ErrorByDefault(t *testing.T) {
+	result := collisionResult()
+
+	err := result.ResolveCollisions(collisionTree(""), nil)
+
+	var collisionErr *CollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	require.Len(t, collisionErr.Collisions, 1)
+	assert.Equal(t, "Makefile", collisionErr.Collisions[0].Path)
+	assert.Equal(t, []string{"base", "docker"}, collisionErr.Collisions[0].Templates)
+}
+
+func TestResolveCollisions_Skip(t *testing.T) {
+	result := collisionResult()
+
+	require.NoError(t, result.ResolveCollisions(collisionTree(CollisionPolicySkip), nil))
+
+	require.Len(t, result.Files["0"], 2)
+	assert.Equal(t, "base", string(result.Files["0"][0].Content))
+	require.Len(t, result.Files["0.0"], 1)
+	assert.Equal(t, "Dockerfile", result.Files["0.0"][0].Path)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "docker", result.Warnings[0].Template)
+}
+
+func TestResolveCollisions_Override(t *testing.T) {
+	result := collisionResult()
+
+	require.NoError(t, result.ResolveCollisions(collisionTree(CollisionPolicyOverride), nil))
+
+	require.Len(t, result.Files["0"], 1)
+	assert.Equal(t, "main.go", result.Files["0"][0].Path)
+	require.Len(t, result.Files["0.0"], 2)
+	require.Len(t, result.Warnings, 1)
+}
+
+func TestResolveCollisions_DifferentDirsDoNotCollide(t *testing.T) {
+	result := collisionResult()
+
+	err := result.ResolveCollisions(collisionTree(""), map[string]string{"0.0": "service"})
+	require.NoError(t, err)
+	assert.Len(t, result.Files["0.0"], 2)
+}
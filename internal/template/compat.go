@@ -0,0 +1,147 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// CompatIssue describes a construct in a template file that the renderer
+// cannot render.
+type CompatIssue struct {
+	File      string
+	Line      int
+	Construct string
+	Message   string
+}
+
+var (
+	undefinedFuncPattern = regexp.MustCompile(`:(\d+): function "([^"]+)" not defined`)
+	parseErrorPattern    = regexp.MustCompile(`^template: [^:]*:(\d+):(?:\d+:)? ?(.*)$`)
+	jinjaBlockPattern    = regexp.MustCompile(`\{%-?\s*(\w+)`)
+	jinjaCommentPattern  = regexp.MustCompile(`\{#`)
+)
+
+// CheckCompatibility reports the constructs in content that the renderer
+// cannot handle. Unlike rendering, it does not stop at the first problem:
+// every unknown function or filter is reported once, along with Jinja
+// statements and comments, which are not template syntax here and would be
+// copied verbatim. A syntax error ends the check of the file.
+func (r *Renderer) CheckCompatibility(name, content string) []CompatIssue {
+	var issues []CompatIssue
+
+	for _, m := range jinjaBlockPattern.FindAllStringSubmatchIndex(content, -1) {
+		keyword := content[m[2]:m[3]]
+		action := keyword
+		if strings.HasPrefix(keyword, "end") {
+			action = "end"
+		}
+		issues = append(issues, CompatIssue{
+			File:      name,
+			Line:      lineAt(content, m[0]),
+			Construct: "{% " + keyword + " %}",
+			Message:   "Jinja statements are not supported; use {{ " + action + " }} actions",
+		})
+	}
+	for _, m := range jinjaCommentPattern.FindAllStringIndex(content, -1) {
+		issues = append(issues, CompatIssue{
+			File:      name,
+			Line:      lineAt(content, m[0]),
+			Construct: "{# #}",
+			Message:   "Jinja comments are not supported; use {{/* */}}",
+		})
+	}
+
+	funcs := make(template.FuncMap, len(r.funcMap))
+	for k, v := range r.funcMap {
+		funcs[k] = v
+	}
+
+	for {
+		_, err := r.newTemplate(name).Funcs(funcs).Parse(content)
+		if err == nil {
+			break
+		}
+
+		if m := undefinedFuncPattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			message := fmt.Sprintf("unknown function or filter %q", m[2])
+			if lib := funcLibraryOf(m[2]); lib != "" {
+				message = fmt.Sprintf("function %q is in the optional %q library; enable it with `functions: [%s]`", m[2], lib, lib)
+			}
+			issues = append(issues, CompatIssue{
+				File:      name,
+				Line:      line,
+				Construct: m[2],
+				Message:   message,
+			})
+			funcs[m[2]] = func(...any) string { return "" }
+			continue
+		}
+
+		issue := CompatIssue{File: name, Construct: "syntax", Message: err.Error()}
+		if m := parseErrorPattern.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+			issue.Message = m[2]
+		}
+		issues = append(issues, issue)
+		break
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// CompatibilityReport checks every file under root in fsys and returns all
+// issues found. Binary files are skipped.
+func (r *Renderer) CompatibilityReport(fsys fs.FS, root string) ([]CompatIssue, error) {
+	var issues []CompatIssue
+
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		if IsBinary(content) {
+			return nil
+		}
+
+		// Destination paths are rendered too, so check the file path itself.
+		for _, issue := range r.CheckCompatibility(p, p) {
+			issue.Line = 0
+			issue.Message += " in file path"
+			issues = append(issues, issue)
+		}
+
+		issues = append(issues, r.CheckCompatibility(p, string(content))...)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("template directory %s not found: %w", root, err)
+		}
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+// lineAt returns the 1-based line number of offset in content.
+func lineAt(content string, offset int) int {
+	return bytes.Count([]byte(content[:offset]), []byte("\n")) + 1
+}
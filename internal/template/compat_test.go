@@ -0,0 +1,74 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCompatibility_ReportsAllUnknownFunctions(t *testing.T) {
+	r, _ := newTestRenderer(t)
+
+	issues := r.CheckCompatibility("README.md", "# {{ .name | upper }}\n\n{{ slugify .name }}\n{{ .name | toUpper }}\n{{ .name | upper }}")
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, "upper", issues[0].Construct)
+	assert.Equal(t, 1, issues[0].Line)
+	assert.Equal(t, "slugify", issues[1].Construct)
+	assert.Equal(t, 3, issues[1].Line)
+}
+
+func TestCheckCompatibility_ReportsJinjaConstructs(t *testing.T) {
+	r, _ := newTestRenderer(t)
+
+	issues := r.CheckCompatibility("main.py", "{# header #}\nimport os\n{% if use_db %}\nimport db\n{% endif %}\n")
+
+	require.Len(t, issues, 3)
+	assert.Equal(t, "{# #}", issues[0].Construct)
+	assert.Equal(t, "{% if %}", issues[1].Construct)
+	assert.Equal(t, 3, issues[1].Line)
+	assert.Equal(t, "{% endif %}", issues[2].Construct)
+	assert.Equal(t, 5, issues[2].Line)
+}
+
+func TestCheckCompatibility_SyntaxError(t *testing.T) {
+	r, _ := newTestRenderer(t)
+
+	issues := r.CheckCompatibility("broken", "line one\n{{ .name ")
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "syntax", issues[0].Construct)
+	assert.Equal(t, 2, issues[0].Line)
+}
+
+func TestCompatibilityReport_ContinuesPastFailingFiles(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("{{ cookiecutter.name }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("{{ .ok }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("{{ name|lower }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), []byte{0x89, 'P', 0, '{', '{'}, 0644))
+
+	issues, err := r.CompatibilityReport(os.DirFS(dir), ".")
+	require.NoError(t, err)
+
+	files := make(map[string]int)
+	for _, issue := range issues {
+		files[issue.File]++
+	}
+	assert.Equal(t, map[string]int{"a.txt": 1, "c.txt": 2}, files)
+}
+
+func TestCheckCompatibility_SuggestsFunctionLibrary(t *testing.T) {
+	r, _ := newTestRenderer(t)
+
+	issues := r.CheckCompatibility("secret.env", "TOKEN={{ randHex 32 }}")
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Message, `optional "crypto" library`)
+
+	require.NoError(t, r.EnableLibrary("crypto"))
+	assert.Empty(t, r.CheckCompatibility("secret.env", "TOKEN={{ randHex 32 }}"))
+}
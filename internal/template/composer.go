@@ -3,78 +3,277 @@ package template
 import (
 	"fmt"
 	"slices"
+	"strings"
 )
 
 // Composer handles building the TemplateNode tree from a root Template.
 type Composer struct {
-	resolver Resolver
-	loader   Loader
+	resolver  Resolver
+	loader    Loader
+	condition func(condition string, ctx *Context) (bool, error)
 }
 
 // NewComposer creates a new template composer with the given resolver and loader.
 func NewComposer(resolver Resolver, loader Loader) *Composer {
 	return &Composer{
-		resolver: resolver,
-		loader:   loader,
+		resolver:  resolver,
+		loader:    loader,
+		condition: NewRenderer().EvaluateCondition,
 	}
 }
 
+// ComposeOptions controls how a template tree is composed.
+type ComposeOptions struct {
+	// Confirm decides which optional includes of a template should be loaded.
+	Confirm ConfirmIncludes
+
+	// Variables, if set, collects the variables of each node before its
+	// includes are resolved. The returned context is used to evaluate the
+	// `when` conditions of the node's includes.
+	Variables CollectVariables
+
+	// Mandated lists includes, keyed by template type, that are composed into
+	// every template of that type without being offered to Confirm.
+	Mandated map[Type][]string
+
+	// IncludeAll composes every declared include, ignoring conditions and
+	// Confirm. It is used to inspect the full shape of a template.
+	IncludeAll bool
+
+	versions *versionSelection
+}
+
 // Compose resolves all includes for a template recursively and builds a TemplateNode tree.
 // It calls confirm for all includes of a template to decide which ones should be loaded.
 func (c *Composer) Compose(loaded *LoadedTemplate, confirm ConfirmIncludes) (*TemplateNode, error) {
-	return c.doCompose(loaded, []string{loaded.Template.Name}, confirm, "0")
+	return c.ComposeWithOptions(loaded, ComposeOptions{Confirm: confirm})
+}
+
+// ComposeWithOptions resolves all includes for a template recursively and builds a TemplateNode tree.
+//
+// Includes with a `when` condition are enabled or disabled by evaluating the condition
+// against the variables of the including node and are never passed to opts.Confirm.
+//
+// Includes may constrain the version of the template they include. Before
+// composing, every constrained template is assigned the newest version that
+// satisfies all constraints on it across the tree; see selectVersions.
+func (c *Composer) ComposeWithOptions(loaded *LoadedTemplate, opts ComposeOptions) (*TemplateNode, error) {
+	versions, err := c.selectVersions(loaded, opts.Mandated)
+	if err != nil {
+		return nil, err
+	}
+	opts.versions = versions
+
+	root := &TemplateNode{ID: rootNodeID, Versions: versions.resolutions}
+	if err := c.doCompose(root, loaded, []string{loaded.Template.Name}, opts); err != nil {
+		return nil, err
+	}
+	return root, nil
 }
 
 // doCompose is the internal recursive composition function that tracks the stack
 // to detect circular dependencies and builds the TemplateNode tree.
-func (c *Composer) doCompose(loaded *LoadedTemplate, stack []string, confirm ConfirmIncludes, id string) (*TemplateNode, error) {
-	node := &TemplateNode{
-		ID:       id,
-		Template: loaded.Template,
-		FS:       loaded.FS,
-		Path:     loaded.Path,
-		Children: make([]*TemplateNode, 0),
+func (c *Composer) doCompose(node *TemplateNode, loaded *LoadedTemplate, stack []string, opts ComposeOptions) error {
+	node.Template = loaded.Template
+	node.FS = loaded.FS
+	node.Path = loaded.Path
+	node.Children = make([]*TemplateNode, 0)
+
+	ctx := NewTemplateContext(make(map[string]any))
+	if opts.Variables != nil {
+		collected, err := opts.Variables(node)
+		if err != nil {
+			return err
+		}
+		if collected != nil {
+			ctx = collected
+		}
 	}
 
-	if len(loaded.Template.Includes) == 0 {
-		return node, nil
+	if len(loaded.Template.Includes) == 0 && len(opts.Mandated[loaded.Template.Type]) == 0 {
+		return nil
 	}
 
-	enabledIncludes, err := confirm(loaded.Template.Includes)
-	if err != nil {
-		return nil, err
+	var enabledIncludes []Include
+	if opts.IncludeAll {
+		for _, inc := range loaded.Template.Includes {
+			if !isMandated(inc.Name, loaded.Template.Type, opts.Mandated) {
+				enabledIncludes = append(enabledIncludes, inc)
+			}
+		}
+	} else {
+		enabled, err := c.enabledIncludes(loaded.Template, ctx, opts.Confirm, opts.Mandated)
+		if err != nil {
+			return err
+		}
+		enabledIncludes = enabled
 	}
 
+	enabledIncludes = appendMandated(enabledIncludes, loaded.Template, stack, opts.Mandated)
+
 	for i, inc := range enabledIncludes {
 		if slices.Contains(stack, inc.Name) {
-			return nil, fmt.Errorf("circular dependency detected: %v -> %s", stack, inc.Name)
+			return fmt.Errorf("circular dependency detected: %v -> %s", stack, inc.Name)
 		}
 
-		ref := TemplateRef{
-			Name: inc.Name,
+		includedTmpl, err := opts.versions.load(c, inc.Name)
+		if err != nil {
+			return err
 		}
 
-		resolved, err := c.resolver.Resolve(ref)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve included template '%s': %w", inc.Name, err)
+		newStack := append(slices.Clone(stack), inc.Name)
+		if err := checkVariableConflicts(loaded.Template, includedTmpl.Template, inc.Inherits, newStack); err != nil {
+			return err
+		}
+
+		childNode := &TemplateNode{
+			ID:        fmt.Sprintf("%s.%d", node.ID, i),
+			Mount:     inc.Mount,
+			Inherited: inc.Inherits,
+			Mandated:  inc.Mandated,
+			Collision: inc.Collision,
+		}
+
+		if err := c.doCompose(childNode, includedTmpl, newStack, opts); err != nil {
+			return err
+		}
+
+		node.Children = append(node.Children, childNode)
+	}
+
+	return nil
+}
+
+// enabledIncludes returns the includes of tmpl that should be loaded, in declaration order.
+// Conditional includes are decided by their `when` expression; the remaining ones are
+// passed to confirm. Mandated includes are left out; they are added by appendMandated.
+func (c *Composer) enabledIncludes(tmpl *Template, ctx *Context, confirm ConfirmIncludes, mandated map[Type][]string) ([]Include, error) {
+	enabledConditional := make(map[int]bool)
+	var optional []Include
+
+	for i, inc := range tmpl.Includes {
+		if isMandated(inc.Name, tmpl.Type, mandated) {
+			continue
 		}
 
-		includedTmpl, err := c.loader.Load(resolved.FS, resolved.Path)
+		if inc.When == "" {
+			optional = append(optional, inc)
+			continue
+		}
+
+		ok, err := c.condition(inc.When, ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load included template '%s' from %s: %w", inc.Name, resolved.Path, err)
+			return nil, fmt.Errorf("failed to evaluate condition for include '%s': %w", inc.Name, err)
 		}
+		enabledConditional[i] = ok
+	}
 
-		newStack := append(slices.Clone(stack), inc.Name)
-		childID := fmt.Sprintf("%s.%d", id, i)
-		childNode, err := c.doCompose(includedTmpl, newStack, confirm, childID)
+	confirmed := make(map[string]bool)
+	if len(optional) > 0 {
+		selected, err := confirm(optional)
 		if err != nil {
 			return nil, err
 		}
-		childNode.Mount = inc.Mount
-		childNode.Inherited = inc.Inherits
+		for _, inc := range selected {
+			confirmed[inc.Name] = true
+		}
+	}
 
-		node.Children = append(node.Children, childNode)
+	var enabled []Include
+	for i, inc := range tmpl.Includes {
+		if isMandated(inc.Name, tmpl.Type, mandated) {
+			continue
+		}
+
+		if inc.When != "" {
+			if enabledConditional[i] {
+				enabled = append(enabled, inc)
+			}
+			continue
+		}
+		if confirmed[inc.Name] {
+			enabled = append(enabled, inc)
+		}
 	}
 
-	return node, nil
+	return enabled, nil
+}
+
+// appendMandated appends the includes mandated for the template's type.
+// Includes already on the composition stack are not injected, so a mandated
+// template is never composed into itself.
+func appendMandated(enabled []Include, tmpl *Template, stack []string, mandated map[Type][]string) []Include {
+	for _, name := range mandated[tmpl.Type] {
+		if slices.Contains(stack, name) {
+			continue
+		}
+
+		inc := Include{Name: name, Mandated: true}
+		for _, declared := range tmpl.Includes {
+			if declared.Name == name {
+				inc = declared
+				inc.Mandated = true
+				break
+			}
+		}
+
+		enabled = append(enabled, inc)
+	}
+	return enabled
+}
+
+func isMandated(name string, typ Type, mandated map[Type][]string) bool {
+	return slices.Contains(mandated[typ], name)
+}
+
+// checkVariableConflicts checks that every variable of an included template
+// that the parent declares as well, or that it inherits from the parent, has
+// the type and options of the parent's variable. Otherwise a value given for
+// both, or inherited, would not be valid for one of them.
+func checkVariableConflicts(parent, child *Template, inherits map[string]string, stack []string) error {
+	for _, cv := range child.Variables {
+		name := cv.Name
+		if inherited, ok := inherits[cv.Name]; ok {
+			name = inherited
+		}
+		i := slices.IndexFunc(parent.Variables, func(v Variable) bool { return v.Name == name })
+		if i < 0 {
+			continue
+		}
+
+		if reason := variableMismatch(parent.Variables[i], cv); reason != "" {
+			return &VariableConflictError{
+				Include:        stack,
+				Variable:       cv.Name,
+				Parent:         parent.Name,
+				ParentVariable: name,
+				Reason:         reason,
+			}
+		}
+	}
+	return nil
+}
+
+// variableMismatch describes how the type or options of v differ from those
+// of want, or returns an empty string when they match.
+func variableMismatch(want, v Variable) string {
+	if v.Type != want.Type {
+		return fmt.Sprintf("type is %s, not %s", v.Type, want.Type)
+	}
+	if v.Type != VariableTypeSelect && v.Type != VariableTypeMultiSelect {
+		return ""
+	}
+
+	values := func(options []Option) []string {
+		out := make([]string, len(options))
+		for i, o := range options {
+			out[i] = o.Value
+		}
+		slices.Sort(out)
+		return out
+	}
+	if got, wanted := values(v.Options), values(want.Options); !slices.Equal(got, wanted) {
+		return fmt.Sprintf("options are %s, not %s", strings.Join(got, ", "), strings.Join(wanted, ", "))
+	}
+	return ""
 }
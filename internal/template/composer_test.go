@@ -3,6 +3,7 @@ package template
 import (
 	"errors"
 	"io/fs"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -132,6 +133,43 @@ func TestCompose_WithIncludes_BuildsTree(t *testing.T) {
 	)
 }
 
+func TestCompose_VariableConflicts(t *testing.T) {
+	compose := func(child *Template, inherits map[string]string) error {
+		app := &Template{
+			Name:     "app",
+			Includes: []Include{{Name: child.Name, EnabledByDefault: true, Inherits: inherits}},
+			Variables: []Variable{
+				{Name: "port", Type: VariableTypeInt},
+				{Name: "driver", Type: VariableTypeSelect, Options: NewOptions("postgres", "mysql")},
+			},
+		}
+		templates := map[string]*Template{child.Name: child}
+		composer := NewComposer(&fakeResolver{templates: templates}, &fakeLoader{templates: templates})
+		_, err := composer.Compose(&LoadedTemplate{Template: app, Path: "app"}, func(includes []Include) ([]Include, error) {
+			return includes, nil
+		})
+		return err
+	}
+
+	require.NoError(t, compose(&Template{Name: "database", Variables: []Variable{
+		{Name: "port", Type: VariableTypeInt},
+		{Name: "driver", Type: VariableTypeSelect, Options: NewOptions("mysql", "postgres")},
+	}}, nil))
+
+	err := compose(&Template{Name: "database", Variables: []Variable{{Name: "port", Type: VariableTypeString}}}, nil)
+	var conflictErr *VariableConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "include app > database: variable port does not match port of app: type is string, not int", err.Error())
+
+	err = compose(&Template{Name: "database", Variables: []Variable{
+		{Name: "engine", Type: VariableTypeSelect, Options: NewOptions("postgres", "sqlite")},
+	}}, map[string]string{"engine": "driver"})
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "engine", conflictErr.Variable)
+	assert.Equal(t, "driver", conflictErr.ParentVariable)
+	assert.Equal(t, "options are postgres, sqlite, not mysql, postgres", conflictErr.Reason)
+}
+
 func TestCompose_CircularDependencyDetected(t *testing.T) {
 	a := &Template{
 		Name: "a",
@@ -300,3 +338,214 @@ func TestCompose_AssignsIDs(t *testing.T) {
 	assert.Equal(t, "0.1.1", out.Children[1].Children[1].ID)
 	assert.Equal(t, "grandchild1", out.Children[1].Children[1].Template.Name)
 }
+
+func TestComposeWithOptions_ConditionalIncludes(t *testing.T) {
+	base := &Template{
+		Name: "base",
+		Includes: []Include{
+			{Name: "postgres", When: `{{ eq .database "postgres" }}`},
+			{Name: "mysql", When: `{{ eq .database "mysql" }}`},
+			{Name: "logging"},
+		},
+	}
+
+	templates := map[string]*Template{
+		"postgres": {Name: "postgres"},
+		"mysql":    {Name: "mysql"},
+		"logging":  {Name: "logging"},
+	}
+
+	composer := NewComposer(&fakeResolver{templates: templates}, &fakeLoader{templates: templates})
+
+	var confirmed []string
+	out, err := composer.ComposeWithOptions(
+		&LoadedTemplate{Template: base, Path: "base"},
+		ComposeOptions{
+			Confirm: func(includes []Include) ([]Include, error) {
+				for _, inc := range includes {
+					confirmed = append(confirmed, inc.Name)
+				}
+				return includes, nil
+			},
+			Variables: func(node *TemplateNode) (*Context, error) {
+				return NewTemplateContext(map[string]any{"database": "postgres"}), nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"logging"}, confirmed)
+	require.Len(t, out.Children, 2)
+	assert.Equal(t, "postgres", out.Children[0].Template.Name)
+	assert.Equal(t, "logging", out.Children[1].Template.Name)
+	assert.Equal(t, "0.1", out.Children[1].ID)
+}
+
+func TestComposeWithOptions_MandatedIncludes(t *testing.T) {
+	base := &Template{
+		Name: "base",
+		Type: TypeProject,
+		Includes: []Include{
+			{Name: "logging"},
+		},
+	}
+
+	templates := map[string]*Template{
+		"logging":    {Name: "logging", Type: TypeFeature},
+		"compliance": {Name: "compliance", Type: TypeFeature},
+	}
+
+	composer := NewComposer(&fakeResolver{templates: templates}, &fakeLoader{templates: templates})
+
+	var offered []string
+	out, err := composer.ComposeWithOptions(
+		&LoadedTemplate{Template: base, Path: "base"},
+		ComposeOptions{
+			Confirm: func(includes []Include) ([]Include, error) {
+				for _, inc := range includes {
+					offered = append(offered, inc.Name)
+				}
+				return nil, nil
+			},
+			Mandated: map[Type][]string{TypeProject: {"compliance"}},
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"logging"}, offered)
+	require.Len(t, out.Children, 1)
+	assert.Equal(t, "compliance", out.Children[0].Template.Name)
+	assert.True(t, out.Children[0].Mandated)
+}
+
+// versionedResolver offers several versions of each template, keyed by
+// name@version.
+type versionedResolver struct {
+	templates map[string]*Template
+}
+
+func (f *versionedResolver) Resolve(ref TemplateRef) (*ResolvedTemplate, error) {
+	var found []string
+	for key, tmpl := range f.templates {
+		if tmpl.Name == ref.Name && (ref.Version == "" || tmpl.Version == ref.Version) {
+			found = append(found, key)
+		}
+	}
+	if len(found) == 0 {
+		return nil, &TemplateNotFoundError{Name: ref.Name}
+	}
+	slices.Sort(found)
+	return &ResolvedTemplate{Path: found[0]}, nil
+}
+
+func (f *versionedResolver) Versions(name string) ([]string, error) {
+	var versions []string
+	for _, tmpl := range f.templates {
+		if tmpl.Name == name {
+			versions = append(versions, tmpl.Version)
+		}
+	}
+	return versions, nil
+}
+
+func TestComposeWithOptions_IncludeVersions(t *testing.T) {
+	templates := map[string]*Template{
+		"api@1.0.0": {Name: "api", Version: "1.0.0", Includes: []Include{
+			{Name: "logging", Version: "^1.2.0"},
+			{Name: "metrics"},
+		}},
+		"metrics@1.0.0": {Name: "metrics", Version: "1.0.0", Includes: []Include{{Name: "logging", Version: "<1.5.0"}}},
+		"logging@1.1.0": {Name: "logging", Version: "1.1.0"},
+		"logging@1.4.2": {Name: "logging", Version: "1.4.2"},
+		"logging@1.6.0": {Name: "logging", Version: "1.6.0"},
+		"logging@2.0.0": {Name: "logging", Version: "2.0.0"},
+	}
+	composer := NewComposer(&versionedResolver{templates: templates}, &fakeLoader{templates: templates})
+
+	out, err := composer.ComposeWithOptions(
+		&LoadedTemplate{Template: templates["api@1.0.0"], Path: "api@1.0.0"},
+		ComposeOptions{IncludeAll: true},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, out.Children, 2)
+	assert.Equal(t, "1.4.2", out.Children[0].Template.Version)
+	assert.Equal(t, "1.4.2", out.Children[1].Children[0].Template.Version)
+	assert.Equal(t, []VersionResolution{{
+		Template: "logging",
+		Version:  "1.4.2",
+		Requirements: []VersionRequirement{
+			{By: "api", Constraint: "^1.2.0"},
+			{By: "metrics", Constraint: "<1.5.0"},
+		},
+	}}, out.Versions)
+}
+
+func TestComposeWithOptions_IncludeVersionConflict(t *testing.T) {
+	templates := map[string]*Template{
+		"api@1.0.0": {Name: "api", Version: "1.0.0", Includes: []Include{
+			{Name: "logging", Version: "^2.0.0"},
+			{Name: "metrics"},
+		}},
+		"metrics@1.0.0": {Name: "metrics", Version: "1.0.0", Includes: []Include{{Name: "logging", Version: "~1.4"}}},
+		"logging@1.4.2": {Name: "logging", Version: "1.4.2"},
+		"logging@2.0.0": {Name: "logging", Version: "2.0.0"},
+	}
+	composer := NewComposer(&versionedResolver{templates: templates}, &fakeLoader{templates: templates})
+
+	_, err := composer.ComposeWithOptions(
+		&LoadedTemplate{Template: templates["api@1.0.0"], Path: "api@1.0.0"},
+		ComposeOptions{IncludeAll: true},
+	)
+	var conflict *VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "logging", conflict.Template)
+	assert.Equal(t, []string{"2.0.0", "1.4.2"}, conflict.Available)
+	assert.Len(t, conflict.Requirements, 2)
+}
+
+func TestComposeWithOptions_IncludeVersionsBacktrack(t *testing.T) {
+	// The newest db requires a newer driver than api allows, so the older
+	// db, which works with the driver api allows, is chosen.
+	templates := map[string]*Template{
+		"api@1.0.0": {Name: "api", Version: "1.0.0", Includes: []Include{
+			{Name: "db", Version: ">=1.0.0"},
+			{Name: "driver", Version: "1.x"},
+		}},
+		"db@1.0.0":     {Name: "db", Version: "1.0.0", Includes: []Include{{Name: "driver", Version: "^1.0.0"}}},
+		"db@2.0.0":     {Name: "db", Version: "2.0.0", Includes: []Include{{Name: "driver", Version: "^2.0.0"}}},
+		"driver@1.3.0": {Name: "driver", Version: "1.3.0"},
+		"driver@2.1.0": {Name: "driver", Version: "2.1.0"},
+	}
+	composer := NewComposer(&versionedResolver{templates: templates}, &fakeLoader{templates: templates})
+
+	out, err := composer.ComposeWithOptions(
+		&LoadedTemplate{Template: templates["api@1.0.0"], Path: "api@1.0.0"},
+		ComposeOptions{IncludeAll: true},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, out.Children, 2)
+	assert.Equal(t, "1.0.0", out.Children[0].Template.Version)
+	assert.Equal(t, "1.3.0", out.Children[1].Template.Version)
+}
+
+func TestAllDependencies_HighestVersionWins(t *testing.T) {
+	tree := &TemplateNode{
+		Template: &Template{Name: "api", Dependencies: []string{"github.com/spf13/cobra@v1.8.0", "github.com/google/uuid"}},
+		Children: []*TemplateNode{
+			{Template: &Template{Name: "cli", Dependencies: []string{"github.com/spf13/cobra@v1.10.2", "github.com/google/uuid"}}},
+			{Template: &Template{Name: "docs", Dependencies: []string{"github.com/spf13/cobra@v1.9.1", "github.com/google/uuid@v1.6.0"}}},
+		},
+	}
+
+	assert.Equal(t, []string{"github.com/spf13/cobra@v1.10.2", "github.com/google/uuid@v1.6.0"}, tree.AllDependencies())
+
+	conflicts := tree.DependencyConflicts()
+	require.Len(t, conflicts, 1, "a version alongside no version is not a conflict")
+	assert.Equal(t, "github.com/spf13/cobra", conflicts[0].Package)
+	assert.Equal(t, "v1.10.2", conflicts[0].Version)
+	assert.Equal(t,
+		"github.com/spf13/cobra: api requests v1.8.0, cli requests v1.10.2, docs requests v1.9.1; using v1.10.2",
+		conflicts[0].String())
+}
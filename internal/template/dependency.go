@@ -1,31 +1,125 @@
 package template
 
-// AllDependencies recursively collects and merges all dependencies from the tree.
+import (
+	"fmt"
+	"strings"
+)
+
+// DependencyRequest is a version of a package that a template of the tree
+// depends on. Version is empty for a dependency declared without one.
+type DependencyRequest struct {
+	Template string
+	Version  string
+}
+
+// DependencyConflict is a package that templates of the tree depend on at
+// different versions.
+type DependencyConflict struct {
+	Package  string
+	Version  string // Version that is installed
+	Requests []DependencyRequest
+}
+
+func (c DependencyConflict) String() string {
+	parts := make([]string, 0, len(c.Requests))
+	for _, r := range c.Requests {
+		if r.Version != "" {
+			parts = append(parts, fmt.Sprintf("%s requests %s", r.Template, r.Version))
+		}
+	}
+	return fmt.Sprintf("%s: %s; using %s", c.Package, strings.Join(parts, ", "), c.Version)
+}
+
+// dependency is a package with every version requested for it.
+type dependency struct {
+	pkg      string
+	requests []DependencyRequest
+}
+
+// version returns the version a dependency is installed at: the highest
+// requested version, or an empty string when no template names one.
+// Versions that are not semver rank below those that are.
+func (d *dependency) version() string {
+	var chosen string
+	for _, r := range d.requests {
+		if r.Version != "" && (chosen == "" || compareVersions(r.Version, chosen) > 0) {
+			chosen = r.Version
+		}
+	}
+	return chosen
+}
+
+// conflicting reports whether different versions are requested.
+func (d *dependency) conflicting() bool {
+	var first string
+	for _, r := range d.requests {
+		switch {
+		case r.Version == "":
+		case first == "":
+			first = r.Version
+		case r.Version != first:
+			return true
+		}
+	}
+	return false
+}
+
+// AllDependencies recursively collects and merges all dependencies from the
+// tree, in the order they are first declared. A package requested at
+// different versions is installed at the highest one.
 func (n *TemplateNode) AllDependencies() []string {
-	depMap := make(map[string]string)
-	n.collectDependencies(depMap)
+	deps := n.dependencies()
 
-	result := make([]string, 0, len(depMap))
-	for pkg, version := range depMap {
-		if version != "" {
-			result = append(result, pkg+"@"+version)
+	result := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		if version := dep.version(); version != "" {
+			result = append(result, dep.pkg+"@"+version)
 		} else {
-			result = append(result, pkg)
+			result = append(result, dep.pkg)
 		}
 	}
 	return result
 }
 
-func (n *TemplateNode) collectDependencies(depMap map[string]string) {
-	for _, dep := range n.Template.Dependencies {
-		pkg, version := parseDependency(dep)
-		if existing, ok := depMap[pkg]; !ok || existing == "" {
-			depMap[pkg] = version
+// DependencyConflicts returns the packages that templates of the tree depend
+// on at different versions.
+func (n *TemplateNode) DependencyConflicts() []DependencyConflict {
+	var conflicts []DependencyConflict
+	for _, dep := range n.dependencies() {
+		if dep.conflicting() {
+			conflicts = append(conflicts, DependencyConflict{
+				Package:  dep.pkg,
+				Version:  dep.version(),
+				Requests: dep.requests,
+			})
+		}
+	}
+	return conflicts
+}
+
+// dependencies returns the packages the templates of the tree depend on, in
+// the order they are first declared.
+func (n *TemplateNode) dependencies() []*dependency {
+	var deps []*dependency
+	index := make(map[string]*dependency)
+	n.collectDependencies(&deps, index)
+	return deps
+}
+
+func (n *TemplateNode) collectDependencies(deps *[]*dependency, index map[string]*dependency) {
+	for _, d := range n.Template.Dependencies {
+		pkg, version := parseDependency(d)
+		dep, ok := index[pkg]
+		if !ok {
+			dep = &dependency{pkg: pkg}
+			index[pkg] = dep
+			*deps = append(*deps, dep)
 		}
+		dep.requests = append(dep.requests, DependencyRequest{Template: n.Template.Name, Version: version})
 	}
 
 	for _, child := range n.Children {
-		child.collectDependencies(depMap)
+		child.collectDependencies(deps, index)
 	}
 }
 
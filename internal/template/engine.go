@@ -17,6 +17,9 @@ type Engine struct {
 // NewEngine creates a new template engine with the given resolver
 func NewEngine(resolver Resolver) *Engine {
 	loader := NewLoader()
+	if libs, ok := resolver.(VariableLibraryResolver); ok {
+		loader = loader.WithLibraries(libs)
+	}
 	composer := NewComposer(resolver, loader)
 	renderer := NewRenderer()
 	validator := NewValidator()
@@ -55,21 +58,58 @@ func (e *Engine) RenderNode(node *TemplateNode, contexts RenderContexts) (*Rende
 	return e.renderer.RenderAll(node, contexts)
 }
 
-// GetFullTree loads a template, resolves all includes using the provided confirm function,
+// PlanNode resolves all files of a template tree without rendering their
+// content; see Renderer.PlanAll.
+func (e *Engine) PlanNode(node *TemplateNode, contexts RenderContexts) (*RenderResult, error) {
+	return e.renderer.PlanAll(node, contexts)
+}
+
+// RenderPath renders a path template such as a destination or workdir with the given context.
+func (e *Engine) RenderPath(pathTemplate string, ctx *Context) (string, error) {
+	return e.renderer.RenderPath(pathTemplate, ctx)
+}
+
+// RenderText renders a text template with the given context.
+func (e *Engine) RenderText(text string, ctx *Context) (string, error) {
+	rendered, err := e.renderer.RenderString(text, ctx, "text")
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// RenderStandalone renders a single template file outside of any template;
+// see Renderer.RenderStandalone.
+func (e *Engine) RenderStandalone(content []byte, ctx *Context, name string) ([]byte, error) {
+	return e.renderer.RenderStandalone(content, ctx, name)
+}
+
+// AnalyzeTree reports the variables referenced by each file and template in a tree.
+func (e *Engine) AnalyzeTree(node *TemplateNode) (*Analysis, error) {
+	return e.renderer.AnalyzeTree(node)
+}
+
+// CheckCompatibility reports the constructs in every file under root that the
+// renderer cannot render.
+func (e *Engine) CheckCompatibility(fsys fs.FS, root string) ([]CompatIssue, error) {
+	return e.renderer.CompatibilityReport(fsys, root)
+}
+
+// GetFullTree loads a template, resolves all includes according to the provided options,
 // and validates the resulting tree.
-func (e *Engine) GetFullTree(ref TemplateRef, confirm ConfirmIncludes) (*TemplateNode, error) {
+func (e *Engine) GetFullTree(ref TemplateRef, opts ComposeOptions) (*TemplateNode, error) {
 	loaded, err := e.LoadTemplate(ref)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load template: %w", err)
 	}
 
-	tree, err := e.composer.Compose(loaded, confirm)
+	tree, err := e.composer.ComposeWithOptions(loaded, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := e.ValidateTree(tree); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, &ValidationError{Template: tree.Template.Name, Err: err}
 	}
 
 	return tree, nil
@@ -90,3 +130,9 @@ func (e *Engine) ValidateContexts(node *TemplateNode, contexts RenderContexts) e
 func (e *Engine) AddTemplateFunc(name string, fn any) {
 	e.renderer.AddFunc(name, fn)
 }
+
+// EnableFuncLibrary makes the functions of an optional library available to
+// every template.
+func (e *Engine) EnableFuncLibrary(name string) error {
+	return e.renderer.EnableLibrary(name)
+}
@@ -1,6 +1,9 @@
 package template
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // TemplateNotFoundError is returned when a template is not found.
 type TemplateNotFoundError struct {
@@ -10,3 +13,249 @@ type TemplateNotFoundError struct {
 func (e *TemplateNotFoundError) Error() string {
 	return fmt.Sprintf("template not found: %s", e.Name)
 }
+
+// VersionConflictError is returned when no available version of a template
+// satisfies every version constraint the includes of a tree place on it.
+type VersionConflictError struct {
+	Template     string
+	Requirements []VersionRequirement
+	Available    []string // Newest first
+}
+
+func (e *VersionConflictError) Error() string {
+	parts := make([]string, 0, len(e.Requirements))
+	for _, r := range e.Requirements {
+		parts = append(parts, fmt.Sprintf("%s requires %s", r.By, r.Constraint))
+	}
+	return fmt.Sprintf("no version of %s satisfies every include (%s; available: %s)",
+		e.Template, strings.Join(parts, ", "), strings.Join(e.Available, ", "))
+}
+
+// Collision describes templates that render files to the same path.
+type Collision struct {
+	Path      string
+	Templates []string
+}
+
+// CollisionError is returned when included templates render files to a path
+// that another template already renders to and the include does not declare
+// how to resolve the collision.
+type CollisionError struct {
+	Collisions []Collision
+}
+
+func (e *CollisionError) Error() string {
+	parts := make([]string, 0, len(e.Collisions))
+	for _, c := range e.Collisions {
+		parts = append(parts, fmt.Sprintf("%s (%s)", c.Path, strings.Join(c.Templates, ", ")))
+	}
+	return fmt.Sprintf("templates render conflicting files: %s", strings.Join(parts, "; "))
+}
+
+// OutsideOutputError is returned when rendered files would be written outside
+// the output directory without the template opting in and the user
+// confirming it.
+type OutsideOutputError struct {
+	Files []OutsideFile
+}
+
+func (e *OutsideOutputError) Error() string {
+	parts := make([]string, 0, len(e.Files))
+	for _, f := range e.Files {
+		parts = append(parts, fmt.Sprintf("%s (%s)", f.Path, f.Template))
+	}
+	return fmt.Sprintf("files would be written outside the output directory: %s", strings.Join(parts, "; "))
+}
+
+// MissingVariable identifies a variable that has no value.
+type MissingVariable struct {
+	Template string
+	NodeID   string
+	Name     string
+	Prompt   string
+}
+
+// MissingVariablesError is returned when variables of a template tree have no
+// value and could not be prompted for.
+type MissingVariablesError struct {
+	Variables []MissingVariable
+}
+
+func (e *MissingVariablesError) Error() string {
+	parts := make([]string, 0, len(e.Variables))
+	for _, v := range e.Variables {
+		parts = append(parts, fmt.Sprintf("template %s (ID: %s): variable %s is missing", v.Template, v.NodeID, v.Name))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// LintError is returned when linting a template found problems.
+type LintError struct {
+	Template string
+	Issues   int
+}
+
+func (e *LintError) Error() string {
+	return fmt.Sprintf("template %s has %d problem(s)", e.Template, e.Issues)
+}
+
+// FieldError is returned when a field of a template definition has a value
+// the schema does not accept.
+type FieldError struct {
+	Template  string
+	Field     string   // Path of the field in template.yaml, e.g. files[0].dest
+	Rule      string   // Validation rule that failed, e.g. required or oneof
+	Value     any      // The rejected value
+	Allowed   []string // Accepted values for oneof rules
+	Condition string   // Condition of required_if rules, e.g. "type is select"
+}
+
+func (e *FieldError) Error() string {
+	switch e.Rule {
+	case "required":
+		return fmt.Sprintf("%s: field is required", e.Field)
+	case "required_if":
+		return fmt.Sprintf("%s: field is required when %s", e.Field, e.Condition)
+	case "oneof":
+		return fmt.Sprintf("%s: %q is not one of %s", e.Field, fmt.Sprint(e.Value), strings.Join(e.Allowed, ", "))
+	default:
+		return fmt.Sprintf("%s: value %v failed the %s rule", e.Field, e.Value, e.Rule)
+	}
+}
+
+// ValidationError is returned when a template or a composed template tree is
+// invalid. Err joins every problem found.
+type ValidationError struct {
+	Template string
+	Err      error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("template validation failed: %v", e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// RenderError is returned when a template cannot be parsed or executed.
+type RenderError struct {
+	Name  string // Template file or expression that was rendered
+	Parse bool   // Whether parsing failed, rather than execution
+	Err   error
+}
+
+func (e *RenderError) Error() string {
+	if e.Parse {
+		return fmt.Sprintf("failed to parse template %s: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("failed to execute template %s: %v", e.Name, e.Err)
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// IncompatibleError is returned when a feature or component is added to a
+// project that does not meet the requirements it declares.
+type IncompatibleError struct {
+	Template   string
+	Project    string // Project template of the project
+	Mismatches []RequirementMismatch
+}
+
+func (e *IncompatibleError) Error() string {
+	parts := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		parts = append(parts, m.String())
+	}
+	return fmt.Sprintf("%s is not compatible with this %s project: %s", e.Template, e.Project, strings.Join(parts, "; "))
+}
+
+// VariableConflictError is returned when an included template declares a
+// variable that the template including it declares, or that it inherits from
+// it, with a different type or options.
+type VariableConflictError struct {
+	Include        []string // Templates from the root to the include
+	Variable       string
+	Parent         string // Template including it
+	ParentVariable string // Variable of the parent it is compared with
+	Reason         string
+}
+
+func (e *VariableConflictError) Error() string {
+	return fmt.Sprintf("include %s: variable %s does not match %s of %s: %s",
+		strings.Join(e.Include, " > "), e.Variable, e.ParentVariable, e.Parent, e.Reason)
+}
+
+// DependencyConflictError is returned when templates of a tree depend on
+// different versions of a package and conflicts are not allowed.
+type DependencyConflictError struct {
+	Conflicts []DependencyConflict
+}
+
+func (e *DependencyConflictError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, c.String())
+	}
+	return fmt.Sprintf("templates depend on conflicting versions (%s)", strings.Join(parts, "; "))
+}
+
+// AnchorNotFoundError is returned when a template injects content into a
+// file that has no comment marking the anchor.
+type AnchorNotFoundError struct {
+	Template string
+	Path     string
+	Anchor   string
+}
+
+func (e *AnchorNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s has no %s%s anchor to inject into", e.Template, e.Path, AnchorPrefix, e.Anchor)
+}
+
+// GoTargetNotFoundError is returned when a Go edit adds to a struct or
+// function that the Go file does not declare.
+type GoTargetNotFoundError struct {
+	Template string
+	Path     string
+	Kind     string // struct or func
+	Name     string
+}
+
+func (e *GoTargetNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s declares no %s %s to edit", e.Template, e.Path, e.Kind, e.Name)
+}
+
+// UnsupportedSchemaError is returned for a template.yaml written for a newer
+// schema than this version of Blueprint supports.
+type UnsupportedSchemaError struct {
+	Template string
+	Schema   int
+}
+
+func (e *UnsupportedSchemaError) Error() string {
+	name := e.Template
+	if name == "" {
+		name = "template"
+	}
+	return fmt.Sprintf("%s uses template schema %d, but this version of blueprint supports schema %d at most",
+		name, e.Schema, SchemaVersion)
+}
+
+// UnsupportedVersionError is returned for a template that declares a newer
+// min_blueprint_version than the running Blueprint release.
+type UnsupportedVersionError struct {
+	Template   string
+	MinVersion string
+	Version    string // Running Blueprint release
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	name := e.Template
+	if name == "" {
+		name = "template"
+	}
+	return fmt.Sprintf("%s requires blueprint %s or newer, but this is blueprint %s; upgrade blueprint to use it",
+		name, e.MinVersion, e.Version)
+}
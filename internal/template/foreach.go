@@ -0,0 +1,74 @@
+package template
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ForEachItemVariable is the variable that holds the current element while
+// a file entry with for_each is rendered.
+const ForEachItemVariable = "item"
+
+// forEachContexts returns the contexts a file entry is rendered with: ctx
+// itself for an ordinary entry, and for an entry with for_each a copy of ctx
+// per element of the list variable, with the element set as the item
+// variable. An unset or empty list renders the entry not at all.
+func forEachContexts(file File, ctx *Context) ([]*Context, error) {
+	if file.ForEach == "" {
+		return []*Context{ctx}, nil
+	}
+
+	value, ok := ctx.Get(file.ForEach)
+	if !ok || value == nil {
+		return nil, nil
+	}
+	items, ok := normalizeStringSlice(value)
+	if !ok {
+		return nil, fmt.Errorf("for_each: variable %s is a %T, not a list", file.ForEach, value)
+	}
+
+	contexts := make([]*Context, len(items))
+	for i, item := range items {
+		vars := maps.Clone(ctx.Variables)
+		vars[ForEachItemVariable] = item
+		contexts[i] = NewTemplateContext(vars)
+	}
+	return contexts, nil
+}
+
+// validateForEach checks that the file entries with for_each loop over a
+// multiselect variable of the template, and that the template does not
+// declare a variable that the item would hide.
+func validateForEach(tmpl *Template) []error {
+	var errs []error
+
+	declared := func(name string) *Variable {
+		i := slices.IndexFunc(tmpl.Variables, func(v Variable) bool { return v.Name == name })
+		if i < 0 {
+			return nil
+		}
+		return &tmpl.Variables[i]
+	}
+
+	looping := false
+	for i, file := range tmpl.Files {
+		if file.ForEach == "" {
+			continue
+		}
+		looping = true
+
+		variable := declared(file.ForEach)
+		switch {
+		case variable == nil:
+			errs = append(errs, fmt.Errorf("files[%d]: for_each: template does not declare variable %q", i, file.ForEach))
+		case variable.Type != VariableTypeMultiSelect:
+			errs = append(errs, fmt.Errorf("files[%d]: for_each: variable %q is a %s, not a multiselect", i, file.ForEach, variable.Type))
+		}
+	}
+
+	if looping && declared(ForEachItemVariable) != nil {
+		errs = append(errs, fmt.Errorf("variable %q is reserved for the element of for_each", ForEachItemVariable))
+	}
+	return errs
+}
@@ -0,0 +1,126 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterFence is the line that opens and closes the front matter of a
+// template file.
+const frontMatterFence = "---"
+
+// FrontMatter is the optional YAML block at the top of a .tmpl file, between
+// two lines of three dashes, that sets how the file is rendered. It lets a
+// directory entry of template.yaml carry files that need their own
+// destination, mode, or condition without an entry of their own. The block is
+// removed from the rendered file.
+type FrontMatter struct {
+	Dest       string   `yaml:"dest,omitempty"`       // Destination relative to the template's output directory; replaces the one of the file entry
+	Mode       string   `yaml:"mode,omitempty"`       // Octal permissions such as 0755
+	When       string   `yaml:"when,omitempty"`       // Condition the file is rendered under, in addition to the entry's
+	Delimiters []string `yaml:"delimiters,omitempty"` // Action delimiters of the rest of the file
+}
+
+// FileMode parses the octal permissions of the front matter. It returns 0
+// when no mode is set.
+func (fm *FrontMatter) FileMode() (fs.FileMode, error) {
+	return File{Mode: fm.Mode}.FileMode()
+}
+
+// SplitFrontMatter splits the content of a template file into its front
+// matter and the rest of the file. Content starts with front matter when its
+// first line is three dashes, a later line closes the block, and the block
+// is a mapping that sets at least one front matter field, so that a YAML
+// document that merely starts with a document separator is not taken for
+// front matter. Without front matter, fm is nil and body is content. A block
+// that sets a front matter field along with unknown ones is an error.
+func SplitFrontMatter(content []byte) (fm *FrontMatter, body []byte, err error) {
+	rest, ok := cutFenceLine(content)
+	if !ok {
+		return nil, content, nil
+	}
+
+	for offset := 0; offset < len(rest); {
+		line := rest[offset:]
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		if after, ok := cutFenceLine(line); ok && len(after) == 0 {
+			parsed, err := parseFrontMatter(rest[:offset])
+			if err != nil || parsed == nil {
+				return nil, content, err
+			}
+			return parsed, rest[offset+len(line):], nil
+		}
+		offset += len(line)
+	}
+	return nil, content, nil
+}
+
+// cutFenceLine returns the content after a first line of three dashes.
+func cutFenceLine(content []byte) ([]byte, bool) {
+	for _, fence := range []string{frontMatterFence + "\n", frontMatterFence + "\r\n"} {
+		if after, ok := bytes.CutPrefix(content, []byte(fence)); ok {
+			return after, true
+		}
+	}
+	if string(content) == frontMatterFence {
+		return nil, true
+	}
+	return nil, false
+}
+
+// parseFrontMatter decodes a front matter block. It returns nil for a block
+// that is not a mapping with a front matter field.
+func parseFrontMatter(block []byte) (*FrontMatter, error) {
+	var fields map[string]any
+	if err := yaml.Unmarshal(block, &fields); err != nil {
+		return nil, nil
+	}
+	known := false
+	for _, name := range []string{"dest", "mode", "when", "delimiters"} {
+		if _, ok := fields[name]; ok {
+			known = true
+		}
+	}
+	if !known {
+		return nil, nil
+	}
+
+	var fm FrontMatter
+	dec := yaml.NewDecoder(bytes.NewReader(block))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fm); err != nil {
+		return nil, fmt.Errorf("invalid front matter: %w", err)
+	}
+	if _, err := fm.FileMode(); err != nil {
+		return nil, fmt.Errorf("front matter: %w", err)
+	}
+	if err := validateDelimiters(fm.Delimiters); err != nil {
+		return nil, fmt.Errorf("front matter: %w", err)
+	}
+	return &fm, nil
+}
+
+// readFrontMatter reads the front matter of a template file. Files that are
+// not templates, or are binary, have none.
+func readFrontMatter(fsys fs.FS, srcPath string) (*FrontMatter, error) {
+	if !isTemplateFile(srcPath) {
+		return nil, nil
+	}
+	content, err := fs.ReadFile(fsys, srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", srcPath, err)
+	}
+	if IsBinary(content) {
+		return nil, nil
+	}
+	fm, _, err := SplitFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", srcPath, err)
+	}
+	return fm, nil
+}
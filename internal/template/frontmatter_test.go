@@ -0,0 +1,48 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	fm, body, err := SplitFrontMatter([]byte("---\ndest: cmd/{{ .name }}/main.go\nmode: \"0755\"\n---\npackage main\n"))
+	require.NoError(t, err)
+	require.NotNil(t, fm)
+	assert.Equal(t, "cmd/{{ .name }}/main.go", fm.Dest)
+	assert.Equal(t, "0755", fm.Mode)
+	assert.Equal(t, "package main\n", string(body))
+
+	fm, body, err = SplitFrontMatter([]byte("---\r\nwhen: \"{{ .docker }}\"\r\n---\r\nFROM golang\r\n"))
+	require.NoError(t, err)
+	require.NotNil(t, fm)
+	assert.Equal(t, "{{ .docker }}", fm.When)
+	assert.Equal(t, "FROM golang\r\n", string(body))
+}
+
+func TestSplitFrontMatter_NotFrontMatter(t *testing.T) {
+	for _, content := range []string{
+		"package main\n",
+		"---\napiVersion: v1\nkind: Service\n---\napiVersion: v1\nkind: Pod\n",
+		"---\ndest: main.go\n",
+		"---\n- dest\n---\n",
+	} {
+		fm, body, err := SplitFrontMatter([]byte(content))
+		require.NoError(t, err, content)
+		assert.Nil(t, fm, content)
+		assert.Equal(t, content, string(body))
+	}
+}
+
+func TestSplitFrontMatter_Invalid(t *testing.T) {
+	_, _, err := SplitFrontMatter([]byte("---\ndest: main.go\ndset: main.go\n---\n"))
+	assert.ErrorContains(t, err, "invalid front matter")
+
+	_, _, err = SplitFrontMatter([]byte("---\nmode: rwx\n---\n"))
+	assert.ErrorContains(t, err, `front matter: invalid mode "rwx"`)
+
+	_, _, err = SplitFrontMatter([]byte("---\ndelimiters: [\"[[\"]\n---\n"))
+	assert.ErrorContains(t, err, "front matter: delimiters")
+}
@@ -0,0 +1,86 @@
+package template
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether a slash-separated relative path matches a glob
+// pattern. Each segment of the pattern is matched like path.Match, and a
+// segment of ** matches any number of segments, including none, so that
+// **/*.png matches logo.png as well as assets/img/logo.png.
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// validateGlob checks that a pattern is a relative glob that MatchGlob can
+// match.
+func validateGlob(pattern string) error {
+	clean := path.Clean(pattern)
+	switch {
+	case pattern == "" || clean == ".":
+		return fmt.Errorf("pattern must not be empty")
+	case path.IsAbs(pattern) || clean == ".." || strings.HasPrefix(clean, "../"):
+		return fmt.Errorf("pattern %q must be relative to the source directory", pattern)
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Selects reports whether a path inside the source directory of the file,
+// relative to it, is rendered. A path matching an exclude pattern is skipped,
+// and so is everything below an excluded directory. When include patterns are
+// set, only files matching one of them are rendered; directories are always
+// searched.
+func (f File) Selects(rel string, dir bool) bool {
+	for _, pattern := range f.Exclude {
+		if MatchGlob(pattern, rel) {
+			return false
+		}
+	}
+	if dir || len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if MatchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsUnder is like Selects for a path p inside the source directory
+// root. The root itself is always selected, so that a file src is rendered
+// regardless of the patterns.
+func (f File) selectsUnder(root, p string, dir bool) bool {
+	if p == root {
+		return true
+	}
+	return f.Selects(strings.TrimPrefix(p, root+"/"), dir)
+}
@@ -0,0 +1,41 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.png", "logo.png", true},
+		{"*.png", "img/logo.png", false},
+		{"**/*.png", "logo.png", true},
+		{"**/*.png", "assets/img/logo.png", true},
+		{"**/*.go.tmpl", "cmd/main.go", false},
+		{"assets/**", "assets/img/logo.png", true},
+		{"assets/**", "docs/logo.png", false},
+		{"cmd/*/main.go.tmpl", "cmd/api/main.go.tmpl", true},
+		{"node_modules", "node_modules", true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchGlob(tt.pattern, tt.name), "%s %s", tt.pattern, tt.name)
+	}
+}
+
+func TestFile_Selects(t *testing.T) {
+	file := File{Include: []string{"**/*.go.tmpl"}, Exclude: []string{"testdata"}}
+
+	assert.True(t, file.Selects("main.go.tmpl", false))
+	assert.True(t, file.Selects("cmd/api/main.go.tmpl", false))
+	assert.False(t, file.Selects("README.md", false))
+	assert.True(t, file.Selects("cmd", true))
+	assert.False(t, file.Selects("testdata", true))
+
+	assert.True(t, File{}.Selects("README.md", false))
+}
@@ -0,0 +1,549 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kinds of Go edits, as reported by GoEdit.Kind.
+const (
+	GoEditImport    = "import"
+	GoEditField     = "field"
+	GoEditStatement = "statement"
+)
+
+// Kind returns the kind of change the edit makes, or an empty string when it
+// sets none or more than one.
+func (e GoEdit) Kind() string {
+	var kinds []string
+	if e.Import != "" {
+		kinds = append(kinds, GoEditImport)
+	}
+	if e.Field != "" {
+		kinds = append(kinds, GoEditField)
+	}
+	if e.Statement != "" {
+		kinds = append(kinds, GoEditStatement)
+	}
+	if len(kinds) != 1 {
+		return ""
+	}
+	return kinds[0]
+}
+
+// EditGo applies a Go edit to the source of a Go file and returns the result
+// formatted like gofmt would. Source the edit was already applied to is
+// returned unchanged, which makes applying an edit again a no-op. It returns a
+// *GoTargetNotFoundError when the file has no struct or function the edit
+// names.
+func EditGo(src []byte, edit GoEdit) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse Go source: %w", err)
+	}
+
+	ed := &goSource{fset: fset, file: file, src: src}
+	var edited []byte
+	switch edit.Kind() {
+	case GoEditImport:
+		edited = ed.addImport(edit.ImportName, edit.Import)
+	case GoEditField:
+		edited, err = ed.addField(edit.Struct, edit.Field)
+	case GoEditStatement:
+		edited, err = ed.addStatement(edit.Func, edit.Statement)
+	default:
+		return nil, fmt.Errorf("edit must set exactly one of import, field, and statement")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if edited == nil {
+		return src, nil
+	}
+
+	formatted, err := format.Source(edited)
+	if err != nil {
+		return nil, fmt.Errorf("edited source does not parse: %w", err)
+	}
+	return formatted, nil
+}
+
+// RevertGoEdit undoes a Go edit that EditGo made to the source of a Go file:
+// it removes the import, the field, or the statements the edit added, and
+// returns the result formatted like gofmt would. It reports false when the
+// source does not hold what the edit added.
+func RevertGoEdit(src []byte, edit GoEdit) ([]byte, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot parse Go source: %w", err)
+	}
+
+	ed := &goSource{fset: fset, file: file, src: src}
+	var nodes []ast.Node
+	switch edit.Kind() {
+	case GoEditImport:
+		nodes = ed.findImport(edit.ImportName, edit.Import)
+	case GoEditField:
+		nodes, err = ed.findField(edit.Struct, edit.Field)
+	case GoEditStatement:
+		nodes, err = ed.findStatements(edit.Func, edit.Statement)
+	default:
+		return nil, false, fmt.Errorf("edit must set exactly one of import, field, and statement")
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(nodes) == 0 {
+		return src, false, nil
+	}
+
+	formatted, err := format.Source(ed.remove(nodes))
+	if err != nil {
+		return nil, false, fmt.Errorf("reverted source does not parse: %w", err)
+	}
+	return formatted, true, nil
+}
+
+// editGoFile applies the Go edit of a snippet to content, naming the template
+// and file in its errors.
+func editGoFile(tmpl string, content []byte, snippet RenderedFile) ([]byte, error) {
+	edited, err := EditGo(content, *snippet.GoEdit)
+	if err != nil {
+		var notFound *GoTargetNotFoundError
+		if errors.As(err, &notFound) {
+			notFound.Template, notFound.Path = tmpl, snippet.Path
+			return nil, notFound
+		}
+		return nil, fmt.Errorf("%s: cannot edit %s: %w", tmpl, snippet.Path, err)
+	}
+	return edited, nil
+}
+
+// goSource is a parsed Go file that edits are made to as text at the
+// positions of its nodes, so that comments stay where they are.
+type goSource struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+}
+
+func (s *goSource) offset(pos token.Pos) int {
+	return s.fset.Position(pos).Offset
+}
+
+// insert returns the source with text inserted at offset.
+func (s *goSource) insert(offset int, text string) []byte {
+	out := make([]byte, 0, len(s.src)+len(text))
+	out = append(out, s.src[:offset]...)
+	out = append(out, text...)
+	return append(out, s.src[offset:]...)
+}
+
+// insertLines returns the source with text inserted on lines of its own
+// before offset.
+func (s *goSource) insertLines(offset int, text string) []byte {
+	lineStart := bytes.LastIndexByte(s.src[:offset], '\n') + 1
+	if strings.TrimSpace(string(s.src[lineStart:offset])) == "" {
+		return s.insert(lineStart, text+"\n")
+	}
+	return s.insert(offset, "\n"+text+"\n")
+}
+
+// remove returns the source without the nodes. A node on lines of its own is
+// removed with its lines, including a comment that follows it.
+func (s *goSource) remove(nodes []ast.Node) []byte {
+	type span struct{ start, end int }
+	spans := make([]span, 0, len(nodes))
+	for _, node := range nodes {
+		start, end := s.offset(node.Pos()), s.offset(node.End())
+
+		lineStart := bytes.LastIndexByte(s.src[:start], '\n') + 1
+		lineEnd := len(s.src)
+		if i := bytes.IndexByte(s.src[end:], '\n'); i >= 0 {
+			lineEnd = end + i + 1
+		}
+		rest := strings.TrimSpace(string(s.src[end:lineEnd]))
+		if strings.TrimSpace(string(s.src[lineStart:start])) == "" && (rest == "" || strings.HasPrefix(rest, "//")) {
+			start, end = lineStart, lineEnd
+		}
+		spans = append(spans, span{start, end})
+	}
+	slices.SortFunc(spans, func(a, b span) int { return b.start - a.start })
+
+	out := slices.Clone(s.src)
+	for _, sp := range spans {
+		out = slices.Delete(out, sp.start, sp.end)
+	}
+	return out
+}
+
+// addImport adds an import to the first import declaration of the file, or
+// declares one after the package clause. It returns nil when the file
+// already imports the path under the name.
+func (s *goSource) addImport(name, importPath string) []byte {
+	for _, spec := range s.file.Imports {
+		existing, _ := strconv.Unquote(spec.Path.Value)
+		specName := ""
+		if spec.Name != nil {
+			specName = spec.Name.Name
+		}
+		if existing == importPath && specName == name {
+			return nil
+		}
+	}
+
+	line := strconv.Quote(importPath)
+	if name != "" {
+		line = name + " " + line
+	}
+
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			// The import joins the last import of its kind, standard library
+			// or not, so that it is sorted into the right group.
+			for i := len(gen.Specs) - 1; i >= 0; i-- {
+				spec := gen.Specs[i].(*ast.ImportSpec)
+				specPath, _ := strconv.Unquote(spec.Path.Value)
+				if isStdImport(specPath) == isStdImport(importPath) {
+					end := s.offset(spec.End())
+					if eol := bytes.IndexByte(s.src[end:], '\n'); eol >= 0 {
+						return s.insert(end+eol, "\n"+line)
+					}
+				}
+			}
+			return s.insertLines(s.offset(gen.Rparen), line)
+		}
+
+		// A single import becomes a block holding both.
+		spec := gen.Specs[0]
+		existing := string(s.src[s.offset(spec.Pos()):s.offset(spec.End())])
+		start, end := s.offset(gen.Pos()), s.offset(gen.End())
+		out := make([]byte, 0, len(s.src)+len(line)+16)
+		out = append(out, s.src[:start]...)
+		out = append(out, "import (\n"+existing+"\n"+line+"\n)"...)
+		return append(out, s.src[end:]...)
+	}
+
+	return s.insert(s.offset(s.file.Name.End()), "\n\nimport "+line)
+}
+
+// findImport returns the import of the path under the name, or its whole
+// declaration when it is the only import the declaration holds.
+func (s *goSource) findImport(name, importPath string) []ast.Node {
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*ast.ImportSpec)
+			specPath, _ := strconv.Unquote(spec.Path.Value)
+			specName := ""
+			if spec.Name != nil {
+				specName = spec.Name.Name
+			}
+			if specPath != importPath || specName != name {
+				continue
+			}
+			if len(gen.Specs) == 1 {
+				return []ast.Node{gen}
+			}
+			return []ast.Node{spec}
+		}
+	}
+	return nil
+}
+
+// isStdImport reports whether an import path belongs to the standard
+// library, whose paths have no dot in their first element.
+func isStdImport(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// addField adds a field declaration to the end of a struct type. It returns
+// nil when the struct already has the field with the same type, and an
+// error when it has a field of the name with another type.
+func (s *goSource) addField(structName, field string) ([]byte, error) {
+	st := s.findStruct(structName)
+	if st == nil {
+		return nil, &GoTargetNotFoundError{Kind: "struct", Name: structName}
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", "package p\ntype _ struct {\n"+field+"\n}\n", 0)
+	if err != nil {
+		return nil, fmt.Errorf("field %q is not a Go field declaration", field)
+	}
+	fields := parsed.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("field %q must declare one field", field)
+	}
+	added := fields[0]
+	addedType := nodeString(fset, added.Type)
+
+	for _, existing := range st.Fields.List {
+		for _, name := range fieldNames(existing) {
+			if !slices.Contains(fieldNames(added), name) {
+				continue
+			}
+			if nodeString(s.fset, existing.Type) != addedType {
+				return nil, fmt.Errorf("struct %s already has a field %s of another type", structName, name)
+			}
+			return nil, nil
+		}
+	}
+
+	return s.insertLines(s.offset(st.Fields.Closing), field), nil
+}
+
+// findField returns the field of a struct type that declares the same names
+// with the same type as the field declaration.
+func (s *goSource) findField(structName, field string) ([]ast.Node, error) {
+	st := s.findStruct(structName)
+	if st == nil {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", "package p\ntype _ struct {\n"+field+"\n}\n", 0)
+	if err != nil {
+		return nil, fmt.Errorf("field %q is not a Go field declaration", field)
+	}
+	fields := parsed.Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("field %q must declare one field", field)
+	}
+	added := fields[0]
+
+	for _, existing := range st.Fields.List {
+		if slices.Equal(fieldNames(existing), fieldNames(added)) &&
+			nodeString(s.fset, existing.Type) == nodeString(fset, added.Type) {
+			return []ast.Node{existing}, nil
+		}
+	}
+	return nil, nil
+}
+
+// findStruct returns the struct type declared under name.
+func (s *goSource) findStruct(name string) *ast.StructType {
+	for _, decl := range s.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok && ts.Name.Name == name {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// addStatement adds statements to the end of the body of a function, before
+// its final return statement if it has one. It returns nil when the body
+// already holds every statement.
+func (s *goSource) addStatement(funcName, statement string) ([]byte, error) {
+	fn := s.findFunc(funcName)
+	if fn == nil || fn.Body == nil {
+		return nil, &GoTargetNotFoundError{Kind: "func", Name: funcName}
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", "package p\nfunc _() {\n"+statement+"\n}\n", 0)
+	if err != nil {
+		return nil, fmt.Errorf("statement %q is not a Go statement", statement)
+	}
+	stmts := parsed.Decls[0].(*ast.FuncDecl).Body.List
+	if len(stmts) == 0 {
+		return nil, fmt.Errorf("statement %q is empty", statement)
+	}
+
+	existing := make(map[string]bool, len(fn.Body.List))
+	for _, stmt := range fn.Body.List {
+		existing[nodeString(s.fset, stmt)] = true
+	}
+	applied := true
+	for _, stmt := range stmts {
+		if !existing[nodeString(fset, stmt)] {
+			applied = false
+			break
+		}
+	}
+	if applied {
+		return nil, nil
+	}
+
+	at := fn.Body.Rbrace
+	if n := len(fn.Body.List); n > 0 {
+		if ret, ok := fn.Body.List[n-1].(*ast.ReturnStmt); ok {
+			at = ret.Pos()
+		}
+	}
+	return s.insertLines(s.offset(at), statement), nil
+}
+
+// findStatements returns the statements of the body of a function that
+// match the given statements, or nil unless it holds every one of them.
+func (s *goSource) findStatements(funcName, statement string) ([]ast.Node, error) {
+	fn := s.findFunc(funcName)
+	if fn == nil || fn.Body == nil {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, "", "package p\nfunc _() {\n"+statement+"\n}\n", 0)
+	if err != nil {
+		return nil, fmt.Errorf("statement %q is not a Go statement", statement)
+	}
+
+	var found []ast.Node
+	used := make(map[ast.Stmt]bool)
+	for _, stmt := range parsed.Decls[0].(*ast.FuncDecl).Body.List {
+		want := nodeString(fset, stmt)
+		i := slices.IndexFunc(fn.Body.List, func(existing ast.Stmt) bool {
+			return !used[existing] && nodeString(s.fset, existing) == want
+		})
+		if i < 0 {
+			return nil, nil
+		}
+		used[fn.Body.List[i]] = true
+		found = append(found, fn.Body.List[i])
+	}
+	return found, nil
+}
+
+// findFunc returns the function declared under name, or the method when name
+// is Type.Method.
+func (s *goSource) findFunc(name string) *ast.FuncDecl {
+	recv, method, isMethod := strings.Cut(name, ".")
+	if !isMethod {
+		method = name
+	}
+
+	for _, decl := range s.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != method {
+			continue
+		}
+		if isMethod != (fn.Recv != nil) {
+			continue
+		}
+		if isMethod && receiverType(fn) != recv {
+			continue
+		}
+		return fn
+	}
+	return nil
+}
+
+// receiverType returns the name of the type a method is declared on.
+func receiverType(fn *ast.FuncDecl) string {
+	if len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// fieldNames returns the names a field declares; an embedded field is named
+// after its type.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+
+	expr := field.Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.SelectorExpr:
+			return []string{t.Sel.Name}
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return []string{t.Name}
+		default:
+			return nil
+		}
+	}
+}
+
+// nodeString prints a node the way gofmt does, without comments.
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// validateGoEdit checks that a Go edit names a Go file and makes exactly
+// one change with the fields that change needs.
+func validateGoEdit(edit GoEdit) []error {
+	var errs []error
+	if edit.File != "" && (path.Ext(edit.File) != ".go" || path.IsAbs(edit.File)) {
+		errs = append(errs, fmt.Errorf("file %q must be a relative path to a .go file", edit.File))
+	}
+
+	switch edit.Kind() {
+	case "":
+		return append(errs, fmt.Errorf("exactly one of import, field, and statement must be set"))
+	case GoEditField:
+		if edit.Struct == "" {
+			errs = append(errs, fmt.Errorf("struct is required with field"))
+		}
+	case GoEditStatement:
+		if edit.Func == "" {
+			errs = append(errs, fmt.Errorf("func is required with statement"))
+		}
+	}
+
+	if edit.ImportName != "" && edit.Import == "" {
+		errs = append(errs, fmt.Errorf("import_name is only used with import"))
+	}
+	if edit.Struct != "" && edit.Field == "" {
+		errs = append(errs, fmt.Errorf("struct is only used with field"))
+	}
+	if edit.Func != "" && edit.Statement == "" {
+		errs = append(errs, fmt.Errorf("func is only used with statement"))
+	}
+	return errs
+}
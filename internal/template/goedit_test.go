@@ -0,0 +1,218 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serverGo = `package server
+
+import "net/http"
+
+// Server serves the API.
+type Server struct {
+	mux *http.ServeMux // Routes of the server
+}
+
+func (s *Server) routes() http.Handler {
+	s.mux.HandleFunc("/health", health)
+	return s.mux
+}
+
+func run() {}
+`
+
+func TestEditGo(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     GoEdit
+		want     string
+		reverted string // Source after reverting the edit; serverGo when empty
+	}{
+		{
+			name: "import",
+			edit: GoEdit{Import: "log/slog"},
+			want: `package server
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// Server serves the API.
+type Server struct {
+	mux *http.ServeMux // Routes of the server
+}
+
+func (s *Server) routes() http.Handler {
+	s.mux.HandleFunc("/health", health)
+	return s.mux
+}
+
+func run() {}
+`,
+			reverted: strings.Replace(serverGo, `import "net/http"`, "import (\n\t\"net/http\"\n)", 1),
+		},
+		{
+			name: "field",
+			edit: GoEdit{Struct: "Server", Field: "logger  *slog.Logger"},
+			want: `package server
+
+import "net/http"
+
+// Server serves the API.
+type Server struct {
+	mux    *http.ServeMux // Routes of the server
+	logger *slog.Logger
+}
+
+func (s *Server) routes() http.Handler {
+	s.mux.HandleFunc("/health", health)
+	return s.mux
+}
+
+func run() {}
+`,
+		},
+		{
+			name: "statement before return",
+			edit: GoEdit{Func: "Server.routes", Statement: `s.mux.HandleFunc("/users",users)`},
+			want: `package server
+
+import "net/http"
+
+// Server serves the API.
+type Server struct {
+	mux *http.ServeMux // Routes of the server
+}
+
+func (s *Server) routes() http.Handler {
+	s.mux.HandleFunc("/health", health)
+	s.mux.HandleFunc("/users", users)
+	return s.mux
+}
+
+func run() {}
+`,
+		},
+		{
+			name: "statement in empty body",
+			edit: GoEdit{Func: "run", Statement: "start()\nwait()"},
+			want: `package server
+
+import "net/http"
+
+// Server serves the API.
+type Server struct {
+	mux *http.ServeMux // Routes of the server
+}
+
+func (s *Server) routes() http.Handler {
+	s.mux.HandleFunc("/health", health)
+	return s.mux
+}
+
+func run() {
+	start()
+	wait()
+}
+`,
+			reverted: strings.Replace(serverGo, "func run() {}", "func run() {\n}", 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edited, err := EditGo([]byte(serverGo), tt.edit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(edited))
+
+			again, err := EditGo(edited, tt.edit)
+			require.NoError(t, err)
+			assert.Equal(t, string(edited), string(again), "applying an edit again is a no-op")
+
+			reverted, ok, err := RevertGoEdit(edited, tt.edit)
+			require.NoError(t, err)
+			require.True(t, ok)
+			want := tt.reverted
+			if want == "" {
+				want = serverGo
+			}
+			assert.Equal(t, want, string(reverted))
+
+			_, ok, err = RevertGoEdit(reverted, tt.edit)
+			require.NoError(t, err)
+			assert.False(t, ok, "an edit that was not made cannot be reverted")
+		})
+	}
+}
+
+func TestEditGo_Imports(t *testing.T) {
+	edited, err := EditGo([]byte("package main\n\nfunc main() {}\n"), GoEdit{Import: "embed", ImportName: "_"})
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nimport _ \"embed\"\n\nfunc main() {}\n", string(edited))
+
+	edited, err = EditGo([]byte("package main\n\nimport (\n\t\"fmt\"\n\t// Router\n\t\"github.com/go-chi/chi/v5\"\n)\n"),
+		GoEdit{Import: "os"})
+	require.NoError(t, err, "a standard library import joins the standard library imports")
+	assert.Equal(t, "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t// Router\n\t\"github.com/go-chi/chi/v5\"\n)\n",
+		string(edited))
+}
+
+func TestRevertGoEdit_KeepsOtherCode(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"os" // Exit codes
+)
+
+func main() {
+	fmt.Println("hi")
+	os.Exit(0)
+}
+`
+	reverted, ok, err := RevertGoEdit([]byte(src), GoEdit{Import: "os"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println(\"hi\")\n\tos.Exit(0)\n}\n",
+		string(reverted))
+
+	reverted, ok, err = RevertGoEdit(reverted, GoEdit{Func: "main", Statement: "os.Exit(0)"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n", string(reverted))
+}
+
+func TestEditGo_Errors(t *testing.T) {
+	var notFound *GoTargetNotFoundError
+	_, err := EditGo([]byte(serverGo), GoEdit{Struct: "Config", Field: "Port int"})
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "struct", notFound.Kind)
+	assert.Equal(t, "Config", notFound.Name)
+
+	_, err = EditGo([]byte(serverGo), GoEdit{Func: "routes", Statement: "x()"})
+	require.ErrorAs(t, err, &notFound, "a method is named with its type")
+
+	_, err = EditGo([]byte(serverGo), GoEdit{Struct: "Server", Field: "mux http.Handler"})
+	assert.ErrorContains(t, err, "already has a field mux of another type")
+
+	_, err = EditGo([]byte(serverGo), GoEdit{Func: "run", Statement: "if {"})
+	assert.ErrorContains(t, err, "is not a Go statement")
+
+	_, err = EditGo([]byte("package"), GoEdit{Import: "os"})
+	assert.ErrorContains(t, err, "cannot parse Go source")
+}
+
+func TestInjectFile_GoEdit(t *testing.T) {
+	snippet := RenderedFile{Path: "server.go", GoEdit: &GoEdit{Struct: "Config", Field: "Port int"}}
+	_, err := InjectFile("metrics", []byte(serverGo), snippet)
+
+	var notFound *GoTargetNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "metrics", notFound.Template)
+	assert.Equal(t, "server.go", notFound.Path)
+}
@@ -0,0 +1,147 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GoVersionVariable is the variable that holds the Go version selected for
+// templates that declare supported Go versions, for use in the go directive
+// of go.mod. A value given by the user is kept.
+const GoVersionVariable = "go_version"
+
+// GoRequirement declares the Go versions the output of a template supports.
+type GoRequirement struct {
+	Min string `yaml:"min,omitempty"` // Oldest supported version, e.g. 1.22
+	Max string `yaml:"max,omitempty"` // Newest supported version; every patch release of a minor version given as 1.24 is supported
+}
+
+// GoVersion is a Go release version. Patch is -1 when the version names a
+// minor release line, such as 1.24.
+type GoVersion struct {
+	Major, Minor, Patch int
+}
+
+// ParseGoVersion parses a Go version such as 1.22, 1.22.5, or go1.23rc1, as
+// printed by go env GOVERSION. Pre-release suffixes are ignored.
+func ParseGoVersion(s string) (GoVersion, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "go")
+	if i := strings.IndexFunc(trimmed, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return GoVersion{}, fmt.Errorf("invalid Go version %q: expected major.minor[.patch]", s)
+	}
+
+	nums := []int{0, 0, -1}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return GoVersion{}, fmt.Errorf("invalid Go version %q: expected major.minor[.patch]", s)
+		}
+		nums[i] = n
+	}
+
+	return GoVersion{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+func (v GoVersion) String() string {
+	if v.Patch < 0 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// compare compares two versions. A minor release line compares equal to
+// each of its patch releases.
+func (v GoVersion) compare(o GoVersion) int {
+	switch {
+	case v.Major != o.Major:
+		return v.Major - o.Major
+	case v.Minor != o.Minor:
+		return v.Minor - o.Minor
+	case v.Patch < 0 || o.Patch < 0:
+		return 0
+	default:
+		return v.Patch - o.Patch
+	}
+}
+
+// Validate checks that the bounds are valid versions and in order.
+func (r GoRequirement) Validate() error {
+	if r.Min == "" && r.Max == "" {
+		return fmt.Errorf("at least one of min and max is required")
+	}
+
+	minV, maxV, err := r.bounds()
+	if err != nil {
+		return err
+	}
+	if minV != nil && maxV != nil && minV.compare(*maxV) > 0 {
+		return fmt.Errorf("min %s is newer than max %s", r.Min, r.Max)
+	}
+	return nil
+}
+
+func (r GoRequirement) bounds() (minV, maxV *GoVersion, err error) {
+	if r.Min != "" {
+		v, err := ParseGoVersion(r.Min)
+		if err != nil {
+			return nil, nil, fmt.Errorf("min: %w", err)
+		}
+		minV = &v
+	}
+	if r.Max != "" {
+		v, err := ParseGoVersion(r.Max)
+		if err != nil {
+			return nil, nil, fmt.Errorf("max: %w", err)
+		}
+		maxV = &v
+	}
+	return minV, maxV, nil
+}
+
+// Check returns a message describing why v is not supported, or an empty
+// string when it is.
+func (r GoRequirement) Check(v GoVersion) string {
+	minV, maxV, err := r.bounds()
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case minV != nil && v.compare(*minV) < 0:
+		return fmt.Sprintf("installed Go %s is older than %s, the oldest version the template supports", v, minV)
+	case maxV != nil && v.compare(*maxV) > 0:
+		return fmt.Sprintf("installed Go %s is newer than %s, the newest version the template supports", v, maxV)
+	}
+	return ""
+}
+
+// Select returns the version to write to the go directive: the installed
+// version when it is supported, otherwise the closest supported bound. With
+// no installed toolchain, the oldest supported version is used, or the newest
+// when there is no lower bound.
+func (r GoRequirement) Select(installed *GoVersion) string {
+	minV, maxV, err := r.bounds()
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case installed == nil && minV != nil:
+		return minV.String()
+	case installed == nil && maxV != nil:
+		return maxV.String()
+	case installed == nil:
+		return ""
+	case minV != nil && installed.compare(*minV) < 0:
+		return minV.String()
+	case maxV != nil && installed.compare(*maxV) > 0:
+		return maxV.String()
+	}
+	return installed.String()
+}
@@ -0,0 +1,62 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGoVersion(t *testing.T) {
+	tests := map[string]GoVersion{
+		"1.22":      {1, 22, -1},
+		"1.22.5":    {1, 22, 5},
+		"go1.23.1":  {1, 23, 1},
+		"go1.24rc1": {1, 24, -1},
+		" go1.21 ":  {1, 21, -1},
+	}
+	for input, want := range tests {
+		got, err := ParseGoVersion(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "1", "go", "1.x", "1.2.3.4", "devel"} {
+		_, err := ParseGoVersion(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestGoRequirementCheck(t *testing.T) {
+	r := GoRequirement{Min: "1.22", Max: "1.24"}
+
+	for _, v := range []GoVersion{{1, 22, 0}, {1, 23, 4}, {1, 24, 9}} {
+		assert.Empty(t, r.Check(v), v.String())
+	}
+	assert.Contains(t, r.Check(GoVersion{1, 21, 13}), "older than 1.22")
+	assert.Contains(t, r.Check(GoVersion{1, 25, 0}), "newer than 1.24")
+}
+
+func TestGoRequirementSelect(t *testing.T) {
+	r := GoRequirement{Min: "1.22", Max: "1.24"}
+
+	installed := func(s string) *GoVersion {
+		v, err := ParseGoVersion(s)
+		require.NoError(t, err)
+		return &v
+	}
+
+	assert.Equal(t, "1.23.4", r.Select(installed("go1.23.4")))
+	assert.Equal(t, "1.22", r.Select(installed("go1.21.0")))
+	assert.Equal(t, "1.24", r.Select(installed("go1.26.1")))
+	assert.Equal(t, "1.22", r.Select(nil))
+	assert.Equal(t, "1.24", GoRequirement{Max: "1.24"}.Select(nil))
+}
+
+func TestGoRequirementValidate(t *testing.T) {
+	assert.NoError(t, GoRequirement{Min: "1.22"}.Validate())
+	assert.NoError(t, GoRequirement{Min: "1.22", Max: "1.22"}.Validate())
+	assert.ErrorContains(t, GoRequirement{}.Validate(), "at least one of min and max")
+	assert.ErrorContains(t, GoRequirement{Min: "1.24", Max: "1.22"}.Validate(), "newer than max")
+	assert.ErrorContains(t, GoRequirement{Max: "latest"}.Validate(), "max: invalid Go version")
+}
@@ -0,0 +1,135 @@
+package template
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// commentStyle describes how to turn text into a comment for a file type.
+type commentStyle struct {
+	prefix string // Prefix for each line
+	open   string // Opening line of a block comment
+	close  string // Closing line of a block comment
+}
+
+var (
+	slashComment = commentStyle{prefix: "// "}
+	hashComment  = commentStyle{prefix: "# "}
+	dashComment  = commentStyle{prefix: "-- "}
+	blockComment = commentStyle{open: "/*", prefix: " * ", close: " */"}
+	xmlComment   = commentStyle{open: "<!--", prefix: "  ", close: "-->"}
+)
+
+// commentStyles maps file extensions to their comment syntax.
+var commentStyles = map[string]commentStyle{
+	".go":    slashComment,
+	".js":    slashComment,
+	".jsx":   slashComment,
+	".ts":    slashComment,
+	".tsx":   slashComment,
+	".java":  slashComment,
+	".kt":    slashComment,
+	".scala": slashComment,
+	".c":     slashComment,
+	".h":     slashComment,
+	".cc":    slashComment,
+	".cpp":   slashComment,
+	".hpp":   slashComment,
+	".cs":    slashComment,
+	".rs":    slashComment,
+	".swift": slashComment,
+	".dart":  slashComment,
+	".proto": slashComment,
+	".py":    hashComment,
+	".rb":    hashComment,
+	".sh":    hashComment,
+	".bash":  hashComment,
+	".zsh":   hashComment,
+	".yaml":  hashComment,
+	".yml":   hashComment,
+	".toml":  hashComment,
+	".tf":    hashComment,
+	".sql":   dashComment,
+	".lua":   dashComment,
+	".hs":    dashComment,
+	".css":   blockComment,
+	".scss":  blockComment,
+	".html":  xmlComment,
+	".xml":   xmlComment,
+	".vue":   xmlComment,
+}
+
+// CommentHeader formats text as a comment block for the file at filePath.
+// It reports false when the file type is unknown.
+func CommentHeader(filePath, text string) (string, bool) {
+	style, ok := commentStyles[strings.ToLower(path.Ext(filePath))]
+	if !ok {
+		return "", false
+	}
+
+	var b strings.Builder
+	if style.open != "" {
+		b.WriteString(style.open + "\n")
+	}
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString(strings.TrimRight(style.prefix+line, " ") + "\n")
+	}
+	if style.close != "" {
+		b.WriteString(style.close + "\n")
+	}
+
+	return b.String(), true
+}
+
+// PrependHeader inserts a comment header into content for the file at filePath.
+// A leading shebang line is kept first. Content that already starts with the
+// header, and files of unknown types, are returned unchanged.
+func PrependHeader(filePath string, content []byte, text string) []byte {
+	header, ok := CommentHeader(filePath, text)
+	if !ok {
+		return content
+	}
+
+	var shebang []byte
+	body := content
+	if bytes.HasPrefix(content, []byte("#!")) {
+		if i := bytes.IndexByte(content, '\n'); i >= 0 {
+			shebang, body = content[:i+1], content[i+1:]
+		} else {
+			shebang, body = append(content, '\n'), nil
+		}
+	}
+
+	if bytes.HasPrefix(body, []byte(header)) {
+		return content
+	}
+
+	out := make([]byte, 0, len(content)+len(header)+1)
+	out = append(out, shebang...)
+	out = append(out, header...)
+	out = append(out, '\n')
+	out = append(out, body...)
+	return out
+}
+
+// ApplyHeader prepends the header text to every rendered file of a known type.
+// Binary files are left untouched.
+func (r *RenderResult) ApplyHeader(text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	for id, files := range r.Files {
+		for i := range files {
+			filePath := files[i].Path
+			files[i].transform(func(content []byte) []byte {
+				if IsBinary(content) {
+					return content
+				}
+				return PrependHeader(filePath, content, text)
+			})
+		}
+		r.Files[id] = files
+	}
+}
@@ -0,0 +1,37 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrependHeader(t *testing.T) {
+	text := "Copyright 2026 Acme\nSPDX-License-Identifier: MIT"
+
+	t.Run("go file uses line comments", func(t *testing.T) {
+		out := PrependHeader("main.go", []byte("package main\n"), text)
+		assert.Equal(t, "// Copyright 2026 Acme\n// SPDX-License-Identifier: MIT\n\npackage main\n", string(out))
+	})
+
+	t.Run("shebang stays first", func(t *testing.T) {
+		out := PrependHeader("run.sh", []byte("#!/bin/sh\necho hi\n"), text)
+		assert.Equal(t, "#!/bin/sh\n# Copyright 2026 Acme\n# SPDX-License-Identifier: MIT\n\necho hi\n", string(out))
+	})
+
+	t.Run("css uses block comments", func(t *testing.T) {
+		out := PrependHeader("style.css", []byte("body {}\n"), "Acme")
+		assert.Equal(t, "/*\n * Acme\n */\n\nbody {}\n", string(out))
+	})
+
+	t.Run("unknown types are unchanged", func(t *testing.T) {
+		out := PrependHeader("README.md", []byte("# Title\n"), text)
+		assert.Equal(t, "# Title\n", string(out))
+	})
+
+	t.Run("existing header is not duplicated", func(t *testing.T) {
+		once := PrependHeader("main.go", []byte("package main\n"), text)
+		twice := PrependHeader("main.go", once, text)
+		assert.Equal(t, string(once), string(twice))
+	})
+}
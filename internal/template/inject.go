@@ -0,0 +1,253 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AnchorPrefix prefixes the name of an anchor in the comment that marks it,
+// such as // blueprint:routes or # blueprint:routes.
+const AnchorPrefix = "blueprint:"
+
+// anchorPattern matches the marker of the named anchor. The name must not
+// continue past the match, so that routes does not match routes-admin.
+func anchorPattern(anchor string) *regexp.Regexp {
+	return regexp.MustCompile(regexp.QuoteMeta(AnchorPrefix+anchor) + `(?:[^\w-]|$)`)
+}
+
+// Inject inserts snippet into content on the lines before the line that holds
+// the anchor, indented like it, so that snippets injected at the same anchor
+// keep the order they were injected in. Content that already holds the
+// snippet is returned unchanged, which makes injecting again a no-op. It
+// reports false when content has no such anchor.
+func Inject(content []byte, anchor string, snippet []byte) ([]byte, bool) {
+	pattern := anchorPattern(anchor)
+
+	offset := 0
+	for offset <= len(content) {
+		end := bytes.IndexByte(content[offset:], '\n')
+		if end < 0 {
+			end = len(content)
+		} else {
+			end += offset
+		}
+		line := content[offset:end]
+
+		if pattern.Match(line) {
+			indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+			eol := "\n"
+			if bytes.HasSuffix(line, []byte("\r")) {
+				eol = "\r\n"
+			}
+
+			block := indentSnippet(snippet, string(indent), eol)
+			if len(block) == 0 || bytes.Contains(content, block) {
+				return content, true
+			}
+
+			out := make([]byte, 0, len(content)+len(block))
+			out = append(out, content[:offset]...)
+			out = append(out, block...)
+			out = append(out, content[offset:]...)
+			return out, true
+		}
+
+		offset = end + 1
+	}
+
+	return content, false
+}
+
+// indentSnippet prefixes every non-empty line of snippet with indent and ends
+// every line with eol.
+func indentSnippet(snippet []byte, indent, eol string) []byte {
+	text := strings.TrimRight(strings.ReplaceAll(string(snippet), "\r\n", "\n"), "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			b.WriteString(indent)
+			b.WriteString(line)
+		}
+		b.WriteString(eol)
+	}
+	return []byte(b.String())
+}
+
+// InjectFile injects the content of the snippet file into content, or makes
+// the Go edit of the snippet to it. It returns an *AnchorNotFoundError when
+// content has no anchor for the snippet.
+func InjectFile(tmpl string, content []byte, snippet RenderedFile) ([]byte, error) {
+	if snippet.GoEdit != nil {
+		return editGoFile(tmpl, content, snippet)
+	}
+
+	text, err := snippet.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	injected, ok := Inject(content, snippet.Anchor, text)
+	if !ok {
+		return nil, &AnchorNotFoundError{Template: tmpl, Path: snippet.Path, Anchor: snippet.Anchor}
+	}
+	return injected, nil
+}
+
+// Uninject removes a snippet that Inject inserted at an anchor from content.
+// It reports false when content has no such anchor or does not hold the
+// snippet, for example because it was changed since.
+func Uninject(content []byte, anchor string, snippet []byte) ([]byte, bool) {
+	pattern := anchorPattern(anchor)
+
+	for offset := 0; offset <= len(content); {
+		end := bytes.IndexByte(content[offset:], '\n')
+		if end < 0 {
+			end = len(content)
+		} else {
+			end += offset
+		}
+		line := content[offset:end]
+
+		if pattern.Match(line) {
+			indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+			eol := "\n"
+			if bytes.HasSuffix(line, []byte("\r")) {
+				eol = "\r\n"
+			}
+
+			block := indentSnippet(snippet, string(indent), eol)
+			if len(block) == 0 {
+				return content, true
+			}
+			// The snippet starts on a line of its own before the anchor.
+			for from := 0; from < offset; {
+				i := bytes.Index(content[from:offset], block)
+				if i < 0 {
+					break
+				}
+				i += from
+				if i == 0 || content[i-1] == '\n' {
+					out := make([]byte, 0, len(content)-len(block))
+					out = append(out, content[:i]...)
+					return append(out, content[i+len(block):]...), true
+				}
+				from = i + 1
+			}
+			return content, false
+		}
+
+		offset = end + 1
+	}
+
+	return content, false
+}
+
+// UninjectFile removes the content of a snippet file that InjectFile
+// injected from content, or reverts its Go edit. It reports false when
+// content does not hold the snippet.
+func UninjectFile(content []byte, snippet RenderedFile) ([]byte, bool, error) {
+	if snippet.GoEdit != nil {
+		return RevertGoEdit(content, *snippet.GoEdit)
+	}
+
+	text, err := snippet.Load()
+	if err != nil {
+		return nil, false, err
+	}
+	reverted, ok := Uninject(content, snippet.Anchor, text)
+	return reverted, ok, nil
+}
+
+// ApplyInjections injects the snippets of the tree into the files the same
+// run renders to their paths, in composition order. dirs maps node IDs to the
+// directory, relative to the output root, that the node's files are written
+// to. Snippets for files the run does not render stay in r.Injections, to be
+// injected into the files on disk when they are written.
+//
+// Snippets are only injected into files inside the output root; others are
+// returned together as an *OutsideOutputError.
+func (r *RenderResult) ApplyInjections(tree *TemplateNode, dirs map[string]string) error {
+	if len(r.Injections) == 0 {
+		return nil
+	}
+
+	type fileRef struct {
+		node  string
+		index int
+	}
+	rendered := make(map[string]fileRef)
+	var collect func(node *TemplateNode)
+	collect = func(node *TemplateNode) {
+		for i, file := range r.Files[node.ID] {
+			rendered[OutputPath(dirs[node.ID], file.Path)] = fileRef{node: node.ID, index: i}
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(tree)
+
+	var denied []OutsideFile
+	var walk func(node *TemplateNode)
+	walk = func(node *TemplateNode) {
+		var pending []RenderedFile
+		for _, snippet := range r.Injections[node.ID] {
+			outPath, outside := resolveOutputPath(dirs[node.ID], snippet.Path)
+			if outside {
+				denied = append(denied, OutsideFile{Template: node.Template.Name, Path: outPath})
+				continue
+			}
+
+			ref, ok := rendered[outPath]
+			if !ok {
+				pending = append(pending, snippet)
+				continue
+			}
+
+			file := &r.Files[ref.node][ref.index]
+			load := file.Load
+			tmpl := node.Template.Name
+			file.Content, file.load = nil, func() ([]byte, error) {
+				content, err := load()
+				if err != nil {
+					return nil, err
+				}
+				return InjectFile(tmpl, content, snippet)
+			}
+			file.Injected = append(file.Injected, Injection{Node: node.ID, Snippet: snippet})
+		}
+
+		if len(pending) > 0 {
+			r.Injections[node.ID] = pending
+		} else {
+			delete(r.Injections, node.ID)
+		}
+
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(tree)
+
+	if len(denied) > 0 {
+		return &OutsideOutputError{Files: denied}
+	}
+	return nil
+}
+
+// validateAnchor checks the name of an anchor.
+func validateAnchor(anchor string) error {
+	if anchor == "" {
+		return fmt.Errorf("anchor is required for action inject")
+	}
+	if strings.ContainsAny(anchor, " \t\r\n") {
+		return fmt.Errorf("anchor %q must not contain whitespace", anchor)
+	}
+	return nil
+}
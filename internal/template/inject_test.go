@@ -0,0 +1,114 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mainGo = `package main
+
+func main() {
+	r := chi.NewRouter()
+	// blueprint:routes
+	// blueprint:routes-admin
+	serve(r)
+}
+`
+
+func TestInject(t *testing.T) {
+	content, ok := Inject([]byte(mainGo), "routes", []byte("r.Get(\"/users\", users)\n"))
+	require.True(t, ok)
+
+	content, ok = Inject(content, "routes", []byte("r.Get(\"/orders\", orders)\n"))
+	require.True(t, ok)
+	assert.Equal(t, `package main
+
+func main() {
+	r := chi.NewRouter()
+	r.Get("/users", users)
+	r.Get("/orders", orders)
+	// blueprint:routes
+	// blueprint:routes-admin
+	serve(r)
+}
+`, string(content))
+
+	again, ok := Inject(content, "routes", []byte("r.Get(\"/users\", users)"))
+	require.True(t, ok)
+	assert.Equal(t, string(content), string(again), "injecting the same snippet again is a no-op")
+
+	_, ok = Inject([]byte(mainGo), "imports", []byte("x"))
+	assert.False(t, ok)
+	_, ok = Inject([]byte(mainGo), "route", []byte("x"))
+	assert.False(t, ok, "an anchor name must match whole")
+}
+
+func TestUninject(t *testing.T) {
+	content, _ := Inject([]byte(mainGo), "routes", []byte("r.Get(\"/users\", users)\n"))
+	content, _ = Inject(content, "routes", []byte("r.Get(\"/orders\", orders)\n"))
+
+	reverted, ok := Uninject(content, "routes", []byte("r.Get(\"/users\", users)\n"))
+	require.True(t, ok)
+	reverted, ok = Uninject(reverted, "routes", []byte("r.Get(\"/orders\", orders)\n"))
+	require.True(t, ok)
+	assert.Equal(t, mainGo, string(reverted))
+
+	_, ok = Uninject(reverted, "routes", []byte("r.Get(\"/users\", users)\n"))
+	assert.False(t, ok, "a snippet that is not there cannot be removed")
+	_, ok = Uninject(content, "imports", []byte("r.Get(\"/users\", users)\n"))
+	assert.False(t, ok)
+}
+
+func TestInject_KeepsLineEndings(t *testing.T) {
+	content, ok := Inject([]byte("a\r\n# blueprint:deps\r\n"), "deps", []byte("b\n"))
+	require.True(t, ok)
+	assert.Equal(t, "a\r\nb\r\n# blueprint:deps\r\n", string(content))
+}
+
+func TestApplyInjections(t *testing.T) {
+	tree := &TemplateNode{
+		ID:       "0",
+		Template: &Template{Name: "api"},
+		Children: []*TemplateNode{
+			{ID: "0.0", Template: &Template{Name: "postgres"}},
+		},
+	}
+	result := &RenderResult{
+		Files: map[string][]RenderedFile{
+			"0": {{Path: "main.go", Content: []byte(mainGo)}},
+		},
+		Injections: map[string][]RenderedFile{
+			"0.0": {
+				{Path: "main.go", Anchor: "routes", Content: []byte("r.Mount(\"/db\", db)\n")},
+				{Path: "cmd/serve.go", Anchor: "flags", Content: []byte("flag")},
+				{Path: "main.go", Anchor: "imports", Content: []byte("x")},
+			},
+		},
+	}
+
+	require.NoError(t, result.ApplyInjections(tree, nil))
+
+	// Snippets for files of the run are injected into them; others stay.
+	require.Len(t, result.Injections["0.0"], 1)
+	assert.Equal(t, "cmd/serve.go", result.Injections["0.0"][0].Path)
+
+	_, err := result.Files["0"][0].Load()
+	var anchorErr *AnchorNotFoundError
+	require.ErrorAs(t, err, &anchorErr)
+	assert.Equal(t, "postgres", anchorErr.Template)
+	assert.Equal(t, "imports", anchorErr.Anchor)
+}
+
+func TestApplyInjections_OutsideOutput(t *testing.T) {
+	tree := &TemplateNode{ID: "0", Template: &Template{Name: "api"}}
+	result := &RenderResult{
+		Injections: map[string][]RenderedFile{
+			"0": {{Path: "../main.go", Anchor: "routes"}},
+		},
+	}
+
+	var outsideErr *OutsideOutputError
+	require.ErrorAs(t, result.ApplyInjections(tree, nil), &outsideErr)
+}
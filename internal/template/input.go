@@ -0,0 +1,134 @@
+package template
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InputMask restricts what can be typed into the prompt of a string variable.
+// Typed characters are converted or dropped as the user types.
+type InputMask string
+
+const (
+	// MaskLowercase converts letters to lowercase.
+	MaskLowercase InputMask = "lowercase"
+	// MaskUppercase converts letters to uppercase.
+	MaskUppercase InputMask = "uppercase"
+	// MaskSlug allows lowercase letters, digits, and dashes. Letters are
+	// converted to lowercase, and spaces and underscores to dashes.
+	MaskSlug InputMask = "slug"
+	// MaskSnake allows lowercase letters, digits, and underscores. Letters are
+	// converted to lowercase, and spaces and dashes to underscores.
+	MaskSnake InputMask = "snake"
+)
+
+// SuggestionProvider computes the suggestions of a string variable when it is
+// prompted for.
+type SuggestionProvider string
+
+const (
+	// SuggestDirectory suggests the name of the current directory.
+	SuggestDirectory SuggestionProvider = "directory"
+	// SuggestGitRemote suggests module paths such as github.com/acme/app
+	// derived from the git remotes of the current directory.
+	SuggestGitRemote SuggestionProvider = "git-remote"
+	// SuggestGitUserName suggests user.name from the git configuration.
+	SuggestGitUserName SuggestionProvider = "git-user-name"
+	// SuggestGitUserEmail suggests user.email from the git configuration.
+	SuggestGitUserEmail SuggestionProvider = "git-user-email"
+	// SuggestLicense suggests common SPDX license identifiers.
+	SuggestLicense SuggestionProvider = "license"
+)
+
+// Suggestions are the completions offered while the value of a string
+// variable is typed. They are written either as a list of values or as a
+// mapping naming a provider:
+//
+//	suggestions: [api, worker, cli]
+//	suggestions:
+//	  provider: git-remote
+type Suggestions struct {
+	Values   []string           `yaml:"-"`
+	Provider SuggestionProvider `yaml:"provider" validate:"omitempty,oneof=directory git-remote git-user-name git-user-email license"`
+}
+
+func (s *Suggestions) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		return node.Decode(&s.Values)
+	case yaml.MappingNode:
+		type plain Suggestions
+		if err := node.Decode((*plain)(s)); err != nil {
+			return err
+		}
+		if s.Provider == "" {
+			return fmt.Errorf("line %d: suggestions must name a provider", node.Line)
+		}
+		return nil
+	default:
+		return fmt.Errorf("line %d: suggestions must be a list of values or a mapping with a provider", node.Line)
+	}
+}
+
+func (s Suggestions) MarshalYAML() (any, error) {
+	if s.Provider != "" {
+		return map[string]SuggestionProvider{"provider": s.Provider}, nil
+	}
+	return s.Values, nil
+}
+
+// Option is a choice of a select or multiselect variable. An option is
+// written either as its value alone or as a mapping that adds a label shown
+// instead of the value and a description:
+//
+//	options:
+//	  - none
+//	  - value: postgres
+//	    label: PostgreSQL (recommended)
+//	    description: Relational database with migrations
+//
+// Only the value is stored in the render context.
+type Option struct {
+	Value       string `yaml:"value"`
+	Label       string `yaml:"label,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// NewOptions returns options whose labels are their values.
+func NewOptions(values ...string) []Option {
+	options := make([]Option, len(values))
+	for i, value := range values {
+		options[i] = Option{Value: value}
+	}
+	return options
+}
+
+// Title returns the text shown for the option: its label, or its value when
+// it has none.
+func (o Option) Title() string {
+	if o.Label != "" {
+		return o.Label
+	}
+	return o.Value
+}
+
+func (o *Option) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Decode(&o.Value)
+	case yaml.MappingNode:
+		type plain Option
+		return node.Decode((*plain)(o))
+	default:
+		return fmt.Errorf("line %d: an option must be a value or a mapping with a value", node.Line)
+	}
+}
+
+func (o Option) MarshalYAML() (any, error) {
+	if o.Label == "" && o.Description == "" {
+		return o.Value, nil
+	}
+	type plain Option
+	return plain(o), nil
+}
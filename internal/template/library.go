@@ -0,0 +1,282 @@
+package template
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// funcLibraries holds the optional function sets that templates and the user
+// configuration can enable by name. They are kept out of the default function
+// map so it stays small.
+var funcLibraries = map[string]template.FuncMap{
+	"crypto": {
+		"sha256sum": sha256sum,
+		"sha1sum":   sha1sum,
+		"md5sum":    md5sum,
+		"b64enc":    b64enc,
+		"b64dec":    b64dec,
+		"randHex":   randHex,
+		"uuidv4":    uuidv4,
+	},
+	"network": {
+		"cidrHost":     cidrHost,
+		"cidrNetmask":  cidrNetmask,
+		"cidrContains": cidrContains,
+		"isIP":         isIP,
+		"joinHostPort": joinHostPort,
+		"urlHost":      urlHost,
+	},
+	"kubernetes-names": {
+		"k8sName":       k8sName,
+		"k8sSubdomain":  k8sSubdomain,
+		"k8sLabelValue": k8sLabelValue,
+		"envVarName":    envVarName,
+	},
+}
+
+// FuncLibraries returns the sorted names of the optional function libraries.
+func FuncLibraries() []string {
+	names := make([]string, 0, len(funcLibraries))
+	for name := range funcLibraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsFuncLibrary reports whether name is an optional function library.
+func IsFuncLibrary(name string) bool {
+	_, ok := funcLibraries[name]
+	return ok
+}
+
+// funcLibraryOf returns the name of the optional library providing the
+// function fn, or "" if no library does.
+func funcLibraryOf(fn string) string {
+	for _, name := range FuncLibraries() {
+		if _, ok := funcLibraries[name][fn]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// allLibraryFuncs returns the functions of every optional library. It is used
+// where templates are only parsed, never executed.
+func allLibraryFuncs() template.FuncMap {
+	funcs := make(template.FuncMap)
+	for _, lib := range funcLibraries {
+		for fn, impl := range lib {
+			funcs[fn] = impl
+		}
+	}
+	return funcs
+}
+
+// Crypto functions
+
+func sha256sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func sha1sum(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func md5sum(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func b64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func b64dec(s string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// randHex returns n random hexadecimal characters, e.g. for generated secrets.
+func randHex(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("randHex: length must not be negative")
+	}
+	buf := make([]byte, (n+1)/2)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf)[:n], nil
+}
+
+func uuidv4() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
+// Network functions
+
+// cidrHost returns the address of host number n within a network, e.g.
+// cidrHost "10.0.0.0/24" 5 is 10.0.0.5.
+func cidrHost(cidr string, n int) (string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return "", err
+	}
+	if n < 0 {
+		return "", fmt.Errorf("cidrHost: host number must not be negative")
+	}
+
+	addr := prefix.Masked().Addr()
+	bytes := addr.AsSlice()
+	carry := n
+	for i := len(bytes) - 1; i >= 0 && carry > 0; i-- {
+		sum := int(bytes[i]) + carry%256
+		carry = carry/256 + sum/256
+		bytes[i] = byte(sum % 256)
+	}
+
+	host, _ := netip.AddrFromSlice(bytes)
+	if carry > 0 || !prefix.Contains(host) {
+		return "", fmt.Errorf("cidrHost: host %d is outside %s", n, cidr)
+	}
+	return host.String(), nil
+}
+
+// cidrNetmask returns the dotted netmask of an IPv4 network.
+func cidrNetmask(cidr string) (string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return "", err
+	}
+	if !prefix.Addr().Is4() {
+		return "", fmt.Errorf("cidrNetmask: %s is not an IPv4 network", cidr)
+	}
+	return net.IP(net.CIDRMask(prefix.Bits(), 32)).String(), nil
+}
+
+func cidrContains(cidr, ip string) (bool, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return false, err
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false, err
+	}
+	return prefix.Contains(addr), nil
+}
+
+func isIP(s string) bool {
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
+
+func joinHostPort(host string, port any) string {
+	return net.JoinHostPort(host, toString(port))
+}
+
+// urlHost returns the host name of a URL without the port.
+func urlHost(s string) (string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return u.Hostname(), nil
+}
+
+// Kubernetes name functions
+
+// k8sName converts s to a DNS-1123 label as used for most resource names:
+// at most 63 lowercase alphanumerics or dashes, starting and ending with an
+// alphanumeric.
+func k8sName(s string) string {
+	return sanitizeName(strings.ToLower(s), 63, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= '0' && r <= '9'
+	}, "-")
+}
+
+// k8sSubdomain converts s to a DNS-1123 subdomain: like k8sName, but up to
+// 253 characters and dots are allowed.
+func k8sSubdomain(s string) string {
+	labels := strings.Split(strings.ToLower(s), ".")
+	kept := labels[:0]
+	for _, label := range labels {
+		if label = k8sName(label); label != "" {
+			kept = append(kept, label)
+		}
+	}
+	name := strings.Join(kept, ".")
+	if len(name) > 253 {
+		name = strings.TrimRight(name[:253], ".-")
+	}
+	return name
+}
+
+// k8sLabelValue converts s to a valid label value: at most 63 alphanumerics,
+// dashes, underscores, or dots, starting and ending with an alphanumeric.
+func k8sLabelValue(s string) string {
+	return sanitizeName(s, 63, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.'
+	}, "-_.")
+}
+
+// envVarName converts s to an environment variable name, e.g. "my-app.port"
+// becomes "MY_APP_PORT".
+func envVarName(s string) string {
+	name := sanitizeName(strings.ToUpper(s), 0, func(r rune) bool {
+		return r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+	}, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// sanitizeName replaces runs of characters not accepted by valid with the
+// first separator character, trims separators from both ends, and limits the
+// result to max bytes when max is positive.
+func sanitizeName(s string, max int, valid func(rune) bool, separators string) string {
+	sep := rune(separators[0])
+
+	var b strings.Builder
+	lastSep := false
+	for _, r := range s {
+		if valid(r) {
+			b.WriteRune(r)
+			lastSep = false
+			continue
+		}
+		if !lastSep {
+			b.WriteRune(sep)
+			lastSep = true
+		}
+	}
+
+	name := strings.Trim(b.String(), separators)
+	if max > 0 && len(name) > max {
+		name = strings.TrimRight(name[:max], separators)
+	}
+	return name
+}
@@ -0,0 +1,115 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_EnableLibrary(t *testing.T) {
+	r := NewRenderer()
+
+	_, err := r.RenderString(`{{ sha256sum "abc" }}`, testContext(nil), "test")
+	require.Error(t, err, "library functions are not available by default")
+
+	require.NoError(t, r.EnableLibrary("crypto"))
+	out, err := r.RenderString(`{{ sha256sum "abc" }}`, testContext(nil), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", string(out))
+
+	require.ErrorContains(t, r.EnableLibrary("missing"), `unknown function library "missing"`)
+}
+
+func TestRenderAll_EnablesTemplateLibraries(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "svc.yaml.tmpl"), []byte("name: {{ k8sName .name }}"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:      "root",
+			Functions: []string{"kubernetes-names"},
+			Files:     []File{{Src: "svc.yaml.tmpl", Dest: "svc.yaml"}},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+		Children: []*TemplateNode{{
+			ID:       "1",
+			Template: &Template{Name: "child", Files: []File{{Src: "svc.yaml.tmpl", Dest: "other.yaml"}}},
+			FS:       os.DirFS(dir),
+			Path:     ".",
+		}},
+	}
+	contexts := RenderContexts{
+		"0": testContext(map[string]any{"name": "My_Service"}),
+		"1": testContext(map[string]any{"name": "My_Service"}),
+	}
+
+	_, err := r.RenderAll(node, contexts)
+	require.Error(t, err, "the child template does not enable the library")
+
+	node.Children = nil
+	out, err := r.RenderAll(node, contexts)
+	require.NoError(t, err)
+	assert.Equal(t, "name: my-service", string(out.Files["0"][0].Content))
+}
+
+func TestLibraryFunctions(t *testing.T) {
+	t.Run("crypto", func(t *testing.T) {
+		assert.Equal(t, "YWJj", b64enc("abc"))
+		decoded, err := b64dec("YWJj")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", decoded)
+
+		hex, err := randHex(7)
+		require.NoError(t, err)
+		assert.Len(t, hex, 7)
+
+		uuid, err := uuidv4()
+		require.NoError(t, err)
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+	})
+
+	t.Run("network", func(t *testing.T) {
+		host, err := cidrHost("10.0.0.0/24", 5)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.5", host)
+
+		host, err = cidrHost("10.0.0.0/16", 300)
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.1.44", host)
+
+		_, err = cidrHost("10.0.0.0/30", 4)
+		require.Error(t, err)
+
+		mask, err := cidrNetmask("192.168.0.0/20")
+		require.NoError(t, err)
+		assert.Equal(t, "255.255.240.0", mask)
+
+		ok, err := cidrContains("10.0.0.0/8", "10.2.3.4")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		assert.True(t, isIP("::1"))
+		assert.False(t, isIP("localhost"))
+		assert.Equal(t, "[::1]:8080", joinHostPort("::1", 8080))
+
+		h, err := urlHost("https://api.example.com:8443/v1")
+		require.NoError(t, err)
+		assert.Equal(t, "api.example.com", h)
+	})
+
+	t.Run("kubernetes names", func(t *testing.T) {
+		assert.Equal(t, "my-service-v2", k8sName("--My_Service  v2!"))
+		assert.Len(t, k8sName(strings.Repeat("b", 100)), 63)
+		assert.Equal(t, "api.my-app.example", k8sSubdomain("API.My_App..example"))
+		assert.Equal(t, "Release_1.2-rc", k8sLabelValue("-Release_1.2 rc-"))
+		assert.Equal(t, "MY_APP_PORT", envVarName("my-app.port"))
+		assert.Equal(t, "_9LIVES", envVarName("9lives"))
+	})
+}
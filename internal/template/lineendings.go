@@ -0,0 +1,89 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+)
+
+// LineEnding selects the line terminator of generated text files.
+type LineEnding string
+
+const (
+	// LineEndingLF terminates lines with \n.
+	LineEndingLF LineEnding = "lf"
+	// LineEndingCRLF terminates lines with \r\n.
+	LineEndingCRLF LineEnding = "crlf"
+	// LineEndingNative uses the terminator of the platform Blueprint runs on.
+	LineEndingNative LineEnding = "native"
+)
+
+// ParseLineEnding parses a line ending setting. The empty string keeps the
+// line endings of the rendered files and is returned as is.
+func ParseLineEnding(s string) (LineEnding, error) {
+	switch e := LineEnding(s); e {
+	case "", LineEndingLF, LineEndingCRLF, LineEndingNative:
+		return e, nil
+	default:
+		return "", fmt.Errorf("invalid line endings %q: expected lf, crlf, or native", s)
+	}
+}
+
+// resolve returns LineEndingLF or LineEndingCRLF for a native ending.
+func (e LineEnding) resolve() LineEnding {
+	if e != LineEndingNative {
+		return e
+	}
+	if runtime.GOOS == "windows" {
+		return LineEndingCRLF
+	}
+	return LineEndingLF
+}
+
+// NormalizeText converts the line endings of text content to e and, with
+// finalNewline, terminates a last line that has no line ending. An empty e
+// keeps the line endings; a missing final newline then takes the form of the
+// first line ending of the content. Empty and binary content is returned
+// unchanged.
+func NormalizeText(content []byte, e LineEnding, finalNewline bool) []byte {
+	if len(content) == 0 || IsBinary(content) {
+		return content
+	}
+
+	newline := []byte("\n")
+	switch e.resolve() {
+	case LineEndingLF:
+		content = bytes.ReplaceAll(content, []byte("\r\n"), newline)
+	case LineEndingCRLF:
+		content = bytes.ReplaceAll(content, []byte("\r\n"), newline)
+		content = bytes.ReplaceAll(content, newline, []byte("\r\n"))
+		newline = []byte("\r\n")
+	default:
+		if i := bytes.IndexByte(content, '\n'); i > 0 && content[i-1] == '\r' {
+			newline = []byte("\r\n")
+		}
+	}
+
+	if finalNewline && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content[:len(content):len(content)], newline...)
+	}
+	return content
+}
+
+// ApplyLineEndings normalizes the line endings and final newline of every
+// text file of the result with NormalizeText. It should be applied last, after
+// the files are complete.
+func (r *RenderResult) ApplyLineEndings(e LineEnding, finalNewline bool) {
+	if e == "" && !finalNewline {
+		return
+	}
+
+	for id, files := range r.Files {
+		for i := range files {
+			files[i].transform(func(content []byte) []byte {
+				return NormalizeText(content, e, finalNewline)
+			})
+		}
+		r.Files[id] = files
+	}
+}
@@ -0,0 +1,45 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := map[string]struct {
+		content      string
+		endings      LineEnding
+		finalNewline bool
+		want         string
+	}{
+		"crlf to lf":                {"a\r\nb\r\n", LineEndingLF, false, "a\nb\n"},
+		"lf to crlf":                {"a\nb\n", LineEndingCRLF, false, "a\r\nb\r\n"},
+		"mixed to crlf":             {"a\r\nb\nc", LineEndingCRLF, false, "a\r\nb\r\nc"},
+		"final newline":             {"a\nb", LineEndingLF, true, "a\nb\n"},
+		"final newline crlf":        {"a\nb", LineEndingCRLF, true, "a\r\nb\r\n"},
+		"final newline kept ending": {"a\r\nb", "", true, "a\r\nb\r\n"},
+		"final newline present":     {"a\n", "", true, "a\n"},
+		"kept":                      {"a\r\nb", "", false, "a\r\nb"},
+		"empty":                     {"", LineEndingCRLF, true, ""},
+		"binary":                    {"a\x00\nb", LineEndingCRLF, true, "a\x00\nb"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NormalizeText([]byte(tt.content), tt.endings, tt.finalNewline)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestParseLineEnding(t *testing.T) {
+	for _, s := range []string{"", "lf", "crlf", "native"} {
+		e, err := ParseLineEnding(s)
+		require.NoError(t, err)
+		assert.Equal(t, LineEnding(s), e)
+	}
+
+	_, err := ParseLineEnding("cr")
+	assert.Error(t, err)
+}
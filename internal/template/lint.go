@@ -0,0 +1,272 @@
+package template
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintIssue is a problem found in a template by Lint.
+type LintIssue struct {
+	Template string // Name of the template, or the path when it could not be read
+	File     string // Source path the issue is in; empty for the template definition
+	Message  string
+}
+
+// Lint checks the template at root for the problems template authors are
+// likely to make: fields the schema does not know or rejects, missing source
+// files, templates that do not parse, includes that cannot be resolved or form
+// a cycle, and variables that are used but never declared or declared but
+// never used. All problems are collected instead of stopping at the first.
+//
+// Variables named in defaults, the defaults section of the user
+// configuration, count as declared, since include conditions may refer to
+// them.
+func (e *Engine) Lint(fsys fs.FS, root string, defaults map[string]any) ([]LintIssue, error) {
+	templatePath := resolveTemplatePath(root)
+
+	data, err := fs.ReadFile(fsys, templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	var tmpl Template
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	l := &linter{renderer: e.renderer, validator: e.validator, defaults: defaults}
+
+	if err := dec.Decode(&tmpl); err != nil {
+		// Unknown fields are reported together, one per line.
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			for _, msg := range typeErr.Errors {
+				l.add(templatePath, "", msg)
+			}
+		} else {
+			l.add(templatePath, "", err.Error())
+		}
+		if schemaErr := checkSchema(data); schemaErr != nil {
+			l.add(templatePath, "", schemaErr.Error())
+		} else if migrated, err := Migrate(data); err == nil && len(migrated.Changes) > 0 {
+			l.add(templatePath, "", fmt.Sprintf("written for template schema %d; upgrade it with blueprint template migrate",
+				migrated.From))
+		}
+		return l.issues, nil
+	}
+
+	if err := expandVariableUses(&tmpl, fsys, e.loader.libraries); err != nil {
+		l.add(tmpl.Name, "", err.Error())
+	}
+
+	if err := e.validator.Validate(&tmpl); err != nil {
+		l.addError(tmpl.Name, "", err)
+	}
+
+	// If an include cannot be composed, only the template itself is checked.
+	loaded := &LoadedTemplate{Template: &tmpl, FS: fsys, Path: path.Dir(templatePath)}
+	tree, err := e.composer.ComposeWithOptions(loaded, ComposeOptions{IncludeAll: true})
+	if err != nil {
+		// Resolvers searching several sources join an error per source; the
+		// first line already names the include.
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		l.add(tmpl.Name, "", msg)
+		tree = &TemplateNode{ID: rootNodeID, Template: &tmpl, FS: fsys, Path: loaded.Path}
+	}
+
+	l.lintNode(tree)
+	return l.issues, nil
+}
+
+type linter struct {
+	renderer  *Renderer
+	validator *Validator
+	defaults  map[string]any
+	issues    []LintIssue
+}
+
+func (l *linter) add(tmpl, file, message string) {
+	l.issues = append(l.issues, LintIssue{Template: tmpl, File: file, Message: message})
+}
+
+// addError adds an issue for every line of err, which splits joined errors
+// and the field errors of the struct validator.
+func (l *linter) addError(tmpl, file string, err error) {
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			l.add(tmpl, file, line)
+		}
+	}
+}
+
+// lintNode checks a node and its children.
+func (l *linter) lintNode(node *TemplateNode) {
+	tmpl := node.Template
+
+	if err := l.validator.validateIncludes(node); err != nil {
+		l.addError(tmpl.Name, "", err)
+	}
+	for _, err := range l.validator.validateNodeFiles(node) {
+		l.add(tmpl.Name, "", err.Error())
+	}
+
+	nr, err := l.renderer.withLibraries(tmpl.Functions)
+	if err != nil {
+		// Unknown libraries are reported by the validator; check with the
+		// default functions only.
+		nr = l.renderer
+	}
+
+	used := make(map[string]bool)
+	parse := func(r *Renderer, text, name, file string) {
+		t, err := r.parse(r.newTemplate(name), text)
+		if err != nil {
+			var renderErr *RenderError
+			if errors.As(err, &renderErr) {
+				err = renderErr.Err
+			}
+			l.add(tmpl.Name, file, err.Error())
+			return
+		}
+		for _, n := range referencedVariables(t, true) {
+			used[n] = true
+		}
+	}
+
+	partials, err := loadPartials(node.FS, node.Path)
+	if err != nil {
+		l.add(tmpl.Name, "", err.Error())
+	}
+
+	// Partials that do not parse are reported once instead of with every file.
+	var parsed []partial
+	pr := nr.withDelimiters(tmpl.Delimiters)
+	for _, p := range partials {
+		if _, err := pr.newTemplate(p.name).Parse(p.content); err != nil {
+			l.add(tmpl.Name, p.source, err.Error())
+			continue
+		}
+		parsed = append(parsed, p)
+	}
+
+	parse(nr, tmpl.LicenseHeader, "license_header", "")
+	parse(nr, tmpl.NextSteps, "next_steps", "")
+	for i, variable := range tmpl.Variables {
+		parse(nr, variable.When, fmt.Sprintf("variables[%d].when", i), "")
+	}
+	for i, inc := range tmpl.Includes {
+		parse(nr, inc.When, fmt.Sprintf("includes[%d].when", i), "")
+		// Inherited values are read from the variables of this template.
+		for _, parentVar := range inc.Inherits {
+			used[parentVar] = true
+		}
+	}
+	for i, cmd := range tmpl.PostInit {
+		parse(nr, cmd.WorkDir, fmt.Sprintf("post_init[%d].workdir", i), "")
+	}
+
+	er := nr.withDelimiters(tmpl.Delimiters)
+	for i, edit := range tmpl.GoEdits {
+		name := fmt.Sprintf("go_edits[%d]", i)
+		for _, text := range []string{
+			edit.File, edit.When, edit.Import, edit.ImportName, edit.Struct, edit.Field, edit.Func, edit.Statement,
+		} {
+			parse(er, text, name, "")
+		}
+	}
+
+	for i, file := range tmpl.Files {
+		parse(nr, file.Dest, fmt.Sprintf("files[%d].dest", i), "")
+		parse(nr, file.When, fmt.Sprintf("files[%d].when", i), "")
+
+		fr := nr.withDelimiters(tmpl.DelimitersFor(file)).withPartials(parsed)
+		srcPath := path.Join(node.Path, file.Src)
+		err := fs.WalkDir(node.FS, srcPath, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !file.selectsUnder(srcPath, p, d.IsDir()) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || d.Type()&fs.ModeSymlink != 0 || !isTemplateFile(p) {
+				return nil
+			}
+
+			content, err := fs.ReadFile(node.FS, p)
+			if err != nil {
+				l.add(tmpl.Name, p, err.Error())
+				return nil
+			}
+			if IsBinary(content) {
+				return nil
+			}
+			fm, body, err := SplitFrontMatter(content)
+			if err != nil {
+				l.add(tmpl.Name, p, err.Error())
+				return nil
+			}
+			if fm != nil {
+				parse(nr, fm.Dest, p+" front matter dest", p)
+				parse(nr, fm.When, p+" front matter when", p)
+				parse(fr.withDelimiters(fm.Delimiters), string(body), p, p)
+				return nil
+			}
+			parse(fr, string(content), p, p)
+			return nil
+		})
+		// Missing sources are reported by validateNodeFiles.
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			l.add(tmpl.Name, srcPath, err.Error())
+		}
+	}
+
+	declared := map[string]bool{BuiltinVariable: true}
+	for _, v := range tmpl.Variables {
+		declared[v.Name] = true
+	}
+	for childVar := range node.Inherited {
+		declared[childVar] = true
+	}
+	if tmpl.Go != nil {
+		declared[GoVersionVariable] = true
+	}
+	for _, file := range tmpl.Files {
+		if file.ForEach != "" {
+			declared[ForEachItemVariable] = true
+			used[file.ForEach] = true
+		}
+	}
+
+	for _, n := range sortedSet(used) {
+		if _, ok := l.defaults[n]; !declared[n] && !ok {
+			l.add(tmpl.Name, "", fmt.Sprintf("variable %s is used but never declared", n))
+		}
+	}
+
+	for _, v := range tmpl.Variables {
+		// Env-only secrets are passed to post-init commands and are not
+		// available to the template.
+		if v.EnvOnly {
+			if used[v.Name] {
+				l.add(tmpl.Name, "", fmt.Sprintf("variable %s is env_only and is not available to the template", v.Name))
+			}
+			continue
+		}
+		// Variables with a role are used by Blueprint itself, e.g. to name the
+		// project directory.
+		if !used[v.Name] && v.Role == "" {
+			l.add(tmpl.Name, "", fmt.Sprintf("variable %s is declared but never used", v.Name))
+		}
+	}
+
+	for _, child := range node.Children {
+		l.lintNode(child)
+	}
+}
@@ -0,0 +1,241 @@
+package template
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fsResolver resolves template names to directories of a single filesystem.
+type fsResolver struct {
+	fsys fstest.MapFS
+}
+
+func (r *fsResolver) Resolve(ref TemplateRef) (*ResolvedTemplate, error) {
+	if _, ok := r.fsys[ref.Name+"/"+FileName]; !ok {
+		return nil, &TemplateNotFoundError{Name: ref.Name}
+	}
+	return &ResolvedTemplate{FS: r.fsys, Path: ref.Name}, nil
+}
+
+func lintMessages(t *testing.T, fsys fstest.MapFS, root string) []string {
+	t.Helper()
+
+	issues, err := NewEngine(&fsResolver{fsys: fsys}).Lint(fsys, root, nil)
+	require.NoError(t, err)
+
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.Message
+	}
+	return messages
+}
+
+const lintProject = `name: app
+type: project
+version: 1.0.0
+description: An app
+variables:
+  - name: project_name
+    prompt: Project name?
+    type: string
+    role: project_name
+`
+
+func TestLint_ValidTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `  - name: port
+    prompt: Port?
+    type: int
+files:
+  - src: files
+    dest: "{{ .project_name }}"
+`)},
+		"app/files/main.go.tmpl": {Data: []byte("{{ with .port }}{{ .Value }}{{ end }}")},
+		"app/files/logo.png":     {Data: []byte{0x89, 'P', 0, '{', '{'}},
+	}
+
+	assert.Empty(t, lintMessages(t, fsys, "app"))
+}
+
+func TestLint_UnknownField(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + "descripton: typo\n")},
+	}
+
+	messages := lintMessages(t, fsys, "app")
+
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "field descripton not found")
+}
+
+func TestLint_ReportsAllProblems(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `  - name: unused
+    prompt: Unused?
+    type: string
+files:
+  - src: main.go.tmpl
+    dest: main.go
+  - src: broken.tmpl
+    dest: broken
+  - src: missing.tmpl
+    dest: missing
+`)},
+		"app/main.go.tmpl": {Data: []byte("{{ .port }}")},
+		"app/broken.tmpl":  {Data: []byte("{{ if .project_name }}")},
+	}
+
+	messages := lintMessages(t, fsys, "app")
+
+	require.Len(t, messages, 4)
+	assert.Contains(t, messages[0], `source file "app/missing.tmpl" does not exist`)
+	assert.Contains(t, messages[1], "unexpected EOF")
+	assert.Equal(t, "variable port is used but never declared", messages[2])
+	assert.Equal(t, "variable unused is declared but never used", messages[3])
+}
+
+func TestLint_IncludeCycle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + "includes:\n  - name: a\n")},
+		"a/template.yaml":   {Data: []byte("name: a\ntype: feature\nversion: 1.0.0\ndescription: A\nincludes:\n  - name: b\n")},
+		"b/template.yaml":   {Data: []byte("name: b\ntype: feature\nversion: 1.0.0\ndescription: B\nincludes:\n  - name: a\n")},
+	}
+
+	messages := lintMessages(t, fsys, "app")
+
+	require.Len(t, messages, 1)
+	assert.Contains(t, messages[0], "circular dependency detected")
+}
+
+func TestLint_InheritedVariables(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `includes:
+  - name: feature
+    inherits:
+      name: project_name
+`)},
+		"feature/template.yaml": {Data: []byte(`name: feature
+type: feature
+version: 1.0.0
+description: A feature
+files:
+  - src: README.md.tmpl
+    dest: README.md
+`)},
+		"feature/README.md.tmpl": {Data: []byte("# {{ .name }} {{ .missing }}")},
+	}
+
+	issues, err := NewEngine(&fsResolver{fsys: fsys}).Lint(fsys, "app", nil)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "feature", issues[0].Template)
+	assert.Equal(t, "variable missing is used but never declared", issues[0].Message)
+}
+
+func TestLint_Partials(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `  - name: owner
+    prompt: Owner?
+    type: string
+files:
+  - src: main.go.tmpl
+    dest: main.go
+`)},
+		"app/main.go.tmpl":          {Data: []byte(`{{ template "header" . }}{{ with .project_name }}{{ template "footer" . }}{{ end }}`)},
+		"app/partials/header.tmpl":  {Data: []byte("// {{ .owner }}")},
+		"app/partials/footer.tmpl":  {Data: []byte("{{ .Value }}")},
+		"app/partials/unused.tmpl":  {Data: []byte("{{ .missing }}")},
+		"app/partials/invalid.tmpl": {Data: []byte("{{ if .owner }}")},
+	}
+
+	issues, err := NewEngine(&fsResolver{fsys: fsys}).Lint(fsys, "app", nil)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, "app/partials/invalid.tmpl", issues[0].File)
+	assert.Contains(t, issues[0].Message, "unexpected EOF")
+}
+
+func TestLint_GoVersionVariable(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `go:
+  min: "1.22"
+files:
+  - src: go.mod.tmpl
+    dest: go.mod
+`)},
+		"app/go.mod.tmpl": {Data: []byte("module {{ .project_name }}\n\ngo {{ .go_version }}\n")},
+	}
+
+	assert.Empty(t, lintMessages(t, fsys, "app"))
+}
+
+func TestLint_ForEachItem(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `  - name: services
+    prompt: Services?
+    type: multiselect
+    options: [users, orders]
+files:
+  - src: handler.go.tmpl
+    dest: "internal/{{ .item }}/handler.go"
+    for_each: services
+`)},
+		"app/handler.go.tmpl": {Data: []byte("package {{ .item }} // {{ .project_name }}\n")},
+	}
+
+	assert.Empty(t, lintMessages(t, fsys, "app"))
+}
+
+func TestLint_VariableConditions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `  - name: use_database
+    prompt: Use a database?
+    type: bool
+  - name: db_port
+    prompt: Port?
+    type: int
+    when: "{{ .use_database }}"
+  - name: db_name
+    prompt: Database name?
+    type: string
+    when: "{{ .use_database "
+files:
+  - src: main.go.tmpl
+    dest: "{{ .project_name }}/main.go"
+`)},
+		"app/main.go.tmpl": {Data: []byte("{{ .db_port }} {{ .db_name }}")},
+	}
+
+	messages := lintMessages(t, fsys, "app")
+
+	require.Len(t, messages, 1, "a variable used only in a condition counts as used")
+	assert.Contains(t, messages[0], "variables[3].when")
+}
+
+func TestLint_EnvOnlySecrets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte(lintProject + `  - name: api_token
+    prompt: API token?
+    type: secret
+    env_only: true
+  - name: deploy_key
+    prompt: Deploy key?
+    type: secret
+    env_only: true
+files:
+  - src: main.go.tmpl
+    dest: "{{ .project_name }}/main.go"
+`)},
+		"app/main.go.tmpl": {Data: []byte("{{ .deploy_key }}")},
+	}
+
+	messages := lintMessages(t, fsys, "app")
+
+	require.Len(t, messages, 1, "an unused env-only secret is not reported")
+	assert.Contains(t, messages[0], "deploy_key is env_only")
+}
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"path"
 
+	"github.com/dhanush0x96c/blueprint/internal/version"
 	"gopkg.in/yaml.v3"
 )
 
@@ -26,7 +27,8 @@ type Loader interface {
 
 // FileLoader handles loading templates from the filesystem
 type FileLoader struct {
-	validate *Validator
+	validate  *Validator
+	libraries VariableLibraryResolver
 }
 
 // NewLoader creates a new template loader.
@@ -36,12 +38,22 @@ func NewLoader() *FileLoader {
 	}
 }
 
+// WithLibraries returns a copy of the loader that looks up the variable
+// libraries a template uses in libs when its own filesystem does not ship
+// them.
+func (l *FileLoader) WithLibraries(libs VariableLibraryResolver) *FileLoader {
+	loader := *l
+	loader.libraries = libs
+	return &loader
+}
+
 // Load loads a template from the given filesystem.
 //
 // The path may refer to either a template.yaml file or a directory
 // containing one. In the latter case, "<dir>/template.yaml" is used.
 //
-// The loaded template is validated.
+// Variables that use a shared definition are expanded, and the loaded
+// template is validated.
 func (l *FileLoader) Load(fsys fs.FS, pth string) (*LoadedTemplate, error) {
 	templatePath := resolveTemplatePath(pth)
 
@@ -50,13 +62,26 @@ func (l *FileLoader) Load(fsys fs.FS, pth string) (*LoadedTemplate, error) {
 		return nil, fmt.Errorf("failed to read template file: %w", err)
 	}
 
+	if err := checkSchema(data); err != nil {
+		return nil, err
+	}
+
 	var tmpl Template
 	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		if migrated, merr := Migrate(data); merr == nil && len(migrated.Changes) > 0 &&
+			yaml.Unmarshal(migrated.Content, &Template{}) == nil {
+			return nil, fmt.Errorf("failed to parse template YAML: %w (written for template schema %d; "+
+				"upgrade it with blueprint template migrate)", err, migrated.From)
+		}
 		return nil, fmt.Errorf("failed to parse template YAML: %w", err)
 	}
 
+	if err := expandVariableUses(&tmpl, fsys, l.libraries); err != nil {
+		return nil, &ValidationError{Template: tmpl.Name, Err: err}
+	}
+
 	if err := l.validate.Validate(&tmpl); err != nil {
-		return nil, fmt.Errorf("template validation failed: %w", err)
+		return nil, &ValidationError{Template: tmpl.Name, Err: err}
 	}
 
 	return &LoadedTemplate{
@@ -87,6 +112,48 @@ func (l *FileLoader) LoadMetadata(fsys fs.FS, pth string) (*Metadata, error) {
 	return &meta, nil
 }
 
+// checkSchema rejects a template.yaml that needs a newer Blueprint than this
+// one, or is written for a newer schema than this version supports, before
+// fields it does not know fail to parse.
+func checkSchema(data []byte) error {
+	var header struct {
+		Name                string `yaml:"name"`
+		Schema              int    `yaml:"schema"`
+		MinBlueprintVersion string `yaml:"min_blueprint_version"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		// Parsing the whole template reports the error.
+		return nil
+	}
+	if !SupportsBlueprintVersion(header.MinBlueprintVersion, version.Version) {
+		return &UnsupportedVersionError{Template: header.Name, MinVersion: header.MinBlueprintVersion, Version: version.Version}
+	}
+	if header.Schema > SchemaVersion {
+		return &UnsupportedSchemaError{Template: header.Name, Schema: header.Schema}
+	}
+	return nil
+}
+
+// SupportsBlueprintVersion reports whether the Blueprint release current
+// satisfies the minimum release a template declares. Development builds, whose
+// version is not semver, and templates without a valid minimum support every
+// template; the validator reports an invalid minimum.
+func SupportsBlueprintVersion(minVersion, current string) bool {
+	if minVersion == "" {
+		return true
+	}
+	want, err := parseSemver(minVersion)
+	if err != nil {
+		return true
+	}
+	have, err := parseSemver(current)
+	if err != nil {
+		return true
+	}
+	// A pre-release of the minimum release does not support it yet.
+	return have.compare(want) >= 0
+}
+
 // resolveTemplatePath resolves a template path to a template manifest path.
 func resolveTemplatePath(pth string) string {
 	if path.Base(pth) == FileName {
@@ -1,10 +1,14 @@
 package template
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
+	"github.com/dhanush0x96c/blueprint/internal/version"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -123,3 +127,117 @@ description: "Template without tags"
 		require.Nil(t, tmpl.Template.Tags)
 	})
 }
+
+func TestLoader_LoadFileMode(t *testing.T) {
+	base := t.TempDir()
+	writeTemplate(t, base, validFeatureTemplate+`
+files:
+  - src: run.sh
+    dest: run.sh
+    mode: 0755
+`)
+
+	tmpl, err := NewLoader().Load(os.DirFS(base), ".")
+	require.NoError(t, err)
+
+	mode, err := tmpl.Template.Files[0].FileMode()
+	require.NoError(t, err)
+	require.Equal(t, fs.FileMode(0o755), mode)
+}
+
+func TestLoader_LoadSuggestions(t *testing.T) {
+	base := t.TempDir()
+	writeTemplate(t, base, validFeatureTemplate+`
+variables:
+  - name: kind
+    prompt: Kind?
+    type: string
+    mask: slug
+    suggestions: [api, worker]
+  - name: module
+    prompt: Module?
+    type: string
+    suggestions:
+      provider: git-remote
+`)
+
+	tmpl, err := NewLoader().Load(os.DirFS(base), ".")
+	require.NoError(t, err)
+
+	vars := tmpl.Template.Variables
+	require.Len(t, vars, 2)
+	require.Equal(t, MaskSlug, vars[0].Mask)
+	require.Equal(t, &Suggestions{Values: []string{"api", "worker"}}, vars[0].Suggestions)
+	require.Equal(t, &Suggestions{Provider: SuggestGitRemote}, vars[1].Suggestions)
+}
+
+func TestLoader_LoadOptions(t *testing.T) {
+	base := t.TempDir()
+	writeTemplate(t, base, validFeatureTemplate+`
+variables:
+  - name: database
+    prompt: Database?
+    type: select
+    options:
+      - none
+      - value: postgres
+        label: PostgreSQL (recommended)
+        description: Relational database with migrations
+`)
+
+	tmpl, err := NewLoader().Load(os.DirFS(base), ".")
+	require.NoError(t, err)
+
+	vars := tmpl.Template.Variables
+	require.Len(t, vars, 1)
+	require.Equal(t, []Option{
+		{Value: "none"},
+		{Value: "postgres", Label: "PostgreSQL (recommended)", Description: "Relational database with migrations"},
+	}, vars[0].Options)
+	require.Equal(t, []string{"none", "postgres"}, vars[0].OptionValues())
+	require.Equal(t, "PostgreSQL (recommended)", vars[0].Options[1].Title())
+	require.Equal(t, "none", vars[0].Options[0].Title())
+}
+
+func TestLoader_RejectsNewerBlueprintVersion(t *testing.T) {
+	current := version.Version
+	t.Cleanup(func() { version.Version = current })
+	version.Version = "v1.2.0"
+
+	fsys := fstest.MapFS{
+		FileName: {Data: []byte("name: a\ntype: feature\nversion: 1.0.0\nmin_blueprint_version: 1.4.0\nschema: 3\n")},
+	}
+
+	_, err := NewLoader().Load(fsys, ".")
+	var versionErr *UnsupportedVersionError
+	require.ErrorAs(t, err, &versionErr, "the version is checked before the schema")
+	assert.Equal(t, "1.4.0", versionErr.MinVersion)
+	assert.EqualError(t, err, "a requires blueprint 1.4.0 or newer, but this is blueprint v1.2.0; upgrade blueprint to use it")
+
+	version.Version = "1.4.0"
+	fsys[FileName].Data = []byte("name: a\ntype: feature\nversion: 1.0.0\nmin_blueprint_version: 1.4.0\n")
+	_, err = NewLoader().Load(fsys, ".")
+	require.NoError(t, err)
+
+	fsys[FileName].Data = []byte("name: a\ntype: feature\nversion: 1.0.0\nmin_blueprint_version: latest\n")
+	_, err = NewLoader().Load(fsys, ".")
+	require.ErrorContains(t, err, `min_blueprint_version: "latest" is not a release version such as 1.4.0`)
+}
+
+func TestSupportsBlueprintVersion(t *testing.T) {
+	tests := []struct {
+		min, current string
+		want         bool
+	}{
+		{"", "1.0.0", true},
+		{"1.2.0", "1.2.0", true},
+		{"1.2.0", "v1.10.0", true},
+		{"1.2.0", "1.1.9", false},
+		{"1.2.0", "1.2.0-rc.1", false},
+		{"1.2.0", "dev", true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, SupportsBlueprintVersion(tt.min, tt.current), "%s on %s", tt.min, tt.current)
+	}
+}
@@ -0,0 +1,121 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// localePattern matches language tags such as de, pt-BR, pt_BR or zh-Hant-TW.
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// IsLocale reports whether s is a language tag such as de or pt-BR.
+func IsLocale(s string) bool {
+	return localePattern.MatchString(s)
+}
+
+// LocaleChain returns the locales tried for locale, most specific first:
+// pt-BR falls back to pt. An empty locale has no chain.
+func LocaleChain(locale string) []string {
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return chain
+}
+
+// Locale returns the locale the files of the template are rendered in: the
+// value of its variable with role locale, else the locale of the builtin
+// variable, which is the configured one.
+func (t *Template) Locale(ctx *Context) string {
+	if v, err := t.VariableByRole(RoleLocale); err == nil {
+		if value, ok := ctx.Get(v.Name); ok && value != nil {
+			if locale := fmt.Sprint(value); locale != "" {
+				return locale
+			}
+		}
+	}
+
+	if builtins, ok := ctx.Variables[BuiltinVariable].(map[string]any); ok {
+		if locale, ok := builtins["locale"].(string); ok {
+			return locale
+		}
+	}
+	return ""
+}
+
+// splitLocaleName splits a file name where the locale of a variant goes:
+// before the first extension, so README.md.tmpl becomes README and
+// .md.tmpl. The leading dot of a hidden file is part of the stem.
+func splitLocaleName(name string) (stem, ext string) {
+	start := 0
+	if strings.HasPrefix(name, ".") {
+		start = 1
+	}
+	i := strings.Index(name[start:], ".")
+	if i < 0 {
+		return name, ""
+	}
+	return name[:start+i], name[start+i:]
+}
+
+// localizedPath returns the path of the variant of a file for locale, e.g.
+// docs/README.de.md.tmpl for docs/README.md.tmpl.
+func localizedPath(p, locale string) string {
+	dir, name := path.Split(p)
+	stem, ext := splitLocaleName(name)
+	return dir + stem + "." + locale + ext
+}
+
+// isLocaleVariant reports whether a file name is the variant of another file
+// for one of the given locales.
+func isLocaleVariant(name string, locales []string) bool {
+	_, ext := splitLocaleName(name)
+	for _, locale := range locales {
+		rest, ok := strings.CutPrefix(ext, "."+locale)
+		if ok && (rest == "" || strings.HasPrefix(rest, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// withLocale returns a renderer that renders the variant of each file for the
+// most specific locale of locale's chain that the template declares and the
+// file has, and skips the variants of files in directories. It returns r
+// itself when the template declares no locales.
+func (r *Renderer) withLocale(declared []string, locale string) *Renderer {
+	if len(declared) == 0 {
+		return r
+	}
+
+	nr := *r
+	nr.variants = declared
+	nr.locales = nil
+	for _, l := range LocaleChain(locale) {
+		if slices.Contains(declared, l) {
+			nr.locales = append(nr.locales, l)
+		}
+	}
+	return &nr
+}
+
+// localize returns the source of a file in the renderer's locale: its most
+// specific variant that exists, else the file itself.
+func (r *Renderer) localize(fsys fs.FS, srcPath string, info fs.FileInfo) (string, fs.FileInfo) {
+	for _, locale := range r.locales {
+		variant := localizedPath(srcPath, locale)
+		if vi, err := fs.Stat(fsys, variant); err == nil && !vi.IsDir() {
+			return variant, vi
+		}
+	}
+	return srcPath, info
+}
@@ -0,0 +1,42 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleChain(t *testing.T) {
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh"}, LocaleChain("zh-Hant-TW"))
+	assert.Equal(t, []string{"pt_BR", "pt"}, LocaleChain("pt_BR"))
+	assert.Equal(t, []string{"de"}, LocaleChain("de"))
+	assert.Empty(t, LocaleChain(""))
+}
+
+func TestLocalizedPath(t *testing.T) {
+	assert.Equal(t, "docs/README.de.md.tmpl", localizedPath("docs/README.md.tmpl", "de"))
+	assert.Equal(t, ".env.pt-BR.example", localizedPath(".env.example", "pt-BR"))
+	assert.Equal(t, ".gitignore.de", localizedPath(".gitignore", "de"))
+	assert.Equal(t, "Makefile.de", localizedPath("Makefile", "de"))
+}
+
+func TestIsLocaleVariant(t *testing.T) {
+	locales := []string{"de", "pt-BR"}
+
+	assert.True(t, isLocaleVariant("README.de.md.tmpl", locales))
+	assert.True(t, isLocaleVariant("README.pt-BR.md", locales))
+	assert.True(t, isLocaleVariant("Makefile.de", locales))
+	assert.False(t, isLocaleVariant("README.md.tmpl", locales))
+	assert.False(t, isLocaleVariant("README.pt.md", locales), "pt is not declared")
+	assert.False(t, isLocaleVariant("config.dev.yaml", locales))
+	assert.False(t, isLocaleVariant("README.de.md", nil))
+}
+
+func TestIsLocale(t *testing.T) {
+	for _, valid := range []string{"de", "pt-BR", "pt_BR", "zh-Hant-TW", "gsw"} {
+		assert.True(t, IsLocale(valid), valid)
+	}
+	for _, invalid := range []string{"", "d", "german", "de-", "de.md", "../de"} {
+		assert.False(t, IsLocale(invalid), invalid)
+	}
+}
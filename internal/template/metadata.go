@@ -0,0 +1,116 @@
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// licenseIDPattern matches an SPDX license identifier, or a license reference
+// such as LicenseRef-Company, with an optional "+" for later versions.
+var licenseIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*\+?$`)
+
+// Metadata returns the identification and description fields of the template.
+func (t *Template) Metadata() *Metadata {
+	return &Metadata{
+		Name:        t.Name,
+		Type:        t.Type,
+		Version:     t.Version,
+		Description: t.Description,
+		Tags:        t.Tags,
+		Keywords:    t.Keywords,
+		Author:      t.Author,
+		License:     t.License,
+		Homepage:    t.Homepage,
+	}
+}
+
+// IsLicenseExpression reports whether s is an SPDX license expression: license
+// identifiers joined with AND, OR, and WITH, optionally grouped in
+// parentheses, e.g. "MIT" or "(MIT OR Apache-2.0) AND BSD-3-Clause".
+// Identifiers are not checked against the SPDX license list.
+func IsLicenseExpression(s string) bool {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s))
+	if len(tokens) == 0 {
+		return false
+	}
+
+	depth := 0
+	operand := true // Whether an identifier or "(" is expected next
+	for _, token := range tokens {
+		switch token {
+		case "(":
+			if !operand {
+				return false
+			}
+			depth++
+		case ")":
+			if operand || depth == 0 {
+				return false
+			}
+			depth--
+		case "AND", "OR", "WITH":
+			if operand {
+				return false
+			}
+			operand = true
+		default:
+			if !operand || !licenseIDPattern.MatchString(token) {
+				return false
+			}
+			operand = false
+		}
+	}
+	return depth == 0 && !operand
+}
+
+// validateMetadata checks the sharing metadata of a template: tags and
+// keywords are distinct words, the license is an SPDX expression, and the
+// homepage is an http or https URL.
+func validateMetadata(meta *Metadata) []error {
+	var errs []error
+
+	errs = append(errs, validateWords("tags", meta.Tags)...)
+	errs = append(errs, validateWords("keywords", meta.Keywords)...)
+
+	if strings.ContainsAny(meta.Author, "\r\n") {
+		errs = append(errs, fmt.Errorf("author: must be a single line"))
+	}
+
+	if meta.License != "" && !IsLicenseExpression(meta.License) {
+		errs = append(errs, fmt.Errorf("license %q: expected an SPDX license expression such as MIT or \"MIT OR Apache-2.0\"",
+			meta.License))
+	}
+
+	if meta.Homepage != "" {
+		u, err := url.Parse(meta.Homepage)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("homepage %q: expected an http or https URL", meta.Homepage))
+		}
+	}
+
+	return errs
+}
+
+// validateWords checks that the tags or keywords of a template are non-empty,
+// free of whitespace and commas, which separate them on the command line, and
+// distinct regardless of case, as they are matched.
+func validateWords(field string, words []string) []error {
+	var errs []error
+
+	seen := make(map[string]bool, len(words))
+	for i, word := range words {
+		switch {
+		case word == "":
+			errs = append(errs, fmt.Errorf("%s[%d]: must not be empty", field, i))
+		case strings.ContainsAny(word, ", \t\r\n"):
+			errs = append(errs, fmt.Errorf("%s[%d]: %q must not contain spaces or commas", field, i, word))
+		case seen[strings.ToLower(word)]:
+			errs = append(errs, fmt.Errorf("%s[%d]: duplicate %q", field, i, word))
+		}
+		seen[strings.ToLower(word)] = true
+	}
+
+	return errs
+}
@@ -0,0 +1,35 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLicenseExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"MIT", true},
+		{"GPL-2.0+", true},
+		{"LicenseRef-Company", true},
+		{"MIT OR Apache-2.0", true},
+		{"Apache-2.0 WITH LLVM-exception", true},
+		{"(MIT OR Apache-2.0) AND BSD-3-Clause", true},
+		{"", false},
+		{"MIT Apache-2.0", false},
+		{"MIT or Apache-2.0", false},
+		{"AND MIT", false},
+		{"(MIT OR Apache-2.0", false},
+		{"MIT)", false},
+		{"()", false},
+		{"Proprietary, all rights reserved", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsLicenseExpression(tt.expr))
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the newest template.yaml schema. A template without a
+// schema field has schema 1.
+const SchemaVersion = 2
+
+// Migration upgrades a template.yaml document from schema From to From+1.
+// It edits the text of the document in place rather than encoding it again,
+// so that comments, blank lines, and quoting survive.
+type Migration struct {
+	From        int
+	Description string
+	edit        func(doc *yaml.Node, e *docEdits) error
+}
+
+// migrations holds one migration per schema version, in order.
+var migrations = []Migration{
+	{
+		From:        1,
+		Description: "post-init commands are mappings with a command field",
+		edit:        migratePostInitCommands,
+	},
+}
+
+// Migrations returns the migrations from schema 1 to SchemaVersion.
+func Migrations() []Migration {
+	return slices.Clone(migrations)
+}
+
+// MigrationResult is the outcome of migrating a template.yaml document.
+type MigrationResult struct {
+	From    int      // Schema of the document before
+	To      int      // Schema of the document after
+	Changes []string // What was rewritten, in document order
+	Content []byte   // Migrated document; the original when nothing changed
+}
+
+// Changed reports whether the document was rewritten.
+func (r *MigrationResult) Changed() bool {
+	return r.From != r.To || len(r.Changes) > 0
+}
+
+// Migrate rewrites a template.yaml document to SchemaVersion, applying the
+// migration of every schema from the document's own, and sets its schema
+// field. A document of a newer schema than this version supports is an
+// *UnsupportedSchemaError.
+func Migrate(data []byte) (*MigrationResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse template YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode ||
+		len(doc.Content[0].Content) == 0 {
+		return nil, fmt.Errorf("template YAML must be a mapping")
+	}
+	root := doc.Content[0]
+
+	_, schemaValue := mappingValue(root, "schema")
+	from := 1
+	if schemaValue != nil {
+		n, err := strconv.Atoi(schemaValue.Value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("line %d: schema must be a positive integer", schemaValue.Line)
+		}
+		from = n
+	}
+	if from > SchemaVersion {
+		name := ""
+		if _, v := mappingValue(root, "name"); v != nil {
+			name = v.Value
+		}
+		return nil, &UnsupportedSchemaError{Template: name, Schema: from}
+	}
+
+	result := &MigrationResult{From: from, To: SchemaVersion, Content: data}
+	if from == SchemaVersion {
+		return result, nil
+	}
+
+	e := newDocEdits(data)
+	for _, m := range migrations {
+		if m.From < from {
+			continue
+		}
+		if err := m.edit(root, e); err != nil {
+			return nil, fmt.Errorf("migrate schema %d to %d: %w", m.From, m.From+1, err)
+		}
+	}
+
+	stamp := fmt.Sprintf("schema: %d", SchemaVersion)
+	if schemaValue != nil {
+		e.replace(schemaValue, strconv.Itoa(SchemaVersion))
+	} else {
+		// The schema goes after the version when it is a plain line.
+		line := root.Content[0].Line
+		if _, version := mappingValue(root, "version"); version != nil && version.Kind == yaml.ScalarNode &&
+			version.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			line = version.Line + 1
+		}
+		e.insertLine(line, stamp)
+	}
+
+	result.Changes = e.changes
+	result.Content = e.apply()
+	return result, nil
+}
+
+// migratePostInitCommands turns post-init commands written as plain strings
+// into mappings with a command field.
+func migratePostInitCommands(root *yaml.Node, e *docEdits) error {
+	_, postInit := mappingValue(root, "post_init")
+	if postInit == nil || postInit.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for i, item := range postInit.Content {
+		if item.Kind != yaml.ScalarNode {
+			continue
+		}
+		if item.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return fmt.Errorf("line %d: post_init[%d] is a block scalar; rewrite it as command: by hand", item.Line, i)
+		}
+		e.insert(item, "command: ", fmt.Sprintf("post_init[%d]: wrote %q as a command mapping", i, item.Value))
+	}
+	return nil
+}
+
+// mappingValue returns the key and value nodes of a key in a mapping.
+func mappingValue(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// docEdits collects text edits of a document at node positions and applies
+// them together.
+type docEdits struct {
+	lines   [][]byte
+	eol     string
+	edits   []docEdit
+	changes []string
+}
+
+type docEdit struct {
+	line, col int    // 1-based position of the edit
+	remove    int    // Bytes removed at the position
+	text      string // Text inserted at the position
+	wholeLine bool   // text is inserted as a line of its own before line
+}
+
+func newDocEdits(data []byte) *docEdits {
+	eol := "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		eol = "\r\n"
+	}
+	return &docEdits{lines: bytes.SplitAfter(data, []byte("\n")), eol: eol}
+}
+
+// insert inserts text before a node and records the change it makes.
+func (e *docEdits) insert(node *yaml.Node, text, change string) {
+	e.edits = append(e.edits, docEdit{line: node.Line, col: node.Column, text: text})
+	e.changes = append(e.changes, change)
+}
+
+// replace replaces the value of a plain scalar node.
+func (e *docEdits) replace(node *yaml.Node, text string) {
+	e.edits = append(e.edits, docEdit{line: node.Line, col: node.Column, remove: len(node.Value), text: text})
+}
+
+// insertLine inserts a line before the given line; a line past the end is
+// appended.
+func (e *docEdits) insertLine(line int, text string) {
+	e.edits = append(e.edits, docEdit{line: line, text: text, wholeLine: true})
+}
+
+// apply returns the document with every edit applied. Edits are applied from
+// the end of the document so that earlier positions stay valid.
+func (e *docEdits) apply() []byte {
+	lines := slices.Clone(e.lines)
+	edits := slices.Clone(e.edits)
+	slices.SortStableFunc(edits, func(a, b docEdit) int {
+		if a.line != b.line {
+			return b.line - a.line
+		}
+		return b.col - a.col
+	})
+
+	for _, ed := range edits {
+		i := ed.line - 1
+		if ed.wholeLine {
+			if i > len(lines) {
+				i = len(lines)
+			}
+			if i > 0 && i == len(lines) && !bytes.HasSuffix(lines[i-1], []byte("\n")) {
+				lines[i-1] = append(slices.Clone(lines[i-1]), e.eol...)
+			}
+			lines = slices.Insert(lines, i, []byte(ed.text+e.eol))
+			continue
+		}
+
+		line := lines[i]
+		col := byteColumn(line, ed.col)
+		edited := make([]byte, 0, len(line)+len(ed.text))
+		edited = append(edited, line[:col]...)
+		edited = append(edited, ed.text...)
+		edited = append(edited, line[min(col+ed.remove, len(line)):]...)
+		lines[i] = edited
+	}
+
+	return bytes.Join(lines, nil)
+}
+
+// byteColumn returns the byte offset of a 1-based column of runes in line.
+func byteColumn(line []byte, col int) int {
+	offset := 0
+	for n := 1; n < col && offset < len(line); n++ {
+		_, size := utf8.DecodeRune(line[offset:])
+		offset += size
+	}
+	return offset
+}
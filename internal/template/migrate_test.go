@@ -0,0 +1,90 @@
+package template
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrate(t *testing.T) {
+	data := `# Worker template
+name: worker
+type: project
+version: 1.0.0 # bump on release
+description: "A worker"
+
+post_init:
+  - go mod tidy   # first
+  - command: git init
+  - "echo 'done'"
+`
+
+	result, err := Migrate([]byte(data))
+	require.NoError(t, err)
+	assert.True(t, result.Changed())
+	assert.Equal(t, 1, result.From)
+	assert.Equal(t, SchemaVersion, result.To)
+	assert.Len(t, result.Changes, 2)
+	assert.Equal(t, `# Worker template
+name: worker
+type: project
+version: 1.0.0 # bump on release
+schema: 2
+description: "A worker"
+
+post_init:
+  - command: go mod tidy   # first
+  - command: git init
+  - command: "echo 'done'"
+`, string(result.Content))
+
+	again, err := Migrate(result.Content)
+	require.NoError(t, err)
+	assert.False(t, again.Changed())
+	assert.Equal(t, string(result.Content), string(again.Content))
+}
+
+func TestMigrate_FlowSequenceAndSchemaField(t *testing.T) {
+	result, err := Migrate([]byte("schema: 1\nname: a\npost_init: [make, \"make test\"]"))
+	require.NoError(t, err)
+	assert.Equal(t, "schema: 2\nname: a\npost_init: [command: make, command: \"make test\"]", string(result.Content))
+
+	var tmpl struct {
+		PostInit []PostInit `yaml:"post_init"`
+	}
+	require.NoError(t, yaml.Unmarshal(result.Content, &tmpl))
+	assert.Equal(t, []PostInit{{Command: "make"}, {Command: "make test"}}, tmpl.PostInit)
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	_, err := Migrate([]byte("name: a\nschema: 99\n"))
+	var schemaErr *UnsupportedSchemaError
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, "a", schemaErr.Template)
+
+	_, err = Migrate([]byte("name: a\npost_init:\n  - |\n    make\n"))
+	require.ErrorContains(t, err, "post_init[0] is a block scalar")
+}
+
+func TestLoader_RejectsNewerSchema(t *testing.T) {
+	fsys := fstest.MapFS{
+		FileName: {Data: []byte("name: a\ntype: feature\nversion: 1.0.0\nschema: 3\nhooks: {}\n")},
+	}
+
+	_, err := NewLoader().Load(fsys, ".")
+	var schemaErr *UnsupportedSchemaError
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, 3, schemaErr.Schema)
+}
+
+func TestLoader_SuggestsMigration(t *testing.T) {
+	fsys := fstest.MapFS{
+		FileName: {Data: []byte("name: a\ntype: feature\nversion: 1.0.0\npost_init:\n  - make\n")},
+	}
+
+	_, err := NewLoader().Load(fsys, ".")
+	require.ErrorContains(t, err, "blueprint template migrate")
+}
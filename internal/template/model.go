@@ -3,6 +3,11 @@ package template
 import (
 	"fmt"
 	"io/fs"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // Type represents the semantic type of a template
@@ -14,6 +19,16 @@ const (
 	TypeComponent Type = "component"
 )
 
+// namePattern matches valid template names: lowercase letters and digits,
+// with single dashes between them.
+var namePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// IsValidName reports whether name can be used as a template name. Valid
+// names are also safe to use as directory names.
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
 // VariableType represents the type of input expected for a variable
 type VariableType string
 
@@ -23,6 +38,44 @@ const (
 	VariableTypeBool        VariableType = "bool"
 	VariableTypeSelect      VariableType = "select"
 	VariableTypeMultiSelect VariableType = "multiselect"
+	VariableTypeSecret      VariableType = "secret" // A string typed with masked input and never recorded
+)
+
+// CollisionPolicy decides what happens when an included template renders a
+// file to a path that another template in the tree already renders to.
+type CollisionPolicy string
+
+const (
+	// CollisionPolicyError fails scaffolding. It is the default policy.
+	CollisionPolicyError CollisionPolicy = "error"
+	// CollisionPolicySkip keeps the existing file and drops the included one.
+	CollisionPolicySkip CollisionPolicy = "skip"
+	// CollisionPolicyOverride replaces the existing file with the included one.
+	CollisionPolicyOverride CollisionPolicy = "override"
+)
+
+// FileTarget is the directory the destination of a file is relative to.
+type FileTarget string
+
+const (
+	// TargetProject writes the file into the project. It is the default target.
+	TargetProject FileTarget = "project"
+	// TargetHome writes the file relative to the user's home directory.
+	TargetHome FileTarget = "home"
+	// TargetXDGConfig writes the file relative to $XDG_CONFIG_HOME, or
+	// ~/.config when it is not set.
+	TargetXDGConfig FileTarget = "xdg-config"
+)
+
+// FileAction is what is done with a rendered file.
+type FileAction string
+
+const (
+	// ActionCreate writes the rendered file whole. It is the default action.
+	ActionCreate FileAction = "create"
+	// ActionInject inserts the rendered content into the existing file at
+	// dest, at the line of an anchor comment.
+	ActionInject FileAction = "inject"
 )
 
 // VariableRole represents the semantic role of a variable.
@@ -31,20 +84,49 @@ type VariableRole string
 const (
 	// RoleProjectName is the role for the project name variable.
 	RoleProjectName VariableRole = "project_name"
+	// RoleLocale is the role for the variable that selects the locale
+	// variants of a template's files.
+	RoleLocale VariableRole = "locale"
 )
 
 // Template represents a complete template definition
 type Template struct {
-	Name         string     `yaml:"name" validate:"required"`
-	Type         Type       `yaml:"type" validate:"required,oneof=project feature component"`
-	Version      string     `yaml:"version" validate:"required"`
-	Description  string     `yaml:"description"`
-	Tags         []string   `yaml:"tags,omitempty"`
-	Variables    []Variable `yaml:"variables,omitempty" validate:"dive"`
-	Includes     []Include  `yaml:"includes,omitempty" validate:"dive"`
-	Dependencies []string   `yaml:"dependencies,omitempty"`
-	Files        []File     `yaml:"files,omitempty" validate:"dive"`
-	PostInit     []PostInit `yaml:"post_init,omitempty" validate:"dive"`
+	Name         string               `yaml:"name" validate:"required"`
+	Type         Type                 `yaml:"type" validate:"required,oneof=project feature component"`
+	Version      string               `yaml:"version" validate:"required"`
+	Schema       int                  `yaml:"schema,omitempty" validate:"omitempty,min=1"` // Version of the template.yaml schema; 1 when unset
+	Description  string               `yaml:"description"`
+	Tags         []string             `yaml:"tags,omitempty"`
+	Keywords     []string             `yaml:"keywords,omitempty"` // Search terms beyond the tags
+	Author       string               `yaml:"author,omitempty"`   // e.g. "Jane Doe <jane@example.com>"
+	License      string               `yaml:"license,omitempty"`  // SPDX license expression, e.g. "MIT OR Apache-2.0"
+	Homepage     string               `yaml:"homepage,omitempty"` // http(s) URL of the template's documentation or repository
+	Variables    []Variable           `yaml:"variables,omitempty" validate:"dive"`
+	Deprecated   []DeprecatedVariable `yaml:"deprecated_variables,omitempty" validate:"dive"`
+	Includes     []Include            `yaml:"includes,omitempty" validate:"dive"`
+	Dependencies []string             `yaml:"dependencies,omitempty"`
+	Files        []File               `yaml:"files,omitempty" validate:"dive"`
+	PostInit     []PostInit           `yaml:"post_init,omitempty" validate:"dive"`
+	Env          []EnvVar             `yaml:"env,omitempty" validate:"dive"`
+	Clean        []string             `yaml:"clean,omitempty"`
+	Functions    []string             `yaml:"functions,omitempty"`  // Optional function libraries used by the files
+	Delimiters   []string             `yaml:"delimiters,omitempty"` // Action delimiters of the file contents, e.g. ["[[", "]]"]
+	Go           *GoRequirement       `yaml:"go,omitempty"`         // Go versions the generated project supports
+	Locales      []string             `yaml:"locales,omitempty"`    // Locales files have variants for, e.g. ["de", "pt-BR"]
+	Requires     *Requirements        `yaml:"requires,omitempty"`   // Projects a feature or component can be added to, and tools the template needs
+	Verify       []string             `yaml:"verify,omitempty"`     // Commands that check a generated project, run by blueprint smoke
+
+	GoEdits []GoEdit `yaml:"go_edits,omitempty" validate:"dive"` // Changes to Go files of the project made through their syntax tree
+
+	AllowOutsideOutput bool `yaml:"allow_outside_output,omitempty"` // Files may be written outside the output directory
+
+	MinBlueprintVersion string `yaml:"min_blueprint_version,omitempty"` // Oldest Blueprint release that can use the template
+
+	LineEndings  LineEnding `yaml:"line_endings,omitempty" validate:"omitempty,oneof=lf crlf native"` // Line terminator of the generated text files; kept as rendered if empty
+	FinalNewline bool       `yaml:"final_newline,omitempty"`                                          // Terminate the last line of every generated text file
+
+	LicenseHeader string `yaml:"license_header,omitempty"`
+	NextSteps     string `yaml:"next_steps,omitempty"` // Shown after scaffolding succeeds
 }
 
 // Metadata represents a subset of Template containing only identification and description fields.
@@ -54,6 +136,10 @@ type Metadata struct {
 	Version     string   `yaml:"version" validate:"required"`
 	Description string   `yaml:"description"`
 	Tags        []string `yaml:"tags,omitempty"`
+	Keywords    []string `yaml:"keywords,omitempty"`
+	Author      string   `yaml:"author,omitempty"`
+	License     string   `yaml:"license,omitempty"`
+	Homepage    string   `yaml:"homepage,omitempty"`
 }
 
 // VariableByRole returns the variable with the given role.
@@ -66,6 +152,26 @@ func (t *Template) VariableByRole(role VariableRole) (*Variable, error) {
 	return nil, fmt.Errorf("template does not have a variable with role %s", role)
 }
 
+// RenamedVariable returns the current name of a deprecated variable, or
+// false if name is not deprecated.
+func (t *Template) RenamedVariable(name string) (string, bool) {
+	for _, d := range t.Deprecated {
+		if d.Name == name {
+			return d.RenamedTo, true
+		}
+	}
+	return "", false
+}
+
+// DelimitersFor returns the action delimiters of the contents of file: the
+// file's own, else the template's, else nil for the default {{ and }}.
+func (t *Template) DelimitersFor(file File) []string {
+	if len(file.Delimiters) > 0 {
+		return file.Delimiters
+	}
+	return t.Delimiters
+}
+
 // ProjectName returns the project name from the context.
 func (t *Template) ProjectName(ctx *Context) (string, error) {
 	v, err := t.VariableByRole(RoleProjectName)
@@ -87,14 +193,91 @@ func (t *Template) ProjectName(ctx *Context) (string, error) {
 }
 
 // RenderedFile represents a file that has been rendered but not yet written to disk.
+// A planned file knows its path but not its content, which Load renders on
+// demand so that large trees never need to be held in memory at once.
 type RenderedFile struct {
 	Path    string
-	Content []byte
+	Content []byte      // Nil for a planned file until the result is loaded
+	Source  string      // Source path within the template filesystem
+	Mode    fs.FileMode // Permissions to write the file with; 0 uses the writer default
+	Binary  bool        // Content is binary and copied verbatim; only set once loaded
+	Target  FileTarget  // Directory Path is relative to; empty for the project
+	Anchor  string      // Anchor the content is injected at; empty for a file that is written whole
+	GoEdit  *GoEdit     // Change made to the Go file at Path instead of writing content; nil for other files
+
+	Injected []Injection // Snippets other nodes of the run injected into the content
+
+	load func() ([]byte, error) // Renders the content of a planned file
+}
+
+// Load returns the content of the file. The content of a planned file is
+// rendered on every call and not retained.
+func (f RenderedFile) Load() ([]byte, error) {
+	if f.load == nil {
+		return f.Content, nil
+	}
+	return f.load()
+}
+
+// transform applies fn to the content of the file. For a planned file it is
+// applied whenever the content is loaded.
+func (f *RenderedFile) transform(fn func(content []byte) []byte) {
+	if f.load == nil {
+		f.Content = fn(f.Content)
+		return
+	}
+
+	load := f.load
+	f.load = func() ([]byte, error) {
+		content, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return fn(content), nil
+	}
+}
+
+// Injection is a snippet that a node of the tree injected into a file.
+type Injection struct {
+	Node    string // ID of the node the snippet belongs to
+	Snippet RenderedFile
 }
 
 // RenderResult represents the result of rendering a template tree.
 type RenderResult struct {
-	Files map[string][]RenderedFile
+	Files      map[string][]RenderedFile
+	Injections map[string][]RenderedFile // Snippets to inject into files that exist, by node ID
+	Warnings   []Warning
+}
+
+// Load renders the content of every planned file and keeps it in memory.
+func (r *RenderResult) Load() error {
+	for _, id := range slices.Sorted(maps.Keys(r.Files)) {
+		files := r.Files[id]
+		for i := range files {
+			content, err := files[i].Load()
+			if err != nil {
+				return err
+			}
+			files[i].Content = content
+			files[i].Binary = IsBinary(content)
+			files[i].load = nil
+		}
+	}
+	return nil
+}
+
+// Warning is a non-fatal issue found while processing a template tree.
+type Warning struct {
+	Template string
+	Message  string
+}
+
+func (w Warning) String() string {
+	if w.Template == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.Template, w.Message)
 }
 
 // AllFiles returns a flat slice of all rendered files.
@@ -116,6 +299,12 @@ type TemplateNode struct {
 	Children  []*TemplateNode
 	Mount     string
 	Inherited map[string]string
+	Mandated  bool            // Composed by organization policy rather than template choice
+	Collision CollisionPolicy // Policy for files colliding with earlier nodes
+
+	// Versions records the versions chosen for templates that includes
+	// constrain. It is only set on the root node.
+	Versions []VersionResolution
 }
 
 const rootNodeID = "0"
@@ -125,6 +314,18 @@ func (n *TemplateNode) IsRootNode() bool {
 	return n != nil && n.ID == rootNodeID
 }
 
+// ParentID returns the ID of the node's parent, or an empty string for the root node.
+func (n *TemplateNode) ParentID() string {
+	if n == nil {
+		return ""
+	}
+	i := strings.LastIndex(n.ID, ".")
+	if i < 0 {
+		return ""
+	}
+	return n.ID[:i]
+}
+
 // RequiredVariables returns the variables that need input for this node.
 // Variables inherited from the parent are excluded.
 func (n *TemplateNode) RequiredVariables() []Variable {
@@ -146,31 +347,117 @@ func (n *TemplateNode) RequiredVariables() []Variable {
 // ConfirmIncludes is a function that decides which optional includes should be loaded.
 type ConfirmIncludes func(includes []Include) ([]Include, error)
 
+// CollectVariables is a function that collects the variables of a node while the tree is composed.
+type CollectVariables func(node *TemplateNode) (*Context, error)
+
 // RenderContexts maps a template name to its specific rendering context.
 type RenderContexts map[string]*Context
 
 // Variable represents a user-configurable variable with an interactive prompt
 type Variable struct {
+	Use     string       `yaml:"use,omitempty"` // Shared definition, as <library>/<variable>, that the other fields override
 	Name    string       `yaml:"name" validate:"required"`
 	Prompt  string       `yaml:"prompt" validate:"required"`
-	Type    VariableType `yaml:"type" validate:"required,oneof=string int bool select multiselect"`
+	Type    VariableType `yaml:"type" validate:"required,oneof=string int bool select multiselect secret"`
 	Role    VariableRole `yaml:"role,omitempty"`
 	Default any          `yaml:"default,omitempty"`
-	Options []string     `yaml:"options,omitempty" validate:"required_if=Type select,required_if=Type multiselect"`
+	Options []Option     `yaml:"options,omitempty" validate:"required_if=Type select,required_if=Type multiselect"`
+
+	Suggestions *Suggestions `yaml:"suggestions,omitempty"`                                                    // Completions offered for a string
+	Mask        InputMask    `yaml:"mask,omitempty" validate:"omitempty,oneof=lowercase uppercase slug snake"` // Applied to a string as it is typed
+	When        string       `yaml:"when,omitempty"`                                                           // Condition on earlier answers; the prompt is skipped when false
+	EnvOnly     bool         `yaml:"env_only,omitempty"`                                                       // A secret only passed to post-init commands, not rendered into files
+}
+
+// IsSecret reports whether the variable holds a secret.
+func (v Variable) IsSecret() bool {
+	return v.Type == VariableTypeSecret
+}
+
+// EnvName returns the environment variable a secret is passed to post-init
+// commands as: its name in upper case.
+func (v Variable) EnvName() string {
+	return strings.ToUpper(v.Name)
+}
+
+// OptionValues returns the values of the variable's options.
+func (v Variable) OptionValues() []string {
+	values := make([]string, len(v.Options))
+	for i, opt := range v.Options {
+		values[i] = opt.Value
+	}
+	return values
+}
+
+// DeprecatedVariable maps a variable name a template no longer declares to
+// the variable that replaced it, so values given under the old name keep
+// working.
+type DeprecatedVariable struct {
+	Name      string `yaml:"name" validate:"required"`
+	RenamedTo string `yaml:"renamed_to" validate:"required"`
 }
 
 // Include represents another template to compose into this one
 type Include struct {
 	Name             string            `yaml:"name" validate:"required"`
+	Version          string            `yaml:"version,omitempty"` // Version constraint, e.g. ^1.2.0
 	EnabledByDefault bool              `yaml:"enabled_by_default"`
 	Mount            string            `yaml:"mount,omitempty"`
 	Inherits         map[string]string `yaml:"inherits,omitempty"`
+	When             string            `yaml:"when,omitempty"`
+	Collision        CollisionPolicy   `yaml:"collision,omitempty" validate:"omitempty,oneof=error skip override"`
+	Mandated         bool              `yaml:"-"` // Injected by organization policy
 }
 
 // File represents a template file to be rendered and written
 type File struct {
 	Src  string `yaml:"src" validate:"required"`
 	Dest string `yaml:"dest" validate:"required"`
+	When string `yaml:"when,omitempty"`
+	Mode string `yaml:"mode,omitempty"` // Octal permissions such as 0755
+
+	Target FileTarget `yaml:"target,omitempty" validate:"omitempty,oneof=project home xdg-config"` // Directory dest is relative to; project by default
+
+	Action FileAction `yaml:"action,omitempty" validate:"omitempty,oneof=create inject"` // What is done with the rendered file; create by default
+	Anchor string     `yaml:"anchor,omitempty"`                                          // Anchor comment an injected file is inserted at
+
+	Delimiters []string `yaml:"delimiters,omitempty"` // Overrides the template's delimiters
+
+	ForEach string `yaml:"for_each,omitempty"` // Multiselect variable the entry is rendered once per element of
+
+	Include []string `yaml:"include,omitempty"` // Globs selecting the files of a directory src that are rendered
+	Exclude []string `yaml:"exclude,omitempty"` // Globs of paths inside a directory src that are skipped
+}
+
+// GoEdit is a change to a Go file of the project that is made by parsing the
+// file, so that it does not depend on how the file is laid out. An edit makes
+// exactly one change: it adds an import, a struct field, or a statement.
+type GoEdit struct {
+	File string `yaml:"file" validate:"required"` // Go file to edit, relative to the template's output directory
+	When string `yaml:"when,omitempty"`
+
+	Import     string `yaml:"import,omitempty"`      // Import path to add
+	ImportName string `yaml:"import_name,omitempty"` // Name to import the path under, e.g. _ or chi
+
+	Struct string `yaml:"struct,omitempty"` // Struct type to add the field to
+	Field  string `yaml:"field,omitempty"`  // Field declaration, e.g. Logger *slog.Logger
+
+	Func      string `yaml:"func,omitempty"`      // Function, or Type.Method, to add the statement to
+	Statement string `yaml:"statement,omitempty"` // Statements added at the end of the function body, before a final return
+}
+
+// FileMode parses the octal permissions of the file. It returns 0 when no
+// mode is set.
+func (f File) FileMode() (fs.FileMode, error) {
+	if f.Mode == "" {
+		return 0, nil
+	}
+
+	mode, err := strconv.ParseUint(strings.TrimPrefix(f.Mode, "0o"), 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid mode %q: expected octal permissions such as 0755", f.Mode)
+	}
+	return fs.FileMode(mode), nil
 }
 
 // Context holds all resolved variables for template rendering
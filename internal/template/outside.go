@@ -0,0 +1,149 @@
+package template
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// OutsideFile is a rendered file whose path resolves outside the output root.
+type OutsideFile struct {
+	Template string
+	Path     string     // Path as rendered, joined with the node's directory or target
+	Target   FileTarget // Target of the file; empty for the project
+	Allowed  bool       // The file has a host target or its template sets allow_outside_output
+}
+
+// HostDirs are the directories files with a host target are written to.
+type HostDirs struct {
+	Home      string
+	XDGConfig string
+}
+
+// dir returns the directory of a host target.
+func (h HostDirs) dir(target FileTarget) string {
+	if target == TargetXDGConfig {
+		return h.XDGConfig
+	}
+	return h.Home
+}
+
+// OutputPath returns the path of a rendered file relative to the output
+// root, or the file path itself when it is absolute.
+func OutputPath(dir, filePath string) string {
+	if isAbsPath(filePath) {
+		return filePath
+	}
+	return path.Join(dir, filePath)
+}
+
+// ConfineOutputPaths checks that every rendered file stays inside the output
+// root. dirs maps node IDs to the directory, relative to the output root,
+// that the node's files are written to.
+//
+// A file escapes the root when its path is absolute, starts with ~, or climbs
+// above the root with "..". Files of templates that do not set
+// allow_outside_output are returned together as an *OutsideOutputError. The
+// files of templates that do are returned so that the caller can ask for
+// confirmation; a leading ~ in their paths is expanded to the home directory.
+//
+// Files with a home or xdg-config target are resolved against their host
+// directory and always returned for confirmation. They must stay inside that
+// directory.
+func (r *RenderResult) ConfineOutputPaths(tree *TemplateNode, dirs map[string]string, hosts HostDirs) ([]OutsideFile, error) {
+	var allowed, denied []OutsideFile
+
+	var walk func(node *TemplateNode) error
+	walk = func(node *TemplateNode) error {
+		files := r.Files[node.ID]
+		for i, file := range files {
+			if file.Target != "" && file.Target != TargetProject {
+				hostFile, err := resolveHostPath(node, file, hosts)
+				if err != nil {
+					return err
+				}
+				if !hostFile.Allowed {
+					denied = append(denied, hostFile)
+					continue
+				}
+				files[i].Path = hostFile.Path
+				allowed = append(allowed, hostFile)
+				continue
+			}
+
+			outPath, outside := resolveOutputPath(dirs[node.ID], file.Path)
+			if !outside {
+				continue
+			}
+
+			if !node.Template.AllowOutsideOutput {
+				denied = append(denied, OutsideFile{Template: node.Template.Name, Path: outPath})
+				continue
+			}
+
+			if outPath == "~" || strings.HasPrefix(outPath, "~/") {
+				if hosts.Home == "" {
+					return fmt.Errorf("template %s: cannot expand ~ in %s without a home directory", node.Template.Name, outPath)
+				}
+				outPath = path.Join(filepath.ToSlash(hosts.Home), strings.TrimPrefix(outPath, "~"))
+				files[i].Path = outPath
+			}
+			allowed = append(allowed, OutsideFile{Template: node.Template.Name, Path: outPath, Allowed: true})
+		}
+
+		for _, child := range node.Children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(tree); err != nil {
+		return nil, err
+	}
+
+	if len(denied) > 0 {
+		return nil, &OutsideOutputError{Files: denied}
+	}
+	return allowed, nil
+}
+
+// resolveHostPath joins the path of a file with a host target to the target's
+// directory. A path that is not local to the directory is not allowed.
+func resolveHostPath(node *TemplateNode, file RenderedFile, hosts HostDirs) (OutsideFile, error) {
+	f := OutsideFile{Template: node.Template.Name, Path: file.Path, Target: file.Target}
+
+	if isAbsPath(file.Path) || !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+		return f, nil
+	}
+
+	dir := hosts.dir(file.Target)
+	if dir == "" {
+		return f, fmt.Errorf("template %s: cannot write %s without a %s directory", node.Template.Name, file.Path, file.Target)
+	}
+
+	f.Path = path.Join(filepath.ToSlash(dir), file.Path)
+	f.Allowed = true
+	return f, nil
+}
+
+// resolveOutputPath joins a rendered file path with its node directory and
+// reports whether the result escapes the output root.
+func resolveOutputPath(dir, filePath string) (string, bool) {
+	if isAbsPath(filePath) {
+		return filePath, true
+	}
+	if filePath == "~" || strings.HasPrefix(filePath, "~/") {
+		return filePath, true
+	}
+
+	outPath := path.Join(dir, filePath)
+	return outPath, outPath == ".." || strings.HasPrefix(outPath, "../")
+}
+
+// isAbsPath reports whether a slash-separated path is absolute on any
+// platform, including Windows paths with a volume name.
+func isAbsPath(p string) bool {
+	return path.IsAbs(p) || filepath.IsAbs(filepath.FromSlash(p))
+}
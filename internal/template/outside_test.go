@@ -0,0 +1,93 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func outsideTree(allow bool) *TemplateNode {
+	return &TemplateNode{
+		ID:       "0",
+		Template: &Template{Name: "app"},
+		Children: []*TemplateNode{
+			{ID: "0.0", Template: &Template{Name: "dotfiles", AllowOutsideOutput: allow}},
+		},
+	}
+}
+
+func outsideResult(paths ...string) *RenderResult {
+	files := make([]RenderedFile, len(paths))
+	for i, p := range paths {
+		files[i] = RenderedFile{Path: p}
+	}
+	return &RenderResult{
+		Files: map[string][]RenderedFile{
+			"0":   {{Path: "main.go"}, {Path: "cmd/../README.md"}},
+			"0.0": files,
+		},
+	}
+}
+
+func TestConfineOutputPaths_InsideRoot(t *testing.T) {
+	result := outsideResult("sub/../config.yaml", "a..b")
+
+	outside, err := result.ConfineOutputPaths(outsideTree(false), map[string]string{"0.0": "svc"}, HostDirs{Home: "/home/me"})
+
+	require.NoError(t, err)
+	assert.Empty(t, outside)
+}
+
+func TestConfineOutputPaths_RejectsEscapingPaths(t *testing.T) {
+	result := outsideResult("../../etc/passwd", "/etc/hosts", "~/.bashrc")
+
+	_, err := result.ConfineOutputPaths(outsideTree(false), map[string]string{"0.0": "svc"}, HostDirs{Home: "/home/me"})
+
+	var outsideErr *OutsideOutputError
+	require.ErrorAs(t, err, &outsideErr)
+	require.Len(t, outsideErr.Files, 3)
+	assert.Equal(t, OutsideFile{Template: "dotfiles", Path: "../etc/passwd"}, outsideErr.Files[0])
+	assert.Equal(t, "/etc/hosts", outsideErr.Files[1].Path)
+	assert.Equal(t, "~/.bashrc", outsideErr.Files[2].Path)
+}
+
+func TestConfineOutputPaths_AllowedTemplate(t *testing.T) {
+	result := outsideResult("~/.config/app/config.yaml", "../shared.txt")
+
+	outside, err := result.ConfineOutputPaths(outsideTree(true), nil, HostDirs{Home: "/home/me"})
+
+	require.NoError(t, err)
+	require.Len(t, outside, 2)
+	assert.Equal(t, OutsideFile{Template: "dotfiles", Path: "/home/me/.config/app/config.yaml", Allowed: true}, outside[0])
+	assert.Equal(t, "../shared.txt", outside[1].Path)
+	assert.Equal(t, "/home/me/.config/app/config.yaml", result.Files["0.0"][0].Path)
+}
+
+func TestConfineOutputPaths_HostTargets(t *testing.T) {
+	result := outsideResult(".aliases", "app/config.yaml")
+	result.Files["0.0"][0].Target = TargetHome
+	result.Files["0.0"][1].Target = TargetXDGConfig
+
+	outside, err := result.ConfineOutputPaths(outsideTree(false), map[string]string{"0.0": "svc"}, HostDirs{
+		Home:      "/home/me",
+		XDGConfig: "/home/me/.config",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, outside, 2)
+	assert.Equal(t, OutsideFile{Template: "dotfiles", Path: "/home/me/.aliases", Target: TargetHome, Allowed: true}, outside[0])
+	assert.Equal(t, "/home/me/.config/app/config.yaml", result.Files["0.0"][1].Path)
+}
+
+func TestConfineOutputPaths_HostTargetMustStayInside(t *testing.T) {
+	result := outsideResult("../.aliases")
+	result.Files["0.0"][0].Target = TargetHome
+
+	_, err := result.ConfineOutputPaths(outsideTree(true), nil, HostDirs{Home: "/home/me"})
+
+	var outsideErr *OutsideOutputError
+	require.ErrorAs(t, err, &outsideErr)
+	require.Len(t, outsideErr.Files, 1)
+	assert.False(t, outsideErr.Files[0].Allowed)
+}
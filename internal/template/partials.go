@@ -0,0 +1,89 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"text/template"
+)
+
+// PartialsDir is the directory of a template whose .tmpl files are parsed
+// into every file the template renders, so they can be used with
+// {{ template "name" . }}.
+const PartialsDir = "partials"
+
+// partial is a named template shared by the files of a template.
+type partial struct {
+	name    string // File path inside PartialsDir without the .tmpl extension
+	source  string // Path of the partial in the template filesystem
+	content string
+}
+
+// loadPartials reads the partials of the template at dir. A template without
+// a partials directory has none.
+func loadPartials(fsys fs.FS, dir string) ([]partial, error) {
+	root := path.Join(dir, PartialsDir)
+
+	var partials []partial
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Type()&fs.ModeSymlink != 0 || !isTemplateFile(p) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		rel, err := relPath(root, p)
+		if err != nil {
+			return err
+		}
+
+		partials = append(partials, partial{
+			name:    stripTemplateExt(rel),
+			source:  p,
+			content: string(content),
+		})
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read partials of %s: %w", dir, err)
+	}
+
+	return partials, nil
+}
+
+// withPartials returns a renderer that parses the given partials into every
+// template it renders. It returns r itself when there are no partials.
+func (r *Renderer) withPartials(partials []partial) *Renderer {
+	if len(partials) == 0 {
+		return r
+	}
+
+	nr := *r
+	nr.partials = partials
+	return &nr
+}
+
+// parse parses content into tmpl together with the partials of the renderer.
+func (r *Renderer) parse(tmpl *template.Template, content string) (*template.Template, error) {
+	for _, p := range r.partials {
+		if _, err := tmpl.New(p.name).Parse(p.content); err != nil {
+			return nil, &RenderError{Name: p.source, Parse: true, Err: err}
+		}
+	}
+
+	if _, err := tmpl.Parse(content); err != nil {
+		return nil, &RenderError{Name: tmpl.Name(), Parse: true, Err: err}
+	}
+
+	return tmpl, nil
+}
@@ -2,8 +2,17 @@ package template
 
 // PostInit represents a command to run after scaffolding
 type PostInit struct {
-	Command string `yaml:"command" validate:"required"`
-	WorkDir string `yaml:"workdir,omitempty"`
+	Command    string `yaml:"command" validate:"required"`
+	WorkDir    string `yaml:"workdir,omitempty"`
+	Idempotent bool   `yaml:"idempotent,omitempty"` // Safe to run again after it completed
+}
+
+// EnvVar represents an environment variable required by post-init commands.
+type EnvVar struct {
+	Name     string `yaml:"name" validate:"required"`
+	Prompt   string `yaml:"prompt,omitempty"`
+	Default  string `yaml:"default,omitempty"`
+	Required bool   `yaml:"required"`
 }
 
 // AllPostInit recursively collects all post-init commands from the tree.
@@ -19,3 +28,26 @@ func (n *TemplateNode) collectPostInit(cmds *[]PostInit) {
 		child.collectPostInit(cmds)
 	}
 }
+
+// AllEnv recursively collects the environment variables required by post-init
+// commands in the tree. When a name is declared more than once, the first
+// declaration wins.
+func (n *TemplateNode) AllEnv() []EnvVar {
+	var env []EnvVar
+	seen := make(map[string]bool)
+	n.collectEnv(&env, seen)
+	return env
+}
+
+func (n *TemplateNode) collectEnv(env *[]EnvVar, seen map[string]bool) {
+	for _, e := range n.Template.Env {
+		if seen[e.Name] {
+			continue
+		}
+		seen[e.Name] = true
+		*env = append(*env, e)
+	}
+	for _, child := range n.Children {
+		child.collectEnv(env, seen)
+	}
+}
@@ -11,7 +11,14 @@ import (
 
 // Renderer handles rendering template files with variables
 type Renderer struct {
-	funcMap template.FuncMap
+	funcMap    template.FuncMap
+	leftDelim  string // Empty for the default "{{"
+	rightDelim string // Empty for the default "}}"
+	partials   []partial
+	locales    []string // Locales whose file variants are rendered, most specific first
+	variants   []string // Locales the template has file variants for
+
+	selects func(srcPath string, dir bool) bool // Reports whether a path inside a directory src is rendered; nil selects all
 }
 
 // NewRenderer creates a new template renderer
@@ -33,19 +40,25 @@ func (r *Renderer) Render(fsys fs.FS, templatePath string, ctx *Context) ([]byte
 
 // RenderString renders a template string with the given context
 func (r *Renderer) RenderString(content string, ctx *Context, name string) ([]byte, error) {
-	tmpl, err := template.New(name).Funcs(r.funcMap).Parse(content)
+	tmpl, err := r.parse(r.newTemplate(name), content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		return nil, err
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, ctx.Variables); err != nil {
-		return nil, fmt.Errorf("failed to execute template %s: %w", name, err)
+		return nil, &RenderError{Name: name, Err: err}
 	}
 
 	return buf.Bytes(), nil
 }
 
+// newTemplate creates an empty template with the renderer's functions and
+// delimiters.
+func (r *Renderer) newTemplate(name string) *template.Template {
+	return template.New(name).Delims(r.leftDelim, r.rightDelim).Funcs(r.funcMap)
+}
+
 // RenderPath renders a destination path template with the given context
 // This allows dynamic file paths like "{{ .package_name }}/main.go"
 func (r *Renderer) RenderPath(pathTemplate string, ctx *Context) (string, error) {
@@ -56,6 +69,28 @@ func (r *Renderer) RenderPath(pathTemplate string, ctx *Context) (string, error)
 	return string(rendered), nil
 }
 
+// EvaluateCondition renders a condition template with the given context and
+// reports whether the result is truthy. An empty condition is always true.
+// The rendered output is false when it is empty, "false", "0", "no" or
+// "<no value>"; any other output is true.
+func (r *Renderer) EvaluateCondition(condition string, ctx *Context) (bool, error) {
+	if strings.TrimSpace(condition) == "" {
+		return true, nil
+	}
+
+	rendered, err := r.RenderString(condition, ctx, "condition")
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(rendered))) {
+	case "", "false", "0", "no", "<no value>":
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
 // Copy reads a file and returns its content without template processing
 func (r *Renderer) Copy(fsys fs.FS, filePath string) ([]byte, error) {
 	content, err := fs.ReadFile(fsys, filePath)
@@ -68,6 +103,22 @@ func (r *Renderer) Copy(fsys fs.FS, filePath string) ([]byte, error) {
 // RenderAll renders all files from a template tree with the given contexts.
 // It walks the tree and renders files for each node with its corresponding context.
 func (r *Renderer) RenderAll(node *TemplateNode, contexts RenderContexts) (*RenderResult, error) {
+	result, err := r.PlanAll(node, contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.Load(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PlanAll walks a template tree like RenderAll and resolves the path, mode,
+// and source of every file, but leaves the content to be rendered on demand
+// by RenderedFile.Load.
+func (r *Renderer) PlanAll(node *TemplateNode, contexts RenderContexts) (*RenderResult, error) {
 	result := &RenderResult{
 		Files: make(map[string][]RenderedFile),
 	}
@@ -78,30 +129,104 @@ func (r *Renderer) RenderAll(node *TemplateNode, contexts RenderContexts) (*Rend
 	return result, nil
 }
 
-// renderNode recursively renders a node and its children.
+// renderNode recursively plans a node and its children. The files of a node
+// are rendered with the function libraries its template enables.
 func (r *Renderer) renderNode(node *TemplateNode, contexts RenderContexts, result *RenderResult) error {
 	ctx, ok := contexts[node.ID]
 	if !ok {
 		return fmt.Errorf("no context found for template %s (ID: %s)", node.Template.Name, node.ID)
 	}
 
-	var nodeFiles []RenderedFile
+	nr, err := r.withLibraries(node.Template.Functions)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", node.Template.Name, err)
+	}
+
+	partials, err := loadPartials(node.FS, node.Path)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", node.Template.Name, err)
+	}
+
+	var nodeFiles, nodeInjections []RenderedFile
 	for _, file := range node.Template.Files {
 		srcPath := path.Join(node.Path, file.Src)
 
-		destPath, err := r.RenderPath(file.Dest, ctx)
+		fileContexts, err := forEachContexts(file, ctx)
 		if err != nil {
-			return fmt.Errorf("failed to render destination path for %s: %w", srcPath, err)
+			return fmt.Errorf("%s: %w", srcPath, err)
+		}
+		for _, ctx := range fileContexts {
+			enabled, err := nr.EvaluateCondition(file.When, ctx)
+			if err != nil {
+				return fmt.Errorf("failed to evaluate condition for %s: %w", srcPath, err)
+			}
+			if !enabled {
+				continue
+			}
+
+			destPath, err := nr.RenderPath(file.Dest, ctx)
+			if err != nil {
+				return fmt.Errorf("failed to render destination path for %s: %w", srcPath, err)
+			}
+
+			mode, err := file.FileMode()
+			if err != nil {
+				return fmt.Errorf("%s: %w", srcPath, err)
+			}
+
+			fr := nr.withDelimiters(node.Template.DelimitersFor(file)).withPartials(partials).
+				withLocale(node.Template.Locales, node.Template.Locale(ctx)).withSelection(file, srcPath)
+			if file.Action == ActionInject {
+				first := len(nodeInjections)
+				if err := fr.processPath(node.FS, srcPath, destPath, mode, ctx, &nodeInjections, result); err != nil {
+					return err
+				}
+				for i := first; i < len(nodeInjections); i++ {
+					nodeInjections[i].Anchor = file.Anchor
+				}
+				continue
+			}
+
+			first := len(nodeFiles)
+			if err := fr.processPath(node.FS, srcPath, destPath, mode, ctx, &nodeFiles, result); err != nil {
+				return err
+			}
+			if file.Target != TargetProject {
+				for i := first; i < len(nodeFiles); i++ {
+					nodeFiles[i].Target = file.Target
+				}
+			}
+		}
+	}
+
+	er := nr.withDelimiters(node.Template.Delimiters)
+	for i, edit := range node.Template.GoEdits {
+		name := fmt.Sprintf("go_edits[%d]", i)
+		enabled, err := er.EvaluateCondition(edit.When, ctx)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate condition for %s: %w", name, err)
+		}
+		if !enabled {
+			continue
 		}
 
-		if err := r.processPath(node.FS, srcPath, destPath, ctx, &nodeFiles); err != nil {
+		rendered, err := er.renderGoEdit(edit, ctx, name)
+		if err != nil {
 			return err
 		}
+		nodeInjections = append(nodeInjections, RenderedFile{Path: rendered.File, GoEdit: rendered})
 	}
 
+	nodeFiles = dropDuplicateFiles(node, nodeFiles, result)
 	if len(nodeFiles) > 0 {
 		result.Files[node.ID] = nodeFiles
 	}
+	if len(nodeInjections) > 0 {
+		if result.Injections == nil {
+			result.Injections = make(map[string][]RenderedFile)
+		}
+		result.Injections[node.ID] = nodeInjections
+	}
 
 	for _, child := range node.Children {
 		if err := r.renderNode(child, contexts, result); err != nil {
@@ -112,22 +237,47 @@ func (r *Renderer) renderNode(node *TemplateNode, contexts RenderContexts, resul
 	return nil
 }
 
-// processPath processes a file or directory path recursively
-func (r *Renderer) processPath(fsys fs.FS, srcPath, destPath string, ctx *Context, results *[]RenderedFile) error {
+// renderGoEdit renders the fields of a Go edit with the given context.
+func (r *Renderer) renderGoEdit(edit GoEdit, ctx *Context, name string) (*GoEdit, error) {
+	rendered := edit
+	rendered.When = ""
+	for _, field := range []*string{
+		&rendered.File, &rendered.Import, &rendered.ImportName,
+		&rendered.Struct, &rendered.Field, &rendered.Func, &rendered.Statement,
+	} {
+		if *field == "" {
+			continue
+		}
+		text, err := r.RenderString(*field, ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		*field = string(text)
+	}
+	return &rendered, nil
+}
+
+// processPath processes a file or directory path recursively. A non-zero
+// mode applies to every file rendered from the path.
+func (r *Renderer) processPath(fsys fs.FS, srcPath, destPath string, mode fs.FileMode, ctx *Context, results *[]RenderedFile, result *RenderResult) error {
 	info, err := fs.Stat(fsys, srcPath)
 	if err != nil {
 		return fmt.Errorf("failed to stat %s: %w", srcPath, err)
 	}
 
 	if info.IsDir() {
-		return r.processDirectory(fsys, srcPath, destPath, ctx, results)
+		return r.processDirectory(fsys, srcPath, destPath, mode, ctx, results, result)
 	}
 
-	return r.processFile(fsys, srcPath, destPath, ctx, results)
+	return r.processFile(fsys, srcPath, destPath, info, mode, ctx, results)
 }
 
-// processDirectory recursively processes all files in a directory
-func (r *Renderer) processDirectory(fsys fs.FS, srcDir, destDir string, ctx *Context, results *[]RenderedFile) error {
+// processDirectory recursively processes all files in a directory.
+// Symbolic links inside the directory are skipped with a warning, locale
+// variants are rendered in place of the files they are variants of, and paths
+// the include and exclude patterns of the file entry do not select are
+// skipped.
+func (r *Renderer) processDirectory(fsys fs.FS, srcDir, destDir string, mode fs.FileMode, ctx *Context, results *[]RenderedFile, result *RenderResult) error {
 	entries, err := fs.ReadDir(fsys, srcDir)
 	if err != nil {
 		return fmt.Errorf("failed to read directory %s: %w", srcDir, err)
@@ -137,7 +287,20 @@ func (r *Renderer) processDirectory(fsys fs.FS, srcDir, destDir string, ctx *Con
 		srcPath := path.Join(srcDir, entry.Name())
 		destPath := path.Join(destDir, entry.Name())
 
-		if err := r.processPath(fsys, srcPath, destPath, ctx, results); err != nil {
+		if entry.Type()&fs.ModeSymlink != 0 {
+			result.Warnings = append(result.Warnings, Warning{
+				Message: fmt.Sprintf("skipped symbolic link %s", srcPath),
+			})
+			continue
+		}
+		if !entry.IsDir() && isLocaleVariant(entry.Name(), r.variants) {
+			continue
+		}
+		if r.selects != nil && !r.selects(srcPath, entry.IsDir()) {
+			continue
+		}
+
+		if err := r.processPath(fsys, srcPath, destPath, mode, ctx, results, result); err != nil {
 			return err
 		}
 	}
@@ -145,6 +308,27 @@ func (r *Renderer) processDirectory(fsys fs.FS, srcDir, destDir string, ctx *Con
 	return nil
 }
 
+// dropDuplicateFiles removes files rendered to a destination that an earlier
+// file of the same node already occupies, recording a warning for each.
+func dropDuplicateFiles(node *TemplateNode, files []RenderedFile, result *RenderResult) []RenderedFile {
+	seen := make(map[string]bool, len(files))
+	kept := files[:0]
+
+	for _, file := range files {
+		if seen[file.Path] {
+			result.Warnings = append(result.Warnings, Warning{
+				Template: node.Template.Name,
+				Message:  fmt.Sprintf("dropped duplicate file %s", file.Path),
+			})
+			continue
+		}
+		seen[file.Path] = true
+		kept = append(kept, file)
+	}
+
+	return kept
+}
+
 // isTemplateFile checks if the path has a .tmpl extension
 func isTemplateFile(path string) bool {
 	return strings.HasSuffix(path, ".tmpl")
@@ -155,38 +339,178 @@ func stripTemplateExt(path string) string {
 	return strings.TrimSuffix(path, ".tmpl")
 }
 
-// processFile processes a single file - renders .tmpl files, copies others
-func (r *Renderer) processFile(fsys fs.FS, srcPath, destPath string, ctx *Context, results *[]RenderedFile) error {
-	var content []byte
-	var err error
+// processFile plans a single file - .tmpl files are rendered, others copied.
+// Copied files keep the executable bit of their source unless mode is set.
+// The file is read from its variant for the renderer's locale, if it has one.
+// The front matter of a template file can skip the file or override its
+// destination, mode, and delimiters.
+func (r *Renderer) processFile(fsys fs.FS, srcPath, destPath string, info fs.FileInfo, mode fs.FileMode, ctx *Context, results *[]RenderedFile) error {
+	srcPath, info = r.localize(fsys, srcPath, info)
 
 	if isTemplateFile(srcPath) {
 		destPath = stripTemplateExt(destPath)
+	} else if mode == 0 && info.Mode().Perm()&0o111 != 0 {
+		mode = 0o755
+	}
 
-		content, err = r.Render(fsys, srcPath, ctx)
+	fm, err := readFrontMatter(fsys, srcPath)
+	if err != nil {
+		return err
+	}
+	if fm != nil {
+		// Like the fields of template.yaml, front matter uses the default
+		// delimiters.
+		pr := r.withDelimiters([]string{"{{", "}}"})
+		enabled, err := pr.EvaluateCondition(fm.When, ctx)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to evaluate front matter condition for %s: %w", srcPath, err)
 		}
-	} else {
-		content, err = r.Copy(fsys, srcPath)
-		if err != nil {
-			return err
+		if !enabled {
+			return nil
+		}
+		if fm.Dest != "" {
+			if destPath, err = pr.RenderPath(fm.Dest, ctx); err != nil {
+				return fmt.Errorf("failed to render front matter destination for %s: %w", srcPath, err)
+			}
+		}
+		if fm.Mode != "" {
+			if mode, err = fm.FileMode(); err != nil {
+				return fmt.Errorf("%s: front matter: %w", srcPath, err)
+			}
 		}
 	}
 
 	*results = append(*results, RenderedFile{
-		Path:    destPath,
-		Content: content,
+		Path:   destPath,
+		Source: srcPath,
+		Mode:   mode,
+		load: func() ([]byte, error) {
+			return r.renderFile(fsys, srcPath, ctx)
+		},
 	})
 
 	return nil
 }
 
+// renderFile returns the content of a file, rendering it when it is a
+// template. Binary files are always copied verbatim, even with a .tmpl
+// extension. The front matter of a template file is removed, and its
+// delimiters are used for the rest of the file.
+func (r *Renderer) renderFile(fsys fs.FS, srcPath string, ctx *Context) ([]byte, error) {
+	content, err := r.Copy(fsys, srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTemplateFile(srcPath) || IsBinary(content) {
+		return content, nil
+	}
+
+	fm, body, err := SplitFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", srcPath, err)
+	}
+	fr := r
+	if fm != nil {
+		fr = r.withDelimiters(fm.Delimiters)
+	}
+
+	return fr.RenderString(string(body), ctx, srcPath)
+}
+
+// RenderStandalone renders the content of a single template file outside of
+// any template, as the render command does to debug it. Every optional
+// function library is enabled. Front matter is removed and its delimiters
+// are used; its other fields have no effect.
+func (r *Renderer) RenderStandalone(content []byte, ctx *Context, name string) ([]byte, error) {
+	nr, err := r.withLibraries(FuncLibraries())
+	if err != nil {
+		return nil, err
+	}
+
+	fm, body, err := SplitFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if fm != nil {
+		nr = nr.withDelimiters(fm.Delimiters)
+	}
+
+	return nr.RenderString(string(body), ctx, name)
+}
+
 // AddFunc adds a custom function to the template function map
 func (r *Renderer) AddFunc(name string, fn any) {
 	r.funcMap[name] = fn
 }
 
+// EnableLibrary adds the functions of an optional library to the template
+// function map.
+func (r *Renderer) EnableLibrary(name string) error {
+	lib, ok := funcLibraries[name]
+	if !ok {
+		return fmt.Errorf("unknown function library %q (available: %s)", name, strings.Join(FuncLibraries(), ", "))
+	}
+	for fn, impl := range lib {
+		r.funcMap[fn] = impl
+	}
+	return nil
+}
+
+// withDelimiters returns a renderer that uses the given action delimiters for
+// file contents. It returns r itself when delims is empty.
+func (r *Renderer) withDelimiters(delims []string) *Renderer {
+	if len(delims) != 2 {
+		return r
+	}
+
+	nr := *r
+	nr.leftDelim, nr.rightDelim = delims[0], delims[1]
+	return &nr
+}
+
+// withSelection returns a renderer that renders only the paths inside the
+// directory root that file selects. It returns r itself when the file has no
+// include or exclude patterns.
+func (r *Renderer) withSelection(file File, root string) *Renderer {
+	if len(file.Include) == 0 && len(file.Exclude) == 0 {
+		return r
+	}
+
+	nr := *r
+	nr.selects = func(srcPath string, dir bool) bool {
+		return file.selectsUnder(root, srcPath, dir)
+	}
+	return &nr
+}
+
+// withLibraries returns a renderer that also has the functions of the given
+// libraries. It returns r itself when no libraries are given.
+func (r *Renderer) withLibraries(names []string) (*Renderer, error) {
+	if len(names) == 0 {
+		return r, nil
+	}
+
+	nr := &Renderer{
+		funcMap:    make(template.FuncMap, len(r.funcMap)),
+		leftDelim:  r.leftDelim,
+		rightDelim: r.rightDelim,
+		partials:   r.partials,
+		locales:    r.locales,
+		variants:   r.variants,
+		selects:    r.selects,
+	}
+	for fn, impl := range r.funcMap {
+		nr.funcMap[fn] = impl
+	}
+	for _, name := range names {
+		if err := nr.EnableLibrary(name); err != nil {
+			return nil, err
+		}
+	}
+	return nr, nil
+}
+
 // defaultFuncMap returns the default set of template functions
 func (r *Renderer) defaultFuncMap() template.FuncMap {
 	return template.FuncMap{
@@ -1,6 +1,7 @@
 package template
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -214,3 +215,506 @@ func TestRenderAll(t *testing.T) {
 	assert.Equal(t, "A=1", resMap["output/a.txt"])
 	assert.Equal(t, "B=2", resMap["output/b.txt"])
 }
+
+func TestEvaluateCondition(t *testing.T) {
+	r, _ := newTestRenderer(t)
+	ctx := testContext(map[string]any{
+		"enabled":  true,
+		"disabled": false,
+		"db":       "postgres",
+	})
+
+	cases := map[string]bool{
+		"":                              true,
+		"{{ .enabled }}":                true,
+		"{{ .disabled }}":               false,
+		"{{ .missing }}":                false,
+		`{{ eq .db "postgres" }}`:       true,
+		`{{ eq .db "mysql" }}`:          false,
+		`{{ if .enabled }}yes{{ end }}`: true,
+	}
+
+	for condition, expected := range cases {
+		got, err := r.EvaluateCondition(condition, ctx)
+		require.NoError(t, err, condition)
+		assert.Equal(t, expected, got, condition)
+	}
+}
+
+func TestRenderAll_SkipsFilesWhenConditionFalse(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "Dockerfile", Dest: "Dockerfile", When: "{{ .use_docker }}"},
+				{Src: "main.go", Dest: "main.go"},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{
+		"0": testContext(map[string]any{"use_docker": false}),
+	})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 1)
+	assert.Equal(t, "main.go", out.Files["0"][0].Path)
+}
+
+func TestRenderAll_DropsDuplicateFilesWithWarning(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("first"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("second"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "a.txt", Dest: "out.txt"},
+				{Src: "b.txt", Dest: "out.txt"},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 1)
+	assert.Equal(t, "first", string(out.Files["0"][0].Content))
+	require.Len(t, out.Warnings, 1)
+	assert.Equal(t, "root", out.Warnings[0].Template)
+	assert.Contains(t, out.Warnings[0].Message, "out.txt")
+}
+
+func TestRenderAll_SkipsSymlinksWithWarning(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	src := filepath.Join(dir, "src")
+	require.NoError(t, os.MkdirAll(src, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "real.txt"), []byte("real"), 0644))
+	require.NoError(t, os.Symlink("real.txt", filepath.Join(src, "link.txt")))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:  "root",
+			Files: []File{{Src: "src", Dest: "out"}},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 1)
+	assert.Equal(t, "out/real.txt", out.Files["0"][0].Path)
+	require.Len(t, out.Warnings, 1)
+	assert.Contains(t, out.Warnings[0].Message, "symbolic link")
+}
+
+func TestRenderAll_FileModes(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "build.sh.tmpl"), []byte("#!/bin/sh"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "run.sh", Dest: "run.sh"},
+				{Src: "build.sh.tmpl", Dest: "build.sh.tmpl", Mode: "0755"},
+				{Src: "notes.txt", Dest: "notes.txt"},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 3)
+
+	modes := make(map[string]fs.FileMode)
+	for _, f := range out.Files["0"] {
+		modes[f.Path] = f.Mode
+	}
+	assert.Equal(t, map[string]fs.FileMode{
+		"run.sh":    0755,
+		"build.sh":  0755,
+		"notes.txt": 0,
+	}, modes)
+}
+
+func TestRenderAll_CustomDelimiters(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	chart := "name: [[ .name ]]\nimage: {{ .Values.image }}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deployment.yaml.tmpl"), []byte(chart), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md.tmpl"), []byte("# {{ .name }} <% .name %>"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:       "root",
+			Delimiters: []string{"[[", "]]"},
+			Files: []File{
+				{Src: "deployment.yaml.tmpl", Dest: "{{ .name }}/deployment.yaml"},
+				{Src: "README.md.tmpl", Dest: "README.md", Delimiters: []string{"<%", "%>"}},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api"})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 2)
+	assert.Equal(t, "api/deployment.yaml", out.Files["0"][0].Path)
+	assert.Equal(t, "name: api\nimage: {{ .Values.image }}\n", string(out.Files["0"][0].Content))
+	assert.Equal(t, "# {{ .name }} api", string(out.Files["0"][1].Content))
+}
+
+func TestRenderAll_DirectoryIncludeExclude(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	for name, content := range map[string]string{
+		"src/main.go.tmpl":         "package {{ .name }}\n",
+		"src/cmd/run.go.tmpl":      "package cmd\n",
+		"src/README.md":            "readme",
+		"src/assets/logo.png":      "png",
+		"src/testdata/in.go.tmpl":  "package testdata\n",
+		"src/testdata/out.go.tmpl": "package testdata\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "src", Dest: "out", Include: []string{"**/*.go.tmpl"}, Exclude: []string{"testdata"}},
+				{Src: "src", Dest: "all", Exclude: []string{"**/*.png"}},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api"})})
+	require.NoError(t, err)
+
+	var paths []string
+	for _, file := range out.Files["0"] {
+		paths = append(paths, file.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"out/main.go", "out/cmd/run.go",
+		"all/main.go", "all/cmd/run.go", "all/README.md", "all/testdata/in.go", "all/testdata/out.go",
+	}, paths)
+}
+
+func TestRenderAll_ForEach(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "handler.go.tmpl"), []byte("package {{ .item }} // {{ .name }}\n"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "handler.go.tmpl", Dest: "internal/{{ .item }}/handler.go", ForEach: "services", When: `{{ ne .item "admin" }}`},
+				{Src: "handler.go.tmpl", Dest: "none.go", ForEach: "unset"},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{
+		"name":     "api",
+		"services": []any{"users", "admin", "orders"},
+	})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 2)
+	assert.Equal(t, "internal/users/handler.go", out.Files["0"][0].Path)
+	assert.Equal(t, "package users // api\n", string(out.Files["0"][0].Content))
+	assert.Equal(t, "internal/orders/handler.go", out.Files["0"][1].Path)
+	assert.Equal(t, "package orders // api\n", string(out.Files["0"][1].Content))
+
+	_, err = r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api", "services": "users"})})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "for_each: variable services is a string, not a list")
+}
+
+func TestRenderAll_FrontMatter(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	for name, content := range map[string]string{
+		"files/main.go.tmpl":      "---\ndest: cmd/{{ .name }}/main.go\n---\npackage main // {{ .name }}\n",
+		"files/run.sh.tmpl":       "---\nmode: \"0755\"\ndelimiters: [\"[[\", \"]]\"]\n---\necho [[ .name ]] {{ not rendered }}\n",
+		"files/Dockerfile.tmpl":   "---\nwhen: \"{{ .docker }}\"\n---\nFROM golang\n",
+		"files/service.yaml.tmpl": "---\napiVersion: v1\nname: {{ .name }}\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:  "root",
+			Files: []File{{Src: "files", Dest: "out"}},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api", "docker": false})})
+	require.NoError(t, err)
+
+	files := make(map[string]RenderedFile)
+	for _, file := range out.Files["0"] {
+		files[file.Path] = file
+	}
+	require.Len(t, files, 3)
+	assert.Equal(t, "package main // api\n", string(files["cmd/api/main.go"].Content))
+	assert.Equal(t, "echo api {{ not rendered }}\n", string(files["out/run.sh"].Content))
+	assert.Equal(t, fs.FileMode(0o755), files["out/run.sh"].Mode)
+	assert.Equal(t, "---\napiVersion: v1\nname: api\n", string(files["out/service.yaml"].Content))
+}
+
+func TestRenderStandalone(t *testing.T) {
+	r := NewRenderer()
+
+	content := "---\ndest: ignored.txt\ndelimiters: [\"[[\", \"]]\"]\n---\n[[ .name | b64enc ]] {{ .name }}\n"
+	out, err := r.RenderStandalone([]byte(content), testContext(map[string]any{"name": "api"}), "hello.txt.tmpl")
+	require.NoError(t, err)
+	assert.Equal(t, "YXBp {{ .name }}\n", string(out))
+
+	_, err = r.RenderString("{{ .name | b64enc }}", testContext(map[string]any{"name": "api"}), "default")
+	require.Error(t, err, "libraries are only enabled for the standalone renderer")
+}
+
+func TestRenderAll_BinaryFilesAreCopiedVerbatim(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR{{ .name }}\xff\xfe")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), png, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "icon.png.tmpl"), png, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package {{ .name }}\n"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "logo.png", Dest: "logo.png"},
+				{Src: "icon.png.tmpl", Dest: "icon.png"},
+				{Src: "main.go.tmpl", Dest: "main.go"},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api"})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 3)
+
+	out.ApplyHeader("Copyright Acme")
+
+	files := out.Files["0"]
+	assert.Equal(t, png, files[0].Content)
+	assert.True(t, files[0].Binary)
+	assert.Equal(t, "icon.png", files[1].Path)
+	assert.Equal(t, png, files[1].Content)
+	assert.True(t, files[1].Binary)
+	assert.False(t, files[2].Binary)
+	assert.Equal(t, "// Copyright Acme\n\npackage api\n", string(files[2].Content))
+}
+
+func TestPlanAll_RendersContentOnDemand(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package {{ .name }}\n"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:  "root",
+			Files: []File{{Src: "main.go.tmpl", Dest: "main.go"}},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.PlanAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api"})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 1)
+	out.ApplyHeader("Copyright Acme")
+
+	file := out.Files["0"][0]
+	assert.Equal(t, "main.go", file.Path)
+	assert.Nil(t, file.Content, "planning must not render content")
+
+	// The content is read when it is loaded, not when it is planned.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package {{ .name }}_test\n"), 0644))
+
+	content, err := file.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "// Copyright Acme\n\npackage api_test\n", string(content))
+
+	require.NoError(t, out.Load())
+	assert.Equal(t, content, out.Files["0"][0].Content)
+}
+
+func TestRenderAll_Partials(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "partials", "go"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partials", "header.tmpl"), []byte("// {{ .name }} service"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partials", "go", "imports.tmpl"), []byte(`{{ define "fmt" }}import "fmt"{{ end }}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("{{ template \"header\" . }}\n{{ template \"fmt\" }}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.go.tmpl"), []byte("{{ template \"header\" . }}\n"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Files: []File{
+				{Src: "main.go.tmpl", Dest: "main.go"},
+				{Src: "app.go.tmpl", Dest: "app.go"},
+			},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	out, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api"})})
+	require.NoError(t, err)
+	require.Len(t, out.Files["0"], 2)
+	assert.Equal(t, "// api service\nimport \"fmt\"\n", string(out.Files["0"][0].Content))
+	assert.Equal(t, "// api service\n", string(out.Files["0"][1].Content))
+}
+
+func TestRenderAll_PartialParseError(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "partials"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partials", "header.tmpl"), []byte("{{ if .name }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package main\n"), 0644))
+
+	node := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name:  "root",
+			Files: []File{{Src: "main.go.tmpl", Dest: "main.go"}},
+		},
+		FS:   os.DirFS(dir),
+		Path: ".",
+	}
+
+	_, err := r.RenderAll(node, RenderContexts{"0": testContext(map[string]any{"name": "api"})})
+
+	var renderErr *RenderError
+	require.ErrorAs(t, err, &renderErr)
+	assert.True(t, renderErr.Parse)
+	assert.Equal(t, "partials/header.tmpl", renderErr.Name)
+}
+
+func TestRenderAll_LocaleVariants(t *testing.T) {
+	r, dir := newTestRenderer(t)
+
+	files := map[string]string{
+		"README.md.tmpl":          "# {{ .name }}",
+		"README.de.md.tmpl":       "# {{ .name }} (Deutsch)",
+		"README.pt.md.tmpl":       "# {{ .name }} (Português)",
+		"docs/guide.md":           "Guide",
+		"docs/guide.de.md":        "Anleitung",
+		"docs/install.md":         "Install",
+		"docs/config.dev.yaml":    "env: dev",
+		"docs/CHANGELOG.pt-BR.md": "unused",
+	}
+	for p, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	render := func(t *testing.T, ctx map[string]any) map[string]string {
+		t.Helper()
+		node := &TemplateNode{
+			ID: "0",
+			Template: &Template{
+				Name:    "root",
+				Locales: []string{"de", "pt", "pt-BR"},
+				Variables: []Variable{
+					{Name: "language", Prompt: "Language?", Type: VariableTypeString, Role: RoleLocale},
+				},
+				Files: []File{
+					{Src: "README.md.tmpl", Dest: "README.md"},
+					{Src: "docs", Dest: "docs"},
+				},
+			},
+			FS:   os.DirFS(dir),
+			Path: ".",
+		}
+
+		out, err := r.RenderAll(node, RenderContexts{"0": testContext(ctx)})
+		require.NoError(t, err)
+
+		rendered := make(map[string]string)
+		for _, f := range out.Files["0"] {
+			rendered[f.Path] = string(f.Content)
+		}
+		return rendered
+	}
+
+	t.Run("variable selects the variants", func(t *testing.T) {
+		assert.Equal(t, map[string]string{
+			"README.md":            "# app (Deutsch)",
+			"docs/guide.md":        "Anleitung",
+			"docs/install.md":      "Install",
+			"docs/config.dev.yaml": "env: dev",
+		}, render(t, map[string]any{"name": "app", "language": "de"}))
+	})
+
+	t.Run("falls back to the language and then the file", func(t *testing.T) {
+		rendered := render(t, map[string]any{"name": "app", "language": "pt-BR"})
+		assert.Equal(t, "# app (Português)", rendered["README.md"])
+		assert.Equal(t, "Guide", rendered["docs/guide.md"])
+		assert.NotContains(t, rendered, "docs/CHANGELOG.pt-BR.md")
+	})
+
+	t.Run("configured locale applies without the variable", func(t *testing.T) {
+		rendered := render(t, map[string]any{
+			"name":          "app",
+			BuiltinVariable: Builtins{Locale: "de"}.For(&Template{}),
+		})
+		assert.Equal(t, "# app (Deutsch)", rendered["README.md"])
+	})
+
+	t.Run("undeclared locale renders the files", func(t *testing.T) {
+		rendered := render(t, map[string]any{"name": "app", "language": "fr"})
+		assert.Equal(t, "# app", rendered["README.md"])
+		assert.Equal(t, "Guide", rendered["docs/guide.md"])
+	})
+}
@@ -0,0 +1,95 @@
+package template
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MismatchPolicy decides what happens when a feature or component is added to
+// a project that does not meet its requirements.
+type MismatchPolicy string
+
+const (
+	MismatchError MismatchPolicy = "error" // Refuse to add the template
+	MismatchWarn  MismatchPolicy = "warn"  // Add the template and warn
+)
+
+// Requirements declares the projects a feature or component can be added to,
+// and the tools any template needs. Every condition given must hold.
+type Requirements struct {
+	Projects   []string          `yaml:"projects,omitempty"`                                          // Project templates the project must be generated from, any of them
+	Variables  map[string]string `yaml:"variables,omitempty"`                                         // Values the project must have been generated with
+	OnMismatch MismatchPolicy    `yaml:"on_mismatch,omitempty" validate:"omitempty,oneof=error warn"` // Defaults to error
+	Tools      []string          `yaml:"tools,omitempty"`                                             // Tools that must be on the PATH, e.g. "go >= 1.22"; see ParseToolRequirement
+}
+
+// projectRequirements reports whether r declares requirements on the project
+// a template is added to, which only features and components can.
+func (r *Requirements) projectRequirements() bool {
+	return len(r.Projects) > 0 || len(r.Variables) > 0 || r.OnMismatch != ""
+}
+
+// ProjectState describes an existing project as its manifest records it.
+type ProjectState struct {
+	Template  string         // Project template the project was generated from
+	Variables map[string]any // Variables recorded for the project
+}
+
+// RequirementMismatch is a requirement that a project does not meet.
+type RequirementMismatch struct {
+	Requirement string // e.g. project go-api or framework=chi
+	Actual      string // What the project has, empty if the variable is not set
+}
+
+func (m RequirementMismatch) String() string {
+	if m.Actual == "" {
+		return fmt.Sprintf("requires %s, which the project does not set", m.Requirement)
+	}
+	return fmt.Sprintf("requires %s, project has %s", m.Requirement, m.Actual)
+}
+
+// Policy returns what to do when the requirements are not met.
+func (r *Requirements) Policy() MismatchPolicy {
+	if r == nil || r.OnMismatch == "" {
+		return MismatchError
+	}
+	return r.OnMismatch
+}
+
+// Check returns the requirements that the project does not meet. Variable
+// values are compared as text.
+func (r *Requirements) Check(state ProjectState) []RequirementMismatch {
+	if r == nil {
+		return nil
+	}
+
+	var mismatches []RequirementMismatch
+	if len(r.Projects) > 0 && !slices.Contains(r.Projects, state.Template) {
+		mismatches = append(mismatches, RequirementMismatch{
+			Requirement: "project " + strings.Join(r.Projects, " or "),
+			Actual:      state.Template,
+		})
+	}
+
+	names := make([]string, 0, len(r.Variables))
+	for name := range r.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		want := r.Variables[name]
+		value, ok := state.Variables[name]
+		if ok && fmt.Sprint(value) == want {
+			continue
+		}
+		mismatch := RequirementMismatch{Requirement: name + "=" + want}
+		if ok {
+			mismatch.Actual = fmt.Sprintf("%s=%v", name, value)
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches
+}
@@ -0,0 +1,33 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirements_Check(t *testing.T) {
+	requires := &Requirements{
+		Projects:  []string{"go-api", "go-grpc"},
+		Variables: map[string]string{"framework": "chi", "metrics": "true"},
+	}
+
+	assert.Empty(t, requires.Check(ProjectState{
+		Template:  "go-grpc",
+		Variables: map[string]any{"framework": "chi", "metrics": true},
+	}))
+
+	mismatches := requires.Check(ProjectState{
+		Template:  "go-cli",
+		Variables: map[string]any{"framework": "gin"},
+	})
+	assert.Equal(t, []string{
+		"requires project go-api or go-grpc, project has go-cli",
+		"requires framework=chi, project has framework=gin",
+		"requires metrics=true, which the project does not set",
+	}, []string{mismatches[0].String(), mismatches[1].String(), mismatches[2].String()})
+
+	var none *Requirements
+	assert.Empty(t, none.Check(ProjectState{Template: "go-cli"}))
+	assert.Equal(t, MismatchError, none.Policy())
+}
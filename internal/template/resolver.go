@@ -4,7 +4,8 @@ import "io/fs"
 
 // TemplateRef represents a reference to a template.
 type TemplateRef struct {
-	Name string
+	Name    string
+	Version string // Exact version to resolve; any version when empty
 }
 
 // ResolvedTemplate represents a resolved template.
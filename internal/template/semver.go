@@ -0,0 +1,294 @@
+package template
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// semver is a major.minor.patch version with an optional pre-release.
+type semver struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseSemver parses a version such as 1.2.3, v1.2.3, or 1.2.3-rc.1. Build
+// metadata is ignored.
+func parseSemver(s string) (semver, error) {
+	v, parts, err := parsePartialSemver(s)
+	if err != nil {
+		return semver{}, err
+	}
+	if parts != 3 {
+		return semver{}, fmt.Errorf("version %q is not of the form major.minor.patch", s)
+	}
+	return v, nil
+}
+
+// parsePartialSemver parses a version that may stop after its major or minor
+// part, or have x or * in place of them, such as 1, 1.2, or 1.x. It returns
+// how many parts were given.
+func parsePartialSemver(s string) (semver, int, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.Index(rest, "+"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	var v semver
+	if i := strings.Index(rest, "-"); i >= 0 {
+		v.pre = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	fields := strings.Split(rest, ".")
+	if len(fields) > 3 || rest == "" {
+		return semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+
+	parts := 0
+	for i, f := range fields {
+		if f == "x" || f == "X" || f == "*" {
+			break
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return semver{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		switch i {
+		case 0:
+			v.major = n
+		case 1:
+			v.minor = n
+		case 2:
+			v.patch = n
+		}
+		parts++
+	}
+	if v.pre != "" && parts != 3 {
+		return semver{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	return v, parts, nil
+}
+
+// compare returns -1, 0, or 1 as v is older than, equal to, or newer than o.
+// A pre-release is older than its release, and pre-releases are ordered as
+// semver orders them.
+func (v semver) compare(o semver) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			if d < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.pre == o.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case o.pre == "":
+		return -1
+	default:
+		return comparePrerelease(v.pre, o.pre)
+	}
+}
+
+// comparePrerelease compares two pre-releases identifier by identifier, as in
+// rc.2 < rc.10. Numeric identifiers compare as numbers and sort before
+// alphanumeric ones, and a pre-release sorts before a longer one it is a
+// prefix of, as in alpha < alpha.1.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, errX := strconv.ParseUint(as[i], 10, 64)
+		y, errY := strconv.ParseUint(bs[i], 10, 64)
+		var c int
+		switch {
+		case errX == nil && errY == nil:
+			c = cmp.Compare(x, y)
+		case errX == nil:
+			c = -1
+		case errY == nil:
+			c = 1
+		default:
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// compareVersions compares two versions like semver.compare. Versions that
+// are not semver sort before those that are, and among themselves by string.
+func compareVersions(a, b string) int {
+	va, errA := parseSemver(a)
+	vb, errB := parseSemver(b)
+	switch {
+	case errA == nil && errB == nil:
+		return va.compare(vb)
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// comparator is one bound of a version constraint.
+type comparator struct {
+	op string // One of = > >= < <=
+	v  semver
+}
+
+func (c comparator) allows(v semver) bool {
+	cmp := v.compare(c.v)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// VersionConstraint is a range of versions that an include accepts for the
+// template it includes. It is a list of conditions separated by spaces or
+// commas, all of which must hold:
+//
+//   - 1.2.3 or =1.2.3: exactly that version
+//   - >1.2.3, >=1.2.3, <2.0.0, <=1.9.9: bounds
+//   - ^1.2.3: compatible versions, >=1.2.3 <2.0.0 (>=0.2.3 <0.3.0 below 1.0.0)
+//   - ~1.2.3: patch releases, >=1.2.3 <1.3.0
+//   - 1, 1.x, 1.2, 1.2.*: any version with that prefix
+//   - * or x: any version
+type VersionConstraint struct {
+	raw         string
+	comparators []comparator
+}
+
+// ParseVersionConstraint parses a version constraint.
+func ParseVersionConstraint(s string) (*VersionConstraint, error) {
+	c := &VersionConstraint{raw: s}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	for _, field := range fields {
+		op := ""
+		for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(field, prefix) {
+				op = prefix
+				break
+			}
+		}
+		value := strings.TrimPrefix(field, op)
+
+		v, parts, err := parsePartialSemver(value)
+		if err != nil && value != "*" && value != "x" && value != "X" {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		comparators, err := expandComparator(op, v, parts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.comparators = append(c.comparators, comparators...)
+	}
+	return c, nil
+}
+
+// expandComparator turns one condition of a constraint into bounds. parts is
+// the number of version parts given.
+func expandComparator(op string, v semver, parts int) ([]comparator, error) {
+	next := func(parts int) semver {
+		switch parts {
+		case 1:
+			return semver{major: v.major + 1}
+		case 2:
+			return semver{major: v.major, minor: v.minor + 1}
+		default:
+			return semver{major: v.major, minor: v.minor, patch: v.patch + 1}
+		}
+	}
+
+	switch op {
+	case "^":
+		if parts == 0 {
+			return nil, nil
+		}
+		// The first non-zero part given may not change.
+		fixed := 1
+		if v.major == 0 && parts > 1 {
+			fixed = 2
+			if v.minor == 0 && parts > 2 {
+				fixed = 3
+			}
+		}
+		return []comparator{{">=", v}, {"<", next(fixed)}}, nil
+	case "~":
+		if parts == 0 {
+			return nil, nil
+		}
+		return []comparator{{">=", v}, {"<", next(min(parts, 2))}}, nil
+	case "", "=":
+		switch parts {
+		case 0:
+			return nil, nil
+		case 3:
+			return []comparator{{"=", v}}, nil
+		default:
+			return []comparator{{">=", v}, {"<", next(parts)}}, nil
+		}
+	default:
+		if parts == 0 {
+			return nil, fmt.Errorf("%s needs a version", op)
+		}
+		// A partial version stands for every version with its prefix:
+		// <=1.2 allows 1.2.9 and >1.2 does not.
+		switch {
+		case parts < 3 && op == "<=":
+			return []comparator{{"<", next(parts)}}, nil
+		case parts < 3 && op == ">":
+			return []comparator{{">=", next(parts)}}, nil
+		}
+		return []comparator{{op, v}}, nil
+	}
+}
+
+// Allows reports whether version satisfies the constraint. Versions that are
+// not semver satisfy no constraint. Like package managers, a pre-release only
+// satisfies a constraint that names a pre-release of the same version, so
+// ^1.0.0 does not pick 2.0.0-rc.1.
+func (c *VersionConstraint) Allows(version string) bool {
+	v, err := parseSemver(version)
+	if err != nil {
+		return false
+	}
+	if v.pre != "" && !slices.ContainsFunc(c.comparators, func(cmp comparator) bool {
+		return cmp.v.pre != "" && cmp.v.major == v.major && cmp.v.minor == v.minor && cmp.v.patch == v.patch
+	}) {
+		return false
+	}
+	for _, cmp := range c.comparators {
+		if !cmp.allows(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the constraint as written.
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
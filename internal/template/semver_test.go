@@ -0,0 +1,66 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allowed    []string
+		denied     []string
+	}{
+		{"1.2.3", []string{"1.2.3", "v1.2.3"}, []string{"1.2.4"}},
+		{"^1.2.0", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"1.x", []string{"1.0.0", "1.5.2"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{">=1.2.0 <2.0.0", []string{"1.2.0", "1.9.0"}, []string{"1.1.0", "2.0.0"}},
+		{">=1.2.0, <=1.4", []string{"1.4.9"}, []string{"1.5.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.5"}},
+		{"*", []string{"0.0.1", "9.0.0"}, []string{"latest"}},
+		{"^1.0.0", []string{"1.1.0"}, []string{"2.0.0-rc.1"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.constraint)
+		require.NoError(t, err, tt.constraint)
+		for _, v := range tt.allowed {
+			assert.True(t, c.Allows(v), "%s allows %s", tt.constraint, v)
+		}
+		for _, v := range tt.denied {
+			assert.False(t, c.Allows(v), "%s denies %s", tt.constraint, v)
+		}
+	}
+
+	for _, s := range []string{"", "^", ">=", "1.2.3.4", "abc", "~>1.0"} {
+		_, err := ParseVersionConstraint(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"v1.0.0", "1.0.0", 0},
+		{"0.0.1", "dev", 1},
+		{"1.0.0-rc.10", "1.0.0-rc.2", 1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"1.0.0-rc.1", "1.0.0-rc.1", 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, compareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
@@ -0,0 +1,176 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// VariableLibraryFileName is the name of the files that declare variables
+// shared by the templates of a source.
+const VariableLibraryFileName = "variables.yaml"
+
+// VariableLibrary is a named set of reusable variable definitions. Templates
+// refer to its variables with `use: <library>/<variable>`.
+type VariableLibrary struct {
+	Name        string     `yaml:"name" validate:"required"`
+	Description string     `yaml:"description,omitempty"`
+	Variables   []Variable `yaml:"variables" validate:"dive"`
+}
+
+// Variable returns the variable of the library with the given name.
+func (l *VariableLibrary) Variable(name string) (Variable, bool) {
+	for _, v := range l.Variables {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variable{}, false
+}
+
+// VariableLibraryResolver finds variable libraries by name.
+type VariableLibraryResolver interface {
+	ResolveVariableLibrary(name string) (*VariableLibrary, error)
+}
+
+// VariableLibraryNotFoundError is returned when no source ships a variable
+// library with the given name.
+type VariableLibraryNotFoundError struct {
+	Name string
+}
+
+func (e *VariableLibraryNotFoundError) Error() string {
+	return fmt.Sprintf("variable library not found: %s", e.Name)
+}
+
+// FindVariableLibrary searches fsys for a variables.yaml declaring the
+// library with the given name.
+func FindVariableLibrary(fsys fs.FS, name string) (*VariableLibrary, error) {
+	var found *VariableLibrary
+
+	err := fs.WalkDir(fsys, ".", func(pth string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() || d.Name() != VariableLibraryFileName {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, pth)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", pth, err)
+		}
+
+		var lib VariableLibrary
+		if err := yaml.Unmarshal(data, &lib); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", pth, err)
+		}
+		if lib.Name != name {
+			return nil
+		}
+
+		if err := NewValidator().ValidateVariableLibrary(&lib); err != nil {
+			return &ValidationError{Template: lib.Name, Err: err}
+		}
+
+		found = &lib
+		return fs.SkipAll
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, &VariableLibraryNotFoundError{Name: name}
+	}
+
+	return found, nil
+}
+
+// expandVariableUses replaces the variables of tmpl that use a shared
+// definition with that definition. Fields set next to `use` override those of
+// the definition. Libraries are looked up in fsys first, then in libs.
+func expandVariableUses(tmpl *Template, fsys fs.FS, libs VariableLibraryResolver) error {
+	loaded := make(map[string]*VariableLibrary)
+
+	for i, v := range tmpl.Variables {
+		if v.Use == "" {
+			continue
+		}
+
+		libName, varName, ok := strings.Cut(v.Use, "/")
+		if !ok || libName == "" || varName == "" {
+			return fmt.Errorf("invalid use %q: expected <library>/<variable>", v.Use)
+		}
+
+		lib, ok := loaded[libName]
+		if !ok {
+			var err error
+			lib, err = resolveVariableLibrary(libName, fsys, libs)
+			if err != nil {
+				return fmt.Errorf("variable %s: %w", v.Use, err)
+			}
+			loaded[libName] = lib
+		}
+
+		shared, ok := lib.Variable(varName)
+		if !ok {
+			return fmt.Errorf("variable %s: library %s has no variable %s", v.Use, libName, varName)
+		}
+
+		tmpl.Variables[i] = mergeVariable(shared, v)
+	}
+
+	return nil
+}
+
+func resolveVariableLibrary(name string, fsys fs.FS, libs VariableLibraryResolver) (*VariableLibrary, error) {
+	lib, err := FindVariableLibrary(fsys, name)
+	var notFound *VariableLibraryNotFoundError
+	if err == nil || !errors.As(err, &notFound) || libs == nil {
+		return lib, err
+	}
+	return libs.ResolveVariableLibrary(name)
+}
+
+// mergeVariable returns the shared definition with the fields set in local
+// applied on top.
+func mergeVariable(shared, local Variable) Variable {
+	merged := shared
+	merged.Use = local.Use
+
+	if local.Name != "" {
+		merged.Name = local.Name
+	}
+	if local.Prompt != "" {
+		merged.Prompt = local.Prompt
+	}
+	if local.Type != "" {
+		merged.Type = local.Type
+	}
+	if local.Role != "" {
+		merged.Role = local.Role
+	}
+	if local.Default != nil {
+		merged.Default = local.Default
+	}
+	if len(local.Options) > 0 {
+		merged.Options = local.Options
+	}
+	if local.Suggestions != nil {
+		merged.Suggestions = local.Suggestions
+	}
+	if local.Mask != "" {
+		merged.Mask = local.Mask
+	}
+	if local.When != "" {
+		merged.When = local.When
+	}
+	if local.EnvOnly {
+		merged.EnvOnly = true
+	}
+
+	return merged
+}
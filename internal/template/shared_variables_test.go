@@ -0,0 +1,107 @@
+package template
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commonVariables = `name: common
+variables:
+  - name: project_name
+    prompt: What is the project name?
+    type: string
+    role: project_name
+  - name: license
+    prompt: License?
+    type: select
+    options: [mit, apache-2.0]
+    default: mit
+`
+
+// libraryResolver serves variable libraries from a filesystem.
+type libraryResolver struct {
+	fsys fstest.MapFS
+}
+
+func (r *libraryResolver) ResolveVariableLibrary(name string) (*VariableLibrary, error) {
+	return FindVariableLibrary(r.fsys, name)
+}
+
+func TestLoader_ExpandsSharedVariables(t *testing.T) {
+	fsys := fstest.MapFS{
+		"shared/variables.yaml": {Data: []byte(commonVariables)},
+		"app/template.yaml": {Data: []byte(`name: app
+type: project
+version: 1.0.0
+variables:
+  - use: common/project_name
+  - use: common/license
+    name: app_license
+    default: apache-2.0
+`)},
+	}
+
+	loaded, err := NewLoader().Load(fsys, "app")
+	require.NoError(t, err)
+
+	vars := loaded.Template.Variables
+	require.Len(t, vars, 2)
+	assert.Equal(t, "project_name", vars[0].Name)
+	assert.Equal(t, RoleProjectName, vars[0].Role)
+	assert.Equal(t, "What is the project name?", vars[0].Prompt)
+	assert.Equal(t, "app_license", vars[1].Name)
+	assert.Equal(t, VariableTypeSelect, vars[1].Type)
+	assert.Equal(t, []string{"mit", "apache-2.0"}, vars[1].OptionValues())
+	assert.Equal(t, "apache-2.0", vars[1].Default)
+}
+
+func TestLoader_SharedVariablesFromOtherSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/template.yaml": {Data: []byte("name: app\ntype: feature\nversion: 1.0.0\nvariables:\n  - use: common/license\n")},
+	}
+	libs := &libraryResolver{fsys: fstest.MapFS{"variables.yaml": {Data: []byte(commonVariables)}}}
+
+	_, err := NewLoader().Load(fsys, "app")
+	var notFound *VariableLibraryNotFoundError
+	require.ErrorAs(t, err, &notFound)
+
+	loaded, err := NewLoader().WithLibraries(libs).Load(fsys, "app")
+	require.NoError(t, err)
+	assert.Equal(t, "license", loaded.Template.Variables[0].Name)
+}
+
+func TestLoader_UnknownSharedVariable(t *testing.T) {
+	fsys := fstest.MapFS{
+		"variables.yaml":    {Data: []byte(commonVariables)},
+		"app/template.yaml": {Data: []byte("name: app\ntype: feature\nversion: 1.0.0\nvariables:\n  - use: common/author\n")},
+	}
+
+	_, err := NewLoader().Load(fsys, "app")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "library common has no variable author")
+}
+
+func TestLint_SharedVariables(t *testing.T) {
+	fsys := fstest.MapFS{
+		"variables.yaml": {Data: []byte(commonVariables)},
+		"app/template.yaml": {Data: []byte(`name: app
+type: project
+version: 1.0.0
+variables:
+  - use: common/project_name
+  - use: shared
+files:
+  - src: main.go.tmpl
+    dest: main.go
+`)},
+		"app/main.go.tmpl": {Data: []byte("// {{ .project_name }}\n")},
+	}
+
+	messages := lintMessages(t, fsys, "app")
+
+	require.NotEmpty(t, messages)
+	assert.Contains(t, messages[0], `invalid use "shared"`)
+}
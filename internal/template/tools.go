@@ -0,0 +1,117 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ToolRequirement is a command-line tool a template needs, such as go >= 1.22,
+// docker, or node >= 20.
+type ToolRequirement struct {
+	Name       string
+	Constraint *VersionConstraint // Versions of the tool accepted; nil if any version will do
+}
+
+// toolConstraintSpace matches the space between an operator and its version,
+// as in >= 1.22.
+var toolConstraintSpace = regexp.MustCompile(`([<>=^~]+)\s+`)
+
+// ParseToolRequirement parses a tool requirement: the name of the command,
+// optionally followed by a version constraint, as in "go >= 1.22" or
+// "node ^20". Versions may stop after their major or minor part.
+func ParseToolRequirement(s string) (ToolRequirement, error) {
+	name, constraint, _ := strings.Cut(strings.TrimSpace(s), " ")
+	if name == "" {
+		return ToolRequirement{}, fmt.Errorf("empty tool requirement")
+	}
+	if strings.ContainsAny(name, `<>=^~/\`) {
+		return ToolRequirement{}, fmt.Errorf("invalid tool requirement %q: expected a command name such as go or docker", s)
+	}
+
+	req := ToolRequirement{Name: name}
+	if constraint = strings.TrimSpace(constraint); constraint != "" {
+		c, err := ParseVersionConstraint(toolConstraintSpace.ReplaceAllString(constraint, "$1"))
+		if err != nil {
+			return ToolRequirement{}, fmt.Errorf("invalid tool requirement %q: %w", s, err)
+		}
+		req.Constraint = c
+	}
+	return req, nil
+}
+
+func (r ToolRequirement) String() string {
+	if r.Constraint == nil {
+		return r.Name
+	}
+	return r.Name + " " + r.Constraint.String()
+}
+
+// Allows reports whether version, as found by ToolVersion, satisfies the
+// requirement. Missing minor and patch parts count as zero, so 20 is 20.0.0.
+func (r ToolRequirement) Allows(version string) bool {
+	if r.Constraint == nil {
+		return true
+	}
+	v, _, err := parsePartialSemver(version)
+	if err != nil {
+		return false
+	}
+	return r.Constraint.Allows(fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch))
+}
+
+// toolVersionPattern matches the first version in the output of a tool, such
+// as 1.22.3 in "go version go1.22.3 linux/amd64" or 20.11.0 in "v20.11.0".
+var toolVersionPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// ToolVersion returns the version a tool printed when asked for it, or an
+// empty string if it printed none.
+func ToolVersion(output string) string {
+	return toolVersionPattern.FindString(output)
+}
+
+// ToolVersionArgs returns the arguments that make a tool print its version.
+// Most tools accept --version.
+func ToolVersionArgs(name string) []string {
+	switch name {
+	case "go":
+		return []string{"version"}
+	case "java":
+		return []string{"-version"}
+	default:
+		return []string{"--version"}
+	}
+}
+
+// RequiredTool is a tool required by a template of a tree.
+type RequiredTool struct {
+	ToolRequirement
+	Template string // Name of the template requiring the tool
+}
+
+// AllTools recursively collects the tools required by the templates in the
+// tree. A requirement declared by several templates is listed once, for the
+// first of them; requirements that cannot be parsed are left out, as
+// validation reports them.
+func (n *TemplateNode) AllTools() []RequiredTool {
+	var tools []RequiredTool
+	seen := make(map[string]bool)
+	n.collectTools(&tools, seen)
+	return tools
+}
+
+func (n *TemplateNode) collectTools(tools *[]RequiredTool, seen map[string]bool) {
+	if n.Template.Requires != nil {
+		for _, s := range n.Template.Requires.Tools {
+			req, err := ParseToolRequirement(s)
+			if err != nil || seen[req.String()] {
+				continue
+			}
+			seen[req.String()] = true
+			*tools = append(*tools, RequiredTool{ToolRequirement: req, Template: n.Template.Name})
+		}
+	}
+	for _, child := range n.Children {
+		child.collectTools(tools, seen)
+	}
+}
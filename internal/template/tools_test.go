@@ -0,0 +1,65 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolRequirement(t *testing.T) {
+	req, err := ParseToolRequirement("docker")
+	require.NoError(t, err)
+	assert.Equal(t, "docker", req.Name)
+	assert.Nil(t, req.Constraint)
+	assert.True(t, req.Allows(""))
+
+	req, err = ParseToolRequirement("go >= 1.22")
+	require.NoError(t, err)
+	assert.Equal(t, "go", req.Name)
+	assert.Equal(t, "go >=1.22", req.String())
+	assert.True(t, req.Allows("1.22"))
+	assert.True(t, req.Allows("1.23.4"))
+	assert.False(t, req.Allows("1.21.9"))
+	assert.False(t, req.Allows(""))
+
+	req, err = ParseToolRequirement("node >=20 <23")
+	require.NoError(t, err)
+	assert.True(t, req.Allows("20.11.0"))
+	assert.False(t, req.Allows("23.0.0"))
+
+	for _, s := range []string{"", ">= 1.22", "go >= latest", "./bin/tool"} {
+		_, err := ParseToolRequirement(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestToolVersion(t *testing.T) {
+	tests := map[string]string{
+		"go version go1.22.3 linux/amd64":      "1.22.3",
+		"v20.11.0\n":                           "20.11.0",
+		"Docker version 24.0.7, build afdd53b": "24.0.7",
+		`openjdk version "21.0.1" 2023-10-17`:  "21.0.1",
+		"Python 3.12":                          "3.12",
+		"usage: tool [options]":                "",
+	}
+	for output, want := range tests {
+		assert.Equal(t, want, ToolVersion(output), output)
+	}
+}
+
+func TestAllTools(t *testing.T) {
+	tree := &TemplateNode{
+		Template: &Template{Name: "go-api", Requires: &Requirements{Tools: []string{"go >= 1.22", "git"}}},
+		Children: []*TemplateNode{
+			{Template: &Template{Name: "docker", Requires: &Requirements{Tools: []string{"docker", "git", "not valid <"}}}},
+			{Template: &Template{Name: "readme"}},
+		},
+	}
+
+	var names []string
+	for _, tool := range tree.AllTools() {
+		names = append(names, tool.Template+": "+tool.String())
+	}
+	assert.Equal(t, []string{"go-api: go >=1.22", "go-api: git", "docker: docker"}, names)
+}
@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"path"
 	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -17,8 +18,19 @@ type Validator struct {
 
 // NewValidator creates a new template validator.
 func NewValidator() *Validator {
+	validate := validator.New()
+
+	// Report fields by their name in template.yaml.
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
 	return &Validator{
-		validate: validator.New(),
+		validate: validate,
 	}
 }
 
@@ -69,19 +81,46 @@ func (v *Validator) validateNodeFiles(node *TemplateNode) []error {
 }
 
 // ValidateTreeContexts recursively validates that all template variables are present
-// in the provided contexts for the entire tree.
+// in the provided contexts for the entire tree. Missing variables are collected
+// across the whole tree and reported together as a *MissingVariablesError.
 func (v *Validator) ValidateTreeContexts(node *TemplateNode, contexts RenderContexts) error {
+	var missing []MissingVariable
+	if err := v.validateTreeContexts(node, contexts, &missing); err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return &MissingVariablesError{Variables: missing}
+	}
+
+	return nil
+}
+
+func (v *Validator) validateTreeContexts(node *TemplateNode, contexts RenderContexts, missing *[]MissingVariable) error {
 	ctx, ok := contexts[node.ID]
 	if !ok {
 		return fmt.Errorf("no context found for template %s (ID: %s)", node.Template.Name, node.ID)
 	}
 
-	if err := v.ValidateContext(node.Template, ctx); err != nil {
-		return fmt.Errorf("template %s (ID: %s): %w", node.Template.Name, node.ID, err)
+	for _, variable := range node.Template.Variables {
+		value, exists := ctx.Get(variable.Name)
+		if !exists {
+			*missing = append(*missing, MissingVariable{
+				Template: node.Template.Name,
+				NodeID:   node.ID,
+				Name:     variable.Name,
+				Prompt:   variable.Prompt,
+			})
+			continue
+		}
+
+		if err := v.validateVariableValue(variable, value); err != nil {
+			return fmt.Errorf("template %s (ID: %s): variable %s is invalid: %w", node.Template.Name, node.ID, variable.Name, err)
+		}
 	}
 
 	for _, child := range node.Children {
-		if err := v.ValidateTreeContexts(child, contexts); err != nil {
+		if err := v.validateTreeContexts(child, contexts, missing); err != nil {
 			return err
 		}
 	}
@@ -110,17 +149,99 @@ func (v *Validator) Validate(tmpl *Template) error {
 	var errs []error
 
 	// Struct tag validation
-	if err := v.validate.Struct(tmpl); err != nil {
-		errs = append(errs, err)
-	}
+	errs = append(errs, v.structErrors(tmpl.Name, tmpl)...)
 
 	// Semantic validation
 	errs = append(errs, v.validateVariables(tmpl.Variables)...)
+	errs = append(errs, validateMetadata(tmpl.Metadata())...)
 
 	if err := v.validateProjectNameRole(tmpl); err != nil {
 		errs = append(errs, err)
 	}
 
+	errs = append(errs, v.validateDeprecated(tmpl)...)
+	errs = append(errs, v.validateEnv(tmpl.Env)...)
+	errs = append(errs, v.validateClean(tmpl.Clean)...)
+	errs = append(errs, v.validateLocales(tmpl)...)
+
+	for i, name := range tmpl.Functions {
+		if !IsFuncLibrary(name) {
+			errs = append(errs, fmt.Errorf("functions[%d]: unknown function library %q (available: %s)",
+				i, name, strings.Join(FuncLibraries(), ", ")))
+		}
+	}
+
+	if err := validateDelimiters(tmpl.Delimiters); err != nil {
+		errs = append(errs, err)
+	}
+
+	if tmpl.Go != nil {
+		if err := tmpl.Go.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("go: %w", err))
+		}
+	}
+
+	if tmpl.MinBlueprintVersion != "" {
+		if _, err := parseSemver(tmpl.MinBlueprintVersion); err != nil {
+			errs = append(errs, fmt.Errorf("min_blueprint_version: %q is not a release version such as 1.4.0",
+				tmpl.MinBlueprintVersion))
+		}
+	}
+
+	if tmpl.Requires != nil {
+		if tmpl.Type == TypeProject && tmpl.Requires.projectRequirements() {
+			errs = append(errs, fmt.Errorf("requires: only feature and component templates can declare required projects and variables"))
+		}
+		for i, tool := range tmpl.Requires.Tools {
+			if _, err := ParseToolRequirement(tool); err != nil {
+				errs = append(errs, fmt.Errorf("requires.tools[%d]: %w", i, err))
+			}
+		}
+	}
+
+	for i, command := range tmpl.Verify {
+		if strings.TrimSpace(command) == "" {
+			errs = append(errs, fmt.Errorf("verify[%d]: command must not be empty", i))
+		}
+	}
+
+	for i, inc := range tmpl.Includes {
+		if inc.Version == "" {
+			continue
+		}
+		if _, err := ParseVersionConstraint(inc.Version); err != nil {
+			errs = append(errs, fmt.Errorf("includes[%d]: %w", i, err))
+		}
+	}
+
+	for i, file := range tmpl.Files {
+		if _, err := file.FileMode(); err != nil {
+			errs = append(errs, fmt.Errorf("files[%d]: %w", i, err))
+		}
+		if err := validateDelimiters(file.Delimiters); err != nil {
+			errs = append(errs, fmt.Errorf("files[%d]: %w", i, err))
+		}
+		errs = append(errs, v.validateFileAction(i, file)...)
+		for _, pattern := range file.Include {
+			if err := validateGlob(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("files[%d]: include: %w", i, err))
+			}
+		}
+		for _, pattern := range file.Exclude {
+			if err := validateGlob(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("files[%d]: exclude: %w", i, err))
+			}
+		}
+	}
+
+	errs = append(errs, validateForEach(tmpl)...)
+
+	for i, edit := range tmpl.GoEdits {
+		for _, err := range validateGoEdit(edit) {
+			errs = append(errs, fmt.Errorf("go_edits[%d]: %w", i, err))
+		}
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -130,7 +251,49 @@ func (v *Validator) Validate(tmpl *Template) error {
 
 // ValidateMetadata validates a template metadata and returns all validation errors.
 func (v *Validator) ValidateMetadata(meta *Metadata) error {
-	return v.validate.Struct(meta)
+	errs := v.structErrors(meta.Name, meta)
+	errs = append(errs, validateMetadata(meta)...)
+	return errors.Join(errs...)
+}
+
+// ValidateVariableLibrary validates a variable library and returns all
+// validation errors.
+func (v *Validator) ValidateVariableLibrary(lib *VariableLibrary) error {
+	errs := v.structErrors(lib.Name, lib)
+	errs = append(errs, v.validateVariables(lib.Variables)...)
+	return errors.Join(errs...)
+}
+
+// structErrors validates the struct tags of s and converts every failing
+// field to a *FieldError.
+func (v *Validator) structErrors(tmpl string, s any) []error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []error{err}
+	}
+
+	errs := make([]error, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		// Drop the name of the Go struct from the namespace.
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+
+		ferr := &FieldError{Template: tmpl, Field: field, Rule: fe.Tag(), Value: fe.Value()}
+		switch fe.Tag() {
+		case "oneof":
+			ferr.Allowed = strings.Fields(fe.Param())
+		case "required_if":
+			if name, value, ok := strings.Cut(fe.Param(), " "); ok {
+				ferr.Condition = fmt.Sprintf("%s is %s", strings.ToLower(name), value)
+			}
+		}
+		errs = append(errs, ferr)
+	}
+	return errs
 }
 
 // validateVariables validates variable-specific rules.
@@ -145,11 +308,25 @@ func (v *Validator) validateVariables(vars []Variable) []error {
 		}
 		seen[variable.Name] = true
 
+		if variable.Name == BuiltinVariable {
+			errs = append(errs, fmt.Errorf("variable[%d]: %q is reserved for builtin values", i, variable.Name))
+		}
+
 		if err := v.validateVariableOptions(i, variable); err != nil {
 			errs = append(errs, err)
 		}
 
-		if variable.Default != nil {
+		if variable.Type != VariableTypeString && (variable.Suggestions != nil || variable.Mask != "") {
+			errs = append(errs, fmt.Errorf("variable[%d] %q: suggestions and mask are only allowed for the string type", i, variable.Name))
+		}
+
+		if variable.EnvOnly && !variable.IsSecret() {
+			errs = append(errs, fmt.Errorf("variable[%d] %q: env_only is only allowed for the secret type", i, variable.Name))
+		}
+
+		if variable.IsSecret() && variable.Default != nil {
+			errs = append(errs, fmt.Errorf("variable[%d] %q: secrets cannot have a default value", i, variable.Name))
+		} else if variable.Default != nil {
 			if err := v.validateVariableValue(variable, variable.Default); err != nil {
 				errs = append(errs, fmt.Errorf("variable[%d] %q: invalid default value: %w", i, variable.Name, err))
 			}
@@ -159,6 +336,163 @@ func (v *Validator) validateVariables(vars []Variable) []error {
 	return errs
 }
 
+// validateDeprecated checks that deprecated variables are renamed to a
+// declared variable and do not shadow one.
+func (v *Validator) validateDeprecated(tmpl *Template) []error {
+	var errs []error
+
+	declared := make(map[string]bool, len(tmpl.Variables))
+	for _, variable := range tmpl.Variables {
+		declared[variable.Name] = true
+	}
+
+	seen := make(map[string]bool)
+	for i, d := range tmpl.Deprecated {
+		switch {
+		case seen[d.Name]:
+			errs = append(errs, fmt.Errorf("deprecated_variables[%d]: duplicate deprecated variable %q", i, d.Name))
+		case declared[d.Name]:
+			errs = append(errs, fmt.Errorf("deprecated_variables[%d]: %q is still declared as a variable", i, d.Name))
+		case d.RenamedTo != "" && !declared[d.RenamedTo]:
+			errs = append(errs, fmt.Errorf("deprecated_variables[%d]: %q is renamed to undeclared variable %q", i, d.Name, d.RenamedTo))
+		}
+		seen[d.Name] = true
+	}
+
+	return errs
+}
+
+// validateEnv validates post-init environment variable declarations.
+func (v *Validator) validateEnv(env []EnvVar) []error {
+	var errs []error
+
+	seen := make(map[string]bool)
+	for i, e := range env {
+		if seen[e.Name] {
+			errs = append(errs, fmt.Errorf("env[%d]: duplicate environment variable %q", i, e.Name))
+		}
+		seen[e.Name] = true
+	}
+
+	return errs
+}
+
+// validateClean validates the patterns of generated paths removed by `blueprint clean`.
+// Patterns must stay inside the template's output directory and name
+// something, rather than match whatever is there, as * or .* would.
+func (v *Validator) validateClean(patterns []string) []error {
+	var errs []error
+
+	for i, pattern := range patterns {
+		clean := path.Clean(pattern)
+		switch {
+		case pattern == "" || clean == ".":
+			errs = append(errs, fmt.Errorf("clean[%d]: pattern must not be empty", i))
+		case path.IsAbs(pattern) || clean == ".." || strings.HasPrefix(clean, "../"):
+			errs = append(errs, fmt.Errorf("clean[%d]: pattern %q must be relative to the output directory", i, pattern))
+		case wildcardOnly(clean):
+			errs = append(errs, fmt.Errorf("clean[%d]: pattern %q matches any path; name the generated paths, e.g. bin or *.out", i, pattern))
+		default:
+			if _, err := path.Match(clean, ""); err != nil {
+				errs = append(errs, fmt.Errorf("clean[%d]: invalid pattern %q: %w", i, pattern, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// wildcardOnly reports whether a pattern has no literal characters besides
+// dots and separators, such as *, .*, **, or */?.
+func wildcardOnly(pattern string) bool {
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*', '?', '.', '/':
+		case '[':
+			// A character class matches any of several characters.
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return false
+			}
+			i += end
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validateFileAction checks that an injected file names an anchor and is
+// injected into a project file, and that only injected files name one.
+func (v *Validator) validateFileAction(index int, file File) []error {
+	if file.Action != ActionInject {
+		if file.Anchor != "" {
+			return []error{fmt.Errorf("files[%d]: anchor is only used with action inject", index)}
+		}
+		return nil
+	}
+
+	var errs []error
+	if err := validateAnchor(file.Anchor); err != nil {
+		errs = append(errs, fmt.Errorf("files[%d]: %w", index, err))
+	}
+	if file.Target != "" && file.Target != TargetProject {
+		errs = append(errs, fmt.Errorf("files[%d]: injected files must target the project", index))
+	}
+	if file.Mode != "" {
+		errs = append(errs, fmt.Errorf("files[%d]: mode cannot be set on an injected file", index))
+	}
+	return errs
+}
+
+// validateLocales checks that the declared locales are distinct language
+// tags and that at most one variable, a string or select, selects the locale.
+func (v *Validator) validateLocales(tmpl *Template) []error {
+	var errs []error
+
+	seen := make(map[string]bool, len(tmpl.Locales))
+	for i, locale := range tmpl.Locales {
+		switch {
+		case !IsLocale(locale):
+			errs = append(errs, fmt.Errorf("locales[%d]: invalid locale %q: expected a language tag such as de or pt-BR", i, locale))
+		case seen[locale]:
+			errs = append(errs, fmt.Errorf("locales[%d]: duplicate locale %q", i, locale))
+		}
+		seen[locale] = true
+	}
+
+	count := 0
+	for _, variable := range tmpl.Variables {
+		if variable.Role != RoleLocale {
+			continue
+		}
+		count++
+		if variable.Type != VariableTypeString && variable.Type != VariableTypeSelect {
+			errs = append(errs, fmt.Errorf("variable %q with role %q must be of type %q or %q",
+				variable.Name, RoleLocale, VariableTypeString, VariableTypeSelect))
+		}
+	}
+	if count > 1 {
+		errs = append(errs, fmt.Errorf("template %q has %d variables with role %q, but may have at most one", tmpl.Name, count, RoleLocale))
+	}
+
+	return errs
+}
+
+// validateDelimiters checks that custom action delimiters are a pair of
+// distinct, non-empty strings without whitespace.
+func validateDelimiters(delims []string) error {
+	if len(delims) == 0 {
+		return nil
+	}
+
+	if len(delims) != 2 || delims[0] == "" || delims[1] == "" ||
+		strings.ContainsAny(delims[0]+delims[1], " \t\r\n") || delims[0] == delims[1] {
+		return fmt.Errorf("delimiters %q: expected two distinct delimiters without spaces, e.g. [\"[[\", \"]]\"]", delims)
+	}
+	return nil
+}
+
 func (v *Validator) validateVariableOptions(index int, variable Variable) error {
 	if variable.Type != VariableTypeSelect && variable.Type != VariableTypeMultiSelect {
 		if len(variable.Options) > 0 {
@@ -173,14 +507,14 @@ func (v *Validator) validateVariableOptions(index int, variable Variable) error
 
 	seen := make(map[string]struct{}, len(variable.Options))
 	for optionIndex, option := range variable.Options {
-		if option == "" {
+		if option.Value == "" {
 			return fmt.Errorf("variable[%d] %q: option[%d] must not be empty", index, variable.Name, optionIndex)
 		}
 
-		if _, ok := seen[option]; ok {
-			return fmt.Errorf("variable[%d] %q: duplicate option %q", index, variable.Name, option)
+		if _, ok := seen[option.Value]; ok {
+			return fmt.Errorf("variable[%d] %q: duplicate option %q", index, variable.Name, option.Value)
 		}
-		seen[option] = struct{}{}
+		seen[option.Value] = struct{}{}
 	}
 
 	return nil
@@ -188,7 +522,7 @@ func (v *Validator) validateVariableOptions(index int, variable Variable) error
 
 func (v *Validator) validateVariableValue(variable Variable, value any) error {
 	switch variable.Type {
-	case VariableTypeString:
+	case VariableTypeString, VariableTypeSecret:
 		if _, ok := value.(string); !ok {
 			return fmt.Errorf("expected type %s, got %T", variable.Type, value)
 		}
@@ -247,9 +581,9 @@ func isIntegerValue(value any) bool {
 	}
 }
 
-func containsOption(options []string, value string) bool {
+func containsOption(options []Option, value string) bool {
 	for _, option := range options {
-		if option == value {
+		if option.Value == value {
 			return true
 		}
 	}
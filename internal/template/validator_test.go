@@ -34,8 +34,8 @@ func TestValidator_Validate(t *testing.T) {
 
 		err := v.Validate(tmpl)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "Name")
-		assert.Contains(t, err.Error(), "Version")
+		assert.Contains(t, err.Error(), "name: field is required")
+		assert.Contains(t, err.Error(), "version: field is required")
 	})
 
 	t.Run("invalid type fails", func(t *testing.T) {
@@ -47,7 +47,7 @@ func TestValidator_Validate(t *testing.T) {
 
 		err := v.Validate(tmpl)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "Type")
+		assert.Contains(t, err.Error(), `type: "invalid" is not one of project, feature, component`)
 	})
 }
 
@@ -83,7 +83,7 @@ func TestValidator_ValidateVariables(t *testing.T) {
 
 		err := v.Validate(tmpl)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "Prompt")
+		assert.Contains(t, err.Error(), "variables[0].prompt: field is required")
 	})
 
 	t.Run("select without options fails", func(t *testing.T) {
@@ -92,7 +92,7 @@ func TestValidator_ValidateVariables(t *testing.T) {
 			Type:    TypeProject,
 			Version: "1.0.0",
 			Variables: []Variable{
-				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: []string{}},
+				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: NewOptions()},
 			},
 		}
 
@@ -123,7 +123,7 @@ func TestValidator_ValidateVariables(t *testing.T) {
 			Version: "1.0.0",
 			Variables: []Variable{
 				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
-				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: []string{"a", "b"}},
+				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: NewOptions("a", "b")},
 			},
 		}
 
@@ -138,7 +138,7 @@ func TestValidator_ValidateVariables(t *testing.T) {
 			Version: "1.0.0",
 			Variables: []Variable{
 				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
-				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: []string{"a", ""}},
+				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: NewOptions("a", "")},
 			},
 		}
 
@@ -154,7 +154,7 @@ func TestValidator_ValidateVariables(t *testing.T) {
 			Version: "1.0.0",
 			Variables: []Variable{
 				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
-				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: []string{"a", "a"}},
+				{Name: "choice", Prompt: "Choose?", Type: VariableTypeSelect, Options: NewOptions("a", "a")},
 			},
 		}
 
@@ -169,7 +169,7 @@ func TestValidator_ValidateVariables(t *testing.T) {
 			Type:    TypeProject,
 			Version: "1.0.0",
 			Variables: []Variable{
-				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName, Options: []string{"a"}},
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName, Options: NewOptions("a")},
 			},
 		}
 
@@ -178,6 +178,100 @@ func TestValidator_ValidateVariables(t *testing.T) {
 		assert.Contains(t, err.Error(), "options are only allowed")
 	})
 
+	t.Run("mask on int variable fails", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeProject,
+			Version: "1.0.0",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName, Mask: MaskSlug},
+				{Name: "port", Prompt: "Port?", Type: VariableTypeInt, Mask: MaskSlug},
+			},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `variable[1] "port": suggestions and mask are only allowed for the string type`)
+	})
+
+	t.Run("unknown suggestion provider fails", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeProject,
+			Version: "1.0.0",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName,
+					Suggestions: &Suggestions{Provider: "weather"}},
+			},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `suggestions.provider: "weather" is not one of`)
+	})
+
+	t.Run("builtin variable name fails", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeProject,
+			Version: "1.0.0",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
+				{Name: BuiltinVariable, Prompt: "Builtin?", Type: VariableTypeString},
+			},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"_blueprint" is reserved`)
+	})
+
+	t.Run("secret with default fails", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeProject,
+			Version: "1.0.0",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
+				{Name: "api_token", Prompt: "API token?", Type: VariableTypeSecret, Default: "hunter2"},
+			},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "secrets cannot have a default value")
+	})
+
+	t.Run("env_only on non-secret fails", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeProject,
+			Version: "1.0.0",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
+				{Name: "region", Prompt: "Region?", Type: VariableTypeString, EnvOnly: true},
+			},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "env_only is only allowed for the secret type")
+	})
+
+	t.Run("env-only secret passes", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeProject,
+			Version: "1.0.0",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName},
+				{Name: "api_token", Prompt: "API token?", Type: VariableTypeSecret, EnvOnly: true},
+			},
+		}
+
+		require.NoError(t, v.Validate(tmpl))
+	})
+
 	t.Run("multiple errors accumulated", func(t *testing.T) {
 		tmpl := &Template{
 			Name:    "test",
@@ -193,8 +287,9 @@ func TestValidator_ValidateVariables(t *testing.T) {
 		err := v.Validate(tmpl)
 		require.Error(t, err)
 		// All three errors should be present
-		assert.Contains(t, err.Error(), "Prompt")
+		assert.Contains(t, err.Error(), "variables[0].prompt: field is required")
 		assert.Contains(t, err.Error(), "options required")
+		assert.Contains(t, err.Error(), "variables[1].options: field is required when type is select")
 		assert.Contains(t, err.Error(), "duplicate variable name")
 	})
 }
@@ -361,7 +456,7 @@ func TestValidator_ValidateTree(t *testing.T) {
 
 		err := v.ValidateTree(root)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "Name")
+		assert.Contains(t, err.Error(), "name: field is required")
 	})
 
 	t.Run("feature including project fails", func(t *testing.T) {
@@ -709,7 +804,7 @@ func TestValidator_ValidateContext(t *testing.T) {
 		typed := &Template{
 			Name: "typed",
 			Variables: []Variable{
-				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: []string{"red", "blue"}},
+				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: NewOptions("red", "blue")},
 			},
 		}
 		ctx := NewTemplateContext(map[string]any{
@@ -723,7 +818,7 @@ func TestValidator_ValidateContext(t *testing.T) {
 		typed := &Template{
 			Name: "typed",
 			Variables: []Variable{
-				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: []string{"red", "blue"}},
+				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: NewOptions("red", "blue")},
 			},
 		}
 		ctx := NewTemplateContext(map[string]any{
@@ -738,7 +833,7 @@ func TestValidator_ValidateContext(t *testing.T) {
 		typed := &Template{
 			Name: "typed",
 			Variables: []Variable{
-				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: []string{"red", "blue"}},
+				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: NewOptions("red", "blue")},
 			},
 		}
 		ctx := NewTemplateContext(map[string]any{
@@ -753,7 +848,7 @@ func TestValidator_ValidateContext(t *testing.T) {
 		typed := &Template{
 			Name: "typed",
 			Variables: []Variable{
-				{Name: "features", Prompt: "?", Type: VariableTypeMultiSelect, Options: []string{"api", "db"}},
+				{Name: "features", Prompt: "?", Type: VariableTypeMultiSelect, Options: NewOptions("api", "db")},
 			},
 		}
 		ctx := NewTemplateContext(map[string]any{
@@ -767,7 +862,7 @@ func TestValidator_ValidateContext(t *testing.T) {
 		typed := &Template{
 			Name: "typed",
 			Variables: []Variable{
-				{Name: "features", Prompt: "?", Type: VariableTypeMultiSelect, Options: []string{"api", "db"}},
+				{Name: "features", Prompt: "?", Type: VariableTypeMultiSelect, Options: NewOptions("api", "db")},
 			},
 		}
 		ctx := NewTemplateContext(map[string]any{
@@ -839,7 +934,7 @@ func TestValidator_Validate_DefaultTypes(t *testing.T) {
 			Version: "1.0.0",
 			Variables: []Variable{
 				{Name: "app", Prompt: "?", Type: VariableTypeString, Role: RoleProjectName},
-				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: []string{"red", "blue"}, Default: "red"},
+				{Name: "color", Prompt: "?", Type: VariableTypeSelect, Options: NewOptions("red", "blue"), Default: "red"},
 			},
 		}
 
@@ -854,7 +949,7 @@ func TestValidator_Validate_DefaultTypes(t *testing.T) {
 			Version: "1.0.0",
 			Variables: []Variable{
 				{Name: "app", Prompt: "?", Type: VariableTypeString, Role: RoleProjectName},
-				{Name: "features", Prompt: "?", Type: VariableTypeMultiSelect, Options: []string{"api", "db"}, Default: []any{"api", "cache"}},
+				{Name: "features", Prompt: "?", Type: VariableTypeMultiSelect, Options: NewOptions("api", "db"), Default: []any{"api", "cache"}},
 			},
 		}
 
@@ -917,3 +1012,414 @@ func TestValidator_ValidateTreeContexts(t *testing.T) {
 		assert.Contains(t, err.Error(), "variable var_child is missing")
 	})
 }
+
+func TestValidator_ValidateEnv(t *testing.T) {
+	v := NewValidator()
+
+	t.Run("duplicate env names fail", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeFeature,
+			Version: "1.0.0",
+			Env: []EnvVar{
+				{Name: "GOPROXY"},
+				{Name: "GOPROXY"},
+			},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate environment variable")
+	})
+
+	t.Run("missing env name fails", func(t *testing.T) {
+		tmpl := &Template{
+			Name:    "test",
+			Type:    TypeFeature,
+			Version: "1.0.0",
+			Env:     []EnvVar{{Prompt: "Proxy?"}},
+		}
+
+		err := v.Validate(tmpl)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "env[0].name: field is required")
+	})
+}
+
+func TestValidator_ValidateDeprecated(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{
+		Name:    "test",
+		Type:    TypeFeature,
+		Version: "1.0.0",
+		Variables: []Variable{
+			{Name: "project_name", Prompt: "Name?", Type: VariableTypeString},
+		},
+		Deprecated: []DeprecatedVariable{
+			{Name: "app_name", RenamedTo: "project_name"},
+			{Name: "app_name", RenamedTo: "project_name"},
+			{Name: "project_name", RenamedTo: "project_name"},
+			{Name: "port", RenamedTo: "http_port"},
+		},
+	}
+
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "deprecated_variables[0]")
+	assert.Contains(t, err.Error(), `deprecated_variables[1]: duplicate deprecated variable "app_name"`)
+	assert.Contains(t, err.Error(), `deprecated_variables[2]: "project_name" is still declared as a variable`)
+	assert.Contains(t, err.Error(), `deprecated_variables[3]: "port" is renamed to undeclared variable "http_port"`)
+}
+
+func TestValidator_ValidateClean(t *testing.T) {
+	v := NewValidator()
+
+	newTemplate := func(patterns ...string) *Template {
+		return &Template{
+			Name:    "test",
+			Type:    TypeFeature,
+			Version: "1.0.0",
+			Clean:   patterns,
+		}
+	}
+
+	t.Run("relative patterns pass", func(t *testing.T) {
+		require.NoError(t, v.Validate(newTemplate("bin/", "coverage.out", "*.test")))
+	})
+
+	t.Run("patterns leaving the output directory fail", func(t *testing.T) {
+		for _, pattern := range []string{"/tmp", "..", "../other", "bin/../../x"} {
+			err := v.Validate(newTemplate(pattern))
+			require.Error(t, err, pattern)
+			assert.Contains(t, err.Error(), "must be relative", pattern)
+		}
+	})
+
+	t.Run("patterns matching any path fail", func(t *testing.T) {
+		for _, pattern := range []string{"*", ".*", "**", "*/*", "?", "[a-z]*", "./*"} {
+			err := v.Validate(newTemplate(pattern))
+			require.Error(t, err, pattern)
+			assert.Contains(t, err.Error(), "matches any path", pattern)
+		}
+		require.NoError(t, v.Validate(newTemplate("*/node_modules", ".cache", "[._]build")))
+	})
+
+	t.Run("empty and malformed patterns fail", func(t *testing.T) {
+		require.Error(t, v.Validate(newTemplate("")))
+		require.Error(t, v.Validate(newTemplate("bin/[")))
+	})
+}
+
+func TestValidator_ValidateTreeContexts_ReportsAllMissing(t *testing.T) {
+	v := NewValidator()
+
+	root := &TemplateNode{
+		ID: "0",
+		Template: &Template{
+			Name: "root",
+			Variables: []Variable{
+				{Name: "app_name", Prompt: "App name?", Type: VariableTypeString},
+				{Name: "module_path", Prompt: "Module?", Type: VariableTypeString},
+			},
+		},
+		Children: []*TemplateNode{
+			{
+				ID: "0.0",
+				Template: &Template{
+					Name:      "child",
+					Variables: []Variable{{Name: "port", Prompt: "Port?", Type: VariableTypeInt}},
+				},
+			},
+		},
+	}
+
+	err := v.ValidateTreeContexts(root, RenderContexts{
+		"0":   NewTemplateContext(map[string]any{"module_path": "example.com/app"}),
+		"0.0": NewTemplateContext(map[string]any{}),
+	})
+
+	var missingErr *MissingVariablesError
+	require.ErrorAs(t, err, &missingErr)
+	require.Len(t, missingErr.Variables, 2)
+	assert.Equal(t, MissingVariable{Template: "root", NodeID: "0", Name: "app_name", Prompt: "App name?"}, missingErr.Variables[0])
+	assert.Equal(t, MissingVariable{Template: "child", NodeID: "0.0", Name: "port", Prompt: "Port?"}, missingErr.Variables[1])
+}
+
+func TestValidator_ValidateFileModes(t *testing.T) {
+	v := NewValidator()
+
+	newTemplate := func(mode string) *Template {
+		return &Template{
+			Name:    "test",
+			Type:    TypeFeature,
+			Version: "1.0.0",
+			Files:   []File{{Src: "run.sh", Dest: "run.sh", Mode: mode}},
+		}
+	}
+
+	for _, mode := range []string{"", "0755", "755", "0o644"} {
+		require.NoError(t, v.Validate(newTemplate(mode)), mode)
+	}
+
+	for _, mode := range []string{"rwx", "0999", "01755", "-1"} {
+		err := v.Validate(newTemplate(mode))
+		require.Error(t, err, mode)
+		assert.Contains(t, err.Error(), "invalid mode", mode)
+	}
+}
+
+func TestValidator_ValidateFunctions(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0", Functions: []string{"crypto", "network"}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Functions = []string{"crypto", "sprig"}
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `functions[1]: unknown function library "sprig"`)
+}
+
+func TestValidator_ValidateDelimiters(t *testing.T) {
+	v := NewValidator()
+
+	newTemplate := func(delims, fileDelims []string) *Template {
+		return &Template{
+			Name:       "test",
+			Type:       TypeFeature,
+			Version:    "1.0.0",
+			Delimiters: delims,
+			Files:      []File{{Src: "a.tmpl", Dest: "a", Delimiters: fileDelims}},
+		}
+	}
+
+	require.NoError(t, v.Validate(newTemplate(nil, nil)))
+	require.NoError(t, v.Validate(newTemplate([]string{"[[", "]]"}, []string{"<%", "%>"})))
+
+	for _, delims := range [][]string{{"[["}, {"[[", ""}, {"[ [", "]]"}, {"##", "##"}, {"a", "b", "c"}} {
+		err := v.Validate(newTemplate(delims, nil))
+		require.Error(t, err, delims)
+		assert.Contains(t, err.Error(), "expected two distinct delimiters", delims)
+
+		err = v.Validate(newTemplate(nil, delims))
+		require.Error(t, err, delims)
+		assert.Contains(t, err.Error(), "files[0]: delimiters", delims)
+	}
+}
+
+func TestValidator_ValidateLocales(t *testing.T) {
+	v := NewValidator()
+
+	newTemplate := func(locales []string, variables ...Variable) *Template {
+		return &Template{
+			Name:      "test",
+			Type:      TypeFeature,
+			Version:   "1.0.0",
+			Locales:   locales,
+			Variables: variables,
+		}
+	}
+	language := Variable{Name: "language", Prompt: "Language?", Type: VariableTypeString, Role: RoleLocale}
+
+	t.Run("language tags and one locale variable pass", func(t *testing.T) {
+		require.NoError(t, v.Validate(newTemplate([]string{"de", "pt-BR"}, language)))
+	})
+
+	t.Run("invalid and duplicate locales fail", func(t *testing.T) {
+		err := v.Validate(newTemplate([]string{"german", "de", "de"}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `locales[0]: invalid locale "german"`)
+		assert.Contains(t, err.Error(), `locales[2]: duplicate locale "de"`)
+	})
+
+	t.Run("locale variable must be a string or select", func(t *testing.T) {
+		err := v.Validate(newTemplate(nil, Variable{Name: "language", Prompt: "Language?", Type: VariableTypeBool, Role: RoleLocale}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `variable "language" with role "locale" must be of type`)
+	})
+
+	t.Run("more than one locale variable fails", func(t *testing.T) {
+		other := language
+		other.Name = "docs_language"
+		err := v.Validate(newTemplate(nil, language, other))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "may have at most one")
+	})
+}
+
+func TestValidator_ValidateRequires(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0", Requires: &Requirements{
+		Projects:   []string{"go-api"},
+		OnMismatch: MismatchWarn,
+	}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Requires.OnMismatch = "ignore"
+	require.Error(t, v.Validate(tmpl))
+
+	tmpl.Requires.OnMismatch = ""
+	tmpl.Type = TypeProject
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only feature and component templates can declare required projects")
+
+	// Any template can require tools.
+	tmpl.Variables = []Variable{{Name: "app_name", Prompt: "App name?", Type: VariableTypeString, Role: RoleProjectName}}
+	tmpl.Requires = &Requirements{Tools: []string{"go >= 1.22", "docker"}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Requires.Tools = append(tmpl.Requires.Tools, ">= 20")
+	err = v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires.tools[2]")
+}
+
+func TestValidator_ValidateVerify(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0", Verify: []string{"go build ./...", "go test ./..."}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Verify = append(tmpl.Verify, " ")
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "verify[2]: command must not be empty")
+}
+
+func TestValidator_ValidateFileAction(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0", Files: []File{
+		{Src: "route.go.tmpl", Dest: "main.go", Action: ActionInject, Anchor: "routes"},
+	}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Files[0].Anchor = ""
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "files[0]: anchor is required for action inject")
+
+	tmpl.Files[0].Anchor = "routes"
+	tmpl.Files[0].Target = TargetHome
+	err = v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "injected files must target the project")
+
+	tmpl.Files[0] = File{Src: "main.go", Dest: "main.go", Anchor: "routes"}
+	err = v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "anchor is only used with action inject")
+
+	tmpl.Files[0] = File{Src: "main.go", Dest: "main.go", Action: "append"}
+	require.Error(t, v.Validate(tmpl))
+}
+
+func TestValidator_ValidateFileGlobs(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0", Files: []File{
+		{Src: "static", Dest: "static", Include: []string{"**/*.go.tmpl"}, Exclude: []string{"**/*.png"}},
+	}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Files[0].Include = []string{"[a-"}
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "files[0]: include: invalid pattern")
+
+	tmpl.Files[0].Include = nil
+	tmpl.Files[0].Exclude = []string{"../secrets"}
+	err = v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "files[0]: exclude: pattern \"../secrets\" must be relative")
+}
+
+func TestValidator_ValidateForEach(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0",
+		Variables: []Variable{
+			{Name: "services", Prompt: "Services?", Type: VariableTypeMultiSelect, Options: []Option{{Label: "Users", Value: "users"}}},
+			{Name: "module", Prompt: "Module?", Type: VariableTypeString},
+		},
+		Files: []File{{Src: "handler.go.tmpl", Dest: "internal/{{ .item }}/handler.go", ForEach: "services"}},
+	}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.Files[0].ForEach = "module"
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `files[0]: for_each: variable "module" is a string, not a multiselect`)
+
+	tmpl.Files[0].ForEach = "handlers"
+	err = v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `files[0]: for_each: template does not declare variable "handlers"`)
+
+	tmpl.Files[0].ForEach = "services"
+	tmpl.Variables[1].Name = ForEachItemVariable
+	err = v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `variable "item" is reserved for the element of for_each`)
+}
+
+func TestValidator_ValidateGoEdits(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{Name: "test", Type: TypeFeature, Version: "1.0.0", GoEdits: []GoEdit{
+		{File: "main.go", Import: "log/slog"},
+		{File: "{{ .package }}/server.go", Struct: "Server", Field: "logger *slog.Logger"},
+		{File: "{{ .package }}/server.go", Func: "Server.routes", Statement: `mux.HandleFunc("/users", users)`},
+	}}
+	require.NoError(t, v.Validate(tmpl))
+
+	tmpl.GoEdits = []GoEdit{
+		{File: "main.go", Import: "os", Field: "x int"},
+		{File: "main.go", Field: "x int"},
+		{File: "main.py", Func: "main", Statement: "run()"},
+		{File: "main.go", Statement: "run()"},
+		{Import: "os"},
+	}
+	err := v.Validate(tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "go_edits[0]: exactly one of import, field, and statement must be set")
+	assert.Contains(t, err.Error(), "go_edits[1]: struct is required with field")
+	assert.Contains(t, err.Error(), `go_edits[2]: file "main.py" must be a relative path to a .go file`)
+	assert.Contains(t, err.Error(), "go_edits[3]: func is required with statement")
+	assert.Contains(t, err.Error(), "go_edits[4].file: field is required")
+}
+
+func TestValidator_ValidateMetadata(t *testing.T) {
+	v := NewValidator()
+
+	tmpl := &Template{
+		Name:     "test",
+		Type:     TypeFeature,
+		Version:  "1.0.0",
+		Tags:     []string{"go", "http"},
+		Keywords: []string{"rest", "router"},
+		Author:   "Jane Doe <jane@example.com>",
+		License:  "(MIT OR Apache-2.0) AND BSD-3-Clause",
+		Homepage: "https://example.com/templates/test",
+	}
+	require.NoError(t, v.Validate(tmpl))
+	require.NoError(t, v.ValidateMetadata(tmpl.Metadata()))
+
+	tmpl.Tags = []string{"go", "Go", "web api"}
+	tmpl.Keywords = []string{""}
+	tmpl.Author = "Jane\nDoe"
+	tmpl.License = "MIT OR"
+	tmpl.Homepage = "example.com"
+	for _, err := range []error{v.Validate(tmpl), v.ValidateMetadata(tmpl.Metadata())} {
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `tags[1]: duplicate "Go"`)
+		assert.Contains(t, err.Error(), `tags[2]: "web api" must not contain spaces or commas`)
+		assert.Contains(t, err.Error(), "keywords[0]: must not be empty")
+		assert.Contains(t, err.Error(), "author: must be a single line")
+		assert.Contains(t, err.Error(), `license "MIT OR": expected an SPDX license expression`)
+		assert.Contains(t, err.Error(), `homepage "example.com": expected an http or https URL`)
+	}
+}
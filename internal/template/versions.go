@@ -0,0 +1,231 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// maxVersionRounds bounds how often versions are chosen again because a
+// chosen version brought in other constraints.
+const maxVersionRounds = 10
+
+// VersionLister is implemented by resolvers that can offer several versions
+// of a template, for example from different sources.
+type VersionLister interface {
+	Versions(name string) ([]string, error)
+}
+
+// VersionRequirement is a version constraint an include places on the
+// template it includes.
+type VersionRequirement struct {
+	By         string // Template declaring the include
+	Constraint string
+}
+
+// VersionResolution is the version chosen for a template that includes
+// constrain: the newest available version that satisfies all of them.
+type VersionResolution struct {
+	Template     string
+	Version      string
+	Requirements []VersionRequirement
+}
+
+// versionSelection holds the versions chosen for a composition and the
+// templates loaded while choosing them, which composition reuses.
+type versionSelection struct {
+	chosen      map[string]string // Version chosen for each constrained template
+	loaded      map[TemplateRef]*LoadedTemplate
+	resolutions []VersionResolution
+}
+
+// load resolves and loads a template, reusing the templates loaded before.
+func (s *versionSelection) load(c *Composer, name string) (*LoadedTemplate, error) {
+	ref := TemplateRef{Name: name, Version: s.chosen[name]}
+	if loaded, ok := s.loaded[ref]; ok {
+		return loaded, nil
+	}
+
+	resolved, err := c.resolver.Resolve(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve included template '%s': %w", name, err)
+	}
+	loaded, err := c.loader.Load(resolved.FS, resolved.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load included template '%s' from %s: %w", name, resolved.Path, err)
+	}
+	s.loaded[ref] = loaded
+	return loaded, nil
+}
+
+// selectVersions chooses the version of every template that an include in
+// the tree of root constrains. All declared includes count, whether or not
+// they end up enabled, so the choice does not depend on answers to prompts.
+// Each template is composed at a single version: the newest available one
+// that satisfies every constraint on it. Since the chosen version of a
+// template decides which constraints its own includes add, versions are
+// chosen again until the choice settles. When no version of a template
+// satisfies its constraints, older versions of the constrained templates
+// that require it are tried before the conflict is reported.
+func (c *Composer) selectVersions(root *LoadedTemplate, mandated map[Type][]string) (*versionSelection, error) {
+	loaded := map[TemplateRef]*LoadedTemplate{}
+	excluded := map[string][]string{}
+	var first error // The conflict reported when backtracking does not help
+
+	for {
+		sel := &versionSelection{chosen: map[string]string{}, loaded: loaded}
+		err := c.chooseVersions(sel, root, mandated, excluded)
+
+		var conflict *VersionConflictError
+		if !errors.As(err, &conflict) {
+			if err != nil {
+				return nil, err
+			}
+			return sel, nil
+		}
+		if first == nil {
+			first = err
+		}
+
+		backtracked := false
+		for i := len(conflict.Requirements) - 1; i >= 0 && !backtracked; i-- {
+			by := conflict.Requirements[i].By
+			if version, ok := sel.chosen[by]; ok {
+				excluded[by] = append(excluded[by], version)
+				backtracked = true
+			}
+		}
+		if !backtracked {
+			return nil, first
+		}
+	}
+}
+
+// chooseVersions chooses versions, skipping the excluded ones, until the
+// choice settles.
+func (c *Composer) chooseVersions(sel *versionSelection, root *LoadedTemplate, mandated map[Type][]string, excluded map[string][]string) error {
+	for round := 0; round < maxVersionRounds; round++ {
+		requirements, order := c.collectRequirements(sel, root, mandated)
+
+		chosen := make(map[string]string, len(order))
+		resolutions := make([]VersionResolution, 0, len(order))
+		for _, name := range order {
+			version, err := c.newestAllowed(name, requirements[name], excluded[name])
+			if err != nil {
+				return err
+			}
+			chosen[name] = version
+			resolutions = append(resolutions, VersionResolution{Template: name, Version: version, Requirements: requirements[name]})
+		}
+
+		settled := len(chosen) == len(sel.chosen)
+		for name, version := range chosen {
+			settled = settled && sel.chosen[name] == version
+		}
+		sel.chosen = chosen
+		sel.resolutions = resolutions
+		if settled {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("include versions did not settle after %d rounds", maxVersionRounds)
+}
+
+// collectRequirements walks the declared includes of the tree, with the
+// versions chosen so far, and returns the version constraints on each
+// template in the order the templates are first constrained. Includes that
+// cannot be resolved are skipped; composition reports them if they are
+// enabled.
+func (c *Composer) collectRequirements(sel *versionSelection, root *LoadedTemplate, mandated map[Type][]string) (map[string][]VersionRequirement, []string) {
+	requirements := make(map[string][]VersionRequirement)
+	var order []string
+	visited := make(map[string]bool)
+
+	var walk func(tmpl *Template, stack []string)
+	walk = func(tmpl *Template, stack []string) {
+		includes := appendMandated(slices.Clone(tmpl.Includes), tmpl, stack, mandated)
+		for _, inc := range includes {
+			if inc.Version != "" {
+				if _, ok := requirements[inc.Name]; !ok {
+					order = append(order, inc.Name)
+				}
+				requirements[inc.Name] = append(requirements[inc.Name], VersionRequirement{By: tmpl.Name, Constraint: inc.Version})
+			}
+			if visited[inc.Name] || slices.Contains(stack, inc.Name) {
+				continue
+			}
+			visited[inc.Name] = true
+
+			loaded, err := sel.load(c, inc.Name)
+			if err != nil {
+				continue
+			}
+			walk(loaded.Template, append(slices.Clone(stack), inc.Name))
+		}
+	}
+	walk(root.Template, []string{root.Template.Name})
+
+	return requirements, order
+}
+
+// newestAllowed returns the newest available version of a template that
+// satisfies every requirement and is not excluded.
+func (c *Composer) newestAllowed(name string, requirements []VersionRequirement, excluded []string) (string, error) {
+	constraints := make([]*VersionConstraint, 0, len(requirements))
+	for _, req := range requirements {
+		constraint, err := ParseVersionConstraint(req.Constraint)
+		if err != nil {
+			return "", fmt.Errorf("include '%s' of %s: %w", name, req.By, err)
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	available, err := c.availableVersions(name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, version := range available {
+		allowed := !slices.Contains(excluded, version)
+		for _, constraint := range constraints {
+			allowed = allowed && constraint.Allows(version)
+		}
+		if allowed {
+			return version, nil
+		}
+	}
+	return "", &VersionConflictError{Template: name, Requirements: requirements, Available: available}
+}
+
+// availableVersions returns the versions of a template the resolver offers,
+// newest first. A resolver that cannot list versions offers the one it
+// resolves.
+func (c *Composer) availableVersions(name string) ([]string, error) {
+	var versions []string
+	if lister, ok := c.resolver.(VersionLister); ok {
+		listed, err := lister.Versions(name)
+		if err != nil {
+			return nil, err
+		}
+		versions = listed
+	} else {
+		resolved, err := c.resolver.Resolve(TemplateRef{Name: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve included template '%s': %w", name, err)
+		}
+		loaded, err := c.loader.Load(resolved.FS, resolved.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load included template '%s' from %s: %w", name, resolved.Path, err)
+		}
+		versions = []string{loaded.Template.Version}
+	}
+	if len(versions) == 0 {
+		return nil, &TemplateNotFoundError{Name: name}
+	}
+
+	versions = slices.Clone(versions)
+	sort.SliceStable(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) > 0 })
+	return slices.Compact(versions), nil
+}
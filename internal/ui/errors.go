@@ -2,9 +2,15 @@ package ui
 
 import (
 	"errors"
+	"io/fs"
 	"os"
+	"syscall"
 
 	"github.com/dhanush0x96c/blueprint/internal/cli"
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/oci"
+	"github.com/dhanush0x96c/blueprint/internal/publish"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 )
 
@@ -12,12 +18,99 @@ import (
 func RenderError(err error) {
 	var templateNotFoundErr *template.TemplateNotFoundError
 	var invalidTemplateTypeErr *cli.InvalidTemplateTypeError
+	var lockedErr *scaffold.LockedError
+	var collisionErr *template.CollisionError
+	var versionConflictErr *template.VersionConflictError
+	var incompatibleErr *template.IncompatibleError
+	var dependencyErr *template.DependencyConflictError
+	var alreadyAddedErr *scaffold.AlreadyAddedError
+	var componentNotFoundErr *scaffold.ComponentNotFoundError
+	var regenConflictErr *scaffold.RegenConflictError
+	var notAddedErr *scaffold.NotAddedError
+	var removeConflictErr *scaffold.RemoveConflictError
+	var anchorErr *template.AnchorNotFoundError
+	var goTargetErr *template.GoTargetNotFoundError
+	var outsideErr *template.OutsideOutputError
+	var postInitDeniedErr *scaffold.PostInitDeniedError
+	var missingToolsErr *scaffold.MissingToolsError
+	var missingErr *template.MissingVariablesError
+	var validationErr *template.ValidationError
+	var renderErr *template.RenderError
+	var conflictErr *install.ConflictError
+	var notInstalledErr *install.NotInstalledError
+	var notGitRepoErr *install.NotGitRepoError
+	var noUpstreamErr *install.NoUpstreamError
+	var checksumErr *install.ChecksumMismatchError
+	var signatureErr *install.SignatureError
+	var versionExistsErr *publish.VersionExistsError
+	var registryAuthErr *oci.AuthError
+	var nothingToUndoErr *scaffold.NothingToUndoError
+	var undoConflictErr *scaffold.UndoConflictError
+	var pathErr *fs.PathError
 
 	switch {
 	case errors.As(err, &templateNotFoundErr):
 		renderTemplateNotFound(templateNotFoundErr)
 	case errors.As(err, &invalidTemplateTypeErr):
 		renderInvalidTemplateType(invalidTemplateTypeErr)
+	case errors.As(err, &lockedErr):
+		renderLocked(lockedErr)
+	case errors.As(err, &collisionErr):
+		renderCollision(collisionErr)
+	case errors.As(err, &versionConflictErr):
+		renderVersionConflict(versionConflictErr)
+	case errors.As(err, &incompatibleErr):
+		renderIncompatible(incompatibleErr)
+	case errors.As(err, &dependencyErr):
+		renderDependencyConflict(dependencyErr)
+	case errors.As(err, &alreadyAddedErr):
+		renderAlreadyAdded(alreadyAddedErr)
+	case errors.As(err, &componentNotFoundErr):
+		renderComponentNotFound(componentNotFoundErr)
+	case errors.As(err, &regenConflictErr):
+		renderRegenConflict(regenConflictErr)
+	case errors.As(err, &notAddedErr):
+		renderNotAdded(notAddedErr)
+	case errors.As(err, &removeConflictErr):
+		renderRemoveConflict(removeConflictErr)
+	case errors.As(err, &anchorErr):
+		renderAnchorNotFound(anchorErr)
+	case errors.As(err, &goTargetErr):
+		renderGoTargetNotFound(goTargetErr)
+	case errors.As(err, &outsideErr):
+		renderOutsideOutput(outsideErr)
+	case errors.As(err, &postInitDeniedErr):
+		renderPostInitDenied(postInitDeniedErr)
+	case errors.As(err, &missingToolsErr):
+		renderMissingTools(missingToolsErr)
+	case errors.As(err, &missingErr):
+		renderMissingVariables(missingErr)
+	case errors.As(err, &validationErr):
+		renderValidation(validationErr)
+	case errors.As(err, &renderErr):
+		renderTemplateError(renderErr)
+	case errors.As(err, &conflictErr):
+		renderInstallConflict(conflictErr)
+	case errors.As(err, &notInstalledErr):
+		renderNotInstalled(notInstalledErr)
+	case errors.As(err, &notGitRepoErr):
+		renderNotGitRepo(notGitRepoErr)
+	case errors.As(err, &noUpstreamErr):
+		renderNoUpstream(noUpstreamErr)
+	case errors.As(err, &checksumErr):
+		renderChecksumMismatch(checksumErr)
+	case errors.As(err, &signatureErr):
+		renderSignature(signatureErr)
+	case errors.As(err, &versionExistsErr):
+		renderVersionExists(versionExistsErr)
+	case errors.As(err, &registryAuthErr):
+		renderRegistryAuth(registryAuthErr)
+	case errors.As(err, &nothingToUndoErr):
+		renderNothingToUndo(nothingToUndoErr)
+	case errors.As(err, &undoConflictErr):
+		renderUndoConflict(undoConflictErr)
+	case errors.As(err, &pathErr) && isFilesystemError(pathErr):
+		renderFilesystem(pathErr)
 	default:
 		renderDefault(err)
 	}
@@ -26,3 +119,23 @@ func RenderError(err error) {
 func renderDefault(err error) {
 	write(os.Stderr, "error: %v\n", err)
 }
+
+// isFilesystemError reports whether err is caused by the filesystem refusing a
+// write rather than by Blueprint or the template.
+func isFilesystemError(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EROFS)
+}
+
+// joinedErrors flattens errors joined with errors.Join into a list.
+func joinedErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, joinedErrors(e)...)
+	}
+	return errs
+}
@@ -2,8 +2,11 @@ package ui
 
 import (
 	"errors"
+	"io/fs"
 
 	"github.com/dhanush0x96c/blueprint/internal/cli"
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 )
 
@@ -22,12 +25,78 @@ const (
 func ExitCode(err error) int {
 	var templateNotFoundErr *template.TemplateNotFoundError
 	var invalidTemplateTypeErr *cli.InvalidTemplateTypeError
+	var collisionErr *template.CollisionError
+	var versionConflictErr *template.VersionConflictError
+	var incompatibleErr *template.IncompatibleError
+	var dependencyErr *template.DependencyConflictError
+	var variableConflictErr *template.VariableConflictError
+	var anchorErr *template.AnchorNotFoundError
+	var goTargetErr *template.GoTargetNotFoundError
+	var outsideErr *template.OutsideOutputError
+	var postInitDeniedErr *scaffold.PostInitDeniedError
+	var missingToolsErr *scaffold.MissingToolsError
+	var toolCheckErr *scaffold.ToolCheckFailedError
+	var missingErr *template.MissingVariablesError
+	var lintErr *template.LintError
+	var testErr *scaffold.TestFailedError
+	var smokeErr *scaffold.SmokeFailedError
+	var validationErr *template.ValidationError
+	var schemaErr *template.UnsupportedSchemaError
+	var blueprintVersionErr *template.UnsupportedVersionError
+	var notInstalledErr *install.NotInstalledError
+	var checksumErr *install.ChecksumMismatchError
+	var signatureErr *install.SignatureError
+	var pathErr *fs.PathError
 
 	switch {
 	case errors.As(err, &templateNotFoundErr):
 		return ExitTemplateNotFound
+	case errors.As(err, &notInstalledErr):
+		return ExitTemplateNotFound
 	case errors.As(err, &invalidTemplateTypeErr):
 		return ExitInvalidArguments
+	case errors.As(err, &missingErr):
+		return ExitInvalidArguments
+	case errors.As(err, &collisionErr):
+		return ExitValidationFailed
+	case errors.As(err, &versionConflictErr):
+		return ExitValidationFailed
+	case errors.As(err, &incompatibleErr):
+		return ExitValidationFailed
+	case errors.As(err, &dependencyErr):
+		return ExitValidationFailed
+	case errors.As(err, &variableConflictErr):
+		return ExitValidationFailed
+	case errors.As(err, &anchorErr):
+		return ExitValidationFailed
+	case errors.As(err, &goTargetErr):
+		return ExitValidationFailed
+	case errors.As(err, &outsideErr):
+		return ExitValidationFailed
+	case errors.As(err, &postInitDeniedErr):
+		return ExitValidationFailed
+	case errors.As(err, &missingToolsErr):
+		return ExitValidationFailed
+	case errors.As(err, &toolCheckErr):
+		return ExitValidationFailed
+	case errors.As(err, &lintErr):
+		return ExitValidationFailed
+	case errors.As(err, &testErr):
+		return ExitValidationFailed
+	case errors.As(err, &smokeErr):
+		return ExitValidationFailed
+	case errors.As(err, &validationErr):
+		return ExitValidationFailed
+	case errors.As(err, &schemaErr):
+		return ExitValidationFailed
+	case errors.As(err, &checksumErr):
+		return ExitValidationFailed
+	case errors.As(err, &signatureErr):
+		return ExitValidationFailed
+	case errors.As(err, &blueprintVersionErr):
+		return ExitValidationFailed
+	case errors.As(err, &pathErr) && isFilesystemError(pathErr):
+		return ExitFilesystemError
 	default:
 		return ExitGeneralError
 	}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// plain is set by SetPlain.
+var plain bool
+
+// asciiSymbols maps the symbols of styled output to ASCII stand-ins.
+var asciiSymbols = strings.NewReplacer(
+	"✓", "ok",
+	"✗", "x",
+	"⚠", "!",
+	"•", "*",
+	"→", "->",
+	"←", "<-",
+	"—", "-",
+	"─", "-",
+	"├", "|",
+	"│", "|",
+	"└", "`",
+)
+
+// SetPlain disables colors and replaces Unicode symbols such as ✓ and • with
+// ASCII in everything rendered afterwards.
+func SetPlain() {
+	plain = true
+	color.NoColor = true
+}
+
+// NoColorRequested reports whether the NO_COLOR environment variable asks
+// for output without styling. Like other tools following no-color.org, any
+// non-empty value counts.
+func NoColorRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// ascii returns s with its symbols replaced when output is plain.
+func ascii(s string) string {
+	if !plain {
+		return s
+	}
+	return asciiSymbols.Replace(s)
+}
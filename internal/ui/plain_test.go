@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureOutput runs fn with stdout, stderr, and the messages writer
+// redirected, and returns what was written to stdout and to stderr.
+func captureOutput(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+
+	outR, outW, err := os.Pipe()
+	require.NoError(t, err)
+	errR, errW, err := os.Pipe()
+	require.NoError(t, err)
+
+	prevStdout, prevStderr, prevMessages := os.Stdout, os.Stderr, messages
+	os.Stdout, os.Stderr = outW, errW
+	if messages == prevStdout {
+		messages = outW
+	} else {
+		messages = errW
+	}
+	defer func() { os.Stdout, os.Stderr, messages = prevStdout, prevStderr, prevMessages }()
+
+	var outBuf, errBuf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&outBuf, outR)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(&errBuf, errR)
+		done <- struct{}{}
+	}()
+
+	fn()
+
+	require.NoError(t, outW.Close())
+	require.NoError(t, errW.Close())
+	<-done
+	<-done
+	return outBuf.String(), errBuf.String()
+}
+
+// usePlain turns plain output on for the rest of the test.
+func usePlain(t *testing.T) {
+	t.Helper()
+	prevPlain, prevNoColor := plain, color.NoColor
+	t.Cleanup(func() { plain, color.NoColor = prevPlain, prevNoColor })
+	SetPlain()
+}
+
+// assertASCII fails the test for every non-ASCII rune in s.
+func assertASCII(t *testing.T, s string) {
+	t.Helper()
+	for _, r := range s {
+		if r > 0x7f {
+			assert.Failf(t, "non-ASCII output", "%q in:\n%s", r, s)
+		}
+	}
+}
+
+func TestPlainOutputIsASCII(t *testing.T) {
+	usePlain(t)
+
+	t.Run("success", func(t *testing.T) {
+		stdout, stderr := captureOutput(t, func() {
+			RenderResult(&scaffold.Result{
+				FilesWritten: []string{"main.go"},
+				Backups:      []scaffold.Backup{{Path: "README.md", Backup: "README.md.bak"}},
+				Mandated:     []string{"license"},
+				PostInit: []scaffold.PostInitResult{
+					{Command: "go mod tidy", Status: scaffold.PostInitSucceeded},
+					{Command: "git init", Status: scaffold.PostInitCompleted},
+				},
+			})
+		})
+		assert.Contains(t, stdout, "ok main.go")
+		assert.Contains(t, stdout, "README.md -> README.md.bak")
+		assertASCII(t, stdout+stderr)
+	})
+
+	t.Run("warning", func(t *testing.T) {
+		stdout, stderr := captureOutput(t, func() {
+			RenderNotifyFailed(errors.New("connection refused"))
+		})
+		assert.Contains(t, stderr, "! Notification failed: connection refused")
+		assertASCII(t, stdout+stderr)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		stdout, stderr := captureOutput(t, func() {
+			RenderResult(&scaffold.Result{
+				PostInit: []scaffold.PostInitResult{
+					{Command: "go test ./...", Status: scaffold.PostInitFailed, Err: errors.New("exit status 1")},
+				},
+			})
+			RenderError(&scaffold.LockedError{Dir: "app", PID: 42, Hostname: "host", Created: time.Now()})
+			RenderError(&template.CollisionError{Collisions: []template.Collision{
+				{Path: "main.go", Templates: []string{"go-cli", "go-api"}},
+			}})
+		})
+		assert.Contains(t, stdout, "x go test ./... (exit status 1)")
+		assert.Contains(t, stderr, "x Output directory is locked: app")
+		assert.Contains(t, stderr, "main.go <- go-cli, go-api")
+		assertASCII(t, stdout+stderr)
+	})
+}
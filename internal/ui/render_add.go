@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+func renderIncompatible(err *template.IncompatibleError) {
+	w := os.Stderr
+
+	write(w, "✗ %s cannot be added to this %s project:\n", err.Template, err.Project)
+	for _, m := range err.Mismatches {
+		write(w, "  %s\n", m)
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  The template declares the projects it supports under requires.")
+	writeln(w, "  Pick a feature made for this project, or set on_mismatch: warn in the template to add it anyway.")
+}
+
+func renderAlreadyAdded(err *scaffold.AlreadyAddedError) {
+	w := os.Stderr
+
+	write(w, "✗ %s is already part of the project (node %s)\n", err.Template, err.Node)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Use --force to add it again; files it writes are overwritten.")
+}
+
+func renderAnchorNotFound(err *template.AnchorNotFoundError) {
+	w := os.Stderr
+
+	write(w, "✗ %s cannot inject into %s: no %s%s anchor\n", err.Template, err.Path, template.AnchorPrefix, err.Anchor)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	write(w, "  Add a comment containing %s%s to %s where the code belongs, e.g.\n", template.AnchorPrefix, err.Anchor, err.Path)
+	write(w, "    // %s%s\n", template.AnchorPrefix, err.Anchor)
+}
+
+func renderGoTargetNotFound(err *template.GoTargetNotFoundError) {
+	w := os.Stderr
+
+	write(w, "✗ %s cannot edit %s: no %s %s\n", err.Template, err.Path, err.Kind, err.Name)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	write(w, "  The template's go_edits add to %s %s; declare it in %s, or check the file the edit names.\n",
+		err.Kind, err.Name, err.Path)
+}
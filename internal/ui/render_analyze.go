@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// AnalysisReport describes where a composed template uses its variables.
+type AnalysisReport struct {
+	Template string             `json:"template"`
+	Files    []FileVariables    `json:"files"`
+	Includes []IncludeVariables `json:"includes"`
+	Matrix   []VariableUsage    `json:"matrix"`
+}
+
+// FileVariables lists the variables referenced by a template file.
+type FileVariables struct {
+	Template  string   `json:"template"`
+	Src       string   `json:"src"`
+	Dest      string   `json:"dest"`
+	Variables []string `json:"variables"`
+}
+
+// IncludeVariables lists the variables referenced by an included template.
+type IncludeVariables struct {
+	Name      string   `json:"name"`
+	ID        string   `json:"id"`
+	Variables []string `json:"variables"`
+}
+
+// VariableUsage lists the files that reference a variable. DeclaredBy is empty
+// for variables that are referenced but not declared by any template.
+type VariableUsage struct {
+	Name       string   `json:"name"`
+	DeclaredBy string   `json:"declared_by,omitempty"`
+	Files      []string `json:"files"`
+}
+
+// RenderAnalysis renders a variable usage report to stdout.
+func RenderAnalysis(report *AnalysisReport, asJSON bool) error {
+	w := os.Stdout
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	nameColor.Fprintln(w, report.Template)
+
+	if len(report.Files) > 0 {
+		writeln(w, "\nFiles:")
+		for _, f := range report.Files {
+			write(w, "  %s (%s)\n", f.Dest, f.Template)
+			descColor.Fprintf(w, "    %s\n", joinOrNone(f.Variables))
+		}
+	}
+
+	if len(report.Includes) > 0 {
+		writeln(w, "\nIncludes:")
+		for _, inc := range report.Includes {
+			write(w, "  %s [%s]\n", inc.Name, inc.ID)
+			descColor.Fprintf(w, "    %s\n", joinOrNone(inc.Variables))
+		}
+	}
+
+	if len(report.Matrix) > 0 {
+		writeln(w, "\nVariable usage:")
+		for _, v := range report.Matrix {
+			line := "  " + v.Name
+			switch {
+			case v.DeclaredBy == "":
+				line += " (undeclared)"
+			case len(v.Files) == 0:
+				line += " (unused)"
+			}
+			writeln(w, line)
+			for _, f := range v.Files {
+				write(w, "    • %s\n", f)
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinOrNone(names []string) string {
+	if len(names) == 0 {
+		return "no variables"
+	}
+	return strings.Join(names, ", ")
+}
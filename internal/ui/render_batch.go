@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"os"
+)
+
+// BatchEntry is the outcome of scaffolding one record of a batch.
+type BatchEntry struct {
+	Record    int
+	OutputDir string
+	Files     int
+	Err       error
+}
+
+// RenderBatchResult prints one line per record processed by `blueprint batch`.
+func RenderBatchResult(entries []BatchEntry, dryRun bool) {
+	w := os.Stdout
+
+	verb := "wrote"
+	if dryRun {
+		verb = "would write"
+	}
+
+	succeeded := 0
+	for _, e := range entries {
+		if e.Err != nil {
+			write(w, "  ✗ record %d: %v\n", e.Record, e.Err)
+			continue
+		}
+		succeeded++
+		write(w, "  ✓ record %d: %s (%s %d files)\n", e.Record, nameColor.Sprint(e.OutputDir), verb, e.Files)
+	}
+
+	write(w, "\n%d of %d records scaffolded.\n", succeeded, len(entries))
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderCleanResult prints the paths handled by `blueprint clean` to stdout.
+func RenderCleanResult(result *scaffold.CleanResult, dryRun bool) {
+	w := os.Stdout
+
+	if len(result.Removed) == 0 && len(result.Protected) == 0 {
+		writeln(w, "Nothing to clean.")
+		return
+	}
+
+	if len(result.Removed) > 0 {
+		if dryRun {
+			writeln(w, "Would remove:")
+		} else {
+			writeln(w, "Removed:")
+		}
+		for _, p := range result.Removed {
+			write(w, "  ✓ %s\n", p)
+		}
+	}
+
+	if len(result.Protected) > 0 {
+		writeln(w, "\nKept (contains scaffolded files):")
+		for _, p := range result.Protected {
+			write(w, "  - %s\n", p)
+		}
+	}
+}
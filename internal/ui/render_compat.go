@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// CompatReport lists the constructs of a template that cannot be rendered.
+type CompatReport struct {
+	Source string            `json:"source"`
+	Issues []CompatIssueInfo `json:"issues"`
+}
+
+// CompatIssueInfo describes a single unsupported construct.
+type CompatIssueInfo struct {
+	File      string `json:"file"`
+	Line      int    `json:"line,omitempty"`
+	Construct string `json:"construct"`
+	Message   string `json:"message"`
+}
+
+// RenderCompatReport renders a compatibility report to stdout.
+func RenderCompatReport(report *CompatReport, asJSON bool) error {
+	w := os.Stdout
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if len(report.Issues) == 0 {
+		write(w, "✓ %s can be rendered without changes\n", report.Source)
+		return nil
+	}
+
+	file := ""
+	for _, issue := range report.Issues {
+		if issue.File != file {
+			if file != "" {
+				writeln(w, "")
+			}
+			file = issue.File
+			nameColor.Fprintln(w, file)
+		}
+
+		if issue.Line > 0 {
+			write(w, "  %d: ", issue.Line)
+		} else {
+			write(w, "  ")
+		}
+		write(w, "%s ", issue.Construct)
+		descColor.Fprintln(w, issue.Message)
+	}
+
+	write(w, "\n%d unsupported construct(s) found\n", len(report.Issues))
+	return nil
+}
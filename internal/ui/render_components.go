@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderComponents prints the components added to the project at root with
+// the values of their variables.
+func RenderComponents(components []manifest.Component, root string) {
+	w := os.Stdout
+
+	if len(components) == 0 {
+		write(w, "No components added to %s\n", root)
+		writeln(w, "Add one with: blueprint add <component>")
+		return
+	}
+
+	nameWidth, templateWidth := 0, 0
+	for _, c := range components {
+		nameWidth = max(nameWidth, len(c.Name))
+		templateWidth = max(templateWidth, len(c.Template)+len(c.Version)+1)
+	}
+
+	for _, c := range components {
+		fmt.Fprint(w, "  ")
+		nameColor.Fprintf(w, "%-*s ", nameWidth+columnPadding, c.Name)
+		write(w, "%-*s ", templateWidth+columnPadding, c.Template+"@"+c.Version)
+		descColor.Fprintf(w, "generated %s\n", c.GeneratedAt.Local().Format("2006-01-02"))
+		if params := componentParams(c.Variables); params != "" {
+			write(w, "  %-*s %s\n", nameWidth+columnPadding, "", params)
+		}
+	}
+}
+
+// componentParams formats the variables of a component as they are given
+// with --var.
+func componentParams(variables map[string]any) string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		params = append(params, fmt.Sprintf("%s=%v", name, variables[name]))
+	}
+	return strings.Join(params, " ")
+}
+
+func renderComponentNotFound(err *scaffold.ComponentNotFoundError) {
+	w := os.Stderr
+
+	write(w, "✗ No component named %q in %s\n", err.Name, err.Dir)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  List the components of the project with: blueprint components list")
+}
+
+func renderRegenConflict(err *scaffold.RegenConflictError) {
+	w := os.Stderr
+
+	write(w, "✗ Files of component %s changed since it was generated:\n", err.Component)
+	for _, p := range err.Files {
+		write(w, "  %s\n", p)
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Pass --force to overwrite them and discard the changes.")
+}
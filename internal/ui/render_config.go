@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/config"
+)
+
+// RenderConfigList prints the effective settings and the config file they
+// were loaded from.
+func RenderConfigList(path string, entries []config.Entry) {
+	w := os.Stdout
+
+	descColor.Fprintf(w, "# %s", path)
+	if _, err := os.Stat(path); err != nil {
+		descColor.Fprint(w, " (not found)")
+	}
+	writeln(w, "")
+
+	for _, e := range entries {
+		value := strings.ReplaceAll(strings.TrimRight(config.FormatValue(e.Value), "\n"), "\n", `\n`)
+		nameColor.Fprint(w, e.Key)
+		write(w, " = %s\n", value)
+	}
+}
+
+// RenderConfigValue prints the value of a single setting.
+func RenderConfigValue(value any) {
+	if s := config.FormatValue(value); s != "" {
+		writeln(os.Stdout, s)
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ContextReport lists the variables collected for every template of a tree.
+type ContextReport struct {
+	Template string            `json:"template" yaml:"template"`
+	Nodes    []ContextNodeInfo `json:"nodes" yaml:"nodes"`
+}
+
+// ContextNodeInfo holds the final variables of a single template.
+type ContextNodeInfo struct {
+	ID        string         `json:"id" yaml:"id"`
+	Template  string         `json:"template" yaml:"template"`
+	Variables map[string]any `json:"variables" yaml:"variables"`
+}
+
+// RenderContextReport renders the collected variables to stdout as YAML, or
+// as JSON when asJSON is set.
+func RenderContextReport(report *ContextReport, asJSON bool) error {
+	w := os.Stdout
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	return enc.Close()
+}
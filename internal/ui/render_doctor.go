@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"io"
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderToolChecks prints the outcome of checking the tools a template
+// requires, with the path and version of each tool found.
+func RenderToolChecks(templateName string, checks []scaffold.ToolCheck) {
+	w := os.Stdout
+
+	if len(checks) == 0 {
+		write(w, "Template %s requires no tools.\n", nameColor.Sprint(templateName))
+		return
+	}
+
+	write(w, "Tools required by %s:\n", nameColor.Sprint(templateName))
+	failed := 0
+	for _, check := range checks {
+		if check.OK() {
+			write(w, "  ✓ %s ", check.Tool)
+			descColor.Fprintf(w, "(%s)\n", toolFound(check))
+			continue
+		}
+		failed++
+		write(w, "  ✗ %s: %s\n", check.Tool, check.Problem)
+		if check.Tool.Template != templateName {
+			descColor.Fprintf(w, "      required by %s\n", check.Tool.Template)
+		}
+	}
+
+	if failed > 0 {
+		writeln(w, "")
+		renderToolsHint(w)
+	}
+}
+
+// toolFound describes where a tool was found and the version it reported.
+func toolFound(check scaffold.ToolCheck) string {
+	if check.Version == "" {
+		return check.Path
+	}
+	return check.Version + ", " + check.Path
+}
+
+func renderMissingTools(err *scaffold.MissingToolsError) {
+	w := os.Stderr
+
+	writeln(w, "✗ Required tools are missing or outdated:")
+	for _, check := range err.Checks {
+		write(w, "  %s (%s): %s\n", check.Tool, check.Tool.Template, check.Problem)
+	}
+	writeln(w, "")
+	renderToolsHint(w)
+	writeln(w, "  Pass --skip-tool-checks to scaffold anyway.")
+}
+
+func renderToolsHint(w io.Writer) {
+	writeln(w, "Hint:")
+	writeln(w, "  Install or upgrade these tools and make sure they are on the PATH, then run again.")
+	writeln(w, "  blueprint doctor <template> checks them without scaffolding.")
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderEvent prints the progress of a scaffold run, for --verbose. Events
+// go to stderr so that they do not mix with the result.
+func RenderEvent(e scaffold.Event) {
+	w := os.Stderr
+
+	switch e.Kind {
+	case scaffold.EventTemplateLoaded:
+		write(w, "→ %s ", e.Template)
+		descColor.Fprintf(w, "(node %s)\n", e.Node)
+	case scaffold.EventVariableCollected:
+		if e.Value == nil {
+			write(w, "    %s = ", e.Variable)
+			descColor.Fprintln(w, "(secret)")
+			return
+		}
+		write(w, "    %s = %v\n", e.Variable, e.Value)
+	case scaffold.EventFileWritten:
+		descColor.Fprintf(w, "  [%d/%d] ", e.Index, e.Total)
+		write(w, "%s\n", e.Path)
+	case scaffold.EventPostInitStarted:
+		write(w, "→ Running %s\n", e.Command)
+	case scaffold.EventPostInitFinished:
+		if e.Status == scaffold.PostInitFailed {
+			write(w, "✗ %s: %v\n", e.Command, e.Err)
+			return
+		}
+		write(w, "✓ %s\n", e.Command)
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"os"
+	"sort"
+
+	"github.com/dhanush0x96c/blueprint/internal/manifest"
+)
+
+// RenderExplain prints the provenance of a generated file to stdout.
+func RenderExplain(m *manifest.Manifest, file *manifest.File) {
+	w := os.Stdout
+
+	lineage := m.Lineage(file.Node)
+
+	write(w, "File:     %s\n", file.Path)
+	write(w, "Source:   %s\n", file.Source)
+	if len(lineage) > 0 {
+		node := lineage[len(lineage)-1]
+		write(w, "Template: %s@%s (ID: %s)\n", node.Template, node.Version, node.ID)
+	}
+
+	if len(lineage) > 1 {
+		writeln(w, "\nInclude chain:")
+		for i, node := range lineage {
+			write(w, "  %*s%s (ID: %s)\n", i*2, "", node.Template, node.ID)
+		}
+	}
+
+	if len(lineage) == 0 {
+		return
+	}
+
+	variables := lineage[len(lineage)-1].Variables
+	if len(variables) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writeln(w, "\nVariables:")
+	for _, name := range names {
+		write(w, "  %s = %v\n", name, variables[name])
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderExtract prints the include created from the files of a template.
+func RenderExtract(name, dir string, result *scaffold.ExtractResult) {
+	w := os.Stdout
+
+	write(w, "Created include %s in %s\n", name, dir)
+	write(w, "  %d files moved from %s\n", len(result.Moved), result.Template)
+
+	if len(result.Variables) > 0 {
+		write(w, "  Inherits: %s\n", strings.Join(result.Variables, ", "))
+	}
+
+	if len(result.Moved) > 0 {
+		writeln(w, "\nMoved:")
+		for _, p := range result.Moved {
+			write(w, "  - %s\n", p)
+		}
+	}
+
+	writeln(w, "")
+	addedColor.Fprintf(w, ascii("✓ %s renders %d files identically\n"), result.Template, result.Verified)
+	write(w, "  Review %s before committing the change.\n", filepath.Join(dir, "template.yaml"))
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// Formats of the graph command.
+const (
+	GraphFormatTree    = "tree"
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+// GraphFormats lists the formats RenderGraph supports.
+var GraphFormats = []string{GraphFormatTree, GraphFormatDOT, GraphFormatMermaid}
+
+// GraphNode describes a template of a composed tree and its includes for the
+// graph command.
+type GraphNode struct {
+	Name             string
+	ID               string
+	Type             template.Type
+	Version          string
+	EnabledByDefault bool
+	When             string
+	Mandated         bool
+	Variables        []string // Variables the template contributes
+	Includes         []*GraphNode
+}
+
+// RenderGraph renders the include graph rooted at root to stdout in the
+// given format.
+func RenderGraph(root *GraphNode, format string) error {
+	w := os.Stdout
+
+	switch format {
+	case GraphFormatTree:
+		renderGraphTree(w, root)
+	case GraphFormatDOT:
+		renderGraphDOT(w, root)
+	case GraphFormatMermaid:
+		renderGraphMermaid(w, root)
+	default:
+		return fmt.Errorf("unknown graph format %q", format)
+	}
+	return nil
+}
+
+// graphStatus describes when an include is composed.
+func graphStatus(node *GraphNode) string {
+	switch {
+	case node.Mandated:
+		return "mandated"
+	case node.When != "":
+		return "when " + node.When
+	case node.EnabledByDefault:
+		return "default"
+	default:
+		return "optional"
+	}
+}
+
+func renderGraphTree(w io.Writer, root *GraphNode) {
+	nameColor.Fprintf(w, "%s", root.Name)
+	write(w, " %s ", root.Version)
+	colorForType(root.Type).Fprintln(w, root.Type)
+	if len(root.Variables) > 0 {
+		descColor.Fprintf(w, "vars: %s\n", strings.Join(root.Variables, ", "))
+	}
+	renderGraphIncludes(w, root.Includes, "")
+}
+
+func renderGraphIncludes(w io.Writer, includes []*GraphNode, indent string) {
+	for i, inc := range includes {
+		branch, next := "├── ", "│   "
+		if i == len(includes)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		write(w, "%s%s", indent, branch)
+		nameColor.Fprintf(w, "%s", inc.Name)
+		write(w, " %s ", inc.Version)
+		colorForType(inc.Type).Fprintf(w, "%s", inc.Type)
+		descColor.Fprintf(w, " [%s]\n", graphStatus(inc))
+		if len(inc.Variables) > 0 {
+			descColor.Fprintf(w, "%s%svars: %s\n", ascii(indent), ascii(next), strings.Join(inc.Variables, ", "))
+		}
+		renderGraphIncludes(w, inc.Includes, indent+next)
+	}
+}
+
+// graphLabel returns the label of a node in DOT and Mermaid output, with
+// lines separated by newline. Only includes are labeled with their status.
+func graphLabel(node *GraphNode, include bool, newline string) string {
+	label := node.Name + " " + node.Version + newline + string(node.Type)
+	if include {
+		label += " (" + graphStatus(node) + ")"
+	}
+	if len(node.Variables) > 0 {
+		label += newline + "vars: " + strings.Join(node.Variables, ", ")
+	}
+	return label
+}
+
+func renderGraphDOT(w io.Writer, root *GraphNode) {
+	writeln(w, "digraph blueprint {")
+	writeln(w, "  node [shape=box];")
+
+	var walk func(node *GraphNode)
+	walk = func(node *GraphNode) {
+		write(w, "  %q [label=%q];\n", node.ID, graphLabel(node, node != root, "\n"))
+		for _, inc := range node.Includes {
+			style := ""
+			if !inc.EnabledByDefault && !inc.Mandated {
+				style = " [style=dashed]"
+			}
+			write(w, "  %q -> %q%s;\n", node.ID, inc.ID, style)
+			walk(inc)
+		}
+	}
+	walk(root)
+
+	writeln(w, "}")
+}
+
+// mermaidEscaper replaces the characters Mermaid interprets in quoted labels
+// with entity codes. # goes first, since entity codes start with it.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+	"&", "#amp;",
+)
+
+func renderGraphMermaid(w io.Writer, root *GraphNode) {
+	writeln(w, "flowchart TD")
+
+	id := func(node *GraphNode) string {
+		return "n" + strings.ReplaceAll(node.ID, ".", "_")
+	}
+
+	var walk func(node *GraphNode)
+	walk = func(node *GraphNode) {
+		label := strings.ReplaceAll(mermaidEscaper.Replace(graphLabel(node, node != root, "\n")), "\n", "<br/>")
+		write(w, "  %s[\"%s\"]\n", id(node), label)
+		for _, inc := range node.Includes {
+			arrow := "-->"
+			if !inc.EnabledByDefault && !inc.Mandated {
+				arrow = "-.->"
+			}
+			write(w, "  %s %s %s\n", id(node), arrow, id(inc))
+			walk(inc)
+		}
+	}
+	walk(root)
+}
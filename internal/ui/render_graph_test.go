@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+)
+
+// graphFixture returns a tree whose names and conditions need quoting: a
+// name with a space and quotes, and a condition with Mermaid-reserved
+// characters.
+func graphFixture() *GraphNode {
+	return &GraphNode{
+		Name:      "go api",
+		ID:        "0",
+		Type:      template.TypeProject,
+		Version:   "1.0.0",
+		Variables: []string{"app_name"},
+		Includes: []*GraphNode{
+			{
+				Name:             `say "hi"`,
+				ID:               "0.0",
+				Type:             template.TypeFeature,
+				Version:          "0.1.0",
+				EnabledByDefault: true,
+			},
+			{
+				Name:    "db",
+				ID:      "0.1",
+				Type:    template.TypeFeature,
+				Version: "2.0.0",
+				When:    `replicas > 1 && engine == "pg#1"`,
+				Includes: []*GraphNode{
+					{Name: "migrations", ID: "0.1.0", Type: template.TypeComponent, Version: "1.0.0", Mandated: true},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderGraphDOT(t *testing.T) {
+	var buf bytes.Buffer
+	renderGraphDOT(&buf, graphFixture())
+
+	assert.Equal(t, `digraph blueprint {
+  node [shape=box];
+  "0" [label="go api 1.0.0\nproject\nvars: app_name"];
+  "0" -> "0.0";
+  "0.0" [label="say \"hi\" 0.1.0\nfeature (default)"];
+  "0" -> "0.1" [style=dashed];
+  "0.1" [label="db 2.0.0\nfeature (when replicas > 1 && engine == \"pg#1\")"];
+  "0.1" -> "0.1.0";
+  "0.1.0" [label="migrations 1.0.0\ncomponent (mandated)"];
+}
+`, buf.String())
+}
+
+func TestRenderGraphMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	renderGraphMermaid(&buf, graphFixture())
+
+	assert.Equal(t, `flowchart TD
+  n0["go api 1.0.0<br/>project<br/>vars: app_name"]
+  n0 --> n0_0
+  n0_0["say #quot;hi#quot; 0.1.0<br/>feature (default)"]
+  n0 -.-> n0_1
+  n0_1["db 2.0.0<br/>feature (when replicas #gt; 1 #amp;#amp; engine == #quot;pg#35;1#quot;)"]
+  n0_1 --> n0_1_0
+  n0_1_0["migrations 1.0.0<br/>component (mandated)"]
+`, buf.String())
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// ImpactReport summarizes what a run inspected with --no-write would do.
+type ImpactReport struct {
+	Impact   scaffold.Impact
+	Commands []string // Commands Blueprint itself would run, besides post-init, e.g. the notify command
+	Network  []string // Requests Blueprint itself would make, e.g. to the notify webhook
+}
+
+// RenderImpact prints the counts of what a real run would do.
+func RenderImpact(report *ImpactReport) {
+	w := messages
+	impact := report.Impact
+
+	writeln(w, "\nImpact (nothing was written or run):")
+	write(w, "  Files:         %d new, %d overwritten (%s)\n", impact.Created, impact.Overwritten, formatSize(impact.Bytes))
+	if impact.Outside > 0 {
+		overwriteColor.Fprintf(w, "  Outside:       %d file(s) outside the output directory\n", impact.Outside)
+	}
+	write(w, "  Commands:      %d post-init", impact.Commands)
+	if len(report.Commands) > 0 {
+		write(w, ", %s", strings.Join(report.Commands, ", "))
+	}
+	writeln(w, "")
+	write(w, "  Dependencies:  %d\n", impact.Dependencies)
+	write(w, "  Network:       %d request(s)", len(report.Network))
+	if len(report.Network) > 0 {
+		write(w, " (%s)", strings.Join(report.Network, ", "))
+	}
+	writeln(w, "")
+	if impact.Commands > 0 {
+		descColor.Fprintln(w, "  Post-init commands were not run; they may write files and access the network.")
+	}
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// TemplateInfo describes a composed template for the info command.
+type TemplateInfo struct {
+	Name         string         `json:"name"`
+	Type         template.Type  `json:"type"`
+	Version      string         `json:"version"`
+	Description  string         `json:"description,omitempty"`
+	Tags         []string       `json:"tags,omitempty"`
+	Keywords     []string       `json:"keywords,omitempty"`
+	Author       string         `json:"author,omitempty"`
+	License      string         `json:"license,omitempty"`
+	Homepage     string         `json:"homepage,omitempty"`
+	Variables    []VariableInfo `json:"variables"`
+	Includes     []IncludeInfo  `json:"includes"`
+	Files        []FileInfo     `json:"files"`
+	Dependencies []string       `json:"dependencies"`
+	PostInit     []PostInitInfo `json:"post_init"`
+	NextSteps    []string       `json:"next_steps,omitempty"` // Unrendered, one per template
+}
+
+// PostInitInfo describes a post-init command.
+type PostInitInfo struct {
+	Command string `json:"command"`
+	WorkDir string `json:"workdir,omitempty"`
+}
+
+// VariableInfo describes a template variable.
+type VariableInfo struct {
+	Name    string                `json:"name"`
+	Type    template.VariableType `json:"type"`
+	Prompt  string                `json:"prompt"`
+	Default any                   `json:"default,omitempty"`
+	Options []string              `json:"options,omitempty"`
+	Role    template.VariableRole `json:"role,omitempty"`
+	When    string                `json:"when,omitempty"`
+}
+
+// IncludeInfo describes an included template and its own includes.
+type IncludeInfo struct {
+	Name             string         `json:"name"`
+	ID               string         `json:"id"`
+	Type             template.Type  `json:"type"`
+	EnabledByDefault bool           `json:"enabled_by_default"`
+	When             string         `json:"when,omitempty"`
+	Mandated         bool           `json:"mandated,omitempty"`
+	Variables        []VariableInfo `json:"variables,omitempty"`
+	Includes         []IncludeInfo  `json:"includes,omitempty"`
+}
+
+// FileInfo describes a file entry of a template.
+type FileInfo struct {
+	Template string `json:"template"`
+	Src      string `json:"src"`
+	Dest     string `json:"dest"`
+	When     string `json:"when,omitempty"`
+}
+
+// RenderTemplateInfo renders template information to stdout.
+func RenderTemplateInfo(info *TemplateInfo, asJSON bool) error {
+	w := os.Stdout
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+
+	nameColor.Fprintf(w, "%s", info.Name)
+	write(w, " %s ", info.Version)
+	colorForType(info.Type).Fprintln(w, info.Type)
+	if info.Description != "" {
+		descColor.Fprintln(w, info.Description)
+	}
+	if len(info.Tags) > 0 {
+		write(w, "Tags: %s\n", strings.Join(info.Tags, ", "))
+	}
+	if len(info.Keywords) > 0 {
+		write(w, "Keywords: %s\n", strings.Join(info.Keywords, ", "))
+	}
+	if info.Author != "" {
+		write(w, "Author: %s\n", info.Author)
+	}
+	if info.License != "" {
+		write(w, "License: %s\n", info.License)
+	}
+	if info.Homepage != "" {
+		write(w, "Homepage: %s\n", info.Homepage)
+	}
+
+	if len(info.Variables) > 0 {
+		writeln(w, "\nVariables:")
+		renderVariableInfos(w, info.Variables, "  ")
+	}
+
+	if len(info.Includes) > 0 {
+		writeln(w, "\nIncludes:")
+		renderIncludeInfos(w, info.Includes, "  ")
+	}
+
+	if len(info.Files) > 0 {
+		writeln(w, "\nFiles:")
+		for _, f := range info.Files {
+			line := fmt.Sprintf("  %s ← %s (%s)", f.Dest, f.Src, f.Template)
+			if f.When != "" {
+				line += fmt.Sprintf(" when %s", f.When)
+			}
+			writeln(w, line)
+		}
+	}
+
+	if len(info.Dependencies) > 0 {
+		writeln(w, "\nDependencies:")
+		for _, dep := range info.Dependencies {
+			write(w, "  • %s\n", dep)
+		}
+	}
+
+	if len(info.PostInit) > 0 {
+		writeln(w, "\nPost-init commands:")
+		for _, cmd := range info.PostInit {
+			if cmd.WorkDir != "" {
+				write(w, "  $ %s (in %s)\n", cmd.Command, cmd.WorkDir)
+				continue
+			}
+			write(w, "  $ %s\n", cmd.Command)
+		}
+	}
+
+	if len(info.NextSteps) > 0 {
+		writeln(w, "\nNext steps:")
+		for _, text := range info.NextSteps {
+			for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+				writeln(w, strings.TrimRight("  "+line, " "))
+			}
+		}
+	}
+
+	return nil
+}
+
+func renderVariableInfos(w io.Writer, vars []VariableInfo, indent string) {
+	for _, v := range vars {
+		line := fmt.Sprintf("%s%s (%s)", indent, v.Name, v.Type)
+		if v.Default != nil {
+			line += fmt.Sprintf(" default=%v", v.Default)
+		}
+		if len(v.Options) > 0 {
+			line += fmt.Sprintf(" options=%s", strings.Join(v.Options, "|"))
+		}
+		if v.Role != "" {
+			line += fmt.Sprintf(" role=%s", v.Role)
+		}
+		if v.When != "" {
+			line += fmt.Sprintf(" when %s", v.When)
+		}
+		writeln(w, line)
+		descColor.Fprintf(w, "%s  %s\n", indent, v.Prompt)
+	}
+}
+
+func renderIncludeInfos(w io.Writer, includes []IncludeInfo, indent string) {
+	for _, inc := range includes {
+		var flags []string
+		if inc.EnabledByDefault {
+			flags = append(flags, "enabled by default")
+		}
+		if inc.When != "" {
+			flags = append(flags, "when "+inc.When)
+		}
+		if inc.Mandated {
+			flags = append(flags, "mandated")
+		}
+
+		line := fmt.Sprintf("%s%s [%s]", indent, inc.Name, inc.Type)
+		if len(flags) > 0 {
+			line += " (" + strings.Join(flags, ", ") + ")"
+		}
+		writeln(w, line)
+
+		renderVariableInfos(w, inc.Variables, indent+"    ")
+		renderIncludeInfos(w, inc.Includes, indent+"  ")
+	}
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplateInfo(t *testing.T) {
+	usePlain(t)
+
+	info := &TemplateInfo{
+		Name:        "app",
+		Type:        template.TypeProject,
+		Version:     "1.0.0",
+		Description: "An app",
+		Tags:        []string{"go", "web"},
+		Variables: []VariableInfo{
+			{Name: "name", Type: template.VariableTypeString, Prompt: "Name?", Default: "app", Role: template.RoleProjectName},
+		},
+		Includes: []IncludeInfo{{
+			Name:             "auth",
+			ID:               "0.0",
+			Type:             template.TypeFeature,
+			EnabledByDefault: true,
+			Variables: []VariableInfo{
+				{Name: "provider", Type: template.VariableTypeSelect, Prompt: "Provider?", Options: []string{"oauth", "basic"}},
+			},
+			Includes: []IncludeInfo{
+				{Name: "session", ID: "0.0.0", Type: template.TypeFeature, When: `provider == "oauth"`, Mandated: true},
+			},
+		}},
+		Files: []FileInfo{
+			{Template: "app", Src: "main.go.tmpl", Dest: "main.go"},
+			{Template: "session", Src: "session.go.tmpl", Dest: "auth/session.go", When: `provider == "oauth"`},
+		},
+		Dependencies: []string{"golang.org/x/oauth2@v0.20.0"},
+		PostInit:     []PostInitInfo{{Command: "go mod tidy"}, {Command: "npm install", WorkDir: "web"}},
+		NextSteps:    []string{"cd app\nmake run\n"},
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		require.NoError(t, RenderTemplateInfo(info, false))
+	})
+	assert.Equal(t, `app 1.0.0 project
+An app
+Tags: go, web
+
+Variables:
+  name (string) default=app role=project_name
+    Name?
+
+Includes:
+  auth [feature] (enabled by default)
+      provider (select) options=oauth|basic
+        Provider?
+    session [feature] (when provider == "oauth", mandated)
+
+Files:
+  main.go <- main.go.tmpl (app)
+  auth/session.go <- session.go.tmpl (session) when provider == "oauth"
+
+Dependencies:
+  * golang.org/x/oauth2@v0.20.0
+
+Post-init commands:
+  $ go mod tidy
+  $ npm install (in web)
+
+Next steps:
+  cd app
+  make run
+`, stdout)
+}
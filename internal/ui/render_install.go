@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/signature"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// InstallAction is what a template management command did to templates.
+type InstallAction string
+
+const (
+	ActionInstall   InstallAction = "install"
+	ActionUpdate    InstallAction = "update"
+	ActionUninstall InstallAction = "uninstall"
+)
+
+// RenderInstallChanges prints the templates that were installed, updated, or
+// uninstalled. With dryRun, the changes are reported as planned.
+func RenderInstallChanges(action InstallAction, changes []install.Change, dir string, dryRun bool) {
+	w := os.Stdout
+
+	if dryRun {
+		writeln(w, "Dry run; nothing was changed.")
+	}
+	if len(changes) == 0 {
+		writeln(w, "No templates installed.")
+	}
+
+	for _, c := range changes {
+		switch {
+		case action == ActionUninstall:
+			removedColor.Fprintf(w, "  - %s", c.Name)
+			write(w, " %s\n", c.Previous)
+		case c.Previous == "":
+			addedColor.Fprintf(w, "  + %s", c.Name)
+			write(w, " %s\n", c.Version)
+		case c.Previous == c.Version:
+			overwriteColor.Fprintf(w, "  ~ %s", c.Name)
+			write(w, " %s", c.Version)
+			descColor.Fprintf(w, " (reinstalled)\n")
+		default:
+			overwriteColor.Fprintf(w, "  ~ %s", c.Name)
+			write(w, " %s → %s\n", c.Previous, c.Version)
+		}
+	}
+
+	if action != ActionUninstall && len(changes) > 0 {
+		descColor.Fprintf(w, "\nFrom %s\n", installOrigin(changes[0].Record))
+	}
+	descColor.Fprintf(w, "Templates directory: %s\n", dir)
+}
+
+// RenderInstalledTemplates prints the templates installed into the user
+// templates directory and where they came from.
+func RenderInstalledTemplates(records []install.Record, dir string) {
+	w := os.Stdout
+
+	if len(records) == 0 {
+		write(w, "No templates installed in %s\n", dir)
+		writeln(w, "Install templates with: blueprint template install <source>")
+		return
+	}
+
+	nameWidth, typeWidth, versionWidth := 0, 0, 0
+	for _, r := range records {
+		nameWidth = max(nameWidth, len(r.Name))
+		typeWidth = max(typeWidth, len(r.Type))
+		versionWidth = max(versionWidth, len(r.Version))
+	}
+
+	for _, r := range records {
+		fmt.Fprint(w, "  ")
+		nameColor.Fprintf(w, "%-*s ", nameWidth+columnPadding, r.Name)
+		colorForType(template.Type(r.Type)).Fprintf(w, "%-*s ", typeWidth+columnPadding, r.Type)
+		write(w, "%-*s ", versionWidth+columnPadding, r.Version)
+		descColor.Fprintf(w, "%s, installed %s\n", installOrigin(r), r.InstalledAt.Local().Format("2006-01-02"))
+	}
+}
+
+// installOrigin describes the source of an installed template, with the ref
+// and commit of git sources and the digest of OCI sources.
+func installOrigin(r install.Record) string {
+	origin := r.Source
+	if r.Ref != "" {
+		origin += "@" + r.Ref
+	}
+	switch {
+	case len(r.Commit) >= 7:
+		origin += " (" + r.Commit[:7] + ")"
+	case len(r.Digest) >= 19 && !strings.Contains(r.Source, "@"):
+		origin += " (" + r.Digest[:19] + ")"
+	}
+	return origin
+}
+
+// RenderPullResult prints the commits and files a pull of the templates
+// repository brought in. With dryRun, the changes are reported as planned.
+func RenderPullResult(result *install.PullResult, dryRun bool) {
+	w := os.Stdout
+
+	if result.UpToDate() {
+		write(w, "Templates are up to date with %s (%s)\n", result.Upstream, shortCommit(result.After))
+		descColor.Fprintf(w, "Templates repository: %s\n", result.Root)
+		return
+	}
+
+	if dryRun {
+		writeln(w, "Dry run; nothing was changed.")
+		write(w, "Would pull %s (%s → %s)\n", result.Upstream, shortCommit(result.Before), shortCommit(result.After))
+	} else {
+		write(w, "✓ Pulled %s (%s → %s)\n", result.Upstream, shortCommit(result.Before), shortCommit(result.After))
+	}
+	for _, f := range result.Files {
+		overwriteColor.Fprintf(w, "  ~ %s\n", f)
+	}
+	descColor.Fprintf(w, "Templates repository: %s\n", result.Root)
+}
+
+// RenderTemplatesRepoHint points out that the templates directory is a git
+// repository, which is updated with pull rather than update.
+func RenderTemplatesRepoHint(root string) {
+	descColor.Fprintf(os.Stdout, "\nThe templates directory is in the git repository %s.\n", root)
+	descColor.Fprintln(os.Stdout, "Update it with: blueprint template pull")
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+func renderNotGitRepo(err *install.NotGitRepoError) {
+	w := os.Stderr
+
+	write(w, "✗ Templates directory %s is not a git repository\n", err.Dir)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Clone your templates repository and point templates_dir at the checkout:")
+	writeln(w, "    blueprint config set templates_dir ~/src/templates")
+	writeln(w, "  Templates installed with `blueprint template install` are updated with `blueprint template update`.")
+}
+
+func renderNoUpstream(err *install.NoUpstreamError) {
+	w := os.Stderr
+
+	write(w, "✗ The current branch of %s has no upstream branch\n", err.Dir)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Set the branch to pull from, e.g.:")
+	write(w, "    git -C %s branch --set-upstream-to origin/main\n", err.Dir)
+}
+
+func renderChecksumMismatch(err *install.ChecksumMismatchError) {
+	w := os.Stderr
+
+	write(w, "✗ %s does not match its published checksum\n", err.URL)
+	writeln(w, "")
+	write(w, "  expected: %s\n", err.Expected)
+	write(w, "  actual:   %s\n", err.Actual)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  The archive or its .sha256 file may have been altered or be incomplete.")
+	writeln(w, "  Nothing was extracted; retry once the publisher has fixed the download.")
+}
+
+func renderSignature(err *install.SignatureError) {
+	w := os.Stderr
+
+	write(w, "✗ Signature verification failed for %s\n", err.Source)
+	write(w, "  %v\n", err.Err)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	if errors.Is(err.Err, signature.ErrUntrustedKey) {
+		writeln(w, "  If you trust the publisher, add their minisign public key to trusted_keys, e.g.:")
+		writeln(w, "    blueprint config set trusted_keys <key>,<other keys>")
+		return
+	}
+	writeln(w, "  With verify_signatures set, only archives signed with minisign by a key in")
+	writeln(w, "  trusted_keys are used. Ask the publisher for a signed archive, or use a local copy")
+	writeln(w, "  of a template you have reviewed.")
+}
+
+func renderInstallConflict(err *install.ConflictError) {
+	w := os.Stderr
+
+	write(w, "✗ Template %q already exists: %s\n", err.Name, err.Path)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Pass --force to replace it, or remove it first.")
+	writeln(w, "  A template of the same name elsewhere in the templates directory must be removed by hand.")
+}
+
+func renderNotInstalled(err *install.NotInstalledError) {
+	w := os.Stderr
+
+	write(w, "✗ Template %q is not installed\n", err.Name)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Run `blueprint template list` to see installed templates.")
+	writeln(w, "  Templates copied into the templates directory by hand are not managed by this command.")
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// LintReport lists the problems found in a template.
+type LintReport struct {
+	Source string          `json:"source"`
+	Issues []LintIssueInfo `json:"issues"`
+}
+
+// LintIssueInfo describes a single problem.
+type LintIssueInfo struct {
+	Template string `json:"template"`
+	File     string `json:"file,omitempty"`
+	Message  string `json:"message"`
+}
+
+// RenderLintReport renders a lint report to stdout.
+func RenderLintReport(report *LintReport, asJSON bool) error {
+	w := os.Stdout
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if len(report.Issues) == 0 {
+		write(w, "✓ %s is valid\n", report.Source)
+		return nil
+	}
+
+	tmpl := ""
+	for _, issue := range report.Issues {
+		if issue.Template != tmpl {
+			if tmpl != "" {
+				writeln(w, "")
+			}
+			tmpl = issue.Template
+			nameColor.Fprintln(w, tmpl)
+		}
+
+		write(w, "  ")
+		if issue.File != "" {
+			write(w, "%s: ", issue.File)
+		}
+		descColor.Fprintln(w, issue.Message)
+	}
+
+	write(w, "\n%d problem(s) found\n", len(report.Issues))
+	return nil
+}
@@ -1,25 +1,60 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/dhanush0x96c/blueprint/internal/template"
 	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
 )
 
 // TemplateListEntry represents a single template in the list output.
 type TemplateListEntry struct {
 	Name        string
 	Type        template.Type
+	Version     string
 	Description string
+	Tags        []string
+	Keywords    []string
+	Author      string
+	License     string
+	Homepage    string
+	Path        string // Template directory, on disk or inside the source
 }
 
 // TemplateListGroup represents a group of templates from a single source.
 type TemplateListGroup struct {
-	Source  string // "BUILTIN" or "USER"
-	Entries []TemplateListEntry
+	Source     string // "BUILTIN" or "USER"
+	SourceType string // "builtin" or "user", as accepted by --source
+	Entries    []TemplateListEntry
+}
+
+// ListFormat is an output format of the template list.
+type ListFormat string
+
+const (
+	ListFormatTable ListFormat = "table"
+	ListFormatJSON  ListFormat = "json"
+	ListFormatYAML  ListFormat = "yaml"
+)
+
+// templateListItem is a template in the JSON and YAML list output.
+type templateListItem struct {
+	Name        string   `json:"name" yaml:"name"`
+	Type        string   `json:"type" yaml:"type"`
+	Version     string   `json:"version" yaml:"version"`
+	Description string   `json:"description" yaml:"description"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Keywords    []string `json:"keywords,omitempty" yaml:"keywords,omitempty"`
+	Author      string   `json:"author,omitempty" yaml:"author,omitempty"`
+	License     string   `json:"license,omitempty" yaml:"license,omitempty"`
+	Homepage    string   `json:"homepage,omitempty" yaml:"homepage,omitempty"`
+	Source      string   `json:"source" yaml:"source"`
+	Path        string   `json:"path" yaml:"path"`
 }
 
 const (
@@ -39,8 +74,10 @@ var (
 )
 
 // RenderTemplateList renders grouped template listings to stdout.
-// When showType is true, the TYPE column is displayed in table output.
-func RenderTemplateList(groups []TemplateListGroup, short, showType bool) {
+// When showType is true, the TYPE column is displayed in table output. When
+// long is true, the version and sharing metadata of every template is shown
+// below it.
+func RenderTemplateList(groups []TemplateListGroup, short, showType, long bool) {
 	w := os.Stdout
 
 	if short {
@@ -48,7 +85,45 @@ func RenderTemplateList(groups []TemplateListGroup, short, showType bool) {
 		return
 	}
 
-	renderTable(w, groups, showType)
+	renderTable(w, groups, showType, long)
+}
+
+// RenderTemplateListData renders the templates of all groups to stdout as a
+// flat JSON or YAML list for scripts and editor integrations.
+func RenderTemplateListData(groups []TemplateListGroup, format ListFormat) error {
+	w := os.Stdout
+
+	items := make([]templateListItem, 0)
+	for _, g := range groups {
+		for _, e := range g.Entries {
+			items = append(items, templateListItem{
+				Name:        e.Name,
+				Type:        string(e.Type),
+				Version:     e.Version,
+				Description: e.Description,
+				Tags:        e.Tags,
+				Keywords:    e.Keywords,
+				Author:      e.Author,
+				License:     e.License,
+				Homepage:    e.Homepage,
+				Source:      g.SourceType,
+				Path:        e.Path,
+			})
+		}
+	}
+
+	if format == ListFormatYAML {
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(items); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(items)
 }
 
 func renderShort(w io.Writer, groups []TemplateListGroup) {
@@ -59,7 +134,7 @@ func renderShort(w io.Writer, groups []TemplateListGroup) {
 	}
 }
 
-func renderTable(w io.Writer, groups []TemplateListGroup, showType bool) {
+func renderTable(w io.Writer, groups []TemplateListGroup, showType, long bool) {
 	nameWidth, typeWidth := calculateColumnWidths(groups)
 
 	for i, g := range groups {
@@ -80,10 +155,36 @@ func renderTable(w io.Writer, groups []TemplateListGroup, showType bool) {
 				colorForType(e.Type).Fprintf(w, "%-*s ", typeWidth, e.Type)
 			}
 			descColor.Fprintln(w, e.Description)
+			if long {
+				renderEntryDetails(w, e)
+			}
 		}
 	}
 }
 
+// renderEntryDetails writes the version and the metadata a template sets,
+// one per line, indented below its entry.
+func renderEntryDetails(w io.Writer, e TemplateListEntry) {
+	const indent = "      "
+
+	write(w, "%sVersion:  %s\n", indent, e.Version)
+	if len(e.Tags) > 0 {
+		write(w, "%sTags:     %s\n", indent, strings.Join(e.Tags, ", "))
+	}
+	if len(e.Keywords) > 0 {
+		write(w, "%sKeywords: %s\n", indent, strings.Join(e.Keywords, ", "))
+	}
+	if e.Author != "" {
+		write(w, "%sAuthor:   %s\n", indent, e.Author)
+	}
+	if e.License != "" {
+		write(w, "%sLicense:  %s\n", indent, e.License)
+	}
+	if e.Homepage != "" {
+		write(w, "%sHomepage: %s\n", indent, e.Homepage)
+	}
+}
+
 func calculateColumnWidths(groups []TemplateListGroup) (nameWidth, typeWidth int) {
 	for _, g := range groups {
 		for _, e := range g.Entries {
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// RenderMigrated prints the templates migrated to the newest schema, with
+// the diff of each when they were not written.
+func RenderMigrated(migrated []scaffold.MigratedTemplate, written bool) {
+	w := os.Stdout
+
+	if len(migrated) == 0 {
+		write(w, "All templates use schema %d.\n", template.SchemaVersion)
+		return
+	}
+
+	for _, m := range migrated {
+		write(w, "%s ", nameColor.Sprint(m.Path))
+		descColor.Fprintf(w, ascii("(schema %d → %d)\n"), m.Result.From, m.Result.To)
+		for _, change := range m.Result.Changes {
+			write(w, "  • %s\n", change)
+		}
+		if !written {
+			writeln(w, "")
+			renderDiff(w, m.Diff)
+		}
+	}
+
+	if written {
+		write(w, "\n%d template(s) migrated to schema %d.\n", len(migrated), template.SchemaVersion)
+		return
+	}
+	write(w, "\n%d template(s) need migrating to schema %d.\n", len(migrated), template.SchemaVersion)
+}
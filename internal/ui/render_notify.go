@@ -0,0 +1,11 @@
+package ui
+
+import "os"
+
+// RenderNotifyFailed reports that the summary of a run could not be sent.
+func RenderNotifyFailed(err error) {
+	w := os.Stderr
+
+	overwriteColor.Fprint(w, ascii("⚠ Notification failed: "))
+	writeln(w, err.Error())
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/fatih/color"
+)
+
+var overwriteColor = color.New(color.FgYellow)
+
+// planEntry is a directory or file in the tree of planned files.
+type planEntry struct {
+	name     string
+	file     *scaffold.PlannedFile // Nil for directories
+	children map[string]*planEntry
+}
+
+// renderPlan prints the files of a dry run as a tree, followed by the diffs
+// against existing files and, when they were kept, the full contents.
+func renderPlan(w io.Writer, planned []scaffold.PlannedFile) {
+	writeln(w, "\nFiles that would be written:")
+	renderPlanTree(w, buildPlanTree(planned), "  ")
+
+	var changed []scaffold.PlannedFile
+	for _, f := range planned {
+		if f.Diff != "" {
+			changed = append(changed, f)
+		}
+	}
+	if len(changed) > 0 {
+		writeln(w, "\nChanges to existing files:")
+		for _, f := range changed {
+			renderDiff(w, f.Diff)
+		}
+	}
+
+	for _, f := range planned {
+		if f.Content == nil {
+			continue
+		}
+		nameColor.Fprintf(w, "\n==> %s <==\n", f.Path)
+		if f.Binary {
+			descColor.Fprintf(w, "(binary file, %s)\n", formatSize(f.Size))
+			continue
+		}
+		write(w, "%s", f.Content)
+		if len(f.Content) > 0 && f.Content[len(f.Content)-1] != '\n' {
+			writeln(w, "")
+		}
+	}
+}
+
+// buildPlanTree arranges planned files by directory. Files outside the output
+// directory are kept at the top level under their full path.
+func buildPlanTree(planned []scaffold.PlannedFile) *planEntry {
+	root := &planEntry{children: make(map[string]*planEntry)}
+
+	for i := range planned {
+		f := &planned[i]
+
+		parts := strings.Split(f.Path, "/")
+		if path.IsAbs(f.Path) || strings.HasPrefix(f.Path, "../") {
+			parts = []string{f.Path}
+		}
+
+		dir := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := dir.children[part]
+			if !ok {
+				child = &planEntry{name: part, children: make(map[string]*planEntry)}
+				dir.children[part] = child
+			}
+			dir = child
+		}
+
+		name := parts[len(parts)-1]
+		dir.children[name] = &planEntry{name: name, file: f}
+	}
+
+	return root
+}
+
+func renderPlanTree(w io.Writer, dir *planEntry, indent string) {
+	names := make([]string, 0, len(dir.children))
+	for name := range dir.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		entry := dir.children[name]
+
+		branch, next := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		if entry.file == nil {
+			write(w, "%s%s%s/\n", indent, branch, name)
+			renderPlanTree(w, entry, indent+next)
+			continue
+		}
+
+		write(w, "%s%s%s ", indent, branch, name)
+		descColor.Fprintf(w, "(%s) ", formatSize(entry.file.Size))
+		renderPlanStatus(w, entry.file.Status)
+	}
+}
+
+func renderPlanStatus(w io.Writer, status scaffold.PlanStatus) {
+	switch status {
+	case scaffold.PlanCreate:
+		addedColor.Fprintln(w, "new")
+	case scaffold.PlanOverwrite:
+		overwriteColor.Fprintln(w, "overwrite")
+	case scaffold.PlanSkip:
+		descColor.Fprintln(w, "exists, skipped")
+	case scaffold.PlanRemove:
+		removedColor.Fprintln(w, "remove")
+	default:
+		descColor.Fprintln(w, string(status))
+	}
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/install"
+	"github.com/dhanush0x96c/blueprint/internal/oci"
+	"github.com/dhanush0x96c/blueprint/internal/publish"
+)
+
+// RenderPublishResult prints the published template, its archive and
+// checksum, and where it was published. With dryRun, the publish is reported
+// as planned.
+func RenderPublishResult(result *publish.Result, dryRun bool) {
+	w := os.Stdout
+	e := result.Entry
+
+	if dryRun {
+		writeln(w, "Dry run; nothing was published.")
+	}
+
+	addedColor.Fprintf(w, ascii("✓ %s"), e.Name)
+	if result.Previous != "" {
+		write(w, " %s → %s\n", result.Previous, e.Version)
+	} else {
+		write(w, " %s\n", e.Version)
+	}
+
+	write(w, "  Archive:  %s\n", e.Archive)
+	write(w, "  SHA-256:  %s\n", e.SHA256)
+
+	registry := result.Registry
+	if result.Branch != "" {
+		registry += " (" + result.Branch + ")"
+	}
+	if len(result.Commit) >= 7 {
+		registry += " at " + result.Commit[:7]
+	}
+	descColor.Fprintf(w, "\nRegistry: %s\n", registry)
+}
+
+// RenderPushResult prints the pushed template and the reference pinned to
+// its digest. With dryRun, the push is reported as planned.
+func RenderPushResult(result *publish.PushResult, dryRun bool) {
+	w := os.Stdout
+
+	if dryRun {
+		writeln(w, "Dry run; nothing was pushed.")
+	}
+
+	addedColor.Fprintf(w, ascii("✓ %s"), result.Name)
+	write(w, " %s\n", result.Version)
+	write(w, "  Reference: %s\n", result.Reference)
+	write(w, "  Size:      %s\n", formatSize(result.Size))
+	if result.Digest == "" {
+		return
+	}
+	write(w, "  Digest:    %s\n", result.Digest)
+	descColor.Fprintf(w, "\nInstall it with: blueprint template install %s\n", result.Pinned())
+}
+
+// RenderPackResult prints the packed template and the archive it was written
+// to. With dryRun, the archive is reported as planned.
+func RenderPackResult(result *publish.PackResult, dryRun bool) {
+	w := os.Stdout
+
+	if dryRun {
+		writeln(w, "Dry run; no archive was written.")
+	}
+
+	addedColor.Fprintf(w, ascii("✓ %s"), result.Name)
+	write(w, " %s\n", result.Version)
+	write(w, "  Archive:  %s\n", result.Path)
+	write(w, "  Files:    %d\n", result.Files)
+	write(w, "  Size:     %s\n", formatSize(result.Size))
+	descColor.Fprintf(w, "\nUse it with: blueprint init %s\n", result.Path)
+}
+
+// RenderUnpackResult prints the unpacked template and the directory it was
+// extracted to. With dryRun, the extraction is reported as planned.
+func RenderUnpackResult(result *install.UnpackResult, dryRun bool) {
+	w := os.Stdout
+
+	if dryRun {
+		writeln(w, "Dry run; nothing was extracted.")
+	}
+
+	addedColor.Fprintf(w, ascii("✓ %s"), result.Name)
+	write(w, " %s\n", result.Version)
+	write(w, "  Directory:  %s\n", result.Dir)
+	write(w, "  Files:      %d\n", result.Files)
+}
+
+func renderVersionExists(err *publish.VersionExistsError) {
+	w := os.Stderr
+
+	write(w, "✗ %s %s is already published to %s\n", err.Name, err.Version, err.Registry)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Pass --bump major, minor, or patch to publish a new version.")
+	writeln(w, "  Pass --force to replace the published archive.")
+}
+
+func renderRegistryAuth(err *oci.AuthError) {
+	w := os.Stderr
+
+	write(w, "✗ %s\n", err)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	write(w, "  Log in with `docker login %s`, or set %s and %s.\n", err.Registry, oci.EnvUsername, oci.EnvPassword)
+	writeln(w, "  Pushing needs write access to the repository.")
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"os"
+	"slices"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderRemovePlan prints the changes `blueprint remove` makes.
+func RenderRemovePlan(result *scaffold.RemoveResult) {
+	w := os.Stdout
+
+	if result.Component != "" {
+		write(w, "Removing component %s (%s, node %s)\n", result.Component, result.Template, result.Node)
+	} else {
+		write(w, "Removing %s (node %s)\n", result.Template, result.Node)
+	}
+
+	if len(result.Removed) > 0 {
+		writeln(w, "\nFiles to delete:")
+		for _, p := range result.Removed {
+			renderRemovePath(w, p, result.Modified, "(changed since it was written)")
+		}
+	}
+
+	if len(result.Reverted) > 0 {
+		writeln(w, "\nFiles to take injected code out of:")
+		for _, p := range result.Reverted {
+			renderRemovePath(w, p, nil, "")
+		}
+	}
+
+	var missing []string
+	for _, p := range result.Modified {
+		if !slices.Contains(result.Removed, p) {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		writeln(w, "\nInjected code not found, left alone:")
+		for _, p := range missing {
+			renderRemovePath(w, p, missing, "(changed since the code was injected)")
+		}
+	}
+}
+
+func renderRemovePath(w *os.File, p string, modified []string, note string) {
+	if slices.Contains(modified, p) {
+		write(w, "  ! %s ", p)
+		overwriteColor.Fprintln(w, note)
+		return
+	}
+	write(w, "  - %s\n", p)
+}
+
+// RenderRemoved confirms that a template was removed.
+func RenderRemoved(result *scaffold.RemoveResult) {
+	w := os.Stdout
+
+	name := result.Template
+	if result.Component != "" {
+		name = "component " + result.Component
+	}
+	write(w, "\n✓ Removed %s (%d files deleted, %d reverted)\n", name, len(result.Removed), len(result.Reverted))
+	writeln(w, "  Run `blueprint undo` to restore it.")
+}
+
+func renderNotAdded(err *scaffold.NotAddedError) {
+	w := os.Stderr
+
+	write(w, "✗ %s is not part of the project in %s\n", err.Name, err.Dir)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Only templates added with `blueprint add`, or included when the project was generated, can be removed.")
+	writeln(w, "  Components are removed by the name listed by: blueprint components list")
+}
+
+func renderRemoveConflict(err *scaffold.RemoveConflictError) {
+	w := os.Stderr
+
+	write(w, "✗ Files of %s changed since it was added:\n", err.Template)
+	for _, p := range err.Files {
+		write(w, "  %s\n", p)
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Pass --force to delete changed files and leave injected code that cannot be found in place.")
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/fatih/color"
+)
+
+var (
+	addedColor   = color.New(color.FgGreen)
+	removedColor = color.New(color.FgRed)
+)
+
+// RenderRenamePlan prints the changes of a project rename as a diff.
+func RenderRenamePlan(plan *scaffold.RenamePlan) {
+	w := os.Stdout
+
+	if len(plan.Changes) == 0 {
+		write(w, "No managed files reference %s.\n", plan.OldName)
+		return
+	}
+
+	write(w, "Renaming %s → %s\n\n", plan.OldName, plan.NewName)
+
+	for _, change := range plan.Changes {
+		if change.NewPath != change.Path {
+			write(w, "rename %s → %s\n", change.Path, change.NewPath)
+		}
+
+		renderDiff(w, change.Diff)
+		writeln(w, "")
+	}
+
+	if len(plan.Variables) > 0 {
+		write(w, "Manifest variables updated: %s\n", strings.Join(plan.Variables, ", "))
+	}
+}
+
+// RenderRenameApplied confirms that a rename was applied.
+func RenderRenameApplied(plan *scaffold.RenamePlan) {
+	write(os.Stdout, "\n✓ Renamed %s to %s (%d files updated)\n", plan.OldName, plan.NewName, len(plan.Changes))
+}
+
+// renderDiff prints a unified diff with added and removed lines colored.
+func renderDiff(w io.Writer, diff string) {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			nameColor.Fprint(w, line)
+		case strings.HasPrefix(line, "+"):
+			addedColor.Fprint(w, line)
+		case strings.HasPrefix(line, "-"):
+			removedColor.Fprint(w, line)
+		default:
+			write(w, "%s", line)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// RenderTemplateSkeleton prints the files of a newly created template.
+func RenderTemplateSkeleton(name, dir string, files []string) {
+	w := os.Stdout
+
+	write(w, "Created template %s in %s\n", name, dir)
+	for _, f := range files {
+		write(w, "  ✓ %s\n", filepath.ToSlash(f))
+	}
+
+	writeln(w, "")
+	writeln(w, "Next steps:")
+	write(w, "  Edit %s, then run `blueprint info %s`.\n", filepath.Join(dir, "template.yaml"), name)
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderSmokeResult prints the outcome of a smoke test: the post-init
+// commands of the scaffolded project and its verify commands, with the output
+// of each command that failed.
+func RenderSmokeResult(result *scaffold.SmokeResult) {
+	w := os.Stdout
+
+	if result.Scaffold != nil {
+		write(w, "Scaffolded %s (%d file(s))\n", nameColor.Sprint(result.Template), len(result.Scaffold.FilesWritten))
+		if len(result.Scaffold.PostInit) > 0 {
+			writeln(w, "\nPost-init commands:")
+			for _, res := range result.Scaffold.PostInit {
+				renderPostInitResult(w, res)
+			}
+		}
+	}
+
+	if len(result.Verify) > 0 {
+		writeln(w, "\nVerify commands:")
+		for _, v := range result.Verify {
+			if v.Passed {
+				write(w, "  ✓ %s ", v.Command)
+				descColor.Fprintf(w, "(%s)\n", v.Duration.Round(time.Millisecond))
+				continue
+			}
+			write(w, "  ✗ %s (%v)\n", v.Command, v.Err)
+			for _, line := range strings.Split(strings.TrimRight(string(v.Output), "\n"), "\n") {
+				descColor.Fprintf(w, "      %s\n", line)
+			}
+		}
+	} else if result.Scaffold != nil && result.Scaffold.PostInitErr() == nil {
+		write(w, "\nTemplate %s declares no verify commands.\n", result.Template)
+	}
+
+	if result.Kept {
+		write(w, "\nProject kept in %s\n", result.Dir)
+	}
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderSnapshot prints the template created from a project.
+func RenderSnapshot(name, dir string, variables []scaffold.SnapshotVariable, result *scaffold.SnapshotResult) {
+	w := os.Stdout
+
+	write(w, "Created template %s in %s\n", name, dir)
+	write(w, "  %d files, %d turned into templates\n", len(result.Files)-1, result.Templated)
+
+	if len(variables) > 0 {
+		writeln(w, "\nVariables:")
+		for _, v := range variables {
+			nameColor.Fprintf(w, "  %s", v.Name)
+			write(w, " ← %q ", v.Value)
+			descColor.Fprintf(w, "(%d replaced)\n", result.Replacements[v.Name])
+		}
+	}
+
+	if len(result.Skipped) > 0 {
+		writeln(w, "\nSkipped:")
+		for _, p := range result.Skipped {
+			write(w, "  - %s\n", p)
+		}
+	}
+
+	writeln(w, "")
+	writeln(w, "Next steps:")
+	write(w, "  Review %s, then run `blueprint validate %s`.\n", filepath.Join(dir, "template.yaml"), dir)
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"os"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/telemetry"
+)
+
+// TelemetryStatus describes the telemetry setting and the runs recorded on
+// this machine.
+type TelemetryStatus struct {
+	Enabled    bool
+	DoNotTrack bool // Telemetry is turned off by DO_NOT_TRACK
+	Endpoint   string
+	StatsPath  string
+	Stats      *telemetry.Stats
+}
+
+// RenderTelemetryStatus prints whether telemetry is on and the runs recorded
+// per template version.
+func RenderTelemetryStatus(status TelemetryStatus) {
+	w := os.Stdout
+
+	switch {
+	case status.Enabled:
+		write(w, "Telemetry is %s\n", addedColor.Sprint("on"))
+	case status.DoNotTrack:
+		write(w, "Telemetry is %s (DO_NOT_TRACK is set)\n", removedColor.Sprint("off"))
+	default:
+		write(w, "Telemetry is %s\n", removedColor.Sprint("off"))
+	}
+
+	if status.Endpoint != "" {
+		write(w, "Endpoint: %s\n", status.Endpoint)
+	}
+	if status.StatsPath != "" {
+		descColor.Fprintf(w, "# %s\n", status.StatsPath)
+	}
+
+	if status.Stats == nil || len(status.Stats.Templates) == 0 {
+		writeln(w, "\nNo runs recorded.")
+		return
+	}
+
+	nameWidth := len("TEMPLATE")
+	for _, t := range status.Stats.Templates {
+		nameWidth = max(nameWidth, len(telemetryName(t)))
+	}
+
+	write(w, "\n%-*s %6s %8s %10s  %s\n", nameWidth, "TEMPLATE", "RUNS", "FAILURES", "AVG TIME", "LAST RUN")
+	for _, t := range status.Stats.Templates {
+		nameColor.Fprintf(w, "%-*s ", nameWidth, telemetryName(t))
+		write(w, "%6d %8d %10s  %s\n",
+			t.Runs, t.Failures, t.AverageDuration().Round(10*time.Millisecond), t.LastRun.Local().Format(time.DateTime))
+	}
+}
+
+func telemetryName(t telemetry.TemplateStats) string {
+	if t.Version == "" {
+		return t.Template
+	}
+	return t.Template + "@" + t.Version
+}
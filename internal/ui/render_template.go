@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"strings"
+	"syscall"
 
 	"github.com/dhanush0x96c/blueprint/internal/cli"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
 	"github.com/dhanush0x96c/blueprint/internal/template"
 )
 
@@ -24,3 +29,188 @@ func renderInvalidTemplateType(err *cli.InvalidTemplateTypeError) {
 	writeln(w, "Hint:")
 	writeln(w, "  Valid types are: projects, features, components")
 }
+
+func renderLocked(err *scaffold.LockedError) {
+	w := os.Stderr
+
+	write(w, "✗ Output directory is locked: %s\n", err.Dir)
+	write(w, "  Held by process %d on %s\n", err.PID, err.Hostname)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Another blueprint run is scaffolding into this directory.")
+	write(w, "  If no other run is active, remove %s and try again.\n", scaffold.LockFileName)
+}
+
+func renderCollision(err *template.CollisionError) {
+	w := os.Stderr
+
+	writeln(w, "✗ Templates render conflicting files:")
+	for _, c := range err.Collisions {
+		write(w, "  %s ← %s\n", c.Path, strings.Join(c.Templates, ", "))
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Set `collision: skip` or `collision: override` on the include to resolve the conflict.")
+}
+
+func renderVersionConflict(err *template.VersionConflictError) {
+	w := os.Stderr
+
+	write(w, "✗ No version of %s satisfies every include:\n", err.Template)
+	for _, r := range err.Requirements {
+		write(w, "  %s requires %s\n", r.By, r.Constraint)
+	}
+	write(w, "  Available: %s\n", strings.Join(err.Available, ", "))
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Widen one of the constraints, or install a version that satisfies all of them.")
+}
+
+func renderDependencyConflict(err *template.DependencyConflictError) {
+	w := os.Stderr
+
+	writeln(w, "✗ Templates depend on different versions of the same package:")
+	for _, c := range err.Conflicts {
+		write(w, "  %s\n", c.Package)
+		for _, r := range c.Requests {
+			if r.Version != "" {
+				write(w, "    %s requests %s\n", r.Template, r.Version)
+			}
+		}
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Align the versions in the templates, or run without --strict-deps to use the highest version.")
+}
+
+func renderOutsideOutput(err *template.OutsideOutputError) {
+	w := os.Stderr
+
+	writeln(w, "✗ Files would be written outside the output directory:")
+	denied, hostDenied := false, false
+	for _, f := range err.Files {
+		if f.Target != "" {
+			write(w, "  %s ← %s (target: %s)\n", f.Path, f.Template, f.Target)
+		} else {
+			write(w, "  %s ← %s\n", f.Path, f.Template)
+		}
+		switch {
+		case f.Allowed:
+		case f.Target != "":
+			hostDenied = true
+		default:
+			denied = true
+		}
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	if hostDenied {
+		writeln(w, "  The dest of a file with a home or xdg-config target must stay inside that directory.")
+	}
+	if denied {
+		writeln(w, "  Fix the dest of these files, or set `allow_outside_output: true` in the template if they are meant to")
+		writeln(w, "  be written outside the project.")
+	}
+	if !denied && !hostDenied {
+		writeln(w, "  Confirm the prompt, or pass --allow-outside-output to write them.")
+	}
+}
+
+func renderPostInitDenied(err *scaffold.PostInitDeniedError) {
+	w := os.Stderr
+
+	write(w, "✗ Post-init command of %s refused: %s\n", err.Template, err.Command)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	switch {
+	case err.Operator != "":
+		write(w, "  The command uses %s, so what it runs or writes cannot be matched against post_init_allow\n", err.Operator)
+		writeln(w, "  and post_init_deny. Only commands of templates trusted with --trust may use it.")
+	case err.Pattern != "":
+		write(w, "  %q matches %q of post_init_deny.\n", err.Part, err.Pattern)
+	default:
+		write(w, "  %q matches none of the patterns of post_init_allow.\n", err.Part)
+	}
+	writeln(w, "  Pass --skip-post-init to scaffold without running post-init commands, or change the")
+	writeln(w, "  policy with blueprint config set.")
+}
+
+func renderMissingVariables(err *template.MissingVariablesError) {
+	w := os.Stderr
+
+	writeln(w, "✗ Missing required variables:")
+	for _, v := range err.Variables {
+		write(w, "  %s (%s)", v.Name, v.Template)
+		if v.Prompt != "" {
+			descColor.Fprintf(w, " %s", v.Prompt)
+		}
+		writeln(w, "")
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Pass values with --var name=value, or run in a terminal without --ci to be prompted.")
+}
+
+func renderValidation(err *template.ValidationError) {
+	w := os.Stderr
+
+	if err.Template != "" {
+		write(w, "✗ Template %s is invalid:\n", err.Template)
+	} else {
+		writeln(w, "✗ Template is invalid:")
+	}
+
+	var allowed []*template.FieldError
+	for _, e := range joinedErrors(err.Err) {
+		write(w, "  %v\n", e)
+
+		var fieldErr *template.FieldError
+		if errors.As(e, &fieldErr) && len(fieldErr.Allowed) > 0 {
+			allowed = append(allowed, fieldErr)
+		}
+	}
+
+	writeln(w, "")
+	writeln(w, "Hint:")
+	for _, fieldErr := range allowed {
+		write(w, "  Set %s to one of: %s\n", fieldErr.Field, strings.Join(fieldErr.Allowed, ", "))
+	}
+	writeln(w, "  Fields are named as in template.yaml. Run `blueprint validate <template-path>` to check a template")
+	writeln(w, "  without scaffolding it.")
+}
+
+func renderTemplateError(err *template.RenderError) {
+	w := os.Stderr
+
+	if err.Parse {
+		write(w, "✗ Template %s does not parse:\n", err.Name)
+	} else {
+		write(w, "✗ Template %s failed to render:\n", err.Name)
+	}
+	write(w, "  %v\n", err.Err)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	if err.Parse {
+		writeln(w, "  Run `blueprint validate <template-path>` to list every file that does not parse, or")
+		writeln(w, "  `blueprint compat <template>` for templates migrated from other tools.")
+	} else {
+		writeln(w, "  A template function failed for the values given. Check the values passed with --var and")
+		writeln(w, "  the defaults section of the configuration.")
+	}
+}
+
+func renderFilesystem(err *fs.PathError) {
+	w := os.Stderr
+
+	write(w, "✗ Cannot %s %s: %v\n", err.Op, err.Path, err.Err)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	switch {
+	case errors.Is(err, syscall.ENOSPC):
+		writeln(w, "  The disk is full. Free up space and try again.")
+	case errors.Is(err, syscall.EROFS):
+		writeln(w, "  The filesystem is read-only. Choose another output directory.")
+	default:
+		writeln(w, "  Check that you can write to the output directory, or choose another one.")
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderTestResults prints the outcome of each test case run by
+// `blueprint test`, with a diff of every file rendered differently.
+func RenderTestResults(templateName string, results []*scaffold.TestResult) {
+	w := os.Stdout
+
+	if len(results) == 0 {
+		write(w, "Template %s has no test cases in %s/.\n", templateName, scaffold.TestCasesDir)
+		return
+	}
+
+	passed, updated := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			write(w, "  ✗ %s: %v\n", nameColor.Sprint(r.Case), r.Err)
+			continue
+		case r.Updated:
+			updated++
+			write(w, "  ✓ %s: expected files updated\n", nameColor.Sprint(r.Case))
+			continue
+		case r.Passed():
+			passed++
+			write(w, "  ✓ %s\n", nameColor.Sprint(r.Case))
+			continue
+		}
+
+		write(w, "  ✗ %s\n", nameColor.Sprint(r.Case))
+		for _, p := range r.Missing {
+			removedColor.Fprintf(w, "      missing    %s\n", p)
+		}
+		for _, p := range r.Unexpected {
+			addedColor.Fprintf(w, "      unexpected %s\n", p)
+		}
+		for _, change := range r.Changed {
+			overwriteColor.Fprintf(w, "      changed    %s\n", change.Path)
+		}
+		for _, change := range r.Changed {
+			if change.Diff == "" {
+				continue
+			}
+			writeln(w, "")
+			renderDiff(w, change.Diff)
+		}
+	}
+
+	if updated > 0 {
+		write(w, "\n%d of %d test cases updated.\n", updated, len(results))
+		return
+	}
+	write(w, "\n%d of %d test cases passed.\n", passed, len(results))
+}
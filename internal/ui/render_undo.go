@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// RenderUndoPlan prints the changes `blueprint undo` reverts.
+func RenderUndoPlan(result *scaffold.UndoResult) {
+	w := os.Stdout
+
+	write(w, "Undoing the run of %s from %s\n", result.Template, result.RunAt.Local().Format(time.DateTime))
+
+	if len(result.Removed) > 0 {
+		writeln(w, "\nFiles to remove (created by the run):")
+		for _, p := range result.Removed {
+			renderUndoPath(w, p, result.Modified)
+		}
+	}
+
+	if len(result.Restored) > 0 {
+		writeln(w, "\nFiles to restore (overwritten or removed by the run):")
+		for _, p := range result.Restored {
+			renderUndoPath(w, p, result.Modified)
+		}
+	}
+
+	if len(result.PostInitEnv) > 0 {
+		write(w, "\nPost-init commands ran with %s set; their changes are not reverted.\n",
+			strings.Join(result.PostInitEnv, ", "))
+	}
+}
+
+func renderUndoPath(w *os.File, p string, modified []string) {
+	if slices.Contains(modified, p) {
+		write(w, "  ! %s ", p)
+		overwriteColor.Fprintln(w, "(changed since the run)")
+		return
+	}
+	write(w, "  - %s\n", p)
+}
+
+// RenderUndoApplied confirms that a run was undone.
+func RenderUndoApplied(result *scaffold.UndoResult) {
+	w := os.Stdout
+
+	write(w, "\n✓ Undid the run of %s (%d files removed, %d restored)\n",
+		result.Template, len(result.Removed), len(result.Restored))
+
+	if len(result.Kept) > 0 {
+		writeln(w, "\nDirectories kept (not empty):")
+		for _, p := range result.Kept {
+			write(w, "  - %s\n", p)
+		}
+	}
+}
+
+func renderNothingToUndo(err *scaffold.NothingToUndoError) {
+	w := os.Stderr
+
+	write(w, "✗ Nothing to undo in %s\n", err.Dir)
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Only runs of `blueprint init` and `blueprint add` into the project are journaled, and each run can be undone once.")
+}
+
+func renderUndoConflict(err *scaffold.UndoConflictError) {
+	w := os.Stderr
+
+	writeln(w, "✗ Files changed since the last run:")
+	for _, p := range err.Files {
+		write(w, "  %s\n", p)
+	}
+	writeln(w, "")
+	writeln(w, "Hint:")
+	writeln(w, "  Pass --force to undo the run anyway and discard these changes.")
+}
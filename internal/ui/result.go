@@ -1,14 +1,151 @@
 package ui
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/dhanush0x96c/blueprint/internal/scaffold"
 )
 
-// RenderResult prints a summary of the scaffolding result to stdout.
+// ResultFormat is the output format of a scaffold result.
+type ResultFormat string
+
+const (
+	ResultFormatText ResultFormat = "text"
+	ResultFormatJSON ResultFormat = "json"
+)
+
+// messages receives the human-readable summaries of a run. It is stdout
+// unless the result itself is printed there for other programs.
+var messages io.Writer = os.Stdout
+
+// MessagesToStderr prints the human-readable summaries of a run to stderr,
+// keeping stdout for machine-readable output.
+func MessagesToStderr() {
+	messages = os.Stderr
+}
+
+// resultJSON is a scaffold result in the JSON output.
+type resultJSON struct {
+	OutputDir          string            `json:"output_dir"`
+	DryRun             bool              `json:"dry_run"`
+	FilesWritten       []string          `json:"files_written"`
+	FilesSkipped       []string          `json:"files_skipped"`
+	Backups            []backupJSON      `json:"backups,omitempty"`
+	Planned            []plannedFileJSON `json:"planned,omitempty"`
+	Removed            []string          `json:"removed,omitempty"`
+	Stale              []staleFileJSON   `json:"stale,omitempty"`
+	Mandated           []string          `json:"mandated,omitempty"`
+	Dependencies       []string          `json:"dependencies"`
+	UnusedDependencies []string          `json:"unused_dependencies,omitempty"`
+	PostInitCommands   []string          `json:"post_init_commands"`
+	PostInit           []postInitJSON    `json:"post_init"`
+	PostInitEnv        []string          `json:"post_init_env,omitempty"`
+	Warnings           []string          `json:"warnings"`
+	NextSteps          []string          `json:"next_steps,omitempty"`
+	DurationMS         int64             `json:"duration_ms"`
+}
+
+type plannedFileJSON struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	Status string `json:"status"`
+	Binary bool   `json:"binary,omitempty"`
+}
+
+type backupJSON struct {
+	Path   string `json:"path"`
+	Backup string `json:"backup"`
+}
+
+type staleFileJSON struct {
+	Path     string `json:"path"`
+	Template string `json:"template"`
+	Modified bool   `json:"modified"`
+}
+
+type postInitJSON struct {
+	Command    string `json:"command"`
+	Dir        string `json:"dir"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// RenderResultJSON prints the scaffolding result as JSON to stdout. elapsed is
+// the duration of the whole run.
+func RenderResultJSON(result *scaffold.Result, dryRun bool, elapsed time.Duration) error {
+	out := resultJSON{
+		OutputDir:          result.OutputDir,
+		DryRun:             dryRun,
+		FilesWritten:       nonNil(result.FilesWritten),
+		FilesSkipped:       nonNil(result.FilesSkipped),
+		Removed:            result.Removed,
+		Mandated:           result.Mandated,
+		Dependencies:       nonNil(result.Dependencies),
+		UnusedDependencies: result.UnusedDependencies,
+		PostInitCommands:   []string{},
+		PostInit:           []postInitJSON{},
+		PostInitEnv:        result.PostInitEnv,
+		Warnings:           []string{},
+		NextSteps:          result.NextSteps,
+		DurationMS:         elapsed.Milliseconds(),
+	}
+
+	for _, f := range result.Planned {
+		out.Planned = append(out.Planned, plannedFileJSON{
+			Path:   f.Path,
+			Size:   f.Size,
+			Status: string(f.Status),
+			Binary: f.Binary,
+		})
+	}
+	for _, b := range result.Backups {
+		out.Backups = append(out.Backups, backupJSON{Path: b.Path, Backup: b.Backup})
+	}
+	for _, f := range result.Stale {
+		out.Stale = append(out.Stale, staleFileJSON{Path: f.Path, Template: f.Template, Modified: f.Modified})
+	}
+	for _, cmd := range result.PostInitCmds {
+		out.PostInitCommands = append(out.PostInitCommands, cmd.Command)
+	}
+	for _, res := range result.PostInit {
+		entry := postInitJSON{
+			Command:    res.Command,
+			Dir:        res.Dir,
+			Status:     string(res.Status),
+			DurationMS: res.Duration.Milliseconds(),
+		}
+		if res.Err != nil {
+			entry.Error = res.Err.Error()
+		}
+		out.PostInit = append(out.PostInit, entry)
+	}
+	for _, warning := range result.Warnings {
+		out.Warnings = append(out.Warnings, warning.String())
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that it is encoded as
+// an empty JSON array rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// RenderResult prints a summary of the scaffolding result.
 func RenderResult(result *scaffold.Result) {
-	w := os.Stdout
+	w := messages
 
 	if len(result.FilesWritten) > 0 {
 		writeln(w, "\nFiles written:")
@@ -17,6 +154,17 @@ func RenderResult(result *scaffold.Result) {
 		}
 	}
 
+	if len(result.Backups) > 0 {
+		writeln(w, "\nBackups of overwritten files:")
+		for _, b := range result.Backups {
+			write(w, "  %s → %s\n", b.Path, b.Backup)
+		}
+	}
+
+	if len(result.Planned) > 0 {
+		renderPlan(w, result.Planned)
+	}
+
 	if len(result.FilesSkipped) > 0 {
 		writeln(w, "\nFiles skipped (already exist):")
 		for _, f := range result.FilesSkipped {
@@ -24,6 +172,32 @@ func RenderResult(result *scaffold.Result) {
 		}
 	}
 
+	if len(result.Removed) > 0 {
+		writeln(w, "\nFiles removed (include no longer enabled):")
+		for _, f := range result.Removed {
+			write(w, "  - %s\n", f)
+		}
+	}
+
+	if len(result.Stale) > 0 {
+		writeln(w, "\nFiles kept from includes no longer enabled:")
+		for _, f := range result.Stale {
+			if f.Modified {
+				write(w, "  ! %s (%s, modified)\n", f.Path, f.Template)
+			} else {
+				write(w, "  - %s (%s)\n", f.Path, f.Template)
+			}
+		}
+		writeln(w, "  Unmodified files are removed with --prune.")
+	}
+
+	if len(result.Mandated) > 0 {
+		writeln(w, "\nMandated includes (organization policy):")
+		for _, name := range result.Mandated {
+			write(w, "  • %s\n", name)
+		}
+	}
+
 	if len(result.Dependencies) > 0 {
 		writeln(w, "\nDependencies declared:")
 		for _, dep := range result.Dependencies {
@@ -31,14 +205,83 @@ func RenderResult(result *scaffold.Result) {
 		}
 	}
 
-	if len(result.PostInitCmds) > 0 {
+	if len(result.UnusedDependencies) > 0 {
+		writeln(w, "\nDependencies no longer declared:")
+		for _, dep := range result.UnusedDependencies {
+			write(w, "  • %s\n", dep)
+		}
+	}
+
+	if len(result.PostInit) > 0 {
+		writeln(w, "\nPost-init commands:")
+		for _, res := range result.PostInit {
+			renderPostInitResult(w, res)
+		}
+		if len(result.PostInitEnv) > 0 {
+			write(w, "  env: %s\n", strings.Join(result.PostInitEnv, ", "))
+		}
+	} else if len(result.PostInitCmds) > 0 {
 		writeln(w, "\nPost-init commands:")
 		for _, cmd := range result.PostInitCmds {
 			write(w, "  $ %s\n", cmd.Command)
 		}
 	}
 
-	if len(result.FilesWritten) == 0 && len(result.FilesSkipped) == 0 {
+	if len(result.Warnings) > 0 {
+		writeln(w, "\nWarnings:")
+		for _, warning := range result.Warnings {
+			write(w, "  ! %s\n", warning)
+		}
+	}
+
+	if len(result.FilesWritten) == 0 && len(result.FilesSkipped) == 0 && len(result.Planned) == 0 && len(result.Removed) == 0 {
 		writeln(w, "No files were written.")
 	}
+
+	if len(result.NextSteps) > 0 && result.PostInitErr() == nil {
+		writeln(w, "\nNext steps:")
+		for _, text := range result.NextSteps {
+			for _, line := range strings.Split(text, "\n") {
+				writeln(w, strings.TrimRight("  "+line, " "))
+			}
+		}
+	}
+}
+
+func renderPostInitResult(w io.Writer, res scaffold.PostInitResult) {
+	switch res.Status {
+	case scaffold.PostInitSucceeded:
+		write(w, "  ✓ %s\n", res.Command)
+	case scaffold.PostInitFailed:
+		write(w, "  ✗ %s (%v)\n", res.Command, res.Err)
+	case scaffold.PostInitCompleted:
+		write(w, "  ✓ %s", res.Command)
+		descColor.Fprintf(w, " (completed earlier)\n")
+	default:
+		write(w, "  - %s (skipped)\n", res.Command)
+	}
+}
+
+// formatSize formats a file size in bytes for display.
+func formatSize(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, suffix := float64(n)/unit, "KiB"
+	for _, next := range []string{"MiB", "GiB"} {
+		if size < unit {
+			break
+		}
+		size, suffix = size/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", size, suffix)
+}
+
+// RenderAnswersSaved reports where the answers of a run were saved.
+func RenderAnswersSaved(path string) {
+	w := messages
+
+	write(w, "\nAnswers saved to %s\n", path)
+	descColor.Fprintf(w, "  Replay with: blueprint init --answers-file %s\n", path)
 }
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderResultJSON(t *testing.T) {
+	prevMessages := messages
+	t.Cleanup(func() { messages = prevMessages })
+	MessagesToStderr()
+
+	result := &scaffold.Result{
+		OutputDir:    "demo",
+		FilesWritten: []string{"main.go", "say \"hi\".txt"},
+		PostInitCmds: []template.PostInit{{Command: "go mod tidy"}, {Command: "go test ./..."}},
+		PostInit: []scaffold.PostInitResult{
+			{Command: "go mod tidy", Dir: "demo", Status: scaffold.PostInitSucceeded, Duration: 1500 * time.Millisecond},
+			{Command: "go test ./...", Dir: "demo", Status: scaffold.PostInitFailed, Err: errors.New("exit status 1")},
+		},
+		Warnings: []template.Warning{{Message: "unused variable port"}},
+	}
+
+	stdout, stderr := captureOutput(t, func() {
+		// Progress, the impact report, and the answers file are reported
+		// around the result, as init does.
+		RenderEvent(scaffold.Event{Kind: scaffold.EventTemplateLoaded, Template: "go-cli", Node: "0"})
+		RenderEvent(scaffold.Event{Kind: scaffold.EventPostInitFinished, Command: "go test ./...", Status: scaffold.PostInitFailed, Err: errors.New("exit status 1")})
+		require.NoError(t, RenderResultJSON(result, true, 2*time.Second))
+		RenderImpact(&ImpactReport{Impact: scaffold.Impact{Created: 2}})
+		RenderAnswersSaved("go-cli.answers.yaml")
+		RenderNotifyFailed(errors.New("connection refused"))
+	})
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out), "stdout holds a single JSON document:\n%s", stdout)
+	assert.Equal(t, "demo", out["output_dir"])
+	assert.Equal(t, true, out["dry_run"])
+	assert.Equal(t, []any{"main.go", "say \"hi\".txt"}, out["files_written"])
+	assert.Equal(t, []any{}, out["files_skipped"])
+	assert.Equal(t, []any{"go mod tidy", "go test ./..."}, out["post_init_commands"])
+	assert.Equal(t, []any{
+		map[string]any{"command": "go mod tidy", "dir": "demo", "status": "succeeded", "duration_ms": float64(1500)},
+		map[string]any{"command": "go test ./...", "dir": "demo", "status": "failed", "error": "exit status 1", "duration_ms": float64(0)},
+	}, out["post_init"])
+	assert.Equal(t, []any{"unused variable port"}, out["warnings"])
+	assert.Equal(t, float64(2000), out["duration_ms"])
+
+	assert.Contains(t, stderr, "go-cli")
+	assert.Contains(t, stderr, "go test ./...: exit status 1")
+	assert.Contains(t, stderr, "Impact (nothing was written or run)")
+	assert.Contains(t, stderr, "Answers saved to go-cli.answers.yaml")
+	assert.Contains(t, stderr, "Notification failed: connection refused")
+}
+
+func TestRenderResultJSONEmptyLists(t *testing.T) {
+	stdout, _ := captureOutput(t, func() {
+		require.NoError(t, RenderResultJSON(&scaffold.Result{OutputDir: "demo"}, false, 0))
+	})
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(stdout), &out))
+	for _, key := range []string{"files_written", "files_skipped", "dependencies", "post_init_commands", "post_init", "warnings"} {
+		assert.Equal(t, []any{}, out[key], key)
+	}
+}
@@ -6,9 +6,9 @@ import (
 )
 
 func write(w io.Writer, format string, args ...any) {
-	_, _ = fmt.Fprintf(w, format, args...)
+	_, _ = fmt.Fprint(w, ascii(fmt.Sprintf(format, args...)))
 }
 
 func writeln(w io.Writer, s string) {
-	_, _ = fmt.Fprintln(w, s)
+	_, _ = fmt.Fprintln(w, ascii(s))
 }
@@ -0,0 +1,25 @@
+package vars
+
+import "github.com/dhanush0x96c/blueprint/internal/template"
+
+// BuiltinCollector sets the builtin variable on every node. It runs before
+// the other collectors, so a value given in the configuration or with --var
+// takes precedence.
+type BuiltinCollector struct {
+	tree     *template.TemplateNode
+	builtins template.Builtins
+}
+
+func NewBuiltinCollector(tree *template.TemplateNode, builtins template.Builtins) *BuiltinCollector {
+	return &BuiltinCollector{
+		tree:     tree,
+		builtins: builtins,
+	}
+}
+
+func (c *BuiltinCollector) Collect(contexts template.RenderContexts) error {
+	return walk(c.tree, func(node *template.TemplateNode) error {
+		ensureContext(contexts, node.ID).Set(template.BuiltinVariable, c.builtins.For(node.Template))
+		return nil
+	})
+}
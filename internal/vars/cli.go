@@ -1,6 +1,13 @@
 package vars
 
-import "github.com/dhanush0x96c/blueprint/internal/template"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
 
 type CLICollector struct {
 	tree *template.TemplateNode
@@ -15,27 +22,100 @@ func NewCLICollector(tree *template.TemplateNode, args Variables) *CLICollector
 }
 
 func (c *CLICollector) Collect(contexts template.RenderContexts) error {
-	walk(c.tree, func(node *template.TemplateNode) error {
+	return walk(c.tree, func(node *template.TemplateNode) error {
 		ctx := ensureContext(contexts, node.ID)
 
-		for key, value := range c.args.Global {
-			ctx.Set(key, value)
+		scopes := []map[string]string{
+			c.args.Global,
+			c.args.NameSpecific[node.Template.Name],
+			c.args.NodeSpecific[node.ID],
 		}
 
-		if nameVars, ok := c.args.NameSpecific[node.Template.Name]; ok {
-			for key, value := range nameVars {
-				ctx.Set(key, value)
-			}
-		}
+		for _, scope := range scopes {
+			for key, raw := range scope {
+				if renamed, ok := node.Template.RenamedVariable(key); ok {
+					// A value given under the current name wins.
+					if _, set := scope[renamed]; set {
+						continue
+					}
+					key = renamed
+				}
 
-		if nodeVars, ok := c.args.NodeSpecific[node.ID]; ok {
-			for key, value := range nodeVars {
+				value, err := coerceValue(node.Template, key, raw)
+				if err != nil {
+					return fmt.Errorf("template %s (ID: %s): %w", node.Template.Name, node.ID, err)
+				}
 				ctx.Set(key, value)
 			}
 		}
 
 		return nil
 	})
+}
 
-	return nil
+// coerceValue converts a raw CLI value to the type declared for the variable
+// in the given template. Values for undeclared variables are kept as strings.
+func coerceValue(tmpl *template.Template, name, raw string) (any, error) {
+	var variable *template.Variable
+	for i := range tmpl.Variables {
+		if tmpl.Variables[i].Name == name {
+			variable = &tmpl.Variables[i]
+			break
+		}
+	}
+
+	if variable == nil {
+		return raw, nil
+	}
+
+	switch variable.Type {
+	case template.VariableTypeInt:
+		value, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %q is not a valid integer", name, raw)
+		}
+		return value, nil
+
+	case template.VariableTypeBool:
+		value, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %q is not a valid boolean (use true or false)", name, raw)
+		}
+		return value, nil
+
+	case template.VariableTypeSelect:
+		value := strings.TrimSpace(raw)
+		if err := checkOption(variable, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+
+	case template.VariableTypeMultiSelect:
+		values := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if err := checkOption(variable, item); err != nil {
+				return nil, err
+			}
+			values = append(values, item)
+		}
+		return values, nil
+
+	default:
+		return raw, nil
+	}
+}
+
+// checkOption returns an error listing the allowed values if value is not the
+// value of one of the variable's options. Labels are only shown in prompts,
+// so a value must be given as the option's value.
+func checkOption(variable *template.Variable, value string) error {
+	allowed := variable.OptionValues()
+	if slices.Contains(allowed, value) {
+		return nil
+	}
+	return fmt.Errorf("variable %s: %q is not one of the allowed values: %s", variable.Name, value, strings.Join(allowed, ", "))
 }
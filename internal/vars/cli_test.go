@@ -0,0 +1,115 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLICollector_CoercesTypes(t *testing.T) {
+	tree := &template.TemplateNode{
+		ID: "0",
+		Template: &template.Template{
+			Name: "root",
+			Variables: []template.Variable{
+				{Name: "name", Type: template.VariableTypeString},
+				{Name: "port", Type: template.VariableTypeInt},
+				{Name: "docker", Type: template.VariableTypeBool},
+				{Name: "linters", Type: template.VariableTypeMultiSelect, Options: template.NewOptions("vet", "lint")},
+				{Name: "license", Type: template.VariableTypeSelect, Options: []template.Option{
+					{Value: "mit", Label: "MIT License"},
+					{Value: "apache-2.0", Label: "Apache License 2.0"},
+				}},
+			},
+		},
+	}
+
+	t.Run("values are converted to declared types", func(t *testing.T) {
+		contexts := make(template.RenderContexts)
+		err := NewCLICollector(tree, Variables{
+			Global: map[string]string{
+				"name":    "app",
+				"port":    "8080",
+				"docker":  "true",
+				"linters": "vet, lint",
+				"license": " apache-2.0",
+				"extra":   "42",
+			},
+		}).Collect(contexts)
+		require.NoError(t, err)
+
+		ctx := contexts["0"]
+		assert.Equal(t, "app", ctx.Variables["name"])
+		assert.Equal(t, 8080, ctx.Variables["port"])
+		assert.Equal(t, true, ctx.Variables["docker"])
+		assert.Equal(t, []string{"vet", "lint"}, ctx.Variables["linters"])
+		assert.Equal(t, "apache-2.0", ctx.Variables["license"])
+		assert.Equal(t, "42", ctx.Variables["extra"])
+	})
+
+	t.Run("invalid int fails", func(t *testing.T) {
+		err := NewCLICollector(tree, Variables{
+			Global: map[string]string{"port": "http"},
+		}).Collect(make(template.RenderContexts))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a valid integer")
+	})
+
+	t.Run("invalid bool fails", func(t *testing.T) {
+		err := NewCLICollector(tree, Variables{
+			NodeSpecific: map[string]map[string]string{"0": {"docker": "maybe"}},
+		}).Collect(make(template.RenderContexts))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a valid boolean")
+	})
+
+	t.Run("select value outside the options fails", func(t *testing.T) {
+		err := NewCLICollector(tree, Variables{
+			Global: map[string]string{"license": "MIT License"},
+		}).Collect(make(template.RenderContexts))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `variable license: "MIT License" is not one of the allowed values: mit, apache-2.0`)
+	})
+
+	t.Run("multiselect item outside the options fails", func(t *testing.T) {
+		err := NewCLICollector(tree, Variables{
+			NameSpecific: map[string]map[string]string{"root": {"linters": "vet,gofmt"}},
+		}).Collect(make(template.RenderContexts))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `variable linters: "gofmt" is not one of the allowed values: vet, lint`)
+	})
+}
+
+func TestCLICollector_TranslatesDeprecatedNames(t *testing.T) {
+	tree := &template.TemplateNode{
+		ID: "0",
+		Template: &template.Template{
+			Name: "root",
+			Variables: []template.Variable{
+				{Name: "project_name", Type: template.VariableTypeString},
+				{Name: "port", Type: template.VariableTypeInt},
+			},
+			Deprecated: []template.DeprecatedVariable{
+				{Name: "app_name", RenamedTo: "project_name"},
+				{Name: "http_port", RenamedTo: "port"},
+			},
+		},
+	}
+
+	contexts := make(template.RenderContexts)
+	err := NewCLICollector(tree, Variables{
+		Global: map[string]string{
+			"app_name":  "legacy",
+			"http_port": "8080",
+			"port":      "9090",
+		},
+	}).Collect(contexts)
+	require.NoError(t, err)
+
+	ctx := contexts["0"]
+	assert.Equal(t, "legacy", ctx.Variables["project_name"])
+	assert.Equal(t, 9090, ctx.Variables["port"], "a value under the current name wins")
+	assert.NotContains(t, ctx.Variables, "app_name")
+}
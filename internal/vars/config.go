@@ -0,0 +1,37 @@
+package vars
+
+import "github.com/dhanush0x96c/blueprint/internal/template"
+
+// ConfigCollector applies variable defaults from the user configuration.
+// Every configured value is set on every node, so include conditions can refer
+// to values a template does not declare. Values for deprecated variables are
+// also set under the current name.
+type ConfigCollector struct {
+	tree     *template.TemplateNode
+	defaults map[string]any
+}
+
+func NewConfigCollector(tree *template.TemplateNode, defaults map[string]any) *ConfigCollector {
+	return &ConfigCollector{
+		tree:     tree,
+		defaults: defaults,
+	}
+}
+
+func (c *ConfigCollector) Collect(contexts template.RenderContexts) error {
+	return walk(c.tree, func(node *template.TemplateNode) error {
+		ctx := ensureContext(contexts, node.ID)
+		for key, value := range c.defaults {
+			ctx.Set(key, value)
+
+			// Defaults saved under the deprecated name of a variable apply to
+			// the variable, unless it has a default of its own.
+			if renamed, ok := node.Template.RenamedVariable(key); ok {
+				if _, set := c.defaults[renamed]; !set {
+					ctx.Set(renamed, value)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigCollector_OverridesTemplateDefaults(t *testing.T) {
+	tree := &template.TemplateNode{
+		ID: "0",
+		Template: &template.Template{
+			Name: "root",
+			Variables: []template.Variable{
+				{Name: "license", Type: template.VariableTypeString, Default: "mit"},
+			},
+		},
+	}
+
+	contexts := make(template.RenderContexts)
+	require.NoError(t, NewDefaultCollector(tree).Collect(contexts))
+	require.NoError(t, NewConfigCollector(tree, map[string]any{
+		"license":           "apache-2.0",
+		"security_baseline": true,
+	}).Collect(contexts))
+
+	assert.Equal(t, "apache-2.0", contexts["0"].Variables["license"])
+	assert.Equal(t, true, contexts["0"].Variables["security_baseline"])
+}
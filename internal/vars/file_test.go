@@ -0,0 +1,27 @@
+package vars
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vars.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: api\nport: 8080\nservices: [users, orders]\n"), 0644))
+
+	values, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":     "api",
+		"port":     8080,
+		"services": []any{"users", "orders"},
+	}, values)
+
+	require.NoError(t, os.WriteFile(path, []byte("- name\n"), 0644))
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, "failed to parse variables file")
+}
@@ -3,22 +3,29 @@ package vars
 import "github.com/dhanush0x96c/blueprint/internal/template"
 
 func ApplyInheritance(tree *template.TemplateNode, contexts template.RenderContexts) {
-	applyInheritance(tree, "", contexts)
+	walk(tree, func(node *template.TemplateNode) error {
+		InheritFromParent(node, contexts)
+		return nil
+	})
 }
 
-func applyInheritance(node *template.TemplateNode, parentID string, contexts template.RenderContexts) {
+// InheritFromParent copies the variables a node inherits from its parent's context.
+func InheritFromParent(node *template.TemplateNode, contexts template.RenderContexts) {
 	ctx := ensureContext(contexts, node.ID)
-	if parentID != "" && len(node.Inherited) > 0 {
-		if parentCtx, ok := contexts[parentID]; ok {
-			for childVar, parentVar := range node.Inherited {
-				if value, ok := parentCtx.Get(parentVar); ok {
-					ctx.Set(childVar, value)
-				}
-			}
-		}
+
+	parentID := node.ParentID()
+	if parentID == "" || len(node.Inherited) == 0 {
+		return
 	}
 
-	for _, child := range node.Children {
-		applyInheritance(child, node.ID, contexts)
+	parentCtx, ok := contexts[parentID]
+	if !ok {
+		return
+	}
+
+	for childVar, parentVar := range node.Inherited {
+		if value, ok := parentCtx.Get(parentVar); ok {
+			ctx.Set(childVar, value)
+		}
 	}
 }
@@ -0,0 +1,63 @@
+package vars
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IncludeOverride sets a single variable of one template in the composed
+// tree, given as include.variable=value. The include is a template name, or
+// a node ID prefixed with # to target a single node.
+type IncludeOverride struct {
+	Include  string
+	Variable string
+	Value    string
+}
+
+// String returns the override in the form it is given on the command line.
+func (o IncludeOverride) String() string {
+	return o.Include + "." + o.Variable + "=" + o.Value
+}
+
+// ParseIncludeOverride parses an override in the form include.variable=value.
+// Node IDs contain dots, so the variable is the part after the last one.
+func ParseIncludeOverride(s string) (IncludeOverride, error) {
+	left, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return IncludeOverride{}, fmt.Errorf("invalid include variable %q: expected include.variable=value", s)
+	}
+
+	i := strings.LastIndex(left, ".")
+	if i <= 0 || i == len(left)-1 || left[:i] == "#" {
+		return IncludeOverride{}, fmt.Errorf("invalid include variable %q: expected include.variable=value", s)
+	}
+
+	return IncludeOverride{Include: left[:i], Variable: left[i+1:], Value: value}, nil
+}
+
+// WithIncludeOverrides returns v with the overrides applied on top, each in
+// the scope of the template or node it targets.
+func (v Variables) WithIncludeOverrides(overrides []IncludeOverride) Variables {
+	if len(overrides) == 0 {
+		return v
+	}
+
+	scoped := Variables{
+		NameSpecific: make(map[string]map[string]string),
+		NodeSpecific: make(map[string]map[string]string),
+	}
+	for _, o := range overrides {
+		scope := scoped.NameSpecific
+		include := o.Include
+		if id, ok := strings.CutPrefix(include, "#"); ok {
+			scope = scoped.NodeSpecific
+			include = id
+		}
+		if scope[include] == nil {
+			scope[include] = make(map[string]string)
+		}
+		scope[include][o.Variable] = o.Value
+	}
+
+	return v.Merge(scoped)
+}
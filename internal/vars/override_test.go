@@ -0,0 +1,43 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIncludeOverride(t *testing.T) {
+	tests := []struct {
+		flag string
+		want IncludeOverride
+	}{
+		{"database.port=5433", IncludeOverride{Include: "database", Variable: "port", Value: "5433"}},
+		{"#0.1.port=5433", IncludeOverride{Include: "#0.1", Variable: "port", Value: "5433"}},
+		{"api.url=http://a.b/c=d", IncludeOverride{Include: "api", Variable: "url", Value: "http://a.b/c=d"}},
+		{"database.name=", IncludeOverride{Include: "database", Variable: "name"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseIncludeOverride(tt.flag)
+		require.NoError(t, err, tt.flag)
+		assert.Equal(t, tt.want, got, tt.flag)
+	}
+
+	for _, flag := range []string{"port=5433", ".port=1", "database.=1", "#.port=1", "database.port"} {
+		_, err := ParseIncludeOverride(flag)
+		assert.Error(t, err, flag)
+	}
+}
+
+func TestVariablesWithIncludeOverrides(t *testing.T) {
+	v := Variables{Global: map[string]string{"port": "8080"}}
+
+	got := v.WithIncludeOverrides([]IncludeOverride{
+		{Include: "database", Variable: "port", Value: "5433"},
+		{Include: "#0.1", Variable: "name", Value: "replica"},
+	})
+
+	assert.Equal(t, map[string]string{"port": "8080"}, got.Global)
+	assert.Equal(t, map[string]string{"port": "5433"}, got.NameSpecific["database"])
+	assert.Equal(t, map[string]string{"name": "replica"}, got.NodeSpecific["0.1"])
+}
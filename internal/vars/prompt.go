@@ -40,12 +40,19 @@ func (c *PromptCollector) Collect(contexts template.RenderContexts) error {
 func (c *PromptCollector) variableGroup(node *template.TemplateNode, ctx *template.Context) prompt.VariableGroup {
 	variables := node.RequiredVariables()
 	group := prompt.VariableGroup{
+		Key:       node.Template.Name + "#" + node.ID,
 		Title:     fmt.Sprintf("Variables for %s (ID: %s)", node.Template.Name, node.ID),
 		Variables: make([]prompt.Variable, 0, len(variables)),
+		Context:   ctx,
 	}
 
 	for _, variable := range variables {
 		promptVariable := prompt.Variable{Variable: variable}
+		for _, d := range node.Template.Deprecated {
+			if d.RenamedTo == variable.Name {
+				promptVariable.PreviousNames = append(promptVariable.PreviousNames, d.Name)
+			}
+		}
 		if value, ok := ctx.Get(variable.Name); ok {
 			promptVariable.Value = value
 		}
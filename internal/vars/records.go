@@ -0,0 +1,122 @@
+package vars
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadRecords reads variable records from a CSV or JSON file, chosen by the
+// file extension. A CSV file uses its header row as variable names; a JSON
+// file holds an array of objects. Each record maps variable names to raw
+// values, which are coerced to declared types like --var values.
+func LoadRecords(path string) ([]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return parseCSVRecords(f)
+	case ".json":
+		return parseJSONRecords(f)
+	default:
+		return nil, fmt.Errorf("unsupported input format %q: expected .csv or .json", ext)
+	}
+}
+
+func parseCSVRecords(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("input has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, name := range header {
+		header[i] = strings.TrimSpace(name)
+		if header[i] == "" {
+			return nil, fmt.Errorf("column %d has an empty header", i+1)
+		}
+	}
+
+	var records []map[string]string
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := make(map[string]string, len(header))
+		for i, name := range header {
+			record[name] = row[i]
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func parseJSONRecords(r io.Reader) ([]map[string]string, error) {
+	var raw []map[string]any
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("input must be a JSON array of objects: %w", err)
+	}
+
+	records := make([]map[string]string, 0, len(raw))
+	for i, obj := range raw {
+		record := make(map[string]string, len(obj))
+		for name, value := range obj {
+			s, err := recordValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: variable %s: %w", i+1, name, err)
+			}
+			record[name] = s
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// recordValue converts a JSON value to its raw string form. Arrays are
+// joined with commas, matching the multiselect format of --var.
+func recordValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := recordValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
@@ -0,0 +1,65 @@
+package vars
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRecordsFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadRecords(t *testing.T) {
+	t.Run("csv header maps columns to variables", func(t *testing.T) {
+		path := writeRecordsFile(t, "services.csv", "project_name, port\nbilling,8080\n\"users, v2\",9090\n")
+
+		records, err := LoadRecords(path)
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]string{
+			{"project_name": "billing", "port": "8080"},
+			{"project_name": "users, v2", "port": "9090"},
+		}, records)
+	})
+
+	t.Run("csv rows must match the header", func(t *testing.T) {
+		path := writeRecordsFile(t, "services.csv", "project_name,port\nbilling\n")
+
+		_, err := LoadRecords(path)
+		require.Error(t, err)
+	})
+
+	t.Run("json values are converted to raw strings", func(t *testing.T) {
+		path := writeRecordsFile(t, "services.json", `[
+			{"project_name": "billing", "port": 8080, "docker": true, "linters": ["vet", "lint"]},
+			{"project_name": "users", "description": null}
+		]`)
+
+		records, err := LoadRecords(path)
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]string{
+			{"project_name": "billing", "port": "8080", "docker": "true", "linters": "vet,lint"},
+			{"project_name": "users", "description": ""},
+		}, records)
+	})
+
+	t.Run("json nested objects are rejected", func(t *testing.T) {
+		path := writeRecordsFile(t, "services.json", `[{"owner": {"name": "x"}}]`)
+
+		_, err := LoadRecords(path)
+		require.ErrorContains(t, err, "record 1: variable owner")
+	})
+
+	t.Run("unknown extension", func(t *testing.T) {
+		path := writeRecordsFile(t, "services.txt", "")
+
+		_, err := LoadRecords(path)
+		require.ErrorContains(t, err, "unsupported input format")
+	})
+}
@@ -8,3 +8,57 @@ type Variables struct {
 
 	NodeSpecific map[string]map[string]string
 }
+
+// Merge returns the values of v with those of override applied on top. A
+// value in override replaces the values of the same variable in v in its own
+// scope and every narrower one, so that a global --var wins over a value
+// recorded for a single template.
+func (v Variables) Merge(override Variables) Variables {
+	merged := Variables{
+		Global:       copyScope(v.Global),
+		NameSpecific: make(map[string]map[string]string, len(v.NameSpecific)),
+		NodeSpecific: make(map[string]map[string]string, len(v.NodeSpecific)),
+	}
+	for name, scope := range v.NameSpecific {
+		merged.NameSpecific[name] = copyScope(scope)
+	}
+	for id, scope := range v.NodeSpecific {
+		merged.NodeSpecific[id] = copyScope(scope)
+	}
+
+	for key, value := range override.Global {
+		merged.Global[key] = value
+		for _, scope := range merged.NameSpecific {
+			delete(scope, key)
+		}
+		for _, scope := range merged.NodeSpecific {
+			delete(scope, key)
+		}
+	}
+	for name, scope := range override.NameSpecific {
+		if merged.NameSpecific[name] == nil {
+			merged.NameSpecific[name] = make(map[string]string)
+		}
+		for key, value := range scope {
+			merged.NameSpecific[name][key] = value
+		}
+	}
+	for id, scope := range override.NodeSpecific {
+		if merged.NodeSpecific[id] == nil {
+			merged.NodeSpecific[id] = make(map[string]string)
+		}
+		for key, value := range scope {
+			merged.NodeSpecific[id][key] = value
+		}
+	}
+
+	return merged
+}
+
+func copyScope(scope map[string]string) map[string]string {
+	copied := make(map[string]string, len(scope))
+	for key, value := range scope {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,27 @@
+package vars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariablesMerge(t *testing.T) {
+	recorded := Variables{
+		NameSpecific: map[string]map[string]string{"app": {"name": "demo", "port": "8080"}},
+		NodeSpecific: map[string]map[string]string{"0.1": {"name": "other"}},
+	}
+	flags := Variables{
+		Global:       map[string]string{"name": "cli"},
+		NameSpecific: map[string]map[string]string{"app": {"port": "9090"}},
+	}
+
+	merged := recorded.Merge(flags)
+
+	assert.Equal(t, map[string]string{"name": "cli"}, merged.Global)
+	assert.Equal(t, map[string]string{"port": "9090"}, merged.NameSpecific["app"])
+	assert.Empty(t, merged.NodeSpecific["0.1"])
+
+	// The receiver is left untouched.
+	assert.Equal(t, "demo", recorded.NameSpecific["app"]["name"])
+}
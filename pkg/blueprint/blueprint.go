@@ -0,0 +1,222 @@
+// Package blueprint embeds Blueprint's template loading and scaffolding in
+// other Go programs.
+//
+// A Blueprint resolves template names against the sources it was created with,
+// followed by the builtin templates:
+//
+//	bp := blueprint.New(blueprint.WithTemplates("company", os.DirFS("templates")))
+//	out := blueprint.NewMemoryOutput()
+//	result, err := bp.Scaffold("go-cli", blueprint.Options{
+//		Variables: map[string]string{"app_name": "my-app", "module_path": "example.com/my-app"},
+//		Output:    out,
+//	})
+//
+// Prompts are never shown. Every required variable must be given in
+// Options.Variables or have a default.
+package blueprint
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/dhanush0x96c/blueprint/internal/builtin/templates"
+	"github.com/dhanush0x96c/blueprint/internal/resolver"
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+	"github.com/dhanush0x96c/blueprint/internal/template"
+	"github.com/dhanush0x96c/blueprint/internal/vars"
+)
+
+// Blueprint loads, composes, renders, and scaffolds templates.
+type Blueprint struct {
+	engine     *template.Engine
+	scaffolder *scaffold.Scaffolder
+}
+
+// Option configures a Blueprint.
+type Option func(*config)
+
+type config struct {
+	sources []resolver.Source
+	builtin bool
+}
+
+// WithTemplates adds a template source searched before the builtin templates.
+// Sources are searched in the order they are added.
+func WithTemplates(name string, fsys fs.FS) Option {
+	return func(c *config) {
+		c.sources = append(c.sources, resolver.Source{
+			Name:       name,
+			Type:       resolver.SourceTypeUser,
+			Filesystem: fsys,
+		})
+	}
+}
+
+// WithoutBuiltin disables the builtin templates.
+func WithoutBuiltin() Option {
+	return func(c *config) {
+		c.builtin = false
+	}
+}
+
+// New creates a Blueprint with the given options.
+func New(opts ...Option) *Blueprint {
+	cfg := &config{builtin: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	sources := cfg.sources
+	if cfg.builtin {
+		sources = append(sources, resolver.Source{
+			Name:       "BUILTIN",
+			Type:       resolver.SourceTypeBuiltin,
+			Filesystem: templates.Templates,
+		})
+	}
+
+	res := resolver.NewChainResolver(sources...)
+	return &Blueprint{
+		engine:     template.NewEngine(res),
+		scaffolder: scaffold.NewScaffolder(res),
+	}
+}
+
+// EnableFunctions makes the functions of optional template function libraries,
+// such as "crypto" or "kubernetes-names", available to every template.
+func (b *Blueprint) EnableFunctions(libraries ...string) error {
+	return b.scaffolder.EnableFuncLibraries(libraries...)
+}
+
+// Options controls how a template is composed, rendered, and scaffolded.
+type Options struct {
+	// Variables holds raw variable values, converted to the declared variable
+	// types like --var flags on the command line.
+	Variables map[string]string
+
+	// Includes enables or disables optional includes by template name. Includes
+	// not listed use their enabled_by_default setting.
+	Includes map[string]bool
+
+	// Output receives the scaffolded files. When nil, Scaffold writes to Dir on
+	// disk, including the project manifest, and rolls back on failure.
+	Output Output
+
+	// Dir is the project directory used when Output is nil. When empty, the
+	// project is created in a directory named after the project.
+	Dir string
+
+	// Overwrite replaces existing files instead of skipping them.
+	Overwrite bool
+
+	// RunPostInit runs the post-init commands of the templates after writing
+	// to disk. It has no effect when Output is set.
+	RunPostInit bool
+}
+
+// Load loads the definition of a template without composing its includes.
+func (b *Blueprint) Load(name string) (*Template, error) {
+	loaded, err := b.engine.LoadTemplate(template.TemplateRef{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return newTemplate(loaded.Template), nil
+}
+
+// Compose composes a template with its includes and resolves the variables of
+// every template in the tree.
+func (b *Blueprint) Compose(name string, opts Options) (*Tree, error) {
+	tree, contexts, err := b.scaffolder.Compose(b.scaffoldOptions(name, opts))
+	if err != nil {
+		return nil, err
+	}
+	return newTree(tree, contexts), nil
+}
+
+// Render composes and renders a template in memory. File paths are relative
+// to the project directory.
+func (b *Blueprint) Render(name string, opts Options) ([]File, error) {
+	rendered, err := b.scaffolder.Render(b.scaffoldOptions(name, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, len(rendered.Files))
+	for i, f := range rendered.Files {
+		content, err := f.Load()
+		if err != nil {
+			return nil, err
+		}
+		files[i] = File{Path: f.Path, Content: content, Mode: f.Mode}
+	}
+	return files, nil
+}
+
+// Scaffold renders a template and writes the files to opts.Output, or to disk
+// when no output is set. Files are rendered and written one at a time.
+func (b *Blueprint) Scaffold(name string, opts Options) (*Result, error) {
+	sopts := b.scaffoldOptions(name, opts)
+
+	if opts.Output == nil {
+		res, err := b.scaffolder.Scaffold(sopts)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{
+			Dir:       res.OutputDir,
+			Written:   res.FilesWritten,
+			Skipped:   res.FilesSkipped,
+			Warnings:  newWarnings(res.Warnings),
+			NextSteps: res.NextSteps,
+		}, nil
+	}
+
+	rendered, err := b.scaffolder.Render(sopts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Written:   make([]string, 0, len(rendered.Files)),
+		Skipped:   make([]string, 0),
+		Warnings:  newWarnings(rendered.Warnings),
+		NextSteps: rendered.NextSteps,
+	}
+
+	for _, f := range rendered.Files {
+		if !opts.Overwrite {
+			if _, err := fs.Stat(opts.Output, f.Path); err == nil {
+				result.Skipped = append(result.Skipped, f.Path)
+				continue
+			}
+		}
+
+		perm := fs.FileMode(0o644)
+		if f.Mode != 0 {
+			perm = f.Mode
+		}
+
+		content, err := f.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := opts.Output.WriteFile(f.Path, content, perm); err != nil {
+			return nil, fmt.Errorf("failed to write file %s: %w", f.Path, err)
+		}
+		result.Written = append(result.Written, f.Path)
+	}
+
+	return result, nil
+}
+
+func (b *Blueprint) scaffoldOptions(name string, opts Options) scaffold.Options {
+	return scaffold.Options{
+		TemplateRef:     template.TemplateRef{Name: name},
+		OutputDir:       opts.Dir,
+		Variables:       vars.Variables{Global: opts.Variables},
+		EnabledIncludes: opts.Includes,
+		Overwrite:       opts.Overwrite,
+		SkipPostInit:    !opts.RunPostInit,
+	}
+}
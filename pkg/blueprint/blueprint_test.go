@@ -0,0 +1,152 @@
+package blueprint
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplates() fstest.MapFS {
+	return fstest.MapFS{
+		"svc/template.yaml": &fstest.MapFile{Data: []byte(`name: svc
+type: project
+version: 1.0.0
+description: A service
+variables:
+  - name: name
+    prompt: Service name
+    type: string
+    role: project_name
+  - name: port
+    prompt: Port
+    type: int
+    default: 8080
+includes:
+  - name: docs
+    enabled_by_default: false
+files:
+  - src: main.txt.tmpl
+    dest: main.txt
+next_steps: |
+  curl localhost:{{ .port }}
+`)},
+		"svc/main.txt.tmpl": &fstest.MapFile{Data: []byte("{{ .name }}:{{ .port }}\n")},
+		"docs/template.yaml": &fstest.MapFile{Data: []byte(`name: docs
+type: feature
+version: 1.0.0
+files:
+  - src: README.md.tmpl
+    dest: README.md
+next_steps: Read README.md
+`)},
+		"docs/README.md.tmpl": &fstest.MapFile{Data: []byte("# {{ .name }}\n")},
+	}
+}
+
+func TestBlueprint_Load(t *testing.T) {
+	bp := New(WithTemplates("test", testTemplates()), WithoutBuiltin())
+
+	tmpl, err := bp.Load("svc")
+	require.NoError(t, err)
+	assert.Equal(t, "project", tmpl.Type)
+	require.Len(t, tmpl.Variables, 2)
+	assert.True(t, tmpl.Variables[0].ProjectName)
+	assert.Equal(t, []Include{{Name: "docs"}}, tmpl.Includes)
+
+	_, err = bp.Load("go-cli")
+	require.Error(t, err)
+}
+
+func TestBlueprint_Compose(t *testing.T) {
+	bp := New(WithTemplates("test", testTemplates()), WithoutBuiltin())
+
+	tree, err := bp.Compose("svc", Options{
+		Variables: map[string]string{"name": "billing"},
+		Includes:  map[string]bool{"docs": true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "billing", tree.Variables["name"])
+	assert.Equal(t, 8080, tree.Variables["port"])
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, "docs", tree.Children[0].Template.Name)
+}
+
+func TestBlueprint_Render(t *testing.T) {
+	bp := New(WithTemplates("test", testTemplates()), WithoutBuiltin())
+
+	files, err := bp.Render("svc", Options{
+		Variables: map[string]string{"name": "billing", "port": "9090"},
+		Includes:  map[string]bool{"docs": true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{Path: "main.txt", Content: []byte("billing:9090\n")},
+		{Path: "README.md", Content: []byte("# billing\n")},
+	}, files)
+}
+
+func TestBlueprint_Scaffold(t *testing.T) {
+	bp := New(WithTemplates("test", testTemplates()), WithoutBuiltin())
+
+	t.Run("memory output", func(t *testing.T) {
+		out := NewMemoryOutput()
+		require.NoError(t, out.WriteFile("README.md", []byte("existing\n"), 0o644))
+
+		result, err := bp.Scaffold("svc", Options{
+			Variables: map[string]string{"name": "billing"},
+			Includes:  map[string]bool{"docs": true},
+			Output:    out,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"main.txt"}, result.Written)
+		assert.Equal(t, []string{"README.md"}, result.Skipped)
+		assert.Equal(t, []string{"curl localhost:8080", "Read README.md"}, result.NextSteps)
+
+		data, err := fs.ReadFile(out, "main.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "billing:8080\n", string(data))
+
+		data, err = fs.ReadFile(out, "README.md")
+		require.NoError(t, err)
+		assert.Equal(t, "existing\n", string(data))
+	})
+
+	t.Run("disk", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "billing")
+
+		result, err := bp.Scaffold("svc", Options{
+			Variables: map[string]string{"name": "billing"},
+			Dir:       dir,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, dir, result.Dir)
+		assert.Equal(t, []string{"main.txt"}, result.Written)
+		assert.Equal(t, []string{"curl localhost:8080"}, result.NextSteps)
+
+		data, err := os.ReadFile(filepath.Join(dir, "main.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "billing:8080\n", string(data))
+		assert.FileExists(t, filepath.Join(dir, ".blueprint", "manifest.yaml"))
+	})
+
+	t.Run("missing variables", func(t *testing.T) {
+		_, err := bp.Scaffold("svc", Options{Output: NewMemoryOutput()})
+		require.ErrorContains(t, err, "variable name is missing")
+	})
+}
+
+func TestDirOutput(t *testing.T) {
+	dir := t.TempDir()
+	out := DirOutput(dir)
+
+	require.NoError(t, out.WriteFile("a/b.txt", []byte("x"), 0o644))
+
+	data, err := fs.ReadFile(out, "a/b.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "x", string(data))
+}
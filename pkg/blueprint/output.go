@@ -0,0 +1,62 @@
+package blueprint
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing/fstest"
+
+	"github.com/dhanush0x96c/blueprint/internal/scaffold"
+)
+
+// Output is a writable file system that receives scaffolded files. Names are
+// slash-separated paths relative to the project directory. Scaffold reads the
+// file system to detect existing files.
+type Output interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// DirOutput returns an Output writing to a directory on disk. Parent
+// directories are created as needed.
+func DirOutput(dir string) Output {
+	return &dirOutput{
+		FS:     os.DirFS(dir),
+		dir:    dir,
+		writer: scaffold.NewWriter(),
+	}
+}
+
+type dirOutput struct {
+	fs.FS
+	dir    string
+	writer *scaffold.Writer
+}
+
+func (o *dirOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return o.writer.WriteFileWithPerm(filepath.Join(o.dir, filepath.FromSlash(name)), data, perm)
+}
+
+// MemoryOutput is an Output holding files in memory.
+type MemoryOutput struct {
+	files fstest.MapFS
+}
+
+// NewMemoryOutput creates an empty MemoryOutput.
+func NewMemoryOutput() *MemoryOutput {
+	return &MemoryOutput{files: make(fstest.MapFS)}
+}
+
+// Open opens the named file for reading.
+func (o *MemoryOutput) Open(name string) (fs.File, error) {
+	return o.files.Open(name)
+}
+
+// WriteFile stores a file, replacing any existing file with the same name.
+func (o *MemoryOutput) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+	o.files[name] = &fstest.MapFile{Data: data, Mode: perm}
+	return nil
+}
@@ -0,0 +1,123 @@
+package blueprint
+
+import (
+	"io/fs"
+
+	"github.com/dhanush0x96c/blueprint/internal/template"
+)
+
+// Template describes a template definition.
+type Template struct {
+	Name        string
+	Type        string // "project", "feature", or "component"
+	Version     string
+	Description string
+	Tags        []string
+	Variables   []Variable
+	Includes    []Include
+}
+
+// Variable describes a variable declared by a template.
+type Variable struct {
+	Name        string
+	Prompt      string
+	Type        string // "string", "int", "bool", "select", or "multiselect"
+	Default     any
+	Options     []string
+	ProjectName bool   // Whether the variable names the project directory
+	When        string // Condition on earlier answers under which the variable is prompted for
+}
+
+// Include describes a template composed into another one.
+type Include struct {
+	Name             string
+	EnabledByDefault bool
+	Mount            string
+	When             string
+}
+
+// Tree is a composed template with the resolved variables of each template.
+type Tree struct {
+	Template  *Template
+	Variables map[string]any
+	Mount     string
+	Children  []*Tree
+}
+
+// File is a rendered file.
+type File struct {
+	Path    string // Slash-separated path relative to the project directory
+	Content []byte
+	Mode    fs.FileMode // Permissions declared by the template; 0 when unset
+}
+
+// Warning is a non-fatal issue found while scaffolding.
+type Warning struct {
+	Template string
+	Message  string
+}
+
+// Result describes the files written by Scaffold.
+type Result struct {
+	Dir       string // Project directory on disk; empty when writing to an Output
+	Written   []string
+	Skipped   []string // Files that already existed
+	Warnings  []Warning
+	NextSteps []string // Rendered next steps of the templates in the tree
+}
+
+func newTemplate(t *template.Template) *Template {
+	tmpl := &Template{
+		Name:        t.Name,
+		Type:        string(t.Type),
+		Version:     t.Version,
+		Description: t.Description,
+		Tags:        t.Tags,
+	}
+
+	for _, v := range t.Variables {
+		tmpl.Variables = append(tmpl.Variables, Variable{
+			Name:        v.Name,
+			Prompt:      v.Prompt,
+			Type:        string(v.Type),
+			Default:     v.Default,
+			Options:     v.OptionValues(),
+			ProjectName: v.Role == template.RoleProjectName,
+			When:        v.When,
+		})
+	}
+
+	for _, inc := range t.Includes {
+		tmpl.Includes = append(tmpl.Includes, Include{
+			Name:             inc.Name,
+			EnabledByDefault: inc.EnabledByDefault,
+			Mount:            inc.Mount,
+			When:             inc.When,
+		})
+	}
+
+	return tmpl
+}
+
+func newTree(node *template.TemplateNode, contexts template.RenderContexts) *Tree {
+	tree := &Tree{
+		Template: newTemplate(node.Template),
+		Mount:    node.Mount,
+	}
+	if ctx, ok := contexts[node.ID]; ok {
+		tree.Variables = ctx.Variables
+	}
+
+	for _, child := range node.Children {
+		tree.Children = append(tree.Children, newTree(child, contexts))
+	}
+	return tree
+}
+
+func newWarnings(warnings []template.Warning) []Warning {
+	result := make([]Warning, len(warnings))
+	for i, w := range warnings {
+		result[i] = Warning{Template: w.Template, Message: w.Message}
+	}
+	return result
+}